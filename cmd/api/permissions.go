@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/cmd/api/router"
+	"api.system.soluciones-cloud.com/internal/core/roles"
+	"api.system.soluciones-cloud.com/internal/core/roles/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/localconfig"
+	"api.system.soluciones-cloud.com/internal/shared/logger"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/repository/postgres"
+)
+
+const syncPermissionsTimeout = 30 * time.Second
+
+// SyncPermissions reconciles auth.modules and auth.module_actions with the
+// permissions declared by the routes and prints what changed. With -check
+// nothing is changed and it fails when the catalog is out of date.
+//
+//	go run ./cmd/api sync-permissions
+//	go run ./cmd/api sync-permissions -check
+func SyncPermissions(args []string) error {
+	flags := flag.NewFlagSet("sync-permissions", flag.ExitOnError)
+	check := flags.Bool("check", false, "report the changes without applying them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var report entity.CatalogReport
+	app := fx.New(
+		localconfig.Module,
+		logger.Module,
+		postgres.Module,
+		roles.Module,
+		fx.NopLogger,
+		fx.Invoke(func(catalog ports.CatalogUseCase) error {
+			ctx, cancel := context.WithTimeout(context.Background(), syncPermissionsTimeout)
+			defer cancel()
+
+			var err error
+			report, err = catalog.Sync(ctx, routeActions(), *check)
+			return err
+		}),
+	)
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	printReport(report)
+	if *check && !report.InSync() {
+		return errors.New("permission catalog is out of date, run: go run ./cmd/api sync-permissions")
+	}
+	return nil
+}
+
+// syncPermissions reconciles the permission catalog before the server
+// starts when AUTH_SYNC_PERMISSIONS is set. Orphaned actions are only
+// logged.
+func syncPermissions(config *localconfig.Config, catalog ports.CatalogUseCase, log ports.Logger) error {
+	if !config.Auth.SyncPermissions {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), syncPermissionsTimeout)
+	defer cancel()
+
+	report, err := catalog.Sync(ctx, routeActions(), false)
+	if err != nil {
+		return fmt.Errorf("failed to sync permission catalog: %w", err)
+	}
+
+	if !report.InSync() {
+		log.Info(ctx, "permission catalog synced",
+			"created_modules", report.CreatedModules,
+			"created_actions", report.CreatedActions,
+			"updated_actions", report.UpdatedActions,
+		)
+	}
+	if len(report.OrphanedActions) > 0 {
+		log.Warn(ctx, "permission catalog has actions no route declares", "orphaned_actions", report.OrphanedActions)
+	}
+	return nil
+}
+
+// routeActions returns the actions declared by the API routes
+func routeActions() []entity.ActionDescriptor {
+	declared := router.Describe().Actions()
+
+	actions := make([]entity.ActionDescriptor, 0, len(declared))
+	for _, action := range declared {
+		actions = append(actions, entity.ActionDescriptor{
+			Module:     action.Module,
+			Action:     action.Action,
+			ActionType: action.Type,
+			IsPublic:   action.Public,
+			Name:       action.Summary,
+		})
+	}
+	return actions
+}
+
+func printReport(report entity.CatalogReport) {
+	lines := []struct {
+		label string
+		codes []string
+	}{
+		{"created module", report.CreatedModules},
+		{"created action", report.CreatedActions},
+		{"updated action", report.UpdatedActions},
+		{"orphaned action", report.OrphanedActions},
+	}
+	for _, line := range lines {
+		for _, code := range line.codes {
+			fmt.Printf("%s: %s\n", line.label, code)
+		}
+	}
+	if report.InSync() {
+		fmt.Println("permission catalog is in sync")
+	}
+}
@@ -0,0 +1,75 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"api.system.soluciones-cloud.com/internal/core/apikeys/domain/entity"
+	"api.system.soluciones-cloud.com/internal/core/apikeys/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/middleware"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/openapi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+var apiKeyIDParam = openapi.PathParam("id", "API key ID", valid.String().UUID())
+
+func RegisterAPIKeyRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.APIKeyHandler) {
+	apiKeysGroup := g.Group("/api-keys")
+
+	route := apiKeysGroup.POST("", server.Handle(handler.CreateAPIKey, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:      "createAPIKey",
+		Summary: "Create a new API key",
+		Description: "Create an API key acting as its own service account within an organization, granted only the given actions, which the caller must be granted too in that organization. " +
+			"The key is only returned in this response; send it as \"Authorization: " + middleware.APIKeyScheme + " <key>\".",
+		Tags:       []string{"api-keys"},
+		Permission: "api_keys.create",
+		Request:    entity.CreateAPIKeyRequest{},
+		Response:   response.Response[entity.CreatedAPIKey]{},
+		Status:     http.StatusCreated,
+	})
+
+	route = apiKeysGroup.GET("", server.Handle(handler.ListAPIKeys))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listAPIKeys",
+		Summary:     "List API keys",
+		Description: "List API keys with optional filtering, sorting, and pagination. Their secret is never returned.",
+		Tags:        []string{"api-keys"},
+		Permission:  "api_keys.read",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("name", "Filter by name (partial match)", valid.String()),
+			openapi.QueryParam("organization_id", "Filter by organization ID", valid.String().UUID()),
+			openapi.QueryParam("revoked", "Filter by revoked status", valid.Bool()),
+			openapi.QueryParam("page", "Page number (default 1)", valid.Int().Min(1)),
+			openapi.QueryParam("page_size", "Page size (default 10)", valid.Int().Range(1, entity.MaxPageSize)),
+			openapi.QueryParam("sort_by", "Sort by field", valid.Enum(entity.APIKeySortFields...)),
+			openapi.QueryParam("sort_order", "Sort order", valid.Enum("asc", "desc")),
+		},
+		Response: response.Response[types.List[entity.APIKey]]{},
+	})
+
+	route = apiKeysGroup.GET("/:id", server.Handle(handler.GetAPIKey))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "getAPIKey",
+		Summary:     "Get API key by ID",
+		Description: "Get an API key by its ID, without its secret",
+		Tags:        []string{"api-keys"},
+		Permission:  "api_keys.read",
+		Parameters:  []openapi.Parameter{apiKeyIDParam},
+		Response:    response.Response[entity.APIKey]{},
+	})
+
+	route = apiKeysGroup.DELETE("/:id", server.Handle(handler.RevokeAPIKey))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "revokeAPIKey",
+		Summary:     "Revoke API key",
+		Description: "Revoke an API key. Requests made with it are rejected right away.",
+		Tags:        []string{"api-keys"},
+		Permission:  "api_keys.revoke",
+		Parameters:  []openapi.Parameter{apiKeyIDParam},
+	})
+}
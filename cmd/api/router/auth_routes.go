@@ -0,0 +1,150 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/core/auth/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/openapi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+var oidcProviderParam = openapi.PathParam("provider", "Identity provider name, see listOIDCProviders", valid.String())
+
+func RegisterAuthRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.AuthHandler) {
+	authGroup := g.Group("/auth")
+
+	route := authGroup.POST("/register", server.Handle(handler.Register, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "register",
+		Summary:     "Register with email and password",
+		Description: "Create a user with an email credential and return its access and refresh tokens",
+		Tags:        []string{"auth"},
+		Request:     entity.RegisterRequest{},
+		Response:    response.Response[entity.Tokens]{},
+		Status:      http.StatusCreated,
+		Errors:      []int{http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+
+	route = authGroup.POST("/login", server.Handle(handler.Login))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "login",
+		Summary:     "Log in with email and password",
+		Description: "Exchange an email and password for an access token and a refresh token. Repeated failures lock the account for a while.",
+		Tags:        []string{"auth"},
+		Request:     entity.LoginRequest{},
+		Response:    response.Response[entity.Tokens]{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusInternalServerError},
+	})
+
+	route = authGroup.POST("/refresh", server.Handle(handler.Refresh))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "refreshTokens",
+		Summary:     "Refresh tokens",
+		Description: "Rotate a refresh token. Reusing a rotated refresh token revokes every token of the same login.",
+		Tags:        []string{"auth"},
+		Request:     entity.RefreshRequest{},
+		Response:    response.Response[entity.Tokens]{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+
+	route = authGroup.POST("/logout", server.Handle(handler.Logout))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "logout",
+		Summary:     "Log out",
+		Description: "Revoke a refresh token and every token rotated from the same login",
+		Tags:        []string{"auth"},
+		Request:     entity.RefreshRequest{},
+	})
+
+	route = authGroup.POST("/verify-email", server.Handle(handler.VerifyEmail))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "verifyEmail",
+		Summary:     "Verify email",
+		Description: "Mark an email as verified with the single-use token of the verification email",
+		Tags:        []string{"auth"},
+		Request:     entity.VerifyEmailRequest{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+
+	route = authGroup.POST("/verify-email/resend", server.Handle(handler.ResendVerification))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "resendVerificationEmail",
+		Summary:     "Resend verification email",
+		Description: "Send a new verification email, invalidating the previous link. Succeeds for unknown and already verified emails.",
+		Tags:        []string{"auth"},
+		Request:     entity.EmailRequest{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+
+	route = authGroup.POST("/forgot-password", server.Handle(handler.ForgotPassword))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "forgotPassword",
+		Summary:     "Request a password reset",
+		Description: "Email a single-use password reset link, invalidating the previous one. Succeeds for unknown emails.",
+		Tags:        []string{"auth"},
+		Request:     entity.EmailRequest{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+
+	route = authGroup.POST("/reset-password", server.Handle(handler.ResetPassword))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "resetPassword",
+		Summary:     "Reset password",
+		Description: "Set a new password with the token of a password reset link. The account is unlocked and every refresh token of the user is revoked.",
+		Tags:        []string{"auth"},
+		Request:     entity.ResetPasswordRequest{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+
+	route = authGroup.GET("/oidc/providers", server.Handle(handler.OIDCProviders))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listOIDCProviders",
+		Summary:     "List identity providers",
+		Description: "List the OpenID Connect providers users may log in with",
+		Tags:        []string{"auth"},
+		Response:    response.Response[types.List[entity.OIDCProvider]]{},
+		Errors:      []int{http.StatusInternalServerError},
+	})
+
+	route = authGroup.POST("/oidc/:provider/authorize", server.Handle(handler.StartOIDCLogin))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "startOIDCLogin",
+		Summary:     "Start a login with an identity provider",
+		Description: "Return the authorization URL to send the user to for an authorization code login with PKCE. The provider redirects the user back to the configured redirect URL with the code and state to post to the callback.",
+		Tags:        []string{"auth"},
+		Parameters:  []openapi.Parameter{oidcProviderParam},
+		Response:    response.Response[entity.OIDCAuthorization]{},
+		Errors:      []int{http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	})
+
+	route = authGroup.POST("/oidc/:provider/callback", server.Handle(handler.CompleteOIDCLogin))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "completeOIDCLogin",
+		Summary:     "Complete a login with an identity provider",
+		Description: "Exchange the code and state the identity provider redirected the user back with for an access token and a refresh token. The first login links the account to the user with the same verified email, or creates a user whose origin is the provider.",
+		Tags:        []string{"auth"},
+		Parameters:  []openapi.Parameter{oidcProviderParam},
+		Request:     entity.OIDCCallbackRequest{},
+		Response:    response.Response[entity.Tokens]{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	})
+}
+
+// RegisterAccountRoutes registers the auth routes of authenticated users
+func RegisterAccountRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.AuthHandler) {
+	route := g.PUT("/me/password", server.Handle(handler.ChangePassword))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:            "changeMyPassword",
+		Summary:       "Change my password",
+		Description:   "Set a new password for the caller, who must send the current one. Wrong current passwords count as failed logins. Every other session of the caller is revoked.",
+		Tags:          []string{"auth"},
+		Authenticated: true,
+		Request:       entity.ChangePasswordRequest{},
+	})
+}
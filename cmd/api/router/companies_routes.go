@@ -0,0 +1,120 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"api.system.soluciones-cloud.com/internal/core/companies/domain/entity"
+	"api.system.soluciones-cloud.com/internal/core/companies/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/openapi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+var companyFilterParams = []openapi.Parameter{
+	openapi.QueryParam("search", "Search by business name or tax ID (partial match, case insensitive)", valid.String()),
+	openapi.QueryParam("business_name", "Filter by business name (partial match, case insensitive)", valid.String()),
+	openapi.QueryParam("tax_id", "Filter by tax ID", valid.String()),
+	openapi.QueryParam("company_type", "Filter by company type, an option of the "+entity.CompanyTypeCatalog+" catalog", valid.String()),
+	openapi.QueryParam("status", "Filter by status, an option of the "+entity.CompanyStatusCatalog+" catalog", valid.String()),
+	openapi.QueryParam("industry", "Filter by industry", valid.String()),
+	openapi.QueryParam("country", "Filter by country", valid.String()),
+	openapi.QueryParam("is_active", "Filter by active status", valid.Bool()),
+	openapi.QueryParam("organization_id", "Filter by organization", valid.String().UUID()),
+	openapi.QueryParam("deleted", "List deleted companies instead of live ones", valid.Bool()),
+}
+
+var companyIDParam = openapi.PathParam("id", "Company ID", valid.String().UUID())
+
+func RegisterCompanyRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.CompanyHandler) {
+	companiesGroup := g.Group("/companies")
+
+	route := companiesGroup.POST("", server.Handle(handler.CreateCompany, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "createCompany",
+		Summary:     "Create a new company",
+		Description: "Create a company in an organization, the one of the request unless organization_id is given. It is a CUSTOMER in the ACTIVE status unless company_type and status are given, which must be options of the " + entity.CompanyTypeCatalog + " and " + entity.CompanyStatusCatalog + " catalogs. The tax_id is validated by the rules of the country, e.g. RUC or DNI in Peru, stored without separators and must be unique in the organization.",
+		Tags:        []string{"companies"},
+		Permission:  "companies.create",
+		Request:     entity.CreateCompanyRequest{},
+		Response:    response.Response[entity.Company]{},
+		Status:      http.StatusCreated,
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+
+	route = companiesGroup.GET("", server.Handle(handler.ListCompanies))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listCompanies",
+		Summary:     "List companies",
+		Description: "List the companies of the organizations of the request with optional filtering, sorting, and pagination",
+		Tags:        []string{"companies"},
+		Permission:  "companies.read",
+		Parameters: append(companyFilterParams,
+			openapi.QueryParam("page", "Page number (default 1)", valid.Int().Min(1)),
+			openapi.QueryParam("page_size", "Page size (default 10)", valid.Int().Range(1, entity.MaxPageSize)),
+			openapi.QueryParam("sort_by", "Sort by field", valid.Enum(entity.CompanySortFields...)),
+			openapi.QueryParam("sort_order", "Sort order", valid.Enum("asc", "desc")),
+		),
+		Response: response.Response[types.List[entity.Company]]{},
+	})
+
+	route = companiesGroup.GET("/count", server.Handle(handler.CountCompanies))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "countCompanies",
+		Summary:     "Count companies",
+		Description: "Count companies with optional filtering",
+		Tags:        []string{"companies"},
+		Permission:  "companies.read",
+		Parameters:  companyFilterParams,
+		Response:    response.Response[response.CountResponse]{},
+	})
+
+	route = companiesGroup.GET("/:id", server.Handle(handler.GetCompany))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "getCompany",
+		Summary:     "Get company by ID",
+		Description: "Get a company by its ID",
+		Tags:        []string{"companies"},
+		Permission:  "companies.read",
+		Parameters:  []openapi.Parameter{companyIDParam},
+		Response:    response.Response[entity.Company]{},
+	})
+
+	route = companiesGroup.PUT("/:id", server.Handle(handler.UpdateCompany))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "updateCompany",
+		Summary:     "Update company",
+		Description: "Update a company. A new company_type or status must be an option of its catalog. A new tax_id or country validates the tax_id by the rules of the country, and a new tax_id must be unique in the organizations of the company.",
+		Tags:        []string{"companies"},
+		Permission:  "companies.update",
+		Parameters:  []openapi.Parameter{companyIDParam},
+		Request:     entity.UpdateCompanyRequest{},
+		Response:    response.Response[entity.Company]{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+
+	route = companiesGroup.DELETE("/:id", server.Handle(handler.DeleteCompany))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "deleteCompany",
+		Summary:     "Delete company",
+		Description: "Soft delete a company. It can be restored.",
+		Tags:        []string{"companies"},
+		Permission:  "companies.delete",
+		Parameters:  []openapi.Parameter{companyIDParam},
+	})
+
+	route = companiesGroup.POST("/:id/restore", server.Handle(handler.RestoreCompany))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "restoreCompany",
+		Summary:     "Restore company",
+		Description: "Restore a deleted company, unless another company of its organizations took its tax_id",
+		Tags:        []string{"companies"},
+		Permission:  "companies.delete",
+		Parameters:  []openapi.Parameter{companyIDParam},
+		Response:    response.Response[entity.Company]{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError},
+	})
+}
@@ -0,0 +1,136 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"api.system.soluciones-cloud.com/internal/core/contacts/domain/entity"
+	"api.system.soluciones-cloud.com/internal/core/contacts/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/openapi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+	"api.system.soluciones-cloud.com/internal/shared/vcard"
+)
+
+var contactFilterParams = []openapi.Parameter{
+	openapi.QueryParam("search", "Search by first name, last name or email (partial match, case insensitive)", valid.String()),
+	openapi.QueryParam("email", "Filter by email (case insensitive)", valid.String()),
+	openapi.QueryParam("department", "Filter by department", valid.String()),
+	openapi.QueryParam("contact_type", "Filter by contact type, e.g. BILLING, an option of the "+entity.ContactTypeCatalog+" catalog", valid.String()),
+	openapi.QueryParam("primary_for", "Filter by the contact type the contacts are the primary contact for", valid.String()),
+	openapi.QueryParam("organization_id", "Filter by organization", valid.String().UUID()),
+}
+
+var contactPageParams = []openapi.Parameter{
+	openapi.QueryParam("page", "Page number (default 1)", valid.Int().Min(1)),
+	openapi.QueryParam("page_size", "Page size (default 10)", valid.Int().Range(1, entity.MaxPageSize)),
+	openapi.QueryParam("sort_by", "Sort by field", valid.Enum(entity.ContactSortFields...)),
+	openapi.QueryParam("sort_order", "Sort order", valid.Enum("asc", "desc")),
+}
+
+var contactIDParam = openapi.PathParam("id", "Contact ID", valid.String().UUID())
+
+func RegisterContactRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.ContactHandler) {
+	companyContactsGroup := g.Group("/companies/:id/contacts")
+
+	route := companyContactsGroup.POST("", server.Handle(handler.CreateContact, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "createContact",
+		Summary:     "Create a contact of a company",
+		Description: "Create a contact of a company in one of its organizations, the one of the request unless organization_id is given. The email is stored lower cased and must be unique among the contacts of the company. contact_types must be options of the " + entity.ContactTypeCatalog + " catalog; primary_types, some of them, make the contact the primary contact of the company for those types instead of the current one.",
+		Tags:        []string{"contacts"},
+		Permission:  "contacts.create",
+		Parameters:  []openapi.Parameter{companyIDParam},
+		Request:     entity.CreateContactRequest{},
+		Response:    response.Response[entity.Contact]{},
+		Status:      http.StatusCreated,
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+
+	route = companyContactsGroup.GET("", server.Handle(handler.ListContacts))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listCompanyContacts",
+		Summary:     "List the contacts of a company",
+		Description: "List the contacts of a company with optional filtering, sorting, and pagination",
+		Tags:        []string{"contacts"},
+		Permission:  "contacts.read",
+		Parameters:  append(append([]openapi.Parameter{companyIDParam}, contactFilterParams...), contactPageParams...),
+		Response:    response.Response[types.List[entity.Contact]]{},
+	})
+
+	route = companyContactsGroup.GET("/vcard", server.Handle(handler.ExportContacts))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "exportCompanyContacts",
+		Summary:     "Export the contacts of a company",
+		Description: "Download the contacts of a company as a vCard 4.0 (RFC 6350) file. Contact types are exported as categories.",
+		Tags:        []string{"contacts"},
+		Permission:  "contacts.read",
+		Parameters:  []openapi.Parameter{companyIDParam},
+		Download:    vcard.ContentType,
+	})
+
+	contactsGroup := g.Group("/contacts")
+
+	route = contactsGroup.GET("", server.Handle(handler.ListContacts))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listContacts",
+		Summary:     "List contacts",
+		Description: "List the contacts of the organizations of the request with optional filtering, sorting, and pagination",
+		Tags:        []string{"contacts"},
+		Permission:  "contacts.read",
+		Parameters: append(append([]openapi.Parameter{
+			openapi.QueryParam("company_id", "Filter by company", valid.String().UUID()),
+		}, contactFilterParams...), contactPageParams...),
+		Response: response.Response[types.List[entity.Contact]]{},
+	})
+
+	route = contactsGroup.GET("/count", server.Handle(handler.CountContacts))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "countContacts",
+		Summary:     "Count contacts",
+		Description: "Count contacts with optional filtering",
+		Tags:        []string{"contacts"},
+		Permission:  "contacts.read",
+		Parameters: append([]openapi.Parameter{
+			openapi.QueryParam("company_id", "Filter by company", valid.String().UUID()),
+		}, contactFilterParams...),
+		Response: response.Response[response.CountResponse]{},
+	})
+
+	route = contactsGroup.GET("/:id", server.Handle(handler.GetContact))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "getContact",
+		Summary:     "Get contact by ID",
+		Description: "Get a contact by its ID",
+		Tags:        []string{"contacts"},
+		Permission:  "contacts.read",
+		Parameters:  []openapi.Parameter{contactIDParam},
+		Response:    response.Response[entity.Contact]{},
+	})
+
+	route = contactsGroup.PUT("/:id", server.Handle(handler.UpdateContact))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "updateContact",
+		Summary:     "Update contact",
+		Description: "Update a contact. New contact_types must be options of the " + entity.ContactTypeCatalog + " catalog and primary_types some of them; the contact stops being primary for the types it loses.",
+		Tags:        []string{"contacts"},
+		Permission:  "contacts.update",
+		Parameters:  []openapi.Parameter{contactIDParam},
+		Request:     entity.UpdateContactRequest{},
+		Response:    response.Response[entity.Contact]{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+
+	route = contactsGroup.DELETE("/:id", server.Handle(handler.DeleteContact))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "deleteContact",
+		Summary:     "Delete contact",
+		Description: "Soft delete a contact",
+		Tags:        []string{"contacts"},
+		Permission:  "contacts.delete",
+		Parameters:  []openapi.Parameter{contactIDParam},
+	})
+}
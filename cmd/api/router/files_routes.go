@@ -0,0 +1,44 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"api.system.soluciones-cloud.com/internal/core/files/domain/entity"
+	"api.system.soluciones-cloud.com/internal/core/files/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/openapi"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+func RegisterFileRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.FileHandler) {
+	filesGroup := g.Group("/files")
+
+	route := filesGroup.POST("", server.Handle(handler.UploadFile, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "uploadFile",
+		Summary:     "Upload a file",
+		Description: "Store a PDF, image, plain text or ZIP file (e.g. an Office document) in the organization of the request. Keep its key, e.g. in file_url columns, to get download URLs later.",
+		Tags:        []string{"files"},
+		Permission:  "files.upload",
+		Files:       []openapi.FileField{{Name: "file", Description: "File"}},
+		Response:    response.Response[entity.StoredFile]{},
+		Status:      http.StatusCreated,
+	})
+
+	route = filesGroup.GET("/url", server.Handle(handler.GetFileURL))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "getFileURL",
+		Summary:     "Get a file download URL",
+		Description: "Sign a URL that downloads the file without credentials until it expires",
+		Tags:        []string{"files"},
+		Permission:  "files.read",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("key", "Key of the file, as returned when it was uploaded", valid.String()),
+		},
+		Response: response.Response[entity.FileURL]{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	})
+}
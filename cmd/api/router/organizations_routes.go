@@ -0,0 +1,184 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"api.system.soluciones-cloud.com/internal/core/organizations/domain/entity"
+	"api.system.soluciones-cloud.com/internal/core/organizations/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/openapi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+var organizationFilterParams = []openapi.Parameter{
+	openapi.QueryParam("name", "Filter by name (partial match)", valid.String()),
+	openapi.QueryParam("code", "Filter by code", valid.String()),
+	openapi.QueryParam("organization_type", "Filter by organization type", valid.Enum(entity.OrganizationTypes...)),
+	openapi.QueryParam("status", "Filter by status", valid.Enum(entity.OrganizationStatuses...)),
+	openapi.QueryParam("is_active", "Filter by active status", valid.Bool()),
+}
+
+var (
+	organizationIDParam = openapi.PathParam("id", "Organization ID", valid.String().UUID())
+	memberIDParam       = openapi.PathParam("member_id", "Member ID", valid.String().UUID())
+)
+
+func RegisterOrganizationRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.OrganizationHandler) {
+	organizationsGroup := g.Group("/organizations")
+
+	route := organizationsGroup.POST("", server.Handle(handler.CreateOrganization, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "createOrganization",
+		Summary:     "Create a new organization",
+		Description: "Create an organization. It is a CUSTOMER in the ACTIVE status unless organization_type and status are given.",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.create",
+		Request:     entity.CreateOrganizationRequest{},
+		Response:    response.Response[entity.Organization]{},
+		Status:      http.StatusCreated,
+	})
+
+	route = organizationsGroup.GET("", server.Handle(handler.ListOrganizations))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listOrganizations",
+		Summary:     "List organizations",
+		Description: "List organizations with optional filtering, sorting, and pagination",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.read",
+		Parameters: append(organizationFilterParams,
+			openapi.QueryParam("page", "Page number (default 1)", valid.Int().Min(1)),
+			openapi.QueryParam("page_size", "Page size (default 10)", valid.Int().Range(1, entity.MaxPageSize)),
+			openapi.QueryParam("sort_by", "Sort by field", valid.Enum(entity.OrganizationSortFields...)),
+			openapi.QueryParam("sort_order", "Sort order", valid.Enum("asc", "desc")),
+		),
+		Response: response.Response[types.List[entity.Organization]]{},
+	})
+
+	route = organizationsGroup.GET("/count", server.Handle(handler.CountOrganizations))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "countOrganizations",
+		Summary:     "Count organizations",
+		Description: "Count organizations with optional filtering",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.read",
+		Parameters:  organizationFilterParams,
+		Response:    response.Response[response.CountResponse]{},
+	})
+
+	route = organizationsGroup.GET("/:id", server.Handle(handler.GetOrganization))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "getOrganization",
+		Summary:     "Get organization by ID",
+		Description: "Get an organization by its ID",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.read",
+		Parameters:  []openapi.Parameter{organizationIDParam},
+		Response:    response.Response[entity.Organization]{},
+	})
+
+	route = organizationsGroup.PUT("/:id", server.Handle(handler.UpdateOrganization))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "updateOrganization",
+		Summary:     "Update organization",
+		Description: "Update an organization. The root organization cannot be deactivated, and the members of inactive organizations lose access to them.",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.update",
+		Parameters:  []openapi.Parameter{organizationIDParam},
+		Request:     entity.UpdateOrganizationRequest{},
+		Response:    response.Response[entity.Organization]{},
+	})
+
+	route = organizationsGroup.DELETE("/:id", server.Handle(handler.DeleteOrganization))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "deleteOrganization",
+		Summary:     "Delete organization",
+		Description: "Soft delete an organization. The root organization cannot be deleted.",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.delete",
+		Parameters:  []openapi.Parameter{organizationIDParam},
+	})
+
+	route = organizationsGroup.POST("/:id/members", server.Handle(handler.AddMember, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "addOrganizationMember",
+		Summary:     "Add member",
+		Description: "Add a user to an organization, optionally on behalf of one of its customers. A user belongs to an organization at most once per customer.",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.members",
+		Parameters:  []openapi.Parameter{organizationIDParam},
+		Request:     entity.AddMemberRequest{},
+		Response:    response.Response[entity.Member]{},
+		Status:      http.StatusCreated,
+	})
+
+	route = organizationsGroup.GET("/:id/members", server.Handle(handler.ListMembers))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listOrganizationMembers",
+		Summary:     "List members",
+		Description: "List the members of an organization with the name and email of their users",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.read",
+		Parameters: []openapi.Parameter{
+			organizationIDParam,
+			openapi.QueryParam("user_id", "Filter by user ID", valid.String().UUID()),
+			openapi.QueryParam("customer_id", "Filter by customer ID", valid.String().UUID()),
+			openapi.QueryParam("relationship", "Filter by relationship", valid.Enum(entity.Relationships...)),
+			openapi.QueryParam("is_active", "Filter by active status", valid.Bool()),
+			openapi.QueryParam("page", "Page number (default 1)", valid.Int().Min(1)),
+			openapi.QueryParam("page_size", "Page size (default 10)", valid.Int().Range(1, entity.MaxPageSize)),
+			openapi.QueryParam("sort_by", "Sort by field", valid.Enum(entity.MemberSortFields...)),
+			openapi.QueryParam("sort_order", "Sort order", valid.Enum("asc", "desc")),
+		},
+		Response: response.Response[types.List[entity.Member]]{},
+	})
+
+	route = organizationsGroup.POST("/:id/invitations", server.Handle(handler.InviteMember, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "inviteOrganizationMember",
+		Summary:     "Invite member",
+		Description: "Add the user registered with an email to an organization and let them know by email",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.members",
+		Parameters:  []openapi.Parameter{organizationIDParam},
+		Request:     entity.InviteMemberRequest{},
+		Response:    response.Response[entity.Member]{},
+		Status:      http.StatusCreated,
+	})
+
+	route = organizationsGroup.PUT("/:id/members/:member_id", server.Handle(handler.UpdateMember))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "updateOrganizationMember",
+		Summary:     "Update member",
+		Description: "Change the relationship of a member, or deactivate and reactivate the membership",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.members",
+		Parameters:  []openapi.Parameter{organizationIDParam, memberIDParam},
+		Request:     entity.UpdateMemberRequest{},
+		Response:    response.Response[entity.Member]{},
+	})
+
+	route = organizationsGroup.DELETE("/:id/members/:member_id", server.Handle(handler.RemoveMember))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "removeOrganizationMember",
+		Summary:     "Remove member",
+		Description: "Remove a member from an organization",
+		Tags:        []string{"organizations"},
+		Permission:  "organizations.members",
+		Parameters:  []openapi.Parameter{organizationIDParam, memberIDParam},
+	})
+
+	route = g.GET("/me/organizations", server.Handle(handler.MyOrganizations))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:            "listMyOrganizations",
+		Summary:       "List my organizations",
+		Description:   "List the active organizations the caller is an active member of, root organization first, e.g. for an organization switcher. Any of them may be sent in the " + tenant.Header + " header.",
+		Tags:          []string{"organizations"},
+		Authenticated: true,
+		Response:      response.Response[types.List[entity.Membership]]{},
+	})
+}
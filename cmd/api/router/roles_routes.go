@@ -0,0 +1,186 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"api.system.soluciones-cloud.com/internal/core/roles/domain/entity"
+	"api.system.soluciones-cloud.com/internal/core/roles/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/openapi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+var roleFilterParams = []openapi.Parameter{
+	openapi.QueryParam("organization_id", "Filter by organization ID", valid.String().UUID()),
+	openapi.QueryParam("code", "Filter by code", valid.String()),
+	openapi.QueryParam("name", "Filter by name (partial match)", valid.String()),
+	openapi.QueryParam("is_active", "Filter by active status", valid.Bool()),
+	openapi.QueryParam("is_system_role", "Filter system roles", valid.Bool()),
+}
+
+var roleIDParam = openapi.PathParam("id", "Role ID", valid.String().UUID())
+
+func RegisterRoleRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.RoleHandler) {
+	rolesGroup := g.Group("/roles")
+
+	route := rolesGroup.POST("", server.Handle(handler.CreateRole, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "createRole",
+		Summary:     "Create a new role",
+		Description: "Create a role in an organization, by default the organization the request acts on. Roles are created active and without permissions.",
+		Tags:        []string{"roles"},
+		Permission:  "roles.create",
+		Request:     entity.CreateRoleRequest{},
+		Response:    response.Response[entity.Role]{},
+		Status:      http.StatusCreated,
+	})
+
+	route = rolesGroup.GET("", server.Handle(handler.ListRoles))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listRoles",
+		Summary:     "List roles",
+		Description: "List roles with optional filtering, sorting, and pagination",
+		Tags:        []string{"roles"},
+		Permission:  "roles.read",
+		Parameters: append(roleFilterParams,
+			openapi.QueryParam("page", "Page number (default 1)", valid.Int().Min(1)),
+			openapi.QueryParam("page_size", "Page size (default 10)", valid.Int().Range(1, entity.MaxPageSize)),
+			openapi.QueryParam("sort_by", "Sort by field", valid.Enum(entity.RoleSortFields...)),
+			openapi.QueryParam("sort_order", "Sort order", valid.Enum("asc", "desc")),
+		),
+		Response: response.Response[types.List[entity.Role]]{},
+	})
+
+	route = rolesGroup.GET("/count", server.Handle(handler.CountRoles))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "countRoles",
+		Summary:     "Count roles",
+		Description: "Count roles with optional filtering",
+		Tags:        []string{"roles"},
+		Permission:  "roles.read",
+		Parameters:  roleFilterParams,
+		Response:    response.Response[response.CountResponse]{},
+	})
+
+	route = rolesGroup.GET("/:id", server.Handle(handler.GetRole))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "getRole",
+		Summary:     "Get role by ID",
+		Description: "Get a role by its ID",
+		Tags:        []string{"roles"},
+		Permission:  "roles.read",
+		Parameters:  []openapi.Parameter{roleIDParam},
+		Response:    response.Response[entity.Role]{},
+	})
+
+	route = rolesGroup.PUT("/:id", server.Handle(handler.UpdateRole))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "updateRole",
+		Summary:     "Update role",
+		Description: "Update a role. System roles cannot be modified.",
+		Tags:        []string{"roles"},
+		Permission:  "roles.update",
+		Parameters:  []openapi.Parameter{roleIDParam},
+		Request:     entity.UpdateRoleRequest{},
+		Response:    response.Response[entity.Role]{},
+	})
+
+	route = rolesGroup.DELETE("/:id", server.Handle(handler.DeleteRole))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "deleteRole",
+		Summary:     "Delete role",
+		Description: "Delete a role with its permissions and assignments. System roles cannot be deleted.",
+		Tags:        []string{"roles"},
+		Permission:  "roles.delete",
+		Parameters:  []openapi.Parameter{roleIDParam},
+	})
+
+	route = rolesGroup.GET("/:id/permissions", server.Handle(handler.ListRolePermissions))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listRolePermissions",
+		Summary:     "List role permissions",
+		Description: "List the module actions granted to a role",
+		Tags:        []string{"roles"},
+		Permission:  "roles.read",
+		Parameters:  []openapi.Parameter{roleIDParam},
+		Response:    response.Response[types.List[entity.ModuleAction]]{},
+	})
+
+	route = rolesGroup.POST("/:id/permissions", server.Handle(handler.GrantPermissions))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "grantRolePermissions",
+		Summary:     "Grant role permissions",
+		Description: "Grant module actions to a role by their <module>.<action> codes. Granted actions are skipped. Callers may only grant the actions they are granted in the organization of the role, and only root organization members may grant actions with the ALL scope.",
+		Tags:        []string{"roles"},
+		Permission:  "roles.grant",
+		Parameters:  []openapi.Parameter{roleIDParam},
+		Request:     entity.RolePermissionsRequest{},
+		Response:    response.Response[types.List[entity.ModuleAction]]{},
+	})
+
+	route = rolesGroup.DELETE("/:id/permissions", server.Handle(handler.RevokePermissions))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "revokeRolePermissions",
+		Summary:     "Revoke role permissions",
+		Description: "Revoke module actions from a role by their <module>.<action> codes",
+		Tags:        []string{"roles"},
+		Permission:  "roles.grant",
+		Parameters:  []openapi.Parameter{roleIDParam},
+		Request:     entity.RolePermissionsRequest{},
+		Response:    response.Response[types.List[entity.ModuleAction]]{},
+	})
+
+	usersGroup := g.Group("/users")
+
+	route = usersGroup.GET("/:id/roles", server.Handle(handler.ListUserRoles))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listUserRoles",
+		Summary:     "List user roles",
+		Description: "List the roles assigned to a user",
+		Tags:        []string{"roles"},
+		Permission:  "roles.read",
+		Parameters:  []openapi.Parameter{userIDParam},
+		Response:    response.Response[types.List[entity.UserRole]]{},
+	})
+
+	route = usersGroup.POST("/:id/roles", server.Handle(handler.AssignRole, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "assignUserRole",
+		Summary:     "Assign role",
+		Description: "Assign a role to a user, optionally until expires_at. Assigning a role again renews it.",
+		Tags:        []string{"roles"},
+		Permission:  "roles.assign",
+		Parameters:  []openapi.Parameter{userIDParam},
+		Request:     entity.AssignRoleRequest{},
+		Response:    response.Response[entity.UserRole]{},
+		Status:      http.StatusCreated,
+	})
+
+	route = usersGroup.DELETE("/:id/roles/:role_id", server.Handle(handler.RevokeRole))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "revokeUserRole",
+		Summary:     "Revoke role",
+		Description: "Remove a role from a user",
+		Tags:        []string{"roles"},
+		Permission:  "roles.assign",
+		Parameters:  []openapi.Parameter{userIDParam, openapi.PathParam("role_id", "Role ID", valid.String().UUID())},
+	})
+
+	route = usersGroup.GET("/:id/permissions", server.Handle(handler.UserPermissions))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "getUserPermissions",
+		Summary:     "Get user permissions",
+		Description: "Get the effective permissions of a user: the actions of their active roles plus the public actions, with their visibility scope",
+		Tags:        []string{"roles"},
+		Permission:  "roles.read",
+		Parameters: []openapi.Parameter{
+			userIDParam,
+			openapi.QueryParam("organization_id", "Only use the roles of this organization", valid.String().UUID()),
+		},
+		Response: response.Response[entity.UserPermissions]{},
+	})
+}
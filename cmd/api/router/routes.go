@@ -2,9 +2,23 @@ package router
 
 import (
 	"fmt"
+	"net/http"
 
+	apikeypresentation "api.system.soluciones-cloud.com/internal/core/apikeys/infrastructure/presentation"
+	authpresentation "api.system.soluciones-cloud.com/internal/core/auth/infrastructure/presentation"
+	companypresentation "api.system.soluciones-cloud.com/internal/core/companies/infrastructure/presentation"
+	contactpresentation "api.system.soluciones-cloud.com/internal/core/contacts/infrastructure/presentation"
+	filepresentation "api.system.soluciones-cloud.com/internal/core/files/infrastructure/presentation"
+	organizationpresentation "api.system.soluciones-cloud.com/internal/core/organizations/infrastructure/presentation"
+	rolepresentation "api.system.soluciones-cloud.com/internal/core/roles/infrastructure/presentation"
 	"api.system.soluciones-cloud.com/internal/core/users/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/blob"
 	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/middleware"
+	"api.system.soluciones-cloud.com/internal/shared/openapi"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
 	"github.com/MarceloPetrucio/go-scalar-api-reference"
 	"github.com/labstack/echo/v4"
 
@@ -13,29 +27,148 @@ import (
 
 type RouterParams struct {
 	fx.In
-	UserHandler *presentation.UserHandler
+	AuthHandler         *authpresentation.AuthHandler
+	UserHandler         *presentation.UserHandler
+	RoleHandler         *rolepresentation.RoleHandler
+	OrganizationHandler *organizationpresentation.OrganizationHandler
+	APIKeyHandler       *apikeypresentation.APIKeyHandler
+	SessionHandler      *authpresentation.SessionHandler
+	FileHandler         *filepresentation.FileHandler
+	CompanyHandler      *companypresentation.CompanyHandler
+	ContactHandler      *contactpresentation.ContactHandler
+	// Authorizer checks the permissions declared by private routes,
+	// Sessions rejects revoked sessions and Users resolves the locale the
+	// caller chose. Only SetAPIRoutes uses them.
+	Authorizer ports.Authorizer
+	Sessions   ports.SessionUseCase
+	Users      ports.UserUseCase
+	// Blobs serves the files of the local storage driver
+	Blobs ports.BlobStore
+}
+
+// NewDocs returns the registry the API routes are documented in.
+func NewDocs() *openapi.Registry {
+	return openapi.NewRegistry(openapi.Info{
+		Title:       "Go Hexagonal Fullstack Monorepo API",
+		Description: "API specification for the Go hexagonal architecture monorepo",
+		Version:     "1.0.0",
+		Contact: &openapi.Contact{
+			Name: "Tech Forge LAT",
+			URL:  "https://github.com/techforge-lat/go-hexagonal-fullstack-monorepo",
+		},
+		License: &openapi.License{
+			Name: "MIT",
+			URL:  "https://opensource.org/licenses/MIT",
+		},
+	}, openapi.Server{URL: "http://localhost:8080", Description: "Development server"})
+}
+
+// RegisterRoutes registers and documents every API route. It is shared by
+// the server and the OpenAPI generator (cmd/openapi), so the spec always
+// matches the routes being served.
+func RegisterRoutes(public, private *echo.Group, docs *openapi.Registry, params RouterParams) {
+	privateDocs := docs.Secured("bearerAuth", openapi.BearerJWT("Access token signed with HS256, RS256 or EdDSA")).
+		Secured("apiKeyAuth", openapi.APIKeyHeader("Authorization", "API key sent as \""+middleware.APIKeyScheme+" <key>\"")).
+		WithParameters(openapi.HeaderParam(tenant.Header,
+			"Organization the request acts on. Defaults to the organization of the token, or else every organization of the user. "+
+				"Members of the root organization may select any organization, or "+tenant.AllOrganizations+" for all of them.",
+			valid.String()))
+
+	// Register auth routes
+	RegisterAuthRoutes(public, docs, params.AuthHandler)
+	RegisterAccountRoutes(private, privateDocs, params.AuthHandler)
+
+	// Register users routes
+	RegisterUserRoutes(private, privateDocs, params.UserHandler)
+	RegisterAvatarRoutes(public, docs, params.UserHandler)
+
+	// Register roles routes
+	RegisterRoleRoutes(private, privateDocs, params.RoleHandler)
+
+	// Register organizations routes
+	RegisterOrganizationRoutes(private, privateDocs, params.OrganizationHandler)
+
+	// Register API keys routes
+	RegisterAPIKeyRoutes(private, privateDocs, params.APIKeyHandler)
+
+	// Register sessions routes
+	RegisterSessionRoutes(private, privateDocs, params.SessionHandler)
+
+	// Register files routes
+	RegisterFileRoutes(private, privateDocs, params.FileHandler)
+
+	// Register companies routes
+	RegisterCompanyRoutes(private, privateDocs, params.CompanyHandler)
+
+	// Register contacts routes
+	RegisterContactRoutes(private, privateDocs, params.ContactHandler)
+}
+
+// Describe documents every API route without serving them, e.g. to
+// generate the spec or to sync the permission catalog. Handlers are only
+// referenced, never called, so they need no dependencies.
+func Describe() *openapi.Registry {
+	api := echo.New()
+	group := api.Group("/api/v1")
+	docs := NewDocs()
+	RegisterRoutes(group, group, docs, RouterParams{
+		AuthHandler:         &authpresentation.AuthHandler{},
+		UserHandler:         &presentation.UserHandler{},
+		RoleHandler:         &rolepresentation.RoleHandler{},
+		OrganizationHandler: &organizationpresentation.OrganizationHandler{},
+		APIKeyHandler:       &apikeypresentation.APIKeyHandler{},
+		SessionHandler:      &authpresentation.SessionHandler{},
+		CompanyHandler:      &companypresentation.CompanyHandler{},
+		ContactHandler:      &contactpresentation.ContactHandler{},
+	})
+	return docs
 }
 
 // SetAPIRoutes configures all API routes for the server
 func SetAPIRoutes(echoServer *server.EchoServer, params RouterParams) error {
-	echoServer.API.GET("/docs", func(c echo.Context) error {
-		htmlContent, err := scalar.ApiReferenceHTML(&scalar.Options{
-			// SpecURL: "https://generator3.swagger.io/openapi.json",// allow external URL or local path file
-			SpecURL: "./cmd/api/docs/openapi.yaml",
-			CustomOptions: scalar.CustomOptions{
-				PageTitle: "Simple API",
-			},
-			DarkMode: true,
-		})
-		if err != nil {
-			fmt.Printf("%v", err)
-		}
-
-		return c.HTML(200, htmlContent)
+	docs := NewDocs()
+
+	// Private routes act on the organizations of the caller, reject
+	// revoked sessions, answer in the locale the caller chose unless ?lang
+	// overrides it and require the permission they are documented with
+	echoServer.PrivateAPI.Use(middleware.Tenant(params.Authorizer))
+	echoServer.PrivateAPI.Use(middleware.Session(params.Sessions))
+	echoServer.PrivateAPI.Use(middleware.Locale(middleware.QueryLocale("lang"), middleware.UserLocale(params.Users)))
+	echoServer.PrivateAPI.Use(middleware.Authorize(params.Authorizer, docs.Permission))
+	RegisterRoutes(echoServer.PublicAPI, echoServer.PrivateAPI, docs, params)
+	echoServer.RouteNotFound()
+
+	// The local storage driver serves its signed URLs itself. The route is
+	// left out of the spec, as it depends on the driver.
+	if local, ok := params.Blobs.(*blob.LocalStore); ok {
+		echoServer.PublicAPI.GET(blob.LocalRoute+"/*", local.Handler())
+	}
+
+	spec, err := docs.JSON()
+	if err != nil {
+		return fmt.Errorf("failed to generate OpenAPI spec: %w", err)
+	}
+
+	echoServer.API.GET("/docs/openapi.json", func(c echo.Context) error {
+		return c.JSONBlob(http.StatusOK, spec)
 	})
 
-	// Register users routes
-	RegisterUserRoutes(echoServer.PublicAPI, params.UserHandler)
+	// The spec is fixed once the routes are registered, so the reference
+	// page is rendered once too
+	reference, err := scalar.ApiReferenceHTML(&scalar.Options{
+		SpecContent: string(spec),
+		CustomOptions: scalar.CustomOptions{
+			PageTitle: "Simple API",
+		},
+		DarkMode: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render API reference: %w", err)
+	}
+
+	echoServer.API.GET("/docs", func(c echo.Context) error {
+		return c.HTML(http.StatusOK, reference)
+	})
 
 	return nil
 }
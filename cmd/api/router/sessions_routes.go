@@ -0,0 +1,58 @@
+package router
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/core/auth/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/openapi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+var sessionIDParam = openapi.PathParam("id", "Session ID", valid.String().UUID())
+
+func RegisterSessionRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.SessionHandler) {
+	route := g.GET("/me/sessions", server.Handle(handler.MySessions))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:            "listMySessions",
+		Summary:       "List my sessions",
+		Description:   "List the active login sessions of the caller, most recently seen first, with the device they were last used from. The session of the request is flagged as current.",
+		Tags:          []string{"sessions"},
+		Authenticated: true,
+		Response:      response.Response[types.List[entity.Session]]{},
+	})
+
+	route = g.DELETE("/me/sessions/:id", server.Handle(handler.RevokeMySession))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:            "revokeMySession",
+		Summary:       "Revoke my session",
+		Description:   "End a session of the caller, e.g. of a lost device. Its refresh tokens are revoked and its access tokens rejected right away.",
+		Tags:          []string{"sessions"},
+		Authenticated: true,
+		Parameters:    []openapi.Parameter{sessionIDParam},
+	})
+
+	route = g.GET("/users/:id/sessions", server.Handle(handler.UserSessions))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listUserSessions",
+		Summary:     "List user sessions",
+		Description: "List the active login sessions of a user, most recently seen first",
+		Tags:        []string{"sessions"},
+		Permission:  "users.sessions",
+		Parameters:  []openapi.Parameter{userIDParam},
+		Response:    response.Response[types.List[entity.Session]]{},
+	})
+
+	route = g.DELETE("/users/:id/sessions", server.Handle(handler.RevokeUserSessions))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "revokeUserSessions",
+		Summary:     "Log user out",
+		Description: "End every session of a user, e.g. of a compromised account. Their refresh tokens are revoked and their access tokens rejected right away.",
+		Tags:        []string{"sessions"},
+		Permission:  "users.sessions",
+		Parameters:  []openapi.Parameter{userIDParam},
+	})
+}
@@ -1,19 +1,243 @@
 package router
 
 import (
+	"net/http"
+
 	"github.com/labstack/echo/v4"
 
+	"api.system.soluciones-cloud.com/internal/core/users/domain/entity"
 	"api.system.soluciones-cloud.com/internal/core/users/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/blob"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/openapi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
 )
 
-func RegisterUserRoutes(g *echo.Group, handler *presentation.UserHandler) {
+var userFilterParams = []openapi.Parameter{
+	openapi.QueryParam("origin", "Filter by origin", valid.String()),
+	openapi.QueryParam("first_name", "Filter by first name (partial match)", valid.String()),
+	openapi.QueryParam("last_name", "Filter by last name (partial match)", valid.String()),
+	openapi.QueryParam("is_active", "Filter by active status", valid.Bool()),
+	openapi.QueryParam("created_by", "Filter by creator ID", valid.String().UUID()),
+	openapi.QueryParam("updated_by", "Filter by last updater ID", valid.String().UUID()),
+}
+
+var userIDParam = openapi.PathParam("id", "User ID", valid.String().UUID())
+
+func RegisterUserRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.UserHandler) {
 	usersGroup := g.Group("/users")
 
-	usersGroup.POST("", handler.CreateUser)
-	usersGroup.GET("", handler.ListUsers)
-	usersGroup.GET("/count", handler.CountUsers)
-	usersGroup.GET("/:id", handler.GetUser)
-	usersGroup.PUT("/:id", handler.UpdateUser)
-	usersGroup.DELETE("/:id", handler.DeleteUser)
-	usersGroup.GET("/:id/exists", handler.UserExists)
-}
\ No newline at end of file
+	route := usersGroup.POST("", server.Handle(handler.CreateUser, server.WithStatus(http.StatusCreated)))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "createUser",
+		Summary:     "Create a new user",
+		Description: "Create a new user with the provided information",
+		Tags:        []string{"users"},
+		Permission:  "users.create",
+		Request:     entity.CreateUserRequest{},
+		Response:    response.Response[entity.User]{},
+		Status:      http.StatusCreated,
+	})
+
+	route = usersGroup.GET("", server.Handle(handler.ListUsers))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "listUsers",
+		Summary:     "List users",
+		Description: "List users with optional filtering, sorting, and pagination",
+		Tags:        []string{"users"},
+		Permission:  "users.read",
+		Parameters: append(userFilterParams,
+			openapi.QueryParam("page", "Page number (default 1)", valid.Int().Min(1)),
+			openapi.QueryParam("page_size", "Page size (default 10)", valid.Int().Range(1, entity.MaxPageSize)),
+			openapi.QueryParam("sort_by", "Sort by field", valid.Enum(entity.UserSortFields...)),
+			openapi.QueryParam("sort_order", "Sort order", valid.Enum("asc", "desc")),
+		),
+		Response: response.Response[types.List[entity.User]]{},
+	})
+
+	route = usersGroup.GET("/count", server.Handle(handler.CountUsers))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "countUsers",
+		Summary:     "Count users",
+		Description: "Count users with optional filtering",
+		Tags:        []string{"users"},
+		Permission:  "users.read",
+		Parameters:  userFilterParams,
+		Response:    response.Response[response.CountResponse]{},
+	})
+
+	route = usersGroup.GET("/:id", server.Handle(handler.GetUser))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "getUser",
+		Summary:     "Get user by ID",
+		Description: "Get a user by its ID",
+		Tags:        []string{"users"},
+		Permission:  "users.read",
+		Parameters:  []openapi.Parameter{userIDParam},
+		Response:    response.Response[entity.User]{},
+	})
+
+	route = usersGroup.PUT("/:id", server.Handle(handler.UpdateUser))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "updateUser",
+		Summary:     "Update user",
+		Description: "Update an existing user with the provided information",
+		Tags:        []string{"users"},
+		Permission:  "users.update",
+		Parameters:  []openapi.Parameter{userIDParam},
+		Request:     entity.UpdateUserRequest{},
+		Response:    response.Response[entity.User]{},
+	})
+
+	route = usersGroup.DELETE("/:id", server.Handle(handler.DeleteUser))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "deleteUser",
+		Summary:     "Delete user",
+		Description: "Soft delete a user by ID",
+		Tags:        []string{"users"},
+		Permission:  "users.delete",
+		Parameters:  []openapi.Parameter{userIDParam},
+		Request:     entity.DeleteUserRequest{},
+	})
+
+	route = usersGroup.GET("/:id/exists", server.Handle(handler.UserExists))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "userExists",
+		Summary:     "Check if user exists",
+		Description: "Check if a user exists by ID",
+		Tags:        []string{"users"},
+		Permission:  "users.read",
+		Parameters:  []openapi.Parameter{userIDParam},
+		Response:    response.Response[response.ExistsResponse]{},
+	})
+
+	route = usersGroup.POST("/:id/activate", server.Handle(handler.ActivateUser))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "activateUser",
+		Summary:     "Activate user",
+		Description: "Activate a user and restore the memberships deactivated with them. Sessions and API keys revoked on deactivation stay revoked.",
+		Tags:        []string{"users"},
+		Permission:  "users.activate",
+		Parameters:  []openapi.Parameter{userIDParam},
+		Response:    response.Response[entity.User]{},
+	})
+
+	route = usersGroup.POST("/:id/deactivate", server.Handle(handler.DeactivateUser))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "deactivateUser",
+		Summary:     "Deactivate user",
+		Description: "Deactivate a user, log them out of every device, revoke their API keys and deactivate their organization memberships. Callers cannot deactivate themselves.",
+		Tags:        []string{"users"},
+		Permission:  "users.activate",
+		Parameters:  []openapi.Parameter{userIDParam},
+		Response:    response.Response[entity.User]{},
+	})
+
+	route = usersGroup.POST("/activate", server.Handle(handler.ActivateUsers))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "activateUsers",
+		Summary:     "Activate users in bulk",
+		Description: "Activate the users matching the filters, like activateUser. At least one filter is required.",
+		Tags:        []string{"users"},
+		Permission:  "users.activate",
+		Parameters:  userFilterParams,
+		Response:    response.Response[entity.StatusChange]{},
+	})
+
+	route = usersGroup.POST("/deactivate", server.Handle(handler.DeactivateUsers))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "deactivateUsers",
+		Summary:     "Deactivate users in bulk",
+		Description: "Deactivate the users matching the filters but the caller, like deactivateUser. At least one filter is required.",
+		Tags:        []string{"users"},
+		Permission:  "users.activate",
+		Parameters:  userFilterParams,
+		Response:    response.Response[entity.StatusChange]{},
+	})
+
+	route = usersGroup.POST("/:id/merge", server.Handle(handler.MergeUsers))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:      "mergeUsers",
+		Summary: "Merge a duplicate user",
+		Description: "Move the created_by and updated_by references, memberships and roles of the duplicate user to the user of the path, " +
+			"then deactivate and delete the duplicate, in a single transaction. References of every organization are moved, " +
+			"so the request must be sent with the " + tenant.Header + " header set to " + tenant.AllOrganizations + ".",
+		Tags:       []string{"users"},
+		Permission: "users.merge",
+		Parameters: []openapi.Parameter{userIDParam},
+		Request:    entity.MergeUsersRequest{},
+		Response:   response.Response[entity.MergeResult]{},
+	})
+
+	route = g.GET("/me", server.Handle(handler.GetMe))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:            "getMe",
+		Summary:       "Get my profile",
+		Description:   "Get the user of the caller",
+		Tags:          []string{"users"},
+		Authenticated: true,
+		Response:      response.Response[entity.User]{},
+	})
+
+	route = g.PATCH("/me", server.Handle(handler.UpdateMe))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:            "updateMe",
+		Summary:       "Update my profile",
+		Description:   "Update the name and picture of the caller. Omitted fields are left as they are; an empty last name or picture clears it.",
+		Tags:          []string{"users"},
+		Authenticated: true,
+		Request:       entity.UpdateMeRequest{},
+		Response:      response.Response[entity.User]{},
+	})
+
+	route = g.GET("/me/permissions", server.Handle(handler.MyPermissions))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:            "getMyPermissions",
+		Summary:       "Get my permissions",
+		Description:   "Summarize the roles and granted actions of the caller in the organization of the request, for clients to show or hide features",
+		Tags:          []string{"users"},
+		Authenticated: true,
+		Response:      response.Response[entity.MyPermissions]{},
+	})
+
+	route = g.PUT("/me/avatar", server.Handle(handler.UploadAvatar))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:            "uploadMyAvatar",
+		Summary:       "Upload my avatar",
+		Description:   "Set the picture of the caller to a JPEG, PNG or GIF image, cropped to a square and scaled down. The previous avatar is deleted.",
+		Tags:          []string{"users"},
+		Authenticated: true,
+		Files:         []openapi.FileField{{Name: "file", Description: "Image", ContentTypes: blob.ImageTypes}},
+		Response:      response.Response[entity.User]{},
+	})
+
+	route = g.DELETE("/me/avatar", server.Handle(handler.DeleteAvatar))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:            "deleteMyAvatar",
+		Summary:       "Delete my avatar",
+		Description:   "Clear the picture of the caller, deleting the uploaded avatar",
+		Tags:          []string{"users"},
+		Authenticated: true,
+		Response:      response.Response[entity.User]{},
+	})
+}
+
+// RegisterAvatarRoutes registers the public route user pictures link to, so
+// they can be shown in img tags without credentials.
+func RegisterAvatarRoutes(g *echo.Group, docs *openapi.Registry, handler *presentation.UserHandler) {
+	route := g.GET("/avatars/:id/:name", server.Handle(handler.GetAvatar))
+	docs.Add(route.Method, route.Path, openapi.Operation{
+		ID:          "getAvatar",
+		Summary:     "Get an avatar",
+		Description: "Redirect to a signed URL of an uploaded avatar",
+		Tags:        []string{"users"},
+		Parameters: []openapi.Parameter{
+			userIDParam,
+			openapi.PathParam("name", "File name", valid.String()),
+		},
+		Status: http.StatusFound,
+	})
+}
@@ -2,10 +2,21 @@ package main
 
 import (
 	"api.system.soluciones-cloud.com/cmd/api/router"
+	"api.system.soluciones-cloud.com/internal/core/apikeys"
+	"api.system.soluciones-cloud.com/internal/core/audit"
+	"api.system.soluciones-cloud.com/internal/core/auth"
+	"api.system.soluciones-cloud.com/internal/core/companies"
+	"api.system.soluciones-cloud.com/internal/core/contacts"
+	"api.system.soluciones-cloud.com/internal/core/files"
+	"api.system.soluciones-cloud.com/internal/core/organizations"
+	"api.system.soluciones-cloud.com/internal/core/roles"
 	"api.system.soluciones-cloud.com/internal/core/users"
+	"api.system.soluciones-cloud.com/internal/shared/auth/token"
+	"api.system.soluciones-cloud.com/internal/shared/blob"
 	"api.system.soluciones-cloud.com/internal/shared/http/server"
 	"api.system.soluciones-cloud.com/internal/shared/localconfig"
 	"api.system.soluciones-cloud.com/internal/shared/logger"
+	"api.system.soluciones-cloud.com/internal/shared/mail"
 	"api.system.soluciones-cloud.com/internal/shared/repository/postgres"
 
 	"go.uber.org/fx"
@@ -19,8 +30,21 @@ func Run() {
 		localconfig.Module,
 		logger.Module,
 		postgres.Module,
+		token.Module,
+		mail.Module,
+		blob.Module,
 		users.Module,
+		auth.Module,
+		audit.Module,
+		roles.Module,
+		organizations.Module,
+		apikeys.Module,
+		files.Module,
+		companies.Module,
+		contacts.Module,
 		server.Module,
+		// Runs before the routes are served
+		fx.Invoke(syncPermissions),
 		fx.Invoke(router.SetAPIRoutes),
 		// fx.NopLogger, // Disable fx's own logging to use our custom logger
 	)
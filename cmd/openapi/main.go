@@ -0,0 +1,48 @@
+// Command openapi writes the OpenAPI spec of the API routes.
+//
+//	go run ./cmd/openapi                  # writes cmd/api/docs/openapi.json
+//	go run ./cmd/openapi -check           # fails when the committed spec is stale
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"api.system.soluciones-cloud.com/cmd/api/router"
+)
+
+func main() {
+	out := flag.String("out", "cmd/api/docs/openapi.json", "file the spec is written to")
+	check := flag.Bool("check", false, "compare the spec with -out instead of writing it")
+	flag.Parse()
+
+	if err := run(*out, *check); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, check bool) error {
+	spec, err := router.Describe().JSON()
+	if err != nil {
+		return fmt.Errorf("failed to generate OpenAPI spec: %w", err)
+	}
+
+	if check {
+		current, err := os.ReadFile(out)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", out, err)
+		}
+		if !bytes.Equal(current, spec) {
+			return fmt.Errorf("%s is out of date, run: go run ./cmd/openapi", out)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(out, spec, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+	return nil
+}
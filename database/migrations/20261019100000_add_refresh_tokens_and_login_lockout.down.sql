@@ -0,0 +1,12 @@
+-- Revert Refresh Tokens and Login Lockout Migration
+
+BEGIN;
+
+ALTER TABLE auth.email_credentials
+DROP COLUMN last_login_at,
+DROP COLUMN locked_until,
+DROP COLUMN failed_login_attempts;
+
+DROP TABLE IF EXISTS auth.refresh_tokens;
+
+COMMIT;
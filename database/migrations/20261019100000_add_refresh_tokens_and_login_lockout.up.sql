@@ -0,0 +1,31 @@
+-- Refresh Tokens and Login Lockout Migration
+-- 1. auth.refresh_tokens stores the SHA-256 hash of every refresh token.
+--    Tokens are rotated on use; all tokens issued from the same login share
+--    a family_id, so reusing a rotated token revokes the whole family.
+-- 2. auth.email_credentials tracks consecutive failed logins to lock the
+--    account for a while.
+
+BEGIN;
+
+CREATE TABLE auth.refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    replaced_by UUID REFERENCES auth.refresh_tokens(id) ON DELETE SET NULL,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON auth.refresh_tokens(user_id);
+CREATE INDEX idx_refresh_tokens_family_id ON auth.refresh_tokens(family_id);
+
+ALTER TABLE auth.email_credentials
+ADD COLUMN failed_login_attempts INTEGER DEFAULT 0 NOT NULL,
+ADD COLUMN locked_until TIMESTAMP,
+ADD COLUMN last_login_at TIMESTAMP;
+
+COMMIT;
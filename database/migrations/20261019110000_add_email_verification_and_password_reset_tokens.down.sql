@@ -0,0 +1,14 @@
+-- Revert Email Verification and Password Reset Tokens Migration
+
+BEGIN;
+
+DROP INDEX IF EXISTS auth.idx_email_credentials_password_reset_token;
+DROP INDEX IF EXISTS auth.idx_email_credentials_verification_token;
+
+ALTER TABLE auth.email_credentials
+DROP COLUMN password_reset_expires_at,
+DROP COLUMN password_reset_token,
+DROP COLUMN verification_expires_at,
+DROP COLUMN verification_token;
+
+COMMIT;
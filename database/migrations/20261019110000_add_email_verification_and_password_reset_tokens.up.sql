@@ -0,0 +1,23 @@
+-- Email Verification and Password Reset Tokens Migration
+-- Restores the token columns of auth.email_credentials dropped by the auth
+-- schema restructure. They store the SHA-256 hex hash of single-use tokens
+-- sent by email, never the token itself, and are cleared when the token is
+-- used.
+
+BEGIN;
+
+ALTER TABLE auth.email_credentials
+ADD COLUMN verification_token VARCHAR(64),
+ADD COLUMN verification_expires_at TIMESTAMP,
+ADD COLUMN password_reset_token VARCHAR(64),
+ADD COLUMN password_reset_expires_at TIMESTAMP;
+
+CREATE UNIQUE INDEX idx_email_credentials_verification_token
+ON auth.email_credentials(verification_token)
+WHERE verification_token IS NOT NULL;
+
+CREATE UNIQUE INDEX idx_email_credentials_password_reset_token
+ON auth.email_credentials(password_reset_token)
+WHERE password_reset_token IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,31 @@
+-- Rollback RBAC Visibility Scopes and Users Module Migration
+-- ORG actions go back to INTERNAL; OWN actions, which had no equivalent,
+-- go back to ALL.
+
+BEGIN;
+
+DELETE FROM auth.permissions
+WHERE module_action_id IN (
+    SELECT ma.id FROM auth.module_actions ma
+    JOIN auth.modules m ON m.id = ma.module_id
+    WHERE m.code = 'users'
+);
+DELETE FROM auth.module_actions WHERE module_id = (SELECT id FROM auth.modules WHERE code = 'users');
+DELETE FROM auth.modules WHERE code = 'users';
+
+ALTER TABLE auth.module_actions DROP CONSTRAINT IF EXISTS module_actions_visibility_scope_check;
+
+UPDATE auth.module_actions SET visibility_scope = 'INTERNAL' WHERE visibility_scope = 'ORG';
+UPDATE auth.module_actions SET visibility_scope = 'ALL' WHERE visibility_scope = 'OWN';
+
+DELETE FROM config.catalog_options
+WHERE catalog_type_id = (SELECT id FROM config.catalog_types WHERE code = 'visibility_scopes')
+AND code IN ('org', 'own');
+
+INSERT INTO config.catalog_options (catalog_type_id, name, code, value, color_code, sort_order, is_active) VALUES
+((SELECT id FROM config.catalog_types WHERE code = 'visibility_scopes'), 'Interno', 'internal', 'INTERNAL', '#EF4444', 2, true),
+((SELECT id FROM config.catalog_types WHERE code = 'visibility_scopes'), 'Clientes', 'customers', 'CUSTOMERS', '#3B82F6', 3, true);
+
+COMMENT ON COLUMN auth.module_actions.visibility_scope IS 'Visibility scope: ALL, INTERNAL, CUSTOMERS';
+
+COMMIT;
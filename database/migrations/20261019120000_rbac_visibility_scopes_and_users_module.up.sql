@@ -0,0 +1,46 @@
+-- RBAC Visibility Scopes and Users Module Migration
+-- Replaces the INTERNAL and CUSTOMERS visibility scopes of module actions
+-- with the scopes enforced by the API: ALL (every row), ORG (rows of the
+-- organizations of the user) and OWN (rows owned by the user). Existing
+-- INTERNAL and CUSTOMERS actions become ORG.
+-- Seeds the users module with the actions its routes require, granted as
+-- "<module>.<action>" permission codes, e.g. users.read.
+
+BEGIN;
+
+-- =============================================================================
+-- 1. VISIBILITY SCOPES
+-- =============================================================================
+
+UPDATE auth.module_actions
+SET visibility_scope = 'ORG', updated_at = NOW()
+WHERE visibility_scope IN ('INTERNAL', 'CUSTOMERS');
+
+DELETE FROM config.catalog_options
+WHERE catalog_type_id = (SELECT id FROM config.catalog_types WHERE code = 'visibility_scopes')
+AND code IN ('internal', 'customers');
+
+INSERT INTO config.catalog_options (catalog_type_id, name, code, value, color_code, sort_order, is_active) VALUES
+((SELECT id FROM config.catalog_types WHERE code = 'visibility_scopes'), 'Organización', 'org', 'ORG', '#3B82F6', 2, true),
+((SELECT id FROM config.catalog_types WHERE code = 'visibility_scopes'), 'Propios', 'own', 'OWN', '#F59E0B', 3, true);
+
+ALTER TABLE auth.module_actions
+ADD CONSTRAINT module_actions_visibility_scope_check
+CHECK (visibility_scope IN ('ALL', 'ORG', 'OWN'));
+
+COMMENT ON COLUMN auth.module_actions.visibility_scope IS 'Visibility scope: ALL (every row), ORG (rows of the user organizations), OWN (rows owned by the user)';
+
+-- =============================================================================
+-- 2. USERS MODULE
+-- =============================================================================
+
+INSERT INTO auth.modules (name, code, description) VALUES
+('Usuarios', 'users', 'User management');
+
+INSERT INTO auth.module_actions (module_id, name, code, description, action_type, visibility_scope, is_public) VALUES
+((SELECT id FROM auth.modules WHERE code = 'users'), 'Crear usuarios', 'create', 'Create users', 'POST', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'users'), 'Ver usuarios', 'read', 'List, count and get users', 'GET', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'users'), 'Editar usuarios', 'update', 'Update users', 'PUT', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'users'), 'Eliminar usuarios', 'delete', 'Soft delete users', 'DELETE', 'ALL', false);
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- Rollback Role Management and Audit Logs Migration
+
+BEGIN;
+
+DELETE FROM auth.permissions
+WHERE module_action_id IN (
+    SELECT ma.id FROM auth.module_actions ma
+    JOIN auth.modules m ON m.id = ma.module_id
+    WHERE m.code = 'roles'
+);
+DELETE FROM auth.module_actions WHERE module_id = (SELECT id FROM auth.modules WHERE code = 'roles');
+DELETE FROM auth.modules WHERE code = 'roles';
+
+DROP TABLE IF EXISTS auth.audit_logs;
+
+DROP INDEX IF EXISTS auth.roles_org_code_uk;
+ALTER TABLE auth.roles DROP COLUMN IF EXISTS is_system_role;
+
+COMMIT;
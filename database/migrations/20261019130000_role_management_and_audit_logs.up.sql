@@ -0,0 +1,62 @@
+-- Role Management and Audit Logs Migration
+-- 1. auth.roles.is_system_role comes back: system roles are seeded with
+--    the database and cannot be edited through the API. Codes of
+--    organization-wide roles become unique.
+-- 2. auth.audit_logs records who changed roles, role assignments and
+--    permissions, and how.
+-- 3. Seeds the roles module with the actions its routes require.
+
+BEGIN;
+
+-- =============================================================================
+-- 1. SYSTEM ROLES
+-- =============================================================================
+
+ALTER TABLE auth.roles
+ADD COLUMN is_system_role BOOLEAN DEFAULT false NOT NULL;
+
+COMMENT ON COLUMN auth.roles.is_system_role IS 'System roles are managed by migrations and cannot be modified through the API';
+
+-- roles_org_company_code_uk does not apply to organization-wide roles,
+-- since their company_id is NULL
+CREATE UNIQUE INDEX roles_org_code_uk ON auth.roles(organization_id, code) WHERE company_id IS NULL;
+
+-- =============================================================================
+-- 2. AUDIT LOGS
+-- =============================================================================
+
+CREATE TABLE auth.audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID REFERENCES auth.organizations(id) ON DELETE SET NULL,
+    actor_id UUID REFERENCES auth.users(id) ON DELETE SET NULL,
+    action VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id UUID NOT NULL,
+    changes JSONB,
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_audit_logs_entity ON auth.audit_logs(entity_type, entity_id);
+CREATE INDEX idx_audit_logs_actor_id ON auth.audit_logs(actor_id);
+CREATE INDEX idx_audit_logs_created_at ON auth.audit_logs(created_at);
+
+COMMENT ON TABLE auth.audit_logs IS 'Append-only log of changes made through the API';
+COMMENT ON COLUMN auth.audit_logs.action IS 'What happened, e.g. role.created or role.permissions_granted';
+COMMENT ON COLUMN auth.audit_logs.changes IS 'Details of the change, e.g. the granted permission codes';
+
+-- =============================================================================
+-- 3. ROLES MODULE
+-- =============================================================================
+
+INSERT INTO auth.modules (name, code, description) VALUES
+('Roles', 'roles', 'Role and permission management');
+
+INSERT INTO auth.module_actions (module_id, name, code, description, action_type, visibility_scope, is_public) VALUES
+((SELECT id FROM auth.modules WHERE code = 'roles'), 'Crear roles', 'create', 'Create roles', 'POST', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'roles'), 'Ver roles', 'read', 'List and get roles, their permissions and the roles of users', 'GET', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'roles'), 'Editar roles', 'update', 'Update roles', 'PUT', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'roles'), 'Eliminar roles', 'delete', 'Delete roles', 'DELETE', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'roles'), 'Asignar roles', 'assign', 'Assign and revoke the roles of users', 'POST', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'roles'), 'Otorgar permisos', 'grant', 'Grant and revoke the permissions of roles', 'POST', 'ALL', false);
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- Rollback Tenant Row-Level Security Migration
+
+BEGIN;
+
+DO $$
+DECLARE
+    target RECORD;
+BEGIN
+    FOR target IN
+        SELECT schemaname, tablename FROM pg_policies WHERE policyname = 'tenant_isolation'
+    LOOP
+        EXECUTE format('DROP POLICY tenant_isolation ON %I.%I', target.schemaname, target.tablename);
+        EXECUTE format('ALTER TABLE %I.%I DISABLE ROW LEVEL SECURITY', target.schemaname, target.tablename);
+    END LOOP;
+END
+$$;
+
+DROP FUNCTION IF EXISTS auth.enable_tenant_isolation(REGCLASS);
+DROP FUNCTION IF EXISTS auth.tenant_visible(UUID);
+
+ALTER DEFAULT PRIVILEGES IN SCHEMA auth, config, relationships, catalog, billing, sales, support, agreements, accounting
+REVOKE SELECT, INSERT, UPDATE, DELETE ON TABLES FROM api_tenant;
+ALTER DEFAULT PRIVILEGES IN SCHEMA auth, config, relationships, catalog, billing, sales, support, agreements, accounting
+REVOKE USAGE, SELECT ON SEQUENCES FROM api_tenant;
+
+DROP OWNED BY api_tenant;
+DROP ROLE IF EXISTS api_tenant;
+
+COMMIT;
@@ -0,0 +1,102 @@
+-- Tenant Row-Level Security Migration
+-- Defense in depth for tenancy: business tables only return and accept the
+-- rows of the organizations of the current request, even when a query
+-- forgets to filter them.
+-- 1. The api_tenant role. The API switches to it (SET ROLE) while serving a
+--    tenant, so policies also apply when it connects as the owner of the
+--    tables or as a superuser.
+-- 2. auth.tenant_visible reads the organizations of the request from the
+--    app.organization_id setting: a comma separated list of ids, or '*'
+--    for every organization.
+-- 3. auth.enable_tenant_isolation enables RLS on a table with an
+--    organization_id column. Migrations creating tenant tables call it.
+-- 4. Enables tenant isolation on every business table.
+
+BEGIN;
+
+-- =============================================================================
+-- 1. TENANT ROLE
+-- =============================================================================
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'api_tenant') THEN
+        CREATE ROLE api_tenant NOLOGIN;
+    END IF;
+END
+$$;
+
+-- The user running the migrations is the one the API connects with. Any
+-- other user of the API needs the same grant to switch to the role.
+GRANT api_tenant TO CURRENT_USER;
+
+GRANT USAGE ON SCHEMA auth, config, relationships, catalog, billing, sales, support, agreements, accounting TO api_tenant;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA auth, config, relationships, catalog, billing, sales, support, agreements, accounting TO api_tenant;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA auth, config, relationships, catalog, billing, sales, support, agreements, accounting TO api_tenant;
+
+ALTER DEFAULT PRIVILEGES IN SCHEMA auth, config, relationships, catalog, billing, sales, support, agreements, accounting
+GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO api_tenant;
+ALTER DEFAULT PRIVILEGES IN SCHEMA auth, config, relationships, catalog, billing, sales, support, agreements, accounting
+GRANT USAGE, SELECT ON SEQUENCES TO api_tenant;
+
+-- =============================================================================
+-- 2. TENANT VISIBILITY
+-- =============================================================================
+
+-- Without app.organization_id the request has no tenant: api_tenant sees
+-- nothing, other roles are not restricted. Rows without organization only
+-- belong to '*'.
+CREATE FUNCTION auth.tenant_visible(organization_id UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT CASE COALESCE(current_setting('app.organization_id', true), '')
+        WHEN '' THEN current_user <> 'api_tenant'
+        WHEN '*' THEN true
+        ELSE COALESCE(organization_id = ANY (string_to_array(current_setting('app.organization_id', true), ',')::UUID[]), false)
+    END
+$$;
+
+COMMENT ON FUNCTION auth.tenant_visible(UUID) IS 'Whether a row of the organization belongs to the tenant of the request (app.organization_id)';
+
+-- =============================================================================
+-- 3. TENANT ISOLATION
+-- =============================================================================
+
+CREATE FUNCTION auth.enable_tenant_isolation(target REGCLASS) RETURNS VOID
+LANGUAGE plpgsql AS $$
+BEGIN
+    EXECUTE format('ALTER TABLE %s ENABLE ROW LEVEL SECURITY', target);
+    EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %s', target);
+    EXECUTE format(
+        'CREATE POLICY tenant_isolation ON %s USING (auth.tenant_visible(organization_id)) WITH CHECK (auth.tenant_visible(organization_id))',
+        target
+    );
+END
+$$;
+
+COMMENT ON FUNCTION auth.enable_tenant_isolation(REGCLASS) IS 'Restricts the rows of a table with organization_id to the tenant of the request';
+
+-- =============================================================================
+-- 4. BUSINESS TABLES
+-- =============================================================================
+
+-- The auth schema is not isolated: memberships, roles and permissions are
+-- resolved across organizations before the tenant of a request is known.
+DO $$
+DECLARE
+    target RECORD;
+BEGIN
+    FOR target IN
+        SELECT c.table_schema, c.table_name
+        FROM information_schema.columns c
+        JOIN information_schema.tables t
+          ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+        WHERE c.column_name = 'organization_id'
+          AND t.table_type = 'BASE TABLE'
+          AND c.table_schema IN ('relationships', 'catalog', 'billing', 'sales', 'support', 'agreements', 'accounting')
+    LOOP
+        PERFORM auth.enable_tenant_isolation(format('%I.%I', target.table_schema, target.table_name)::REGCLASS);
+    END LOOP;
+END
+$$;
+
+COMMIT;
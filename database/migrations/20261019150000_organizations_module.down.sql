@@ -0,0 +1,7 @@
+-- Rollback Organizations Module Migration
+
+BEGIN;
+
+DROP INDEX IF EXISTS auth.organization_users_org_user_uk;
+
+COMMIT;
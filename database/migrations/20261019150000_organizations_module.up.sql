@@ -0,0 +1,40 @@
+-- Organizations Module Migration
+-- 1. A user belongs once to an organization without customer: the unique
+--    (organization_id, user_id, customer_id) constraint treats NULL
+--    customers as distinct, so duplicates are merged and a partial index
+--    covers them.
+-- The organizations module and its actions are created by the permission
+-- sync from the routes, see go run ./cmd/api sync-permissions.
+
+BEGIN;
+
+-- =============================================================================
+-- 1. MEMBERSHIPS WITHOUT CUSTOMER
+-- =============================================================================
+
+-- Keeps the oldest membership of each duplicate, active if any of them is
+UPDATE auth.organization_users ou
+SET is_active = true
+WHERE ou.customer_id IS NULL
+  AND NOT ou.is_active
+  AND EXISTS (
+      SELECT 1 FROM auth.organization_users other
+      WHERE other.organization_id = ou.organization_id
+        AND other.user_id = ou.user_id
+        AND other.customer_id IS NULL
+        AND other.is_active
+  );
+
+DELETE FROM auth.organization_users ou
+USING auth.organization_users older
+WHERE ou.customer_id IS NULL
+  AND older.customer_id IS NULL
+  AND older.organization_id = ou.organization_id
+  AND older.user_id = ou.user_id
+  AND (older.created_at, older.id) < (ou.created_at, ou.id);
+
+CREATE UNIQUE INDEX organization_users_org_user_uk
+ON auth.organization_users (organization_id, user_id)
+WHERE customer_id IS NULL;
+
+COMMIT;
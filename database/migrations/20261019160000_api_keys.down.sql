@@ -0,0 +1,23 @@
+-- Rollback API Keys Migration
+
+BEGIN;
+
+DELETE FROM auth.permissions
+WHERE module_action_id IN (
+    SELECT ma.id FROM auth.module_actions ma
+    JOIN auth.modules m ON m.id = ma.module_id
+    WHERE m.code = 'api_keys'
+);
+DELETE FROM auth.module_actions WHERE module_id = (SELECT id FROM auth.modules WHERE code = 'api_keys');
+DELETE FROM auth.modules WHERE code = 'api_keys';
+
+DROP TABLE IF EXISTS auth.api_key_actions;
+DROP TABLE IF EXISTS auth.api_keys;
+
+-- Service accounts may be referenced by the rows they created, so they are
+-- deleted softly
+UPDATE auth.users
+SET is_active = false, deleted_at = NOW()
+WHERE origin = 'API_KEY' AND deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,62 @@
+-- API Keys Migration
+-- 1. auth.api_keys lets integrations and scripts call the API without a
+--    human login. Every key acts as its own service account user (origin
+--    API_KEY) within a single organization. Only the SHA-256 hash of a key
+--    is stored; its prefix identifies it in listings and lookups.
+-- 2. auth.api_key_actions holds the module actions a key is granted.
+--    Keys get no roles: they are only granted these actions.
+-- 3. Seeds the api_keys module with the actions its routes require.
+
+BEGIN;
+
+-- =============================================================================
+-- 1. API KEYS
+-- =============================================================================
+
+CREATE TABLE auth.api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES auth.organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL UNIQUE REFERENCES auth.users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(32) NOT NULL UNIQUE,
+    key_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    revoked_by UUID REFERENCES auth.users(id),
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+    created_by UUID REFERENCES auth.users(id)
+);
+
+CREATE INDEX idx_api_keys_organization_id ON auth.api_keys(organization_id);
+
+COMMENT ON TABLE auth.api_keys IS 'Keys of service accounts for machine-to-machine access';
+COMMENT ON COLUMN auth.api_keys.user_id IS 'Service account the key acts as, e.g. in created_by columns and audit logs';
+COMMENT ON COLUMN auth.api_keys.prefix IS 'Public part of the key, shown in listings and used to look it up';
+COMMENT ON COLUMN auth.api_keys.key_hash IS 'Hex SHA-256 of the whole key, which is only shown when it is created';
+
+-- =============================================================================
+-- 2. API KEY ACTIONS
+-- =============================================================================
+
+CREATE TABLE auth.api_key_actions (
+    api_key_id UUID NOT NULL REFERENCES auth.api_keys(id) ON DELETE CASCADE,
+    module_action_id UUID NOT NULL REFERENCES auth.module_actions(id) ON DELETE CASCADE,
+    PRIMARY KEY (api_key_id, module_action_id)
+);
+
+COMMENT ON TABLE auth.api_key_actions IS 'Module actions granted to API keys, with the visibility scope of the action';
+
+-- =============================================================================
+-- 3. API KEYS MODULE
+-- =============================================================================
+
+INSERT INTO auth.modules (name, code, description) VALUES
+('Claves de API', 'api_keys', 'API key management');
+
+INSERT INTO auth.module_actions (module_id, name, code, description, action_type, visibility_scope, is_public) VALUES
+((SELECT id FROM auth.modules WHERE code = 'api_keys'), 'Crear claves de API', 'create', 'Create API keys with actions the creator is granted', 'POST', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'api_keys'), 'Ver claves de API', 'read', 'List and get API keys, never their secret', 'GET', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'api_keys'), 'Revocar claves de API', 'revoke', 'Revoke API keys', 'DELETE', 'ALL', false);
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Rollback OIDC Login Migration
+
+BEGIN;
+
+DROP TABLE IF EXISTS auth.oidc_login_states;
+DROP TABLE IF EXISTS auth.user_identities;
+
+COMMIT;
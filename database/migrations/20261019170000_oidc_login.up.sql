@@ -0,0 +1,51 @@
+-- OIDC Login Migration
+-- 1. auth.user_identities links users to the accounts they log in with at
+--    an OpenID Connect provider, by the subject of the provider. A user may
+--    have one identity per provider next to their email credential.
+-- 2. auth.oidc_login_states holds the logins started with a provider until
+--    the user comes back with the code. Only the SHA-256 hash of the state
+--    is stored; it is deleted when used or expired.
+
+BEGIN;
+
+-- =============================================================================
+-- 1. USER IDENTITIES
+-- =============================================================================
+
+CREATE TABLE auth.user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+    last_login_at TIMESTAMP,
+    UNIQUE (provider, subject),
+    UNIQUE (user_id, provider)
+);
+
+COMMENT ON TABLE auth.user_identities IS 'Accounts of users at OpenID Connect providers';
+COMMENT ON COLUMN auth.user_identities.subject IS 'sub claim of the ID tokens of the provider, stable for the account';
+COMMENT ON COLUMN auth.user_identities.email IS 'Email claim of the first login, for reference only';
+
+-- =============================================================================
+-- 2. LOGIN STATES
+-- =============================================================================
+
+CREATE TABLE auth.oidc_login_states (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    provider VARCHAR(50) NOT NULL,
+    state_hash VARCHAR(64) NOT NULL UNIQUE,
+    code_verifier VARCHAR(128) NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_oidc_login_states_expires_at ON auth.oidc_login_states(expires_at);
+
+COMMENT ON TABLE auth.oidc_login_states IS 'OpenID Connect logins waiting for the authorization code';
+COMMENT ON COLUMN auth.oidc_login_states.state_hash IS 'Hex SHA-256 of the state parameter sent to the provider';
+COMMENT ON COLUMN auth.oidc_login_states.code_verifier IS 'PKCE code verifier, sent with the code to the token endpoint';
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Rollback Sessions Migration
+
+BEGIN;
+
+DELETE FROM auth.permissions
+WHERE module_action_id IN (
+    SELECT ma.id FROM auth.module_actions ma
+    JOIN auth.modules m ON m.id = ma.module_id
+    WHERE m.code = 'users' AND ma.code = 'sessions'
+);
+DELETE FROM auth.module_actions
+WHERE module_id = (SELECT id FROM auth.modules WHERE code = 'users') AND code = 'sessions';
+
+ALTER TABLE auth.refresh_tokens DROP CONSTRAINT IF EXISTS fk_refresh_tokens_session;
+DROP TABLE IF EXISTS auth.sessions;
+
+COMMIT;
@@ -0,0 +1,73 @@
+-- Sessions Migration
+-- 1. auth.sessions lists the logins of users, for them to review and end
+--    their devices. A session is a family of refresh tokens: its id is the
+--    family_id of the tokens, and access tokens carry it in the sid claim.
+--    The client and last_seen_at follow the latest refresh and request;
+--    organization_id is the organization the session last acted on.
+-- 2. Creates the sessions of the existing refresh token families.
+-- 3. Seeds the users.sessions action of the admin session routes.
+
+BEGIN;
+
+-- =============================================================================
+-- 1. SESSIONS
+-- =============================================================================
+
+CREATE TABLE auth.sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    organization_id UUID REFERENCES auth.organizations(id) ON DELETE SET NULL,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+    last_seen_at TIMESTAMP DEFAULT NOW() NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    revoked_by UUID REFERENCES auth.users(id)
+);
+
+CREATE INDEX idx_sessions_user_id ON auth.sessions(user_id);
+CREATE INDEX idx_sessions_revoked_at ON auth.sessions(revoked_at) WHERE revoked_at IS NOT NULL;
+
+COMMENT ON TABLE auth.sessions IS 'Logins of users, each with its family of refresh tokens';
+COMMENT ON COLUMN auth.sessions.id IS 'family_id of the refresh tokens of the session, sid claim of its access tokens';
+COMMENT ON COLUMN auth.sessions.organization_id IS 'Organization the session last acted on, if any';
+COMMENT ON COLUMN auth.sessions.expires_at IS 'Expiry of the latest refresh token of the session';
+COMMENT ON COLUMN auth.sessions.revoked_by IS 'User who ended the session, NULL when it ended on its own, e.g. on logout';
+
+-- =============================================================================
+-- 2. EXISTING SESSIONS
+-- =============================================================================
+
+-- The client is the one of the latest token of each family; a family is
+-- revoked once all its tokens are
+INSERT INTO auth.sessions (id, user_id, user_agent, ip_address, created_at, last_seen_at, expires_at, revoked_at)
+SELECT latest.family_id, latest.user_id, latest.user_agent, latest.ip_address,
+    family.created_at, family.last_seen_at, family.expires_at, family.revoked_at
+FROM (
+    SELECT DISTINCT ON (family_id) family_id, user_id, user_agent, ip_address
+    FROM auth.refresh_tokens
+    ORDER BY family_id, created_at DESC
+) latest
+JOIN (
+    SELECT family_id,
+        MIN(created_at) AS created_at,
+        MAX(created_at) AS last_seen_at,
+        MAX(expires_at) AS expires_at,
+        CASE WHEN BOOL_AND(revoked_at IS NOT NULL) THEN MAX(revoked_at) END AS revoked_at
+    FROM auth.refresh_tokens
+    GROUP BY family_id
+) family ON family.family_id = latest.family_id;
+
+ALTER TABLE auth.refresh_tokens
+ADD CONSTRAINT fk_refresh_tokens_session
+FOREIGN KEY (family_id) REFERENCES auth.sessions(id) ON DELETE CASCADE;
+
+-- =============================================================================
+-- 3. SESSION ACTIONS
+-- =============================================================================
+
+INSERT INTO auth.module_actions (module_id, name, code, description, action_type, visibility_scope, is_public) VALUES
+((SELECT id FROM auth.modules WHERE code = 'users'), 'Gestionar sesiones', 'sessions', 'List and end the sessions of users', 'GET', 'ALL', false);
+
+COMMIT;
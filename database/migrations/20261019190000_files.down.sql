@@ -0,0 +1,15 @@
+-- Rollback Files Migration
+-- Stored files are left in the blob store.
+
+BEGIN;
+
+DELETE FROM auth.permissions
+WHERE module_action_id IN (
+    SELECT ma.id FROM auth.module_actions ma
+    JOIN auth.modules m ON m.id = ma.module_id
+    WHERE m.code = 'files'
+);
+DELETE FROM auth.module_actions WHERE module_id = (SELECT id FROM auth.modules WHERE code = 'files');
+DELETE FROM auth.modules WHERE code = 'files';
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- Files Migration
+-- Uploaded files live in the blob store (local directory or S3 bucket), not
+-- in the database: rows keep their key, e.g. in file_url columns.
+
+BEGIN;
+
+INSERT INTO auth.modules (name, code, description) VALUES
+('Archivos', 'files', 'Uploaded files of the organization');
+
+INSERT INTO auth.module_actions (module_id, name, code, description, action_type, visibility_scope, is_public) VALUES
+((SELECT id FROM auth.modules WHERE code = 'files'), 'Subir archivos', 'upload', 'Upload files to the organization', 'POST', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'files'), 'Descargar archivos', 'read', 'Get signed download URLs of the files of the organization', 'GET', 'ALL', false);
+
+COMMIT;
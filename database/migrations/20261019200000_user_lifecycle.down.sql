@@ -0,0 +1,17 @@
+-- Rollback User Lifecycle Migration
+-- Memberships deactivated with their users stay inactive.
+
+BEGIN;
+
+DELETE FROM auth.permissions
+WHERE module_action_id IN (
+    SELECT ma.id FROM auth.module_actions ma
+    JOIN auth.modules m ON m.id = ma.module_id
+    WHERE m.code = 'users' AND ma.code IN ('activate', 'merge')
+);
+DELETE FROM auth.module_actions
+WHERE module_id = (SELECT id FROM auth.modules WHERE code = 'users') AND code IN ('activate', 'merge');
+
+ALTER TABLE auth.organization_users DROP COLUMN IF EXISTS deactivated_with_user;
+
+COMMIT;
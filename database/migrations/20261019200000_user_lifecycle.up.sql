@@ -0,0 +1,26 @@
+-- User Lifecycle Migration
+-- 1. Deactivating a user deactivates their memberships. The ones it
+--    deactivated are flagged, so activating the user restores them and
+--    leaves alone the memberships admins deactivated.
+-- 2. Seeds the users.activate and users.merge actions.
+
+BEGIN;
+
+-- =============================================================================
+-- 1. MEMBERSHIPS OF DEACTIVATED USERS
+-- =============================================================================
+
+ALTER TABLE auth.organization_users
+ADD COLUMN deactivated_with_user BOOLEAN DEFAULT false NOT NULL;
+
+COMMENT ON COLUMN auth.organization_users.deactivated_with_user IS 'Set when the membership was deactivated with its user, which restores it when activated';
+
+-- =============================================================================
+-- 2. LIFECYCLE ACTIONS
+-- =============================================================================
+
+INSERT INTO auth.module_actions (module_id, name, code, description, action_type, visibility_scope, is_public) VALUES
+((SELECT id FROM auth.modules WHERE code = 'users'), 'Activar usuarios', 'activate', 'Activate and deactivate users, one by one or in bulk', 'POST', 'ALL', false),
+((SELECT id FROM auth.modules WHERE code = 'users'), 'Fusionar usuarios', 'merge', 'Merge duplicate users into a canonical one', 'POST', 'ALL', false);
+
+COMMIT;
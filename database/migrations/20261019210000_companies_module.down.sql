@@ -0,0 +1,8 @@
+-- Rollback Companies Module Migration
+
+BEGIN;
+
+DROP INDEX IF EXISTS relationships.idx_companies_tax_id;
+DROP INDEX IF EXISTS auth.idx_organization_customers_customer_id;
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Companies Module Migration
+-- 1. Companies belong to organizations through auth.organization_customers.
+--    Indexes the lookups of companies by organization and by tax id.
+-- The companies module and its actions are created by the permission sync
+-- from the routes, see go run ./cmd/api sync-permissions.
+
+BEGIN;
+
+-- =============================================================================
+-- 1. INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_organization_customers_customer_id
+ON auth.organization_customers (customer_id);
+
+CREATE INDEX IF NOT EXISTS idx_companies_tax_id
+ON relationships.companies (tax_id)
+WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Normalize Company Tax IDs Rollback
+-- This migration cannot be reverted: the up migration overwrote the
+-- original formatting of the tax ids without keeping it, so they stay
+-- normalized and this rollback changes nothing.
+
+BEGIN;
+
+COMMIT;
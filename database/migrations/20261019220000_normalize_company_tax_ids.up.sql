@@ -0,0 +1,13 @@
+-- Normalize Company Tax IDs Migration
+-- Tax ids are stored without spaces, dots or hyphens and upper cased, so
+-- duplicates written in different formats are found.
+-- This migration cannot be reverted: the original formatting of the tax
+-- ids is overwritten and not kept anywhere.
+
+BEGIN;
+
+UPDATE relationships.companies
+SET tax_id = NULLIF(upper(regexp_replace(tax_id, '[[:space:].-]', '', 'g')), '')
+WHERE tax_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Rollback Contacts Module Migration
+-- Contact types and emails stay normalized.
+
+BEGIN;
+
+DROP INDEX IF EXISTS relationships.idx_contacts_company_email;
+
+ALTER TABLE relationships.contacts
+DROP CONSTRAINT IF EXISTS contacts_primary_types_check,
+DROP COLUMN IF EXISTS primary_types;
+
+COMMIT;
@@ -0,0 +1,58 @@
+-- Contacts Module Migration
+-- 1. Contact types are the values of the contact_type catalog, e.g.
+--    ["BILLING"], and contacts can be the primary contact of their company
+--    for some of their types (primary_types).
+-- 2. Emails are stored trimmed and lower cased, and unique among the live
+--    contacts of a company in an organization. Existing duplicates are
+--    moved to the notes of the newer contacts.
+-- The contacts module and its actions are created by the permission sync
+-- from the routes, see go run ./cmd/api sync-permissions.
+
+BEGIN;
+
+-- =============================================================================
+-- 1. CONTACT TYPES
+-- =============================================================================
+
+UPDATE relationships.contacts
+SET contact_types = CASE
+    WHEN jsonb_typeof(contact_types) = 'array' THEN COALESCE(
+        (SELECT jsonb_agg(DISTINCT upper(t)) FROM jsonb_array_elements_text(contact_types) t),
+        '[]'::jsonb
+    )
+    ELSE '[]'::jsonb
+END;
+
+ALTER TABLE relationships.contacts
+ADD COLUMN IF NOT EXISTS primary_types JSONB DEFAULT '[]' NOT NULL,
+ADD CONSTRAINT contacts_primary_types_check CHECK (contact_types @> primary_types);
+
+COMMENT ON COLUMN relationships.contacts.contact_types IS 'Values of the contact_type catalog, e.g. ["BILLING", "TECHNICAL"]';
+COMMENT ON COLUMN relationships.contacts.primary_types IS 'Contact types the contact is the primary contact of its company for, a subset of contact_types';
+
+-- =============================================================================
+-- 2. EMAILS
+-- =============================================================================
+
+UPDATE relationships.contacts
+SET email = NULLIF(lower(btrim(email)), '')
+WHERE email IS NOT NULL;
+
+UPDATE relationships.contacts c
+SET notes = concat_ws(E'\n', c.notes, 'Duplicate email: ' || c.email),
+    email = NULL
+FROM (
+    SELECT id, row_number() OVER (
+        PARTITION BY company_id, organization_id, email
+        ORDER BY created_at, id
+    ) AS position
+    FROM relationships.contacts
+    WHERE email IS NOT NULL AND deleted_at IS NULL
+) duplicates
+WHERE c.id = duplicates.id AND duplicates.position > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contacts_company_email
+ON relationships.contacts (company_id, organization_id, email) NULLS NOT DISTINCT
+WHERE deleted_at IS NULL AND email IS NOT NULL;
+
+COMMIT;
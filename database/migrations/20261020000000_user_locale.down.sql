@@ -0,0 +1,7 @@
+-- Rollback User Locale Migration
+
+BEGIN;
+
+ALTER TABLE auth.users DROP COLUMN IF EXISTS locale;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- User Locale Migration
+-- Stores the locale users prefer for API messages. It takes precedence
+-- over Accept-Language on authenticated requests; NULL falls back to it.
+
+BEGIN;
+
+ALTER TABLE auth.users
+ADD COLUMN locale VARCHAR(8);
+
+COMMENT ON COLUMN auth.users.locale IS 'Preferred locale for API messages, a lowercase BCP 47 primary language tag such as en or es';
+
+COMMIT;
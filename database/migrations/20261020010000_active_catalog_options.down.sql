@@ -0,0 +1,7 @@
+-- Rollback Active Catalog Options Migration
+
+BEGIN;
+
+DROP VIEW IF EXISTS config.active_catalog_options;
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Active Catalog Options Migration
+-- Lists the values of the active options of the active config catalogs by
+-- catalog code, so they can be checked with a single lookup per catalog.
+
+BEGIN;
+
+CREATE VIEW config.active_catalog_options AS
+SELECT t.code AS catalog, o.value
+FROM config.catalog_options o
+JOIN config.catalog_types t ON t.id = o.catalog_type_id
+WHERE t.is_active AND o.is_active AND o.value IS NOT NULL;
+
+COMMENT ON VIEW config.active_catalog_options IS 'Values of the active options of the active catalogs, by catalog code';
+
+COMMIT;
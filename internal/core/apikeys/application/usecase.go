@@ -0,0 +1,299 @@
+package application
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/core/apikeys/domain/entity"
+	auditentity "api.system.soluciones-cloud.com/internal/core/audit/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// apiKeyVisibility restricts OWN scopes to the keys created by the caller
+// and ORG scopes to the keys of the caller's organizations
+var apiKeyVisibility = rbac.Visibility{Owner: "created_by", Organization: "organization_id"}
+
+// auditAPIKey is the audited entity type of API keys
+const auditAPIKey = "api_key"
+
+// touchInterval throttles the updates of last_used_at, so busy keys do
+// not write on every request
+const touchInterval = time.Minute
+
+// APIKeyUseCase manages API keys and authenticates the requests made with
+// them. Creating and revoking keys is recorded in the audit log in the
+// same transaction.
+type APIKeyUseCase struct {
+	uow        ports.UnitOfWork
+	keys       ports.APIKeyRepository
+	audit      ports.AuditRepository
+	authorizer ports.Authorizer
+	now        func() time.Time
+	tracer     trace.Tracer
+}
+
+func NewAPIKeyUseCase(
+	uow ports.UnitOfWork,
+	keys ports.APIKeyRepository,
+	audit ports.AuditRepository,
+	authorizer ports.Authorizer,
+) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		uow:        uow,
+		keys:       keys,
+		audit:      audit,
+		authorizer: authorizer,
+		now:        time.Now,
+		tracer:     otel.Tracer("api-keys-usecase"),
+	}
+}
+
+// CreateAPIKey creates a key and the service account it acts as. Callers
+// may only grant the actions they are granted themselves in the
+// organization of the key. The returned
+// key is the only copy of its secret.
+func (u *APIKeyUseCase) CreateAPIKey(ctx context.Context, req entity.CreateAPIKeyRequest) (entity.CreatedAPIKey, error) {
+	ctx, span := u.tracer.Start(ctx, "CreateAPIKey")
+	defer span.End()
+
+	now := u.now()
+	if req.ExpiresAt.Valid && !req.ExpiresAt.Time.After(now) {
+		return entity.CreatedAPIKey{}, fault.New("expires_at must be in the future").Code(fault.BadRequest)
+	}
+
+	organizationID, err := tenant.Assign(ctx, req.OrganizationID)
+	if err != nil {
+		return entity.CreatedAPIKey{}, err
+	}
+	if err := checkOrganization(ctx, organizationID); err != nil {
+		return entity.CreatedAPIKey{}, err
+	}
+
+	actions := slices.Compact(slices.Sorted(slices.Values(req.Actions)))
+	if err := u.checkGrantable(ctx, organizationID, actions); err != nil {
+		return entity.CreatedAPIKey{}, err
+	}
+
+	raw, prefix, err := entity.NewKey()
+	if err != nil {
+		return entity.CreatedAPIKey{}, err
+	}
+
+	key := entity.APIKey{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		UserID:         uuid.New(),
+		Name:           req.Name,
+		Prefix:         prefix,
+		KeyHash:        entity.HashKey(raw),
+		Actions:        actions,
+		ExpiresAt:      req.ExpiresAt,
+		CreatedAt:      now,
+		CreatedBy:      auth.ActorID(ctx),
+	}
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.keys.WithTx(tx).Create(ctx, key); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, "api_key.created", key, map[string]any{
+			"name":       key.Name,
+			"prefix":     key.Prefix,
+			"actions":    key.Actions,
+			"expires_at": key.ExpiresAt,
+		})
+	})
+	if err != nil {
+		return entity.CreatedAPIKey{}, fault.Wrap(err).Message("failed to create API key")
+	}
+
+	return entity.CreatedAPIKey{APIKey: key, Key: raw}, nil
+}
+
+func (u *APIKeyUseCase) GetAPIKeyByID(ctx context.Context, id uuid.UUID) (entity.APIKey, error) {
+	ctx, span := u.tracer.Start(ctx, "GetAPIKeyByID")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, dafi.Where("id", dafi.Equal, id), apiKeyVisibility)
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+
+	key, err := u.keys.Find(ctx, criteria)
+	if err != nil {
+		return entity.APIKey{}, fault.Wrap(err).Message("failed to get API key by ID")
+	}
+
+	return key, nil
+}
+
+func (u *APIKeyUseCase) ListAPIKeys(ctx context.Context, criteria dafi.Criteria) (types.List[entity.APIKey], error) {
+	ctx, span := u.tracer.Start(ctx, "ListAPIKeys")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, apiKeyVisibility)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := u.keys.List(ctx, criteria)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list API keys")
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey revokes a key. Requests made with it are rejected right
+// away.
+func (u *APIKeyUseCase) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	ctx, span := u.tracer.Start(ctx, "RevokeAPIKey")
+	defer span.End()
+
+	key, err := u.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt.Valid {
+		return fault.New("API key is already revoked").Code(fault.Conflict)
+	}
+
+	key.RevokedAt = null.TimeFrom(u.now())
+	key.RevokedBy = auth.ActorID(ctx)
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.keys.WithTx(tx).Revoke(ctx, key, dafi.FilterBy("id", dafi.Equal, key.ID)...); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, "api_key.revoked", key, map[string]any{
+			"name":   key.Name,
+			"prefix": key.Prefix,
+		})
+	})
+	if err != nil {
+		return fault.Wrap(err).Message("failed to revoke API key")
+	}
+
+	u.authorizer.Invalidate(key.UserID)
+
+	return nil
+}
+
+// VerifyAPIKey returns the principal of the service account of a usable
+// key, bound to the organization of the key. The last use of the key is
+// recorded at most once every touchInterval; failing to record it does
+// not fail the request.
+func (u *APIKeyUseCase) VerifyAPIKey(ctx context.Context, raw string) (auth.Principal, error) {
+	ctx, span := u.tracer.Start(ctx, "VerifyAPIKey")
+	defer span.End()
+
+	prefix, ok := entity.ParseKey(raw)
+	if !ok {
+		return auth.Principal{}, invalidKey(nil)
+	}
+
+	key, err := u.keys.FindUsable(ctx, prefix)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return auth.Principal{}, invalidKey(err)
+	}
+	if err != nil {
+		return auth.Principal{}, fault.Wrap(err).Message("failed to verify API key")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(entity.HashKey(raw)), []byte(key.KeyHash)) != 1 {
+		return auth.Principal{}, invalidKey(nil)
+	}
+
+	now := u.now()
+	if !key.LastUsedAt.Valid || now.Sub(key.LastUsedAt.Time) >= touchInterval {
+		if err := u.keys.Touch(ctx, key.ID, now); err != nil {
+			recordError(span, err)
+		}
+	}
+
+	return auth.Principal{
+		UserID:         key.UserID,
+		OrganizationID: uuid.NullUUID{UUID: key.OrganizationID, Valid: true},
+		APIKeyID:       uuid.NullUUID{UUID: key.ID, Valid: true},
+	}, nil
+}
+
+// checkGrantable rejects the actions the caller is not granted in the
+// organization of the key, so keys never hold more than their creator
+func (u *APIKeyUseCase) checkGrantable(ctx context.Context, organizationID uuid.UUID, actions []string) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return fault.New("authentication required").Code(fault.Unauthorized)
+	}
+	principal.OrganizationID = uuid.NullUUID{UUID: organizationID, Valid: true}
+
+	permissions, err := u.authorizer.Permissions(ctx, principal)
+	if err != nil {
+		return err
+	}
+
+	for _, action := range actions {
+		if _, ok := permissions.Scope(action); !ok {
+			return fault.New("you cannot grant an action you are not granted").
+				Code(fault.Forbidden).
+				With("action", action)
+		}
+	}
+	return nil
+}
+
+// record appends a change of key made by the caller to the audit log
+func (u *APIKeyUseCase) record(ctx context.Context, tx ports.Transaction, action string, key entity.APIKey, changes map[string]any) error {
+	return u.audit.WithTx(tx).Record(ctx, auditentity.Entry{
+		ID:             uuid.New(),
+		OrganizationID: uuid.NullUUID{UUID: key.OrganizationID, Valid: true},
+		ActorID:        auth.UserIDFrom(ctx),
+		Action:         action,
+		EntityType:     auditAPIKey,
+		EntityID:       key.ID,
+		Changes:        changes,
+		CreatedAt:      u.now(),
+	})
+}
+
+// checkOrganization forbids callers with the ORG scope from acting on
+// organizations they are not members of
+func checkOrganization(ctx context.Context, organizationID uuid.UUID) error {
+	access, ok := rbac.AccessFrom(ctx)
+	if !ok || access.Scope != rbac.ScopeOrganization || slices.Contains(access.Organizations, organizationID) {
+		return nil
+	}
+	return fault.New("organization not accessible").Code(fault.Forbidden).With("organization_id", organizationID)
+}
+
+// invalidKey is the error of keys that do not authenticate, whatever the
+// reason, so callers cannot tell unknown keys from revoked ones
+func invalidKey(err error) error {
+	if err == nil {
+		return fault.New("invalid API key").Code(fault.Unauthorized)
+	}
+	return fault.Wrap(err).Code(fault.Unauthorized).Message("invalid API key")
+}
+
+// recordError marks the span as failed for errors that do not fail the
+// request, such as untracked key uses.
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
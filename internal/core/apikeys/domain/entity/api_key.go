@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+
+	authentity "api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// KeyPrefix starts every API key, so leaked keys are easy to recognize,
+// e.g. by secret scanners
+const KeyPrefix = "sk_"
+
+// prefixLength is the length of the public part of keys, KeyPrefix and 12
+// hex characters
+const prefixLength = len(KeyPrefix) + 12
+
+// ServiceAccountOrigin is the origin of the users API keys act as
+const ServiceAccountOrigin = "API_KEY"
+
+// APIKey is a key of a service account. Keys are sent as
+// "<prefix>.<secret>"; only the SHA-256 hash of the whole key is stored.
+// A key acts as its own user within a single organization and is only
+// granted its Actions.
+type APIKey struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	Name           string     `json:"name" db:"name"`
+	Prefix         string     `json:"prefix" db:"prefix"`
+	KeyHash        string     `json:"-" db:"key_hash"`
+	Actions        []string   `json:"actions"`
+	ExpiresAt      null.Time  `json:"expires_at" db:"expires_at"`
+	LastUsedAt     null.Time  `json:"last_used_at" db:"last_used_at"`
+	RevokedAt      null.Time  `json:"revoked_at" db:"revoked_at"`
+	RevokedBy      *uuid.UUID `json:"revoked_by" db:"revoked_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	CreatedBy      *uuid.UUID `json:"created_by" db:"created_by"`
+}
+
+// CreatedAPIKey is a new API key with its secret, which is only returned
+// once
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// NewKey returns a new raw key and its prefix
+func NewKey() (raw, prefix string, err error) {
+	b := make([]byte, (prefixLength-len(KeyPrefix))/2)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fault.Wrap(err).Message("failed to generate API key")
+	}
+	prefix = KeyPrefix + hex.EncodeToString(b)
+
+	secret, err := authentity.NewOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	return prefix + "." + secret, prefix, nil
+}
+
+// ParseKey returns the prefix of a raw key. It reports false when raw is
+// not shaped like a key.
+func ParseKey(raw string) (string, bool) {
+	prefix, secret, ok := strings.Cut(raw, ".")
+	if !ok || secret == "" || len(prefix) != prefixLength || !strings.HasPrefix(prefix, KeyPrefix) {
+		return "", false
+	}
+	return prefix, true
+}
+
+// HashKey returns the hash of a raw key, as stored in KeyHash
+func HashKey(raw string) string {
+	return authentity.HashToken(raw)
+}
+
+// IsUsable reports whether the key is neither revoked nor expired at now
+func (k APIKey) IsUsable(now time.Time) bool {
+	return !k.RevokedAt.Valid && (!k.ExpiresAt.Valid || now.Before(k.ExpiresAt.Time))
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+const (
+	// actionCodePattern is the pattern of "<module>.<action>" codes
+	actionCodePattern = `^[a-z][a-z0-9_-]*\.[a-z][a-z0-9_-]*$`
+	// MaxActionsPerKey bounds the actions granted to a key
+	MaxActionsPerKey = 100
+)
+
+// CreateAPIKeyRequest creates an API key granted Actions, which the
+// caller must be granted too in the organization of the key.
+// OrganizationID defaults to the organization the request acts on. Keys
+// without ExpiresAt never expire.
+type CreateAPIKeyRequest struct {
+	Name           string    `json:"name"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Actions        []string  `json:"actions"`
+	ExpiresAt      null.Time `json:"expires_at,omitempty"`
+}
+
+func (r CreateAPIKeyRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"name":            valid.String().MaxLength(100).Required(),
+		"organization_id": valid.String().UUID(),
+		"actions":         valid.Array(valid.String().Pattern(actionCodePattern)).Length(1, MaxActionsPerKey).Required(),
+		"expires_at":      valid.Time(),
+	})
+}
+
+func (r CreateAPIKeyRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// APIKeySortFields are the fields API keys can be sorted by
+var APIKeySortFields = []string{"name", "prefix", "expires_at", "last_used_at", "revoked_at", "created_at"}
+
+// APIKeyFilter holds the filters accepted by the list endpoint
+type APIKeyFilter struct {
+	Name           string     `json:"name,omitempty" query:"name"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" query:"organization_id"`
+	Revoked        *bool      `json:"revoked,omitempty" query:"revoked"`
+}
+
+// Criteria returns the filters as dafi criteria. Names match partially.
+func (f APIKeyFilter) Criteria() dafi.Criteria {
+	criteria := dafi.New()
+
+	if f.Name != "" {
+		criteria = criteria.And("name", dafi.Like, "%"+f.Name+"%")
+	}
+	if f.OrganizationID != nil {
+		criteria = criteria.And("organization_id", dafi.Equal, *f.OrganizationID)
+	}
+	if f.Revoked != nil {
+		if *f.Revoked {
+			criteria = criteria.And("revoked_at", dafi.IsNotNull, nil)
+		} else {
+			criteria = criteria.And("revoked_at", dafi.IsNull, nil)
+		}
+	}
+
+	return criteria
+}
+
+// ListAPIKeysRequest adds pagination and sorting to APIKeyFilter
+type ListAPIKeysRequest struct {
+	APIKeyFilter
+	Page      uint   `json:"page,omitempty" query:"page"`
+	PageSize  uint   `json:"page_size,omitempty" query:"page_size"`
+	SortBy    string `json:"sort_by,omitempty" query:"sort_by"`
+	SortOrder string `json:"sort_order,omitempty" query:"sort_order"`
+}
+
+func (r ListAPIKeysRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"page":       valid.Int().Min(1),
+		"page_size":  valid.Int().Range(1, MaxPageSize),
+		"sort_by":    valid.Enum(APIKeySortFields...),
+		"sort_order": valid.Enum("asc", "desc").CaseInsensitive(),
+	})
+}
+
+func (r ListAPIKeysRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// Criteria returns the filters, page and sort as dafi criteria. The first
+// page of DefaultPageSize keys is returned when no page is given.
+func (r ListAPIKeysRequest) Criteria() dafi.Criteria {
+	page, pageSize := r.Page, r.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	criteria := r.APIKeyFilter.Criteria().Page(page).Limit(pageSize)
+
+	if r.SortBy != "" {
+		if strings.EqualFold(r.SortOrder, "desc") {
+			criteria = criteria.SortBy(r.SortBy, dafi.Desc)
+		} else {
+			criteria = criteria.SortBy(r.SortBy, dafi.Asc)
+		}
+	}
+
+	return criteria
+}
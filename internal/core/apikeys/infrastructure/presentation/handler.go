@@ -0,0 +1,66 @@
+package presentation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/apikeys/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// APIKeyIDRequest binds the API key id path parameter
+type APIKeyIDRequest struct {
+	ID uuid.UUID `param:"id"`
+}
+
+// APIKeyHandler exposes the API key use cases over HTTP. Its methods are
+// adapted to echo handlers with server.Handle.
+type APIKeyHandler struct {
+	usecase ports.APIKeyUseCase
+	tracer  trace.Tracer
+}
+
+func NewAPIKeyHandler(usecase ports.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		usecase: usecase,
+		tracer:  otel.Tracer("api-keys-handler"),
+	}
+}
+
+// CreateAPIKey creates an API key and returns its secret, only once
+func (h *APIKeyHandler) CreateAPIKey(ctx context.Context, req entity.CreateAPIKeyRequest) (entity.CreatedAPIKey, error) {
+	ctx, span := h.tracer.Start(ctx, "APIKeyHandler.CreateAPIKey")
+	defer span.End()
+
+	return h.usecase.CreateAPIKey(ctx, req)
+}
+
+// GetAPIKey gets an API key by its ID
+func (h *APIKeyHandler) GetAPIKey(ctx context.Context, req APIKeyIDRequest) (entity.APIKey, error) {
+	ctx, span := h.tracer.Start(ctx, "APIKeyHandler.GetAPIKey")
+	defer span.End()
+
+	return h.usecase.GetAPIKeyByID(ctx, req.ID)
+}
+
+// ListAPIKeys lists API keys with optional filtering, sorting, and
+// pagination
+func (h *APIKeyHandler) ListAPIKeys(ctx context.Context, req entity.ListAPIKeysRequest) (types.List[entity.APIKey], error) {
+	ctx, span := h.tracer.Start(ctx, "APIKeyHandler.ListAPIKeys")
+	defer span.End()
+
+	return h.usecase.ListAPIKeys(ctx, req.Criteria())
+}
+
+// RevokeAPIKey revokes an API key
+func (h *APIKeyHandler) RevokeAPIKey(ctx context.Context, req APIKeyIDRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "APIKeyHandler.RevokeAPIKey")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.RevokeAPIKey(ctx, req.ID)
+}
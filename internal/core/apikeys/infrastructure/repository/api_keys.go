@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/apikeys/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/sqlcraft"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// uniqueViolation is the PostgreSQL error code of unique constraint
+// violations
+const uniqueViolation = "23505"
+
+const apiKeyColumns = "id, organization_id, user_id, name, prefix, key_hash, actions, expires_at, last_used_at, revoked_at, revoked_by, created_at, created_by"
+
+// apiKeys adds to auth.api_keys the codes of the actions of the keys, so
+// keys can be filtered and sorted by unqualified columns
+const apiKeys = `(
+	SELECT k.id, k.organization_id, k.user_id, k.name, k.prefix, k.key_hash,
+		ARRAY(
+			SELECT m.code || '.' || ma.code
+			FROM auth.api_key_actions ka
+			JOIN auth.module_actions ma ON ma.id = ka.module_action_id
+			JOIN auth.modules m ON m.id = ma.module_id
+			WHERE ka.api_key_id = k.id
+			ORDER BY 1
+		) AS actions,
+		k.expires_at, k.last_used_at, k.revoked_at, k.revoked_by, k.created_at, k.created_by
+	FROM auth.api_keys k
+) api_keys`
+
+// apiKeyColumnByDomainField maps the fields keys can be filtered by to
+// their columns, both in apiKeys and in auth.api_keys
+var apiKeyColumnByDomainField = map[string]string{
+	"id":              "id",
+	"organization_id": "organization_id",
+	"user_id":         "user_id",
+	"name":            "name",
+	"prefix":          "prefix",
+	"expires_at":      "expires_at",
+	"last_used_at":    "last_used_at",
+	"revoked_at":      "revoked_at",
+	"created_at":      "created_at",
+	"created_by":      "created_by",
+}
+
+type APIKeyRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewAPIKeyRepository(db ports.Database) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:     db,
+		tracer: otel.Tracer("api-keys-repository"),
+	}
+}
+
+func (r *APIKeyRepository) WithTx(tx ports.Transaction) ports.APIKeyRepository {
+	return &APIKeyRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *APIKeyRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key entity.APIKey) error {
+	ctx, span := r.tracer.Start(ctx, "APIKeyRepository.Create")
+	defer span.End()
+
+	db := r.getExecutor()
+
+	userQuery := `
+		INSERT INTO auth.users (id, origin, first_name, is_active, created_at, created_by)
+		VALUES ($1, $2, $3, true, $4, $5)
+	`
+	if _, err := db.Exec(ctx, userQuery, key.UserID, entity.ServiceAccountOrigin, key.Name, key.CreatedAt, key.CreatedBy); err != nil {
+		return fault.Wrap(err).Message("failed to create service account")
+	}
+
+	keyQuery := `
+		INSERT INTO auth.api_keys (id, organization_id, user_id, name, prefix, key_hash, expires_at, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := db.Exec(ctx, keyQuery,
+		key.ID,
+		key.OrganizationID,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		key.ExpiresAt,
+		key.CreatedAt,
+		key.CreatedBy,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return fault.Wrap(err).Code(fault.Conflict).Message("API key already exists")
+		}
+		return fault.Wrap(err).Message("failed to create API key")
+	}
+
+	actionsQuery := `
+		INSERT INTO auth.api_key_actions (api_key_id, module_action_id)
+		SELECT $1, ma.id
+		FROM auth.module_actions ma
+		JOIN auth.modules m ON m.id = ma.module_id
+		WHERE m.code || '.' || ma.code = ANY($2)
+	`
+	result, err := db.Exec(ctx, actionsQuery, key.ID, key.Actions)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to grant actions to API key")
+	}
+
+	if result.RowsAffected() != int64(len(key.Actions)) {
+		return fault.New("unknown action").Code(fault.BadRequest).With("actions", key.Actions)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) Find(ctx context.Context, criteria dafi.Criteria) (entity.APIKey, error) {
+	ctx, span := r.tracer.Start(ctx, "APIKeyRepository.Find")
+	defer span.End()
+
+	clause, err := apiKeyWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+
+	query := "SELECT " + apiKeyColumns + " FROM " + apiKeys + clause.Sql + " LIMIT 1"
+
+	key, err := scanAPIKey(r.getExecutor().QueryRow(ctx, query, clause.Args...))
+	if err != nil {
+		return entity.APIKey{}, fault.Wrap(err).Message("failed to find API key")
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context, criteria dafi.Criteria) (types.List[entity.APIKey], error) {
+	ctx, span := r.tracer.Start(ctx, "APIKeyRepository.List")
+	defer span.End()
+
+	clause, err := apiKeyWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := " ORDER BY created_at DESC"
+	if !criteria.Sorts.IsZero() {
+		orderBy = sqlcraft.BuildOrderBy(criteria.Sorts)
+	}
+
+	query := "SELECT " + apiKeyColumns + " FROM " + apiKeys + clause.Sql + orderBy + sqlcraft.BuildPagination(criteria.Pagination)
+
+	rows, err := r.getExecutor().Query(ctx, query, clause.Args...)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list API keys")
+	}
+	defer rows.Close()
+
+	var list types.List[entity.APIKey]
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fault.Wrap(err).Message("failed to scan API key")
+		}
+		list = append(list, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fault.Wrap(err).Message("failed to list API keys")
+	}
+
+	return list, nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, key entity.APIKey, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "APIKeyRepository.Revoke")
+	defer span.End()
+
+	filters = append(dafi.Filters(filters).Grouped(), dafi.Filter{Field: "revoked_at", Operator: dafi.IsNull})
+	clause, err := apiKeyWhere(ctx, 2, filters)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE auth.api_keys SET revoked_at = $1, revoked_by = $2" + clause.Sql
+	args := append([]any{key.RevokedAt, key.RevokedBy}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to revoke API key")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("API key not found")).Code(fault.NotFound).Message("API key not found")
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) RevokeUsers(ctx context.Context, at time.Time, by *uuid.UUID, userIDs ...uuid.UUID) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "APIKeyRepository.RevokeUsers")
+	defer span.End()
+
+	query := "UPDATE auth.api_keys SET revoked_at = $1, revoked_by = $2 WHERE user_id = ANY($3) AND revoked_at IS NULL"
+
+	result, err := r.getExecutor().Exec(ctx, query, at, by, userIDs)
+	if err != nil {
+		return 0, fault.Wrap(err).Message("failed to revoke API keys of users")
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *APIKeyRepository) FindUsable(ctx context.Context, prefix string) (entity.APIKey, error) {
+	ctx, span := r.tracer.Start(ctx, "APIKeyRepository.FindUsable")
+	defer span.End()
+
+	query := "SELECT " + apiKeyColumns + " FROM " + apiKeys + `
+		WHERE prefix = $1
+			AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > NOW())
+			AND organization_id IN (
+				SELECT id FROM auth.organizations WHERE is_active AND deleted_at IS NULL
+			)
+	`
+
+	key, err := scanAPIKey(r.getExecutor().QueryRow(ctx, query, prefix))
+	if err != nil {
+		return entity.APIKey{}, fault.Wrap(err).Message("failed to find API key")
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepository) Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "APIKeyRepository.Touch")
+	defer span.End()
+
+	if _, err := r.getExecutor().Exec(ctx, "UPDATE auth.api_keys SET last_used_at = $2 WHERE id = $1", id, usedAt); err != nil {
+		return fault.Wrap(err).Message("failed to record API key use")
+	}
+
+	return nil
+}
+
+// apiKeyWhere builds the WHERE clause of filters on keys, restricted to
+// the organizations of the tenant of ctx
+func apiKeyWhere(ctx context.Context, initialArgCount int, filters dafi.Filters) (sqlcraft.Result, error) {
+	filters, err := tenant.Restrict(ctx, filters, "organization_id")
+	if err != nil {
+		return sqlcraft.Result{}, err
+	}
+	return sqlcraft.WhereSafe(initialArgCount, apiKeyColumnByDomainField, slices.Clone(filters)...)
+}
+
+func scanAPIKey(row pgx.Row) (entity.APIKey, error) {
+	var key entity.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.OrganizationID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Actions,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.RevokedBy,
+		&key.CreatedAt,
+		&key.CreatedBy,
+	)
+	return key, err
+}
@@ -0,0 +1,24 @@
+package apikeys
+
+import (
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/core/apikeys/application"
+	"api.system.soluciones-cloud.com/internal/core/apikeys/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/core/apikeys/infrastructure/repository"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			repository.NewAPIKeyRepository,
+			fx.As(new(ports.APIKeyRepository)),
+		),
+		fx.Annotate(
+			application.NewAPIKeyUseCase,
+			fx.As(new(ports.APIKeyUseCase)),
+		),
+		presentation.NewAPIKeyHandler,
+	),
+)
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entry records a change made through the API to an entity, e.g. the
+// permissions granted to a role.
+type Entry struct {
+	ID             uuid.UUID     `json:"id"`
+	OrganizationID uuid.NullUUID `json:"organization_id"`
+	// ActorID is the user that made the change
+	ActorID uuid.NullUUID `json:"actor_id"`
+	// Action is "<entity>.<verb>", e.g. role.created
+	Action     string    `json:"action"`
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	// Changes holds the details of the change. It is stored as JSON.
+	Changes   map[string]any `json:"changes,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/audit/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+type AuditRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewAuditRepository(db ports.Database) *AuditRepository {
+	return &AuditRepository{
+		db:     db,
+		tracer: otel.Tracer("audit-repository"),
+	}
+}
+
+func (r *AuditRepository) WithTx(tx ports.Transaction) ports.AuditRepository {
+	return &AuditRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *AuditRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *AuditRepository) Record(ctx context.Context, entry entity.Entry) error {
+	ctx, span := r.tracer.Start(ctx, "AuditRepository.Record")
+	defer span.End()
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+
+	// Changes made on behalf of a single organization belong to it
+	if t, ok := tenant.From(ctx); ok && !entry.OrganizationID.Valid && len(t.Organizations) == 1 {
+		entry.OrganizationID = uuid.NullUUID{UUID: t.Organizations[0], Valid: true}
+	}
+
+	query := `
+		INSERT INTO auth.audit_logs (id, organization_id, actor_id, action, entity_type, entity_id, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.getExecutor().Exec(ctx, query,
+		entry.ID,
+		entry.OrganizationID,
+		entry.ActorID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		entry.Changes,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to record audit entry").With("action", entry.Action)
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package audit
+
+import (
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/core/audit/infrastructure/repository"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			repository.NewAuditRepository,
+			fx.As(new(ports.AuditRepository)),
+		),
+	),
+)
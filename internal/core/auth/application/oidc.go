@@ -0,0 +1,232 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	userentity "api.system.soluciones-cloud.com/internal/core/users/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth/oidc"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// maxNameLength is the length of the name columns of auth.users
+const maxNameLength = 100
+
+// pictureSchema accepts the pictures users may set themselves, see
+// userentity.UpdateMeRequest
+var pictureSchema = valid.String().URL().MaxLength(2048)
+
+// OIDCProviders lists the identity providers users may log in with
+func (u *AuthUseCase) OIDCProviders(ctx context.Context) (types.List[entity.OIDCProvider], error) {
+	providers := types.List[entity.OIDCProvider]{}
+	for _, name := range u.providers.Names() {
+		providers = append(providers, entity.OIDCProvider{Name: name})
+	}
+	return providers, nil
+}
+
+// StartOIDCLogin returns the authorization URL of the provider for an
+// authorization code login with PKCE. The code verifier and nonce stay on
+// the server with the state, which the client gets back from the provider
+// and posts to CompleteOIDCLogin within OIDCStateTTL.
+func (u *AuthUseCase) StartOIDCLogin(ctx context.Context, req entity.OIDCAuthorizeRequest) (entity.OIDCAuthorization, error) {
+	ctx, span := u.tracer.Start(ctx, "StartOIDCLogin")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("oidc.provider", req.Provider))
+
+	state, err := entity.NewOpaqueToken()
+	if err != nil {
+		return entity.OIDCAuthorization{}, err
+	}
+	nonce, err := entity.NewOpaqueToken()
+	if err != nil {
+		return entity.OIDCAuthorization{}, err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return entity.OIDCAuthorization{}, err
+	}
+
+	authorizationURL, err := u.providers.AuthCodeURL(ctx, req.Provider, state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return entity.OIDCAuthorization{}, err
+	}
+
+	now := u.now()
+	err = u.loginStates.Create(ctx, entity.LoginState{
+		ID:           uuid.New(),
+		Provider:     req.Provider,
+		StateHash:    entity.HashToken(state),
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    now.Add(u.config.OIDCStateTTL),
+		CreatedAt:    now,
+	})
+	if err != nil {
+		return entity.OIDCAuthorization{}, fault.Wrap(err).Message("failed to store login state")
+	}
+
+	return entity.OIDCAuthorization{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+		ExpiresIn:        int64(u.config.OIDCStateTTL.Seconds()),
+	}, nil
+}
+
+// CompleteOIDCLogin redeems the code of a login started with
+// StartOIDCLogin and logs the user of the provider account in. The first
+// login links the account to the user whose verified email credential has
+// the verified email of the provider, or creates a user whose origin is
+// the provider.
+func (u *AuthUseCase) CompleteOIDCLogin(ctx context.Context, req entity.OIDCCallbackRequest) (entity.Tokens, error) {
+	ctx, span := u.tracer.Start(ctx, "CompleteOIDCLogin")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("oidc.provider", req.Provider))
+
+	now := u.now()
+
+	state, err := u.loginStates.Consume(ctx, req.Provider, entity.HashToken(req.State), now)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Tokens{}, fault.New("invalid or expired login state").Code(fault.Unauthorized)
+	}
+	if err != nil {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to find login state")
+	}
+
+	external, err := u.providers.Exchange(ctx, req.Provider, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return entity.Tokens{}, err
+	}
+
+	var userID uuid.UUID
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		identities := u.identities.WithTx(tx)
+
+		identity, err := identities.FindBySubject(ctx, external.Provider, external.Subject)
+		if err == nil {
+			userID = identity.UserID
+			if err := identities.RecordLogin(ctx, identity.ID, now); err != nil {
+				return fault.Wrap(err).Message("failed to record login")
+			}
+			return nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fault.Wrap(err).Message("failed to find identity")
+		}
+
+		userID, err = u.linkUser(ctx, tx, external)
+		if err != nil {
+			return err
+		}
+
+		// Keep the conflict of a concurrent first login for the client
+		return identities.Create(ctx, entity.Identity{
+			ID:          uuid.New(),
+			UserID:      userID,
+			Provider:    external.Provider,
+			Subject:     external.Subject,
+			Email:       userentity.NewNullString(entity.NormalizeEmail(external.Email)),
+			CreatedAt:   now,
+			LastLoginAt: null.TimeFrom(now),
+		})
+	})
+	if err != nil {
+		return entity.Tokens{}, err
+	}
+
+	if err := u.ensureActive(ctx, userID); err != nil {
+		return entity.Tokens{}, err
+	}
+
+	span.SetAttributes(attribute.String("user.id", userID.String()))
+	return u.login(ctx, userID, req.Client)
+}
+
+// linkUser returns the user a provider account is linked to on its first
+// login. Emails are only matched when both the provider and the email
+// credential verified them, so nobody can take over an account by
+// registering its email first on either side.
+func (u *AuthUseCase) linkUser(ctx context.Context, tx ports.Transaction, external entity.ExternalIdentity) (uuid.UUID, error) {
+	users := u.users.WithTx(tx)
+
+	if external.Email != "" && external.EmailVerified {
+		credential, err := u.credentials.WithTx(tx).FindByEmail(ctx, entity.NormalizeEmail(external.Email))
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, fault.Wrap(err).Message("failed to find credential")
+		}
+
+		if err == nil && credential.IsVerified {
+			user, err := users.Find(ctx, dafi.Where("id", dafi.Equal, credential.UserID).And("deleted_at", dafi.IsNull, nil))
+			if errors.Is(err, pgx.ErrNoRows) {
+				return uuid.Nil, fault.New("account is disabled").Code(fault.Forbidden)
+			}
+			if err != nil {
+				return uuid.Nil, fault.Wrap(err).Message("failed to find user")
+			}
+
+			if picture := pictureURL(external.Picture); !user.Picture.Valid && picture != "" {
+				user.Picture = null.StringFrom(picture)
+				user.UpdatedAt = userentity.NewNullTime(u.now())
+				user.UpdatedBy = &user.ID
+				if err := users.Update(ctx, user); err != nil {
+					return uuid.Nil, fault.Wrap(err).Message("failed to update user")
+				}
+			}
+			return user.ID, nil
+		}
+	}
+
+	firstName, lastName := external.GivenName, external.FamilyName
+	if firstName == "" {
+		firstName, lastName = external.Name, ""
+	}
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(external.Email, "@")
+	}
+
+	user := userentity.User{
+		ID:        uuid.New(),
+		Origin:    strings.ToUpper(external.Provider),
+		FirstName: truncate(firstName, maxNameLength),
+		LastName:  userentity.NewNullString(truncate(lastName, maxNameLength)),
+		Picture:   userentity.NewNullString(pictureURL(external.Picture)),
+		IsActive:  true,
+		CreatedAt: u.now(),
+	}
+	if err := users.Create(ctx, user); err != nil {
+		return uuid.Nil, fault.Wrap(err).Message("failed to create user")
+	}
+
+	return user.ID, nil
+}
+
+// pictureURL returns the picture of a provider account, or "" when it is
+// not an http or https URL clients can render
+func pictureURL(picture string) string {
+	if !pictureSchema.Parse(picture).Success {
+		return ""
+	}
+	return picture
+}
+
+// truncate cuts s to at most n characters
+func truncate(s string, n int) string {
+	runes := []rune(strings.TrimSpace(s))
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return string(runes)
+}
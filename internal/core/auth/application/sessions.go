@@ -0,0 +1,249 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/auth/session"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// userVisibility restricts OWN scopes to the users created by the caller
+// and ORG scopes to the members of the caller's organizations, like the
+// users routes
+var userVisibility = rbac.Visibility{Owner: "created_by", Organization: "organization_id"}
+
+// sessionCheckInterval throttles the database checks of sessions, which
+// record their activity and catch revocations made by other instances
+const sessionCheckInterval = time.Minute
+
+// SessionUseCase lists and revokes the login sessions of users, and
+// rejects the requests of revoked sessions. Revoked sessions are denied
+// in memory until their access tokens expire.
+type SessionUseCase struct {
+	uow           ports.UnitOfWork
+	sessions      ports.SessionRepository
+	refreshTokens ports.RefreshTokenRepository
+	users         ports.UserRepository
+	denylist      ports.SessionDenylist
+	issuer        ports.AccessTokenIssuer
+	config        Config
+	throttle      *session.Throttle
+	now           func() time.Time
+	tracer        trace.Tracer
+}
+
+func NewSessionUseCase(
+	uow ports.UnitOfWork,
+	sessions ports.SessionRepository,
+	refreshTokens ports.RefreshTokenRepository,
+	users ports.UserRepository,
+	denylist ports.SessionDenylist,
+	issuer ports.AccessTokenIssuer,
+	config Config,
+) *SessionUseCase {
+	return &SessionUseCase{
+		uow:           uow,
+		sessions:      sessions,
+		refreshTokens: refreshTokens,
+		users:         users,
+		denylist:      denylist,
+		issuer:        issuer,
+		config:        config,
+		throttle:      session.NewThrottle(sessionCheckInterval),
+		now:           time.Now,
+		tracer:        otel.Tracer("sessions-usecase"),
+	}
+}
+
+func errSessionRevoked() error {
+	return fault.New("session has been revoked").Code(fault.Unauthorized)
+}
+
+// MySessions lists the active sessions of the caller, flagging the one of
+// the request
+func (u *SessionUseCase) MySessions(ctx context.Context) (types.List[entity.Session], error) {
+	ctx, span := u.tracer.Start(ctx, "MySessions")
+	defer span.End()
+
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return nil, fault.New("authentication required").Code(fault.Unauthorized)
+	}
+
+	sessions, err := u.sessions.ListActive(ctx, principal.UserID, u.now())
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list sessions")
+	}
+
+	for i := range sessions {
+		sessions[i].Current = principal.SessionID.Valid && sessions[i].ID == principal.SessionID.UUID
+	}
+	return sessions, nil
+}
+
+// RevokeMySession ends a session of the caller, e.g. of a lost device.
+// Sessions of other users are not found.
+func (u *SessionUseCase) RevokeMySession(ctx context.Context, id uuid.UUID) error {
+	ctx, span := u.tracer.Start(ctx, "RevokeMySession")
+	defer span.End()
+
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return fault.New("authentication required").Code(fault.Unauthorized)
+	}
+
+	now := u.now()
+	err := ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.sessions.WithTx(tx).Revoke(ctx, principal.UserID, id, now, &principal.UserID); err != nil {
+			return fault.Wrap(err).Message("failed to revoke session")
+		}
+		if err := u.refreshTokens.WithTx(tx).RevokeFamily(ctx, id, now); err != nil {
+			return fault.Wrap(err).Message("failed to revoke refresh tokens")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	u.denylist.Add(now.Add(denyTTL(u.issuer, u.config)), id)
+	return nil
+}
+
+// UserSessions lists the active sessions of a user the caller may see
+func (u *SessionUseCase) UserSessions(ctx context.Context, userID uuid.UUID) (types.List[entity.Session], error) {
+	ctx, span := u.tracer.Start(ctx, "UserSessions")
+	defer span.End()
+
+	if err := u.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := u.sessions.ListActive(ctx, userID, u.now())
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list sessions")
+	}
+	return sessions, nil
+}
+
+// RevokeUserSessions logs a user the caller may see out of every device.
+// Their access tokens are rejected right away and their refresh tokens
+// revoked.
+func (u *SessionUseCase) RevokeUserSessions(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := u.tracer.Start(ctx, "RevokeUserSessions")
+	defer span.End()
+
+	if err := u.checkUser(ctx, userID); err != nil {
+		return err
+	}
+
+	now := u.now()
+	var ended []uuid.UUID
+	err := ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		var err error
+		ended, err = u.sessions.WithTx(tx).RevokeUser(ctx, userID, now, auth.ActorID(ctx))
+		if err != nil {
+			return fault.Wrap(err).Message("failed to revoke sessions")
+		}
+		if err := u.refreshTokens.WithTx(tx).RevokeUser(ctx, userID, now); err != nil {
+			return fault.Wrap(err).Message("failed to revoke refresh tokens")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	span.SetAttributes(attribute.Int("sessions.revoked", len(ended)))
+	if len(ended) > 0 {
+		u.denylist.Add(now.Add(denyTTL(u.issuer, u.config)), ended...)
+	}
+	return nil
+}
+
+// CheckSession rejects the requests of revoked sessions. Sessions revoked
+// by this instance are denied in memory; every sessionCheckInterval the
+// session is also checked in the database, which records its activity and
+// the organization it acts on. Failing to check it does not fail the
+// request.
+func (u *SessionUseCase) CheckSession(ctx context.Context, principal auth.Principal) error {
+	if !principal.SessionID.Valid {
+		return nil
+	}
+
+	id := principal.SessionID.UUID
+	if u.denylist.Contains(id) {
+		return errSessionRevoked()
+	}
+	if !u.throttle.Due(id) {
+		return nil
+	}
+
+	ctx, span := u.tracer.Start(ctx, "CheckSession")
+	defer span.End()
+
+	now := u.now()
+	err := u.sessions.Touch(ctx, id, now, principal.OrganizationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		u.denylist.Add(now.Add(denyTTL(u.issuer, u.config)), id)
+		return errSessionRevoked()
+	}
+	if err != nil {
+		recordError(span, err)
+	}
+	return nil
+}
+
+// RestoreDenylist denies the sessions revoked recently enough for their
+// access tokens to be valid, e.g. before a restarted instance serves
+// requests.
+func (u *SessionUseCase) RestoreDenylist(ctx context.Context) error {
+	ctx, span := u.tracer.Start(ctx, "RestoreDenylist")
+	defer span.End()
+
+	now := u.now()
+	ids, err := u.sessions.RevokedSince(ctx, now.Add(-denyTTL(u.issuer, u.config)))
+	if err != nil {
+		return err
+	}
+
+	span.SetAttributes(attribute.Int("sessions.denied", len(ids)))
+	if len(ids) > 0 {
+		u.denylist.Add(now.Add(denyTTL(u.issuer, u.config)), ids...)
+	}
+	return nil
+}
+
+// checkUser returns a fault.NotFound error when the user does not exist
+// or is not visible to the caller
+func (u *SessionUseCase) checkUser(ctx context.Context, userID uuid.UUID) error {
+	criteria, err := rbac.RestrictCriteria(ctx, dafi.Where("id", dafi.Equal, userID).And("deleted_at", dafi.IsNull, nil), userVisibility)
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.users.Find(ctx, criteria); err != nil {
+		return fault.Wrap(err).Message("failed to find user")
+	}
+	return nil
+}
+
+// denyTTL is how long revoked sessions are denied: until the last access
+// token issued for them expires
+func denyTTL(issuer ports.AccessTokenIssuer, config Config) time.Duration {
+	return issuer.TTL() + config.ClockSkew
+}
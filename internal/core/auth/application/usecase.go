@@ -0,0 +1,696 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	userentity "api.system.soluciones-cloud.com/internal/core/users/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+// Config holds the login policy and the links of the emails sent.
+type Config struct {
+	RefreshTokenTTL time.Duration
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+	// VerifyEmailURL and ResetPasswordURL are the client pages the emails
+	// link to, with the token in the token query parameter
+	VerifyEmailURL        string
+	ResetPasswordURL      string
+	VerificationTokenTTL  time.Duration
+	PasswordResetTokenTTL time.Duration
+	// OIDCStateTTL bounds how long users may take to log in with their
+	// identity provider
+	OIDCStateTTL time.Duration
+	// ClockSkew is the leeway of access token expiry. Revoked sessions are
+	// denied for the access token TTL plus ClockSkew.
+	ClockSkew time.Duration
+}
+
+type AuthUseCase struct {
+	uow           ports.UnitOfWork
+	users         ports.UserRepository
+	credentials   ports.CredentialRepository
+	refreshTokens ports.RefreshTokenRepository
+	sessions      ports.SessionRepository
+	denylist      ports.SessionDenylist
+	identities    ports.IdentityRepository
+	loginStates   ports.LoginStateRepository
+	providers     ports.IdentityProviders
+	hasher        ports.PasswordHasher
+	issuer        ports.AccessTokenIssuer
+	mailer        ports.Mailer
+	templates     ports.EmailTemplates
+	authorizer    ports.Authorizer
+	config        Config
+	now           func() time.Time
+	tracer        trace.Tracer
+}
+
+func NewAuthUseCase(
+	uow ports.UnitOfWork,
+	users ports.UserRepository,
+	credentials ports.CredentialRepository,
+	refreshTokens ports.RefreshTokenRepository,
+	sessions ports.SessionRepository,
+	denylist ports.SessionDenylist,
+	identities ports.IdentityRepository,
+	loginStates ports.LoginStateRepository,
+	providers ports.IdentityProviders,
+	hasher ports.PasswordHasher,
+	issuer ports.AccessTokenIssuer,
+	mailer ports.Mailer,
+	templates ports.EmailTemplates,
+	authorizer ports.Authorizer,
+	config Config,
+) *AuthUseCase {
+	return &AuthUseCase{
+		uow:           uow,
+		users:         users,
+		credentials:   credentials,
+		refreshTokens: refreshTokens,
+		sessions:      sessions,
+		denylist:      denylist,
+		identities:    identities,
+		loginStates:   loginStates,
+		providers:     providers,
+		hasher:        hasher,
+		issuer:        issuer,
+		mailer:        mailer,
+		templates:     templates,
+		authorizer:    authorizer,
+		config:        config,
+		now:           time.Now,
+		tracer:        otel.Tracer("auth-usecase"),
+	}
+}
+
+// errInvalidCredentials is returned for unknown emails and wrong passwords
+// alike, so responses do not reveal which accounts exist.
+func errInvalidCredentials() error {
+	return fault.New("invalid email or password").Code(fault.Unauthorized)
+}
+
+func errInvalidRefreshToken() error {
+	return fault.New("invalid refresh token").Code(fault.Unauthorized)
+}
+
+// Register creates a user with an email credential and logs it in. A
+// verification email is sent to the address; failing to send it does not
+// fail the registration, since it can be sent again.
+func (u *AuthUseCase) Register(ctx context.Context, req entity.RegisterRequest) (entity.Tokens, error) {
+	ctx, span := u.tracer.Start(ctx, "Register")
+	defer span.End()
+
+	hash, err := u.hasher.Hash(req.Password)
+	if err != nil {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to hash password")
+	}
+
+	verificationToken, err := entity.NewOpaqueToken()
+	if err != nil {
+		return entity.Tokens{}, err
+	}
+
+	now := u.now()
+	user := userentity.User{
+		ID:        uuid.New(),
+		Origin:    entity.OriginEmail,
+		FirstName: req.FirstName,
+		LastName:  userentity.NewNullString(req.LastName),
+		IsActive:  true,
+		CreatedAt: now,
+	}
+	credential := entity.EmailCredential{
+		ID:           uuid.New(),
+		UserID:       user.ID,
+		Email:        entity.NormalizeEmail(req.Email),
+		PasswordHash: null.StringFrom(hash),
+		CreatedAt:    now,
+		CreatedBy:    &user.ID,
+
+		VerificationToken:     null.StringFrom(entity.HashToken(verificationToken)),
+		VerificationExpiresAt: null.TimeFrom(now.Add(u.config.VerificationTokenTTL)),
+	}
+
+	var tokens entity.Tokens
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.users.WithTx(tx).Create(ctx, user); err != nil {
+			return fault.Wrap(err).Message("failed to create user")
+		}
+		// Keep the conflict message of a duplicate email for the client
+		if err := u.credentials.WithTx(tx).Create(ctx, credential); err != nil {
+			return fault.Wrap(err)
+		}
+
+		tokens, err = u.issue(ctx, tx, user.ID, req.Client)
+		return err
+	})
+	if err != nil {
+		return entity.Tokens{}, err
+	}
+
+	span.SetAttributes(attribute.String("user.id", user.ID.String()))
+
+	if err := u.sendVerificationEmail(ctx, credential.Email, user.FirstName, verificationToken); err != nil {
+		recordError(span, err)
+	}
+
+	return tokens, nil
+}
+
+// Login checks the email and password. The password is always verified,
+// even for unknown emails, so response times do not reveal which accounts
+// exist. MaxFailedLogins consecutive failures lock the account for
+// LockoutDuration.
+func (u *AuthUseCase) Login(ctx context.Context, req entity.LoginRequest) (entity.Tokens, error) {
+	ctx, span := u.tracer.Start(ctx, "Login")
+	defer span.End()
+
+	now := u.now()
+
+	credential, err := u.credentials.FindByEmail(ctx, entity.NormalizeEmail(req.Email))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to find credential")
+	}
+	found := err == nil
+
+	if found && credential.IsLocked(now) {
+		return entity.Tokens{}, fault.New("account is temporarily locked after too many failed logins").
+			Code(fault.TooManyRequests).
+			With("locked_until", credential.LockedUntil.Time)
+	}
+
+	ok, needsRehash, err := u.hasher.Verify(req.Password, credential.PasswordHash.String)
+	if err != nil {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to verify password")
+	}
+	if !found {
+		return entity.Tokens{}, errInvalidCredentials()
+	}
+	if !ok {
+		lockUntil := now.Add(u.config.LockoutDuration)
+		if err := u.credentials.RecordFailedLogin(ctx, credential.ID, u.config.MaxFailedLogins, lockUntil); err != nil {
+			return entity.Tokens{}, fault.Wrap(err).Message("failed to record failed login")
+		}
+		return entity.Tokens{}, errInvalidCredentials()
+	}
+
+	if err := u.ensureActive(ctx, credential.UserID); err != nil {
+		return entity.Tokens{}, err
+	}
+
+	if err := u.credentials.RecordLogin(ctx, credential.ID, now); err != nil {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to record login")
+	}
+
+	if needsRehash {
+		if hash, err := u.hasher.Hash(req.Password); err == nil {
+			if err := u.credentials.UpdatePasswordHash(ctx, credential.ID, hash, now); err != nil {
+				span.RecordError(err)
+			}
+		}
+	}
+
+	span.SetAttributes(attribute.String("user.id", credential.UserID.String()))
+	return u.login(ctx, credential.UserID, req.Client)
+}
+
+// Refresh rotates the refresh token: the presented token is revoked and a
+// new one of the same family is returned with a new access token. A token
+// that was already rotated means it leaked, so the whole family and its
+// session are revoked and the client has to log in again.
+func (u *AuthUseCase) Refresh(ctx context.Context, req entity.RefreshRequest) (entity.Tokens, error) {
+	ctx, span := u.tracer.Start(ctx, "Refresh")
+	defer span.End()
+
+	now := u.now()
+
+	var tokens entity.Tokens
+	// reused is the family of a reused token
+	var reused uuid.NullUUID
+	err := ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		refreshTokens := u.refreshTokens.WithTx(tx)
+
+		current, err := refreshTokens.FindByHash(ctx, entity.HashToken(req.RefreshToken))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errInvalidRefreshToken()
+		}
+		if err != nil {
+			return fault.Wrap(err).Message("failed to find refresh token")
+		}
+
+		if current.RevokedAt.Valid {
+			if current.ReplacedBy != nil {
+				reused = uuid.NullUUID{UUID: current.FamilyID, Valid: true}
+				span.AddEvent("refresh token reuse detected", trace.WithAttributes(
+					attribute.String("user.id", current.UserID.String()),
+					attribute.String("refresh_token.family_id", current.FamilyID.String()),
+				))
+				// The revocation is committed although the request fails
+				return u.endSession(ctx, tx, current, now)
+			}
+			return errInvalidRefreshToken()
+		}
+		if current.IsExpired(now) {
+			return errInvalidRefreshToken()
+		}
+
+		if err := u.ensureActive(ctx, current.UserID); err != nil {
+			return err
+		}
+
+		tokens, err = u.issueRotation(ctx, tx, current, req.Client)
+		return err
+	})
+	if err != nil {
+		return entity.Tokens{}, err
+	}
+	if reused.Valid {
+		u.deny(now, reused.UUID)
+		return entity.Tokens{}, fault.New("refresh token was already used").Code(fault.Unauthorized)
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes the family of the refresh token, ending the session on
+// every token rotated from the same login. The access tokens of the
+// session are rejected from then on. Unknown tokens are ignored.
+func (u *AuthUseCase) Logout(ctx context.Context, req entity.RefreshRequest) error {
+	ctx, span := u.tracer.Start(ctx, "Logout")
+	defer span.End()
+
+	now := u.now()
+
+	var ended uuid.NullUUID
+	err := ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		current, err := u.refreshTokens.WithTx(tx).FindByHash(ctx, entity.HashToken(req.RefreshToken))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fault.Wrap(err).Message("failed to find refresh token")
+		}
+
+		if err := u.endSession(ctx, tx, current, now); err != nil {
+			return fault.Wrap(err).Message("failed to log out")
+		}
+		ended = uuid.NullUUID{UUID: current.FamilyID, Valid: true}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if ended.Valid {
+		u.deny(now, ended.UUID)
+	}
+	return nil
+}
+
+// VerifyEmail consumes a verification token and marks the email of its
+// credential as verified.
+func (u *AuthUseCase) VerifyEmail(ctx context.Context, req entity.VerifyEmailRequest) error {
+	ctx, span := u.tracer.Start(ctx, "VerifyEmail")
+	defer span.End()
+
+	credential, err := u.credentials.VerifyEmail(ctx, entity.HashToken(req.Token), u.now())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fault.New("invalid or expired verification token").Code(fault.BadRequest)
+	}
+	if err != nil {
+		return fault.Wrap(err).Message("failed to verify email")
+	}
+
+	span.SetAttributes(attribute.String("user.id", credential.UserID.String()))
+	return nil
+}
+
+// ResendVerification sends a new verification email, invalidating the
+// previous token. Unknown and already verified emails are ignored, and
+// delivery failures are only traced, so responses do not reveal which
+// accounts exist.
+func (u *AuthUseCase) ResendVerification(ctx context.Context, req entity.EmailRequest) error {
+	ctx, span := u.tracer.Start(ctx, "ResendVerification")
+	defer span.End()
+
+	credential, user, err := u.findAccount(ctx, req.Email)
+	if err != nil || credential.ID == uuid.Nil || credential.IsVerified {
+		return err
+	}
+
+	token, err := entity.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := u.now().Add(u.config.VerificationTokenTTL)
+	if err := u.credentials.SetVerificationToken(ctx, credential.ID, entity.HashToken(token), expiresAt); err != nil {
+		return fault.Wrap(err).Message("failed to store verification token")
+	}
+
+	if err := u.sendVerificationEmail(ctx, credential.Email, user.FirstName, token); err != nil {
+		recordError(span, err)
+	}
+	return nil
+}
+
+// ForgotPassword emails a single-use password reset link, invalidating the
+// previous one. Like ResendVerification it succeeds for unknown emails.
+func (u *AuthUseCase) ForgotPassword(ctx context.Context, req entity.EmailRequest) error {
+	ctx, span := u.tracer.Start(ctx, "ForgotPassword")
+	defer span.End()
+
+	credential, user, err := u.findAccount(ctx, req.Email)
+	if err != nil || credential.ID == uuid.Nil {
+		return err
+	}
+
+	token, err := entity.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := u.now().Add(u.config.PasswordResetTokenTTL)
+	if err := u.credentials.SetPasswordResetToken(ctx, credential.ID, entity.HashToken(token), expiresAt); err != nil {
+		return fault.Wrap(err).Message("failed to store password reset token")
+	}
+
+	err = u.sendEmail(ctx, credential.Email, "reset_password", map[string]any{
+		"Name":    user.FirstName,
+		"URL":     withToken(u.config.ResetPasswordURL, token),
+		"Minutes": int(u.config.PasswordResetTokenTTL.Minutes()),
+	})
+	if err != nil {
+		recordError(span, err)
+	}
+	return nil
+}
+
+// ResetPassword consumes a password reset token and sets the new password.
+// The account is unlocked and every session and refresh token of the user
+// is revoked, so sessions opened with the old password end.
+func (u *AuthUseCase) ResetPassword(ctx context.Context, req entity.ResetPasswordRequest) error {
+	ctx, span := u.tracer.Start(ctx, "ResetPassword")
+	defer span.End()
+
+	hash, err := u.hasher.Hash(req.Password)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to hash password")
+	}
+
+	now := u.now()
+	var ended []uuid.UUID
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		credential, err := u.credentials.WithTx(tx).ResetPassword(ctx, entity.HashToken(req.Token), hash, now)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fault.New("invalid or expired password reset token").Code(fault.BadRequest)
+		}
+		if err != nil {
+			return fault.Wrap(err).Message("failed to reset password")
+		}
+
+		span.SetAttributes(attribute.String("user.id", credential.UserID.String()))
+		if err := u.refreshTokens.WithTx(tx).RevokeUser(ctx, credential.UserID, now); err != nil {
+			return fault.Wrap(err).Message("failed to revoke refresh tokens")
+		}
+		ended, err = u.sessions.WithTx(tx).RevokeUser(ctx, credential.UserID, now, nil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	u.deny(now, ended...)
+	return nil
+}
+
+// ChangePassword sets a new password for the caller after checking the
+// current one. Wrong current passwords count as failed logins, so they
+// lock the account like wrong logins do. Every other session of the user
+// is revoked, so sessions opened with the old password end; the session
+// of the request is kept.
+func (u *AuthUseCase) ChangePassword(ctx context.Context, req entity.ChangePasswordRequest) error {
+	ctx, span := u.tracer.Start(ctx, "ChangePassword")
+	defer span.End()
+
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return fault.New("authentication required").Code(fault.Unauthorized)
+	}
+
+	now := u.now()
+	credential, err := u.credentials.FindByUser(ctx, principal.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fault.New("account has no password").Code(fault.BadRequest)
+	}
+	if err != nil {
+		return fault.Wrap(err).Message("failed to find credential")
+	}
+	if credential.IsLocked(now) {
+		return fault.New("account is temporarily locked after too many failed logins").
+			Code(fault.TooManyRequests).
+			With("locked_until", credential.LockedUntil.Time)
+	}
+
+	ok, _, err = u.hasher.Verify(req.CurrentPassword, credential.PasswordHash.String)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to verify password")
+	}
+	if !ok {
+		lockUntil := now.Add(u.config.LockoutDuration)
+		if err := u.credentials.RecordFailedLogin(ctx, credential.ID, u.config.MaxFailedLogins, lockUntil); err != nil {
+			return fault.Wrap(err).Message("failed to record failed login")
+		}
+		return fault.New("current password is incorrect").Code(fault.BadRequest)
+	}
+
+	hash, err := u.hasher.Hash(req.Password)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to hash password")
+	}
+
+	var ended []uuid.UUID
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.credentials.WithTx(tx).UpdatePasswordHash(ctx, credential.ID, hash, now); err != nil {
+			return err
+		}
+
+		sessions := u.sessions.WithTx(tx)
+		if !principal.SessionID.Valid {
+			if err := u.refreshTokens.WithTx(tx).RevokeUser(ctx, principal.UserID, now); err != nil {
+				return fault.Wrap(err).Message("failed to revoke refresh tokens")
+			}
+			ended, err = sessions.RevokeUser(ctx, principal.UserID, now, &principal.UserID)
+			return err
+		}
+
+		ended, err = sessions.RevokeOthers(ctx, principal.UserID, principal.SessionID.UUID, now, &principal.UserID)
+		if err != nil {
+			return err
+		}
+		for _, id := range ended {
+			if err := u.refreshTokens.WithTx(tx).RevokeFamily(ctx, id, now); err != nil {
+				return fault.Wrap(err).Message("failed to revoke refresh tokens")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	span.SetAttributes(attribute.Int("sessions.revoked", len(ended)))
+	u.deny(now, ended...)
+	return nil
+}
+
+// findAccount returns the credential and user of email, or zero values
+// when no credential has it.
+func (u *AuthUseCase) findAccount(ctx context.Context, email string) (entity.EmailCredential, userentity.User, error) {
+	credential, err := u.credentials.FindByEmail(ctx, entity.NormalizeEmail(email))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.EmailCredential{}, userentity.User{}, nil
+	}
+	if err != nil {
+		return entity.EmailCredential{}, userentity.User{}, fault.Wrap(err).Message("failed to find credential")
+	}
+
+	user, err := u.users.Find(ctx, dafi.Where("id", dafi.Equal, credential.UserID).And("deleted_at", dafi.IsNull, nil))
+	if err != nil {
+		return entity.EmailCredential{}, userentity.User{}, fault.Wrap(err).Message("failed to find user")
+	}
+	return credential, user, nil
+}
+
+func (u *AuthUseCase) sendVerificationEmail(ctx context.Context, to, name, token string) error {
+	return u.sendEmail(ctx, to, "verify_email", map[string]any{
+		"Name":  name,
+		"URL":   withToken(u.config.VerifyEmailURL, token),
+		"Hours": int(u.config.VerificationTokenTTL.Hours()),
+	})
+}
+
+// sendEmail renders template in the locale of the request and sends it.
+func (u *AuthUseCase) sendEmail(ctx context.Context, to, template string, data map[string]any) error {
+	email, err := u.templates.Render(i18n.FromContext(ctx), template, data)
+	if err != nil {
+		return err
+	}
+	email.To = []string{to}
+
+	if err := u.mailer.Send(ctx, email); err != nil {
+		return fault.Wrap(err).Message("failed to send email").With("template", template)
+	}
+	return nil
+}
+
+// withToken adds token to the query of link.
+func withToken(link, token string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return link + "?token=" + url.QueryEscape(token)
+	}
+
+	query := parsed.Query()
+	query.Set("token", token)
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// recordError marks the span as failed for errors that do not fail the
+// request, such as undelivered emails.
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func (u *AuthUseCase) ensureActive(ctx context.Context, userID uuid.UUID) error {
+	user, err := u.users.Find(ctx, dafi.Where("id", dafi.Equal, userID).And("deleted_at", dafi.IsNull, nil))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errInvalidCredentials()
+	}
+	if err != nil {
+		return fault.Wrap(err).Message("failed to find user")
+	}
+	if !user.IsActive {
+		return fault.New("account is disabled").Code(fault.Forbidden)
+	}
+	return nil
+}
+
+// login opens a session of the user
+func (u *AuthUseCase) login(ctx context.Context, userID uuid.UUID, client entity.ClientInfo) (entity.Tokens, error) {
+	var tokens entity.Tokens
+	err := ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		var err error
+		tokens, err = u.issue(ctx, tx, userID, client)
+		return err
+	})
+	return tokens, err
+}
+
+// issue opens a session of the user with the first refresh token of its
+// family
+func (u *AuthUseCase) issue(ctx context.Context, tx ports.Transaction, userID uuid.UUID, client entity.ClientInfo) (entity.Tokens, error) {
+	now := u.now()
+	session := entity.NewSession(userID, client, now, u.config.RefreshTokenTTL)
+	if err := u.sessions.WithTx(tx).Create(ctx, session); err != nil {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to store session")
+	}
+
+	refreshToken, raw, err := entity.NewRefreshToken(userID, session.ID, client, now, u.config.RefreshTokenTTL)
+	if err != nil {
+		return entity.Tokens{}, err
+	}
+	if err := u.refreshTokens.WithTx(tx).Create(ctx, refreshToken); err != nil {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to store refresh token")
+	}
+
+	return u.tokens(ctx, userID, session.ID, raw)
+}
+
+// issueRotation replaces the current refresh token with the next one of
+// its family, which renews the session
+func (u *AuthUseCase) issueRotation(ctx context.Context, tx ports.Transaction, current entity.RefreshToken, client entity.ClientInfo) (entity.Tokens, error) {
+	now := u.now()
+	next, raw, err := entity.NewRefreshToken(current.UserID, current.FamilyID, client, now, u.config.RefreshTokenTTL)
+	if err != nil {
+		return entity.Tokens{}, err
+	}
+
+	refreshTokens := u.refreshTokens.WithTx(tx)
+	if err := refreshTokens.Create(ctx, next); err != nil {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to store refresh token")
+	}
+	if err := refreshTokens.Rotate(ctx, current.ID, next.ID, now); err != nil {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to rotate refresh token")
+	}
+	if err := u.sessions.WithTx(tx).Renew(ctx, current.FamilyID, client, now, next.ExpiresAt); err != nil {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to renew session")
+	}
+
+	return u.tokens(ctx, current.UserID, current.FamilyID, raw)
+}
+
+// endSession revokes the session of the refresh token and its family. The
+// session is denied once the transaction commits, see deny.
+func (u *AuthUseCase) endSession(ctx context.Context, tx ports.Transaction, token entity.RefreshToken, at time.Time) error {
+	if err := u.refreshTokens.WithTx(tx).RevokeFamily(ctx, token.FamilyID, at); err != nil {
+		return err
+	}
+
+	// The session may have been revoked already, e.g. by an admin
+	err := u.sessions.WithTx(tx).Revoke(ctx, token.UserID, token.FamilyID, at, nil)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+// deny rejects the access tokens of the sessions revoked at at, until
+// they expire
+func (u *AuthUseCase) deny(at time.Time, ids ...uuid.UUID) {
+	if len(ids) > 0 {
+		u.denylist.Add(at.Add(denyTTL(u.issuer, u.config)), ids...)
+	}
+}
+
+// tokens issues an access token of the session carrying the role codes
+// of the user, for clients to adapt their UI. Routes are authorized with
+// the permissions resolved on each request, not with these roles.
+func (u *AuthUseCase) tokens(ctx context.Context, userID, sessionID uuid.UUID, refreshToken string) (entity.Tokens, error) {
+	principal := auth.Principal{UserID: userID, SessionID: uuid.NullUUID{UUID: sessionID, Valid: true}}
+	permissions, err := u.authorizer.Permissions(ctx, principal)
+	if err != nil {
+		return entity.Tokens{}, err
+	}
+	principal.Roles = permissions.Roles
+
+	accessToken, err := u.issuer.Issue(principal)
+	if err != nil {
+		return entity.Tokens{}, fault.Wrap(err).Message("failed to issue access token")
+	}
+
+	return entity.Tokens{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(u.issuer.TTL().Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int64(u.config.RefreshTokenTTL.Seconds()),
+	}, nil
+}
@@ -0,0 +1,196 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+type RegisterRequest struct {
+	Email     string     `json:"email"`
+	Password  string     `json:"password"`
+	FirstName string     `json:"first_name"`
+	LastName  string     `json:"last_name,omitempty"`
+	Client    ClientInfo `json:"-"`
+}
+
+func (r RegisterRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"email":      valid.String().Email().MaxLength(255).Required(),
+		"password":   valid.String().Length(8, 128).Required(),
+		"first_name": valid.String().MaxLength(100).Required(),
+		"last_name":  valid.String().MaxLength(100),
+	})
+}
+
+func (r RegisterRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+type LoginRequest struct {
+	Email    string     `json:"email"`
+	Password string     `json:"password"`
+	Client   ClientInfo `json:"-"`
+}
+
+func (r LoginRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"email":    valid.String().MaxLength(255).Required(),
+		"password": valid.String().MaxLength(128).Required(),
+	})
+}
+
+func (r LoginRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// RefreshRequest exchanges a refresh token for a new token pair. It is
+// also the body of logout, which revokes the token.
+type RefreshRequest struct {
+	RefreshToken string     `json:"refresh_token"`
+	Client       ClientInfo `json:"-"`
+}
+
+func (r RefreshRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"refresh_token": valid.String().MaxLength(128).Required(),
+	})
+}
+
+func (r RefreshRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// EmailRequest names the account of a forgot password or resend
+// verification request.
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+func (r EmailRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"email": valid.String().MaxLength(255).Required(),
+	})
+}
+
+func (r EmailRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+func (r VerifyEmailRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"token": valid.String().MaxLength(128).Required(),
+	})
+}
+
+func (r VerifyEmailRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (r ResetPasswordRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"token":    valid.String().MaxLength(128).Required(),
+		"password": valid.String().Length(8, 128).Required(),
+	})
+}
+
+func (r ResetPasswordRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// ChangePasswordRequest changes the password of the caller, who must know
+// the current one
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	Password        string `json:"password"`
+}
+
+func (r ChangePasswordRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"current_password": valid.String().MaxLength(128).Required(),
+		"password":         valid.String().Length(8, 128).Required(),
+	})
+}
+
+func (r ChangePasswordRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// OIDCAuthorizeRequest starts a login with the identity provider of the
+// path
+type OIDCAuthorizeRequest struct {
+	Provider string `json:"-" param:"provider"`
+}
+
+// OIDCCallbackRequest completes a login with the code and state the
+// identity provider redirected the user back with
+type OIDCCallbackRequest struct {
+	Provider string     `json:"-" param:"provider"`
+	Code     string     `json:"code"`
+	State    string     `json:"state"`
+	Client   ClientInfo `json:"-"`
+}
+
+func (r OIDCCallbackRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"code":  valid.String().MaxLength(2048).Required(),
+		"state": valid.String().MaxLength(128).Required(),
+	})
+}
+
+func (r OIDCCallbackRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// SessionIDRequest binds the session id path parameter of the caller's
+// sessions
+type SessionIDRequest struct {
+	ID uuid.UUID `param:"id"`
+}
+
+// UserSessionsRequest binds the user id path parameter of the admin
+// session routes
+type UserSessionsRequest struct {
+	UserID uuid.UUID `param:"id"`
+}
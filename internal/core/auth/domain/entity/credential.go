@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+// OriginEmail is the auth.users origin of users registered with email and
+// password.
+const OriginEmail = "EMAIL"
+
+// EmailCredential is the email and password login of a user.
+type EmailCredential struct {
+	ID                  uuid.UUID   `json:"id" db:"id"`
+	UserID              uuid.UUID   `json:"user_id" db:"user_id"`
+	Email               string      `json:"email" db:"email"`
+	PasswordHash        null.String `json:"-" db:"password_hash"`
+	IsVerified          bool        `json:"is_verified" db:"is_verified"`
+	FailedLoginAttempts int         `json:"-" db:"failed_login_attempts"`
+	LockedUntil         null.Time   `json:"-" db:"locked_until"`
+	LastLoginAt         null.Time   `json:"last_login_at" db:"last_login_at"`
+	// VerificationToken and PasswordResetToken hold the HashToken of the
+	// single-use tokens sent by email
+	VerificationToken      null.String `json:"-" db:"verification_token"`
+	VerificationExpiresAt  null.Time   `json:"-" db:"verification_expires_at"`
+	PasswordResetToken     null.String `json:"-" db:"password_reset_token"`
+	PasswordResetExpiresAt null.Time   `json:"-" db:"password_reset_expires_at"`
+	CreatedAt              time.Time   `json:"created_at" db:"created_at"`
+	CreatedBy              *uuid.UUID  `json:"created_by" db:"created_by"`
+	UpdatedAt              null.Time   `json:"updated_at" db:"updated_at"`
+	UpdatedBy              *uuid.UUID  `json:"updated_by" db:"updated_by"`
+}
+
+// IsLocked reports whether too many failed logins locked the credential.
+func (c EmailCredential) IsLocked(now time.Time) bool {
+	return c.LockedUntil.Valid && c.LockedUntil.Time.After(now)
+}
+
+// NormalizeEmail lowercases and trims email, so lookups and the unique
+// constraint are case insensitive.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
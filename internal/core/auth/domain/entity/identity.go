@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Identity links a user to their account at an OpenID Connect provider.
+// Subject is the sub claim of the provider, stable for the account.
+type Identity struct {
+	ID          uuid.UUID   `db:"id"`
+	UserID      uuid.UUID   `db:"user_id"`
+	Provider    string      `db:"provider"`
+	Subject     string      `db:"subject"`
+	Email       null.String `db:"email"`
+	CreatedAt   time.Time   `db:"created_at"`
+	LastLoginAt null.Time   `db:"last_login_at"`
+}
+
+// LoginState is an OpenID Connect login waiting for the user to come back
+// from the provider with the authorization code. Only the HashToken of
+// the state is stored.
+type LoginState struct {
+	ID           uuid.UUID `db:"id"`
+	Provider     string    `db:"provider"`
+	StateHash    string    `db:"state_hash"`
+	CodeVerifier string    `db:"code_verifier"`
+	Nonce        string    `db:"nonce"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// OIDCProvider is an identity provider users may log in with
+type OIDCProvider struct {
+	Name string `json:"name"`
+}
+
+// OIDCAuthorization starts a login with an identity provider. The client
+// sends the user to AuthorizationURL and, when the provider redirects back,
+// posts the code and State to the callback route within ExpiresIn seconds.
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	ExpiresIn        int64  `json:"expires_in"`
+}
+
+// ExternalIdentity is the account a user logged in with at an identity
+// provider, as stated by the claims of its ID token
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	GivenName     string
+	FamilyName    string
+	Picture       string
+}
@@ -0,0 +1,12 @@
+package entity
+
+// Tokens is the token pair returned by register, login and refresh. Names
+// follow the OAuth 2.0 token response (RFC 6749).
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	// RefreshExpiresIn is the lifetime of the refresh token in seconds
+	RefreshExpiresIn int64 `json:"refresh_expires_in"`
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// RefreshToken is a stored refresh token. Only the SHA-256 hash of the
+// token is kept. Tokens rotate on every use; tokens issued from the same
+// login share a FamilyID, so a rotated token presented again revokes the
+// whole family.
+type RefreshToken struct {
+	ID         uuid.UUID   `db:"id"`
+	UserID     uuid.UUID   `db:"user_id"`
+	FamilyID   uuid.UUID   `db:"family_id"`
+	TokenHash  string      `db:"token_hash"`
+	ExpiresAt  time.Time   `db:"expires_at"`
+	RevokedAt  null.Time   `db:"revoked_at"`
+	ReplacedBy *uuid.UUID  `db:"replaced_by"`
+	UserAgent  null.String `db:"user_agent"`
+	IPAddress  null.String `db:"ip_address"`
+	CreatedAt  time.Time   `db:"created_at"`
+}
+
+// NewRefreshToken returns a refresh token of the family and its raw value,
+// which is only handed to the client.
+func NewRefreshToken(userID, familyID uuid.UUID, client ClientInfo, now time.Time, ttl time.Duration) (RefreshToken, string, error) {
+	raw, err := NewOpaqueToken()
+	if err != nil {
+		return RefreshToken{}, "", err
+	}
+
+	return RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: HashToken(raw),
+		ExpiresAt: now.Add(ttl),
+		UserAgent: null.NewString(client.UserAgent, client.UserAgent != ""),
+		IPAddress: null.NewString(client.IPAddress, client.IPAddress != ""),
+		CreatedAt: now,
+	}, raw, nil
+}
+
+// NewOpaqueToken returns a random URL-safe token of 256 bits. Only its
+// HashToken is stored.
+func NewOpaqueToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fault.Wrap(err).Message("failed to generate token")
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashToken returns the hex SHA-256 of an opaque token. Tokens carry 256
+// bits of randomness, so a fast hash is enough.
+func HashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsExpired reports whether the token can no longer be used.
+func (t RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// ClientInfo describes the client a token was issued to.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
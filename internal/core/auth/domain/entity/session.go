@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Session is a login of a user on a device. Its ID is the FamilyID of the
+// refresh tokens rotated from the login, and the sid claim of the access
+// tokens issued with them. The client and LastSeenAt follow the latest
+// refresh and request; OrganizationID is the organization the session
+// last acted on.
+type Session struct {
+	ID             uuid.UUID     `json:"id" db:"id"`
+	UserID         uuid.UUID     `json:"user_id" db:"user_id"`
+	OrganizationID uuid.NullUUID `json:"organization_id" db:"organization_id"`
+	UserAgent      null.String   `json:"user_agent" db:"user_agent"`
+	IPAddress      null.String   `json:"ip_address" db:"ip_address"`
+	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
+	LastSeenAt     time.Time     `json:"last_seen_at" db:"last_seen_at"`
+	ExpiresAt      time.Time     `json:"expires_at" db:"expires_at"`
+	RevokedAt      null.Time     `json:"-" db:"revoked_at"`
+	RevokedBy      *uuid.UUID    `json:"-" db:"revoked_by"`
+	// Current is set on the session of the request
+	Current bool `json:"current"`
+}
+
+// NewSession returns a session of the user opened now, which expires with
+// its first refresh token
+func NewSession(userID uuid.UUID, client ClientInfo, now time.Time, ttl time.Duration) Session {
+	return Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		UserAgent:  null.NewString(client.UserAgent, client.UserAgent != ""),
+		IPAddress:  null.NewString(client.IPAddress, client.IPAddress != ""),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+}
@@ -0,0 +1,43 @@
+package identity
+
+import (
+	"context"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth/oidc"
+)
+
+// Providers adapts oidc.Providers to ports.IdentityProviders
+type Providers struct {
+	providers *oidc.Providers
+}
+
+func NewProviders(providers *oidc.Providers) *Providers {
+	return &Providers{providers: providers}
+}
+
+func (p *Providers) Names() []string {
+	return p.providers.Names()
+}
+
+func (p *Providers) AuthCodeURL(ctx context.Context, provider, state, nonce, challenge string) (string, error) {
+	return p.providers.AuthCodeURL(ctx, provider, state, nonce, challenge)
+}
+
+func (p *Providers) Exchange(ctx context.Context, provider, code, verifier, nonce string) (entity.ExternalIdentity, error) {
+	claims, err := p.providers.Exchange(ctx, provider, code, verifier, nonce)
+	if err != nil {
+		return entity.ExternalIdentity{}, err
+	}
+
+	return entity.ExternalIdentity{
+		Provider:      provider,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+		Picture:       claims.Picture,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package presentation
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/request"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// AuthHandler exposes the email and password and the OpenID Connect logins
+// over HTTP. Its methods
+// are adapted to echo handlers with server.Handle.
+type AuthHandler struct {
+	usecase ports.AuthUseCase
+	tracer  trace.Tracer
+}
+
+func NewAuthHandler(usecase ports.AuthUseCase) *AuthHandler {
+	return &AuthHandler{
+		usecase: usecase,
+		tracer:  otel.Tracer("auth-handler"),
+	}
+}
+
+// Register creates a user with an email credential and returns its tokens
+func (h *AuthHandler) Register(ctx context.Context, req entity.RegisterRequest) (entity.Tokens, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.Register")
+	defer span.End()
+
+	req.Client = entity.ClientInfo(request.ClientFrom(ctx))
+	return h.usecase.Register(ctx, req)
+}
+
+// Login exchanges an email and password for tokens
+func (h *AuthHandler) Login(ctx context.Context, req entity.LoginRequest) (entity.Tokens, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.Login")
+	defer span.End()
+
+	req.Client = entity.ClientInfo(request.ClientFrom(ctx))
+	return h.usecase.Login(ctx, req)
+}
+
+// Refresh rotates a refresh token
+func (h *AuthHandler) Refresh(ctx context.Context, req entity.RefreshRequest) (entity.Tokens, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.Refresh")
+	defer span.End()
+
+	req.Client = entity.ClientInfo(request.ClientFrom(ctx))
+	return h.usecase.Refresh(ctx, req)
+}
+
+// Logout revokes a refresh token and the tokens rotated from the same login
+func (h *AuthHandler) Logout(ctx context.Context, req entity.RefreshRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.Logout")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.Logout(ctx, req)
+}
+
+// VerifyEmail marks an email as verified with the token sent to it
+func (h *AuthHandler) VerifyEmail(ctx context.Context, req entity.VerifyEmailRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.VerifyEmail")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.VerifyEmail(ctx, req)
+}
+
+// ResendVerification sends a new verification email
+func (h *AuthHandler) ResendVerification(ctx context.Context, req entity.EmailRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.ResendVerification")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.ResendVerification(ctx, req)
+}
+
+// ForgotPassword emails a password reset link
+func (h *AuthHandler) ForgotPassword(ctx context.Context, req entity.EmailRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.ForgotPassword")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.ForgotPassword(ctx, req)
+}
+
+// ResetPassword sets a new password with the token of a password reset link
+func (h *AuthHandler) ResetPassword(ctx context.Context, req entity.ResetPasswordRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.ResetPassword")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.ResetPassword(ctx, req)
+}
+
+// ChangePassword changes the password of the caller
+func (h *AuthHandler) ChangePassword(ctx context.Context, req entity.ChangePasswordRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.ChangePassword")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.ChangePassword(ctx, req)
+}
+
+// OIDCProviders lists the identity providers users may log in with
+func (h *AuthHandler) OIDCProviders(ctx context.Context, _ struct{}) (types.List[entity.OIDCProvider], error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.OIDCProviders")
+	defer span.End()
+
+	return h.usecase.OIDCProviders(ctx)
+}
+
+// StartOIDCLogin returns the URL to send users to for logging in with an
+// identity provider
+func (h *AuthHandler) StartOIDCLogin(ctx context.Context, req entity.OIDCAuthorizeRequest) (entity.OIDCAuthorization, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.StartOIDCLogin")
+	defer span.End()
+
+	return h.usecase.StartOIDCLogin(ctx, req)
+}
+
+// CompleteOIDCLogin exchanges the code of an identity provider for tokens
+func (h *AuthHandler) CompleteOIDCLogin(ctx context.Context, req entity.OIDCCallbackRequest) (entity.Tokens, error) {
+	ctx, span := h.tracer.Start(ctx, "AuthHandler.CompleteOIDCLogin")
+	defer span.End()
+
+	req.Client = entity.ClientInfo(request.ClientFrom(ctx))
+	return h.usecase.CompleteOIDCLogin(ctx, req)
+}
@@ -0,0 +1,60 @@
+package presentation
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// SessionHandler exposes the login sessions of the caller and, to admins,
+// of other users. Its methods are adapted to echo handlers with
+// server.Handle.
+type SessionHandler struct {
+	usecase ports.SessionUseCase
+	tracer  trace.Tracer
+}
+
+func NewSessionHandler(usecase ports.SessionUseCase) *SessionHandler {
+	return &SessionHandler{
+		usecase: usecase,
+		tracer:  otel.Tracer("sessions-handler"),
+	}
+}
+
+// MySessions lists the active sessions of the caller
+func (h *SessionHandler) MySessions(ctx context.Context, _ struct{}) (types.List[entity.Session], error) {
+	ctx, span := h.tracer.Start(ctx, "SessionHandler.MySessions")
+	defer span.End()
+
+	return h.usecase.MySessions(ctx)
+}
+
+// RevokeMySession ends a session of the caller
+func (h *SessionHandler) RevokeMySession(ctx context.Context, req entity.SessionIDRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "SessionHandler.RevokeMySession")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.RevokeMySession(ctx, req.ID)
+}
+
+// UserSessions lists the active sessions of a user
+func (h *SessionHandler) UserSessions(ctx context.Context, req entity.UserSessionsRequest) (types.List[entity.Session], error) {
+	ctx, span := h.tracer.Start(ctx, "SessionHandler.UserSessions")
+	defer span.End()
+
+	return h.usecase.UserSessions(ctx, req.UserID)
+}
+
+// RevokeUserSessions logs a user out of every session
+func (h *SessionHandler) RevokeUserSessions(ctx context.Context, req entity.UserSessionsRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "SessionHandler.RevokeUserSessions")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.RevokeUserSessions(ctx, req.UserID)
+}
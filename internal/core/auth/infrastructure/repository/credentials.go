@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+// uniqueViolation is the PostgreSQL error code of unique constraint violations
+const uniqueViolation = "23505"
+
+const credentialColumns = `id, user_id, email, password_hash, is_verified, failed_login_attempts, locked_until, last_login_at,
+	verification_token, verification_expires_at, password_reset_token, password_reset_expires_at,
+	created_at, created_by, updated_at, updated_by`
+
+func scanCredential(row pgx.Row) (entity.EmailCredential, error) {
+	var credential entity.EmailCredential
+	err := row.Scan(
+		&credential.ID,
+		&credential.UserID,
+		&credential.Email,
+		&credential.PasswordHash,
+		&credential.IsVerified,
+		&credential.FailedLoginAttempts,
+		&credential.LockedUntil,
+		&credential.LastLoginAt,
+		&credential.VerificationToken,
+		&credential.VerificationExpiresAt,
+		&credential.PasswordResetToken,
+		&credential.PasswordResetExpiresAt,
+		&credential.CreatedAt,
+		&credential.CreatedBy,
+		&credential.UpdatedAt,
+		&credential.UpdatedBy,
+	)
+	return credential, err
+}
+
+type CredentialRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewCredentialRepository(db ports.Database) *CredentialRepository {
+	return &CredentialRepository{
+		db:     db,
+		tracer: otel.Tracer("credentials-repository"),
+	}
+}
+
+func (r *CredentialRepository) WithTx(tx ports.Transaction) ports.CredentialRepository {
+	return &CredentialRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *CredentialRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *CredentialRepository) Create(ctx context.Context, credential entity.EmailCredential) error {
+	ctx, span := r.tracer.Start(ctx, "CredentialRepository.Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO auth.email_credentials (id, user_id, email, password_hash, is_verified,
+			verification_token, verification_expires_at, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.getExecutor().Exec(ctx, query,
+		credential.ID,
+		credential.UserID,
+		credential.Email,
+		credential.PasswordHash,
+		credential.IsVerified,
+		credential.VerificationToken,
+		credential.VerificationExpiresAt,
+		credential.CreatedAt,
+		credential.CreatedBy,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return fault.Wrap(err).Code(fault.Conflict).Message("email is already registered")
+		}
+		return fault.Wrap(err).Message("failed to create email credential")
+	}
+
+	return nil
+}
+
+func (r *CredentialRepository) FindByEmail(ctx context.Context, email string) (entity.EmailCredential, error) {
+	ctx, span := r.tracer.Start(ctx, "CredentialRepository.FindByEmail")
+	defer span.End()
+
+	query := `
+		SELECT ` + credentialColumns + `
+		FROM auth.email_credentials
+		WHERE email = $1
+	`
+
+	credential, err := scanCredential(r.getExecutor().QueryRow(ctx, query, email))
+	if err != nil {
+		return entity.EmailCredential{}, fault.Wrap(err).Message("failed to find email credential")
+	}
+
+	return credential, nil
+}
+
+func (r *CredentialRepository) FindByUser(ctx context.Context, userID uuid.UUID) (entity.EmailCredential, error) {
+	ctx, span := r.tracer.Start(ctx, "CredentialRepository.FindByUser")
+	defer span.End()
+
+	query := `
+		SELECT ` + credentialColumns + `
+		FROM auth.email_credentials
+		WHERE user_id = $1
+	`
+
+	credential, err := scanCredential(r.getExecutor().QueryRow(ctx, query, userID))
+	if err != nil {
+		return entity.EmailCredential{}, fault.Wrap(err).Message("failed to find email credential")
+	}
+
+	return credential, nil
+}
+
+func (r *CredentialRepository) RecordFailedLogin(ctx context.Context, id uuid.UUID, maxAttempts int, lockUntil time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "CredentialRepository.RecordFailedLogin")
+	defer span.End()
+
+	query := `
+		UPDATE auth.email_credentials
+		SET failed_login_attempts = CASE WHEN failed_login_attempts + 1 >= $2 THEN 0 ELSE failed_login_attempts + 1 END,
+			locked_until = CASE WHEN failed_login_attempts + 1 >= $2 THEN $3 ELSE locked_until END
+		WHERE id = $1
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, id, maxAttempts, lockUntil); err != nil {
+		return fault.Wrap(err).Message("failed to record failed login")
+	}
+
+	return nil
+}
+
+func (r *CredentialRepository) RecordLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "CredentialRepository.RecordLogin")
+	defer span.End()
+
+	query := `
+		UPDATE auth.email_credentials
+		SET failed_login_attempts = 0, locked_until = NULL, last_login_at = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, id, at); err != nil {
+		return fault.Wrap(err).Message("failed to record login")
+	}
+
+	return nil
+}
+
+func (r *CredentialRepository) UpdatePasswordHash(ctx context.Context, id uuid.UUID, hash string, at time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "CredentialRepository.UpdatePasswordHash")
+	defer span.End()
+
+	query := `
+		UPDATE auth.email_credentials
+		SET password_hash = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, id, hash, at); err != nil {
+		return fault.Wrap(err).Message("failed to update password hash")
+	}
+
+	return nil
+}
+
+func (r *CredentialRepository) SetVerificationToken(ctx context.Context, id uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "CredentialRepository.SetVerificationToken")
+	defer span.End()
+
+	query := `
+		UPDATE auth.email_credentials
+		SET verification_token = $2, verification_expires_at = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, id, tokenHash, expiresAt); err != nil {
+		return fault.Wrap(err).Message("failed to set verification token")
+	}
+
+	return nil
+}
+
+func (r *CredentialRepository) VerifyEmail(ctx context.Context, tokenHash string, at time.Time) (entity.EmailCredential, error) {
+	ctx, span := r.tracer.Start(ctx, "CredentialRepository.VerifyEmail")
+	defer span.End()
+
+	query := `
+		UPDATE auth.email_credentials
+		SET is_verified = true, verification_token = NULL, verification_expires_at = NULL, updated_at = $2
+		WHERE verification_token = $1 AND verification_expires_at > $2
+		RETURNING ` + credentialColumns
+
+	credential, err := scanCredential(r.getExecutor().QueryRow(ctx, query, tokenHash, at))
+	if err != nil {
+		return entity.EmailCredential{}, fault.Wrap(err).Message("failed to verify email")
+	}
+
+	return credential, nil
+}
+
+func (r *CredentialRepository) SetPasswordResetToken(ctx context.Context, id uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "CredentialRepository.SetPasswordResetToken")
+	defer span.End()
+
+	query := `
+		UPDATE auth.email_credentials
+		SET password_reset_token = $2, password_reset_expires_at = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, id, tokenHash, expiresAt); err != nil {
+		return fault.Wrap(err).Message("failed to set password reset token")
+	}
+
+	return nil
+}
+
+// ResetPassword also marks the email as verified, since the token was
+// received at it.
+func (r *CredentialRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string, at time.Time) (entity.EmailCredential, error) {
+	ctx, span := r.tracer.Start(ctx, "CredentialRepository.ResetPassword")
+	defer span.End()
+
+	query := `
+		UPDATE auth.email_credentials
+		SET password_hash = $2, password_reset_token = NULL, password_reset_expires_at = NULL,
+			is_verified = true, verification_token = NULL, verification_expires_at = NULL,
+			failed_login_attempts = 0, locked_until = NULL, updated_at = $3
+		WHERE password_reset_token = $1 AND password_reset_expires_at > $3
+		RETURNING ` + credentialColumns
+
+	credential, err := scanCredential(r.getExecutor().QueryRow(ctx, query, tokenHash, passwordHash, at))
+	if err != nil {
+		return entity.EmailCredential{}, fault.Wrap(err).Message("failed to reset password")
+	}
+
+	return credential, nil
+}
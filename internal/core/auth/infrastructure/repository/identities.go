@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+type IdentityRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewIdentityRepository(db ports.Database) *IdentityRepository {
+	return &IdentityRepository{
+		db:     db,
+		tracer: otel.Tracer("identities-repository"),
+	}
+}
+
+func (r *IdentityRepository) WithTx(tx ports.Transaction) ports.IdentityRepository {
+	return &IdentityRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *IdentityRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *IdentityRepository) Create(ctx context.Context, identity entity.Identity) error {
+	ctx, span := r.tracer.Start(ctx, "IdentityRepository.Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO auth.user_identities (id, user_id, provider, subject, email, created_at, last_login_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.getExecutor().Exec(ctx, query,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		identity.CreatedAt,
+		identity.LastLoginAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return fault.Wrap(err).Code(fault.Conflict).Message("account is already linked to a user")
+		}
+		return fault.Wrap(err).Message("failed to create identity")
+	}
+
+	return nil
+}
+
+func (r *IdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (entity.Identity, error) {
+	ctx, span := r.tracer.Start(ctx, "IdentityRepository.FindBySubject")
+	defer span.End()
+
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at, last_login_at
+		FROM auth.user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	var identity entity.Identity
+	err := r.getExecutor().QueryRow(ctx, query, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+	if err != nil {
+		return entity.Identity{}, fault.Wrap(err).Message("failed to find identity")
+	}
+
+	return identity, nil
+}
+
+func (r *IdentityRepository) RecordLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "IdentityRepository.RecordLogin")
+	defer span.End()
+
+	query := `
+		UPDATE auth.user_identities
+		SET last_login_at = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, id, at); err != nil {
+		return fault.Wrap(err).Message("failed to record login")
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+type LoginStateRepository struct {
+	db     ports.Database
+	tracer trace.Tracer
+}
+
+func NewLoginStateRepository(db ports.Database) *LoginStateRepository {
+	return &LoginStateRepository{
+		db:     db,
+		tracer: otel.Tracer("login-states-repository"),
+	}
+}
+
+func (r *LoginStateRepository) Create(ctx context.Context, state entity.LoginState) error {
+	ctx, span := r.tracer.Start(ctx, "LoginStateRepository.Create")
+	defer span.End()
+
+	// Logins that were never completed are cleaned up here, so the table
+	// does not need a job of its own
+	if _, err := r.db.Exec(ctx, `DELETE FROM auth.oidc_login_states WHERE expires_at <= $1`, state.CreatedAt); err != nil {
+		return fault.Wrap(err).Message("failed to delete expired login states")
+	}
+
+	query := `
+		INSERT INTO auth.oidc_login_states (id, provider, state_hash, code_verifier, nonce, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		state.ID,
+		state.Provider,
+		state.StateHash,
+		state.CodeVerifier,
+		state.Nonce,
+		state.ExpiresAt,
+		state.CreatedAt,
+	)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to create login state")
+	}
+
+	return nil
+}
+
+func (r *LoginStateRepository) Consume(ctx context.Context, provider, stateHash string, now time.Time) (entity.LoginState, error) {
+	ctx, span := r.tracer.Start(ctx, "LoginStateRepository.Consume")
+	defer span.End()
+
+	query := `
+		DELETE FROM auth.oidc_login_states
+		WHERE provider = $1 AND state_hash = $2 AND expires_at > $3
+		RETURNING id, provider, state_hash, code_verifier, nonce, expires_at, created_at
+	`
+
+	var state entity.LoginState
+	err := r.db.QueryRow(ctx, query, provider, stateHash, now).Scan(
+		&state.ID,
+		&state.Provider,
+		&state.StateHash,
+		&state.CodeVerifier,
+		&state.Nonce,
+		&state.ExpiresAt,
+		&state.CreatedAt,
+	)
+	if err != nil {
+		return entity.LoginState{}, fault.Wrap(err).Message("failed to consume login state")
+	}
+
+	return state, nil
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+// activeRoles selects the ids of the active, unexpired roles of user $1,
+// limited to the roles of organization $2 when it is not null
+const activeRoles = `
+	SELECT r.id
+	FROM auth.user_roles ur
+	JOIN auth.roles r ON r.id = ur.role_id
+	WHERE ur.user_id = $1
+		AND ur.is_active
+		AND (ur.expires_at IS NULL OR ur.expires_at > NOW())
+		AND r.is_active
+		AND ($2::uuid IS NULL OR r.organization_id = $2)
+`
+
+type PermissionRepository struct {
+	db     ports.Database
+	tracer trace.Tracer
+}
+
+func NewPermissionRepository(db ports.Database) *PermissionRepository {
+	return &PermissionRepository{
+		db:     db,
+		tracer: otel.Tracer("permissions-repository"),
+	}
+}
+
+func (r *PermissionRepository) Permissions(ctx context.Context, userID uuid.UUID, organizationID uuid.NullUUID) (rbac.Permissions, error) {
+	ctx, span := r.tracer.Start(ctx, "PermissionRepository.Permissions")
+	defer span.End()
+
+	permissions := rbac.Permissions{Actions: map[string]rbac.Scope{}}
+
+	// Public actions are granted to every user
+	actionsQuery := `
+		SELECT m.code || '.' || ma.code, ma.visibility_scope
+		FROM auth.module_actions ma
+		JOIN auth.modules m ON m.id = ma.module_id
+		WHERE ma.is_public OR ma.id IN (
+			SELECT p.module_action_id
+			FROM auth.permissions p
+			WHERE p.role_id IN (` + activeRoles + `)
+		)
+	`
+	rows, err := r.db.Query(ctx, actionsQuery, userID, organizationID)
+	if err != nil {
+		return rbac.Permissions{}, fault.Wrap(err).Message("failed to load permissions")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code, scope string
+		if err := rows.Scan(&code, &scope); err != nil {
+			return rbac.Permissions{}, fault.Wrap(err).Message("failed to scan permission")
+		}
+		permissions.Actions[code] = rbac.Scope(scope)
+	}
+	if err := rows.Err(); err != nil {
+		return rbac.Permissions{}, fault.Wrap(err).Message("failed to load permissions")
+	}
+
+	// Without an organization the roles of every organization are merged,
+	// so each action is limited to the organizations of the roles granting
+	// it. Roles without an organization grant it in all of them.
+	if !organizationID.Valid {
+		actionOrganizationsQuery := `
+			SELECT m.code || '.' || ma.code, array_agg(DISTINCT r.organization_id)
+			FROM auth.permissions p
+			JOIN auth.roles r ON r.id = p.role_id
+			JOIN auth.module_actions ma ON ma.id = p.module_action_id
+			JOIN auth.modules m ON m.id = ma.module_id
+			WHERE NOT ma.is_public AND p.role_id IN (` + activeRoles + `)
+			GROUP BY 1
+			HAVING bool_and(r.organization_id IS NOT NULL)
+		`
+		rows, err := r.db.Query(ctx, actionOrganizationsQuery, userID, organizationID)
+		if err != nil {
+			return rbac.Permissions{}, fault.Wrap(err).Message("failed to load permission organizations")
+		}
+
+		permissions.ActionOrganizations = map[string][]uuid.UUID{}
+		for rows.Next() {
+			var code string
+			var organizations []uuid.UUID
+			if err := rows.Scan(&code, &organizations); err != nil {
+				rows.Close()
+				return rbac.Permissions{}, fault.Wrap(err).Message("failed to scan permission organizations")
+			}
+			permissions.ActionOrganizations[code] = organizations
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return rbac.Permissions{}, fault.Wrap(err).Message("failed to load permission organizations")
+		}
+	}
+
+	rolesQuery := `
+		SELECT DISTINCT code
+		FROM auth.roles
+		WHERE id IN (` + activeRoles + `)
+		ORDER BY code
+	`
+	rows, err = r.db.Query(ctx, rolesQuery, userID, organizationID)
+	if err == nil {
+		permissions.Roles, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	}
+	if err != nil {
+		return rbac.Permissions{}, fault.Wrap(err).Message("failed to load roles")
+	}
+
+	// Memberships of inactive or deleted organizations grant no access
+	organizationsQuery := `
+		SELECT DISTINCT ou.organization_id
+		FROM auth.organization_users ou
+		JOIN auth.organizations o ON o.id = ou.organization_id
+		WHERE ou.user_id = $1
+			AND ou.is_active
+			AND o.is_active
+			AND o.deleted_at IS NULL
+			AND ($2::uuid IS NULL OR ou.organization_id = $2)
+	`
+	rows, err = r.db.Query(ctx, organizationsQuery, userID, organizationID)
+	if err == nil {
+		permissions.Organizations, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	}
+	if err != nil {
+		return rbac.Permissions{}, fault.Wrap(err).Message("failed to load organizations")
+	}
+
+	rootQuery := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM auth.organization_users ou
+			JOIN auth.organizations o ON o.id = ou.organization_id
+			WHERE ou.user_id = $1
+				AND ou.is_active
+				AND o.is_active
+				AND o.deleted_at IS NULL
+				AND o.is_root_organization
+		)
+	`
+	if err := r.db.QueryRow(ctx, rootQuery, userID).Scan(&permissions.Root); err != nil {
+		return rbac.Permissions{}, fault.Wrap(err).Message("failed to load root membership")
+	}
+
+	return permissions, nil
+}
+
+// APIKeyPermissions loads the actions granted to the API key, with the
+// organization of the key. Revoked and expired keys, and keys of inactive
+// organizations, are granted nothing.
+func (r *PermissionRepository) APIKeyPermissions(ctx context.Context, apiKeyID uuid.UUID) (rbac.Permissions, error) {
+	ctx, span := r.tracer.Start(ctx, "PermissionRepository.APIKeyPermissions")
+	defer span.End()
+
+	permissions := rbac.Permissions{Actions: map[string]rbac.Scope{}}
+
+	keyQuery := `
+		SELECT k.organization_id
+		FROM auth.api_keys k
+		JOIN auth.organizations o ON o.id = k.organization_id
+		WHERE k.id = $1
+			AND k.revoked_at IS NULL
+			AND (k.expires_at IS NULL OR k.expires_at > NOW())
+			AND o.is_active
+			AND o.deleted_at IS NULL
+	`
+	rows, err := r.db.Query(ctx, keyQuery, apiKeyID)
+	if err == nil {
+		permissions.Organizations, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	}
+	if err != nil {
+		return rbac.Permissions{}, fault.Wrap(err).Message("failed to load API key")
+	}
+	if len(permissions.Organizations) == 0 {
+		return permissions, nil
+	}
+
+	actionsQuery := `
+		SELECT m.code || '.' || ma.code, ma.visibility_scope
+		FROM auth.api_key_actions ka
+		JOIN auth.module_actions ma ON ma.id = ka.module_action_id
+		JOIN auth.modules m ON m.id = ma.module_id
+		WHERE ka.api_key_id = $1
+	`
+	rows, err = r.db.Query(ctx, actionsQuery, apiKeyID)
+	if err != nil {
+		return rbac.Permissions{}, fault.Wrap(err).Message("failed to load API key permissions")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code, scope string
+		if err := rows.Scan(&code, &scope); err != nil {
+			return rbac.Permissions{}, fault.Wrap(err).Message("failed to scan permission")
+		}
+		permissions.Actions[code] = rbac.Scope(scope)
+	}
+	if err := rows.Err(); err != nil {
+		return rbac.Permissions{}, fault.Wrap(err).Message("failed to load API key permissions")
+	}
+
+	return permissions, nil
+}
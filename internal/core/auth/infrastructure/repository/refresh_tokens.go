@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+type RefreshTokenRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewRefreshTokenRepository(db ports.Database) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db:     db,
+		tracer: otel.Tracer("refresh-tokens-repository"),
+	}
+}
+
+func (r *RefreshTokenRepository) WithTx(tx ports.Transaction) ports.RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *RefreshTokenRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token entity.RefreshToken) error {
+	ctx, span := r.tracer.Start(ctx, "RefreshTokenRepository.Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO auth.refresh_tokens (id, user_id, family_id, token_hash, expires_at, user_agent, ip_address, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.getExecutor().Exec(ctx, query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.UserAgent,
+		token.IPAddress,
+		token.CreatedAt,
+	)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to create refresh token")
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, hash string) (entity.RefreshToken, error) {
+	ctx, span := r.tracer.Start(ctx, "RefreshTokenRepository.FindByHash")
+	defer span.End()
+
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, replaced_by, user_agent, ip_address, created_at
+		FROM auth.refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+
+	var token entity.RefreshToken
+	err := r.getExecutor().QueryRow(ctx, query, hash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+		&token.UserAgent,
+		&token.IPAddress,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return entity.RefreshToken{}, fault.Wrap(err).Message("failed to find refresh token")
+	}
+
+	return token, nil
+}
+
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, id, replacedBy uuid.UUID, at time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "RefreshTokenRepository.Rotate")
+	defer span.End()
+
+	query := `
+		UPDATE auth.refresh_tokens
+		SET revoked_at = $3, replaced_by = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, id, replacedBy, at); err != nil {
+		return fault.Wrap(err).Message("failed to rotate refresh token")
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "RefreshTokenRepository.RevokeFamily")
+	defer span.End()
+
+	query := `
+		UPDATE auth.refresh_tokens
+		SET revoked_at = $2
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, familyID, at); err != nil {
+		return fault.Wrap(err).Message("failed to revoke refresh token family")
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "RefreshTokenRepository.RevokeUser")
+	defer span.End()
+
+	query := `
+		UPDATE auth.refresh_tokens
+		SET revoked_at = $2
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, userID, at); err != nil {
+		return fault.Wrap(err).Message("failed to revoke refresh tokens")
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+const sessionColumns = "id, user_id, organization_id, user_agent, ip_address, created_at, last_seen_at, expires_at, revoked_at, revoked_by"
+
+type SessionRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewSessionRepository(db ports.Database) *SessionRepository {
+	return &SessionRepository{
+		db:     db,
+		tracer: otel.Tracer("sessions-repository"),
+	}
+}
+
+func (r *SessionRepository) WithTx(tx ports.Transaction) ports.SessionRepository {
+	return &SessionRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *SessionRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session entity.Session) error {
+	ctx, span := r.tracer.Start(ctx, "SessionRepository.Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO auth.sessions (id, user_id, organization_id, user_agent, ip_address, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.getExecutor().Exec(ctx, query,
+		session.ID,
+		session.UserID,
+		session.OrganizationID,
+		session.UserAgent,
+		session.IPAddress,
+		session.CreatedAt,
+		session.LastSeenAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to create session")
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) Renew(ctx context.Context, id uuid.UUID, client entity.ClientInfo, at, expiresAt time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "SessionRepository.Renew")
+	defer span.End()
+
+	// The client is kept when the refresh does not tell it
+	query := `
+		UPDATE auth.sessions
+		SET user_agent = COALESCE(NULLIF($2, ''), user_agent),
+			ip_address = COALESCE(NULLIF($3, ''), ip_address),
+			last_seen_at = $4,
+			expires_at = $5
+		WHERE id = $1
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, id, client.UserAgent, client.IPAddress, at, expiresAt); err != nil {
+		return fault.Wrap(err).Message("failed to renew session")
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) Touch(ctx context.Context, id uuid.UUID, at time.Time, organizationID uuid.NullUUID) error {
+	ctx, span := r.tracer.Start(ctx, "SessionRepository.Touch")
+	defer span.End()
+
+	query := `
+		UPDATE auth.sessions
+		SET last_seen_at = GREATEST(last_seen_at, $2), organization_id = COALESCE($3, organization_id)
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING id
+	`
+
+	if err := r.getExecutor().QueryRow(ctx, query, id, at, organizationID).Scan(&id); err != nil {
+		return fault.Wrap(err).Message("failed to record session activity")
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) ListActive(ctx context.Context, userID uuid.UUID, now time.Time) (types.List[entity.Session], error) {
+	ctx, span := r.tracer.Start(ctx, "SessionRepository.ListActive")
+	defer span.End()
+
+	query := "SELECT " + sessionColumns + `
+		FROM auth.sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.getExecutor().Query(ctx, query, userID, now)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list sessions")
+	}
+	defer rows.Close()
+
+	list := types.List[entity.Session]{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fault.Wrap(err).Message("failed to scan session")
+		}
+		list = append(list, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fault.Wrap(err).Message("failed to list sessions")
+	}
+
+	return list, nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, userID, id uuid.UUID, at time.Time, by *uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "SessionRepository.Revoke")
+	defer span.End()
+
+	query := `
+		UPDATE auth.sessions
+		SET revoked_at = $3, revoked_by = $4
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+		RETURNING id
+	`
+
+	if err := r.getExecutor().QueryRow(ctx, query, id, userID, at, by).Scan(&id); err != nil {
+		return fault.Wrap(err).Message("failed to revoke session")
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time, by *uuid.UUID) ([]uuid.UUID, error) {
+	ctx, span := r.tracer.Start(ctx, "SessionRepository.RevokeUser")
+	defer span.End()
+
+	query := `
+		UPDATE auth.sessions
+		SET revoked_at = $2, revoked_by = $3
+		WHERE user_id = $1 AND revoked_at IS NULL
+		RETURNING id
+	`
+
+	rows, err := r.getExecutor().Query(ctx, query, userID, at, by)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to revoke sessions")
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to revoke sessions")
+	}
+
+	return ids, nil
+}
+
+func (r *SessionRepository) RevokeOthers(ctx context.Context, userID, keep uuid.UUID, at time.Time, by *uuid.UUID) ([]uuid.UUID, error) {
+	ctx, span := r.tracer.Start(ctx, "SessionRepository.RevokeOthers")
+	defer span.End()
+
+	query := `
+		UPDATE auth.sessions
+		SET revoked_at = $3, revoked_by = $4
+		WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL
+		RETURNING id
+	`
+
+	rows, err := r.getExecutor().Query(ctx, query, userID, keep, at, by)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to revoke sessions")
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to revoke sessions")
+	}
+
+	return ids, nil
+}
+
+func (r *SessionRepository) RevokedSince(ctx context.Context, since time.Time) ([]uuid.UUID, error) {
+	ctx, span := r.tracer.Start(ctx, "SessionRepository.RevokedSince")
+	defer span.End()
+
+	rows, err := r.getExecutor().Query(ctx, "SELECT id FROM auth.sessions WHERE revoked_at >= $1", since)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list revoked sessions")
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list revoked sessions")
+	}
+
+	return ids, nil
+}
+
+func scanSession(row pgx.Row) (entity.Session, error) {
+	var session entity.Session
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.OrganizationID,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.RevokedBy,
+	)
+	return session, err
+}
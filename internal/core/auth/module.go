@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/application"
+	"api.system.soluciones-cloud.com/internal/core/auth/infrastructure/identity"
+	"api.system.soluciones-cloud.com/internal/core/auth/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/core/auth/infrastructure/repository"
+	"api.system.soluciones-cloud.com/internal/shared/auth/oidc"
+	"api.system.soluciones-cloud.com/internal/shared/auth/password"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/auth/session"
+	"api.system.soluciones-cloud.com/internal/shared/auth/token"
+	"api.system.soluciones-cloud.com/internal/shared/localconfig"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			repository.NewCredentialRepository,
+			fx.As(new(ports.CredentialRepository)),
+		),
+		fx.Annotate(
+			repository.NewRefreshTokenRepository,
+			fx.As(new(ports.RefreshTokenRepository)),
+		),
+		fx.Annotate(
+			repository.NewSessionRepository,
+			fx.As(new(ports.SessionRepository)),
+		),
+		fx.Annotate(
+			session.NewDenylist,
+			fx.As(new(ports.SessionDenylist)),
+		),
+		fx.Annotate(
+			repository.NewIdentityRepository,
+			fx.As(new(ports.IdentityRepository)),
+		),
+		fx.Annotate(
+			repository.NewLoginStateRepository,
+			fx.As(new(ports.LoginStateRepository)),
+		),
+		fx.Annotate(
+			newIdentityProviders,
+			fx.As(new(ports.IdentityProviders)),
+		),
+		fx.Annotate(
+			repository.NewPermissionRepository,
+			fx.As(new(ports.PermissionRepository)),
+		),
+		fx.Annotate(
+			newAuthorizer,
+			fx.As(new(ports.Authorizer)),
+		),
+		fx.Annotate(
+			newPasswordHasher,
+			fx.As(new(ports.PasswordHasher)),
+		),
+		newConfig,
+		fx.Annotate(
+			application.NewAuthUseCase,
+			fx.As(new(ports.AuthUseCase)),
+		),
+		fx.Annotate(
+			application.NewSessionUseCase,
+			fx.As(fx.Self()),
+			fx.As(new(ports.SessionUseCase)),
+		),
+		presentation.NewAuthHandler,
+		presentation.NewSessionHandler,
+	),
+	fx.Invoke(restoreDenylist),
+)
+
+func newPasswordHasher() *password.Hasher {
+	return password.NewHasher(password.DefaultParams)
+}
+
+func newAuthorizer(store ports.PermissionRepository, config *localconfig.Config) *rbac.Authorizer {
+	return rbac.NewAuthorizer(store, config.Auth.PermissionCacheTTL)
+}
+
+// oidcTimeout bounds the requests to identity providers, which are made
+// while a login request waits
+const oidcTimeout = 10 * time.Second
+
+func newIdentityProviders(config *localconfig.Config) *identity.Providers {
+	configs := make([]oidc.Config, 0, len(config.OIDC.Providers))
+	for _, provider := range config.OIDC.Providers {
+		configs = append(configs, oidc.Config{
+			Name:         provider.Name,
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+			ClockSkew:    config.JWT.ClockSkew,
+		})
+	}
+
+	return identity.NewProviders(oidc.NewProviders(configs, &http.Client{Timeout: oidcTimeout}))
+}
+
+func newConfig(config *localconfig.Config) application.Config {
+	return application.Config{
+		RefreshTokenTTL:       config.Auth.RefreshTokenTTL,
+		MaxFailedLogins:       config.Auth.MaxFailedLogins,
+		LockoutDuration:       config.Auth.LockoutDuration,
+		VerifyEmailURL:        config.Auth.VerifyEmailURL,
+		ResetPasswordURL:      config.Auth.ResetPasswordURL,
+		VerificationTokenTTL:  config.Auth.VerificationTokenTTL,
+		PasswordResetTokenTTL: config.Auth.PasswordResetTokenTTL,
+		OIDCStateTTL:          config.OIDC.StateTTL,
+		ClockSkew:             clockSkew(config),
+	}
+}
+
+func clockSkew(config *localconfig.Config) time.Duration {
+	if config.JWT.ClockSkew == 0 {
+		return token.DefaultClockSkew
+	}
+	return config.JWT.ClockSkew
+}
+
+// restoreDenylist denies the sessions revoked before the server started
+// whose access tokens may still be valid
+func restoreDenylist(lc fx.Lifecycle, sessions *application.SessionUseCase) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return sessions.RestoreDenylist(ctx)
+		},
+	})
+}
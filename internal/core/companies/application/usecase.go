@@ -0,0 +1,340 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/core/companies/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/taxid"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// visibility restricts OWN scopes to the companies created by the caller
+// and ORG scopes to the companies of the caller's organizations
+var visibility = rbac.Visibility{Owner: "created_by", Organization: "organization_id"}
+
+// CompanyUseCase manages the companies of the organizations. Company
+// types and statuses are options of the config catalogs, so they can be
+// extended without a release. Tax ids are normalized by the rules of the
+// country of the company and unique within its organizations.
+type CompanyUseCase struct {
+	uow       ports.UnitOfWork
+	companies ports.CompanyRepository
+	now       func() time.Time
+	tracer    trace.Tracer
+}
+
+func NewCompanyUseCase(uow ports.UnitOfWork, companies ports.CompanyRepository) *CompanyUseCase {
+	return &CompanyUseCase{
+		uow:       uow,
+		companies: companies,
+		now:       time.Now,
+		tracer:    otel.Tracer("companies-usecase"),
+	}
+}
+
+// CreateCompany creates a company in the organization of the request, or
+// the one of the tenant
+func (u *CompanyUseCase) CreateCompany(ctx context.Context, req entity.CreateCompanyRequest) (entity.Company, error) {
+	ctx, span := u.tracer.Start(ctx, "CreateCompany")
+	defer span.End()
+
+	organizationID, err := tenant.Assign(ctx, req.OrganizationID)
+	if err != nil {
+		return entity.Company{}, err
+	}
+	if organizationID == uuid.Nil {
+		return entity.Company{}, fault.New("organization is required").Code(fault.BadRequest)
+	}
+
+	company := entity.Company{
+		ID:             uuid.New(),
+		BusinessName:   req.BusinessName,
+		CommercialName: types.OptionalString(req.CommercialName),
+		TaxID:          types.OptionalString(req.TaxID),
+		Email:          types.OptionalString(req.Email),
+		Phone:          types.OptionalString(req.Phone),
+		Website:        types.OptionalString(req.Website),
+		AddressLine1:   types.OptionalString(req.AddressLine1),
+		AddressLine2:   types.OptionalString(req.AddressLine2),
+		City:           types.OptionalString(req.City),
+		StateProvince:  types.OptionalString(req.StateProvince),
+		PostalCode:     types.OptionalString(req.PostalCode),
+		Country:        types.OptionalString(req.Country),
+		Industry:       types.OptionalString(req.Industry),
+		CompanySize:    types.OptionalString(req.CompanySize),
+		CompanyType:    req.CompanyType,
+		Status:         req.Status,
+		IsActive:       true,
+		Notes:          types.OptionalString(req.Notes),
+		CreatedAt:      u.now(),
+		CreatedBy:      auth.ActorID(ctx),
+	}
+	if company.CompanyType == "" {
+		company.CompanyType = entity.DefaultCompanyType
+	}
+	if company.Status == "" {
+		company.Status = entity.DefaultCompanyStatus
+	}
+
+	if err := checkOptions(ctx, company); err != nil {
+		return entity.Company{}, err
+	}
+	if err := normalizeTaxID(&company); err != nil {
+		return entity.Company{}, err
+	}
+
+	// The tax id is checked once the company is in the organization, in
+	// the same transaction
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		companies := u.companies.WithTx(tx)
+		if err := companies.Create(ctx, company); err != nil {
+			return fault.Wrap(err).Message("failed to create company")
+		}
+		if err := companies.AddOrganization(ctx, company.ID, organizationID, company.CreatedAt, company.CreatedBy); err != nil {
+			return fault.Wrap(err).Message("failed to create company")
+		}
+		return checkTaxID(ctx, companies, company)
+	})
+	if err != nil {
+		return entity.Company{}, err
+	}
+
+	return company, nil
+}
+
+func (u *CompanyUseCase) GetCompanyByID(ctx context.Context, id uuid.UUID) (entity.Company, error) {
+	ctx, span := u.tracer.Start(ctx, "GetCompanyByID")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, dafi.Where("id", dafi.Equal, id), visibility)
+	if err != nil {
+		return entity.Company{}, err
+	}
+
+	company, err := u.companies.Find(ctx, criteria)
+	if err != nil {
+		return entity.Company{}, fault.Wrap(err).Message("failed to get company by ID")
+	}
+
+	return company, nil
+}
+
+func (u *CompanyUseCase) ListCompanies(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Company], error) {
+	ctx, span := u.tracer.Start(ctx, "ListCompanies")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, visibility)
+	if err != nil {
+		return types.List[entity.Company]{}, err
+	}
+
+	companies, err := u.companies.List(ctx, criteria)
+	if err != nil {
+		return types.List[entity.Company]{}, fault.Wrap(err).Message("failed to list companies")
+	}
+
+	return companies, nil
+}
+
+func (u *CompanyUseCase) CountCompanies(ctx context.Context, criteria dafi.Criteria) (int64, error) {
+	ctx, span := u.tracer.Start(ctx, "CountCompanies")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, visibility)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := u.companies.Count(ctx, criteria)
+	if err != nil {
+		return 0, fault.Wrap(err).Message("failed to count companies")
+	}
+
+	return count, nil
+}
+
+func (u *CompanyUseCase) UpdateCompany(ctx context.Context, req entity.UpdateCompanyRequest) (entity.Company, error) {
+	ctx, span := u.tracer.Start(ctx, "UpdateCompany")
+	defer span.End()
+
+	company, err := u.GetCompanyByID(ctx, req.ID)
+	if err != nil {
+		return entity.Company{}, err
+	}
+
+	previous := company
+
+	if req.BusinessName.Valid {
+		company.BusinessName = req.BusinessName.String
+	}
+	types.SetString(&company.CommercialName, req.CommercialName)
+	types.SetString(&company.TaxID, req.TaxID)
+	types.SetString(&company.Email, req.Email)
+	types.SetString(&company.Phone, req.Phone)
+	types.SetString(&company.Website, req.Website)
+	types.SetString(&company.AddressLine1, req.AddressLine1)
+	types.SetString(&company.AddressLine2, req.AddressLine2)
+	types.SetString(&company.City, req.City)
+	types.SetString(&company.StateProvince, req.StateProvince)
+	types.SetString(&company.PostalCode, req.PostalCode)
+	types.SetString(&company.Country, req.Country)
+	types.SetString(&company.Industry, req.Industry)
+	types.SetString(&company.CompanySize, req.CompanySize)
+	types.SetString(&company.Notes, req.Notes)
+	if req.IsActive.Valid {
+		company.IsActive = req.IsActive.Bool
+	}
+
+	// Options removed from the catalogs stay valid on the companies that
+	// already use them
+	if (req.CompanyType.Valid && req.CompanyType.String != company.CompanyType) ||
+		(req.Status.Valid && req.Status.String != company.Status) {
+		if req.CompanyType.Valid {
+			company.CompanyType = req.CompanyType.String
+		}
+		if req.Status.Valid {
+			company.Status = req.Status.String
+		}
+		if err := checkOptions(ctx, company); err != nil {
+			return entity.Company{}, err
+		}
+	}
+
+	// Like options, tax ids stored before they were validated are kept
+	// until the tax id or the country changes
+	if company.TaxID != previous.TaxID || company.Country != previous.Country {
+		if err := normalizeTaxID(&company); err != nil {
+			return entity.Company{}, err
+		}
+	}
+
+	company.UpdatedAt = null.TimeFrom(u.now())
+	company.UpdatedBy = auth.ActorID(ctx)
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		companies := u.companies.WithTx(tx)
+		if company.TaxID != previous.TaxID {
+			if err := checkTaxID(ctx, companies, company); err != nil {
+				return err
+			}
+		}
+		if err := companies.Update(ctx, company, dafi.FilterBy("id", dafi.Equal, company.ID)...); err != nil {
+			return fault.Wrap(err).Message("failed to update company")
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.Company{}, err
+	}
+
+	return company, nil
+}
+
+func (u *CompanyUseCase) DeleteCompany(ctx context.Context, id uuid.UUID) error {
+	ctx, span := u.tracer.Start(ctx, "DeleteCompany")
+	defer span.End()
+
+	if _, err := u.GetCompanyByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := u.companies.Delete(ctx, dafi.FilterBy("id", dafi.Equal, id)...); err != nil {
+		return fault.Wrap(err).Message("failed to delete company")
+	}
+
+	return nil
+}
+
+func (u *CompanyUseCase) RestoreCompany(ctx context.Context, id uuid.UUID) (entity.Company, error) {
+	ctx, span := u.tracer.Start(ctx, "RestoreCompany")
+	defer span.End()
+
+	filters, err := rbac.Restrict(ctx, dafi.FilterBy("id", dafi.Equal, id), visibility)
+	if err != nil {
+		return entity.Company{}, err
+	}
+
+	// Another company may have taken the tax id while this one was deleted
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		companies := u.companies.WithTx(tx)
+		if err := companies.Restore(ctx, filters...); err != nil {
+			return fault.Wrap(err).Message("failed to restore company")
+		}
+
+		company, err := companies.Find(ctx, dafi.Where("id", dafi.Equal, id))
+		if err != nil {
+			return fault.Wrap(err).Message("failed to restore company")
+		}
+		return checkTaxID(ctx, companies, company)
+	})
+	if err != nil {
+		return entity.Company{}, err
+	}
+
+	return u.GetCompanyByID(ctx, id)
+}
+
+// checkOptions validates the type and status of the company against the
+// active options of their catalogs
+func checkOptions(ctx context.Context, company entity.Company) error {
+	result := valid.Object(map[string]valid.Schema{
+		"company_type": valid.String().Required().Rule(ports.CatalogOption(entity.CompanyTypeCatalog)),
+		"status":       valid.String().Required().Rule(ports.CatalogOption(entity.CompanyStatusCatalog)),
+	}).ParseCtx(ctx, company)
+	if result.Cause != nil {
+		return fault.Wrap(result.Cause).Message("failed to check catalog options")
+	}
+	if !result.Success {
+		return fault.Wrap(&result.Errors[0]).Code(fault.UnprocessableEntity).Message("validation failed")
+	}
+	return nil
+}
+
+// normalizeTaxID validates the tax id of the company by the rules of its
+// country and replaces it with its normalized form
+func normalizeTaxID(company *entity.Company) error {
+	if !company.TaxID.Valid {
+		return nil
+	}
+
+	result := valid.Object(map[string]valid.Schema{
+		"tax_id": valid.String().Custom(taxid.Rule(company.Country.String)),
+	}).Parse(*company)
+	if !result.Success {
+		return fault.Wrap(&result.Errors[0]).Code(fault.UnprocessableEntity).Message("validation failed")
+	}
+
+	company.TaxID.String, _ = taxid.Normalize(company.Country.String, company.TaxID.String)
+	return nil
+}
+
+// checkTaxID returns a fault.Conflict error when another company of the
+// organizations of the company has its tax id. companies must be bound to
+// the transaction writing the company.
+func checkTaxID(ctx context.Context, companies ports.CompanyRepository, company entity.Company) error {
+	if !company.TaxID.Valid {
+		return nil
+	}
+
+	taken, err := companies.TaxIDTaken(ctx, company)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fault.New("a company with this tax id already exists in the organization").Code(fault.Conflict)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// CreateCompanyRequest creates a company in an organization, the one of
+// the tenant unless OrganizationID is given. CompanyType defaults to
+// CUSTOMER and Status to ACTIVE; both must be options of their catalog.
+// TaxID is validated and normalized by the rules of Country, see taxid.
+type CreateCompanyRequest struct {
+	OrganizationID uuid.UUID `json:"organization_id,omitempty"`
+	BusinessName   string    `json:"business_name"`
+	CommercialName string    `json:"commercial_name,omitempty"`
+	TaxID          string    `json:"tax_id,omitempty"`
+	Email          string    `json:"email,omitempty"`
+	Phone          string    `json:"phone,omitempty"`
+	Website        string    `json:"website,omitempty"`
+	AddressLine1   string    `json:"address_line_1,omitempty"`
+	AddressLine2   string    `json:"address_line_2,omitempty"`
+	City           string    `json:"city,omitempty"`
+	StateProvince  string    `json:"state_province,omitempty"`
+	PostalCode     string    `json:"postal_code,omitempty"`
+	Country        string    `json:"country,omitempty"`
+	Industry       string    `json:"industry,omitempty"`
+	CompanySize    string    `json:"company_size,omitempty"`
+	CompanyType    string    `json:"company_type,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	Notes          string    `json:"notes,omitempty"`
+}
+
+func (r CreateCompanyRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"organization_id": valid.String().UUID(),
+		"business_name":   valid.String().MaxLength(200).Required(),
+		"commercial_name": valid.String().MaxLength(200),
+		"tax_id":          valid.String().MaxLength(50),
+		"email":           valid.String().Email().MaxLength(255),
+		"phone":           valid.String().MaxLength(20),
+		"website":         valid.String().MaxLength(255),
+		"address_line_1":  valid.String().MaxLength(255),
+		"address_line_2":  valid.String().MaxLength(255),
+		"city":            valid.String().MaxLength(100),
+		"state_province":  valid.String().MaxLength(100),
+		"postal_code":     valid.String().MaxLength(20),
+		"country":         valid.String().MaxLength(100),
+		"industry":        valid.String().MaxLength(100),
+		"company_size":    valid.String().MaxLength(50),
+		"company_type":    valid.String().MaxLength(50),
+		"status":          valid.String().MaxLength(50),
+		"notes":           valid.String(),
+	})
+}
+
+func (r CreateCompanyRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// UpdateCompanyRequest changes the given fields of a company. An empty
+// string clears an optional field.
+type UpdateCompanyRequest struct {
+	ID             uuid.UUID   `json:"-" param:"id"`
+	BusinessName   null.String `json:"business_name,omitempty"`
+	CommercialName null.String `json:"commercial_name,omitempty"`
+	TaxID          null.String `json:"tax_id,omitempty"`
+	Email          null.String `json:"email,omitempty"`
+	Phone          null.String `json:"phone,omitempty"`
+	Website        null.String `json:"website,omitempty"`
+	AddressLine1   null.String `json:"address_line_1,omitempty"`
+	AddressLine2   null.String `json:"address_line_2,omitempty"`
+	City           null.String `json:"city,omitempty"`
+	StateProvince  null.String `json:"state_province,omitempty"`
+	PostalCode     null.String `json:"postal_code,omitempty"`
+	Country        null.String `json:"country,omitempty"`
+	Industry       null.String `json:"industry,omitempty"`
+	CompanySize    null.String `json:"company_size,omitempty"`
+	CompanyType    null.String `json:"company_type,omitempty"`
+	Status         null.String `json:"status,omitempty"`
+	IsActive       null.Bool   `json:"is_active,omitempty"`
+	Notes          null.String `json:"notes,omitempty"`
+}
+
+func (r UpdateCompanyRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"business_name":   valid.String().Length(1, 200),
+		"commercial_name": valid.String().MaxLength(200),
+		"tax_id":          valid.String().MaxLength(50),
+		"email":           valid.String().Email().MaxLength(255),
+		"phone":           valid.String().MaxLength(20),
+		"website":         valid.String().MaxLength(255),
+		"address_line_1":  valid.String().MaxLength(255),
+		"address_line_2":  valid.String().MaxLength(255),
+		"city":            valid.String().MaxLength(100),
+		"state_province":  valid.String().MaxLength(100),
+		"postal_code":     valid.String().MaxLength(20),
+		"country":         valid.String().MaxLength(100),
+		"industry":        valid.String().MaxLength(100),
+		"company_size":    valid.String().MaxLength(50),
+		"company_type":    valid.String().Length(1, 50),
+		"status":          valid.String().Length(1, 50),
+		"is_active":       valid.Bool(),
+		"notes":           valid.String(),
+	})
+}
+
+func (r UpdateCompanyRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Catalogs (config.catalog_types codes) the company types and statuses are
+// validated against
+const (
+	CompanyTypeCatalog   = "company_type"
+	CompanyStatusCatalog = "customer_statuses"
+)
+
+// Defaults of new companies
+const (
+	DefaultCompanyType   = "CUSTOMER"
+	DefaultCompanyStatus = "ACTIVE"
+)
+
+// Company is a business the organizations work with: a customer,
+// prospect, partner, vendor or supplier. Companies belong to organizations
+// through auth.organization_customers, and a company may belong to
+// several of them.
+type Company struct {
+	ID             uuid.UUID   `json:"id" db:"id"`
+	BusinessName   string      `json:"business_name" db:"business_name"`
+	CommercialName null.String `json:"commercial_name" db:"commercial_name"`
+	TaxID          null.String `json:"tax_id" db:"tax_id"`
+	Email          null.String `json:"email" db:"email"`
+	Phone          null.String `json:"phone" db:"phone"`
+	Website        null.String `json:"website" db:"website"`
+	AddressLine1   null.String `json:"address_line_1" db:"address_line_1"`
+	AddressLine2   null.String `json:"address_line_2" db:"address_line_2"`
+	City           null.String `json:"city" db:"city"`
+	StateProvince  null.String `json:"state_province" db:"state_province"`
+	PostalCode     null.String `json:"postal_code" db:"postal_code"`
+	Country        null.String `json:"country" db:"country"`
+	Industry       null.String `json:"industry" db:"industry"`
+	CompanySize    null.String `json:"company_size" db:"company_size"`
+	CompanyType    string      `json:"company_type" db:"company_type"`
+	Status         string      `json:"status" db:"status"`
+	IsActive       bool        `json:"is_active" db:"is_active"`
+	Notes          null.String `json:"notes" db:"notes"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+	CreatedBy      *uuid.UUID  `json:"created_by" db:"created_by"`
+	UpdatedAt      null.Time   `json:"updated_at" db:"updated_at"`
+	UpdatedBy      *uuid.UUID  `json:"updated_by" db:"updated_by"`
+	DeletedAt      null.Time   `json:"deleted_at" db:"deleted_at"`
+	DeletedBy      *uuid.UUID  `json:"deleted_by" db:"deleted_by"`
+}
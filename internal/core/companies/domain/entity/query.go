@@ -0,0 +1,136 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/taxid"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// CompanySortFields are the fields companies can be sorted by
+var CompanySortFields = []string{"id", "business_name", "commercial_name", "tax_id", "company_type", "status", "is_active", "country", "created_at", "updated_at"}
+
+// CompanyFilter holds the filters accepted by the list and count
+// endpoints. Deleted companies are left out unless Deleted is set.
+type CompanyFilter struct {
+	// Search matches the business name or the tax id partially, ignoring
+	// case
+	Search         string     `json:"search,omitempty" query:"search"`
+	BusinessName   string     `json:"business_name,omitempty" query:"business_name"`
+	TaxID          string     `json:"tax_id,omitempty" query:"tax_id"`
+	CompanyType    string     `json:"company_type,omitempty" query:"company_type"`
+	Status         string     `json:"status,omitempty" query:"status"`
+	Industry       string     `json:"industry,omitempty" query:"industry"`
+	Country        string     `json:"country,omitempty" query:"country"`
+	IsActive       *bool      `json:"is_active,omitempty" query:"is_active"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" query:"organization_id"`
+	Deleted        *bool      `json:"deleted,omitempty" query:"deleted"`
+}
+
+// Criteria returns the filters as dafi criteria. Business names match
+// partially, ignoring case, and tax ids in any format.
+func (f CompanyFilter) Criteria() dafi.Criteria {
+	criteria := dafi.New()
+
+	if f.Search != "" {
+		criteria = criteria.AndGroup(
+			dafi.Filter{Field: "business_name", Operator: dafi.Contains, Value: f.Search, ChainingKey: dafi.Or},
+			dafi.Filter{Field: "tax_id", Operator: dafi.Contains, Value: f.Search},
+		)
+	}
+	if f.BusinessName != "" {
+		criteria = criteria.And("business_name", dafi.Contains, f.BusinessName)
+	}
+	if f.TaxID != "" {
+		taxID, err := taxid.Normalize("", f.TaxID)
+		if err != nil {
+			taxID = f.TaxID
+		}
+		criteria = criteria.And("tax_id", dafi.Equal, taxID)
+	}
+	if f.CompanyType != "" {
+		criteria = criteria.And("company_type", dafi.Equal, f.CompanyType)
+	}
+	if f.Status != "" {
+		criteria = criteria.And("status", dafi.Equal, f.Status)
+	}
+	if f.Industry != "" {
+		criteria = criteria.And("industry", dafi.Equal, f.Industry)
+	}
+	if f.Country != "" {
+		criteria = criteria.And("country", dafi.Equal, f.Country)
+	}
+	if f.IsActive != nil {
+		criteria = criteria.And("is_active", dafi.Equal, *f.IsActive)
+	}
+	if f.OrganizationID != nil {
+		criteria = criteria.And("organization_id", dafi.Equal, *f.OrganizationID)
+	}
+	if f.Deleted != nil {
+		if *f.Deleted {
+			criteria = criteria.And("deleted_at", dafi.IsNotNull, nil)
+		} else {
+			criteria = criteria.And("deleted_at", dafi.IsNull, nil)
+		}
+	}
+
+	return criteria
+}
+
+// ListCompaniesRequest adds pagination and sorting to CompanyFilter
+type ListCompaniesRequest struct {
+	CompanyFilter
+	Page      uint   `json:"page,omitempty" query:"page"`
+	PageSize  uint   `json:"page_size,omitempty" query:"page_size"`
+	SortBy    string `json:"sort_by,omitempty" query:"sort_by"`
+	SortOrder string `json:"sort_order,omitempty" query:"sort_order"`
+}
+
+func (r ListCompaniesRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"page":       valid.Int().Min(1),
+		"page_size":  valid.Int().Range(1, MaxPageSize),
+		"sort_by":    valid.Enum(CompanySortFields...),
+		"sort_order": valid.Enum("asc", "desc").CaseInsensitive(),
+	})
+}
+
+func (r ListCompaniesRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// Criteria returns the filters, page and sort as dafi criteria. The first
+// page of DefaultPageSize companies is returned when no page is given.
+func (r ListCompaniesRequest) Criteria() dafi.Criteria {
+	page, pageSize := r.Page, r.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	criteria := r.CompanyFilter.Criteria().Page(page).Limit(pageSize)
+
+	if r.SortBy != "" {
+		if strings.EqualFold(r.SortOrder, "desc") {
+			criteria = criteria.SortBy(r.SortBy, dafi.Desc)
+		} else {
+			criteria = criteria.SortBy(r.SortBy, dafi.Asc)
+		}
+	}
+
+	return criteria
+}
@@ -0,0 +1,96 @@
+package presentation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/companies/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// CompanyIDRequest binds the company id path parameter
+type CompanyIDRequest struct {
+	ID uuid.UUID `param:"id"`
+}
+
+// CompanyHandler exposes the company use cases over HTTP. Its methods are
+// adapted to echo handlers with server.Handle.
+type CompanyHandler struct {
+	usecase ports.CompanyUseCase
+	tracer  trace.Tracer
+}
+
+func NewCompanyHandler(usecase ports.CompanyUseCase) *CompanyHandler {
+	return &CompanyHandler{
+		usecase: usecase,
+		tracer:  otel.Tracer("companies-handler"),
+	}
+}
+
+// CreateCompany creates a company
+func (h *CompanyHandler) CreateCompany(ctx context.Context, req entity.CreateCompanyRequest) (entity.Company, error) {
+	ctx, span := h.tracer.Start(ctx, "CompanyHandler.CreateCompany")
+	defer span.End()
+
+	return h.usecase.CreateCompany(ctx, req)
+}
+
+// GetCompany gets a company by its ID
+func (h *CompanyHandler) GetCompany(ctx context.Context, req CompanyIDRequest) (entity.Company, error) {
+	ctx, span := h.tracer.Start(ctx, "CompanyHandler.GetCompany")
+	defer span.End()
+
+	return h.usecase.GetCompanyByID(ctx, req.ID)
+}
+
+// ListCompanies lists companies with optional filtering, sorting, and
+// pagination
+func (h *CompanyHandler) ListCompanies(ctx context.Context, req entity.ListCompaniesRequest) (types.List[entity.Company], error) {
+	ctx, span := h.tracer.Start(ctx, "CompanyHandler.ListCompanies")
+	defer span.End()
+
+	return h.usecase.ListCompanies(ctx, req.Criteria())
+}
+
+// CountCompanies counts companies with optional filtering
+func (h *CompanyHandler) CountCompanies(ctx context.Context, req entity.CompanyFilter) (response.CountResponse, error) {
+	ctx, span := h.tracer.Start(ctx, "CompanyHandler.CountCompanies")
+	defer span.End()
+
+	count, err := h.usecase.CountCompanies(ctx, req.Criteria())
+	if err != nil {
+		return response.CountResponse{}, err
+	}
+
+	return response.CountResponse{Count: count}, nil
+}
+
+// UpdateCompany updates a company
+func (h *CompanyHandler) UpdateCompany(ctx context.Context, req entity.UpdateCompanyRequest) (entity.Company, error) {
+	ctx, span := h.tracer.Start(ctx, "CompanyHandler.UpdateCompany")
+	defer span.End()
+
+	return h.usecase.UpdateCompany(ctx, req)
+}
+
+// DeleteCompany soft deletes a company
+func (h *CompanyHandler) DeleteCompany(ctx context.Context, req CompanyIDRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "CompanyHandler.DeleteCompany")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.DeleteCompany(ctx, req.ID)
+}
+
+// RestoreCompany undoes the deletion of a company
+func (h *CompanyHandler) RestoreCompany(ctx context.Context, req CompanyIDRequest) (entity.Company, error) {
+	ctx, span := h.tracer.Start(ctx, "CompanyHandler.RestoreCompany")
+	defer span.End()
+
+	return h.usecase.RestoreCompany(ctx, req.ID)
+}
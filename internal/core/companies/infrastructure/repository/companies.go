@@ -0,0 +1,456 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/companies/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/sqlcraft"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+const companyColumns = "id, business_name, commercial_name, tax_id, email, phone, website, address_line_1, address_line_2, city, state_province, postal_code, country, industry, company_size, company_type, status, is_active, notes, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by"
+
+// companyColumnByDomainField maps the fields companies can be filtered by
+// to their columns
+var companyColumnByDomainField = map[string]string{
+	"id":              "id",
+	"business_name":   "business_name",
+	"commercial_name": "commercial_name",
+	"tax_id":          "tax_id",
+	"email":           "email",
+	"industry":        "industry",
+	"company_size":    "company_size",
+	"company_type":    "company_type",
+	"status":          "status",
+	"is_active":       "is_active",
+	"city":            "city",
+	"country":         "country",
+	"created_at":      "created_at",
+	"created_by":      "created_by",
+	"updated_at":      "updated_at",
+	"updated_by":      "updated_by",
+	"deleted_at":      "deleted_at",
+}
+
+type CompanyRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewCompanyRepository(db ports.Database) *CompanyRepository {
+	return &CompanyRepository{
+		db:     db,
+		tracer: otel.Tracer("companies-repository"),
+	}
+}
+
+func (r *CompanyRepository) WithTx(tx ports.Transaction) ports.CompanyRepository {
+	return &CompanyRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *CompanyRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *CompanyRepository) Create(ctx context.Context, company entity.Company) error {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO relationships.companies (
+			id, business_name, commercial_name, tax_id, email, phone, website,
+			address_line_1, address_line_2, city, state_province, postal_code, country,
+			industry, company_size, company_type, status, is_active, notes, created_at, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`
+
+	_, err := r.getExecutor().Exec(ctx, query,
+		company.ID,
+		company.BusinessName,
+		company.CommercialName,
+		company.TaxID,
+		company.Email,
+		company.Phone,
+		company.Website,
+		company.AddressLine1,
+		company.AddressLine2,
+		company.City,
+		company.StateProvince,
+		company.PostalCode,
+		company.Country,
+		company.Industry,
+		company.CompanySize,
+		company.CompanyType,
+		company.Status,
+		company.IsActive,
+		company.Notes,
+		company.CreatedAt,
+		company.CreatedBy,
+	)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to create company")
+	}
+
+	return nil
+}
+
+func (r *CompanyRepository) CreateBulk(ctx context.Context, companies types.List[entity.Company]) error {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.CreateBulk")
+	defer span.End()
+
+	for _, company := range companies {
+		if err := r.Create(ctx, company); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddOrganization links the company to the organization, reactivating a
+// link that was deactivated
+func (r *CompanyRepository) AddOrganization(ctx context.Context, companyID, organizationID uuid.UUID, at time.Time, by *uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.AddOrganization")
+	defer span.End()
+
+	query := `
+		INSERT INTO auth.organization_customers (organization_id, customer_id, is_active, created_at, created_by)
+		VALUES ($1, $2, true, $3, $4)
+		ON CONFLICT (organization_id, customer_id)
+		DO UPDATE SET is_active = true, updated_at = EXCLUDED.created_at, updated_by = EXCLUDED.created_by
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, organizationID, companyID, at, by); err != nil {
+		return fault.Wrap(err).Message("failed to add company to organization")
+	}
+
+	return nil
+}
+
+func (r *CompanyRepository) Find(ctx context.Context, criteria dafi.Criteria) (entity.Company, error) {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.Find")
+	defer span.End()
+
+	clause, err := companyWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return entity.Company{}, err
+	}
+
+	query := "SELECT " + companyColumns + " FROM relationships.companies" + clause.Sql + " LIMIT 1"
+
+	company, err := scanCompany(r.getExecutor().QueryRow(ctx, query, clause.Args...))
+	if err != nil {
+		return entity.Company{}, fault.Wrap(err).Message("failed to find company")
+	}
+
+	return company, nil
+}
+
+func (r *CompanyRepository) List(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Company], error) {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.List")
+	defer span.End()
+
+	clause, err := companyWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := " ORDER BY business_name"
+	if !criteria.Sorts.IsZero() {
+		orderBy = sqlcraft.BuildOrderBy(criteria.Sorts)
+	}
+
+	query := "SELECT " + companyColumns + " FROM relationships.companies" + clause.Sql + orderBy + sqlcraft.BuildPagination(criteria.Pagination)
+
+	rows, err := r.getExecutor().Query(ctx, query, clause.Args...)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list companies")
+	}
+	defer rows.Close()
+
+	var companies types.List[entity.Company]
+	for rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			return nil, fault.Wrap(err).Message("failed to scan company")
+		}
+		companies = append(companies, company)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fault.Wrap(err).Message("failed to list companies")
+	}
+
+	return companies, nil
+}
+
+func (r *CompanyRepository) Update(ctx context.Context, company entity.Company, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.Update")
+	defer span.End()
+
+	clause, err := companyWhere(ctx, 19, filters)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE relationships.companies SET business_name = $1, commercial_name = $2, tax_id = $3, email = $4, phone = $5, website = $6,
+		address_line_1 = $7, address_line_2 = $8, city = $9, state_province = $10, postal_code = $11, country = $12,
+		industry = $13, company_size = $14, company_type = $15, status = $16, is_active = $17, notes = $18, updated_at = $19, updated_by = $20` + clause.Sql
+	args := append([]any{
+		company.BusinessName,
+		company.CommercialName,
+		company.TaxID,
+		company.Email,
+		company.Phone,
+		company.Website,
+		company.AddressLine1,
+		company.AddressLine2,
+		company.City,
+		company.StateProvince,
+		company.PostalCode,
+		company.Country,
+		company.Industry,
+		company.CompanySize,
+		company.CompanyType,
+		company.Status,
+		company.IsActive,
+		company.Notes,
+		company.UpdatedAt,
+		company.UpdatedBy,
+	}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to update company")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("company not found")).Code(fault.NotFound).Message("company not found")
+	}
+
+	return nil
+}
+
+// Delete soft deletes the companies matching the filters
+func (r *CompanyRepository) Delete(ctx context.Context, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.Delete")
+	defer span.End()
+
+	clause, err := companyWhere(ctx, 2, filters)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE relationships.companies SET deleted_at = $1, deleted_by = $2" + clause.Sql
+	args := append([]any{time.Now(), auth.UserIDFrom(ctx)}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to delete company")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("company not found")).Code(fault.NotFound).Message("company not found")
+	}
+
+	return nil
+}
+
+func (r *CompanyRepository) Restore(ctx context.Context, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.Restore")
+	defer span.End()
+
+	filters = append(dafi.Filters(filters).Grouped(), dafi.Filter{Field: "deleted_at", Operator: dafi.IsNotNull})
+	clause, err := companyWhere(ctx, 2, filters)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE relationships.companies SET deleted_at = NULL, deleted_by = NULL, updated_at = $1, updated_by = $2" + clause.Sql
+	args := append([]any{time.Now(), auth.UserIDFrom(ctx)}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to restore company")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("company not found")).Code(fault.NotFound).Message("deleted company not found")
+	}
+
+	return nil
+}
+
+// TaxIDTaken looks up the tax id among the companies sharing an
+// organization with the company, regardless of the tenant, so duplicates
+// are found even in organizations the caller cannot see. The tax id is
+// first locked in those organizations until the transaction ends, so
+// concurrent checks of the same tax id wait for the company to be written
+// and then find it.
+func (r *CompanyRepository) TaxIDTaken(ctx context.Context, company entity.Company) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.TaxIDTaken")
+	defer span.End()
+
+	if r.tx == nil {
+		return false, fault.New("company tax ids must be checked in a transaction")
+	}
+
+	// Locked in a statement of its own: the lookup must see the companies
+	// committed while waiting for the lock
+	lock := `
+		SELECT pg_advisory_xact_lock(hashtext(organization_id::text || $1))
+		FROM (
+			SELECT DISTINCT organization_id FROM auth.organization_customers
+			WHERE customer_id = $2 AND is_active
+			ORDER BY organization_id
+		) organizations
+	`
+	if _, err := r.getExecutor().Exec(ctx, lock, company.TaxID, company.ID); err != nil {
+		return false, fault.Wrap(err).Message("failed to lock company tax id")
+	}
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM relationships.companies c
+			JOIN auth.organization_customers oc ON oc.customer_id = c.id AND oc.is_active
+			WHERE c.tax_id = $1
+				AND c.id <> $2
+				AND c.deleted_at IS NULL
+				AND oc.organization_id IN (
+					SELECT organization_id FROM auth.organization_customers
+					WHERE customer_id = $2 AND is_active
+				)
+		)
+	`
+
+	var taken bool
+	if err := r.getExecutor().QueryRow(ctx, query, company.TaxID, company.ID).Scan(&taken); err != nil {
+		return false, fault.Wrap(err).Message("failed to check company tax id")
+	}
+
+	return taken, nil
+}
+
+func (r *CompanyRepository) Exists(ctx context.Context, criteria dafi.Criteria) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.Exists")
+	defer span.End()
+
+	clause, err := companyWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := r.getExecutor().QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM relationships.companies"+clause.Sql+")", clause.Args...).Scan(&exists); err != nil {
+		return false, fault.Wrap(err).Message("failed to check if company exists")
+	}
+
+	return exists, nil
+}
+
+func (r *CompanyRepository) Count(ctx context.Context, criteria dafi.Criteria) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "CompanyRepository.Count")
+	defer span.End()
+
+	clause, err := companyWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := r.getExecutor().QueryRow(ctx, "SELECT COUNT(*) FROM relationships.companies"+clause.Sql, clause.Args...).Scan(&count); err != nil {
+		return 0, fault.Wrap(err).Message("failed to count companies")
+	}
+
+	return count, nil
+}
+
+// companyWhere builds the WHERE clause of filters on companies, restricted
+// to the organizations of the tenant of ctx. Companies belong to
+// organizations through auth.organization_customers, so organization_id
+// filters become sub-queries. Deleted companies are left out unless the
+// filters select them by deleted_at.
+func companyWhere(ctx context.Context, initialArgCount int, filters dafi.Filters) (sqlcraft.Result, error) {
+	filters, err := tenant.Restrict(ctx, filters, "organization_id")
+	if err != nil {
+		return sqlcraft.Result{}, err
+	}
+
+	filters = slices.Clone(filters)
+	deleted := false
+	for i, filter := range filters {
+		switch filter.Field {
+		case "deleted_at":
+			deleted = true
+		case "organization_id":
+			switch filter.Operator {
+			case dafi.In:
+				filters[i].Field = "id IN (SELECT customer_id FROM auth.organization_customers WHERE is_active AND organization_id = ANY(?))"
+			case dafi.Equal, "":
+				filters[i].Field = "id IN (SELECT customer_id FROM auth.organization_customers WHERE is_active AND organization_id = ?)"
+			default:
+				return sqlcraft.Result{}, fault.New("unsupported organization_id filter").Code(fault.BadRequest).With("operator", filter.Operator)
+			}
+			filters[i].Operator = dafi.Default
+		}
+	}
+	if !deleted {
+		filters = filters.Grouped().And("deleted_at", dafi.IsNull, nil)
+	}
+
+	return sqlcraft.WhereSafe(initialArgCount, companyColumnByDomainField, filters...)
+}
+
+func scanCompany(row pgx.Row) (entity.Company, error) {
+	var company entity.Company
+	err := row.Scan(
+		&company.ID,
+		&company.BusinessName,
+		&company.CommercialName,
+		&company.TaxID,
+		&company.Email,
+		&company.Phone,
+		&company.Website,
+		&company.AddressLine1,
+		&company.AddressLine2,
+		&company.City,
+		&company.StateProvince,
+		&company.PostalCode,
+		&company.Country,
+		&company.Industry,
+		&company.CompanySize,
+		&company.CompanyType,
+		&company.Status,
+		&company.IsActive,
+		&company.Notes,
+		&company.CreatedAt,
+		&company.CreatedBy,
+		&company.UpdatedAt,
+		&company.UpdatedBy,
+		&company.DeletedAt,
+		&company.DeletedBy,
+	)
+	return company, err
+}
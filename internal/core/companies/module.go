@@ -0,0 +1,24 @@
+package companies
+
+import (
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/core/companies/application"
+	"api.system.soluciones-cloud.com/internal/core/companies/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/core/companies/infrastructure/repository"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			repository.NewCompanyRepository,
+			fx.As(new(ports.CompanyRepository)),
+		),
+		fx.Annotate(
+			application.NewCompanyUseCase,
+			fx.As(new(ports.CompanyUseCase)),
+		),
+		presentation.NewCompanyHandler,
+	),
+)
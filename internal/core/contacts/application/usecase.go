@@ -0,0 +1,352 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/core/contacts/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+	"api.system.soluciones-cloud.com/internal/shared/vcard"
+)
+
+// visibility restricts OWN scopes to the contacts created by the caller
+// and ORG scopes to the contacts of the caller's organizations
+var visibility = rbac.Visibility{Owner: "created_by", Organization: "organization_id"}
+
+// ContactUseCase manages the contacts of the companies. Contacts belong to
+// a company and to one of its organizations; within it, their emails are
+// unique per company and each contact type has at most one primary
+// contact.
+type ContactUseCase struct {
+	uow       ports.UnitOfWork
+	contacts  ports.ContactRepository
+	companies ports.CompanyRepository
+	now       func() time.Time
+	tracer    trace.Tracer
+}
+
+func NewContactUseCase(uow ports.UnitOfWork, contacts ports.ContactRepository, companies ports.CompanyRepository) *ContactUseCase {
+	return &ContactUseCase{
+		uow:       uow,
+		contacts:  contacts,
+		companies: companies,
+		now:       time.Now,
+		tracer:    otel.Tracer("contacts-usecase"),
+	}
+}
+
+// CreateContact creates a contact of a company in the organization of the
+// request, or the one of the tenant, which the company must belong to
+func (u *ContactUseCase) CreateContact(ctx context.Context, req entity.CreateContactRequest) (entity.Contact, error) {
+	ctx, span := u.tracer.Start(ctx, "CreateContact")
+	defer span.End()
+
+	organizationID, err := tenant.Assign(ctx, req.OrganizationID)
+	if err != nil {
+		return entity.Contact{}, err
+	}
+	if organizationID == uuid.Nil {
+		return entity.Contact{}, fault.New("organization is required").Code(fault.BadRequest)
+	}
+
+	exists, err := u.companies.Exists(ctx, dafi.Where("id", dafi.Equal, req.CompanyID).And("organization_id", dafi.Equal, organizationID))
+	if err != nil {
+		return entity.Contact{}, fault.Wrap(err).Message("failed to get company")
+	}
+	if !exists {
+		return entity.Contact{}, fault.New("company not found").Code(fault.NotFound)
+	}
+
+	contact := entity.Contact{
+		ID:             uuid.New(),
+		CompanyID:      req.CompanyID,
+		OrganizationID: &organizationID,
+		FirstName:      req.FirstName,
+		LastName:       types.OptionalString(req.LastName),
+		Email:          types.OptionalString(entity.NormalizeEmail(req.Email)),
+		Phone:          types.OptionalString(req.Phone),
+		Mobile:         types.OptionalString(req.Mobile),
+		Position:       types.OptionalString(req.Position),
+		Department:     types.OptionalString(req.Department),
+		ContactTypes:   entity.NormalizeTypes(req.ContactTypes),
+		PrimaryTypes:   entity.NormalizeTypes(req.PrimaryTypes),
+		Notes:          types.OptionalString(req.Notes),
+		CreatedAt:      u.now(),
+		CreatedBy:      auth.ActorID(ctx),
+	}
+
+	if err := checkTypes(ctx, contact); err != nil {
+		return entity.Contact{}, err
+	}
+	if err := u.checkEmail(ctx, contact); err != nil {
+		return entity.Contact{}, err
+	}
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		contacts := u.contacts.WithTx(tx)
+		if err := contacts.Create(ctx, contact); err != nil {
+			return err
+		}
+		if err := contacts.ReleasePrimary(ctx, contact); err != nil {
+			return fault.Wrap(err).Message("failed to create contact")
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.Contact{}, err
+	}
+
+	return contact, nil
+}
+
+func (u *ContactUseCase) GetContactByID(ctx context.Context, id uuid.UUID) (entity.Contact, error) {
+	ctx, span := u.tracer.Start(ctx, "GetContactByID")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, dafi.Where("id", dafi.Equal, id), visibility)
+	if err != nil {
+		return entity.Contact{}, err
+	}
+
+	contact, err := u.contacts.Find(ctx, criteria)
+	if err != nil {
+		return entity.Contact{}, fault.Wrap(err).Message("failed to get contact by ID")
+	}
+
+	return contact, nil
+}
+
+func (u *ContactUseCase) ListContacts(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Contact], error) {
+	ctx, span := u.tracer.Start(ctx, "ListContacts")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, visibility)
+	if err != nil {
+		return types.List[entity.Contact]{}, err
+	}
+
+	contacts, err := u.contacts.List(ctx, criteria)
+	if err != nil {
+		return types.List[entity.Contact]{}, fault.Wrap(err).Message("failed to list contacts")
+	}
+
+	return contacts, nil
+}
+
+func (u *ContactUseCase) CountContacts(ctx context.Context, criteria dafi.Criteria) (int64, error) {
+	ctx, span := u.tracer.Start(ctx, "CountContacts")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, visibility)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := u.contacts.Count(ctx, criteria)
+	if err != nil {
+		return 0, fault.Wrap(err).Message("failed to count contacts")
+	}
+
+	return count, nil
+}
+
+func (u *ContactUseCase) UpdateContact(ctx context.Context, req entity.UpdateContactRequest) (entity.Contact, error) {
+	ctx, span := u.tracer.Start(ctx, "UpdateContact")
+	defer span.End()
+
+	contact, err := u.GetContactByID(ctx, req.ID)
+	if err != nil {
+		return entity.Contact{}, err
+	}
+	previous := contact
+
+	if req.FirstName.Valid {
+		contact.FirstName = req.FirstName.String
+	}
+	types.SetString(&contact.LastName, req.LastName)
+	if req.Email.Valid {
+		contact.Email = types.OptionalString(entity.NormalizeEmail(req.Email.String))
+	}
+	types.SetString(&contact.Phone, req.Phone)
+	types.SetString(&contact.Mobile, req.Mobile)
+	types.SetString(&contact.Position, req.Position)
+	types.SetString(&contact.Department, req.Department)
+	types.SetString(&contact.Notes, req.Notes)
+	if req.ContactTypes != nil {
+		contact.ContactTypes = entity.NormalizeTypes(req.ContactTypes)
+	}
+	if req.PrimaryTypes != nil {
+		contact.PrimaryTypes = entity.NormalizeTypes(req.PrimaryTypes)
+	} else if req.ContactTypes != nil {
+		// The contact stops being primary for the types it no longer has
+		contact.PrimaryTypes = slices.DeleteFunc(slices.Clone(contact.PrimaryTypes), func(t string) bool {
+			return !slices.Contains(contact.ContactTypes, t)
+		})
+	}
+
+	// Types removed from the catalog stay valid on the contacts that
+	// already have them
+	if req.ContactTypes != nil || req.PrimaryTypes != nil {
+		if err := checkTypes(ctx, contact); err != nil {
+			return entity.Contact{}, err
+		}
+	}
+	if contact.Email != previous.Email {
+		if err := u.checkEmail(ctx, contact); err != nil {
+			return entity.Contact{}, err
+		}
+	}
+
+	contact.UpdatedAt = null.TimeFrom(u.now())
+	contact.UpdatedBy = auth.ActorID(ctx)
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		contacts := u.contacts.WithTx(tx)
+		if err := contacts.Update(ctx, contact, dafi.FilterBy("id", dafi.Equal, contact.ID)...); err != nil {
+			return err
+		}
+		if err := contacts.ReleasePrimary(ctx, contact); err != nil {
+			return fault.Wrap(err).Message("failed to update contact")
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.Contact{}, err
+	}
+
+	return contact, nil
+}
+
+func (u *ContactUseCase) DeleteContact(ctx context.Context, id uuid.UUID) error {
+	ctx, span := u.tracer.Start(ctx, "DeleteContact")
+	defer span.End()
+
+	if _, err := u.GetContactByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := u.contacts.Delete(ctx, dafi.FilterBy("id", dafi.Equal, id)...); err != nil {
+		return fault.Wrap(err).Message("failed to delete contact")
+	}
+
+	return nil
+}
+
+// ExportContacts returns the contacts of a company visible to the caller
+// as vCards
+func (u *ContactUseCase) ExportContacts(ctx context.Context, companyID uuid.UUID) (entity.ContactExport, error) {
+	ctx, span := u.tracer.Start(ctx, "ExportContacts")
+	defer span.End()
+
+	company, err := u.companies.Find(ctx, dafi.Where("id", dafi.Equal, companyID))
+	if err != nil {
+		return entity.ContactExport{}, fault.Wrap(err).Message("failed to get company")
+	}
+
+	contacts, err := u.ListContacts(ctx, dafi.Where("company_id", dafi.Equal, companyID))
+	if err != nil {
+		return entity.ContactExport{}, err
+	}
+
+	cards := make([]vcard.Card, 0, len(contacts))
+	for _, contact := range contacts {
+		cards = append(cards, card(contact, company.BusinessName))
+	}
+
+	var data bytes.Buffer
+	if err := vcard.Encode(&data, cards...); err != nil {
+		return entity.ContactExport{}, fault.Wrap(err).Message("failed to export contacts")
+	}
+
+	return entity.ContactExport{
+		FileName:    "contacts.vcf",
+		ContentType: vcard.ContentType,
+		Data:        data.Bytes(),
+	}, nil
+}
+
+// card returns the vCard of a contact of the company. Its contact types
+// are exported as categories.
+func card(contact entity.Contact, company string) vcard.Card {
+	c := vcard.Card{
+		UID:           "urn:uuid:" + contact.ID.String(),
+		FormattedName: contact.FullName(),
+		GivenName:     contact.FirstName,
+		FamilyName:    contact.LastName.String,
+		Organization:  []string{company},
+		Title:         contact.Position.String,
+		Categories:    contact.ContactTypes,
+		Note:          contact.Notes.String,
+		Revision:      contact.CreatedAt,
+	}
+	if contact.Department.Valid {
+		c.Organization = append(c.Organization, contact.Department.String)
+	}
+	if contact.Email.Valid {
+		c.Emails = []string{contact.Email.String}
+	}
+	if contact.Phone.Valid {
+		c.Phones = append(c.Phones, vcard.Phone{Number: contact.Phone.String, Types: []string{"work", "voice"}})
+	}
+	if contact.Mobile.Valid {
+		c.Phones = append(c.Phones, vcard.Phone{Number: contact.Mobile.String, Types: []string{"cell"}})
+	}
+	if contact.UpdatedAt.Valid {
+		c.Revision = contact.UpdatedAt.Time
+	}
+	return c
+}
+
+// checkTypes validates the types of the contact against the active options
+// of the contact_type catalog, and its primary types against its types
+func checkTypes(ctx context.Context, contact entity.Contact) error {
+	result := valid.Object(map[string]valid.Schema{
+		"contact_types": valid.Array(valid.String().Rule(ports.CatalogOption(entity.ContactTypeCatalog))),
+		"primary_types": valid.Array(valid.Enum(contact.ContactTypes...)),
+	}).ParseCtx(ctx, contact)
+	if result.Cause != nil {
+		return fault.Wrap(result.Cause).Message("failed to check catalog options")
+	}
+	if !result.Success {
+		return fault.Wrap(&result.Errors[0]).Code(fault.UnprocessableEntity).Message("validation failed")
+	}
+	return nil
+}
+
+// checkEmail returns a fault.Conflict error when another contact of the
+// company in the organization of the contact has its email. The unique
+// index on the emails settles concurrent requests.
+func (u *ContactUseCase) checkEmail(ctx context.Context, contact entity.Contact) error {
+	if !contact.Email.Valid {
+		return nil
+	}
+
+	criteria := dafi.Where("company_id", dafi.Equal, contact.CompanyID).
+		And("email", dafi.Equal, contact.Email.String).
+		And("id", dafi.NotEqual, contact.ID)
+	if contact.OrganizationID != nil {
+		criteria = criteria.And("organization_id", dafi.Equal, *contact.OrganizationID)
+	}
+	exists, err := u.contacts.Exists(ctx, criteria)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to check contact email")
+	}
+	if exists {
+		return fault.New("a contact with this email already exists in the company").Code(fault.Conflict)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// CreateContactRequest creates a contact of a company in an organization
+// of the company, the one of the tenant unless OrganizationID is given.
+// Contact types must be options of the contact_type catalog, and primary
+// types some of them.
+type CreateContactRequest struct {
+	CompanyID      uuid.UUID `json:"-" param:"id"`
+	OrganizationID uuid.UUID `json:"organization_id,omitempty"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name,omitempty"`
+	Email          string    `json:"email,omitempty"`
+	Phone          string    `json:"phone,omitempty"`
+	Mobile         string    `json:"mobile,omitempty"`
+	Position       string    `json:"position,omitempty"`
+	Department     string    `json:"department,omitempty"`
+	ContactTypes   []string  `json:"contact_types,omitempty"`
+	PrimaryTypes   []string  `json:"primary_types,omitempty"`
+	Notes          string    `json:"notes,omitempty"`
+}
+
+func (r CreateContactRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"organization_id": valid.String().UUID(),
+		"first_name":      valid.String().MaxLength(100).Required(),
+		"last_name":       valid.String().MaxLength(100),
+		"email":           valid.String().Email().MaxLength(255),
+		"phone":           valid.String().MaxLength(20),
+		"mobile":          valid.String().MaxLength(20),
+		"position":        valid.String().MaxLength(100),
+		"department":      valid.String().MaxLength(100),
+		"contact_types":   valid.Array(valid.String().Length(1, 50)),
+		"primary_types":   valid.Array(valid.String().Length(1, 50)),
+		"notes":           valid.String(),
+	})
+}
+
+func (r CreateContactRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// UpdateContactRequest changes the given fields of a contact. An empty
+// string clears an optional field. ContactTypes and PrimaryTypes replace
+// the types of the contact when given, an empty list clears them.
+type UpdateContactRequest struct {
+	ID           uuid.UUID   `json:"-" param:"id"`
+	FirstName    null.String `json:"first_name,omitempty"`
+	LastName     null.String `json:"last_name,omitempty"`
+	Email        null.String `json:"email,omitempty"`
+	Phone        null.String `json:"phone,omitempty"`
+	Mobile       null.String `json:"mobile,omitempty"`
+	Position     null.String `json:"position,omitempty"`
+	Department   null.String `json:"department,omitempty"`
+	ContactTypes []string    `json:"contact_types,omitempty"`
+	PrimaryTypes []string    `json:"primary_types,omitempty"`
+	Notes        null.String `json:"notes,omitempty"`
+}
+
+func (r UpdateContactRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"first_name":    valid.String().Length(1, 100),
+		"last_name":     valid.String().MaxLength(100),
+		"email":         valid.String().Email().MaxLength(255),
+		"phone":         valid.String().MaxLength(20),
+		"mobile":        valid.String().MaxLength(20),
+		"position":      valid.String().MaxLength(100),
+		"department":    valid.String().MaxLength(100),
+		"contact_types": valid.Array(valid.String().Length(1, 50)),
+		"primary_types": valid.Array(valid.String().Length(1, 50)),
+		"notes":         valid.String(),
+	})
+}
+
+func (r UpdateContactRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
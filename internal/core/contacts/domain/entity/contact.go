@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+// ContactTypeCatalog is the config.catalog_types code contact types are
+// validated against
+const ContactTypeCatalog = "contact_type"
+
+// Contact is a person of a company the organization deals with, e.g. its
+// billing or technical contact. A contact is the primary contact of its
+// company in its organization for the types in PrimaryTypes, a subset of
+// ContactTypes.
+type Contact struct {
+	ID             uuid.UUID   `json:"id" db:"id"`
+	CompanyID      uuid.UUID   `json:"company_id" db:"company_id"`
+	OrganizationID *uuid.UUID  `json:"organization_id" db:"organization_id"`
+	FirstName      string      `json:"first_name" db:"first_name"`
+	LastName       null.String `json:"last_name" db:"last_name"`
+	Email          null.String `json:"email" db:"email"`
+	Phone          null.String `json:"phone" db:"phone"`
+	Mobile         null.String `json:"mobile" db:"mobile"`
+	Position       null.String `json:"position" db:"position"`
+	Department     null.String `json:"department" db:"department"`
+	ContactTypes   []string    `json:"contact_types" db:"contact_types"`
+	PrimaryTypes   []string    `json:"primary_types" db:"primary_types"`
+	Notes          null.String `json:"notes" db:"notes"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+	CreatedBy      *uuid.UUID  `json:"created_by" db:"created_by"`
+	UpdatedAt      null.Time   `json:"updated_at" db:"updated_at"`
+	UpdatedBy      *uuid.UUID  `json:"updated_by" db:"updated_by"`
+	DeletedAt      null.Time   `json:"deleted_at" db:"deleted_at"`
+	DeletedBy      *uuid.UUID  `json:"deleted_by" db:"deleted_by"`
+}
+
+// FullName returns the first and last names of the contact
+func (c Contact) FullName() string {
+	if !c.LastName.Valid {
+		return c.FirstName
+	}
+	return c.FirstName + " " + c.LastName.String
+}
+
+// NormalizeEmail trims and lower cases an email, so duplicates are found
+// regardless of how they were written
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeTypes upper cases contact types and removes duplicates, keeping
+// their order. It never returns nil, so types are stored as a JSON array.
+func NormalizeTypes(types []string) []string {
+	normalized := make([]string, 0, len(types))
+	for _, t := range types {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		if !slices.Contains(normalized, t) {
+			normalized = append(normalized, t)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// ContactSortFields are the fields contacts can be sorted by
+var ContactSortFields = []string{"id", "first_name", "last_name", "email", "position", "department", "created_at", "updated_at"}
+
+// ContactFilter holds the filters accepted by the list and count
+// endpoints. CompanyID is bound from the path on the routes nested under
+// a company.
+type ContactFilter struct {
+	// Search matches the first name, last name or email partially,
+	// ignoring case
+	Search         string     `json:"search,omitempty" query:"search"`
+	CompanyID      *uuid.UUID `json:"company_id,omitempty" query:"company_id" param:"id"`
+	Email          string     `json:"email,omitempty" query:"email"`
+	Department     string     `json:"department,omitempty" query:"department"`
+	ContactType    string     `json:"contact_type,omitempty" query:"contact_type"`
+	PrimaryFor     string     `json:"primary_for,omitempty" query:"primary_for"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" query:"organization_id"`
+}
+
+// Criteria returns the filters as dafi criteria. ContactType selects the
+// contacts having the type, PrimaryFor the primary contacts for the type.
+func (f ContactFilter) Criteria() dafi.Criteria {
+	criteria := dafi.New()
+
+	if f.Search != "" {
+		criteria = criteria.AndGroup(
+			dafi.Filter{Field: "first_name", Operator: dafi.Contains, Value: f.Search, ChainingKey: dafi.Or},
+			dafi.Filter{Field: "last_name", Operator: dafi.Contains, Value: f.Search, ChainingKey: dafi.Or},
+			dafi.Filter{Field: "email", Operator: dafi.Contains, Value: f.Search},
+		)
+	}
+	if f.CompanyID != nil {
+		criteria = criteria.And("company_id", dafi.Equal, *f.CompanyID)
+	}
+	if f.Email != "" {
+		criteria = criteria.And("email", dafi.Equal, NormalizeEmail(f.Email))
+	}
+	if f.Department != "" {
+		criteria = criteria.And("department", dafi.Equal, f.Department)
+	}
+	if f.ContactType != "" {
+		criteria = criteria.And("contact_types", dafi.Equal, strings.ToUpper(f.ContactType))
+	}
+	if f.PrimaryFor != "" {
+		criteria = criteria.And("primary_types", dafi.Equal, strings.ToUpper(f.PrimaryFor))
+	}
+	if f.OrganizationID != nil {
+		criteria = criteria.And("organization_id", dafi.Equal, *f.OrganizationID)
+	}
+
+	return criteria
+}
+
+// ListContactsRequest adds pagination and sorting to ContactFilter
+type ListContactsRequest struct {
+	ContactFilter
+	Page      uint   `json:"page,omitempty" query:"page"`
+	PageSize  uint   `json:"page_size,omitempty" query:"page_size"`
+	SortBy    string `json:"sort_by,omitempty" query:"sort_by"`
+	SortOrder string `json:"sort_order,omitempty" query:"sort_order"`
+}
+
+func (r ListContactsRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"page":       valid.Int().Min(1),
+		"page_size":  valid.Int().Range(1, MaxPageSize),
+		"sort_by":    valid.Enum(ContactSortFields...),
+		"sort_order": valid.Enum("asc", "desc").CaseInsensitive(),
+	})
+}
+
+func (r ListContactsRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// Criteria returns the filters, page and sort as dafi criteria. The first
+// page of DefaultPageSize contacts is returned when no page is given.
+func (r ListContactsRequest) Criteria() dafi.Criteria {
+	page, pageSize := r.Page, r.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	criteria := r.ContactFilter.Criteria().Page(page).Limit(pageSize)
+
+	if r.SortBy != "" {
+		if strings.EqualFold(r.SortOrder, "desc") {
+			criteria = criteria.SortBy(r.SortBy, dafi.Desc)
+		} else {
+			criteria = criteria.SortBy(r.SortBy, dafi.Asc)
+		}
+	}
+
+	return criteria
+}
+
+// ExportContactsRequest exports the contacts of a company
+type ExportContactsRequest struct {
+	CompanyID uuid.UUID `param:"id"`
+}
+
+// ContactExport is a file with the contacts of a company
+type ContactExport struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,101 @@
+package presentation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/contacts/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// ContactIDRequest binds the contact id path parameter
+type ContactIDRequest struct {
+	ID uuid.UUID `param:"id"`
+}
+
+// ContactHandler exposes the contact use cases over HTTP. Its methods are
+// adapted to echo handlers with server.Handle.
+type ContactHandler struct {
+	usecase ports.ContactUseCase
+	tracer  trace.Tracer
+}
+
+func NewContactHandler(usecase ports.ContactUseCase) *ContactHandler {
+	return &ContactHandler{
+		usecase: usecase,
+		tracer:  otel.Tracer("contacts-handler"),
+	}
+}
+
+// CreateContact creates a contact of the company of the path
+func (h *ContactHandler) CreateContact(ctx context.Context, req entity.CreateContactRequest) (entity.Contact, error) {
+	ctx, span := h.tracer.Start(ctx, "ContactHandler.CreateContact")
+	defer span.End()
+
+	return h.usecase.CreateContact(ctx, req)
+}
+
+// GetContact gets a contact by its ID
+func (h *ContactHandler) GetContact(ctx context.Context, req ContactIDRequest) (entity.Contact, error) {
+	ctx, span := h.tracer.Start(ctx, "ContactHandler.GetContact")
+	defer span.End()
+
+	return h.usecase.GetContactByID(ctx, req.ID)
+}
+
+// ListContacts lists contacts, of the company of the path on the nested
+// route, with optional filtering, sorting, and pagination
+func (h *ContactHandler) ListContacts(ctx context.Context, req entity.ListContactsRequest) (types.List[entity.Contact], error) {
+	ctx, span := h.tracer.Start(ctx, "ContactHandler.ListContacts")
+	defer span.End()
+
+	return h.usecase.ListContacts(ctx, req.Criteria())
+}
+
+// CountContacts counts contacts with optional filtering
+func (h *ContactHandler) CountContacts(ctx context.Context, req entity.ContactFilter) (response.CountResponse, error) {
+	ctx, span := h.tracer.Start(ctx, "ContactHandler.CountContacts")
+	defer span.End()
+
+	count, err := h.usecase.CountContacts(ctx, req.Criteria())
+	if err != nil {
+		return response.CountResponse{}, err
+	}
+
+	return response.CountResponse{Count: count}, nil
+}
+
+// UpdateContact updates a contact
+func (h *ContactHandler) UpdateContact(ctx context.Context, req entity.UpdateContactRequest) (entity.Contact, error) {
+	ctx, span := h.tracer.Start(ctx, "ContactHandler.UpdateContact")
+	defer span.End()
+
+	return h.usecase.UpdateContact(ctx, req)
+}
+
+// DeleteContact soft deletes a contact
+func (h *ContactHandler) DeleteContact(ctx context.Context, req ContactIDRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "ContactHandler.DeleteContact")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.DeleteContact(ctx, req.ID)
+}
+
+// ExportContacts downloads the contacts of the company of the path as a
+// vCard file
+func (h *ContactHandler) ExportContacts(ctx context.Context, req entity.ExportContactsRequest) (server.Attachment, error) {
+	ctx, span := h.tracer.Start(ctx, "ContactHandler.ExportContacts")
+	defer span.End()
+
+	export, err := h.usecase.ExportContacts(ctx, req.CompanyID)
+	if err != nil {
+		return server.Attachment{}, err
+	}
+	return server.Attachment{Name: export.FileName, ContentType: export.ContentType, Data: export.Data}, nil
+}
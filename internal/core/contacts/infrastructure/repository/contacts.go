@@ -0,0 +1,383 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/contacts/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/sqlcraft"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// uniqueViolation is the PostgreSQL error code of unique violations
+const uniqueViolation = "23505"
+
+const contactColumns = "id, company_id, organization_id, first_name, last_name, email, phone, mobile, position, department, contact_types, primary_types, notes, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by"
+
+// contactColumnByDomainField maps the fields contacts can be filtered by
+// to their columns
+var contactColumnByDomainField = map[string]string{
+	"id":              "id",
+	"company_id":      "company_id",
+	"organization_id": "organization_id",
+	"first_name":      "first_name",
+	"last_name":       "last_name",
+	"email":           "email",
+	"position":        "position",
+	"department":      "department",
+	"created_at":      "created_at",
+	"created_by":      "created_by",
+	"updated_at":      "updated_at",
+	"updated_by":      "updated_by",
+	"deleted_at":      "deleted_at",
+}
+
+type ContactRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewContactRepository(db ports.Database) *ContactRepository {
+	return &ContactRepository{
+		db:     db,
+		tracer: otel.Tracer("contacts-repository"),
+	}
+}
+
+func (r *ContactRepository) WithTx(tx ports.Transaction) ports.ContactRepository {
+	return &ContactRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *ContactRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *ContactRepository) Create(ctx context.Context, contact entity.Contact) error {
+	ctx, span := r.tracer.Start(ctx, "ContactRepository.Create")
+	defer span.End()
+
+	if contact.OrganizationID != nil {
+		organizationID, err := tenant.Assign(ctx, *contact.OrganizationID)
+		if err != nil {
+			return err
+		}
+		contact.OrganizationID = &organizationID
+	}
+
+	query := `
+		INSERT INTO relationships.contacts (
+			id, company_id, organization_id, first_name, last_name, email, phone, mobile,
+			position, department, contact_types, primary_types, notes, created_at, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	_, err := r.getExecutor().Exec(ctx, query,
+		contact.ID,
+		contact.CompanyID,
+		contact.OrganizationID,
+		contact.FirstName,
+		contact.LastName,
+		contact.Email,
+		contact.Phone,
+		contact.Mobile,
+		contact.Position,
+		contact.Department,
+		contact.ContactTypes,
+		contact.PrimaryTypes,
+		contact.Notes,
+		contact.CreatedAt,
+		contact.CreatedBy,
+	)
+	if err != nil {
+		return contactConstraintError(err, "failed to create contact")
+	}
+
+	return nil
+}
+
+func (r *ContactRepository) CreateBulk(ctx context.Context, contacts types.List[entity.Contact]) error {
+	ctx, span := r.tracer.Start(ctx, "ContactRepository.CreateBulk")
+	defer span.End()
+
+	for _, contact := range contacts {
+		if err := r.Create(ctx, contact); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ContactRepository) Find(ctx context.Context, criteria dafi.Criteria) (entity.Contact, error) {
+	ctx, span := r.tracer.Start(ctx, "ContactRepository.Find")
+	defer span.End()
+
+	clause, err := contactWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return entity.Contact{}, err
+	}
+
+	query := "SELECT " + contactColumns + " FROM relationships.contacts" + clause.Sql + " LIMIT 1"
+
+	contact, err := scanContact(r.getExecutor().QueryRow(ctx, query, clause.Args...))
+	if err != nil {
+		return entity.Contact{}, fault.Wrap(err).Message("failed to find contact")
+	}
+
+	return contact, nil
+}
+
+func (r *ContactRepository) List(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Contact], error) {
+	ctx, span := r.tracer.Start(ctx, "ContactRepository.List")
+	defer span.End()
+
+	clause, err := contactWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := " ORDER BY first_name, last_name"
+	if !criteria.Sorts.IsZero() {
+		orderBy = sqlcraft.BuildOrderBy(criteria.Sorts)
+	}
+
+	query := "SELECT " + contactColumns + " FROM relationships.contacts" + clause.Sql + orderBy + sqlcraft.BuildPagination(criteria.Pagination)
+
+	rows, err := r.getExecutor().Query(ctx, query, clause.Args...)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list contacts")
+	}
+	defer rows.Close()
+
+	var contacts types.List[entity.Contact]
+	for rows.Next() {
+		contact, err := scanContact(rows)
+		if err != nil {
+			return nil, fault.Wrap(err).Message("failed to scan contact")
+		}
+		contacts = append(contacts, contact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fault.Wrap(err).Message("failed to list contacts")
+	}
+
+	return contacts, nil
+}
+
+func (r *ContactRepository) Update(ctx context.Context, contact entity.Contact, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "ContactRepository.Update")
+	defer span.End()
+
+	clause, err := contactWhere(ctx, 12, filters)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE relationships.contacts SET first_name = $1, last_name = $2, email = $3, phone = $4, mobile = $5,
+		position = $6, department = $7, contact_types = $8, primary_types = $9, notes = $10, updated_at = $11, updated_by = $12` + clause.Sql
+	args := append([]any{
+		contact.FirstName,
+		contact.LastName,
+		contact.Email,
+		contact.Phone,
+		contact.Mobile,
+		contact.Position,
+		contact.Department,
+		contact.ContactTypes,
+		contact.PrimaryTypes,
+		contact.Notes,
+		contact.UpdatedAt,
+		contact.UpdatedBy,
+	}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return contactConstraintError(err, "failed to update contact")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("contact not found")).Code(fault.NotFound).Message("contact not found")
+	}
+
+	return nil
+}
+
+// Delete soft deletes the contacts matching the filters
+func (r *ContactRepository) Delete(ctx context.Context, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "ContactRepository.Delete")
+	defer span.End()
+
+	clause, err := contactWhere(ctx, 2, filters)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE relationships.contacts SET deleted_at = $1, deleted_by = $2" + clause.Sql
+	args := append([]any{time.Now(), auth.UserIDFrom(ctx)}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to delete contact")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("contact not found")).Code(fault.NotFound).Message("contact not found")
+	}
+
+	return nil
+}
+
+// ReleasePrimary keeps a single primary contact per type: the other
+// contacts of the company in the organization of the contact lose the
+// primary types of the contact
+func (r *ContactRepository) ReleasePrimary(ctx context.Context, contact entity.Contact) error {
+	ctx, span := r.tracer.Start(ctx, "ContactRepository.ReleasePrimary")
+	defer span.End()
+
+	if len(contact.PrimaryTypes) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE relationships.contacts
+		SET primary_types = primary_types - $4::text[], updated_at = $5, updated_by = $6
+		WHERE company_id = $1
+			AND organization_id IS NOT DISTINCT FROM $2
+			AND id <> $3
+			AND primary_types ?| $4::text[]
+			AND deleted_at IS NULL
+	`
+
+	_, err := r.getExecutor().Exec(ctx, query,
+		contact.CompanyID,
+		contact.OrganizationID,
+		contact.ID,
+		contact.PrimaryTypes,
+		time.Now(),
+		auth.UserIDFrom(ctx),
+	)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to release primary contact types")
+	}
+
+	return nil
+}
+
+func (r *ContactRepository) Exists(ctx context.Context, criteria dafi.Criteria) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "ContactRepository.Exists")
+	defer span.End()
+
+	clause, err := contactWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := r.getExecutor().QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM relationships.contacts"+clause.Sql+")", clause.Args...).Scan(&exists); err != nil {
+		return false, fault.Wrap(err).Message("failed to check if contact exists")
+	}
+
+	return exists, nil
+}
+
+func (r *ContactRepository) Count(ctx context.Context, criteria dafi.Criteria) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "ContactRepository.Count")
+	defer span.End()
+
+	clause, err := contactWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := r.getExecutor().QueryRow(ctx, "SELECT COUNT(*) FROM relationships.contacts"+clause.Sql, clause.Args...).Scan(&count); err != nil {
+		return 0, fault.Wrap(err).Message("failed to count contacts")
+	}
+
+	return count, nil
+}
+
+// contactWhere builds the WHERE clause of filters on contacts, restricted
+// to the organizations of the tenant of ctx. Filters on the contact_types
+// and primary_types arrays become containment conditions, which the GIN
+// index on contact_types serves. Deleted contacts are left out.
+func contactWhere(ctx context.Context, initialArgCount int, filters dafi.Filters) (sqlcraft.Result, error) {
+	filters, err := tenant.Restrict(ctx, filters, "organization_id")
+	if err != nil {
+		return sqlcraft.Result{}, err
+	}
+
+	filters = slices.Clone(filters)
+	for i, filter := range filters {
+		switch filter.Field {
+		case "contact_types", "primary_types":
+			if filter.Operator != dafi.Equal && filter.Operator != "" {
+				return sqlcraft.Result{}, fault.New("unsupported contact type filter").Code(fault.BadRequest).With("operator", filter.Operator)
+			}
+			filters[i].Field = filter.Field + " @> jsonb_build_array(?::text)"
+			filters[i].Operator = dafi.Default
+		}
+	}
+	filters = filters.Grouped().And("deleted_at", dafi.IsNull, nil)
+
+	return sqlcraft.WhereSafe(initialArgCount, contactColumnByDomainField, filters...)
+}
+
+// contactConstraintError reports the emails taken by another contact of
+// the company, which the use case checks first but concurrent requests
+// may still race for, as fault.Conflict errors
+func contactConstraintError(err error, message string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fault.Wrap(err).Code(fault.Conflict).Message("a contact with this email already exists in the company")
+	}
+	return fault.Wrap(err).Message(message)
+}
+
+func scanContact(row pgx.Row) (entity.Contact, error) {
+	var contact entity.Contact
+	err := row.Scan(
+		&contact.ID,
+		&contact.CompanyID,
+		&contact.OrganizationID,
+		&contact.FirstName,
+		&contact.LastName,
+		&contact.Email,
+		&contact.Phone,
+		&contact.Mobile,
+		&contact.Position,
+		&contact.Department,
+		&contact.ContactTypes,
+		&contact.PrimaryTypes,
+		&contact.Notes,
+		&contact.CreatedAt,
+		&contact.CreatedBy,
+		&contact.UpdatedAt,
+		&contact.UpdatedBy,
+		&contact.DeletedAt,
+		&contact.DeletedBy,
+	)
+	return contact, err
+}
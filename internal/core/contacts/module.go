@@ -0,0 +1,24 @@
+package contacts
+
+import (
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/core/contacts/application"
+	"api.system.soluciones-cloud.com/internal/core/contacts/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/core/contacts/infrastructure/repository"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			repository.NewContactRepository,
+			fx.As(new(ports.ContactRepository)),
+		),
+		fx.Annotate(
+			application.NewContactUseCase,
+			fx.As(new(ports.ContactUseCase)),
+		),
+		presentation.NewContactHandler,
+	),
+)
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/files/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/blob"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+// organizationPrefix starts the keys of files, followed by the
+// organization id and the file name
+const organizationPrefix = "organizations/"
+
+type Config struct {
+	// Limits bound uploaded files
+	Limits blob.Limits
+	// URLExpiry bounds how long signed URLs work
+	URLExpiry time.Duration
+}
+
+type FileUseCase struct {
+	blobs  ports.BlobStore
+	config Config
+	tracer trace.Tracer
+}
+
+func NewFileUseCase(blobs ports.BlobStore, config Config) *FileUseCase {
+	return &FileUseCase{
+		blobs:  blobs,
+		config: config,
+		tracer: otel.Tracer("files-usecase"),
+	}
+}
+
+// Upload stores the file under a random name, so uploads never replace
+// each other. The extension follows the sniffed content type.
+func (u *FileUseCase) Upload(ctx context.Context, body io.Reader) (entity.StoredFile, error) {
+	ctx, span := u.tracer.Start(ctx, "Upload")
+	defer span.End()
+
+	organizationID, err := tenant.Assign(ctx, uuid.Nil)
+	if err != nil {
+		return entity.StoredFile{}, err
+	}
+	if organizationID == uuid.Nil {
+		return entity.StoredFile{}, fault.New("organization is required").Code(fault.BadRequest)
+	}
+
+	file, err := blob.Read(body, u.config.Limits)
+	if err != nil {
+		return entity.StoredFile{}, err
+	}
+
+	key := organizationPrefix + organizationID.String() + "/" + uuid.NewString() + blob.Extension(file.ContentType)
+	stored := file.Blob(key)
+	if err := u.blobs.Put(ctx, stored, file.Reader()); err != nil {
+		return entity.StoredFile{}, fault.Wrap(err).Message("failed to store file")
+	}
+
+	signed, err := u.sign(ctx, key)
+	if err != nil {
+		return entity.StoredFile{}, err
+	}
+
+	return entity.StoredFile{
+		Key:         key,
+		ContentType: stored.ContentType,
+		Size:        stored.Size,
+		URL:         signed.URL,
+		ExpiresAt:   signed.ExpiresAt,
+	}, nil
+}
+
+func (u *FileUseCase) URL(ctx context.Context, key string) (entity.FileURL, error) {
+	ctx, span := u.tracer.Start(ctx, "URL")
+	defer span.End()
+
+	notFound := fault.New("file not found").Code(fault.NotFound).With("key", key)
+
+	organization, _, ok := strings.Cut(strings.TrimPrefix(key, organizationPrefix), "/")
+	if !strings.HasPrefix(key, organizationPrefix) || !ok {
+		return entity.FileURL{}, notFound
+	}
+	organizationID, err := uuid.Parse(organization)
+	if err != nil {
+		return entity.FileURL{}, notFound
+	}
+	if _, err := tenant.Assign(ctx, organizationID); err != nil {
+		return entity.FileURL{}, notFound
+	}
+
+	return u.sign(ctx, key)
+}
+
+func (u *FileUseCase) sign(ctx context.Context, key string) (entity.FileURL, error) {
+	expiresAt := time.Now().Add(u.config.URLExpiry)
+	signed, err := u.blobs.SignedURL(ctx, key, u.config.URLExpiry)
+	if err != nil {
+		return entity.FileURL{}, fault.Wrap(err).Message("failed to sign file URL")
+	}
+	return entity.FileURL{Key: key, URL: signed, ExpiresAt: expiresAt}, nil
+}
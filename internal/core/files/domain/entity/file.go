@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"mime/multipart"
+	"time"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// StoredFile is an uploaded file of an organization. Clients keep the key,
+// e.g. in file_url columns, and exchange it for a signed URL to download
+// the file.
+type StoredFile struct {
+	Key         string `json:"key"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// URL downloads the file without credentials until ExpiresAt
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FileURL is a signed download URL of a stored file.
+type FileURL struct {
+	Key       string    `json:"key"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UploadFileRequest is the multipart/form-data body of a file upload.
+type UploadFileRequest struct {
+	File *multipart.FileHeader `json:"-" form:"file"`
+}
+
+func (r UploadFileRequest) Validate() error {
+	if r.File == nil {
+		return &valid.ValidationError{Path: "file", Message: "file is required", Code: "required"}
+	}
+	return nil
+}
+
+// FileURLRequest binds the key of the file to sign a URL for.
+type FileURLRequest struct {
+	Key string `query:"key"`
+}
+
+func (r FileURLRequest) Validate() error {
+	if r.Key == "" {
+		return &valid.ValidationError{Path: "key", Message: "key is required", Code: "required"}
+	}
+	return nil
+}
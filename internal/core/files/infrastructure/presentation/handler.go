@@ -0,0 +1,48 @@
+package presentation
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/files/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+// FileHandler exposes the file use cases over HTTP. Its methods are
+// adapted to echo handlers with server.Handle.
+type FileHandler struct {
+	usecase ports.FileUseCase
+	tracer  trace.Tracer
+}
+
+func NewFileHandler(usecase ports.FileUseCase) *FileHandler {
+	return &FileHandler{
+		usecase: usecase,
+		tracer:  otel.Tracer("files-handler"),
+	}
+}
+
+// UploadFile stores the uploaded file
+func (h *FileHandler) UploadFile(ctx context.Context, req entity.UploadFileRequest) (entity.StoredFile, error) {
+	ctx, span := h.tracer.Start(ctx, "FileHandler.UploadFile")
+	defer span.End()
+
+	file, err := req.File.Open()
+	if err != nil {
+		return entity.StoredFile{}, fault.Wrap(err).Code(fault.BadRequest).Message("failed to read file")
+	}
+	defer file.Close()
+
+	return h.usecase.Upload(ctx, file)
+}
+
+// GetFileURL signs a download URL of a stored file
+func (h *FileHandler) GetFileURL(ctx context.Context, req entity.FileURLRequest) (entity.FileURL, error) {
+	ctx, span := h.tracer.Start(ctx, "FileHandler.GetFileURL")
+	defer span.End()
+
+	return h.usecase.URL(ctx, req.Key)
+}
@@ -0,0 +1,33 @@
+package files
+
+import (
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/core/files/application"
+	"api.system.soluciones-cloud.com/internal/core/files/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/shared/blob"
+	"api.system.soluciones-cloud.com/internal/shared/localconfig"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Options(
+	fx.Provide(
+		newConfig,
+		fx.Annotate(
+			application.NewFileUseCase,
+			fx.As(new(ports.FileUseCase)),
+		),
+		presentation.NewFileHandler,
+	),
+)
+
+// contentTypes are the accepted uploads: PDFs, images, plain text and
+// ZIP archives, which Office documents are
+var contentTypes = []string{"application/pdf", "application/zip", "image/gif", "image/jpeg", "image/png", "image/webp", "text/plain"}
+
+func newConfig(config *localconfig.Config) application.Config {
+	return application.Config{
+		Limits:    blob.Limits{MaxSize: config.Storage.MaxUploadSize, ContentTypes: contentTypes},
+		URLExpiry: config.Storage.URLExpiry,
+	}
+}
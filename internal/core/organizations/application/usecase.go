@@ -0,0 +1,514 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/guregu/null.v4"
+
+	auditentity "api.system.soluciones-cloud.com/internal/core/audit/domain/entity"
+	authentity "api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/core/organizations/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+var (
+	// organizationVisibility restricts OWN scopes to the organizations
+	// created by the caller and ORG scopes to the caller's organizations
+	organizationVisibility = rbac.Visibility{Owner: "created_by", Organization: "id"}
+	// memberVisibility restricts OWN scopes to the members added by the
+	// caller and ORG scopes to the members of the caller's organizations
+	memberVisibility = rbac.Visibility{Owner: "created_by", Organization: "organization_id"}
+)
+
+// Audited entity types
+const (
+	auditOrganization = "organization"
+	auditMember       = "member"
+)
+
+// OrganizationUseCase manages organizations and their members. Every
+// change is recorded in the audit log in the same transaction, and the
+// cached permissions of the affected users are invalidated once it is
+// committed, since memberships decide the organizations users may act on.
+type OrganizationUseCase struct {
+	uow           ports.UnitOfWork
+	organizations ports.OrganizationRepository
+	members       ports.MemberRepository
+	credentials   ports.CredentialRepository
+	audit         ports.AuditRepository
+	authorizer    ports.Authorizer
+	mailer        ports.Mailer
+	templates     ports.EmailTemplates
+	now           func() time.Time
+	tracer        trace.Tracer
+}
+
+func NewOrganizationUseCase(
+	uow ports.UnitOfWork,
+	organizations ports.OrganizationRepository,
+	members ports.MemberRepository,
+	credentials ports.CredentialRepository,
+	audit ports.AuditRepository,
+	authorizer ports.Authorizer,
+	mailer ports.Mailer,
+	templates ports.EmailTemplates,
+) *OrganizationUseCase {
+	return &OrganizationUseCase{
+		uow:           uow,
+		organizations: organizations,
+		members:       members,
+		credentials:   credentials,
+		audit:         audit,
+		authorizer:    authorizer,
+		mailer:        mailer,
+		templates:     templates,
+		now:           time.Now,
+		tracer:        otel.Tracer("organizations-usecase"),
+	}
+}
+
+func (u *OrganizationUseCase) CreateOrganization(ctx context.Context, req entity.CreateOrganizationRequest) (entity.Organization, error) {
+	ctx, span := u.tracer.Start(ctx, "CreateOrganization")
+	defer span.End()
+
+	organization := entity.Organization{
+		ID:               uuid.New(),
+		Name:             req.Name,
+		Code:             null.NewString(req.Code, req.Code != ""),
+		Description:      null.NewString(req.Description, req.Description != ""),
+		OrganizationType: req.OrganizationType,
+		Status:           req.Status,
+		IsActive:         true,
+		CreatedAt:        u.now(),
+		CreatedBy:        auth.ActorID(ctx),
+	}
+	if organization.OrganizationType == "" {
+		organization.OrganizationType = entity.DefaultOrganizationType
+	}
+	if organization.Status == "" {
+		organization.Status = entity.DefaultOrganizationStatus
+	}
+
+	err := ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.organizations.WithTx(tx).Create(ctx, organization); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, "organization.created", auditOrganization, organization.ID, organization.ID, map[string]any{
+			"name":              organization.Name,
+			"code":              organization.Code,
+			"organization_type": organization.OrganizationType,
+		})
+	})
+	if err != nil {
+		return entity.Organization{}, fault.Wrap(err).Message("failed to create organization")
+	}
+
+	return organization, nil
+}
+
+func (u *OrganizationUseCase) GetOrganizationByID(ctx context.Context, id uuid.UUID) (entity.Organization, error) {
+	ctx, span := u.tracer.Start(ctx, "GetOrganizationByID")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, dafi.Where("id", dafi.Equal, id), organizationVisibility)
+	if err != nil {
+		return entity.Organization{}, err
+	}
+
+	organization, err := u.organizations.Find(ctx, criteria)
+	if err != nil {
+		return entity.Organization{}, fault.Wrap(err).Message("failed to get organization by ID")
+	}
+
+	return organization, nil
+}
+
+func (u *OrganizationUseCase) ListOrganizations(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Organization], error) {
+	ctx, span := u.tracer.Start(ctx, "ListOrganizations")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, organizationVisibility)
+	if err != nil {
+		return types.List[entity.Organization]{}, err
+	}
+
+	organizations, err := u.organizations.List(ctx, criteria)
+	if err != nil {
+		return types.List[entity.Organization]{}, fault.Wrap(err).Message("failed to list organizations")
+	}
+
+	return organizations, nil
+}
+
+func (u *OrganizationUseCase) CountOrganizations(ctx context.Context, criteria dafi.Criteria) (int64, error) {
+	ctx, span := u.tracer.Start(ctx, "CountOrganizations")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, organizationVisibility)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := u.organizations.Count(ctx, criteria)
+	if err != nil {
+		return 0, fault.Wrap(err).Message("failed to count organizations")
+	}
+
+	return count, nil
+}
+
+func (u *OrganizationUseCase) UpdateOrganization(ctx context.Context, req entity.UpdateOrganizationRequest) (entity.Organization, error) {
+	ctx, span := u.tracer.Start(ctx, "UpdateOrganization")
+	defer span.End()
+
+	organization, err := u.GetOrganizationByID(ctx, req.ID)
+	if err != nil {
+		return entity.Organization{}, err
+	}
+
+	changes := map[string]any{}
+	if req.Name.Valid && req.Name.String != organization.Name {
+		organization.Name = req.Name.String
+		changes["name"] = organization.Name
+	}
+	if req.Code.Valid && req.Code.String != organization.Code.String {
+		organization.Code = null.NewString(req.Code.String, req.Code.String != "")
+		changes["code"] = organization.Code
+	}
+	if req.Description.Valid && req.Description.String != organization.Description.String {
+		organization.Description = null.NewString(req.Description.String, req.Description.String != "")
+		changes["description"] = organization.Description
+	}
+	if req.OrganizationType.Valid && req.OrganizationType.String != organization.OrganizationType {
+		organization.OrganizationType = req.OrganizationType.String
+		changes["organization_type"] = organization.OrganizationType
+	}
+	if req.Status.Valid && req.Status.String != organization.Status {
+		organization.Status = req.Status.String
+		changes["status"] = organization.Status
+	}
+	if req.IsActive.Valid && req.IsActive.Bool != organization.IsActive {
+		if organization.IsRootOrganization {
+			return entity.Organization{}, fault.New("the root organization cannot be deactivated").Code(fault.Forbidden)
+		}
+		organization.IsActive = req.IsActive.Bool
+		changes["is_active"] = organization.IsActive
+	}
+	if len(changes) == 0 {
+		return organization, nil
+	}
+
+	organization.UpdatedAt = null.TimeFrom(u.now())
+	organization.UpdatedBy = auth.ActorID(ctx)
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.organizations.WithTx(tx).Update(ctx, organization, dafi.FilterBy("id", dafi.Equal, organization.ID)...); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, "organization.updated", auditOrganization, organization.ID, organization.ID, changes)
+	})
+	if err != nil {
+		return entity.Organization{}, fault.Wrap(err).Message("failed to update organization")
+	}
+
+	// Inactive organizations grant no access to any of their members
+	if _, ok := changes["is_active"]; ok {
+		u.authorizer.InvalidateAll()
+	}
+
+	return organization, nil
+}
+
+func (u *OrganizationUseCase) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	ctx, span := u.tracer.Start(ctx, "DeleteOrganization")
+	defer span.End()
+
+	organization, err := u.GetOrganizationByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if organization.IsRootOrganization {
+		return fault.New("the root organization cannot be deleted").Code(fault.Forbidden)
+	}
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.organizations.WithTx(tx).Delete(ctx, dafi.FilterBy("id", dafi.Equal, organization.ID)...); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, "organization.deleted", auditOrganization, organization.ID, organization.ID, map[string]any{
+			"name": organization.Name,
+			"code": organization.Code,
+		})
+	})
+	if err != nil {
+		return fault.Wrap(err).Message("failed to delete organization")
+	}
+
+	u.authorizer.InvalidateAll()
+
+	return nil
+}
+
+func (u *OrganizationUseCase) ListMembers(ctx context.Context, organizationID uuid.UUID, criteria dafi.Criteria) (types.List[entity.Member], error) {
+	ctx, span := u.tracer.Start(ctx, "ListMembers")
+	defer span.End()
+
+	if _, err := u.GetOrganizationByID(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	criteria, err := rbac.RestrictCriteria(ctx, criteria.And("organization_id", dafi.Equal, organizationID), memberVisibility)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := u.members.List(ctx, criteria)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list members")
+	}
+
+	return members, nil
+}
+
+func (u *OrganizationUseCase) AddMember(ctx context.Context, req entity.AddMemberRequest) (entity.Member, error) {
+	ctx, span := u.tracer.Start(ctx, "AddMember")
+	defer span.End()
+
+	organization, err := u.GetOrganizationByID(ctx, req.OrganizationID)
+	if err != nil {
+		return entity.Member{}, err
+	}
+
+	return u.addMember(ctx, organization, req.UserID, req.CustomerID, req.Relationship, "member.added")
+}
+
+// InviteMember adds the user registered with the email of req and lets
+// them know by email. Undelivered emails do not fail the request.
+func (u *OrganizationUseCase) InviteMember(ctx context.Context, req entity.InviteMemberRequest) (entity.Member, error) {
+	ctx, span := u.tracer.Start(ctx, "InviteMember")
+	defer span.End()
+
+	organization, err := u.GetOrganizationByID(ctx, req.OrganizationID)
+	if err != nil {
+		return entity.Member{}, err
+	}
+
+	credential, err := u.credentials.FindByEmail(ctx, authentity.NormalizeEmail(req.Email))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Member{}, fault.Wrap(err).Code(fault.NotFound).Message("no user is registered with this email")
+	}
+	if err != nil {
+		return entity.Member{}, fault.Wrap(err).Message("failed to find credential")
+	}
+
+	member, err := u.addMember(ctx, organization, credential.UserID, req.CustomerID, req.Relationship, "member.invited")
+	if err != nil {
+		return entity.Member{}, err
+	}
+
+	err = u.sendEmail(ctx, credential.Email, "organization_invitation", map[string]any{
+		"Name":         member.FirstName,
+		"Organization": organization.Name,
+		"Relationship": member.Relationship,
+	})
+	if err != nil {
+		recordError(span, err)
+	}
+
+	return member, nil
+}
+
+func (u *OrganizationUseCase) UpdateMember(ctx context.Context, req entity.UpdateMemberRequest) (entity.Member, error) {
+	ctx, span := u.tracer.Start(ctx, "UpdateMember")
+	defer span.End()
+
+	member, err := u.findMember(ctx, req.OrganizationID, req.MemberID)
+	if err != nil {
+		return entity.Member{}, err
+	}
+
+	changes := map[string]any{}
+	if req.Relationship.Valid && req.Relationship.String != member.Relationship {
+		member.Relationship = req.Relationship.String
+		changes["relationship"] = member.Relationship
+	}
+	if req.IsActive.Valid && req.IsActive.Bool != member.IsActive {
+		member.IsActive = req.IsActive.Bool
+		changes["is_active"] = member.IsActive
+	}
+	if len(changes) == 0 {
+		return member, nil
+	}
+
+	member.UpdatedAt = null.TimeFrom(u.now())
+	member.UpdatedBy = auth.ActorID(ctx)
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.members.WithTx(tx).Update(ctx, member, dafi.FilterBy("id", dafi.Equal, member.ID)...); err != nil {
+			return err
+		}
+		changes["user_id"] = member.UserID
+		return u.record(ctx, tx, "member.updated", auditMember, member.ID, member.OrganizationID, changes)
+	})
+	if err != nil {
+		return entity.Member{}, fault.Wrap(err).Message("failed to update member")
+	}
+
+	u.authorizer.Invalidate(member.UserID)
+
+	return member, nil
+}
+
+func (u *OrganizationUseCase) RemoveMember(ctx context.Context, req entity.MemberRequest) error {
+	ctx, span := u.tracer.Start(ctx, "RemoveMember")
+	defer span.End()
+
+	member, err := u.findMember(ctx, req.OrganizationID, req.MemberID)
+	if err != nil {
+		return err
+	}
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.members.WithTx(tx).Remove(ctx, dafi.FilterBy("id", dafi.Equal, member.ID)...); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, "member.removed", auditMember, member.ID, member.OrganizationID, map[string]any{
+			"user_id":      member.UserID,
+			"customer_id":  member.CustomerID,
+			"relationship": member.Relationship,
+		})
+	})
+	if err != nil {
+		return fault.Wrap(err).Message("failed to remove member")
+	}
+
+	u.authorizer.Invalidate(member.UserID)
+
+	return nil
+}
+
+func (u *OrganizationUseCase) MyOrganizations(ctx context.Context) (types.List[entity.Membership], error) {
+	ctx, span := u.tracer.Start(ctx, "MyOrganizations")
+	defer span.End()
+
+	userID := auth.UserIDFrom(ctx)
+	if !userID.Valid {
+		return nil, fault.New("authentication required").Code(fault.Unauthorized)
+	}
+
+	memberships, err := u.members.Memberships(ctx, userID.UUID)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list organizations of the user")
+	}
+
+	return memberships, nil
+}
+
+// addMember adds the user to organization and returns the member with
+// the name and email of the user
+func (u *OrganizationUseCase) addMember(ctx context.Context, organization entity.Organization, userID uuid.UUID, customerID *uuid.UUID, relationship, action string) (entity.Member, error) {
+	if relationship == "" {
+		relationship = entity.DefaultRelationship
+	}
+
+	member := entity.Member{
+		ID:             uuid.New(),
+		OrganizationID: organization.ID,
+		UserID:         userID,
+		CustomerID:     customerID,
+		Relationship:   relationship,
+		IsActive:       true,
+		CreatedAt:      u.now(),
+		CreatedBy:      auth.ActorID(ctx),
+	}
+
+	err := ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.members.WithTx(tx).Add(ctx, member); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, action, auditMember, member.ID, organization.ID, map[string]any{
+			"user_id":      member.UserID,
+			"customer_id":  member.CustomerID,
+			"relationship": member.Relationship,
+		})
+	})
+	if err != nil {
+		return entity.Member{}, fault.Wrap(err).Message("failed to add member")
+	}
+
+	u.authorizer.Invalidate(member.UserID)
+
+	added, err := u.members.Find(ctx, dafi.Where("id", dafi.Equal, member.ID))
+	if err != nil {
+		return entity.Member{}, fault.Wrap(err).Message("failed to find member")
+	}
+
+	return added, nil
+}
+
+// findMember returns a member of a visible organization
+func (u *OrganizationUseCase) findMember(ctx context.Context, organizationID, memberID uuid.UUID) (entity.Member, error) {
+	if _, err := u.GetOrganizationByID(ctx, organizationID); err != nil {
+		return entity.Member{}, err
+	}
+
+	criteria, err := rbac.RestrictCriteria(ctx, dafi.Where("id", dafi.Equal, memberID).And("organization_id", dafi.Equal, organizationID), memberVisibility)
+	if err != nil {
+		return entity.Member{}, err
+	}
+
+	member, err := u.members.Find(ctx, criteria)
+	if err != nil {
+		return entity.Member{}, fault.Wrap(err).Message("failed to find member")
+	}
+
+	return member, nil
+}
+
+func (u *OrganizationUseCase) sendEmail(ctx context.Context, to, template string, data map[string]any) error {
+	email, err := u.templates.Render(i18n.FromContext(ctx), template, data)
+	if err != nil {
+		return err
+	}
+	email.To = []string{to}
+
+	if err := u.mailer.Send(ctx, email); err != nil {
+		return fault.Wrap(err).Message("failed to send email").With("template", template)
+	}
+	return nil
+}
+
+// record appends a change made by the caller to the audit log
+func (u *OrganizationUseCase) record(ctx context.Context, tx ports.Transaction, action, entityType string, entityID, organizationID uuid.UUID, changes map[string]any) error {
+	return u.audit.WithTx(tx).Record(ctx, auditentity.Entry{
+		ID:             uuid.New(),
+		OrganizationID: uuid.NullUUID{UUID: organizationID, Valid: true},
+		ActorID:        auth.UserIDFrom(ctx),
+		Action:         action,
+		EntityType:     entityType,
+		EntityID:       entityID,
+		Changes:        changes,
+		CreatedAt:      u.now(),
+	})
+}
+
+// recordError marks the span as failed for errors that do not fail the
+// request, such as undelivered emails.
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
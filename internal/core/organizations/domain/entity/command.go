@@ -0,0 +1,145 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// CreateOrganizationRequest creates an organization. OrganizationType
+// defaults to CUSTOMER and Status to ACTIVE.
+type CreateOrganizationRequest struct {
+	Name             string `json:"name"`
+	Code             string `json:"code,omitempty"`
+	Description      string `json:"description,omitempty"`
+	OrganizationType string `json:"organization_type,omitempty"`
+	Status           string `json:"status,omitempty"`
+}
+
+func (r CreateOrganizationRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"name":              valid.String().MaxLength(200).Required(),
+		"code":              valid.String().MaxLength(100),
+		"description":       valid.String(),
+		"organization_type": valid.Enum(OrganizationTypes...),
+		"status":            valid.Enum(OrganizationStatuses...),
+	})
+}
+
+func (r CreateOrganizationRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+type UpdateOrganizationRequest struct {
+	ID               uuid.UUID   `json:"-" param:"id"`
+	Name             null.String `json:"name,omitempty"`
+	Code             null.String `json:"code,omitempty"`
+	Description      null.String `json:"description,omitempty"`
+	OrganizationType null.String `json:"organization_type,omitempty"`
+	Status           null.String `json:"status,omitempty"`
+	IsActive         null.Bool   `json:"is_active,omitempty"`
+}
+
+func (r UpdateOrganizationRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"name":              valid.String().MaxLength(200),
+		"code":              valid.String().MaxLength(100),
+		"description":       valid.String(),
+		"organization_type": valid.Enum(OrganizationTypes...),
+		"status":            valid.Enum(OrganizationStatuses...),
+		"is_active":         valid.Bool(),
+	})
+}
+
+func (r UpdateOrganizationRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// AddMemberRequest adds a user to an organization, optionally on behalf
+// of one of its customers. Relationship defaults to EMPLOYEE.
+type AddMemberRequest struct {
+	OrganizationID uuid.UUID  `json:"-" param:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	CustomerID     *uuid.UUID `json:"customer_id,omitempty"`
+	Relationship   string     `json:"relationship,omitempty"`
+}
+
+func (r AddMemberRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"user_id":      valid.String().UUID().Required(),
+		"customer_id":  valid.String().UUID(),
+		"relationship": valid.Enum(Relationships...),
+	})
+}
+
+func (r AddMemberRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// InviteMemberRequest adds the user registered with Email to an
+// organization and lets them know by email
+type InviteMemberRequest struct {
+	OrganizationID uuid.UUID  `json:"-" param:"id"`
+	Email          string     `json:"email"`
+	CustomerID     *uuid.UUID `json:"customer_id,omitempty"`
+	Relationship   string     `json:"relationship,omitempty"`
+}
+
+func (r InviteMemberRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"email":        valid.String().Email().MaxLength(255).Required(),
+		"customer_id":  valid.String().UUID(),
+		"relationship": valid.Enum(Relationships...),
+	})
+}
+
+func (r InviteMemberRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// UpdateMemberRequest changes the relationship of a member, or
+// deactivates and reactivates the membership
+type UpdateMemberRequest struct {
+	OrganizationID uuid.UUID   `json:"-" param:"id"`
+	MemberID       uuid.UUID   `json:"-" param:"member_id"`
+	Relationship   null.String `json:"relationship,omitempty"`
+	IsActive       null.Bool   `json:"is_active,omitempty"`
+}
+
+func (r UpdateMemberRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"relationship": valid.Enum(Relationships...),
+		"is_active":    valid.Bool(),
+	})
+}
+
+func (r UpdateMemberRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// MemberRequest selects a member of an organization
+type MemberRequest struct {
+	OrganizationID uuid.UUID `param:"id"`
+	MemberID       uuid.UUID `param:"member_id"`
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Organization types and statuses, see the organization_types and
+// organization_statuses catalogs
+var (
+	OrganizationTypes    = []string{"CUSTOMER", "SUPPLIER", "PARTNER", "INTERNAL"}
+	OrganizationStatuses = []string{"ACTIVE", "INACTIVE", "SUSPENDED", "PENDING"}
+)
+
+// Relationships of members with their organization, see the
+// user_customer_relationships catalog
+var Relationships = []string{"EMPLOYEE", "ADMIN", "CONTACT", "OWNER"}
+
+// Defaults of new organizations and members
+const (
+	DefaultOrganizationType   = "CUSTOMER"
+	DefaultOrganizationStatus = "ACTIVE"
+	DefaultRelationship       = "EMPLOYEE"
+)
+
+// Organization is a tenant: the business data of the API belongs to
+// organizations and users reach it through their memberships. The root
+// organization is seeded by migrations and cannot be deleted or
+// deactivated.
+type Organization struct {
+	ID                 uuid.UUID   `json:"id" db:"id"`
+	Name               string      `json:"name" db:"name"`
+	Code               null.String `json:"code" db:"code"`
+	Description        null.String `json:"description" db:"description"`
+	OrganizationType   string      `json:"organization_type" db:"organization_type"`
+	Status             string      `json:"status" db:"status"`
+	IsActive           bool        `json:"is_active" db:"is_active"`
+	IsRootOrganization bool        `json:"is_root_organization" db:"is_root_organization"`
+	CreatedAt          time.Time   `json:"created_at" db:"created_at"`
+	CreatedBy          *uuid.UUID  `json:"created_by" db:"created_by"`
+	UpdatedAt          null.Time   `json:"updated_at" db:"updated_at"`
+	UpdatedBy          *uuid.UUID  `json:"updated_by" db:"updated_by"`
+}
+
+// Member is the membership of a user in an organization, optionally on
+// behalf of one of its customers. Inactive memberships grant no access.
+type Member struct {
+	ID             uuid.UUID   `json:"id" db:"id"`
+	OrganizationID uuid.UUID   `json:"organization_id" db:"organization_id"`
+	UserID         uuid.UUID   `json:"user_id" db:"user_id"`
+	CustomerID     *uuid.UUID  `json:"customer_id" db:"customer_id"`
+	Relationship   string      `json:"relationship" db:"relationship"`
+	IsActive       bool        `json:"is_active" db:"is_active"`
+	FirstName      string      `json:"first_name" db:"first_name"`
+	LastName       null.String `json:"last_name" db:"last_name"`
+	Email          null.String `json:"email" db:"email"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+	CreatedBy      *uuid.UUID  `json:"created_by" db:"created_by"`
+	UpdatedAt      null.Time   `json:"updated_at" db:"updated_at"`
+	UpdatedBy      *uuid.UUID  `json:"updated_by" db:"updated_by"`
+}
+
+// Membership is an organization the caller may act on, as listed by the
+// organization switcher
+type Membership struct {
+	OrganizationID     uuid.UUID   `json:"organization_id"`
+	Name               string      `json:"name"`
+	Code               null.String `json:"code"`
+	OrganizationType   string      `json:"organization_type"`
+	IsRootOrganization bool        `json:"is_root_organization"`
+	MemberID           uuid.UUID   `json:"member_id"`
+	CustomerID         *uuid.UUID  `json:"customer_id"`
+	Relationship       string      `json:"relationship"`
+}
@@ -0,0 +1,169 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// OrganizationSortFields are the fields organizations can be sorted by
+var OrganizationSortFields = []string{"id", "name", "code", "organization_type", "status", "is_active", "created_at", "updated_at"}
+
+// MemberSortFields are the fields members can be sorted by
+var MemberSortFields = []string{"relationship", "is_active", "first_name", "last_name", "created_at"}
+
+// OrganizationFilter holds the filters accepted by the list and count
+// endpoints
+type OrganizationFilter struct {
+	Name             string `json:"name,omitempty" query:"name"`
+	Code             string `json:"code,omitempty" query:"code"`
+	OrganizationType string `json:"organization_type,omitempty" query:"organization_type"`
+	Status           string `json:"status,omitempty" query:"status"`
+	IsActive         *bool  `json:"is_active,omitempty" query:"is_active"`
+}
+
+// Criteria returns the filters as dafi criteria. Names match partially.
+func (f OrganizationFilter) Criteria() dafi.Criteria {
+	criteria := dafi.New()
+
+	if f.Name != "" {
+		criteria = criteria.And("name", dafi.Like, "%"+f.Name+"%")
+	}
+	if f.Code != "" {
+		criteria = criteria.And("code", dafi.Equal, f.Code)
+	}
+	if f.OrganizationType != "" {
+		criteria = criteria.And("organization_type", dafi.Equal, f.OrganizationType)
+	}
+	if f.Status != "" {
+		criteria = criteria.And("status", dafi.Equal, f.Status)
+	}
+	if f.IsActive != nil {
+		criteria = criteria.And("is_active", dafi.Equal, *f.IsActive)
+	}
+
+	return criteria
+}
+
+// ListOrganizationsRequest adds pagination and sorting to
+// OrganizationFilter
+type ListOrganizationsRequest struct {
+	OrganizationFilter
+	Page      uint   `json:"page,omitempty" query:"page"`
+	PageSize  uint   `json:"page_size,omitempty" query:"page_size"`
+	SortBy    string `json:"sort_by,omitempty" query:"sort_by"`
+	SortOrder string `json:"sort_order,omitempty" query:"sort_order"`
+}
+
+func (r ListOrganizationsRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"page":       valid.Int().Min(1),
+		"page_size":  valid.Int().Range(1, MaxPageSize),
+		"sort_by":    valid.Enum(OrganizationSortFields...),
+		"sort_order": valid.Enum("asc", "desc").CaseInsensitive(),
+	})
+}
+
+func (r ListOrganizationsRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// Criteria returns the filters, page and sort as dafi criteria. The first
+// page of DefaultPageSize organizations is returned when no page is given.
+func (r ListOrganizationsRequest) Criteria() dafi.Criteria {
+	return paginate(r.OrganizationFilter.Criteria(), r.Page, r.PageSize, r.SortBy, r.SortOrder)
+}
+
+// MemberFilter holds the filters accepted by the member list
+type MemberFilter struct {
+	UserID       *uuid.UUID `json:"user_id,omitempty" query:"user_id"`
+	CustomerID   *uuid.UUID `json:"customer_id,omitempty" query:"customer_id"`
+	Relationship string     `json:"relationship,omitempty" query:"relationship"`
+	IsActive     *bool      `json:"is_active,omitempty" query:"is_active"`
+}
+
+// Criteria returns the filters as dafi criteria
+func (f MemberFilter) Criteria() dafi.Criteria {
+	criteria := dafi.New()
+
+	if f.UserID != nil {
+		criteria = criteria.And("user_id", dafi.Equal, *f.UserID)
+	}
+	if f.CustomerID != nil {
+		criteria = criteria.And("customer_id", dafi.Equal, *f.CustomerID)
+	}
+	if f.Relationship != "" {
+		criteria = criteria.And("relationship", dafi.Equal, f.Relationship)
+	}
+	if f.IsActive != nil {
+		criteria = criteria.And("is_active", dafi.Equal, *f.IsActive)
+	}
+
+	return criteria
+}
+
+// ListMembersRequest lists the members of an organization with
+// pagination and sorting
+type ListMembersRequest struct {
+	MemberFilter
+	OrganizationID uuid.UUID `json:"-" param:"id"`
+	Page           uint      `json:"page,omitempty" query:"page"`
+	PageSize       uint      `json:"page_size,omitempty" query:"page_size"`
+	SortBy         string    `json:"sort_by,omitempty" query:"sort_by"`
+	SortOrder      string    `json:"sort_order,omitempty" query:"sort_order"`
+}
+
+func (r ListMembersRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"page":       valid.Int().Min(1),
+		"page_size":  valid.Int().Range(1, MaxPageSize),
+		"sort_by":    valid.Enum(MemberSortFields...),
+		"sort_order": valid.Enum("asc", "desc").CaseInsensitive(),
+	})
+}
+
+func (r ListMembersRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// Criteria returns the filters, page and sort as dafi criteria
+func (r ListMembersRequest) Criteria() dafi.Criteria {
+	return paginate(r.MemberFilter.Criteria(), r.Page, r.PageSize, r.SortBy, r.SortOrder)
+}
+
+func paginate(criteria dafi.Criteria, page, pageSize uint, sortBy, sortOrder string) dafi.Criteria {
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	criteria = criteria.Page(page).Limit(pageSize)
+
+	if sortBy != "" {
+		if strings.EqualFold(sortOrder, "desc") {
+			criteria = criteria.SortBy(sortBy, dafi.Desc)
+		} else {
+			criteria = criteria.SortBy(sortBy, dafi.Asc)
+		}
+	}
+
+	return criteria
+}
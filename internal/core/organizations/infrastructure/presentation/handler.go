@@ -0,0 +1,137 @@
+package presentation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/organizations/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// OrganizationIDRequest binds the organization id path parameter
+type OrganizationIDRequest struct {
+	ID uuid.UUID `param:"id"`
+}
+
+// OrganizationHandler exposes the organization use cases over HTTP. Its
+// methods are adapted to echo handlers with server.Handle.
+type OrganizationHandler struct {
+	usecase ports.OrganizationUseCase
+	tracer  trace.Tracer
+}
+
+func NewOrganizationHandler(usecase ports.OrganizationUseCase) *OrganizationHandler {
+	return &OrganizationHandler{
+		usecase: usecase,
+		tracer:  otel.Tracer("organizations-handler"),
+	}
+}
+
+// CreateOrganization creates an organization
+func (h *OrganizationHandler) CreateOrganization(ctx context.Context, req entity.CreateOrganizationRequest) (entity.Organization, error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.CreateOrganization")
+	defer span.End()
+
+	return h.usecase.CreateOrganization(ctx, req)
+}
+
+// GetOrganization gets an organization by its ID
+func (h *OrganizationHandler) GetOrganization(ctx context.Context, req OrganizationIDRequest) (entity.Organization, error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.GetOrganization")
+	defer span.End()
+
+	return h.usecase.GetOrganizationByID(ctx, req.ID)
+}
+
+// ListOrganizations lists organizations with optional filtering, sorting,
+// and pagination
+func (h *OrganizationHandler) ListOrganizations(ctx context.Context, req entity.ListOrganizationsRequest) (types.List[entity.Organization], error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.ListOrganizations")
+	defer span.End()
+
+	return h.usecase.ListOrganizations(ctx, req.Criteria())
+}
+
+// CountOrganizations counts organizations with optional filtering
+func (h *OrganizationHandler) CountOrganizations(ctx context.Context, req entity.OrganizationFilter) (response.CountResponse, error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.CountOrganizations")
+	defer span.End()
+
+	count, err := h.usecase.CountOrganizations(ctx, req.Criteria())
+	if err != nil {
+		return response.CountResponse{}, err
+	}
+
+	return response.CountResponse{Count: count}, nil
+}
+
+// UpdateOrganization updates an organization
+func (h *OrganizationHandler) UpdateOrganization(ctx context.Context, req entity.UpdateOrganizationRequest) (entity.Organization, error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.UpdateOrganization")
+	defer span.End()
+
+	return h.usecase.UpdateOrganization(ctx, req)
+}
+
+// DeleteOrganization soft deletes an organization other than the root one
+func (h *OrganizationHandler) DeleteOrganization(ctx context.Context, req OrganizationIDRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.DeleteOrganization")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.DeleteOrganization(ctx, req.ID)
+}
+
+// ListMembers lists the members of an organization
+func (h *OrganizationHandler) ListMembers(ctx context.Context, req entity.ListMembersRequest) (types.List[entity.Member], error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.ListMembers")
+	defer span.End()
+
+	return h.usecase.ListMembers(ctx, req.OrganizationID, req.Criteria())
+}
+
+// AddMember adds a user to an organization
+func (h *OrganizationHandler) AddMember(ctx context.Context, req entity.AddMemberRequest) (entity.Member, error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.AddMember")
+	defer span.End()
+
+	return h.usecase.AddMember(ctx, req)
+}
+
+// InviteMember adds a user to an organization by email
+func (h *OrganizationHandler) InviteMember(ctx context.Context, req entity.InviteMemberRequest) (entity.Member, error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.InviteMember")
+	defer span.End()
+
+	return h.usecase.InviteMember(ctx, req)
+}
+
+// UpdateMember changes the relationship or active flag of a member
+func (h *OrganizationHandler) UpdateMember(ctx context.Context, req entity.UpdateMemberRequest) (entity.Member, error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.UpdateMember")
+	defer span.End()
+
+	return h.usecase.UpdateMember(ctx, req)
+}
+
+// RemoveMember removes a member from an organization
+func (h *OrganizationHandler) RemoveMember(ctx context.Context, req entity.MemberRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.RemoveMember")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.RemoveMember(ctx, req)
+}
+
+// MyOrganizations lists the organizations the caller is an active
+// member of
+func (h *OrganizationHandler) MyOrganizations(ctx context.Context, _ struct{}) (types.List[entity.Membership], error) {
+	ctx, span := h.tracer.Start(ctx, "OrganizationHandler.MyOrganizations")
+	defer span.End()
+
+	return h.usecase.MyOrganizations(ctx)
+}
@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/organizations/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/sqlcraft"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+const memberColumns = "id, organization_id, user_id, customer_id, relationship, is_active, first_name, last_name, email, created_at, created_by, updated_at, updated_by"
+
+// members joins auth.organization_users with the name and email of their
+// users, so members can be filtered and sorted by unqualified columns
+const members = `(
+	SELECT ou.id, ou.organization_id, ou.user_id, ou.customer_id, ou.relationship, ou.is_active,
+		u.first_name, u.last_name, ec.email, ou.created_at, ou.created_by, ou.updated_at, ou.updated_by
+	FROM auth.organization_users ou
+	JOIN auth.users u ON u.id = ou.user_id
+	LEFT JOIN auth.email_credentials ec ON ec.user_id = u.id
+) members`
+
+// memberColumnByDomainField maps the fields members can be filtered by to
+// their columns, both in members and in auth.organization_users
+var memberColumnByDomainField = map[string]string{
+	"id":              "id",
+	"organization_id": "organization_id",
+	"user_id":         "user_id",
+	"customer_id":     "customer_id",
+	"relationship":    "relationship",
+	"is_active":       "is_active",
+}
+
+type MemberRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewMemberRepository(db ports.Database) *MemberRepository {
+	return &MemberRepository{
+		db:     db,
+		tracer: otel.Tracer("members-repository"),
+	}
+}
+
+func (r *MemberRepository) WithTx(tx ports.Transaction) ports.MemberRepository {
+	return &MemberRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *MemberRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *MemberRepository) Add(ctx context.Context, member entity.Member) error {
+	ctx, span := r.tracer.Start(ctx, "MemberRepository.Add")
+	defer span.End()
+
+	// Only live organizations of the tenant can get members
+	clause, err := organizationWhere(ctx, 7, dafi.FilterBy("id", dafi.Equal, member.OrganizationID))
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO auth.organization_users (id, organization_id, user_id, customer_id, relationship, is_active, created_at, created_by)
+		SELECT $1::uuid, id, $2::uuid, $3::uuid, $4::varchar, $5::boolean, $6::timestamp, $7::uuid
+		FROM auth.organizations` + clause.Sql
+
+	args := append([]any{
+		member.ID,
+		member.UserID,
+		member.CustomerID,
+		member.Relationship,
+		member.IsActive,
+		member.CreatedAt,
+		member.CreatedBy,
+	}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return memberConstraintError(err, "failed to add member")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("organization not found")).Code(fault.NotFound).Message("organization not found")
+	}
+
+	return nil
+}
+
+func (r *MemberRepository) Find(ctx context.Context, criteria dafi.Criteria) (entity.Member, error) {
+	ctx, span := r.tracer.Start(ctx, "MemberRepository.Find")
+	defer span.End()
+
+	clause, err := memberWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return entity.Member{}, err
+	}
+
+	query := "SELECT " + memberColumns + " FROM " + members + clause.Sql + " LIMIT 1"
+
+	member, err := scanMember(r.getExecutor().QueryRow(ctx, query, clause.Args...))
+	if err != nil {
+		return entity.Member{}, fault.Wrap(err).Message("failed to find member")
+	}
+
+	return member, nil
+}
+
+func (r *MemberRepository) List(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Member], error) {
+	ctx, span := r.tracer.Start(ctx, "MemberRepository.List")
+	defer span.End()
+
+	clause, err := memberWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := " ORDER BY first_name, last_name"
+	if !criteria.Sorts.IsZero() {
+		orderBy = sqlcraft.BuildOrderBy(criteria.Sorts)
+	}
+
+	query := "SELECT " + memberColumns + " FROM " + members + clause.Sql + orderBy + sqlcraft.BuildPagination(criteria.Pagination)
+
+	rows, err := r.getExecutor().Query(ctx, query, clause.Args...)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list members")
+	}
+	defer rows.Close()
+
+	var list types.List[entity.Member]
+	for rows.Next() {
+		member, err := scanMember(rows)
+		if err != nil {
+			return nil, fault.Wrap(err).Message("failed to scan member")
+		}
+		list = append(list, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fault.Wrap(err).Message("failed to list members")
+	}
+
+	return list, nil
+}
+
+func (r *MemberRepository) Update(ctx context.Context, member entity.Member, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "MemberRepository.Update")
+	defer span.End()
+
+	clause, err := memberWhere(ctx, 4, filters)
+	if err != nil {
+		return err
+	}
+
+	// Admins take over the memberships deactivated with their users
+	query := "UPDATE auth.organization_users SET relationship = $1, is_active = $2, updated_at = $3, updated_by = $4, deactivated_with_user = false" + clause.Sql
+	args := append([]any{member.Relationship, member.IsActive, member.UpdatedAt, member.UpdatedBy}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return memberConstraintError(err, "failed to update member")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("member not found")).Code(fault.NotFound).Message("member not found")
+	}
+
+	return nil
+}
+
+func (r *MemberRepository) Remove(ctx context.Context, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "MemberRepository.Remove")
+	defer span.End()
+
+	clause, err := memberWhere(ctx, 0, filters)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.getExecutor().Exec(ctx, "DELETE FROM auth.organization_users"+clause.Sql, clause.Args...)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to remove member")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("member not found")).Code(fault.NotFound).Message("member not found")
+	}
+
+	return nil
+}
+
+func (r *MemberRepository) Memberships(ctx context.Context, userID uuid.UUID) (types.List[entity.Membership], error) {
+	ctx, span := r.tracer.Start(ctx, "MemberRepository.Memberships")
+	defer span.End()
+
+	query := `
+		SELECT o.id, o.name, o.code, o.organization_type, o.is_root_organization, ou.id, ou.customer_id, ou.relationship
+		FROM auth.organization_users ou
+		JOIN auth.organizations o ON o.id = ou.organization_id
+		WHERE ou.user_id = $1
+			AND ou.is_active
+			AND o.is_active
+			AND o.deleted_at IS NULL
+		ORDER BY o.is_root_organization DESC, o.name
+	`
+
+	rows, err := r.getExecutor().Query(ctx, query, userID)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list memberships")
+	}
+	defer rows.Close()
+
+	var memberships types.List[entity.Membership]
+	for rows.Next() {
+		var m entity.Membership
+		err := rows.Scan(&m.OrganizationID, &m.Name, &m.Code, &m.OrganizationType, &m.IsRootOrganization, &m.MemberID, &m.CustomerID, &m.Relationship)
+		if err != nil {
+			return nil, fault.Wrap(err).Message("failed to scan membership")
+		}
+		memberships = append(memberships, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fault.Wrap(err).Message("failed to list memberships")
+	}
+
+	return memberships, nil
+}
+
+func (r *MemberRepository) DeactivateUsers(ctx context.Context, at time.Time, by *uuid.UUID, userIDs ...uuid.UUID) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "MemberRepository.DeactivateUsers")
+	defer span.End()
+
+	query := `
+		UPDATE auth.organization_users
+		SET is_active = false, deactivated_with_user = true, updated_at = $1, updated_by = $2
+		WHERE user_id = ANY($3) AND is_active
+	`
+
+	result, err := r.getExecutor().Exec(ctx, query, at, by, userIDs)
+	if err != nil {
+		return 0, fault.Wrap(err).Message("failed to deactivate memberships")
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *MemberRepository) ActivateUsers(ctx context.Context, at time.Time, by *uuid.UUID, userIDs ...uuid.UUID) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "MemberRepository.ActivateUsers")
+	defer span.End()
+
+	query := `
+		UPDATE auth.organization_users
+		SET is_active = true, deactivated_with_user = false, updated_at = $1, updated_by = $2
+		WHERE user_id = ANY($3) AND deactivated_with_user
+	`
+
+	result, err := r.getExecutor().Exec(ctx, query, at, by, userIDs)
+	if err != nil {
+		return 0, fault.Wrap(err).Message("failed to activate memberships")
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// memberWhere builds the WHERE clause of filters on members, restricted
+// to the organizations of the tenant of ctx
+func memberWhere(ctx context.Context, initialArgCount int, filters dafi.Filters) (sqlcraft.Result, error) {
+	filters, err := tenant.Restrict(ctx, filters, "organization_id")
+	if err != nil {
+		return sqlcraft.Result{}, err
+	}
+	return sqlcraft.WhereSafe(initialArgCount, memberColumnByDomainField, slices.Clone(filters)...)
+}
+
+// memberConstraintError returns duplicate memberships as fault.Conflict
+// and unknown users or customers as fault.BadRequest errors
+func memberConstraintError(err error, message string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolation:
+			return fault.Wrap(err).Code(fault.Conflict).Message("the user is already a member of this organization")
+		case foreignKeyViolation:
+			return fault.Wrap(err).Code(fault.BadRequest).Message("referenced record does not exist").With("constraint", pgErr.ConstraintName)
+		}
+	}
+	return fault.Wrap(err).Message(message)
+}
+
+func scanMember(row pgx.Row) (entity.Member, error) {
+	var member entity.Member
+	err := row.Scan(
+		&member.ID,
+		&member.OrganizationID,
+		&member.UserID,
+		&member.CustomerID,
+		&member.Relationship,
+		&member.IsActive,
+		&member.FirstName,
+		&member.LastName,
+		&member.Email,
+		&member.CreatedAt,
+		&member.CreatedBy,
+		&member.UpdatedAt,
+		&member.UpdatedBy,
+	)
+	return member, err
+}
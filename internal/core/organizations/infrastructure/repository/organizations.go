@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/organizations/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/sqlcraft"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// PostgreSQL error codes of constraint violations
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+const organizationColumns = "id, name, code, description, organization_type, status, is_active, is_root_organization, created_at, created_by, updated_at, updated_by"
+
+// organizationColumnByDomainField maps the fields organizations can be
+// filtered by to their columns
+var organizationColumnByDomainField = map[string]string{
+	"id":                   "id",
+	"name":                 "name",
+	"code":                 "code",
+	"organization_type":    "organization_type",
+	"status":               "status",
+	"is_active":            "is_active",
+	"is_root_organization": "is_root_organization",
+	"created_at":           "created_at",
+	"created_by":           "created_by",
+	"updated_at":           "updated_at",
+	"updated_by":           "updated_by",
+	"deleted_at":           "deleted_at",
+}
+
+type OrganizationRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewOrganizationRepository(db ports.Database) *OrganizationRepository {
+	return &OrganizationRepository{
+		db:     db,
+		tracer: otel.Tracer("organizations-repository"),
+	}
+}
+
+func (r *OrganizationRepository) WithTx(tx ports.Transaction) ports.OrganizationRepository {
+	return &OrganizationRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *OrganizationRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, organization entity.Organization) error {
+	ctx, span := r.tracer.Start(ctx, "OrganizationRepository.Create")
+	defer span.End()
+
+	query := `
+		INSERT INTO auth.organizations (id, name, code, description, organization_type, status, is_active, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.getExecutor().Exec(ctx, query,
+		organization.ID,
+		organization.Name,
+		organization.Code,
+		organization.Description,
+		organization.OrganizationType,
+		organization.Status,
+		organization.IsActive,
+		organization.CreatedAt,
+		organization.CreatedBy,
+	)
+	if err != nil {
+		return organizationConstraintError(err, "failed to create organization")
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) CreateBulk(ctx context.Context, organizations types.List[entity.Organization]) error {
+	ctx, span := r.tracer.Start(ctx, "OrganizationRepository.CreateBulk")
+	defer span.End()
+
+	for _, organization := range organizations {
+		if err := r.Create(ctx, organization); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) Find(ctx context.Context, criteria dafi.Criteria) (entity.Organization, error) {
+	ctx, span := r.tracer.Start(ctx, "OrganizationRepository.Find")
+	defer span.End()
+
+	clause, err := organizationWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return entity.Organization{}, err
+	}
+
+	query := "SELECT " + organizationColumns + " FROM auth.organizations" + clause.Sql + " LIMIT 1"
+
+	organization, err := scanOrganization(r.getExecutor().QueryRow(ctx, query, clause.Args...))
+	if err != nil {
+		return entity.Organization{}, fault.Wrap(err).Message("failed to find organization")
+	}
+
+	return organization, nil
+}
+
+func (r *OrganizationRepository) List(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Organization], error) {
+	ctx, span := r.tracer.Start(ctx, "OrganizationRepository.List")
+	defer span.End()
+
+	clause, err := organizationWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := " ORDER BY name"
+	if !criteria.Sorts.IsZero() {
+		orderBy = sqlcraft.BuildOrderBy(criteria.Sorts)
+	}
+
+	query := "SELECT " + organizationColumns + " FROM auth.organizations" + clause.Sql + orderBy + sqlcraft.BuildPagination(criteria.Pagination)
+
+	rows, err := r.getExecutor().Query(ctx, query, clause.Args...)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list organizations")
+	}
+	defer rows.Close()
+
+	var organizations types.List[entity.Organization]
+	for rows.Next() {
+		organization, err := scanOrganization(rows)
+		if err != nil {
+			return nil, fault.Wrap(err).Message("failed to scan organization")
+		}
+		organizations = append(organizations, organization)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fault.Wrap(err).Message("failed to list organizations")
+	}
+
+	return organizations, nil
+}
+
+func (r *OrganizationRepository) Update(ctx context.Context, organization entity.Organization, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "OrganizationRepository.Update")
+	defer span.End()
+
+	clause, err := organizationWhere(ctx, 8, filters)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE auth.organizations SET name = $1, code = $2, description = $3, organization_type = $4, status = $5, is_active = $6, updated_at = $7, updated_by = $8" + clause.Sql
+	args := append([]any{
+		organization.Name,
+		organization.Code,
+		organization.Description,
+		organization.OrganizationType,
+		organization.Status,
+		organization.IsActive,
+		organization.UpdatedAt,
+		organization.UpdatedBy,
+	}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return organizationConstraintError(err, "failed to update organization")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("organization not found")).Code(fault.NotFound).Message("organization not found")
+	}
+
+	return nil
+}
+
+// Delete soft deletes the organizations matching the filters. Their
+// memberships are kept but grant no access.
+func (r *OrganizationRepository) Delete(ctx context.Context, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "OrganizationRepository.Delete")
+	defer span.End()
+
+	clause, err := organizationWhere(ctx, 2, filters)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE auth.organizations SET deleted_at = $1, deleted_by = $2, is_active = false" + clause.Sql
+	args := append([]any{time.Now(), auth.UserIDFrom(ctx)}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to delete organization")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("organization not found")).Code(fault.NotFound).Message("organization not found")
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) Exists(ctx context.Context, criteria dafi.Criteria) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "OrganizationRepository.Exists")
+	defer span.End()
+
+	clause, err := organizationWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := r.getExecutor().QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM auth.organizations"+clause.Sql+")", clause.Args...).Scan(&exists); err != nil {
+		return false, fault.Wrap(err).Message("failed to check if organization exists")
+	}
+
+	return exists, nil
+}
+
+func (r *OrganizationRepository) Count(ctx context.Context, criteria dafi.Criteria) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "OrganizationRepository.Count")
+	defer span.End()
+
+	clause, err := organizationWhere(ctx, 0, criteria.Filters)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := r.getExecutor().QueryRow(ctx, "SELECT COUNT(*) FROM auth.organizations"+clause.Sql, clause.Args...).Scan(&count); err != nil {
+		return 0, fault.Wrap(err).Message("failed to count organizations")
+	}
+
+	return count, nil
+}
+
+// organizationWhere builds the WHERE clause of filters on live
+// organizations, restricted to the organizations of the tenant of ctx
+func organizationWhere(ctx context.Context, initialArgCount int, filters dafi.Filters) (sqlcraft.Result, error) {
+	filters, err := tenant.Restrict(ctx, filters, "id")
+	if err != nil {
+		return sqlcraft.Result{}, err
+	}
+	filters = slices.Clone(filters).And("deleted_at", dafi.IsNull, nil)
+	return sqlcraft.WhereSafe(initialArgCount, organizationColumnByDomainField, filters...)
+}
+
+// organizationConstraintError returns duplicate codes as fault.Conflict
+// errors
+func organizationConstraintError(err error, message string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fault.Wrap(err).Code(fault.Conflict).Message("an organization with this code already exists")
+	}
+	return fault.Wrap(err).Message(message)
+}
+
+func scanOrganization(row pgx.Row) (entity.Organization, error) {
+	var organization entity.Organization
+	err := row.Scan(
+		&organization.ID,
+		&organization.Name,
+		&organization.Code,
+		&organization.Description,
+		&organization.OrganizationType,
+		&organization.Status,
+		&organization.IsActive,
+		&organization.IsRootOrganization,
+		&organization.CreatedAt,
+		&organization.CreatedBy,
+		&organization.UpdatedAt,
+		&organization.UpdatedBy,
+	)
+	return organization, err
+}
@@ -0,0 +1,28 @@
+package organizations
+
+import (
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/core/organizations/application"
+	"api.system.soluciones-cloud.com/internal/core/organizations/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/core/organizations/infrastructure/repository"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			repository.NewOrganizationRepository,
+			fx.As(new(ports.OrganizationRepository)),
+		),
+		fx.Annotate(
+			repository.NewMemberRepository,
+			fx.As(new(ports.MemberRepository)),
+		),
+		fx.Annotate(
+			application.NewOrganizationUseCase,
+			fx.As(new(ports.OrganizationUseCase)),
+		),
+		presentation.NewOrganizationHandler,
+	),
+)
@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/roles/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+// CatalogUseCase keeps the permission catalog in line with the routes. It
+// runs before the API serves requests, so no cached permission has to be
+// invalidated; other instances pick the changes up once their cache
+// expires.
+type CatalogUseCase struct {
+	uow     ports.UnitOfWork
+	catalog ports.CatalogRepository
+	tracer  trace.Tracer
+}
+
+func NewCatalogUseCase(uow ports.UnitOfWork, catalog ports.CatalogRepository) *CatalogUseCase {
+	return &CatalogUseCase{
+		uow:     uow,
+		catalog: catalog,
+		tracer:  otel.Tracer("catalog-usecase"),
+	}
+}
+
+func (u *CatalogUseCase) Sync(ctx context.Context, actions []entity.ActionDescriptor, dryRun bool) (entity.CatalogReport, error) {
+	ctx, span := u.tracer.Start(ctx, "SyncCatalog")
+	defer span.End()
+
+	tx, err := u.uow.Begin(ctx)
+	if err != nil {
+		return entity.CatalogReport{}, fault.Wrap(err).Message("failed to begin transaction")
+	}
+
+	report, err := u.catalog.WithTx(tx).Sync(ctx, actions)
+	if err != nil || dryRun {
+		_ = u.uow.Rollback(ctx, tx)
+		return report, err
+	}
+
+	if err := u.uow.Commit(ctx, tx); err != nil {
+		return entity.CatalogReport{}, fault.Wrap(err).Message("failed to commit transaction")
+	}
+	return report, nil
+}
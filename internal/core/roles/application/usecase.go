@@ -0,0 +1,553 @@
+package application
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/guregu/null.v4"
+
+	auditentity "api.system.soluciones-cloud.com/internal/core/audit/domain/entity"
+	"api.system.soluciones-cloud.com/internal/core/roles/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+var (
+	// roleVisibility restricts OWN scopes to the roles created by the
+	// caller and ORG scopes to the roles of the caller's organizations
+	roleVisibility = rbac.Visibility{Owner: "created_by", Organization: "organization_id"}
+	// userRoleVisibility restricts OWN scopes to the assignments made by
+	// the caller and ORG scopes to the roles of the caller's organizations
+	userRoleVisibility = rbac.Visibility{Owner: "assigned_by", Organization: "organization_id"}
+)
+
+// Audited entity types
+const (
+	auditRole = "role"
+	auditUser = "user"
+)
+
+// RoleUseCase manages roles, their permissions and their assignments.
+// Every change is recorded in the audit log in the same transaction, and
+// the cached permissions of the affected users are invalidated once it
+// is committed.
+type RoleUseCase struct {
+	uow         ports.UnitOfWork
+	roles       ports.RoleRepository
+	userRoles   ports.UserRoleRepository
+	permissions ports.RolePermissionRepository
+	audit       ports.AuditRepository
+	authorizer  ports.Authorizer
+	now         func() time.Time
+	tracer      trace.Tracer
+}
+
+func NewRoleUseCase(
+	uow ports.UnitOfWork,
+	roles ports.RoleRepository,
+	userRoles ports.UserRoleRepository,
+	permissions ports.RolePermissionRepository,
+	audit ports.AuditRepository,
+	authorizer ports.Authorizer,
+) *RoleUseCase {
+	return &RoleUseCase{
+		uow:         uow,
+		roles:       roles,
+		userRoles:   userRoles,
+		permissions: permissions,
+		audit:       audit,
+		authorizer:  authorizer,
+		now:         time.Now,
+		tracer:      otel.Tracer("roles-usecase"),
+	}
+}
+
+func (u *RoleUseCase) CreateRole(ctx context.Context, req entity.CreateRoleRequest) (entity.Role, error) {
+	ctx, span := u.tracer.Start(ctx, "CreateRole")
+	defer span.End()
+
+	organizationID, err := tenant.Assign(ctx, req.OrganizationID)
+	if err != nil {
+		return entity.Role{}, err
+	}
+	if err := checkOrganization(ctx, organizationID); err != nil {
+		return entity.Role{}, err
+	}
+
+	role := entity.Role{
+		ID:             uuid.New(),
+		Name:           req.Name,
+		Code:           req.Code,
+		Description:    null.NewString(req.Description, req.Description != ""),
+		OrganizationID: organizationID,
+		CompanyID:      req.CompanyID,
+		IsActive:       true,
+		CreatedAt:      u.now(),
+		CreatedBy:      auth.ActorID(ctx),
+	}
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.roles.WithTx(tx).Create(ctx, role); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, "role.created", auditRole, role.ID, role.OrganizationID, map[string]any{
+			"name": role.Name,
+			"code": role.Code,
+		})
+	})
+	if err != nil {
+		return entity.Role{}, fault.Wrap(err).Message("failed to create role")
+	}
+
+	return role, nil
+}
+
+func (u *RoleUseCase) GetRoleByID(ctx context.Context, id uuid.UUID) (entity.Role, error) {
+	ctx, span := u.tracer.Start(ctx, "GetRoleByID")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, dafi.Where("id", dafi.Equal, id), roleVisibility)
+	if err != nil {
+		return entity.Role{}, err
+	}
+
+	role, err := u.roles.Find(ctx, criteria)
+	if err != nil {
+		return entity.Role{}, fault.Wrap(err).Message("failed to get role by ID")
+	}
+
+	return role, nil
+}
+
+func (u *RoleUseCase) ListRoles(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Role], error) {
+	ctx, span := u.tracer.Start(ctx, "ListRoles")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, roleVisibility)
+	if err != nil {
+		return types.List[entity.Role]{}, err
+	}
+
+	roles, err := u.roles.List(ctx, criteria)
+	if err != nil {
+		return types.List[entity.Role]{}, fault.Wrap(err).Message("failed to list roles")
+	}
+
+	return roles, nil
+}
+
+func (u *RoleUseCase) CountRoles(ctx context.Context, criteria dafi.Criteria) (int64, error) {
+	ctx, span := u.tracer.Start(ctx, "CountRoles")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, roleVisibility)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := u.roles.Count(ctx, criteria)
+	if err != nil {
+		return 0, fault.Wrap(err).Message("failed to count roles")
+	}
+
+	return count, nil
+}
+
+func (u *RoleUseCase) UpdateRole(ctx context.Context, req entity.UpdateRoleRequest) (entity.Role, error) {
+	ctx, span := u.tracer.Start(ctx, "UpdateRole")
+	defer span.End()
+
+	role, err := u.modifiableRole(ctx, req.ID)
+	if err != nil {
+		return entity.Role{}, err
+	}
+
+	changes := map[string]any{}
+	if req.Name.Valid && req.Name.String != role.Name {
+		role.Name = req.Name.String
+		changes["name"] = role.Name
+	}
+	if req.Code.Valid && req.Code.String != role.Code {
+		role.Code = req.Code.String
+		changes["code"] = role.Code
+	}
+	if req.Description.Valid && req.Description != role.Description {
+		role.Description = null.NewString(req.Description.String, req.Description.String != "")
+		changes["description"] = role.Description
+	}
+	if req.IsActive.Valid && req.IsActive.Bool != role.IsActive {
+		role.IsActive = req.IsActive.Bool
+		changes["is_active"] = role.IsActive
+	}
+	if len(changes) == 0 {
+		return role, nil
+	}
+
+	role.UpdatedAt = null.TimeFrom(u.now())
+	role.UpdatedBy = auth.ActorID(ctx)
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.roles.WithTx(tx).Update(ctx, role, dafi.FilterBy("id", dafi.Equal, role.ID)...); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, "role.updated", auditRole, role.ID, role.OrganizationID, changes)
+	})
+	if err != nil {
+		return entity.Role{}, fault.Wrap(err).Message("failed to update role")
+	}
+
+	// Role codes are in the access tokens and inactive roles grant nothing
+	u.authorizer.InvalidateAll()
+
+	return role, nil
+}
+
+func (u *RoleUseCase) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	ctx, span := u.tracer.Start(ctx, "DeleteRole")
+	defer span.End()
+
+	role, err := u.modifiableRole(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.roles.WithTx(tx).Delete(ctx, dafi.FilterBy("id", dafi.Equal, role.ID)...); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, "role.deleted", auditRole, role.ID, role.OrganizationID, map[string]any{
+			"name": role.Name,
+			"code": role.Code,
+		})
+	})
+	if err != nil {
+		return fault.Wrap(err).Message("failed to delete role")
+	}
+
+	u.authorizer.InvalidateAll()
+
+	return nil
+}
+
+func (u *RoleUseCase) ListRolePermissions(ctx context.Context, roleID uuid.UUID) (types.List[entity.ModuleAction], error) {
+	ctx, span := u.tracer.Start(ctx, "ListRolePermissions")
+	defer span.End()
+
+	if _, err := u.GetRoleByID(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	actions, err := u.permissions.List(ctx, roleID)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list role permissions")
+	}
+
+	return actions, nil
+}
+
+func (u *RoleUseCase) GrantPermissions(ctx context.Context, req entity.RolePermissionsRequest) (types.List[entity.ModuleAction], error) {
+	ctx, span := u.tracer.Start(ctx, "GrantPermissions")
+	defer span.End()
+
+	return u.changePermissions(ctx, req, "role.permissions_granted", u.checkGrantable, ports.RolePermissionRepository.Grant)
+}
+
+func (u *RoleUseCase) RevokePermissions(ctx context.Context, req entity.RolePermissionsRequest) (types.List[entity.ModuleAction], error) {
+	ctx, span := u.tracer.Start(ctx, "RevokePermissions")
+	defer span.End()
+
+	return u.changePermissions(ctx, req, "role.permissions_revoked", nil, ports.RolePermissionRepository.Revoke)
+}
+
+// changePermissions grants or revokes the actions of a role with change,
+// once check, if any, accepted them, and returns the permissions of the
+// role afterwards
+func (u *RoleUseCase) changePermissions(
+	ctx context.Context,
+	req entity.RolePermissionsRequest,
+	action string,
+	check func(ctx context.Context, role entity.Role, actions types.List[entity.ModuleAction]) error,
+	change func(repo ports.RolePermissionRepository, ctx context.Context, roleID uuid.UUID, actionIDs []uuid.UUID) error,
+) (types.List[entity.ModuleAction], error) {
+
+	role, err := u.modifiableRole(ctx, req.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := u.permissions.Actions(ctx, req.Actions)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to find module actions")
+	}
+
+	codes := make([]string, 0, len(actions))
+	actionIDs := make([]uuid.UUID, 0, len(actions))
+	for _, moduleAction := range actions {
+		codes = append(codes, moduleAction.Code)
+		actionIDs = append(actionIDs, moduleAction.ID)
+	}
+
+	var unknown []string
+	for _, code := range req.Actions {
+		if !slices.Contains(codes, code) && !slices.Contains(unknown, code) {
+			unknown = append(unknown, code)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fault.New("unknown module actions").Code(fault.BadRequest).With("actions", unknown)
+	}
+
+	if check != nil {
+		if err := check(ctx, role, actions); err != nil {
+			return nil, err
+		}
+	}
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := change(u.permissions.WithTx(tx), ctx, role.ID, actionIDs); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, action, auditRole, role.ID, role.OrganizationID, map[string]any{"actions": codes})
+	})
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to change role permissions")
+	}
+
+	// Any user may have the role
+	u.authorizer.InvalidateAll()
+
+	granted, err := u.permissions.List(ctx, role.ID)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list role permissions")
+	}
+
+	return granted, nil
+}
+
+func (u *RoleUseCase) ListUserRoles(ctx context.Context, userID uuid.UUID) (types.List[entity.UserRole], error) {
+	ctx, span := u.tracer.Start(ctx, "ListUserRoles")
+	defer span.End()
+
+	criteria, err := rbac.RestrictCriteria(ctx, dafi.Where("user_id", dafi.Equal, userID), userRoleVisibility)
+	if err != nil {
+		return nil, err
+	}
+
+	userRoles, err := u.userRoles.List(ctx, criteria)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list user roles")
+	}
+
+	return userRoles, nil
+}
+
+func (u *RoleUseCase) AssignRole(ctx context.Context, req entity.AssignRoleRequest) (entity.UserRole, error) {
+	ctx, span := u.tracer.Start(ctx, "AssignRole")
+	defer span.End()
+
+	now := u.now()
+	if req.ExpiresAt.Valid && !req.ExpiresAt.Time.After(now) {
+		return entity.UserRole{}, fault.New("expires_at must be in the future").Code(fault.BadRequest)
+	}
+
+	role, err := u.GetRoleByID(ctx, req.RoleID)
+	if err != nil {
+		return entity.UserRole{}, err
+	}
+	if !role.IsActive {
+		return entity.UserRole{}, fault.New("inactive roles cannot be assigned").Code(fault.BadRequest)
+	}
+
+	userRole := entity.UserRole{
+		ID:             uuid.New(),
+		UserID:         req.UserID,
+		RoleID:         role.ID,
+		RoleCode:       role.Code,
+		RoleName:       role.Name,
+		OrganizationID: role.OrganizationID,
+		AssignedBy:     auth.ActorID(ctx),
+		AssignedAt:     now,
+		ExpiresAt:      req.ExpiresAt,
+		IsActive:       true,
+	}
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		id, err := u.userRoles.WithTx(tx).Assign(ctx, userRole)
+		if err != nil {
+			return err
+		}
+		userRole.ID = id
+
+		return u.record(ctx, tx, "user.role_assigned", auditUser, userRole.UserID, role.OrganizationID, map[string]any{
+			"role_id":    role.ID,
+			"role_code":  role.Code,
+			"expires_at": userRole.ExpiresAt,
+		})
+	})
+	if err != nil {
+		return entity.UserRole{}, fault.Wrap(err).Message("failed to assign role")
+	}
+
+	u.authorizer.Invalidate(userRole.UserID)
+
+	return userRole, nil
+}
+
+func (u *RoleUseCase) RevokeRole(ctx context.Context, req entity.RevokeRoleRequest) error {
+	ctx, span := u.tracer.Start(ctx, "RevokeRole")
+	defer span.End()
+
+	role, err := u.GetRoleByID(ctx, req.RoleID)
+	if err != nil {
+		return err
+	}
+
+	filters, err := rbac.Restrict(ctx, dafi.FilterBy("user_id", dafi.Equal, req.UserID).And("role_id", dafi.Equal, role.ID), userRoleVisibility)
+	if err != nil {
+		return err
+	}
+
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		if err := u.userRoles.WithTx(tx).Revoke(ctx, filters...); err != nil {
+			return err
+		}
+		return u.record(ctx, tx, "user.role_revoked", auditUser, req.UserID, role.OrganizationID, map[string]any{
+			"role_id":   role.ID,
+			"role_code": role.Code,
+		})
+	})
+	if err != nil {
+		return fault.Wrap(err).Message("failed to revoke role")
+	}
+
+	u.authorizer.Invalidate(req.UserID)
+
+	return nil
+}
+
+// UserPermissions returns the effective permissions of a user. Callers
+// with the ORG scope must select one of their organizations, callers with
+// the OWN scope may only see their own permissions.
+func (u *RoleUseCase) UserPermissions(ctx context.Context, req entity.UserPermissionsRequest) (entity.UserPermissions, error) {
+	ctx, span := u.tracer.Start(ctx, "UserPermissions")
+	defer span.End()
+
+	principal := auth.Principal{UserID: req.UserID}
+	if req.OrganizationID != nil {
+		principal.OrganizationID = uuid.NullUUID{UUID: *req.OrganizationID, Valid: true}
+	}
+
+	if access, ok := rbac.AccessFrom(ctx); ok {
+		switch access.Scope {
+		case rbac.ScopeOwn:
+			if principal.UserID != access.UserID {
+				return entity.UserPermissions{}, fault.New("only your own permissions are visible").Code(fault.Forbidden)
+			}
+		case rbac.ScopeOrganization:
+			if err := checkOrganization(ctx, principal.OrganizationID.UUID); err != nil {
+				return entity.UserPermissions{}, err
+			}
+		}
+	}
+
+	permissions, err := u.authorizer.Permissions(ctx, principal)
+	if err != nil {
+		return entity.UserPermissions{}, fault.Wrap(err).Message("failed to load user permissions")
+	}
+
+	result := entity.UserPermissions{
+		UserID:        req.UserID,
+		Roles:         append([]string{}, permissions.Roles...),
+		Permissions:   make(map[string]string, len(permissions.Actions)),
+		Organizations: append([]uuid.UUID{}, permissions.Organizations...),
+	}
+	for code, scope := range permissions.Actions {
+		result.Permissions[code] = string(scope)
+	}
+
+	return result, nil
+}
+
+// checkGrantable rejects the actions the caller is not granted in the
+// organization of the role, so roles never hold more than the callers
+// managing them. Actions with the ALL scope reach every organization and
+// may only be granted by root organization members, who grant with the
+// permissions they act with.
+func (u *RoleUseCase) checkGrantable(ctx context.Context, role entity.Role, actions types.List[entity.ModuleAction]) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return fault.New("authentication required").Code(fault.Unauthorized)
+	}
+
+	permissions, err := u.authorizer.Permissions(ctx, principal)
+	if err != nil {
+		return err
+	}
+	if !permissions.Root {
+		principal.OrganizationID = uuid.NullUUID{UUID: role.OrganizationID, Valid: true}
+		if permissions, err = u.authorizer.Permissions(ctx, principal); err != nil {
+			return err
+		}
+	}
+
+	for _, moduleAction := range actions {
+		if _, ok := permissions.Scope(moduleAction.Code); !ok {
+			return fault.New("you cannot grant an action you are not granted").
+				Code(fault.Forbidden).
+				With("action", moduleAction.Code)
+		}
+		if rbac.Scope(moduleAction.VisibilityScope) == rbac.ScopeAll && !permissions.Root {
+			return fault.New("only root organization members can grant actions on every organization").
+				Code(fault.Forbidden).
+				With("action", moduleAction.Code)
+		}
+	}
+	return nil
+}
+
+// modifiableRole returns the role unless it is a system role
+func (u *RoleUseCase) modifiableRole(ctx context.Context, id uuid.UUID) (entity.Role, error) {
+	role, err := u.GetRoleByID(ctx, id)
+	if err != nil {
+		return entity.Role{}, err
+	}
+
+	if role.IsSystemRole {
+		return entity.Role{}, fault.New("system roles cannot be modified").Code(fault.Forbidden).With("role_id", id)
+	}
+
+	return role, nil
+}
+
+// record appends a change made by the caller to the audit log
+func (u *RoleUseCase) record(ctx context.Context, tx ports.Transaction, action, entityType string, entityID, organizationID uuid.UUID, changes map[string]any) error {
+	return u.audit.WithTx(tx).Record(ctx, auditentity.Entry{
+		ID:             uuid.New(),
+		OrganizationID: uuid.NullUUID{UUID: organizationID, Valid: true},
+		ActorID:        auth.UserIDFrom(ctx),
+		Action:         action,
+		EntityType:     entityType,
+		EntityID:       entityID,
+		Changes:        changes,
+		CreatedAt:      u.now(),
+	})
+}
+
+// checkOrganization forbids callers with the ORG scope from acting on
+// organizations they are not members of
+func checkOrganization(ctx context.Context, organizationID uuid.UUID) error {
+	access, ok := rbac.AccessFrom(ctx)
+	if !ok || access.Scope != rbac.ScopeOrganization || slices.Contains(access.Organizations, organizationID) {
+		return nil
+	}
+	return fault.New("organization not accessible").Code(fault.Forbidden).With("organization_id", organizationID)
+}
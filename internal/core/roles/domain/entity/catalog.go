@@ -0,0 +1,34 @@
+package entity
+
+// ActionDescriptor declares a module action exposed by the API routes.
+// Name is only used when the action is created.
+type ActionDescriptor struct {
+	Module     string
+	Action     string
+	ActionType string
+	IsPublic   bool
+	Name       string
+}
+
+// Code returns the "<module>.<action>" code of the action.
+func (d ActionDescriptor) Code() string {
+	return d.Module + "." + d.Action
+}
+
+// CatalogReport lists the differences found between the declared actions
+// and auth.modules/auth.module_actions, as action codes.
+type CatalogReport struct {
+	CreatedModules []string `json:"created_modules"`
+	CreatedActions []string `json:"created_actions"`
+	// UpdatedActions had a different action_type or is_public
+	UpdatedActions []string `json:"updated_actions"`
+	// OrphanedActions are declared by no route. They are only reported,
+	// roles may still be granted them.
+	OrphanedActions []string `json:"orphaned_actions"`
+}
+
+// InSync reports whether the catalog needed no change. Orphaned actions
+// are not changes.
+func (r CatalogReport) InSync() bool {
+	return len(r.CreatedModules) == 0 && len(r.CreatedActions) == 0 && len(r.UpdatedActions) == 0
+}
@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+const (
+	// codePattern is the pattern of role codes, e.g. sales-manager
+	codePattern = `^[a-z][a-z0-9_-]*$`
+	// actionCodePattern is the pattern of "<module>.<action>" codes
+	actionCodePattern = `^[a-z][a-z0-9_-]*\.[a-z][a-z0-9_-]*$`
+	// MaxActionsPerRequest bounds the actions granted or revoked at once
+	MaxActionsPerRequest = 100
+)
+
+// CreateRoleRequest creates a role. OrganizationID defaults to the
+// organization the request acts on.
+type CreateRoleRequest struct {
+	Name           string     `json:"name"`
+	Code           string     `json:"code"`
+	Description    string     `json:"description,omitempty"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	CompanyID      *uuid.UUID `json:"company_id,omitempty"`
+}
+
+func (r CreateRoleRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"name":            valid.String().MaxLength(100).Required(),
+		"code":            valid.String().MaxLength(100).Pattern(codePattern).Required(),
+		"description":     valid.String(),
+		"organization_id": valid.String().UUID(),
+		"company_id":      valid.String().UUID(),
+	})
+}
+
+func (r CreateRoleRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+type UpdateRoleRequest struct {
+	ID          uuid.UUID   `json:"-" param:"id"`
+	Name        null.String `json:"name,omitempty"`
+	Code        null.String `json:"code,omitempty"`
+	Description null.String `json:"description,omitempty"`
+	IsActive    null.Bool   `json:"is_active,omitempty"`
+}
+
+func (r UpdateRoleRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"name":        valid.String().MaxLength(100),
+		"code":        valid.String().MaxLength(100).Pattern(codePattern),
+		"description": valid.String(),
+		"is_active":   valid.Bool(),
+	})
+}
+
+func (r UpdateRoleRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// RolePermissionsRequest grants or revokes module actions of a role
+type RolePermissionsRequest struct {
+	RoleID  uuid.UUID `json:"-" param:"id"`
+	Actions []string  `json:"actions"`
+}
+
+func (r RolePermissionsRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"actions": valid.Array(valid.String().Pattern(actionCodePattern)).Length(1, MaxActionsPerRequest).Required(),
+	})
+}
+
+func (r RolePermissionsRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// AssignRoleRequest assigns a role to a user, or renews the assignment
+// when the user already has it
+type AssignRoleRequest struct {
+	UserID    uuid.UUID `json:"-" param:"id"`
+	RoleID    uuid.UUID `json:"role_id"`
+	ExpiresAt null.Time `json:"expires_at,omitempty"`
+}
+
+func (r AssignRoleRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"role_id":    valid.String().UUID().Required(),
+		"expires_at": valid.Time(),
+	})
+}
+
+func (r AssignRoleRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// RevokeRoleRequest removes a role from a user
+type RevokeRoleRequest struct {
+	UserID uuid.UUID `param:"id"`
+	RoleID uuid.UUID `param:"role_id"`
+}
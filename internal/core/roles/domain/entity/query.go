@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// RoleSortFields are the fields roles can be sorted by
+var RoleSortFields = []string{"id", "name", "code", "is_active", "created_at", "updated_at"}
+
+// RoleFilter holds the filters accepted by the list and count endpoints
+type RoleFilter struct {
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" query:"organization_id"`
+	Code           string     `json:"code,omitempty" query:"code"`
+	Name           string     `json:"name,omitempty" query:"name"`
+	IsActive       *bool      `json:"is_active,omitempty" query:"is_active"`
+	IsSystemRole   *bool      `json:"is_system_role,omitempty" query:"is_system_role"`
+}
+
+// Criteria returns the filters as dafi criteria. Names match partially.
+func (f RoleFilter) Criteria() dafi.Criteria {
+	criteria := dafi.New()
+
+	if f.OrganizationID != nil {
+		criteria = criteria.And("organization_id", dafi.Equal, *f.OrganizationID)
+	}
+	if f.Code != "" {
+		criteria = criteria.And("code", dafi.Equal, f.Code)
+	}
+	if f.Name != "" {
+		criteria = criteria.And("name", dafi.Like, "%"+f.Name+"%")
+	}
+	if f.IsActive != nil {
+		criteria = criteria.And("is_active", dafi.Equal, *f.IsActive)
+	}
+	if f.IsSystemRole != nil {
+		criteria = criteria.And("is_system_role", dafi.Equal, *f.IsSystemRole)
+	}
+
+	return criteria
+}
+
+// ListRolesRequest adds pagination and sorting to RoleFilter
+type ListRolesRequest struct {
+	RoleFilter
+	Page      uint   `json:"page,omitempty" query:"page"`
+	PageSize  uint   `json:"page_size,omitempty" query:"page_size"`
+	SortBy    string `json:"sort_by,omitempty" query:"sort_by"`
+	SortOrder string `json:"sort_order,omitempty" query:"sort_order"`
+}
+
+func (r ListRolesRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"page":       valid.Int().Min(1),
+		"page_size":  valid.Int().Range(1, MaxPageSize),
+		"sort_by":    valid.Enum(RoleSortFields...),
+		"sort_order": valid.Enum("asc", "desc").CaseInsensitive(),
+	})
+}
+
+func (r ListRolesRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// Criteria returns the filters, page and sort as dafi criteria. The first
+// page of DefaultPageSize roles is returned when no page is given.
+func (r ListRolesRequest) Criteria() dafi.Criteria {
+	page, pageSize := r.Page, r.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	criteria := r.RoleFilter.Criteria().Page(page).Limit(pageSize)
+
+	if r.SortBy != "" {
+		if strings.EqualFold(r.SortOrder, "desc") {
+			criteria = criteria.SortBy(r.SortBy, dafi.Desc)
+		} else {
+			criteria = criteria.SortBy(r.SortBy, dafi.Asc)
+		}
+	}
+
+	return criteria
+}
+
+// UserPermissionsRequest selects the user whose effective permissions are
+// listed, optionally limited to the roles of one organization
+type UserPermissionsRequest struct {
+	UserID         uuid.UUID  `param:"id"`
+	OrganizationID *uuid.UUID `query:"organization_id"`
+}
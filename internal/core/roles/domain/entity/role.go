@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Role groups the module actions granted to the users it is assigned to.
+// System roles are seeded by migrations and cannot be modified.
+type Role struct {
+	ID             uuid.UUID   `json:"id" db:"id"`
+	Name           string      `json:"name" db:"name"`
+	Code           string      `json:"code" db:"code"`
+	Description    null.String `json:"description" db:"description"`
+	OrganizationID uuid.UUID   `json:"organization_id" db:"organization_id"`
+	CompanyID      *uuid.UUID  `json:"company_id" db:"company_id"`
+	IsSystemRole   bool        `json:"is_system_role" db:"is_system_role"`
+	IsActive       bool        `json:"is_active" db:"is_active"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+	CreatedBy      *uuid.UUID  `json:"created_by" db:"created_by"`
+	UpdatedAt      null.Time   `json:"updated_at" db:"updated_at"`
+	UpdatedBy      *uuid.UUID  `json:"updated_by" db:"updated_by"`
+}
+
+// UserRole is a role assigned to a user. Assignments grant nothing once
+// inactive or expired.
+type UserRole struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	RoleID         uuid.UUID  `json:"role_id" db:"role_id"`
+	RoleCode       string     `json:"role_code" db:"role_code"`
+	RoleName       string     `json:"role_name" db:"role_name"`
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	AssignedBy     *uuid.UUID `json:"assigned_by" db:"assigned_by"`
+	AssignedAt     time.Time  `json:"assigned_at" db:"assigned_at"`
+	ExpiresAt      null.Time  `json:"expires_at" db:"expires_at"`
+	IsActive       bool       `json:"is_active" db:"is_active"`
+}
+
+// ModuleAction is an action roles are granted, identified by its
+// "<module>.<action>" code, e.g. users.read
+type ModuleAction struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	Code            string    `json:"code" db:"code"`
+	Name            string    `json:"name" db:"name"`
+	VisibilityScope string    `json:"visibility_scope" db:"visibility_scope"`
+	IsPublic        bool      `json:"is_public" db:"is_public"`
+}
+
+// UserPermissions are the effective permissions of a user: the actions
+// of their active roles plus the public actions.
+type UserPermissions struct {
+	UserID uuid.UUID `json:"user_id"`
+	Roles  []string  `json:"roles"`
+	// Permissions maps the granted action codes to their visibility scope
+	Permissions   map[string]string `json:"permissions"`
+	Organizations []uuid.UUID       `json:"organizations"`
+}
@@ -0,0 +1,148 @@
+package presentation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/roles/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// RoleIDRequest binds the role id path parameter
+type RoleIDRequest struct {
+	ID uuid.UUID `param:"id"`
+}
+
+// UserIDRequest binds the user id path parameter
+type UserIDRequest struct {
+	ID uuid.UUID `param:"id"`
+}
+
+// RoleHandler exposes the role use cases over HTTP. Its methods are
+// adapted to echo handlers with server.Handle.
+type RoleHandler struct {
+	usecase ports.RoleUseCase
+	tracer  trace.Tracer
+}
+
+func NewRoleHandler(usecase ports.RoleUseCase) *RoleHandler {
+	return &RoleHandler{
+		usecase: usecase,
+		tracer:  otel.Tracer("roles-handler"),
+	}
+}
+
+// CreateRole creates a role in an organization
+func (h *RoleHandler) CreateRole(ctx context.Context, req entity.CreateRoleRequest) (entity.Role, error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.CreateRole")
+	defer span.End()
+
+	return h.usecase.CreateRole(ctx, req)
+}
+
+// GetRole gets a role by its ID
+func (h *RoleHandler) GetRole(ctx context.Context, req RoleIDRequest) (entity.Role, error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.GetRole")
+	defer span.End()
+
+	return h.usecase.GetRoleByID(ctx, req.ID)
+}
+
+// ListRoles lists roles with optional filtering, sorting, and pagination
+func (h *RoleHandler) ListRoles(ctx context.Context, req entity.ListRolesRequest) (types.List[entity.Role], error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.ListRoles")
+	defer span.End()
+
+	return h.usecase.ListRoles(ctx, req.Criteria())
+}
+
+// CountRoles counts roles with optional filtering
+func (h *RoleHandler) CountRoles(ctx context.Context, req entity.RoleFilter) (response.CountResponse, error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.CountRoles")
+	defer span.End()
+
+	count, err := h.usecase.CountRoles(ctx, req.Criteria())
+	if err != nil {
+		return response.CountResponse{}, err
+	}
+
+	return response.CountResponse{Count: count}, nil
+}
+
+// UpdateRole updates a role that is not a system role
+func (h *RoleHandler) UpdateRole(ctx context.Context, req entity.UpdateRoleRequest) (entity.Role, error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.UpdateRole")
+	defer span.End()
+
+	return h.usecase.UpdateRole(ctx, req)
+}
+
+// DeleteRole deletes a role that is not a system role
+func (h *RoleHandler) DeleteRole(ctx context.Context, req RoleIDRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.DeleteRole")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.DeleteRole(ctx, req.ID)
+}
+
+// ListRolePermissions lists the module actions granted to a role
+func (h *RoleHandler) ListRolePermissions(ctx context.Context, req RoleIDRequest) (types.List[entity.ModuleAction], error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.ListRolePermissions")
+	defer span.End()
+
+	return h.usecase.ListRolePermissions(ctx, req.ID)
+}
+
+// GrantPermissions grants module actions to a role
+func (h *RoleHandler) GrantPermissions(ctx context.Context, req entity.RolePermissionsRequest) (types.List[entity.ModuleAction], error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.GrantPermissions")
+	defer span.End()
+
+	return h.usecase.GrantPermissions(ctx, req)
+}
+
+// RevokePermissions revokes module actions from a role
+func (h *RoleHandler) RevokePermissions(ctx context.Context, req entity.RolePermissionsRequest) (types.List[entity.ModuleAction], error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.RevokePermissions")
+	defer span.End()
+
+	return h.usecase.RevokePermissions(ctx, req)
+}
+
+// ListUserRoles lists the roles assigned to a user
+func (h *RoleHandler) ListUserRoles(ctx context.Context, req UserIDRequest) (types.List[entity.UserRole], error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.ListUserRoles")
+	defer span.End()
+
+	return h.usecase.ListUserRoles(ctx, req.ID)
+}
+
+// AssignRole assigns a role to a user
+func (h *RoleHandler) AssignRole(ctx context.Context, req entity.AssignRoleRequest) (entity.UserRole, error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.AssignRole")
+	defer span.End()
+
+	return h.usecase.AssignRole(ctx, req)
+}
+
+// RevokeRole removes a role from a user
+func (h *RoleHandler) RevokeRole(ctx context.Context, req entity.RevokeRoleRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.RevokeRole")
+	defer span.End()
+
+	return server.NoContent{}, h.usecase.RevokeRole(ctx, req)
+}
+
+// UserPermissions returns the effective permissions of a user
+func (h *RoleHandler) UserPermissions(ctx context.Context, req entity.UserPermissionsRequest) (entity.UserPermissions, error) {
+	ctx, span := h.tracer.Start(ctx, "RoleHandler.UserPermissions")
+	defer span.End()
+
+	return h.usecase.UserPermissions(ctx, req)
+}
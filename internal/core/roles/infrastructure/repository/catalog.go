@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/roles/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+type CatalogRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewCatalogRepository(db ports.Database) *CatalogRepository {
+	return &CatalogRepository{
+		db:     db,
+		tracer: otel.Tracer("catalog-repository"),
+	}
+}
+
+func (r *CatalogRepository) WithTx(tx ports.Transaction) ports.CatalogRepository {
+	return &CatalogRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *CatalogRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *CatalogRepository) Sync(ctx context.Context, actions []entity.ActionDescriptor) (entity.CatalogReport, error) {
+	ctx, span := r.tracer.Start(ctx, "CatalogRepository.Sync")
+	defer span.End()
+
+	var (
+		modules     = make([]string, 0, len(actions))
+		codes       = make([]string, 0, len(actions))
+		actionTypes = make([]string, 0, len(actions))
+		public      = make([]bool, 0, len(actions))
+		names       = make([]string, 0, len(actions))
+		declared    = make([]string, 0, len(actions))
+	)
+	for _, action := range actions {
+		modules = append(modules, action.Module)
+		codes = append(codes, action.Action)
+		actionTypes = append(actionTypes, action.ActionType)
+		public = append(public, action.IsPublic)
+		names = append(names, action.Name)
+		declared = append(declared, action.Code())
+	}
+
+	var report entity.CatalogReport
+
+	// New modules are named after their code until renamed
+	modulesQuery := `
+		INSERT INTO auth.modules (name, code)
+		SELECT DISTINCT code, code
+		FROM UNNEST($1::text[]) AS code
+		ON CONFLICT (code) DO NOTHING
+		RETURNING code
+	`
+	rows, err := r.getExecutor().Query(ctx, modulesQuery, modules)
+	if err == nil {
+		report.CreatedModules, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	}
+	if err != nil {
+		return entity.CatalogReport{}, fault.Wrap(err).Message("failed to create modules")
+	}
+
+	// Names and descriptions of existing actions are left untouched, they
+	// are curated in the database. xmax is 0 for inserted rows.
+	actionsQuery := `
+		INSERT INTO auth.module_actions AS ma (module_id, name, code, action_type, is_public)
+		SELECT m.id, LEFT(d.name, 100), d.code, d.action_type, d.is_public
+		FROM UNNEST($1::text[], $2::text[], $3::text[], $4::boolean[], $5::text[])
+			AS d(module, code, action_type, is_public, name)
+		JOIN auth.modules m ON m.code = d.module
+		ON CONFLICT (module_id, code) DO UPDATE
+		SET action_type = EXCLUDED.action_type, is_public = EXCLUDED.is_public, updated_at = NOW()
+		WHERE (ma.action_type, ma.is_public) IS DISTINCT FROM (EXCLUDED.action_type, EXCLUDED.is_public)
+		RETURNING (SELECT m.code FROM auth.modules m WHERE m.id = ma.module_id) || '.' || ma.code, ma.xmax = 0
+	`
+	rows, err = r.getExecutor().Query(ctx, actionsQuery, modules, codes, actionTypes, public, names)
+	if err != nil {
+		return entity.CatalogReport{}, fault.Wrap(err).Message("failed to upsert module actions")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code string
+		var created bool
+		if err := rows.Scan(&code, &created); err != nil {
+			return entity.CatalogReport{}, fault.Wrap(err).Message("failed to scan module action")
+		}
+		if created {
+			report.CreatedActions = append(report.CreatedActions, code)
+		} else {
+			report.UpdatedActions = append(report.UpdatedActions, code)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return entity.CatalogReport{}, fault.Wrap(err).Message("failed to upsert module actions")
+	}
+
+	orphansQuery := `
+		SELECT m.code || '.' || ma.code
+		FROM auth.module_actions ma
+		JOIN auth.modules m ON m.id = ma.module_id
+		WHERE m.code || '.' || ma.code <> ALL($1)
+		ORDER BY 1
+	`
+	rows, err = r.getExecutor().Query(ctx, orphansQuery, declared)
+	if err == nil {
+		report.OrphanedActions, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	}
+	if err != nil {
+		return entity.CatalogReport{}, fault.Wrap(err).Message("failed to list orphaned module actions")
+	}
+
+	slices.Sort(report.CreatedModules)
+	slices.Sort(report.CreatedActions)
+	slices.Sort(report.UpdatedActions)
+	return report, nil
+}
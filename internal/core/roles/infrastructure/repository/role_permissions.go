@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/roles/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+const moduleActionColumns = "ma.id, m.code || '.' || ma.code, ma.name, ma.visibility_scope, ma.is_public"
+
+type RolePermissionRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewRolePermissionRepository(db ports.Database) *RolePermissionRepository {
+	return &RolePermissionRepository{
+		db:     db,
+		tracer: otel.Tracer("role-permissions-repository"),
+	}
+}
+
+func (r *RolePermissionRepository) WithTx(tx ports.Transaction) ports.RolePermissionRepository {
+	return &RolePermissionRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *RolePermissionRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *RolePermissionRepository) Actions(ctx context.Context, codes []string) (types.List[entity.ModuleAction], error) {
+	ctx, span := r.tracer.Start(ctx, "RolePermissionRepository.Actions")
+	defer span.End()
+
+	query := `
+		SELECT ` + moduleActionColumns + `
+		FROM auth.module_actions ma
+		JOIN auth.modules m ON m.id = ma.module_id
+		WHERE m.code || '.' || ma.code = ANY($1)
+		ORDER BY 2
+	`
+
+	return r.listActions(ctx, query, codes)
+}
+
+func (r *RolePermissionRepository) List(ctx context.Context, roleID uuid.UUID) (types.List[entity.ModuleAction], error) {
+	ctx, span := r.tracer.Start(ctx, "RolePermissionRepository.List")
+	defer span.End()
+
+	query := `
+		SELECT ` + moduleActionColumns + `
+		FROM auth.permissions p
+		JOIN auth.module_actions ma ON ma.id = p.module_action_id
+		JOIN auth.modules m ON m.id = ma.module_id
+		WHERE p.role_id = $1
+		ORDER BY 2
+	`
+
+	return r.listActions(ctx, query, roleID)
+}
+
+func (r *RolePermissionRepository) Grant(ctx context.Context, roleID uuid.UUID, actionIDs []uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "RolePermissionRepository.Grant")
+	defer span.End()
+
+	query := `
+		INSERT INTO auth.permissions (role_id, module_action_id, created_by)
+		SELECT $1::uuid, action_id, $3::uuid
+		FROM UNNEST($2::uuid[]) AS action_id
+		ON CONFLICT (role_id, module_action_id) DO NOTHING
+	`
+
+	if _, err := r.getExecutor().Exec(ctx, query, roleID, actionIDs, auth.UserIDFrom(ctx)); err != nil {
+		return fault.Wrap(err).Message("failed to grant permissions")
+	}
+
+	return nil
+}
+
+func (r *RolePermissionRepository) Revoke(ctx context.Context, roleID uuid.UUID, actionIDs []uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "RolePermissionRepository.Revoke")
+	defer span.End()
+
+	query := "DELETE FROM auth.permissions WHERE role_id = $1 AND module_action_id = ANY($2)"
+
+	if _, err := r.getExecutor().Exec(ctx, query, roleID, actionIDs); err != nil {
+		return fault.Wrap(err).Message("failed to revoke permissions")
+	}
+
+	return nil
+}
+
+func (r *RolePermissionRepository) listActions(ctx context.Context, query string, args ...any) (types.List[entity.ModuleAction], error) {
+	rows, err := r.getExecutor().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list module actions")
+	}
+
+	actions, err := pgx.CollectRows(rows, scanModuleAction)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to scan module action")
+	}
+
+	return actions, nil
+}
+
+func scanModuleAction(row pgx.CollectableRow) (entity.ModuleAction, error) {
+	var action entity.ModuleAction
+	err := row.Scan(&action.ID, &action.Code, &action.Name, &action.VisibilityScope, &action.IsPublic)
+	return action, err
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/roles/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/sqlcraft"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// PostgreSQL error codes of constraint violations
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+const roleColumns = "id, name, code, description, organization_id, company_id, is_system_role, is_active, created_at, created_by, updated_at, updated_by"
+
+// roleColumnByDomainField maps the fields roles can be filtered by to
+// their columns
+var roleColumnByDomainField = map[string]string{
+	"id":              "id",
+	"name":            "name",
+	"code":            "code",
+	"organization_id": "organization_id",
+	"company_id":      "company_id",
+	"is_system_role":  "is_system_role",
+	"is_active":       "is_active",
+	"created_at":      "created_at",
+	"created_by":      "created_by",
+	"updated_at":      "updated_at",
+	"updated_by":      "updated_by",
+}
+
+type RoleRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewRoleRepository(db ports.Database) *RoleRepository {
+	return &RoleRepository{
+		db:     db,
+		tracer: otel.Tracer("roles-repository"),
+	}
+}
+
+func (r *RoleRepository) WithTx(tx ports.Transaction) ports.RoleRepository {
+	return &RoleRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *RoleRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *RoleRepository) Create(ctx context.Context, role entity.Role) error {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.Create")
+	defer span.End()
+
+	organizationID, err := tenant.Assign(ctx, role.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO auth.roles (id, name, code, description, organization_id, company_id, is_system_role, is_active, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err = r.getExecutor().Exec(ctx, query,
+		role.ID,
+		role.Name,
+		role.Code,
+		role.Description,
+		organizationID,
+		role.CompanyID,
+		role.IsSystemRole,
+		role.IsActive,
+		role.CreatedAt,
+		role.CreatedBy,
+	)
+	if err != nil {
+		return constraintError(err, "failed to create role")
+	}
+
+	return nil
+}
+
+func (r *RoleRepository) CreateBulk(ctx context.Context, roles types.List[entity.Role]) error {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.CreateBulk")
+	defer span.End()
+
+	for _, role := range roles {
+		if err := r.Create(ctx, role); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *RoleRepository) Find(ctx context.Context, criteria dafi.Criteria) (entity.Role, error) {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.Find")
+	defer span.End()
+
+	clause, err := where(ctx, 0, roleColumnByDomainField, criteria.Filters)
+	if err != nil {
+		return entity.Role{}, err
+	}
+
+	query := "SELECT " + roleColumns + " FROM auth.roles" + clause.Sql + " LIMIT 1"
+
+	role, err := scanRole(r.getExecutor().QueryRow(ctx, query, clause.Args...))
+	if err != nil {
+		return entity.Role{}, fault.Wrap(err).Message("failed to find role")
+	}
+
+	return role, nil
+}
+
+func (r *RoleRepository) List(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Role], error) {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.List")
+	defer span.End()
+
+	clause, err := where(ctx, 0, roleColumnByDomainField, criteria.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := " ORDER BY name"
+	if !criteria.Sorts.IsZero() {
+		orderBy = sqlcraft.BuildOrderBy(criteria.Sorts)
+	}
+
+	query := "SELECT " + roleColumns + " FROM auth.roles" + clause.Sql + orderBy + sqlcraft.BuildPagination(criteria.Pagination)
+
+	rows, err := r.getExecutor().Query(ctx, query, clause.Args...)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list roles")
+	}
+	defer rows.Close()
+
+	var roles types.List[entity.Role]
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, fault.Wrap(err).Message("failed to scan role")
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fault.Wrap(err).Message("failed to list roles")
+	}
+
+	return roles, nil
+}
+
+func (r *RoleRepository) Update(ctx context.Context, role entity.Role, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.Update")
+	defer span.End()
+
+	clause, err := where(ctx, 6, roleColumnByDomainField, filters)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE auth.roles SET name = $1, code = $2, description = $3, is_active = $4, updated_at = $5, updated_by = $6" + clause.Sql
+	args := append([]any{role.Name, role.Code, role.Description, role.IsActive, role.UpdatedAt, role.UpdatedBy}, clause.Args...)
+
+	result, err := r.getExecutor().Exec(ctx, query, args...)
+	if err != nil {
+		return constraintError(err, "failed to update role")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("role not found")).Code(fault.NotFound).Message("role not found")
+	}
+
+	return nil
+}
+
+// Delete deletes the roles matching the filters, with their assignments
+// and permissions
+func (r *RoleRepository) Delete(ctx context.Context, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.Delete")
+	defer span.End()
+
+	clause, err := where(ctx, 0, roleColumnByDomainField, filters)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.getExecutor().Exec(ctx, "DELETE FROM auth.roles"+clause.Sql, clause.Args...)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to delete role")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("role not found")).Code(fault.NotFound).Message("role not found")
+	}
+
+	return nil
+}
+
+func (r *RoleRepository) Exists(ctx context.Context, criteria dafi.Criteria) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.Exists")
+	defer span.End()
+
+	clause, err := where(ctx, 0, roleColumnByDomainField, criteria.Filters)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := r.getExecutor().QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM auth.roles"+clause.Sql+")", clause.Args...).Scan(&exists); err != nil {
+		return false, fault.Wrap(err).Message("failed to check if role exists")
+	}
+
+	return exists, nil
+}
+
+func (r *RoleRepository) Count(ctx context.Context, criteria dafi.Criteria) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.Count")
+	defer span.End()
+
+	clause, err := where(ctx, 0, roleColumnByDomainField, criteria.Filters)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := r.getExecutor().QueryRow(ctx, "SELECT COUNT(*) FROM auth.roles"+clause.Sql, clause.Args...).Scan(&count); err != nil {
+		return 0, fault.Wrap(err).Message("failed to count roles")
+	}
+
+	return count, nil
+}
+
+// where builds the WHERE clause of filters, restricted to the tenant of
+// ctx by the organization_id field. WhereSafe renames the fields in
+// place, so the filters are cloned first.
+func where(ctx context.Context, initialArgCount int, columns map[string]string, filters dafi.Filters) (sqlcraft.Result, error) {
+	filters, err := tenant.Restrict(ctx, filters, "organization_id")
+	if err != nil {
+		return sqlcraft.Result{}, err
+	}
+	return sqlcraft.WhereSafe(initialArgCount, columns, slices.Clone(filters)...)
+}
+
+// constraintError returns duplicate codes as fault.Conflict and unknown
+// organizations, companies or users as fault.BadRequest errors
+func constraintError(err error, message string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolation:
+			return fault.Wrap(err).Code(fault.Conflict).Message("a role with this code already exists")
+		case foreignKeyViolation:
+			return fault.Wrap(err).Code(fault.BadRequest).Message("referenced record does not exist").With("constraint", pgErr.ConstraintName)
+		}
+	}
+	return fault.Wrap(err).Message(message)
+}
+
+func scanRole(row pgx.Row) (entity.Role, error) {
+	var role entity.Role
+	err := row.Scan(
+		&role.ID,
+		&role.Name,
+		&role.Code,
+		&role.Description,
+		&role.OrganizationID,
+		&role.CompanyID,
+		&role.IsSystemRole,
+		&role.IsActive,
+		&role.CreatedAt,
+		&role.CreatedBy,
+		&role.UpdatedAt,
+		&role.UpdatedBy,
+	)
+	return role, err
+}
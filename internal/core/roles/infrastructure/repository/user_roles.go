@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/roles/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/sqlcraft"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// userRoleColumnByDomainField maps the fields assignments can be filtered
+// by to the columns of auth.user_roles ur joined with auth.roles r
+var userRoleColumnByDomainField = map[string]string{
+	"user_id":         "ur.user_id",
+	"role_id":         "ur.role_id",
+	"organization_id": "r.organization_id",
+	"assigned_by":     "ur.assigned_by",
+	"is_active":       "ur.is_active",
+}
+
+type UserRoleRepository struct {
+	db     ports.Database
+	tx     ports.Transaction
+	tracer trace.Tracer
+}
+
+func NewUserRoleRepository(db ports.Database) *UserRoleRepository {
+	return &UserRoleRepository{
+		db:     db,
+		tracer: otel.Tracer("user-roles-repository"),
+	}
+}
+
+func (r *UserRoleRepository) WithTx(tx ports.Transaction) ports.UserRoleRepository {
+	return &UserRoleRepository{
+		db:     r.db,
+		tx:     tx,
+		tracer: r.tracer,
+	}
+}
+
+func (r *UserRoleRepository) getExecutor() ports.DatabaseExecutor {
+	if r.tx != nil {
+		return r.tx.GetTx()
+	}
+	return r.db
+}
+
+func (r *UserRoleRepository) Assign(ctx context.Context, userRole entity.UserRole) (uuid.UUID, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRoleRepository.Assign")
+	defer span.End()
+
+	// Only roles of the tenant can be assigned
+	clause, err := where(ctx, 5, roleColumnByDomainField, dafi.FilterBy("id", dafi.Equal, userRole.RoleID))
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	query := `
+		INSERT INTO auth.user_roles (id, user_id, role_id, assigned_by, assigned_at, expires_at, is_active)
+		SELECT $1::uuid, $2::uuid, id, $3::uuid, $4::timestamp, $5::timestamp, true
+		FROM auth.roles` + clause.Sql + `
+		ON CONFLICT (user_id, role_id) DO UPDATE
+		SET assigned_by = EXCLUDED.assigned_by,
+			assigned_at = EXCLUDED.assigned_at,
+			expires_at = EXCLUDED.expires_at,
+			is_active = true
+		RETURNING id
+	`
+	args := append([]any{
+		userRole.ID,
+		userRole.UserID,
+		userRole.AssignedBy,
+		userRole.AssignedAt,
+		userRole.ExpiresAt,
+	}, clause.Args...)
+
+	var id uuid.UUID
+	err = r.getExecutor().QueryRow(ctx, query, args...).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fault.Wrap(err).Code(fault.NotFound).Message("role not found")
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			return uuid.Nil, fault.Wrap(err).Code(fault.NotFound).Message("user not found")
+		}
+		return uuid.Nil, fault.Wrap(err).Message("failed to assign role")
+	}
+
+	return id, nil
+}
+
+func (r *UserRoleRepository) Revoke(ctx context.Context, filters ...dafi.Filter) error {
+	ctx, span := r.tracer.Start(ctx, "UserRoleRepository.Revoke")
+	defer span.End()
+
+	clause, err := where(ctx, 0, userRoleColumnByDomainField, filters)
+	if err != nil {
+		return err
+	}
+
+	query := "DELETE FROM auth.user_roles ur USING auth.roles r WHERE r.id = ur.role_id"
+	if clause.Sql != "" {
+		query += " AND (" + strings.TrimPrefix(clause.Sql, " WHERE ") + ")"
+	}
+
+	result, err := r.getExecutor().Exec(ctx, query, clause.Args...)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to revoke role")
+	}
+
+	if result.RowsAffected() == 0 {
+		return fault.Wrap(fmt.Errorf("role not assigned")).Code(fault.NotFound).Message("the user does not have the role")
+	}
+
+	return nil
+}
+
+func (r *UserRoleRepository) List(ctx context.Context, criteria dafi.Criteria) (types.List[entity.UserRole], error) {
+	ctx, span := r.tracer.Start(ctx, "UserRoleRepository.List")
+	defer span.End()
+
+	clause, err := where(ctx, 0, userRoleColumnByDomainField, criteria.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ur.id, ur.user_id, ur.role_id, r.code, r.name, r.organization_id, ur.assigned_by, ur.assigned_at, ur.expires_at, ur.is_active
+		FROM auth.user_roles ur
+		JOIN auth.roles r ON r.id = ur.role_id` + clause.Sql + " ORDER BY r.name" + sqlcraft.BuildPagination(criteria.Pagination)
+
+	rows, err := r.getExecutor().Query(ctx, query, clause.Args...)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to list user roles")
+	}
+	defer rows.Close()
+
+	var userRoles types.List[entity.UserRole]
+	for rows.Next() {
+		var userRole entity.UserRole
+		err := rows.Scan(
+			&userRole.ID,
+			&userRole.UserID,
+			&userRole.RoleID,
+			&userRole.RoleCode,
+			&userRole.RoleName,
+			&userRole.OrganizationID,
+			&userRole.AssignedBy,
+			&userRole.AssignedAt,
+			&userRole.ExpiresAt,
+			&userRole.IsActive,
+		)
+		if err != nil {
+			return nil, fault.Wrap(err).Message("failed to scan user role")
+		}
+		userRoles = append(userRoles, userRole)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fault.Wrap(err).Message("failed to list user roles")
+	}
+
+	return userRoles, nil
+}
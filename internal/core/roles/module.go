@@ -0,0 +1,40 @@
+package roles
+
+import (
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/core/roles/application"
+	"api.system.soluciones-cloud.com/internal/core/roles/infrastructure/presentation"
+	"api.system.soluciones-cloud.com/internal/core/roles/infrastructure/repository"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			repository.NewRoleRepository,
+			fx.As(new(ports.RoleRepository)),
+		),
+		fx.Annotate(
+			repository.NewUserRoleRepository,
+			fx.As(new(ports.UserRoleRepository)),
+		),
+		fx.Annotate(
+			repository.NewRolePermissionRepository,
+			fx.As(new(ports.RolePermissionRepository)),
+		),
+		fx.Annotate(
+			repository.NewCatalogRepository,
+			fx.As(new(ports.CatalogRepository)),
+		),
+		fx.Annotate(
+			application.NewRoleUseCase,
+			fx.As(new(ports.RoleUseCase)),
+		),
+		fx.Annotate(
+			application.NewCatalogUseCase,
+			fx.As(new(ports.CatalogUseCase)),
+		),
+		presentation.NewRoleHandler,
+	),
+)
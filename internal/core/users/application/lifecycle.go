@@ -0,0 +1,225 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/core/users/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+// ActivateUser activates a user the caller may see and restores the
+// memberships deactivated with them. The sessions and API keys revoked on
+// deactivation stay revoked.
+func (u *UserUseCase) ActivateUser(ctx context.Context, id uuid.UUID) (entity.User, error) {
+	ctx, span := u.tracer.Start(ctx, "ActivateUser")
+	defer span.End()
+
+	if _, err := u.GetUserByID(ctx, id); err != nil {
+		return entity.User{}, err
+	}
+	if _, err := u.setActive(ctx, true, dafi.FilterBy("id", dafi.Equal, id)); err != nil {
+		return entity.User{}, err
+	}
+
+	return u.GetUserByID(ctx, id)
+}
+
+// DeactivateUser deactivates a user the caller may see, logging them out
+// of every device, revoking their API keys and deactivating their
+// memberships. Callers cannot deactivate themselves.
+func (u *UserUseCase) DeactivateUser(ctx context.Context, id uuid.UUID) (entity.User, error) {
+	ctx, span := u.tracer.Start(ctx, "DeactivateUser")
+	defer span.End()
+
+	if caller := auth.UserIDFrom(ctx); caller.Valid && caller.UUID == id {
+		return entity.User{}, fault.New("you cannot deactivate yourself").Code(fault.BadRequest)
+	}
+	if _, err := u.GetUserByID(ctx, id); err != nil {
+		return entity.User{}, err
+	}
+	if _, err := u.setActive(ctx, false, dafi.FilterBy("id", dafi.Equal, id)); err != nil {
+		return entity.User{}, err
+	}
+
+	return u.GetUserByID(ctx, id)
+}
+
+// ActivateUsers activates the users matching the criteria the caller may
+// see, like ActivateUser
+func (u *UserUseCase) ActivateUsers(ctx context.Context, criteria dafi.Criteria) (entity.StatusChange, error) {
+	ctx, span := u.tracer.Start(ctx, "ActivateUsers")
+	defer span.End()
+
+	return u.setActive(ctx, true, criteria.Filters)
+}
+
+// DeactivateUsers deactivates the users matching the criteria the caller
+// may see, like DeactivateUser. The caller is left out.
+func (u *UserUseCase) DeactivateUsers(ctx context.Context, criteria dafi.Criteria) (entity.StatusChange, error) {
+	ctx, span := u.tracer.Start(ctx, "DeactivateUsers")
+	defer span.End()
+
+	filters := criteria.Filters
+	if caller := auth.UserIDFrom(ctx); caller.Valid {
+		filters = filters.And("id", dafi.NotEqual, caller.UUID)
+	}
+	return u.setActive(ctx, false, filters)
+}
+
+// MergeUsers merges a duplicate user into the canonical one in a single
+// transaction: the rows the duplicate created or updated, their
+// memberships and their roles move to the canonical user, then the
+// duplicate is deactivated, like DeactivateUser, and deleted. Rows of
+// every organization are re-pointed, so the request must act on all of
+// them.
+func (u *UserUseCase) MergeUsers(ctx context.Context, req entity.MergeUsersRequest) (entity.MergeResult, error) {
+	ctx, span := u.tracer.Start(ctx, "MergeUsers")
+	defer span.End()
+
+	if t, ok := tenant.From(ctx); ok && !t.All {
+		return entity.MergeResult{}, fault.New("merging users requires acting on every organization, send the " + tenant.Header + " header with " + tenant.AllOrganizations).
+			Code(fault.Forbidden)
+	}
+	if caller := auth.UserIDFrom(ctx); caller.Valid && caller.UUID == req.DuplicateID {
+		return entity.MergeResult{}, fault.New("you cannot merge yourself into another user").Code(fault.BadRequest)
+	}
+
+	if _, err := u.GetUserByID(ctx, req.ID); err != nil {
+		return entity.MergeResult{}, err
+	}
+	if _, err := u.GetUserByID(ctx, req.DuplicateID); err != nil {
+		return entity.MergeResult{}, err
+	}
+
+	now := u.now()
+	by := auth.ActorID(ctx)
+	var result entity.MergeResult
+	var revoked []uuid.UUID
+	err := ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		repo := u.repo.WithTx(tx)
+
+		var err error
+		result, err = repo.Merge(ctx, req.DuplicateID, req.ID, now, by)
+		if err != nil {
+			return fault.Wrap(err).Message("failed to merge users")
+		}
+
+		duplicate := dafi.FilterBy("id", dafi.Equal, req.DuplicateID)
+		if _, err := repo.SetActive(ctx, false, now, by, duplicate...); err != nil {
+			return fault.Wrap(err).Message("failed to deactivate duplicate user")
+		}
+		var change entity.StatusChange
+		revoked, err = u.revokeAccess(ctx, tx, now, by, []uuid.UUID{req.DuplicateID}, &change)
+		if err != nil {
+			return err
+		}
+		if err := repo.Delete(ctx, duplicate...); err != nil {
+			return fault.Wrap(err).Message("failed to delete duplicate user")
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.MergeResult{}, err
+	}
+
+	u.denySessions(now, revoked)
+	u.authorizer.Invalidate(req.ID, req.DuplicateID)
+	span.SetAttributes(attribute.Int64("references.moved", result.References))
+
+	result.User, err = u.GetUserByID(ctx, req.ID)
+	if err != nil {
+		return entity.MergeResult{}, err
+	}
+	return result, nil
+}
+
+// setActive sets the status of the users matching the filters the caller
+// may see. Activation restores the memberships deactivated with the
+// users, deactivation revokes their access.
+func (u *UserUseCase) setActive(ctx context.Context, active bool, filters dafi.Filters) (entity.StatusChange, error) {
+	filters, err := rbac.Restrict(ctx, filters, visibility)
+	if err != nil {
+		return entity.StatusChange{}, err
+	}
+
+	now := u.now()
+	by := auth.ActorID(ctx)
+	change := entity.StatusChange{UserIDs: []uuid.UUID{}}
+	var revoked []uuid.UUID
+	err = ports.InTx(ctx, u.uow, func(tx ports.Transaction) error {
+		ids, err := u.repo.WithTx(tx).SetActive(ctx, active, now, by, filters...)
+		if err != nil {
+			return fault.Wrap(err).Message("failed to set user status")
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		change.UserIDs = ids
+
+		if active {
+			change.Memberships, err = u.members.WithTx(tx).ActivateUsers(ctx, now, by, ids...)
+			return err
+		}
+		revoked, err = u.revokeAccess(ctx, tx, now, by, ids, &change)
+		return err
+	})
+	if err != nil {
+		return entity.StatusChange{}, err
+	}
+
+	u.denySessions(now, revoked)
+	if len(change.UserIDs) > 0 {
+		u.authorizer.Invalidate(change.UserIDs...)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("users.changed", len(change.UserIDs)))
+	return change, nil
+}
+
+// revokeAccess revokes the sessions, refresh tokens and API keys of the
+// users and deactivates their memberships, recording the counts in
+// change. It returns the revoked sessions, to deny once committed.
+func (u *UserUseCase) revokeAccess(ctx context.Context, tx ports.Transaction, now time.Time, by *uuid.UUID, userIDs []uuid.UUID, change *entity.StatusChange) ([]uuid.UUID, error) {
+	sessions := u.sessions.WithTx(tx)
+	refreshTokens := u.refreshTokens.WithTx(tx)
+
+	var revoked []uuid.UUID
+	for _, userID := range userIDs {
+		ended, err := sessions.RevokeUser(ctx, userID, now, by)
+		if err != nil {
+			return nil, fault.Wrap(err).Message("failed to revoke sessions")
+		}
+		if err := refreshTokens.RevokeUser(ctx, userID, now); err != nil {
+			return nil, fault.Wrap(err).Message("failed to revoke refresh tokens")
+		}
+		revoked = append(revoked, ended...)
+	}
+	change.Sessions = len(revoked)
+
+	var err error
+	change.APIKeys, err = u.apiKeys.WithTx(tx).RevokeUsers(ctx, now, by, userIDs...)
+	if err != nil {
+		return nil, err
+	}
+	change.Memberships, err = u.members.WithTx(tx).DeactivateUsers(ctx, now, by, userIDs...)
+	if err != nil {
+		return nil, err
+	}
+	return revoked, nil
+}
+
+// denySessions rejects the access tokens of revoked sessions right away
+func (u *UserUseCase) denySessions(now time.Time, sessionIDs []uuid.UUID) {
+	if len(sessionIDs) > 0 {
+		u.denylist.Add(now.Add(u.config.SessionDenyTTL), sessionIDs...)
+	}
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// PreferredLocale returns the locale the user chose, or "" when they
+// chose none. It runs on every authenticated request, so the locales are
+// cached for Config.LocaleCacheTTL; UpdateMe drops the cached locale of
+// the caller right away.
+func (u *UserUseCase) PreferredLocale(ctx context.Context, userID uuid.UUID) (i18n.Locale, error) {
+	if locale, ok := u.locales.get(userID, u.now()); ok {
+		return locale, nil
+	}
+
+	ctx, span := u.tracer.Start(ctx, "PreferredLocale")
+	defer span.End()
+
+	generation := u.locales.generation()
+	user, err := u.repo.Find(ctx, dafi.Where("id", dafi.Equal, userID).And("deleted_at", dafi.IsNull, nil))
+	if err != nil {
+		return "", fault.Wrap(err).Message("failed to get user locale")
+	}
+
+	locale := i18n.Locale(user.Locale.String)
+	u.locales.put(userID, locale, generation, u.now())
+	return locale, nil
+}
+
+// checkLocale validates the locale against the locales with a message
+// catalog. Unsupported ones are reported as validation errors listing
+// them; an empty locale clears the preference.
+func checkLocale(ctx context.Context, locale i18n.Locale) error {
+	if locale == "" || i18n.Default.Supports(locale) {
+		return nil
+	}
+
+	supported := i18n.Default.Locales()
+	values := make([]string, len(supported))
+	for i, l := range supported {
+		values[i] = string(l)
+	}
+
+	err := &valid.ValidationError{
+		Path:    "locale",
+		Code:    "enum",
+		Message: i18n.T(ctx, "valid.enum", strings.Join(values, ", ")),
+	}
+	return fault.Wrap(err).Code(fault.UnprocessableEntity).Message("validation failed")
+}
+
+type localeEntry struct {
+	locale    i18n.Locale
+	expiresAt time.Time
+}
+
+// localeCache holds the preferred locales of users for a TTL
+type localeCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]localeEntry
+	// gen changes on every invalidation, so locales loaded while it
+	// happened are not cached
+	gen uint64
+}
+
+func newLocaleCache(ttl time.Duration) *localeCache {
+	return &localeCache{ttl: ttl, entries: make(map[uuid.UUID]localeEntry)}
+}
+
+func (c *localeCache) get(userID uuid.UUID, now time.Time) (i18n.Locale, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[userID]
+	if !ok || !now.Before(entry.expiresAt) {
+		return "", false
+	}
+	return entry.locale, true
+}
+
+func (c *localeCache) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+func (c *localeCache) put(userID uuid.UUID, locale i18n.Locale, generation uint64, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gen != generation {
+		return
+	}
+	// expired entries are dropped here, so users who stopped calling the
+	// API do not stay in memory
+	for id, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[userID] = localeEntry{locale: locale, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *localeCache) forget(userID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, userID)
+	c.gen++
+}
@@ -2,6 +2,8 @@ package application
 
 import (
 	"context"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,21 +11,87 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"api.system.soluciones-cloud.com/internal/core/users/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/blob"
 	"api.system.soluciones-cloud.com/internal/shared/dafi"
 	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
 	"api.system.soluciones-cloud.com/internal/shared/ports"
 	"api.system.soluciones-cloud.com/internal/shared/types"
 )
 
+// visibility restricts OWN scopes to the users created by the caller and
+// ORG scopes to the members of the caller's organizations
+var visibility = rbac.Visibility{Owner: "created_by", Organization: "organization_id"}
+
+// avatarPrefix starts the keys of avatars, followed by the user id and
+// the file name
+const avatarPrefix = "avatars/"
+
+type Config struct {
+	// AvatarLimits bound uploaded avatars, which are stored scaled down to
+	// AvatarSize x AvatarSize pixels
+	AvatarLimits blob.Limits
+	AvatarSize   int
+	// AvatarURL is the public URL of the avatar route. Pictures link to
+	// AvatarURL/<user id>/<name>, which redirects to a signed URL valid
+	// for URLExpiry.
+	AvatarURL string
+	URLExpiry time.Duration
+	// SessionDenyTTL is how long the sessions of deactivated users are
+	// denied: until their last access tokens expire
+	SessionDenyTTL time.Duration
+	// LocaleCacheTTL bounds how long the preferred locales of users are
+	// cached. Zero disables the cache.
+	LocaleCacheTTL time.Duration
+}
+
+// UserUseCase manages users. Deactivating users also revokes their
+// sessions, with their refresh tokens, and their API keys, and
+// deactivates their memberships.
 type UserUseCase struct {
-	repo   ports.UserRepository
-	tracer trace.Tracer
+	uow           ports.UnitOfWork
+	repo          ports.UserRepository
+	authorizer    ports.Authorizer
+	blobs         ports.BlobStore
+	sessions      ports.SessionRepository
+	refreshTokens ports.RefreshTokenRepository
+	denylist      ports.SessionDenylist
+	apiKeys       ports.APIKeyRepository
+	members       ports.MemberRepository
+	config        Config
+	locales       *localeCache
+	now           func() time.Time
+	tracer        trace.Tracer
 }
 
-func NewUserUseCase(repo ports.UserRepository) *UserUseCase {
+func NewUserUseCase(
+	uow ports.UnitOfWork,
+	repo ports.UserRepository,
+	authorizer ports.Authorizer,
+	blobs ports.BlobStore,
+	sessions ports.SessionRepository,
+	refreshTokens ports.RefreshTokenRepository,
+	denylist ports.SessionDenylist,
+	apiKeys ports.APIKeyRepository,
+	members ports.MemberRepository,
+	config Config,
+) *UserUseCase {
 	return &UserUseCase{
-		repo:   repo,
-		tracer: otel.Tracer("users-usecase"),
+		uow:           uow,
+		repo:          repo,
+		authorizer:    authorizer,
+		blobs:         blobs,
+		sessions:      sessions,
+		refreshTokens: refreshTokens,
+		denylist:      denylist,
+		apiKeys:       apiKeys,
+		members:       members,
+		config:        config,
+		locales:       newLocaleCache(config.LocaleCacheTTL),
+		now:           time.Now,
+		tracer:        otel.Tracer("users-usecase"),
 	}
 }
 
@@ -31,10 +99,6 @@ func (u *UserUseCase) CreateUser(ctx context.Context, req entity.CreateUserReque
 	ctx, span := u.tracer.Start(ctx, "CreateUser")
 	defer span.End()
 
-	if err := req.Validate(); err != nil {
-		return entity.User{}, fault.Wrap(err).Code(fault.BadRequest).Message("validation failed")
-	}
-
 	user := entity.User{
 		ID:        uuid.New(),
 		Origin:    req.Origin,
@@ -43,7 +107,7 @@ func (u *UserUseCase) CreateUser(ctx context.Context, req entity.CreateUserReque
 		Picture:   entity.NewNullString(req.Picture),
 		IsActive:  req.IsActive,
 		CreatedAt: time.Now(),
-		CreatedBy: req.CreatedBy,
+		CreatedBy: auth.ActorID(ctx),
 	}
 
 	if err := u.repo.Create(ctx, user); err != nil {
@@ -57,8 +121,10 @@ func (u *UserUseCase) GetUserByID(ctx context.Context, id uuid.UUID) (entity.Use
 	ctx, span := u.tracer.Start(ctx, "GetUserByID")
 	defer span.End()
 
-	criteria := dafi.Where("id", dafi.Equal, id).
-		And("deleted_at", dafi.IsNull, nil)
+	criteria, err := rbac.RestrictCriteria(ctx, dafi.Where("id", dafi.Equal, id).And("deleted_at", dafi.IsNull, nil), visibility)
+	if err != nil {
+		return entity.User{}, err
+	}
 
 	user, err := u.repo.Find(ctx, criteria)
 	if err != nil {
@@ -73,6 +139,10 @@ func (u *UserUseCase) ListUsers(ctx context.Context, criteria dafi.Criteria) (ty
 	defer span.End()
 
 	criteria.Filters = criteria.Filters.And("deleted_at", dafi.IsNull, nil)
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, visibility)
+	if err != nil {
+		return types.List[entity.User]{}, err
+	}
 
 	users, err := u.repo.List(ctx, criteria)
 	if err != nil {
@@ -86,10 +156,6 @@ func (u *UserUseCase) UpdateUser(ctx context.Context, req entity.UpdateUserReque
 	ctx, span := u.tracer.Start(ctx, "UpdateUser")
 	defer span.End()
 
-	if err := req.Validate(); err != nil {
-		return entity.User{}, fault.Wrap(err).Code(fault.BadRequest).Message("validation failed")
-	}
-
 	user, err := u.GetUserByID(ctx, req.ID)
 	if err != nil {
 		return entity.User{}, fault.Wrap(err).Message("failed to get user for update")
@@ -107,17 +173,22 @@ func (u *UserUseCase) UpdateUser(ctx context.Context, req entity.UpdateUserReque
 	if req.Picture.Valid {
 		user.Picture = req.Picture
 	}
-	if req.IsActive.Valid {
-		user.IsActive = req.IsActive.Bool
-	}
 	user.UpdatedAt = entity.NewNullTime(time.Now())
-	user.UpdatedBy = req.UpdatedBy
+	user.UpdatedBy = auth.ActorID(ctx)
 
 	filters := dafi.FilterBy("id", dafi.Equal, req.ID)
 	if err := u.repo.Update(ctx, user, filters...); err != nil {
 		return entity.User{}, fault.Wrap(err).Message("failed to update user")
 	}
 
+	// Status changes cascade like ActivateUser and DeactivateUser
+	if req.IsActive.Valid && req.IsActive.Bool != user.IsActive {
+		if _, err := u.setActive(ctx, req.IsActive.Bool, filters); err != nil {
+			return entity.User{}, err
+		}
+		user.IsActive = req.IsActive.Bool
+	}
+
 	return user, nil
 }
 
@@ -125,13 +196,11 @@ func (u *UserUseCase) DeleteUser(ctx context.Context, req entity.DeleteUserReque
 	ctx, span := u.tracer.Start(ctx, "DeleteUser")
 	defer span.End()
 
-	if err := req.Validate(); err != nil {
-		return fault.Wrap(err).Code(fault.BadRequest).Message("validation failed")
+	filters, err := rbac.Restrict(ctx, dafi.FilterBy("id", dafi.Equal, req.ID).And("deleted_at", dafi.IsNull, nil), visibility)
+	if err != nil {
+		return err
 	}
 
-	filters := dafi.FilterBy("id", dafi.Equal, req.ID).
-		And("deleted_at", dafi.IsNull, nil)
-
 	if err := u.repo.Delete(ctx, filters...); err != nil {
 		return fault.Wrap(err).Message("failed to delete user")
 	}
@@ -143,8 +212,10 @@ func (u *UserUseCase) ExistsUser(ctx context.Context, id uuid.UUID) (bool, error
 	ctx, span := u.tracer.Start(ctx, "ExistsUser")
 	defer span.End()
 
-	criteria := dafi.Where("id", dafi.Equal, id).
-		And("deleted_at", dafi.IsNull, nil)
+	criteria, err := rbac.RestrictCriteria(ctx, dafi.Where("id", dafi.Equal, id).And("deleted_at", dafi.IsNull, nil), visibility)
+	if err != nil {
+		return false, err
+	}
 
 	exists, err := u.repo.Exists(ctx, criteria)
 	if err != nil {
@@ -159,6 +230,10 @@ func (u *UserUseCase) CountUsers(ctx context.Context, criteria dafi.Criteria) (i
 	defer span.End()
 
 	criteria.Filters = criteria.Filters.And("deleted_at", dafi.IsNull, nil)
+	criteria, err := rbac.RestrictCriteria(ctx, criteria, visibility)
+	if err != nil {
+		return 0, err
+	}
 
 	count, err := u.repo.Count(ctx, criteria)
 	if err != nil {
@@ -166,4 +241,194 @@ func (u *UserUseCase) CountUsers(ctx context.Context, criteria dafi.Criteria) (i
 	}
 
 	return count, nil
-}
\ No newline at end of file
+}
+
+// GetMe returns the user of the caller. Users always see themselves,
+// whatever the visibility of their permissions.
+func (u *UserUseCase) GetMe(ctx context.Context) (entity.User, error) {
+	ctx, span := u.tracer.Start(ctx, "GetMe")
+	defer span.End()
+
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return entity.User{}, fault.New("authentication required").Code(fault.Unauthorized)
+	}
+
+	user, err := u.repo.Find(ctx, dafi.Where("id", dafi.Equal, principal.UserID).And("deleted_at", dafi.IsNull, nil))
+	if err != nil {
+		return entity.User{}, fault.Wrap(err).Message("failed to get current user")
+	}
+
+	return user, nil
+}
+
+// UpdateMe updates the profile of the caller. Only the name, picture and
+// locale may be edited this way; the origin and active status are left to
+// admins.
+func (u *UserUseCase) UpdateMe(ctx context.Context, req entity.UpdateMeRequest) (entity.User, error) {
+	ctx, span := u.tracer.Start(ctx, "UpdateMe")
+	defer span.End()
+
+	user, err := u.GetMe(ctx)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	if req.FirstName.Valid {
+		user.FirstName = req.FirstName.String
+	}
+	if req.LastName.Valid {
+		user.LastName = entity.NewNullString(req.LastName.String)
+	}
+	if req.Picture.Valid {
+		user.Picture = entity.NewNullString(req.Picture.String)
+	}
+	if req.Locale.Valid {
+		locale := i18n.Normalize(req.Locale.String)
+		if err := checkLocale(ctx, locale); err != nil {
+			return entity.User{}, err
+		}
+		user.Locale = entity.NewNullString(string(locale))
+	}
+	user.UpdatedAt = entity.NewNullTime(time.Now())
+	user.UpdatedBy = &user.ID
+
+	filters := dafi.FilterBy("id", dafi.Equal, user.ID)
+	if err := u.repo.Update(ctx, user, filters...); err != nil {
+		return entity.User{}, fault.Wrap(err).Message("failed to update current user")
+	}
+	u.locales.forget(user.ID)
+
+	return user, nil
+}
+
+// MyPermissions returns the effective permissions of the caller in the
+// organization of the request, see MyPermissions
+func (u *UserUseCase) MyPermissions(ctx context.Context) (entity.MyPermissions, error) {
+	ctx, span := u.tracer.Start(ctx, "MyPermissions")
+	defer span.End()
+
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return entity.MyPermissions{}, fault.New("authentication required").Code(fault.Unauthorized)
+	}
+
+	permissions, err := u.authorizer.Permissions(ctx, principal)
+	if err != nil {
+		return entity.MyPermissions{}, fault.Wrap(err).Message("failed to load permissions")
+	}
+
+	result := entity.MyPermissions{
+		OrganizationID: principal.OrganizationID,
+		Roles:          append([]string{}, permissions.Roles...),
+		Permissions:    make(map[string]string, len(permissions.Actions)),
+		Organizations:  append([]uuid.UUID{}, permissions.Organizations...),
+		Root:           permissions.Root,
+	}
+	for code, scope := range permissions.Actions {
+		result.Permissions[code] = string(scope)
+	}
+
+	return result, nil
+}
+
+// UploadAvatar sets the picture of the caller to a square thumbnail of the
+// image, replacing the avatar uploaded before
+func (u *UserUseCase) UploadAvatar(ctx context.Context, image io.Reader) (entity.User, error) {
+	ctx, span := u.tracer.Start(ctx, "UploadAvatar")
+	defer span.End()
+
+	user, err := u.GetMe(ctx)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	file, err := blob.Read(image, u.config.AvatarLimits)
+	if err != nil {
+		return entity.User{}, err
+	}
+	thumbnail, err := blob.Thumbnail(file.Data, u.config.AvatarSize)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	name := uuid.NewString() + blob.Extension(thumbnail.ContentType)
+	key := avatarPrefix + user.ID.String() + "/" + name
+	if err := u.blobs.Put(ctx, thumbnail.Blob(key), thumbnail.Reader()); err != nil {
+		return entity.User{}, fault.Wrap(err).Message("failed to store avatar")
+	}
+
+	previous, hadAvatar := u.avatarKey(user)
+	user.Picture = entity.NewNullString(u.config.AvatarURL + "/" + user.ID.String() + "/" + name)
+	user.UpdatedAt = entity.NewNullTime(time.Now())
+	user.UpdatedBy = &user.ID
+
+	if err := u.repo.Update(ctx, user, dafi.FilterBy("id", dafi.Equal, user.ID)...); err != nil {
+		u.removeAvatar(context.WithoutCancel(ctx), key)
+		return entity.User{}, fault.Wrap(err).Message("failed to update current user")
+	}
+
+	if hadAvatar {
+		u.removeAvatar(ctx, previous)
+	}
+	return user, nil
+}
+
+// DeleteAvatar clears the picture of the caller, deleting the avatar it
+// links to
+func (u *UserUseCase) DeleteAvatar(ctx context.Context) (entity.User, error) {
+	ctx, span := u.tracer.Start(ctx, "DeleteAvatar")
+	defer span.End()
+
+	user, err := u.GetMe(ctx)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	previous, hadAvatar := u.avatarKey(user)
+	user.Picture = entity.NewNullString("")
+	user.UpdatedAt = entity.NewNullTime(time.Now())
+	user.UpdatedBy = &user.ID
+
+	if err := u.repo.Update(ctx, user, dafi.FilterBy("id", dafi.Equal, user.ID)...); err != nil {
+		return entity.User{}, fault.Wrap(err).Message("failed to update current user")
+	}
+
+	if hadAvatar {
+		u.removeAvatar(ctx, previous)
+	}
+	return user, nil
+}
+
+// AvatarURL returns a signed URL of the avatar. Avatars are not secret:
+// anyone with the link of a picture may download it.
+func (u *UserUseCase) AvatarURL(ctx context.Context, req entity.AvatarRequest) (entity.SignedURL, error) {
+	ctx, span := u.tracer.Start(ctx, "AvatarURL")
+	defer span.End()
+
+	expiresAt := time.Now().Add(u.config.URLExpiry)
+	signed, err := u.blobs.SignedURL(ctx, avatarPrefix+req.UserID.String()+"/"+req.Name, u.config.URLExpiry)
+	if err != nil {
+		return entity.SignedURL{}, fault.Wrap(err).Message("failed to sign avatar URL")
+	}
+	return entity.SignedURL{URL: signed, ExpiresAt: expiresAt}, nil
+}
+
+// avatarKey returns the key of the avatar the picture of the user links
+// to, unless it links elsewhere, e.g. to the picture of an identity
+// provider
+func (u *UserUseCase) avatarKey(user entity.User) (string, bool) {
+	prefix := u.config.AvatarURL + "/" + user.ID.String() + "/"
+	if !user.Picture.Valid || !strings.HasPrefix(user.Picture.String, prefix) {
+		return "", false
+	}
+	return avatarPrefix + user.ID.String() + "/" + strings.TrimPrefix(user.Picture.String, prefix), true
+}
+
+// removeAvatar deletes a replaced avatar. A failure only leaves an unused
+// file behind, so it is recorded instead of failing the request.
+func (u *UserUseCase) removeAvatar(ctx context.Context, key string) {
+	if err := u.blobs.Delete(ctx, key); err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+	}
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"mime/multipart"
+
 	"github.com/google/uuid"
 	"gopkg.in/guregu/null.v4"
 
@@ -8,24 +10,26 @@ import (
 )
 
 type CreateUserRequest struct {
-	Origin    string     `json:"origin" validate:"required,max=50"`
-	FirstName string     `json:"first_name" validate:"required,max=100"`
-	LastName  string     `json:"last_name,omitempty" validate:"omitempty,max=100"`
-	Picture   string     `json:"picture,omitempty"`
-	IsActive  bool       `json:"is_active"`
-	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	Origin    string `json:"origin" validate:"required,max=50"`
+	FirstName string `json:"first_name" validate:"required,max=100"`
+	LastName  string `json:"last_name,omitempty" validate:"omitempty,max=100"`
+	Picture   string `json:"picture,omitempty"`
+	IsActive  bool   `json:"is_active"`
 }
 
-func (r CreateUserRequest) Validate() error {
-	schema := valid.Object(map[string]valid.Schema{
+// Schema describes the request body. It is used both to validate and to
+// document the request in the OpenAPI spec.
+func (r CreateUserRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
 		"origin":     valid.String().MaxLength(50).Required(),
 		"first_name": valid.String().MaxLength(100).Required(),
 		"last_name":  valid.String().MaxLength(100),
-		"picture":    valid.String(),
-		"created_by": valid.String().UUID(),
+		"picture":    valid.String().URL().MaxLength(2048),
 	})
-	
-	result := schema.Parse(r)
+}
+
+func (r CreateUserRequest) Validate() error {
+	result := r.Schema().Parse(r)
 	if !result.Success {
 		return &result.Errors[0]
 	}
@@ -33,26 +37,26 @@ func (r CreateUserRequest) Validate() error {
 }
 
 type UpdateUserRequest struct {
-	ID        uuid.UUID   `json:"id" validate:"required,uuid"`
+	ID        uuid.UUID   `json:"id" param:"id" validate:"required,uuid"`
 	Origin    null.String `json:"origin,omitempty" validate:"omitempty,max=50"`
 	FirstName null.String `json:"first_name,omitempty" validate:"omitempty,max=100"`
 	LastName  null.String `json:"last_name,omitempty" validate:"omitempty,max=100"`
 	Picture   null.String `json:"picture,omitempty"`
 	IsActive  null.Bool   `json:"is_active,omitempty"`
-	UpdatedBy *uuid.UUID  `json:"updated_by,omitempty"`
 }
 
-func (r UpdateUserRequest) Validate() error {
-	schema := valid.Object(map[string]valid.Schema{
+func (r UpdateUserRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
 		"id":         valid.String().UUID().Required(),
 		"origin":     valid.String().MaxLength(50),
 		"first_name": valid.String().MaxLength(100),
 		"last_name":  valid.String().MaxLength(100),
-		"picture":    valid.String(),
-		"updated_by": valid.String().UUID(),
+		"picture":    valid.String().URL().MaxLength(2048),
 	})
-	
-	result := schema.Parse(r)
+}
+
+func (r UpdateUserRequest) Validate() error {
+	result := r.Schema().Parse(r)
 	if !result.Success {
 		return &result.Errors[0]
 	}
@@ -60,20 +64,109 @@ func (r UpdateUserRequest) Validate() error {
 }
 
 type DeleteUserRequest struct {
-	ID        uuid.UUID `json:"id" validate:"required,uuid"`
-	DeletedBy uuid.UUID `json:"deleted_by" validate:"required,uuid"`
+	ID uuid.UUID `json:"id" param:"id" validate:"required,uuid"`
+}
+
+func (r DeleteUserRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"id": valid.String().UUID().Required(),
+	})
 }
 
 func (r DeleteUserRequest) Validate() error {
-	schema := valid.Object(map[string]valid.Schema{
-		"id":         valid.String().UUID().Required(),
-		"deleted_by": valid.String().UUID().Required(),
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// UpdateMeRequest holds the profile fields users may edit themselves.
+// Omitted fields are left as they are; an empty last name, picture or
+// locale clears it. Pictures must be http or https URLs, as clients render
+// them.
+type UpdateMeRequest struct {
+	FirstName null.String `json:"first_name,omitempty"`
+	LastName  null.String `json:"last_name,omitempty"`
+	Picture   null.String `json:"picture,omitempty"`
+	// Locale is a language tag such as "es" or "en-US", stored as its
+	// primary language, which must have a message catalog
+	Locale null.String `json:"locale,omitempty"`
+}
+
+func (r UpdateMeRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"first_name": valid.String().Length(1, 100),
+		"last_name":  valid.String().MaxLength(100),
+		"picture":    valid.String().URL().MaxLength(2048),
+		"locale":     valid.String().MaxLength(35),
 	})
-	
-	result := schema.Parse(r)
+}
+
+func (r UpdateMeRequest) Validate() error {
+	result := r.Schema().Parse(r)
 	if !result.Success {
 		return &result.Errors[0]
 	}
 	return nil
 }
 
+// UploadAvatarRequest is the multipart/form-data body of an avatar upload.
+type UploadAvatarRequest struct {
+	File *multipart.FileHeader `json:"-" form:"file"`
+}
+
+func (r UploadAvatarRequest) Validate() error {
+	if r.File == nil {
+		return &valid.ValidationError{Path: "file", Message: "file is required", Code: "required"}
+	}
+	return nil
+}
+
+// AvatarRequest identifies an avatar by the path of its URL, see
+// User.Picture.
+type AvatarRequest struct {
+	UserID uuid.UUID `param:"id"`
+	Name   string    `param:"name"`
+}
+
+// BulkStatusRequest selects the users of a bulk activation or
+// deactivation with the filters of the list endpoint. At least one filter
+// is required, so every user is not changed by mistake.
+type BulkStatusRequest struct {
+	UserFilter
+}
+
+func (r BulkStatusRequest) Validate() error {
+	if len(r.Criteria().Filters) == 0 {
+		return &valid.ValidationError{Message: "at least one filter is required", Code: "required"}
+	}
+	return nil
+}
+
+// MergeUsersRequest merges the duplicate user into the canonical one, the
+// user of the path.
+type MergeUsersRequest struct {
+	ID          uuid.UUID `json:"-" param:"id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (r MergeUsersRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"duplicate_id": valid.String().UUID().Required(),
+	})
+}
+
+func (r MergeUsersRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	if r.DuplicateID == uuid.Nil {
+		return &valid.ValidationError{Path: "duplicate_id", Message: "duplicate_id is required", Code: "required"}
+	}
+	if r.DuplicateID == r.ID {
+		return &valid.ValidationError{Path: "duplicate_id", Message: "a user cannot be merged into itself", Code: "invalid"}
+	}
+	return nil
+}
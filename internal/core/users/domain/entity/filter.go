@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// UserSortFields are the fields users can be sorted by
+var UserSortFields = []string{"id", "origin", "first_name", "last_name", "is_active", "created_at", "updated_at"}
+
+// UserFilter holds the filters accepted by the list and count endpoints
+type UserFilter struct {
+	Origin    string     `json:"origin,omitempty" query:"origin"`
+	FirstName string     `json:"first_name,omitempty" query:"first_name"`
+	LastName  string     `json:"last_name,omitempty" query:"last_name"`
+	IsActive  *bool      `json:"is_active,omitempty" query:"is_active"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" query:"created_by"`
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty" query:"updated_by"`
+}
+
+// Criteria returns the filters as dafi criteria. Names match partially.
+func (f UserFilter) Criteria() dafi.Criteria {
+	criteria := dafi.New()
+
+	if f.Origin != "" {
+		criteria = criteria.And("origin", dafi.Equal, f.Origin)
+	}
+	if f.FirstName != "" {
+		criteria = criteria.And("first_name", dafi.Like, "%"+f.FirstName+"%")
+	}
+	if f.LastName != "" {
+		criteria = criteria.And("last_name", dafi.Like, "%"+f.LastName+"%")
+	}
+	if f.IsActive != nil {
+		criteria = criteria.And("is_active", dafi.Equal, *f.IsActive)
+	}
+	if f.CreatedBy != nil {
+		criteria = criteria.And("created_by", dafi.Equal, *f.CreatedBy)
+	}
+	if f.UpdatedBy != nil {
+		criteria = criteria.And("updated_by", dafi.Equal, *f.UpdatedBy)
+	}
+
+	return criteria
+}
+
+// ListUsersRequest adds pagination and sorting to UserFilter
+type ListUsersRequest struct {
+	UserFilter
+	Page      uint   `json:"page,omitempty" query:"page"`
+	PageSize  uint   `json:"page_size,omitempty" query:"page_size"`
+	SortBy    string `json:"sort_by,omitempty" query:"sort_by"`
+	SortOrder string `json:"sort_order,omitempty" query:"sort_order"`
+}
+
+func (r ListUsersRequest) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"page":       valid.Int().Min(1),
+		"page_size":  valid.Int().Range(1, MaxPageSize),
+		"sort_by":    valid.Enum(UserSortFields...),
+		"sort_order": valid.Enum("asc", "desc").CaseInsensitive(),
+	})
+}
+
+func (r ListUsersRequest) Validate() error {
+	result := r.Schema().Parse(r)
+	if !result.Success {
+		return &result.Errors[0]
+	}
+	return nil
+}
+
+// Criteria returns the filters, page and sort as dafi criteria. The first
+// page of DefaultPageSize users is returned when no page is given.
+func (r ListUsersRequest) Criteria() dafi.Criteria {
+	page, pageSize := r.Page, r.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	criteria := r.UserFilter.Criteria().Page(page).Limit(pageSize)
+
+	if r.SortBy != "" {
+		if strings.EqualFold(r.SortOrder, "desc") {
+			criteria = criteria.SortBy(r.SortBy, dafi.Desc)
+		} else {
+			criteria = criteria.SortBy(r.SortBy, dafi.Asc)
+		}
+	}
+
+	return criteria
+}
@@ -13,6 +13,9 @@ type User struct {
 	FirstName string      `json:"first_name" db:"first_name"`
 	LastName  null.String `json:"last_name" db:"last_name"`
 	Picture   null.String `json:"picture" db:"picture"`
+	// Locale is the language the user prefers API messages in, if they
+	// chose one
+	Locale    null.String `json:"locale" db:"locale"`
 	IsActive  bool        `json:"is_active" db:"is_active"`
 	CreatedAt time.Time   `json:"created_at" db:"created_at"`
 	CreatedBy *uuid.UUID  `json:"created_by" db:"created_by"`
@@ -31,4 +34,50 @@ func NewNullString(s string) null.String {
 
 func NewNullTime(t time.Time) null.Time {
 	return null.TimeFrom(t)
-}
\ No newline at end of file
+}
+
+// MyPermissions summarizes what the caller may do in the organization of
+// the request, for clients to show or hide features. Routes are still
+// authorized on each request.
+type MyPermissions struct {
+	// OrganizationID is the organization the permissions apply to, if the
+	// request acts on one
+	OrganizationID uuid.NullUUID `json:"organization_id"`
+	Roles          []string      `json:"roles"`
+	// Permissions maps the granted action codes to their visibility scope
+	Permissions   map[string]string `json:"permissions"`
+	Organizations []uuid.UUID       `json:"organizations"`
+	// Root is set for members of the root organization, who may act on
+	// any organization
+	Root bool `json:"root"`
+}
+
+// SignedURL is a download URL that works without credentials until
+// ExpiresAt.
+type SignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// StatusChange reports the users an activation or deactivation changed.
+// Users already in the requested status are left out. Deactivation also
+// reports what it revoked; activation the memberships it restored.
+type StatusChange struct {
+	UserIDs     []uuid.UUID `json:"user_ids"`
+	Sessions    int         `json:"sessions"`
+	APIKeys     int64       `json:"api_keys"`
+	Memberships int64       `json:"memberships"`
+}
+
+// MergeResult reports what a merge moved from the duplicate user to the
+// canonical one.
+type MergeResult struct {
+	User User `json:"user"`
+	// References counts the created_by and updated_by columns re-pointed
+	References int64 `json:"references"`
+	// Memberships and Roles count the organization memberships and role
+	// assignments moved. Those the canonical user already had are merged
+	// into theirs.
+	Memberships int64 `json:"memberships"`
+	Roles       int64 `json:"roles"`
+}
@@ -1,20 +1,29 @@
 package presentation
 
 import (
-	"net/http"
-	"strconv"
+	"context"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/labstack/echo/v4"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 
 	"api.system.soluciones-cloud.com/internal/core/users/domain/entity"
-	"api.system.soluciones-cloud.com/internal/shared/dafi"
 	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/http/server"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
 	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/types"
 )
 
+// UserIDRequest binds the user id path parameter
+type UserIDRequest struct {
+	ID uuid.UUID `param:"id"`
+}
+
+// UserHandler exposes the user use cases over HTTP. Its methods are
+// adapted to echo handlers with server.Handle, which binds, validates and
+// renders the responses.
 type UserHandler struct {
 	usecase ports.UserUseCase
 	tracer  trace.Tracer
@@ -27,440 +36,170 @@ func NewUserHandler(usecase ports.UserUseCase) *UserHandler {
 	}
 }
 
-// CreateUser godoc
-// @Summary Create a new user
-// @Description Create a new user with the provided information
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param user body entity.CreateUserRequest true "User creation request"
-// @Success 201 {object} entity.User
-// @Failure 400 {object} map[string]any
-// @Failure 500 {object} map[string]any
-// @Router /users [post]
-func (h *UserHandler) CreateUser(c echo.Context) error {
-	ctx, span := h.tracer.Start(c.Request().Context(), "UserHandler.CreateUser")
+// CreateUser creates a new user with the provided information
+func (h *UserHandler) CreateUser(ctx context.Context, req entity.CreateUserRequest) (entity.User, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.CreateUser")
 	defer span.End()
 
-	var req entity.CreateUserRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]any{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
-	}
-
-	user, err := h.usecase.CreateUser(ctx, req)
-	if err != nil {
-		if faultErr, ok := err.(*fault.Error); ok && faultErr.HTTPStatus() == http.StatusBadRequest {
-			return c.JSON(http.StatusBadRequest, map[string]any{
-				"error":   "validation failed",
-				"details": err.Error(),
-			})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]any{
-			"error":   "failed to create user",
-			"details": err.Error(),
-		})
-	}
-
-	return c.JSON(http.StatusCreated, user)
+	return h.usecase.CreateUser(ctx, req)
 }
 
-// GetUser godoc
-// @Summary Get user by ID
-// @Description Get a user by its ID
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param id path string true "User ID"
-// @Success 200 {object} entity.User
-// @Failure 400 {object} map[string]any
-// @Failure 404 {object} map[string]any
-// @Failure 500 {object} map[string]any
-// @Router /users/{id} [get]
-func (h *UserHandler) GetUser(c echo.Context) error {
-	ctx, span := h.tracer.Start(c.Request().Context(), "UserHandler.GetUser")
+// GetUser gets a user by its ID
+func (h *UserHandler) GetUser(ctx context.Context, req UserIDRequest) (entity.User, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.GetUser")
 	defer span.End()
 
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]any{
-			"error":   "invalid user ID",
-			"details": err.Error(),
-		})
-	}
-
-	user, err := h.usecase.GetUserByID(ctx, id)
-	if err != nil {
-		if faultErr, ok := err.(*fault.Error); ok && faultErr.HTTPStatus() == http.StatusNotFound {
-			return c.JSON(http.StatusNotFound, map[string]any{
-				"error":   "user not found",
-				"details": err.Error(),
-			})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]any{
-			"error":   "failed to get user",
-			"details": err.Error(),
-		})
-	}
-
-	return c.JSON(http.StatusOK, user)
+	return h.usecase.GetUserByID(ctx, req.ID)
 }
 
-// ListUsers godoc
-// @Summary List users
-// @Description List users with optional filtering, sorting, and pagination
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param origin query string false "Filter by origin"
-// @Param first_name query string false "Filter by first name (partial match)"
-// @Param last_name query string false "Filter by last name (partial match)"
-// @Param is_active query boolean false "Filter by active status"
-// @Param page query int false "Page number (default: 1)"
-// @Param page_size query int false "Page size (default: 10)"
-// @Param sort_by query string false "Sort by field"
-// @Param sort_order query string false "Sort order (asc/desc)"
-// @Success 200 {object} []entity.User
-// @Failure 400 {object} map[string]any
-// @Failure 500 {object} map[string]any
-// @Router /users [get]
-func (h *UserHandler) ListUsers(c echo.Context) error {
-	ctx, span := h.tracer.Start(c.Request().Context(), "UserHandler.ListUsers")
+// ListUsers lists users with optional filtering, sorting, and pagination
+func (h *UserHandler) ListUsers(ctx context.Context, req entity.ListUsersRequest) (types.List[entity.User], error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.ListUsers")
 	defer span.End()
 
-	criteria := dafi.New()
-
-	// Add filters based on query parameters
-	if origin := c.QueryParam("origin"); origin != "" {
-		criteria = criteria.And("origin", dafi.Equal, origin)
-	}
-
-	if firstName := c.QueryParam("first_name"); firstName != "" {
-		criteria = criteria.And("first_name", dafi.Like, "%"+firstName+"%")
-	}
+	return h.usecase.ListUsers(ctx, req.Criteria())
+}
 
-	if lastName := c.QueryParam("last_name"); lastName != "" {
-		criteria = criteria.And("last_name", dafi.Like, "%"+lastName+"%")
-	}
+// UpdateUser updates an existing user with the provided information
+func (h *UserHandler) UpdateUser(ctx context.Context, req entity.UpdateUserRequest) (entity.User, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.UpdateUser")
+	defer span.End()
 
-	if isActiveParam := c.QueryParam("is_active"); isActiveParam != "" {
-		isActive, err := strconv.ParseBool(isActiveParam)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]any{
-				"error":   "invalid is_active parameter",
-				"details": err.Error(),
-			})
-		}
-		criteria = criteria.And("is_active", dafi.Equal, isActive)
-	}
+	return h.usecase.UpdateUser(ctx, req)
+}
 
-	if createdByParam := c.QueryParam("created_by"); createdByParam != "" {
-		createdBy, err := uuid.Parse(createdByParam)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]any{
-				"error":   "invalid created_by parameter",
-				"details": err.Error(),
-			})
-		}
-		criteria = criteria.And("created_by", dafi.Equal, createdBy)
-	}
+// DeleteUser soft deletes a user by ID
+func (h *UserHandler) DeleteUser(ctx context.Context, req entity.DeleteUserRequest) (server.NoContent, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.DeleteUser")
+	defer span.End()
 
-	if updatedByParam := c.QueryParam("updated_by"); updatedByParam != "" {
-		updatedBy, err := uuid.Parse(updatedByParam)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]any{
-				"error":   "invalid updated_by parameter",
-				"details": err.Error(),
-			})
-		}
-		criteria = criteria.And("updated_by", dafi.Equal, updatedBy)
-	}
+	return server.NoContent{}, h.usecase.DeleteUser(ctx, req)
+}
 
-	// Handle pagination
-	page := uint(1)
-	if pageParam := c.QueryParam("page"); pageParam != "" {
-		p, err := strconv.ParseUint(pageParam, 10, 32)
-		if err != nil || p < 1 {
-			return c.JSON(http.StatusBadRequest, map[string]any{
-				"error":   "invalid page parameter",
-				"details": "page must be a positive integer",
-			})
-		}
-		page = uint(p)
-	}
+// UserExists checks if a user exists by ID
+func (h *UserHandler) UserExists(ctx context.Context, req UserIDRequest) (response.ExistsResponse, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.UserExists")
+	defer span.End()
 
-	pageSize := uint(10)
-	if pageSizeParam := c.QueryParam("page_size"); pageSizeParam != "" {
-		ps, err := strconv.ParseUint(pageSizeParam, 10, 32)
-		if err != nil || ps < 1 {
-			return c.JSON(http.StatusBadRequest, map[string]any{
-				"error":   "invalid page_size parameter",
-				"details": "page_size must be a positive integer",
-			})
-		}
-		pageSize = uint(ps)
+	exists, err := h.usecase.ExistsUser(ctx, req.ID)
+	if err != nil {
+		return response.ExistsResponse{}, err
 	}
 
-	criteria = criteria.Page(page).Limit(pageSize)
+	return response.ExistsResponse{Exists: exists}, nil
+}
 
-	// Handle sorting
-	if sortBy := c.QueryParam("sort_by"); sortBy != "" {
-		sortOrder := c.QueryParam("sort_order")
-		if sortOrder == "desc" {
-			criteria = criteria.SortBy(sortBy, dafi.Desc)
-		} else {
-			criteria = criteria.SortBy(sortBy, dafi.Asc)
-		}
-	}
+// CountUsers counts users with optional filtering
+func (h *UserHandler) CountUsers(ctx context.Context, req entity.UserFilter) (response.CountResponse, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.CountUsers")
+	defer span.End()
 
-	users, err := h.usecase.ListUsers(ctx, criteria)
+	count, err := h.usecase.CountUsers(ctx, req.Criteria())
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]any{
-			"error":   "failed to list users",
-			"details": err.Error(),
-		})
+		return response.CountResponse{}, err
 	}
 
-	return c.JSON(http.StatusOK, users)
+	return response.CountResponse{Count: count}, nil
 }
 
-// UpdateUser godoc
-// @Summary Update user
-// @Description Update an existing user with the provided information
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param id path string true "User ID"
-// @Param user body entity.UpdateUserRequest true "User update request"
-// @Success 200 {object} entity.User
-// @Failure 400 {object} map[string]any
-// @Failure 404 {object} map[string]any
-// @Failure 500 {object} map[string]any
-// @Router /users/{id} [put]
-func (h *UserHandler) UpdateUser(c echo.Context) error {
-	ctx, span := h.tracer.Start(c.Request().Context(), "UserHandler.UpdateUser")
+// GetMe gets the user of the caller
+func (h *UserHandler) GetMe(ctx context.Context, _ struct{}) (entity.User, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.GetMe")
 	defer span.End()
 
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]any{
-			"error":   "invalid user ID",
-			"details": err.Error(),
-		})
-	}
+	return h.usecase.GetMe(ctx)
+}
 
-	var req entity.UpdateUserRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]any{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
-	}
+// UpdateMe updates the profile of the caller
+func (h *UserHandler) UpdateMe(ctx context.Context, req entity.UpdateMeRequest) (entity.User, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.UpdateMe")
+	defer span.End()
 
-	req.ID = id
+	return h.usecase.UpdateMe(ctx, req)
+}
 
-	user, err := h.usecase.UpdateUser(ctx, req)
-	if err != nil {
-		if faultErr, ok := err.(*fault.Error); ok {
-			switch faultErr.HTTPStatus() {
-			case http.StatusBadRequest:
-				return c.JSON(http.StatusBadRequest, map[string]any{
-					"error":   "validation failed",
-					"details": err.Error(),
-				})
-			case http.StatusNotFound:
-				return c.JSON(http.StatusNotFound, map[string]any{
-					"error":   "user not found",
-					"details": err.Error(),
-				})
-			}
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]any{
-			"error":   "failed to update user",
-			"details": err.Error(),
-		})
-	}
+// MyPermissions returns the effective permissions of the caller
+func (h *UserHandler) MyPermissions(ctx context.Context, _ struct{}) (entity.MyPermissions, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.MyPermissions")
+	defer span.End()
 
-	return c.JSON(http.StatusOK, user)
+	return h.usecase.MyPermissions(ctx)
 }
 
-// DeleteUser godoc
-// @Summary Delete user
-// @Description Soft delete a user by ID
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param id path string true "User ID"
-// @Param user body entity.DeleteUserRequest true "Delete request with deleted_by"
-// @Success 204
-// @Failure 400 {object} map[string]any
-// @Failure 404 {object} map[string]any
-// @Failure 500 {object} map[string]any
-// @Router /users/{id} [delete]
-func (h *UserHandler) DeleteUser(c echo.Context) error {
-	ctx, span := h.tracer.Start(c.Request().Context(), "UserHandler.DeleteUser")
+// UploadAvatar replaces the picture of the caller with the uploaded image
+func (h *UserHandler) UploadAvatar(ctx context.Context, req entity.UploadAvatarRequest) (entity.User, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.UploadAvatar")
 	defer span.End()
 
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	file, err := req.File.Open()
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]any{
-			"error":   "invalid user ID",
-			"details": err.Error(),
-		})
-	}
-
-	var req entity.DeleteUserRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]any{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
+		return entity.User{}, fault.Wrap(err).Code(fault.BadRequest).Message("failed to read file")
 	}
+	defer file.Close()
 
-	req.ID = id
+	return h.usecase.UploadAvatar(ctx, file)
+}
 
-	err = h.usecase.DeleteUser(ctx, req)
-	if err != nil {
-		if faultErr, ok := err.(*fault.Error); ok {
-			switch faultErr.HTTPStatus() {
-			case http.StatusBadRequest:
-				return c.JSON(http.StatusBadRequest, map[string]any{
-					"error":   "validation failed",
-					"details": err.Error(),
-				})
-			case http.StatusNotFound:
-				return c.JSON(http.StatusNotFound, map[string]any{
-					"error":   "user not found",
-					"details": err.Error(),
-				})
-			}
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]any{
-			"error":   "failed to delete user",
-			"details": err.Error(),
-		})
-	}
+// DeleteAvatar removes the picture of the caller
+func (h *UserHandler) DeleteAvatar(ctx context.Context, _ struct{}) (entity.User, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.DeleteAvatar")
+	defer span.End()
 
-	return c.NoContent(http.StatusNoContent)
+	return h.usecase.DeleteAvatar(ctx)
 }
 
-// UserExists godoc
-// @Summary Check if user exists
-// @Description Check if a user exists by ID
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param id path string true "User ID"
-// @Success 200 {object} map[string]bool
-// @Failure 400 {object} map[string]any
-// @Failure 500 {object} map[string]any
-// @Router /users/{id}/exists [get]
-func (h *UserHandler) UserExists(c echo.Context) error {
-	ctx, span := h.tracer.Start(c.Request().Context(), "UserHandler.UserExists")
+// GetAvatar redirects to a signed URL of the avatar. The redirect is cached
+// for half the lifetime of the URL.
+func (h *UserHandler) GetAvatar(ctx context.Context, req entity.AvatarRequest) (server.Redirect, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.GetAvatar")
 	defer span.End()
 
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	signed, err := h.usecase.AvatarURL(ctx, req)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]any{
-			"error":   "invalid user ID",
-			"details": err.Error(),
-		})
+		return server.Redirect{}, err
 	}
-
-	exists, err := h.usecase.ExistsUser(ctx, id)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]any{
-			"error":   "failed to check if user exists",
-			"details": err.Error(),
-		})
-	}
-
-	return c.JSON(http.StatusOK, map[string]bool{
-		"exists": exists,
-	})
+	return server.Redirect{URL: signed.URL, MaxAge: time.Until(signed.ExpiresAt) / 2}, nil
 }
 
-// CountUsers godoc
-// @Summary Count users
-// @Description Count users with optional filtering
-// @Tags users
-// @Accept json
-// @Produce json
-// @Param origin query string false "Filter by origin"
-// @Param first_name query string false "Filter by first name (partial match)"
-// @Param last_name query string false "Filter by last name (partial match)"
-// @Param is_active query boolean false "Filter by active status"
-// @Success 200 {object} map[string]int64
-// @Failure 400 {object} map[string]any
-// @Failure 500 {object} map[string]any
-// @Router /users/count [get]
-func (h *UserHandler) CountUsers(c echo.Context) error {
-	ctx, span := h.tracer.Start(c.Request().Context(), "UserHandler.CountUsers")
+// ActivateUser activates a user, restoring the memberships deactivated
+// with them
+func (h *UserHandler) ActivateUser(ctx context.Context, req UserIDRequest) (entity.User, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.ActivateUser")
 	defer span.End()
 
-	criteria := dafi.New()
+	return h.usecase.ActivateUser(ctx, req.ID)
+}
 
-	// Add filters based on query parameters
-	if origin := c.QueryParam("origin"); origin != "" {
-		criteria = criteria.And("origin", dafi.Equal, origin)
-	}
+// DeactivateUser deactivates a user, revoking their sessions and API keys
+// and deactivating their memberships
+func (h *UserHandler) DeactivateUser(ctx context.Context, req UserIDRequest) (entity.User, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.DeactivateUser")
+	defer span.End()
 
-	if firstName := c.QueryParam("first_name"); firstName != "" {
-		criteria = criteria.And("first_name", dafi.Like, "%"+firstName+"%")
-	}
+	return h.usecase.DeactivateUser(ctx, req.ID)
+}
 
-	if lastName := c.QueryParam("last_name"); lastName != "" {
-		criteria = criteria.And("last_name", dafi.Like, "%"+lastName+"%")
-	}
+// ActivateUsers activates the users matching the filters
+func (h *UserHandler) ActivateUsers(ctx context.Context, req entity.BulkStatusRequest) (entity.StatusChange, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.ActivateUsers")
+	defer span.End()
 
-	if isActiveParam := c.QueryParam("is_active"); isActiveParam != "" {
-		isActive, err := strconv.ParseBool(isActiveParam)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]any{
-				"error":   "invalid is_active parameter",
-				"details": err.Error(),
-			})
-		}
-		criteria = criteria.And("is_active", dafi.Equal, isActive)
-	}
+	return h.usecase.ActivateUsers(ctx, req.Criteria())
+}
 
-	if createdByParam := c.QueryParam("created_by"); createdByParam != "" {
-		createdBy, err := uuid.Parse(createdByParam)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]any{
-				"error":   "invalid created_by parameter",
-				"details": err.Error(),
-			})
-		}
-		criteria = criteria.And("created_by", dafi.Equal, createdBy)
-	}
+// DeactivateUsers deactivates the users matching the filters but the
+// caller
+func (h *UserHandler) DeactivateUsers(ctx context.Context, req entity.BulkStatusRequest) (entity.StatusChange, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.DeactivateUsers")
+	defer span.End()
 
-	if updatedByParam := c.QueryParam("updated_by"); updatedByParam != "" {
-		updatedBy, err := uuid.Parse(updatedByParam)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]any{
-				"error":   "invalid updated_by parameter",
-				"details": err.Error(),
-			})
-		}
-		criteria = criteria.And("updated_by", dafi.Equal, updatedBy)
-	}
+	return h.usecase.DeactivateUsers(ctx, req.Criteria())
+}
 
-	count, err := h.usecase.CountUsers(ctx, criteria)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]any{
-			"error":   "failed to count users",
-			"details": err.Error(),
-		})
-	}
+// MergeUsers merges a duplicate user into the user of the path
+func (h *UserHandler) MergeUsers(ctx context.Context, req entity.MergeUsersRequest) (entity.MergeResult, error) {
+	ctx, span := h.tracer.Start(ctx, "UserHandler.MergeUsers")
+	defer span.End()
 
-	return c.JSON(http.StatusOK, map[string]int64{
-		"count": count,
-	})
-}
\ No newline at end of file
+	return h.usecase.MergeUsers(ctx, req)
+}
@@ -3,19 +3,40 @@ package repository
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 
 	"api.system.soluciones-cloud.com/internal/core/users/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
 	"api.system.soluciones-cloud.com/internal/shared/dafi"
 	"api.system.soluciones-cloud.com/internal/shared/fault"
 	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/sqlcraft"
 	"api.system.soluciones-cloud.com/internal/shared/types"
 )
 
+const userColumns = "id, origin, first_name, last_name, picture, locale, is_active, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by"
+
+// sqlColumnByDomainField maps the fields users can be filtered by to their
+// columns
+var sqlColumnByDomainField = map[string]string{
+	"id":         "id",
+	"origin":     "origin",
+	"first_name": "first_name",
+	"last_name":  "last_name",
+	"is_active":  "is_active",
+	"created_at": "created_at",
+	"created_by": "created_by",
+	"updated_at": "updated_at",
+	"updated_by": "updated_by",
+	"deleted_at": "deleted_at",
+}
+
 type UserRepository struct {
 	db     ports.Database
 	tx     ports.Transaction
@@ -109,43 +130,14 @@ func (r *UserRepository) Find(ctx context.Context, criteria dafi.Criteria) (enti
 	ctx, span := r.tracer.Start(ctx, "UserRepository.Find")
 	defer span.End()
 
-	query := `
-		SELECT id, origin, first_name, last_name, picture, is_active, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by
-		FROM auth.users
-		WHERE deleted_at IS NULL
-	`
-
-	// For now, use simple ID lookup - proper DAFI implementation would build the query dynamically
-	var user entity.User
-	executor := r.getExecutor()
-	
-	// Extract ID from criteria for simple implementation
-	var id uuid.UUID
-	for _, filter := range criteria.Filters {
-		if filter.Field == "id" && filter.Operator == dafi.Equal {
-			id = filter.Value.(uuid.UUID)
-			break
-		}
+	where, err := r.where(0, criteria.Filters)
+	if err != nil {
+		return entity.User{}, err
 	}
 
-	query += " AND id = $1"
-	
-	row := executor.QueryRow(ctx, query, id)
-	err := row.Scan(
-		&user.ID,
-		&user.Origin,
-		&user.FirstName,
-		&user.LastName,
-		&user.Picture,
-		&user.IsActive,
-		&user.CreatedAt,
-		&user.CreatedBy,
-		&user.UpdatedAt,
-		&user.UpdatedBy,
-		&user.DeletedAt,
-		&user.DeletedBy,
-	)
+	query := "SELECT " + userColumns + " FROM auth.users" + where.Sql + " LIMIT 1"
 
+	user, err := scanUser(r.getExecutor().QueryRow(ctx, query, where.Args...))
 	if err != nil {
 		return entity.User{}, fault.Wrap(err).Message("failed to find user")
 	}
@@ -157,21 +149,19 @@ func (r *UserRepository) List(ctx context.Context, criteria dafi.Criteria) (type
 	ctx, span := r.tracer.Start(ctx, "UserRepository.List")
 	defer span.End()
 
-	query := `
-		SELECT id, origin, first_name, last_name, picture, is_active, created_at, created_by, updated_at, updated_by, deleted_at, deleted_by
-		FROM auth.users
-		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
-	`
+	where, err := r.where(0, criteria.Filters)
+	if err != nil {
+		return nil, err
+	}
 
-	// Apply pagination if specified
-	if criteria.Pagination.PageSize > 0 {
-		offset := (criteria.Pagination.PageNumber - 1) * criteria.Pagination.PageSize
-		query += fmt.Sprintf(" LIMIT %d OFFSET %d", criteria.Pagination.PageSize, offset)
+	orderBy := " ORDER BY created_at DESC"
+	if !criteria.Sorts.IsZero() {
+		orderBy = sqlcraft.BuildOrderBy(criteria.Sorts)
 	}
 
-	executor := r.getExecutor()
-	rows, err := executor.Query(ctx, query)
+	query := "SELECT " + userColumns + " FROM auth.users" + where.Sql + orderBy + sqlcraft.BuildPagination(criteria.Pagination)
+
+	rows, err := r.getExecutor().Query(ctx, query, where.Args...)
 	if err != nil {
 		return nil, fault.Wrap(err).Message("failed to list users")
 	}
@@ -179,26 +169,15 @@ func (r *UserRepository) List(ctx context.Context, criteria dafi.Criteria) (type
 
 	var users types.List[entity.User]
 	for rows.Next() {
-		var user entity.User
-		err := rows.Scan(
-			&user.ID,
-			&user.Origin,
-			&user.FirstName,
-			&user.LastName,
-			&user.Picture,
-			&user.IsActive,
-			&user.CreatedAt,
-			&user.CreatedBy,
-			&user.UpdatedAt,
-			&user.UpdatedBy,
-			&user.DeletedAt,
-			&user.DeletedBy,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, fault.Wrap(err).Message("failed to scan user")
 		}
 		users = append(users, user)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fault.Wrap(err).Message("failed to list users")
+	}
 
 	return users, nil
 }
@@ -211,7 +190,7 @@ func (r *UserRepository) Update(ctx context.Context, user entity.User, filters .
 
 	query := `
 		UPDATE auth.users
-		SET origin = $2, first_name = $3, last_name = $4, picture = $5, is_active = $6, updated_at = $7, updated_by = $8
+		SET origin = $2, first_name = $3, last_name = $4, picture = $5, locale = $6, is_active = $7, updated_at = $8, updated_by = $9
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
@@ -222,6 +201,7 @@ func (r *UserRepository) Update(ctx context.Context, user entity.User, filters .
 		user.FirstName,
 		user.LastName,
 		user.Picture,
+		user.Locale,
 		user.IsActive,
 		user.UpdatedAt,
 		user.UpdatedBy,
@@ -242,23 +222,15 @@ func (r *UserRepository) Delete(ctx context.Context, filters ...dafi.Filter) err
 	ctx, span := r.tracer.Start(ctx, "UserRepository.Delete")
 	defer span.End()
 
-	// Extract ID from filters for simple implementation
-	var id uuid.UUID
-	for _, filter := range filters {
-		if filter.Field == "id" && filter.Operator == dafi.Equal {
-			id = filter.Value.(uuid.UUID)
-			break
-		}
+	where, err := r.where(2, filters)
+	if err != nil {
+		return err
 	}
 
-	query := `
-		UPDATE auth.users
-		SET deleted_at = $2, deleted_by = $3
-		WHERE id = $1 AND deleted_at IS NULL
-	`
+	query := "UPDATE auth.users SET deleted_at = $1, deleted_by = $2" + where.Sql
+	args := append([]any{time.Now(), auth.UserIDFrom(ctx)}, where.Args...)
 
-	executor := r.getExecutor()
-	result, err := executor.Exec(ctx, query, id, time.Now(), nil) // TODO: Get deleted_by from context
+	result, err := r.getExecutor().Exec(ctx, query, args...)
 	if err != nil {
 		return fault.Wrap(err).Message("failed to delete user")
 	}
@@ -274,52 +246,211 @@ func (r *UserRepository) Exists(ctx context.Context, criteria dafi.Criteria) (bo
 	ctx, span := r.tracer.Start(ctx, "UserRepository.Exists")
 	defer span.End()
 
-	query := `
-		SELECT 1
-		FROM auth.users
-		WHERE deleted_at IS NULL
-	`
-
-	// Extract ID from criteria for simple implementation
-	var id uuid.UUID
-	for _, filter := range criteria.Filters {
-		if filter.Field == "id" && filter.Operator == dafi.Equal {
-			id = filter.Value.(uuid.UUID)
-			break
-		}
+	where, err := r.where(0, criteria.Filters)
+	if err != nil {
+		return false, err
 	}
 
-	query += " AND id = $1 LIMIT 1"
+	query := "SELECT EXISTS (SELECT 1 FROM auth.users" + where.Sql + ")"
 
-	executor := r.getExecutor()
-	var exists int
-	err := executor.QueryRow(ctx, query, id).Scan(&exists)
-	if err != nil {
-		if err.Error() == "no rows in result set" {
-			return false, nil
-		}
+	var exists bool
+	if err := r.getExecutor().QueryRow(ctx, query, where.Args...).Scan(&exists); err != nil {
 		return false, fault.Wrap(err).Message("failed to check if user exists")
 	}
 
-	return exists == 1, nil
+	return exists, nil
 }
 
 func (r *UserRepository) Count(ctx context.Context, criteria dafi.Criteria) (int64, error) {
 	ctx, span := r.tracer.Start(ctx, "UserRepository.Count")
 	defer span.End()
 
-	query := `
-		SELECT COUNT(*)
-		FROM auth.users
-		WHERE deleted_at IS NULL
-	`
+	where, err := r.where(0, criteria.Filters)
+	if err != nil {
+		return 0, err
+	}
+
+	query := "SELECT COUNT(*) FROM auth.users" + where.Sql
 
-	executor := r.getExecutor()
 	var count int64
-	err := executor.QueryRow(ctx, query).Scan(&count)
-	if err != nil {
+	if err := r.getExecutor().QueryRow(ctx, query, where.Args...).Scan(&count); err != nil {
 		return 0, fault.Wrap(err).Message("failed to count users")
 	}
 
 	return count, nil
-}
\ No newline at end of file
+}
+
+func (r *UserRepository) SetActive(ctx context.Context, active bool, at time.Time, by *uuid.UUID, filters ...dafi.Filter) ([]uuid.UUID, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.SetActive")
+	defer span.End()
+
+	filters = append(dafi.Filters(filters).Grouped(),
+		dafi.Filter{Field: "is_active", Operator: dafi.NotEqual, Value: active},
+		dafi.Filter{Field: "deleted_at", Operator: dafi.IsNull},
+	)
+	where, err := r.where(3, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "UPDATE auth.users SET is_active = $1, updated_at = $2, updated_by = $3" + where.Sql + " RETURNING id"
+	args := append([]any{active, at, by}, where.Args...)
+
+	rows, err := r.getExecutor().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to set user status")
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to set user status")
+	}
+
+	return ids, nil
+}
+
+// auditColumns lists the created_by and updated_by columns of every table
+const auditColumns = `
+	SELECT c.table_schema, c.table_name, c.column_name
+	FROM information_schema.columns c
+	JOIN information_schema.tables t
+		ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+	WHERE c.column_name IN ('created_by', 'updated_by')
+		AND t.table_type = 'BASE TABLE'
+		AND c.table_schema NOT IN ('pg_catalog', 'information_schema')
+	ORDER BY 1, 2, 3
+`
+
+// Merge finds the audit columns in the catalog, so tables added later are
+// merged too. Memberships and role assignments the canonical user already
+// has keep theirs, active if either was.
+func (r *UserRepository) Merge(ctx context.Context, duplicateID, canonicalID uuid.UUID, at time.Time, by *uuid.UUID) (entity.MergeResult, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.Merge")
+	defer span.End()
+
+	executor := r.getExecutor()
+	var result entity.MergeResult
+
+	rows, err := executor.Query(ctx, auditColumns)
+	if err != nil {
+		return entity.MergeResult{}, fault.Wrap(err).Message("failed to list audit columns")
+	}
+	columns, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) ([3]string, error) {
+		var column [3]string
+		err := row.Scan(&column[0], &column[1], &column[2])
+		return column, err
+	})
+	if err != nil {
+		return entity.MergeResult{}, fault.Wrap(err).Message("failed to list audit columns")
+	}
+
+	for _, column := range columns {
+		table := pgx.Identifier{column[0], column[1]}.Sanitize()
+		name := pgx.Identifier{column[2]}.Sanitize()
+
+		tag, err := executor.Exec(ctx, "UPDATE "+table+" SET "+name+" = $1 WHERE "+name+" = $2", canonicalID, duplicateID)
+		if err != nil {
+			return entity.MergeResult{}, fault.Wrap(err).Message("failed to re-point user references").With("table", column[0]+"."+column[1])
+		}
+		result.References += tag.RowsAffected()
+	}
+
+	_, err = executor.Exec(ctx, `
+		UPDATE auth.organization_users keep
+		SET is_active = keep.is_active OR duplicate.is_active, updated_at = $3, updated_by = $4
+		FROM auth.organization_users duplicate
+		WHERE keep.user_id = $2
+			AND duplicate.user_id = $1
+			AND duplicate.organization_id = keep.organization_id
+			AND duplicate.customer_id IS NOT DISTINCT FROM keep.customer_id
+	`, duplicateID, canonicalID, at, by)
+	if err != nil {
+		return entity.MergeResult{}, fault.Wrap(err).Message("failed to merge memberships")
+	}
+	_, err = executor.Exec(ctx, `
+		DELETE FROM auth.organization_users duplicate
+		USING auth.organization_users keep
+		WHERE keep.user_id = $2
+			AND duplicate.user_id = $1
+			AND duplicate.organization_id = keep.organization_id
+			AND duplicate.customer_id IS NOT DISTINCT FROM keep.customer_id
+	`, duplicateID, canonicalID)
+	if err != nil {
+		return entity.MergeResult{}, fault.Wrap(err).Message("failed to merge memberships")
+	}
+	tag, err := executor.Exec(ctx, "UPDATE auth.organization_users SET user_id = $2, updated_at = $3, updated_by = $4 WHERE user_id = $1", duplicateID, canonicalID, at, by)
+	if err != nil {
+		return entity.MergeResult{}, fault.Wrap(err).Message("failed to move memberships")
+	}
+	result.Memberships = tag.RowsAffected()
+
+	_, err = executor.Exec(ctx, `
+		UPDATE auth.user_roles keep
+		SET is_active = keep.is_active OR duplicate.is_active
+		FROM auth.user_roles duplicate
+		WHERE keep.user_id = $2 AND duplicate.user_id = $1 AND duplicate.role_id = keep.role_id
+	`, duplicateID, canonicalID)
+	if err != nil {
+		return entity.MergeResult{}, fault.Wrap(err).Message("failed to merge roles")
+	}
+	_, err = executor.Exec(ctx, `
+		DELETE FROM auth.user_roles duplicate
+		USING auth.user_roles keep
+		WHERE keep.user_id = $2 AND duplicate.user_id = $1 AND duplicate.role_id = keep.role_id
+	`, duplicateID, canonicalID)
+	if err != nil {
+		return entity.MergeResult{}, fault.Wrap(err).Message("failed to merge roles")
+	}
+	tag, err = executor.Exec(ctx, "UPDATE auth.user_roles SET user_id = $2 WHERE user_id = $1", duplicateID, canonicalID)
+	if err != nil {
+		return entity.MergeResult{}, fault.Wrap(err).Message("failed to move roles")
+	}
+	result.Roles = tag.RowsAffected()
+
+	return result, nil
+}
+
+// where builds the WHERE clause of filters on auth.users. Users belong to
+// organizations through auth.organization_users, so organization_id
+// filters become membership sub-queries. Deactivated users still belong to
+// the organizations their memberships were deactivated with.
+func (r *UserRepository) where(initialArgCount int, filters dafi.Filters) (sqlcraft.Result, error) {
+	filters = slices.Clone(filters)
+	for i, filter := range filters {
+		if filter.Field != "organization_id" {
+			continue
+		}
+
+		switch filter.Operator {
+		case dafi.In:
+			filters[i].Field = "id IN (SELECT user_id FROM auth.organization_users WHERE (is_active OR deactivated_with_user) AND organization_id = ANY(?))"
+		case dafi.Equal, "":
+			filters[i].Field = "id IN (SELECT user_id FROM auth.organization_users WHERE (is_active OR deactivated_with_user) AND organization_id = ?)"
+		default:
+			return sqlcraft.Result{}, fault.New("unsupported organization_id filter").Code(fault.BadRequest).With("operator", filter.Operator)
+		}
+		filters[i].Operator = dafi.Default
+	}
+
+	return sqlcraft.WhereSafe(initialArgCount, sqlColumnByDomainField, filters...)
+}
+
+func scanUser(row pgx.Row) (entity.User, error) {
+	var user entity.User
+	err := row.Scan(
+		&user.ID,
+		&user.Origin,
+		&user.FirstName,
+		&user.LastName,
+		&user.Picture,
+		&user.Locale,
+		&user.IsActive,
+		&user.CreatedAt,
+		&user.CreatedBy,
+		&user.UpdatedAt,
+		&user.UpdatedBy,
+		&user.DeletedAt,
+		&user.DeletedBy,
+	)
+	return user, err
+}
@@ -1,11 +1,16 @@
 package users
 
 import (
+	"time"
+
 	"go.uber.org/fx"
 
 	"api.system.soluciones-cloud.com/internal/core/users/application"
 	"api.system.soluciones-cloud.com/internal/core/users/infrastructure/presentation"
 	"api.system.soluciones-cloud.com/internal/core/users/infrastructure/repository"
+	"api.system.soluciones-cloud.com/internal/shared/auth/token"
+	"api.system.soluciones-cloud.com/internal/shared/blob"
+	"api.system.soluciones-cloud.com/internal/shared/localconfig"
 	"api.system.soluciones-cloud.com/internal/shared/ports"
 )
 
@@ -15,10 +20,33 @@ var Module = fx.Options(
 			repository.NewUserRepository,
 			fx.As(new(ports.UserRepository)),
 		),
+		newConfig,
 		fx.Annotate(
 			application.NewUserUseCase,
 			fx.As(new(ports.UserUseCase)),
 		),
 		presentation.NewUserHandler,
 	),
-)
\ No newline at end of file
+)
+
+func newConfig(config *localconfig.Config) application.Config {
+	return application.Config{
+		AvatarLimits: blob.Limits{MaxSize: config.Storage.AvatarMaxSize, ContentTypes: blob.ImageTypes},
+		AvatarSize:   config.Storage.AvatarSize,
+		AvatarURL:    config.HTTP.PublicURL + "/api/v1/avatars",
+		URLExpiry:    config.Storage.URLExpiry,
+		// Access tokens of revoked sessions are valid until they expire,
+		// give or take the clock skew verifiers tolerate
+		SessionDenyTTL: config.JWT.AccessTokenTTL + clockSkew(config),
+		// Preferred locales only pick the language of messages, so they
+		// may be as stale as cached permissions
+		LocaleCacheTTL: config.Auth.PermissionCacheTTL,
+	}
+}
+
+func clockSkew(config *localconfig.Config) time.Duration {
+	if config.JWT.ClockSkew == 0 {
+		return token.DefaultClockSkew
+	}
+	return config.JWT.ClockSkew
+}
@@ -0,0 +1,377 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+const testClientID = "api-client"
+
+// fakeProvider is an OpenID Connect provider issuing the ID token of the
+// next login for any code, after checking the PKCE code verifier
+type fakeProvider struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	kid       string
+	key       *rsa.PrivateKey
+	claims    Claims
+	challenge string
+	jwksHits  int
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+
+	p := &fakeProvider{}
+	p.rotate(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                                p.URL,
+			"authorization_endpoint":                p.URL + "/authorize",
+			"token_endpoint":                        p.URL + "/token",
+			"jwks_uri":                              p.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+			"code_challenge_methods_supported":      []string{"S256"},
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.jwksHits++
+		writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": p.kid,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		clientID, secret, _ := r.BasicAuth()
+		if clientID != testClientID || secret != "secret" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+		if CodeChallenge(r.PostFormValue("code_verifier")) != p.challenge {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, p.claims)
+		tok.Header["kid"] = p.kid
+		raw, err := tok.SignedString(p.key)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"access_token": "opaque", "token_type": "Bearer", "id_token": raw})
+	})
+
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *fakeProvider) rotate(t *testing.T, kid string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.kid = kid
+	p.key = key
+}
+
+// login prepares the ID token of the next code exchange
+func (p *fakeProvider) login(challenge string, claims Claims) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.challenge = challenge
+	p.claims = claims
+}
+
+func (p *fakeProvider) idTokenClaims(nonce string) Claims {
+	now := time.Now()
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "248289761001",
+			Issuer:    p.URL,
+			Audience:  jwt.ClaimStrings{testClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Nonce:         nonce,
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		GivenName:     "Jane",
+		FamilyName:    "Doe",
+		Picture:       "https://example.com/jane.png",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func newTestProvider(fake *fakeProvider) *Provider {
+	return NewProvider(Config{
+		Name:         "fake",
+		Issuer:       fake.URL,
+		ClientID:     testClientID,
+		ClientSecret: "secret",
+		RedirectURL:  "https://app.example.com/login/callback",
+	}, fake.Client())
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := newTestProvider(fake)
+
+	raw, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", "challenge-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	authURL, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := authURL.Scheme + "://" + authURL.Host + authURL.Path; got != fake.URL+"/authorize" {
+		t.Errorf("Expected the authorization endpoint, got %s", got)
+	}
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          "https://app.example.com/login/callback",
+		"scope":                 "openid email profile",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        "challenge-1",
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if got := authURL.Query().Get(name); got != value {
+			t.Errorf("Expected %s=%q, got %q", name, value, got)
+		}
+	}
+}
+
+func TestProvider_Discovery_IssuerMismatch(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := NewProvider(Config{Name: "fake", Issuer: fake.URL + "/", ClientID: testClientID}, fake.Client())
+
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge"); err == nil {
+		t.Fatal("Expected metadata of another issuer to be rejected")
+	}
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := newTestProvider(fake)
+
+	verifier, err := NewCodeVerifier()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		verifier string
+		nonce    string
+		claims   func(c Claims) Claims
+		wantErr  string
+	}{
+		{
+			name:     "valid",
+			verifier: verifier,
+			nonce:    "nonce-1",
+		},
+		{
+			name:     "wrong code verifier",
+			verifier: "another-verifier",
+			nonce:    "nonce-1",
+			wantErr:  "identity provider rejected the login",
+		},
+		{
+			name:     "nonce mismatch",
+			verifier: verifier,
+			nonce:    "nonce-2",
+			wantErr:  "ID token nonce does not match",
+		},
+		{
+			name:     "wrong audience",
+			verifier: verifier,
+			nonce:    "nonce-1",
+			claims: func(c Claims) Claims {
+				c.Audience = jwt.ClaimStrings{"another-client"}
+				return c
+			},
+			wantErr: "invalid ID token",
+		},
+		{
+			name:     "wrong issuer",
+			verifier: verifier,
+			nonce:    "nonce-1",
+			claims: func(c Claims) Claims {
+				c.Issuer = "https://evil.example.com"
+				return c
+			},
+			wantErr: "invalid ID token",
+		},
+		{
+			name:     "expired",
+			verifier: verifier,
+			nonce:    "nonce-1",
+			claims: func(c Claims) Claims {
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+				return c
+			},
+			wantErr: "invalid ID token",
+		},
+		{
+			name:     "several audiences for another client",
+			verifier: verifier,
+			nonce:    "nonce-1",
+			claims: func(c Claims) Claims {
+				c.Audience = jwt.ClaimStrings{testClientID, "another-client"}
+				c.AuthorizedParty = "another-client"
+				return c
+			},
+			wantErr: "ID token is meant for another client",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := fake.idTokenClaims("nonce-1")
+			if tt.claims != nil {
+				claims = tt.claims(claims)
+			}
+			fake.login(CodeChallenge(verifier), claims)
+
+			got, err := provider.Exchange(context.Background(), "code", tt.verifier, tt.nonce)
+
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("Expected error %q, got claims %+v", tt.wantErr, got)
+				}
+				if fault.CodeOf(err) != fault.Unauthorized {
+					t.Errorf("Expected code %s, got %s", fault.Unauthorized, fault.CodeOf(err))
+				}
+				if msg := fault.MessageOf(err); msg != tt.wantErr {
+					t.Errorf("Expected message %q, got %q (%v)", tt.wantErr, msg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got.Subject != claims.Subject || got.Email != claims.Email || !bool(got.EmailVerified) {
+				t.Errorf("Expected the claims of the login, got %+v", got)
+			}
+			if got.GivenName != "Jane" || got.FamilyName != "Doe" || got.Picture != claims.Picture {
+				t.Errorf("Expected the profile claims, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestProvider_Exchange_KeyRotation(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := newTestProvider(fake)
+	verifier, _ := NewCodeVerifier()
+
+	fake.login(CodeChallenge(verifier), fake.idTokenClaims("nonce"))
+	if _, err := provider.Exchange(context.Background(), "code", verifier, "nonce"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// An unknown key id fetches the JWKS again right away
+	fake.rotate(t, "key-2")
+	provider.keys.MinRefreshInterval = 0
+	if _, err := provider.Exchange(context.Background(), "code", verifier, "nonce"); err != nil {
+		t.Fatalf("Expected the rotated key to be picked up, got %v", err)
+	}
+	if fake.jwksHits != 2 {
+		t.Errorf("Expected 2 JWKS fetches, got %d", fake.jwksHits)
+	}
+}
+
+func TestBool_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Bool
+	}{
+		{raw: `true`, want: true},
+		{raw: `false`, want: false},
+		{raw: `"true"`, want: true},
+		{raw: `"TRUE"`, want: true},
+		{raw: `"false"`, want: false},
+		{raw: `1`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			var got Bool
+			if err := json.Unmarshal([]byte(tt.raw), &got); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProviders_Unknown(t *testing.T) {
+	providers := NewProviders(nil, http.DefaultClient)
+
+	_, err := providers.AuthCodeURL(context.Background(), "unknown", "state", "nonce", "challenge")
+	if fault.CodeOf(err) != fault.NotFound {
+		t.Errorf("Expected code %s, got %s (%v)", fault.NotFound, fault.CodeOf(err), err)
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	verifier, err := NewCodeVerifier()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(verifier) != 43 {
+		t.Errorf("Expected a verifier of 43 characters, got %q", verifier)
+	}
+
+	got := CodeChallenge("dBjftJeZ4CVP-mB92K5uOcdvIHh3Cp0KxQbHDcKM2sg")
+	if want := "mWVqt4pPpDYVze1SvlVlABjaz6HE4a8YlI3RygUh_ho"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,29 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// ChallengeMethod is the only PKCE code challenge method sent. plain
+// would expose the verifier in the authorization URL.
+const ChallengeMethod = "S256"
+
+// NewCodeVerifier returns a random PKCE code verifier (RFC 7636, section
+// 4.1) of 43 characters
+func NewCodeVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fault.Wrap(err).Message("failed to generate code verifier")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge returns the S256 code challenge of a code verifier
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
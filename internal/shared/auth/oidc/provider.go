@@ -0,0 +1,314 @@
+// Package oidc is the relying party of the OpenID Connect login: it sends
+// users to the authorization endpoint of their identity provider and turns
+// the authorization code they come back with into verified ID token
+// claims. Only the authorization code flow with PKCE (S256) is supported.
+//
+// Provider metadata is discovered from the issuer on first use, and the
+// JWKS of the provider is cached, see token.RemoteKeySet.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth/token"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// DefaultScopes are requested when a provider configures none
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+// maxResponseSize bounds the discovery and token responses read
+const maxResponseSize = 1 << 20
+
+// Config is a provider the API accepts logins from
+type Config struct {
+	// Name identifies the provider in routes and is the origin of the
+	// users it creates, e.g. google
+	Name     string
+	Issuer   string
+	ClientID string
+	// ClientSecret is sent to the token endpoint with HTTP Basic
+	// authentication. Public clients leave it empty.
+	ClientSecret string
+	// RedirectURL is the client page the provider sends users back to
+	// with the code and state query parameters
+	RedirectURL string
+	Scopes      []string
+	// ClockSkew is the leeway for exp and iat. Defaults to
+	// token.DefaultClockSkew.
+	ClockSkew time.Duration
+}
+
+// Metadata is the part of the provider configuration document (OpenID
+// Connect Discovery 1.0) the relying party uses
+type Metadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	SigningAlgValues      []string `json:"id_token_signing_alg_values_supported"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported"`
+}
+
+// Claims are the ID token claims of a login
+type Claims struct {
+	jwt.RegisteredClaims
+	Nonce           string `json:"nonce"`
+	AuthorizedParty string `json:"azp,omitempty"`
+	Email           string `json:"email,omitempty"`
+	EmailVerified   Bool   `json:"email_verified,omitempty"`
+	Name            string `json:"name,omitempty"`
+	GivenName       string `json:"given_name,omitempty"`
+	FamilyName      string `json:"family_name,omitempty"`
+	Picture         string `json:"picture,omitempty"`
+}
+
+// Bool is a boolean claim some providers send as a string
+type Bool bool
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		*b = Bool(v)
+	case string:
+		*b = Bool(strings.EqualFold(v, "true"))
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// Provider is an OpenID Connect provider. It is safe for concurrent use.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *Metadata
+	keys     *token.RemoteKeySet
+}
+
+func NewProvider(config Config, client *http.Client) *Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultScopes
+	}
+	if config.ClockSkew == 0 {
+		config.ClockSkew = token.DefaultClockSkew
+	}
+
+	return &Provider{
+		config: config,
+		client: client,
+	}
+}
+
+// Name returns the name of the provider
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL returns the authorization endpoint URL users are sent to.
+// state and nonce must be unguessable values bound to the login, and
+// challenge the CodeChallenge of its code verifier.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, challenge string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fault.Wrap(err).Message("invalid authorization endpoint").With("provider", p.config.Name)
+	}
+
+	query := endpoint.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", challenge)
+	query.Set("code_challenge_method", ChallengeMethod)
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String(), nil
+}
+
+// Exchange redeems an authorization code with its code verifier and
+// returns the claims of the ID token, which must carry nonce. Rejected
+// codes and invalid ID tokens are fault.Unauthorized errors.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (Claims, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, fault.Wrap(err).Message("failed to build token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Claims{}, fault.Wrap(err).Code(fault.ServiceUnavailable).
+			Message("identity provider is not available").
+			With("provider", p.config.Name)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&body); err != nil {
+		return Claims{}, fault.Wrap(err).Code(fault.Unauthorized).
+			Message("invalid token response").
+			With("provider", p.config.Name)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Claims{}, fault.New("identity provider rejected the login").Code(fault.Unauthorized).
+			With("provider", p.config.Name).
+			With("error", body.Error).
+			With("error_description", body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return Claims{}, fault.New("token response has no ID token").Code(fault.Unauthorized).With("provider", p.config.Name)
+	}
+
+	return p.VerifyIDToken(ctx, body.IDToken, nonce)
+}
+
+// VerifyIDToken checks the signature, issuer, audience, lifetime and nonce
+// of an ID token and returns its claims.
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (Claims, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	algorithms := []string{token.RS256, token.EdDSA}
+	if len(metadata.SigningAlgValues) > 0 {
+		algorithms = slices.DeleteFunc(slices.Clone(metadata.SigningAlgValues), func(alg string) bool {
+			return alg != token.RS256 && alg != token.EdDSA
+		})
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(algorithms),
+		jwt.WithLeeway(p.config.ClockSkew),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+	)
+
+	claims := Claims{}
+	_, err = parser.ParseWithClaims(raw, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.keys.Lookup(ctx, kid, t.Method.Alg())
+	})
+	if err != nil {
+		return Claims{}, fault.Wrap(err).Code(fault.Unauthorized).Message("invalid ID token").With("provider", p.config.Name)
+	}
+
+	if claims.Subject == "" {
+		return Claims{}, fault.New("ID token has no subject").Code(fault.Unauthorized).With("provider", p.config.Name)
+	}
+	if claims.Nonce != nonce {
+		return Claims{}, fault.New("ID token nonce does not match").Code(fault.Unauthorized).With("provider", p.config.Name)
+	}
+	// Tokens issued to several clients name the one they are meant for
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return Claims{}, fault.New("ID token is meant for another client").Code(fault.Unauthorized).With("provider", p.config.Name)
+	}
+
+	return claims, nil
+}
+
+// discover returns the metadata of the provider, fetching it on first use.
+// Failures are not cached, so an unavailable provider is retried on the
+// next login.
+func (p *Provider) discover(ctx context.Context) (Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return *p.metadata, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return Metadata{}, fault.Wrap(err).Message("failed to build discovery request")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Metadata{}, fault.Wrap(err).Code(fault.ServiceUnavailable).
+			Message("identity provider is not available").
+			With("provider", p.config.Name)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Metadata{}, fault.New("identity provider is not available").Code(fault.ServiceUnavailable).
+			With("provider", p.config.Name).
+			With("status", resp.StatusCode)
+	}
+
+	var metadata Metadata
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&metadata); err != nil {
+		return Metadata{}, fault.Wrap(err).Message("invalid provider metadata").With("provider", p.config.Name)
+	}
+
+	// The issuer must match exactly, so a document served elsewhere cannot
+	// impersonate the provider (OpenID Connect Discovery 1.0, section 4.3)
+	if metadata.Issuer != p.config.Issuer {
+		return Metadata{}, fault.New("provider metadata issuer does not match").
+			With("provider", p.config.Name).
+			With("issuer", metadata.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return Metadata{}, fault.New("provider metadata is incomplete").With("provider", p.config.Name)
+	}
+	if len(metadata.CodeChallengeMethods) > 0 && !slices.Contains(metadata.CodeChallengeMethods, ChallengeMethod) {
+		return Metadata{}, fault.New("provider does not support PKCE with "+ChallengeMethod).With("provider", p.config.Name)
+	}
+
+	p.metadata = &metadata
+	p.keys = token.NewRemoteKeySet(metadata.JWKSURI, p.client)
+	return metadata, nil
+}
@@ -0,0 +1,60 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// Providers are the identity providers the API accepts logins from, by
+// name
+type Providers struct {
+	names     []string
+	providers map[string]*Provider
+}
+
+// NewProviders returns the providers of configs, which share client
+func NewProviders(configs []Config, client *http.Client) *Providers {
+	p := &Providers{providers: make(map[string]*Provider, len(configs))}
+	for _, config := range configs {
+		p.names = append(p.names, config.Name)
+		p.providers[config.Name] = NewProvider(config, client)
+	}
+	return p
+}
+
+// Names returns the names of the providers in configuration order
+func (p *Providers) Names() []string {
+	return p.names
+}
+
+// Get returns the provider called name. Unknown providers are
+// fault.NotFound errors.
+func (p *Providers) Get(name string) (*Provider, error) {
+	provider, ok := p.providers[name]
+	if !ok {
+		return nil, fault.New("unknown identity provider").Code(fault.NotFound).With("provider", name)
+	}
+	return provider, nil
+}
+
+// AuthCodeURL returns the authorization URL of the provider called name,
+// see Provider.AuthCodeURL
+func (p *Providers) AuthCodeURL(ctx context.Context, name, state, nonce, challenge string) (string, error) {
+	provider, err := p.Get(name)
+	if err != nil {
+		return "", err
+	}
+	return provider.AuthCodeURL(ctx, state, nonce, challenge)
+}
+
+// Exchange redeems a code with the provider called name, see
+// Provider.Exchange
+func (p *Providers) Exchange(ctx context.Context, name, code, verifier, nonce string) (Claims, error) {
+	provider, err := p.Get(name)
+	if err != nil {
+		return Claims{}, err
+	}
+	return provider.Exchange(ctx, code, verifier, nonce)
+}
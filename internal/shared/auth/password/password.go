@@ -0,0 +1,129 @@
+// Package password hashes and verifies user passwords. New hashes use
+// argon2id in the PHC string format; bcrypt hashes are still verified so
+// they can be upgraded on the next successful login.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// Params are the argon2id cost parameters.
+type Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultParams follow the OWASP recommendation for argon2id.
+var DefaultParams = Params{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Hasher hashes passwords with argon2id.
+type Hasher struct {
+	params Params
+	// dummy is verified when there is no stored hash, so unknown accounts
+	// take as long as known ones
+	dummy string
+}
+
+func NewHasher(params Params) *Hasher {
+	h := &Hasher{params: params}
+	h.dummy, _ = h.Hash("dummy password")
+	return h
+}
+
+// Hash returns the argon2id hash of password, e.g.
+// $argon2id$v=19$m=19456,t=2,p=1$<salt>$<key>.
+func (h *Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fault.Wrap(err).Message("failed to generate salt")
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.params.Memory,
+		h.params.Iterations,
+		h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify reports whether password matches hash in constant time.
+// needsRehash is true when the hash should be replaced, e.g. a bcrypt hash
+// or argon2id with weaker parameters. An empty hash never matches, but still
+// costs a full verification.
+func (h *Hasher) Verify(password, hash string) (ok bool, needsRehash bool, err error) {
+	switch {
+	case hash == "":
+		_, _, _ = h.verifyArgon2(password, h.dummy)
+		return false, false, nil
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return h.verifyArgon2(password, hash)
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err != nil {
+			return false, false, nil
+		}
+		return true, true, nil
+	default:
+		return false, false, fault.New("unsupported password hash").Code(fault.InternalError)
+	}
+}
+
+func (h *Hasher) verifyArgon2(password, hash string) (bool, bool, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false, false, fault.New("malformed argon2id hash").Code(fault.InternalError)
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false, fault.New("unsupported argon2 version").Code(fault.InternalError)
+	}
+
+	var params Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return false, false, fault.Wrap(err).Code(fault.InternalError).Message("malformed argon2id parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, fault.Wrap(err).Code(fault.InternalError).Message("malformed argon2id salt")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false, fault.Wrap(err).Code(fault.InternalError).Message("malformed argon2id key")
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, candidate) != 1 {
+		return false, false, nil
+	}
+
+	needsRehash := params.Memory < h.params.Memory ||
+		params.Iterations < h.params.Iterations ||
+		params.Parallelism < h.params.Parallelism ||
+		uint32(len(key)) < h.params.KeyLength
+
+	return true, needsRehash, nil
+}
@@ -0,0 +1,66 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams keep the tests fast
+var testParams = Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestHasher_Hash(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	first, err := hasher.Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := hasher.Hash("correct horse battery staple")
+
+	if !strings.HasPrefix(first, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("Unexpected hash format %s", first)
+	}
+	if first == second {
+		t.Error("Expected a different salt per hash")
+	}
+}
+
+func TestHasher_Verify(t *testing.T) {
+	hasher := NewHasher(testParams)
+
+	argonHash, _ := hasher.Hash("s3cret-password")
+	weakHash, _ := NewHasher(Params{Memory: 32, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}).Hash("s3cret-password")
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte("s3cret-password"), bcrypt.MinCost)
+
+	tests := []struct {
+		name            string
+		password        string
+		hash            string
+		wantOK          bool
+		wantNeedsRehash bool
+		wantErr         bool
+	}{
+		{name: "argon2id match", password: "s3cret-password", hash: argonHash, wantOK: true},
+		{name: "argon2id mismatch", password: "wrong-password", hash: argonHash},
+		{name: "weaker parameters need rehash", password: "s3cret-password", hash: weakHash, wantOK: true, wantNeedsRehash: true},
+		{name: "bcrypt match needs rehash", password: "s3cret-password", hash: string(bcryptHash), wantOK: true, wantNeedsRehash: true},
+		{name: "bcrypt mismatch", password: "wrong-password", hash: string(bcryptHash)},
+		{name: "no stored hash", password: "s3cret-password", hash: ""},
+		{name: "unknown format", password: "s3cret-password", hash: "plain", wantErr: true},
+		{name: "malformed argon2id", password: "s3cret-password", hash: "$argon2id$v=19$m=64", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, needsRehash, err := hasher.Verify(tt.password, tt.hash)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%t, got %v", tt.wantErr, err)
+			}
+			if ok != tt.wantOK || needsRehash != tt.wantNeedsRehash {
+				t.Errorf("Expected ok=%t needsRehash=%t, got ok=%t needsRehash=%t", tt.wantOK, tt.wantNeedsRehash, ok, needsRehash)
+			}
+		})
+	}
+}
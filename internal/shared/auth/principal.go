@@ -0,0 +1,65 @@
+// Package auth holds the authenticated caller of a request. Authentication
+// middleware stores a Principal in the request context; use cases read it
+// with PrincipalFrom instead of depending on echo.
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserID uuid.UUID
+	// OrganizationID is the organization the credentials were issued for,
+	// if any
+	OrganizationID uuid.NullUUID
+	Roles          []string
+	// APIKeyID is the API key the request authenticated with, if any.
+	// UserID is then the service account of the key, and the principal is
+	// only granted the actions of the key.
+	APIKeyID uuid.NullUUID
+	// SessionID is the login session the access token was issued for, if
+	// any. Requests of revoked sessions are rejected.
+	SessionID uuid.NullUUID
+}
+
+// HasRole reports whether the principal has the given role.
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal stored in ctx, if any.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// UserIDFrom returns the id of the authenticated user, e.g. to fill
+// created_by and updated_by columns.
+func UserIDFrom(ctx context.Context) uuid.NullUUID {
+	p, ok := PrincipalFrom(ctx)
+	if !ok {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: p.UserID, Valid: true}
+}
+
+// ActorID returns the id of the authenticated user as the created_by and
+// updated_by fields of entities hold it, nil when there is none.
+func ActorID(ctx context.Context) *uuid.UUID {
+	userID := UserIDFrom(ctx)
+	if !userID.Valid {
+		return nil
+	}
+	return &userID.UUID
+}
@@ -0,0 +1,154 @@
+package rbac
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// Store loads the effective permissions of a user. When organizationID is
+// set, only the roles of that organization are taken into account.
+// APIKeyPermissions loads the permissions of an API key instead: the
+// actions of the key in its organization only.
+type Store interface {
+	Permissions(ctx context.Context, userID uuid.UUID, organizationID uuid.NullUUID) (Permissions, error)
+	APIKeyPermissions(ctx context.Context, apiKeyID uuid.UUID) (Permissions, error)
+}
+
+type cacheKey struct {
+	userID         uuid.UUID
+	organizationID uuid.NullUUID
+	apiKeyID       uuid.NullUUID
+}
+
+type cacheEntry struct {
+	permissions Permissions
+	expiresAt   time.Time
+}
+
+// Authorizer resolves and caches the permissions of principals. Cached
+// permissions expire after the TTL; Invalidate and InvalidateAll drop them
+// right away and must be called when roles or permissions change.
+type Authorizer struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+	// generation changes on every invalidation, so permissions loaded
+	// while it happened are not cached
+	generation uint64
+}
+
+func NewAuthorizer(store Store, ttl time.Duration) *Authorizer {
+	return &Authorizer{
+		store:   store,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[cacheKey]cacheEntry),
+	}
+}
+
+// Permissions returns the effective permissions of the principal, or of
+// its API key when it authenticated with one.
+func (a *Authorizer) Permissions(ctx context.Context, principal auth.Principal) (Permissions, error) {
+	key := cacheKey{userID: principal.UserID, organizationID: principal.OrganizationID, apiKeyID: principal.APIKeyID}
+
+	a.mu.Lock()
+	entry, ok := a.entries[key]
+	generation := a.generation
+	a.mu.Unlock()
+
+	if ok && a.now().Before(entry.expiresAt) {
+		return entry.permissions, nil
+	}
+
+	var permissions Permissions
+	var err error
+	if principal.APIKeyID.Valid {
+		permissions, err = a.store.APIKeyPermissions(ctx, principal.APIKeyID.UUID)
+	} else {
+		permissions, err = a.store.Permissions(ctx, principal.UserID, principal.OrganizationID)
+	}
+	if err != nil {
+		return Permissions{}, fault.Wrap(err).Message("failed to load permissions")
+	}
+
+	if a.ttl > 0 {
+		a.mu.Lock()
+		if a.generation == generation {
+			a.entries[key] = cacheEntry{permissions: permissions, expiresAt: a.now().Add(a.ttl)}
+		}
+		a.mu.Unlock()
+	}
+
+	return permissions, nil
+}
+
+// Authorize returns the access the principal has to the action code, or a
+// fault.Forbidden error when the action is not granted. Unless the user is
+// a root organization member, an action granted by the roles of some of
+// their organizations only gives access to those.
+func (a *Authorizer) Authorize(ctx context.Context, principal auth.Principal, code string) (Access, error) {
+	permissions, err := a.Permissions(ctx, principal)
+	if err != nil {
+		return Access{}, err
+	}
+
+	scope, ok := permissions.Scope(code)
+	if !ok {
+		return Access{}, fault.New("you do not have permission to perform this action").
+			Code(fault.Forbidden).
+			With("permission", code)
+	}
+
+	access := Access{
+		Code:          code,
+		Scope:         scope,
+		UserID:        principal.UserID,
+		Organizations: permissions.Organizations,
+	}
+
+	if granting, ok := permissions.ActionOrganizations[code]; ok && !permissions.Root {
+		access.Organizations = slices.DeleteFunc(slices.Clone(permissions.Organizations), func(id uuid.UUID) bool {
+			return !slices.Contains(granting, id)
+		})
+		access.Limited = true
+	}
+
+	return access, nil
+}
+
+// Invalidate drops the cached permissions of the users, e.g. after their
+// roles changed, or of the service accounts of API keys.
+func (a *Authorizer) Invalidate(userIDs ...uuid.UUID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.generation++
+	for key := range a.entries {
+		for _, userID := range userIDs {
+			if key.userID == userID {
+				delete(a.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// InvalidateAll drops every cached permission, e.g. after the permissions
+// of a role changed.
+func (a *Authorizer) InvalidateAll() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.generation++
+	clear(a.entries)
+}
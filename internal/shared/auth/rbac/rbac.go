@@ -0,0 +1,77 @@
+// Package rbac authorizes principals against the roles and permissions
+// stored in the auth schema. A user is granted the module actions of the
+// active roles assigned to them, plus every public action. Actions are
+// identified by "<module>.<action>" codes, e.g. "users.read", and carry a
+// visibility scope that restricts the rows the user may see.
+package rbac
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Scope is the visibility scope of a module action.
+type Scope string
+
+const (
+	// ScopeAll grants access to every row
+	ScopeAll Scope = "ALL"
+	// ScopeOrganization grants access to the rows of the organizations
+	// the user is a member of
+	ScopeOrganization Scope = "ORG"
+	// ScopeOwn grants access to the rows owned by the user
+	ScopeOwn Scope = "OWN"
+)
+
+// Permissions are the effective permissions of a user.
+type Permissions struct {
+	// Actions maps the codes of the granted actions to their scope
+	Actions map[string]Scope
+	// Roles are the codes of the active roles of the user
+	Roles []string
+	// Organizations are the organizations the user is an active member
+	// of. It holds at most the principal's organization when the
+	// principal has one.
+	Organizations []uuid.UUID
+	// Root is set when the user is an active member of the root
+	// organization, whatever the principal's organization
+	Root bool
+	// ActionOrganizations maps the codes of the actions granted by roles
+	// to the organizations of those roles. It is only loaded for
+	// principals without an organization, whose roles in every
+	// organization are merged in Actions, so each action can be limited
+	// to the organizations granting it. Public actions are not limited.
+	ActionOrganizations map[string][]uuid.UUID
+}
+
+// Scope returns the scope the action code is granted with.
+func (p Permissions) Scope(code string) (Scope, bool) {
+	scope, ok := p.Actions[code]
+	return scope, ok
+}
+
+// Access is the permission a request was authorized with.
+type Access struct {
+	Code          string
+	Scope         Scope
+	UserID        uuid.UUID
+	Organizations []uuid.UUID
+	// Limited is set when the action is only granted in some of the
+	// organizations of the user, the Organizations of the access. The
+	// request must not act on the others.
+	Limited bool
+}
+
+type accessKey struct{}
+
+// WithAccess returns a copy of ctx carrying a.
+func WithAccess(ctx context.Context, a Access) context.Context {
+	return context.WithValue(ctx, accessKey{}, a)
+}
+
+// AccessFrom returns the access stored in ctx, if any.
+func AccessFrom(ctx context.Context) (Access, bool) {
+	a, ok := ctx.Value(accessKey{}).(Access)
+	return a, ok
+}
@@ -0,0 +1,319 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+type fakeStore struct {
+	permissions map[uuid.UUID]Permissions
+	apiKeys     map[uuid.UUID]Permissions
+	calls       int
+	err         error
+}
+
+func (s *fakeStore) Permissions(_ context.Context, userID uuid.UUID, _ uuid.NullUUID) (Permissions, error) {
+	s.calls++
+	if s.err != nil {
+		return Permissions{}, s.err
+	}
+	return s.permissions[userID], nil
+}
+
+func (s *fakeStore) APIKeyPermissions(_ context.Context, apiKeyID uuid.UUID) (Permissions, error) {
+	s.calls++
+	if s.err != nil {
+		return Permissions{}, s.err
+	}
+	return s.apiKeys[apiKeyID], nil
+}
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	userID, orgID, apiKeyID := uuid.New(), uuid.New(), uuid.New()
+	// memberID is an admin of orgA and a viewer of orgB, rootID the same
+	// as a root organization member
+	memberID, rootID, orgA, orgB := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	merged := Permissions{
+		Actions:       map[string]Scope{"users.read": ScopeOrganization, "users.delete": ScopeAll, "health.read": ScopeAll},
+		Organizations: []uuid.UUID{orgA, orgB},
+		ActionOrganizations: map[string][]uuid.UUID{
+			"users.read":   {orgA, orgB},
+			"users.delete": {orgA},
+		},
+	}
+	root := merged
+	root.Root = true
+	store := &fakeStore{
+		permissions: map[uuid.UUID]Permissions{
+			userID: {
+				Actions:       map[string]Scope{"users.read": ScopeOrganization, "users.create": ScopeAll},
+				Organizations: []uuid.UUID{orgID},
+			},
+			memberID: merged,
+			rootID:   root,
+		},
+		apiKeys: map[uuid.UUID]Permissions{
+			apiKeyID: {
+				Actions:       map[string]Scope{"users.create": ScopeAll},
+				Organizations: []uuid.UUID{orgID},
+			},
+		},
+	}
+	apiKey := uuid.NullUUID{UUID: apiKeyID, Valid: true}
+	authorizer := NewAuthorizer(store, time.Minute)
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		code      string
+		want      Access
+		wantCode  fault.Code
+	}{
+		{
+			name:      "granted",
+			principal: auth.Principal{UserID: userID},
+			code:      "users.read",
+			want:      Access{Code: "users.read", Scope: ScopeOrganization, UserID: userID, Organizations: []uuid.UUID{orgID}},
+		},
+		{
+			name:      "not granted",
+			principal: auth.Principal{UserID: userID},
+			code:      "users.delete",
+			wantCode:  fault.Forbidden,
+		},
+		{
+			name:      "api key action",
+			principal: auth.Principal{UserID: userID, APIKeyID: apiKey},
+			code:      "users.create",
+			want:      Access{Code: "users.create", Scope: ScopeAll, UserID: userID, Organizations: []uuid.UUID{orgID}},
+		},
+		{
+			name:      "api keys only get their actions",
+			principal: auth.Principal{UserID: userID, APIKeyID: apiKey},
+			code:      "users.read",
+			wantCode:  fault.Forbidden,
+		},
+		{
+			name:      "action granted in every organization",
+			principal: auth.Principal{UserID: memberID},
+			code:      "users.read",
+			want:      Access{Code: "users.read", Scope: ScopeOrganization, UserID: memberID, Organizations: []uuid.UUID{orgA, orgB}, Limited: true},
+		},
+		{
+			name:      "action limited to the organizations granting it",
+			principal: auth.Principal{UserID: memberID},
+			code:      "users.delete",
+			want:      Access{Code: "users.delete", Scope: ScopeAll, UserID: memberID, Organizations: []uuid.UUID{orgA}, Limited: true},
+		},
+		{
+			name:      "public action",
+			principal: auth.Principal{UserID: memberID},
+			code:      "health.read",
+			want:      Access{Code: "health.read", Scope: ScopeAll, UserID: memberID, Organizations: []uuid.UUID{orgA, orgB}},
+		},
+		{
+			name:      "root members are not limited",
+			principal: auth.Principal{UserID: rootID},
+			code:      "users.delete",
+			want:      Access{Code: "users.delete", Scope: ScopeAll, UserID: rootID, Organizations: []uuid.UUID{orgA, orgB}},
+		},
+		{
+			name:      "user without roles",
+			principal: auth.Principal{UserID: uuid.New()},
+			code:      "users.read",
+			wantCode:  fault.Forbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access, err := authorizer.Authorize(context.Background(), tt.principal, tt.code)
+			if tt.wantCode != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantCode, fault.CodeOf(err), "unexpected error %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, access)
+		})
+	}
+}
+
+func TestAuthorizer_Cache(t *testing.T) {
+	userID := uuid.New()
+	principal := auth.Principal{UserID: userID}
+	store := &fakeStore{permissions: map[uuid.UUID]Permissions{
+		userID: {Actions: map[string]Scope{"users.read": ScopeAll}},
+	}}
+
+	now := time.Now()
+	authorizer := NewAuthorizer(store, time.Minute)
+	authorizer.now = func() time.Time { return now }
+
+	load := func() {
+		t.Helper()
+		_, err := authorizer.Permissions(context.Background(), principal)
+		require.NoError(t, err)
+	}
+
+	load()
+	load()
+	assert.Equal(t, 1, store.calls, "permissions should be cached")
+
+	authorizer.Invalidate(uuid.New())
+	load()
+	assert.Equal(t, 1, store.calls, "invalidating other users should keep the cache")
+
+	authorizer.Invalidate(userID)
+	load()
+	assert.Equal(t, 2, store.calls, "invalidating the user should reload")
+
+	authorizer.InvalidateAll()
+	load()
+	assert.Equal(t, 3, store.calls, "invalidating all should reload")
+
+	now = now.Add(time.Minute)
+	load()
+	assert.Equal(t, 4, store.calls, "expired permissions should reload")
+
+	// Organizations are cached separately
+	principal.OrganizationID = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	load()
+	assert.Equal(t, 5, store.calls)
+
+	// So are API keys, which are invalidated with their service account
+	principal.APIKeyID = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	load()
+	load()
+	assert.Equal(t, 6, store.calls)
+
+	authorizer.Invalidate(userID)
+	load()
+	assert.Equal(t, 7, store.calls)
+}
+
+func TestAuthorizer_StoreError(t *testing.T) {
+	store := &fakeStore{err: errors.New("connection refused")}
+	authorizer := NewAuthorizer(store, time.Minute)
+
+	_, err := authorizer.Authorize(context.Background(), auth.Principal{UserID: uuid.New()}, "users.read")
+	require.Error(t, err)
+	assert.NotEqual(t, fault.Forbidden, fault.CodeOf(err))
+
+	// Errors are not cached
+	_, _ = authorizer.Authorize(context.Background(), auth.Principal{UserID: uuid.New()}, "users.read")
+	assert.Equal(t, 2, store.calls)
+}
+
+func TestRestrict(t *testing.T) {
+	userID, orgID := uuid.New(), uuid.New()
+	visibility := Visibility{Owner: "created_by", Organization: "organization_id"}
+	base := dafi.FilterBy("is_active", dafi.Equal, true)
+
+	tests := []struct {
+		name       string
+		access     *Access
+		filters    dafi.Filters
+		visibility Visibility
+		want       dafi.Filters
+		wantCode   fault.Code
+		wantErr    bool
+	}{
+		{
+			name:       "no access",
+			filters:    base,
+			visibility: visibility,
+			want:       base,
+		},
+		{
+			name:       "all",
+			access:     &Access{Scope: ScopeAll, UserID: userID},
+			filters:    base,
+			visibility: visibility,
+			want:       base,
+		},
+		{
+			name:       "own",
+			access:     &Access{Scope: ScopeOwn, UserID: userID},
+			filters:    base,
+			visibility: visibility,
+			want: dafi.Filters{
+				{Field: "is_active", Operator: dafi.Equal, Value: true, ChainingKey: dafi.And},
+				{Field: "created_by", Operator: dafi.Equal, Value: userID},
+			},
+		},
+		{
+			name:       "own without filters",
+			access:     &Access{Scope: ScopeOwn, UserID: userID},
+			visibility: visibility,
+			want:       dafi.Filters{{Field: "created_by", Operator: dafi.Equal, Value: userID}},
+		},
+		{
+			name:       "organization",
+			access:     &Access{Scope: ScopeOrganization, UserID: userID, Organizations: []uuid.UUID{orgID}},
+			filters:    base,
+			visibility: visibility,
+			want: dafi.Filters{
+				{Field: "is_active", Operator: dafi.Equal, Value: true, ChainingKey: dafi.And},
+				{Field: "organization_id", Operator: dafi.In, Value: []uuid.UUID{orgID}},
+			},
+		},
+		{
+			name:       "organization without memberships",
+			access:     &Access{Scope: ScopeOrganization, UserID: userID},
+			filters:    base,
+			visibility: visibility,
+			wantCode:   fault.Forbidden,
+		},
+		{
+			name:       "or filters are grouped",
+			access:     &Access{Scope: ScopeOwn, UserID: userID},
+			filters:    dafi.FilterBy("origin", dafi.Equal, "web").Or("origin", dafi.Equal, "mobile"),
+			visibility: visibility,
+			want: dafi.Filters{
+				{Field: "origin", Operator: dafi.Equal, Value: "web", ChainingKey: dafi.Or, IsGroupOpen: true, GroupOpenQty: 1},
+				{Field: "origin", Operator: dafi.Equal, Value: "mobile", ChainingKey: dafi.And, IsGroupClose: true, GroupCloseQty: 1},
+				{Field: "created_by", Operator: dafi.Equal, Value: userID},
+			},
+		},
+		{
+			name:       "scope not supported by the resource",
+			access:     &Access{Scope: ScopeOwn, UserID: userID},
+			filters:    base,
+			visibility: Visibility{Organization: "organization_id"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.access != nil {
+				ctx = WithAccess(ctx, *tt.access)
+			}
+
+			original := append(dafi.Filters(nil), tt.filters...)
+			got, err := Restrict(ctx, tt.filters, tt.visibility)
+			if tt.wantCode != "" || tt.wantErr {
+				require.Error(t, err)
+				if tt.wantCode != "" {
+					assert.Equal(t, tt.wantCode, fault.CodeOf(err), "unexpected error %v", err)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, original, tt.filters, "filters should not be modified")
+		})
+	}
+}
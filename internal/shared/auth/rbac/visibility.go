@@ -0,0 +1,71 @@
+package rbac
+
+import (
+	"context"
+
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// Visibility names the fields a resource is restricted by.
+type Visibility struct {
+	// Owner is the field holding the id of the user that owns a row,
+	// e.g. created_by
+	Owner string
+	// Organization is the field holding the organization of a row. It is
+	// filtered with dafi.In and the organizations of the user.
+	Organization string
+}
+
+// Restrict adds to filters the conditions of the scope the request was
+// authorized with (see AccessFrom). Filters are left as they are for
+// ScopeAll and when the request carries no access, e.g. for routes that
+// only require authentication.
+func Restrict(ctx context.Context, filters dafi.Filters, visibility Visibility) (dafi.Filters, error) {
+	access, ok := AccessFrom(ctx)
+	if !ok {
+		return filters, nil
+	}
+
+	var restriction dafi.Filter
+	switch access.Scope {
+	case ScopeAll:
+		return filters, nil
+	case ScopeOwn:
+		if visibility.Owner == "" {
+			return nil, unsupportedScope(access)
+		}
+		restriction = dafi.Filter{Field: dafi.FilterField(visibility.Owner), Operator: dafi.Equal, Value: access.UserID}
+	case ScopeOrganization:
+		if visibility.Organization == "" {
+			return nil, unsupportedScope(access)
+		}
+		if len(access.Organizations) == 0 {
+			return nil, fault.New("you are not a member of any organization").
+				Code(fault.Forbidden).
+				With("permission", access.Code)
+		}
+		restriction = dafi.Filter{Field: dafi.FilterField(visibility.Organization), Operator: dafi.In, Value: access.Organizations}
+	default:
+		return nil, unsupportedScope(access)
+	}
+
+	return append(filters.Grouped(), restriction), nil
+}
+
+// RestrictCriteria restricts the filters of criteria, see Restrict.
+func RestrictCriteria(ctx context.Context, criteria dafi.Criteria, visibility Visibility) (dafi.Criteria, error) {
+	filters, err := Restrict(ctx, criteria.Filters, visibility)
+	if err != nil {
+		return dafi.Criteria{}, err
+	}
+
+	criteria.Filters = filters
+	return criteria, nil
+}
+
+func unsupportedScope(access Access) error {
+	return fault.New("visibility scope is not supported by the resource").
+		With("permission", access.Code).
+		With("scope", access.Scope)
+}
@@ -0,0 +1,106 @@
+// Package session keeps in memory what the API must know about login
+// sessions on every request. Access tokens carry the id of their session
+// (the sid claim) and cannot be revoked, so revoked sessions are held in a
+// Denylist until their last access token expires. A Throttle limits how
+// often each session is checked against the database.
+//
+// Both are local to the process: other instances learn of a revocation
+// when they next check the session, at most one throttle interval later.
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Denylist holds revoked sessions until the access tokens issued for them
+// expire.
+type Denylist struct {
+	now func() time.Time
+
+	mu      sync.RWMutex
+	entries map[uuid.UUID]time.Time
+}
+
+func NewDenylist() *Denylist {
+	return &Denylist{
+		now:     time.Now,
+		entries: make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Add denies the sessions until the given time. Expired entries are
+// dropped, since revocations are rare compared to lookups.
+func (d *Denylist) Add(until time.Time, ids ...uuid.UUID) {
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for id, expiresAt := range d.entries {
+		if !now.Before(expiresAt) {
+			delete(d.entries, id)
+		}
+	}
+	for _, id := range ids {
+		if until.After(d.entries[id]) {
+			d.entries[id] = until
+		}
+	}
+}
+
+// Contains reports whether the session is denied.
+func (d *Denylist) Contains(id uuid.UUID) bool {
+	d.mu.RLock()
+	until, ok := d.entries[id]
+	d.mu.RUnlock()
+
+	return ok && d.now().Before(until)
+}
+
+// Throttle lets each session through at most once per interval, e.g. to
+// record when it was last seen without writing on every request.
+type Throttle struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	last     map[uuid.UUID]time.Time
+	purgedAt time.Time
+}
+
+func NewThrottle(interval time.Duration) *Throttle {
+	return &Throttle{
+		interval: interval,
+		now:      time.Now,
+		last:     make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Due reports whether the session was not let through during the last
+// interval, and records it as let through when it was not.
+func (t *Throttle) Due(id uuid.UUID) bool {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Sessions not seen for an interval are due anyway, so they are
+	// forgotten once per interval
+	if now.Sub(t.purgedAt) >= t.interval {
+		for id, at := range t.last {
+			if now.Sub(at) >= t.interval {
+				delete(t.last, id)
+			}
+		}
+		t.purgedAt = now
+	}
+
+	if at, ok := t.last[id]; ok && now.Sub(at) < t.interval {
+		return false
+	}
+	t.last[id] = now
+	return true
+}
@@ -0,0 +1,96 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDenylist(t *testing.T) {
+	now := time.Now()
+	denylist := NewDenylist()
+	denylist.now = func() time.Time { return now }
+
+	revoked, other, expired := uuid.New(), uuid.New(), uuid.New()
+	denylist.Add(now.Add(-time.Second), expired)
+	denylist.Add(now.Add(time.Minute), revoked)
+	// A shorter deny does not shorten an existing one
+	denylist.Add(now.Add(time.Second), revoked)
+
+	tests := []struct {
+		name  string
+		id    uuid.UUID
+		after time.Duration
+		want  bool
+	}{
+		{name: "revoked", id: revoked, want: true},
+		{name: "revoked after the shorter deny", id: revoked, after: 30 * time.Second, want: true},
+		{name: "revoked after the access tokens expired", id: revoked, after: time.Minute},
+		{name: "not revoked", id: other},
+		{name: "expired", id: expired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			denylist.now = func() time.Time { return now.Add(tt.after) }
+			assert.Equal(t, tt.want, denylist.Contains(tt.id))
+		})
+	}
+}
+
+func TestDenylist_AddDropsExpiredEntries(t *testing.T) {
+	now := time.Now()
+	denylist := NewDenylist()
+	denylist.now = func() time.Time { return now }
+
+	old := uuid.New()
+	denylist.Add(now.Add(time.Minute), old)
+
+	denylist.now = func() time.Time { return now.Add(time.Hour) }
+	denylist.Add(now.Add(2*time.Hour), uuid.New())
+
+	assert.NotContains(t, denylist.entries, old)
+	assert.Len(t, denylist.entries, 1)
+}
+
+func TestThrottle_Due(t *testing.T) {
+	now := time.Now()
+	throttle := NewThrottle(time.Minute)
+	throttle.now = func() time.Time { return now }
+
+	id := uuid.New()
+	steps := []struct {
+		name  string
+		id    uuid.UUID
+		after time.Duration
+		want  bool
+	}{
+		{name: "first request", id: id, want: true},
+		{name: "same interval", id: id, after: 59 * time.Second},
+		{name: "other session", id: uuid.New(), after: 59 * time.Second, want: true},
+		{name: "next interval", id: id, after: time.Minute, want: true},
+		{name: "same interval again", id: id, after: time.Minute + time.Second},
+	}
+
+	for _, step := range steps {
+		throttle.now = func() time.Time { return now.Add(step.after) }
+		assert.Equal(t, step.want, throttle.Due(step.id), step.name)
+	}
+}
+
+func TestThrottle_ForgetsIdleSessions(t *testing.T) {
+	now := time.Now()
+	throttle := NewThrottle(time.Minute)
+	throttle.now = func() time.Time { return now }
+
+	idle := uuid.New()
+	throttle.Due(idle)
+
+	throttle.now = func() time.Time { return now.Add(2 * time.Minute) }
+	throttle.Due(uuid.New())
+
+	assert.NotContains(t, throttle.last, idle)
+	assert.Len(t, throttle.last, 1)
+}
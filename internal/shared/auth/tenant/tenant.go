@@ -0,0 +1,191 @@
+// Package tenant scopes requests to the organizations (tenants) they act
+// on. The tenant of a private request is resolved from the principal or
+// the X-Organization-ID header (Resolve) and stored in the request
+// context. Repositories then filter rows by it and check the organization
+// of inserted rows (Restrict, Assign).
+//
+// Requests without a tenant, e.g. public routes or background jobs, are
+// not scoped. Neither are tables without organization_id, e.g. auth.users.
+package tenant
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+const (
+	// Header selects the organization a request acts on
+	Header = "X-Organization-ID"
+	// AllOrganizations is the Header value root organization members
+	// send to act on every organization
+	AllOrganizations = "*"
+)
+
+// Tenant is the set of organizations a request acts on.
+type Tenant struct {
+	// Organizations the request may read and write. It holds a single
+	// organization when one was selected.
+	Organizations []uuid.UUID
+	// All is set when a root organization member acts on every
+	// organization. Nothing is filtered then.
+	All bool
+}
+
+// Within returns the tenant limited to the organizations, e.g. the ones an
+// action is granted in. Tenants acting on every organization are returned
+// as they are.
+func (t Tenant) Within(organizations []uuid.UUID) Tenant {
+	if t.All {
+		return t
+	}
+
+	within := make([]uuid.UUID, 0, len(t.Organizations))
+	for _, organizationID := range t.Organizations {
+		if slices.Contains(organizations, organizationID) {
+			within = append(within, organizationID)
+		}
+	}
+	return Tenant{Organizations: within}
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying t.
+func WithTenant(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, t)
+}
+
+// From returns the tenant stored in ctx, if any.
+func From(ctx context.Context) (Tenant, bool) {
+	t, ok := ctx.Value(tenantKey{}).(Tenant)
+	return t, ok
+}
+
+// Memberships loads the organizations principals are members of, e.g.
+// rbac.Authorizer.
+type Memberships interface {
+	Permissions(ctx context.Context, principal auth.Principal) (rbac.Permissions, error)
+}
+
+// Resolve returns the tenant of the principal for the requested
+// organization, the Header value, and the principal to authorize the
+// request with.
+//
+// Without a requested organization the tenant is the organization of the
+// principal, or every organization the user is a member of. The roles of
+// all of them apply then, each action limited to the organizations whose
+// roles grant it (see rbac.Access.Limited). A requested
+// organization must be one of those, unless the user is a member of the
+// root organization: root members may request any organization, or
+// AllOrganizations. When a member selects one of their organizations,
+// the principal is bound to it, so only the roles of that organization
+// apply.
+func Resolve(ctx context.Context, memberships Memberships, principal auth.Principal, requested string) (Tenant, auth.Principal, error) {
+	permissions, err := memberships.Permissions(ctx, principal)
+	if err != nil {
+		return Tenant{}, auth.Principal{}, err
+	}
+
+	if requested == "" {
+		if principal.OrganizationID.Valid {
+			return Tenant{Organizations: []uuid.UUID{principal.OrganizationID.UUID}}, principal, nil
+		}
+		return Tenant{Organizations: permissions.Organizations}, principal, nil
+	}
+
+	if requested == AllOrganizations {
+		if !permissions.Root {
+			return Tenant{}, auth.Principal{}, notAccessible(requested)
+		}
+		return Tenant{All: true}, principal, nil
+	}
+
+	organizationID, err := uuid.Parse(requested)
+	if err != nil {
+		return Tenant{}, auth.Principal{}, fault.Wrap(err).
+			Code(fault.BadRequest).
+			Message("invalid " + Header + " header")
+	}
+
+	member := slices.Contains(permissions.Organizations, organizationID)
+	switch {
+	case member:
+		principal.OrganizationID = uuid.NullUUID{UUID: organizationID, Valid: true}
+	case !permissions.Root:
+		return Tenant{}, auth.Principal{}, notAccessible(requested)
+	}
+
+	return Tenant{Organizations: []uuid.UUID{organizationID}}, principal, nil
+}
+
+// Restrict adds to filters the condition limiting field, the column
+// holding the organization of a row, to the tenant of ctx. Filters are
+// left as they are when ctx carries no tenant or acts on every
+// organization.
+func Restrict(ctx context.Context, filters dafi.Filters, field string) (dafi.Filters, error) {
+	t, ok := From(ctx)
+	if !ok || t.All {
+		return filters, nil
+	}
+
+	var restriction dafi.Filter
+	switch len(t.Organizations) {
+	case 0:
+		return nil, fault.New("you are not a member of any organization").Code(fault.Forbidden)
+	case 1:
+		restriction = dafi.Filter{Field: dafi.FilterField(field), Operator: dafi.Equal, Value: t.Organizations[0]}
+	default:
+		restriction = dafi.Filter{Field: dafi.FilterField(field), Operator: dafi.In, Value: t.Organizations}
+	}
+
+	return append(filters.Grouped(), restriction), nil
+}
+
+// RestrictCriteria restricts the filters of criteria, see Restrict.
+func RestrictCriteria(ctx context.Context, criteria dafi.Criteria, field string) (dafi.Criteria, error) {
+	filters, err := Restrict(ctx, criteria.Filters, field)
+	if err != nil {
+		return dafi.Criteria{}, err
+	}
+
+	criteria.Filters = filters
+	return criteria, nil
+}
+
+// Assign returns the organization a row inserted with organizationID
+// belongs to. uuid.Nil is replaced with the organization of the tenant,
+// which must be a single one. Organizations outside the tenant are
+// rejected with a fault.Forbidden error. Without a tenant in ctx
+// organizationID is returned as is.
+func Assign(ctx context.Context, organizationID uuid.UUID) (uuid.UUID, error) {
+	t, ok := From(ctx)
+	if !ok {
+		return organizationID, nil
+	}
+
+	if organizationID == uuid.Nil {
+		if len(t.Organizations) != 1 {
+			return uuid.Nil, fault.New("organization is required, select one with the " + Header + " header").
+				Code(fault.BadRequest)
+		}
+		return t.Organizations[0], nil
+	}
+
+	if !t.All && !slices.Contains(t.Organizations, organizationID) {
+		return uuid.Nil, notAccessible(organizationID.String())
+	}
+	return organizationID, nil
+}
+
+func notAccessible(organization string) error {
+	return fault.New("organization not accessible").
+		Code(fault.Forbidden).
+		With("organization_id", organization)
+}
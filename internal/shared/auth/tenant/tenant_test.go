@@ -0,0 +1,247 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+type fakeMemberships map[uuid.UUID]rbac.Permissions
+
+func (f fakeMemberships) Permissions(_ context.Context, principal auth.Principal) (rbac.Permissions, error) {
+	return f[principal.UserID], nil
+}
+
+func TestResolve(t *testing.T) {
+	member, root := uuid.New(), uuid.New()
+	orgA, orgB, other := uuid.New(), uuid.New(), uuid.New()
+	memberships := fakeMemberships{
+		member: {Organizations: []uuid.UUID{orgA, orgB}},
+		root:   {Organizations: []uuid.UUID{orgA}, Root: true},
+	}
+	bound := func(id uuid.UUID) uuid.NullUUID { return uuid.NullUUID{UUID: id, Valid: true} }
+
+	tests := []struct {
+		name          string
+		principal     auth.Principal
+		requested     string
+		want          Tenant
+		wantPrincipal auth.Principal
+		wantCode      fault.Code
+	}{
+		{
+			name:          "memberships",
+			principal:     auth.Principal{UserID: member},
+			want:          Tenant{Organizations: []uuid.UUID{orgA, orgB}},
+			wantPrincipal: auth.Principal{UserID: member},
+		},
+		{
+			name:          "principal organization",
+			principal:     auth.Principal{UserID: member, OrganizationID: bound(orgB)},
+			want:          Tenant{Organizations: []uuid.UUID{orgB}},
+			wantPrincipal: auth.Principal{UserID: member, OrganizationID: bound(orgB)},
+		},
+		{
+			name:          "requested membership binds the principal",
+			principal:     auth.Principal{UserID: member},
+			requested:     orgB.String(),
+			want:          Tenant{Organizations: []uuid.UUID{orgB}},
+			wantPrincipal: auth.Principal{UserID: member, OrganizationID: bound(orgB)},
+		},
+		{
+			name:      "requested foreign organization",
+			principal: auth.Principal{UserID: member},
+			requested: other.String(),
+			wantCode:  fault.Forbidden,
+		},
+		{
+			name:      "requested every organization",
+			principal: auth.Principal{UserID: member},
+			requested: AllOrganizations,
+			wantCode:  fault.Forbidden,
+		},
+		{
+			name:      "invalid organization",
+			principal: auth.Principal{UserID: member},
+			requested: "not-a-uuid",
+			wantCode:  fault.BadRequest,
+		},
+		{
+			name:          "root crosses tenants",
+			principal:     auth.Principal{UserID: root},
+			requested:     other.String(),
+			want:          Tenant{Organizations: []uuid.UUID{other}},
+			wantPrincipal: auth.Principal{UserID: root},
+		},
+		{
+			name:          "root acts on every organization",
+			principal:     auth.Principal{UserID: root},
+			requested:     AllOrganizations,
+			want:          Tenant{All: true},
+			wantPrincipal: auth.Principal{UserID: root},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, principal, err := Resolve(context.Background(), memberships, tt.principal, tt.requested)
+
+			if tt.wantCode != "" {
+				assert.Equal(t, tt.wantCode, fault.CodeOf(err), "error: %v", err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantPrincipal, principal)
+		})
+	}
+}
+
+func TestRestrict(t *testing.T) {
+	orgA, orgB := uuid.New(), uuid.New()
+	base := dafi.FilterBy("is_active", dafi.Equal, true)
+
+	tests := []struct {
+		name     string
+		tenant   *Tenant
+		want     dafi.Filters
+		wantCode fault.Code
+	}{
+		{
+			name: "no tenant",
+			want: base,
+		},
+		{
+			name:   "every organization",
+			tenant: &Tenant{All: true},
+			want:   base,
+		},
+		{
+			name:   "single organization",
+			tenant: &Tenant{Organizations: []uuid.UUID{orgA}},
+			want: dafi.Filters{
+				{Field: "is_active", Operator: dafi.Equal, Value: true, ChainingKey: dafi.And},
+				{Field: "organization_id", Operator: dafi.Equal, Value: orgA},
+			},
+		},
+		{
+			name:   "several organizations",
+			tenant: &Tenant{Organizations: []uuid.UUID{orgA, orgB}},
+			want: dafi.Filters{
+				{Field: "is_active", Operator: dafi.Equal, Value: true, ChainingKey: dafi.And},
+				{Field: "organization_id", Operator: dafi.In, Value: []uuid.UUID{orgA, orgB}},
+			},
+		},
+		{
+			name:     "no organization",
+			tenant:   &Tenant{},
+			wantCode: fault.Forbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.tenant != nil {
+				ctx = WithTenant(ctx, *tt.tenant)
+			}
+
+			got, err := Restrict(ctx, base, "organization_id")
+
+			if tt.wantCode != "" {
+				assert.Equal(t, tt.wantCode, fault.CodeOf(err), "error: %v", err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// The filters of the caller are never modified
+	assert.Equal(t, dafi.FilterBy("is_active", dafi.Equal, true), base)
+}
+
+func TestAssign(t *testing.T) {
+	orgA, orgB := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		tenant         *Tenant
+		organizationID uuid.UUID
+		want           uuid.UUID
+		wantCode       fault.Code
+	}{
+		{name: "no tenant", organizationID: orgB, want: orgB},
+		{name: "defaults to the tenant", tenant: &Tenant{Organizations: []uuid.UUID{orgA}}, want: orgA},
+		{name: "within the tenant", tenant: &Tenant{Organizations: []uuid.UUID{orgA, orgB}}, organizationID: orgB, want: orgB},
+		{name: "outside the tenant", tenant: &Tenant{Organizations: []uuid.UUID{orgA}}, organizationID: orgB, wantCode: fault.Forbidden},
+		{name: "ambiguous tenant", tenant: &Tenant{Organizations: []uuid.UUID{orgA, orgB}}, wantCode: fault.BadRequest},
+		{name: "every organization", tenant: &Tenant{All: true}, organizationID: orgB, want: orgB},
+		{name: "every organization requires one", tenant: &Tenant{All: true}, wantCode: fault.BadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.tenant != nil {
+				ctx = WithTenant(ctx, *tt.tenant)
+			}
+
+			got, err := Assign(ctx, tt.organizationID)
+
+			if tt.wantCode != "" {
+				assert.Equal(t, tt.wantCode, fault.CodeOf(err), "error: %v", err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTenant_Within(t *testing.T) {
+	orgA, orgB, orgC := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name          string
+		tenant        Tenant
+		organizations []uuid.UUID
+		want          Tenant
+	}{
+		{
+			name:          "limited",
+			tenant:        Tenant{Organizations: []uuid.UUID{orgA, orgB}},
+			organizations: []uuid.UUID{orgB, orgC},
+			want:          Tenant{Organizations: []uuid.UUID{orgB}},
+		},
+		{
+			name:          "nothing in common",
+			tenant:        Tenant{Organizations: []uuid.UUID{orgA}},
+			organizations: []uuid.UUID{orgC},
+			want:          Tenant{Organizations: []uuid.UUID{}},
+		},
+		{
+			name:          "every organization",
+			tenant:        Tenant{All: true},
+			organizations: []uuid.UUID{orgA},
+			want:          Tenant{All: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.tenant.Within(tt.organizations))
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// DefaultAccessTokenTTL is the lifetime of access tokens. They cannot be
+// revoked, only denied with their session, so keep it short and rely on
+// refresh tokens.
+const DefaultAccessTokenTTL = 15 * time.Minute
+
+type IssuerConfig struct {
+	Issuer   string
+	Audience string
+	// TTL defaults to DefaultAccessTokenTTL
+	TTL time.Duration
+}
+
+// Issuer signs access tokens. It signs with HS256 and the shared secret,
+// unless a private key is set with WithSigningKey.
+type Issuer struct {
+	config IssuerConfig
+	method jwt.SigningMethod
+	key    any
+	kid    string
+	now    func() time.Time
+}
+
+func NewIssuer(secret []byte, config IssuerConfig) *Issuer {
+	if config.TTL == 0 {
+		config.TTL = DefaultAccessTokenTTL
+	}
+
+	return &Issuer{
+		config: config,
+		method: jwt.SigningMethodHS256,
+		key:    secret,
+		now:    time.Now,
+	}
+}
+
+// WithSigningKey signs with an RSA (RS256) or Ed25519 (EdDSA) private key.
+// Its public key must be published under kid in the JWKS file.
+func (i *Issuer) WithSigningKey(kid string, key any) (*Issuer, error) {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		i.method = jwt.SigningMethodRS256
+	case ed25519.PrivateKey:
+		i.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fault.New("unsupported signing key").Code(fault.InternalError)
+	}
+
+	i.key = key
+	i.kid = kid
+	return i, nil
+}
+
+// TTL returns the lifetime of the issued tokens.
+func (i *Issuer) TTL() time.Duration {
+	return i.config.TTL
+}
+
+// Issue signs an access token for p.
+func (i *Issuer) Issue(p auth.Principal) (string, error) {
+	now := i.now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   p.UserID.String(),
+			Issuer:    i.config.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.config.TTL)),
+		},
+		Roles: p.Roles,
+	}
+	if i.config.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{i.config.Audience}
+	}
+	if p.OrganizationID.Valid {
+		claims.OrganizationID = p.OrganizationID.UUID.String()
+	}
+	if p.SessionID.Valid {
+		claims.SessionID = p.SessionID.UUID.String()
+	}
+
+	t := jwt.NewWithClaims(i.method, claims)
+	if i.kid != "" {
+		t.Header["kid"] = i.kid
+	}
+
+	signed, err := t.SignedString(i.key)
+	if err != nil {
+		return "", fault.Wrap(err).Message("failed to sign access token")
+	}
+	return signed, nil
+}
+
+// LoadPrivateKey reads a PKCS#8 PEM encoded RSA or Ed25519 private key.
+func LoadPrivateKey(path string) (any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to read signing key")
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fault.New("signing key is not PEM encoded").With("path", path)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fault.Wrap(err).Message("failed to parse signing key").With("path", path)
+	}
+	return key, nil
+}
@@ -0,0 +1,88 @@
+package token
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+)
+
+func TestIssuer_Issue(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksPath, ed25519JWK("ed-2024", publicKey))
+
+	keys, err := NewKeySet(testSecret, jwksPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	config := IssuerConfig{Issuer: "https://api.example.com", Audience: "api", TTL: 5 * time.Minute}
+	verifier := NewVerifier(keys, Config{Issuer: config.Issuer, Audience: config.Audience})
+
+	signed, err := NewIssuer(testSecret, config).WithSigningKey("ed-2024", privateKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		issuer *Issuer
+	}{
+		{name: "HS256 with the shared secret", issuer: NewIssuer(testSecret, config)},
+		{name: "EdDSA with a signing key", issuer: signed},
+	}
+
+	principal := auth.Principal{
+		UserID:         uuid.New(),
+		OrganizationID: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		SessionID:      uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		Roles:          []string{"admin", "sales"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := tt.issuer.Issue(principal)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got, err := verifier.Verify(context.Background(), raw)
+			if err != nil {
+				t.Fatalf("Expected the issued token to verify, got %v", err)
+			}
+			if got.UserID != principal.UserID || got.OrganizationID != principal.OrganizationID ||
+				got.SessionID != principal.SessionID || len(got.Roles) != 2 {
+				t.Errorf("Expected principal %+v, got %+v", principal, got)
+			}
+		})
+	}
+
+	t.Run("expires after the TTL", func(t *testing.T) {
+		issuer := NewIssuer(testSecret, config)
+		issuer.now = func() time.Time { return time.Now().Add(-time.Hour) }
+
+		raw, err := issuer.Issue(principal)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := verifier.Verify(context.Background(), raw); err == nil {
+			t.Error("Expected an expired token")
+		}
+	})
+}
+
+func TestIssuer_WithSigningKey(t *testing.T) {
+	if _, err := NewIssuer(testSecret, IssuerConfig{}).WithSigningKey("kid", []byte("secret")); err == nil {
+		t.Error("Expected unsupported key error")
+	}
+}
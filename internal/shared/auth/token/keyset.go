@@ -0,0 +1,257 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// Supported signing algorithms.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+const (
+	// DefaultRefreshInterval is how often the JWKS file is checked for
+	// changes, so removed keys stop being accepted
+	DefaultRefreshInterval = time.Minute
+	// DefaultMinReloadInterval throttles the reloads caused by unknown key
+	// ids, so tokens with made-up key ids cannot make every request read
+	// the JWKS file. New key ids are picked up within it.
+	DefaultMinReloadInterval = 5 * time.Second
+)
+
+type key struct {
+	alg string
+	// value is a []byte, *rsa.PublicKey or ed25519.PublicKey
+	value any
+}
+
+// KeySet resolves verification keys by key id (kid). Tokens without a kid
+// are verified with the shared HS256 secret. Keys with a kid come from a
+// local JWKS file, which is reloaded when it changes, so keys can be rotated
+// by publishing the new key next to the old one and removing the old one
+// once its tokens have expired. Unknown key ids reload the file at most
+// once every DefaultMinReloadInterval.
+type KeySet struct {
+	secret            []byte
+	path              string
+	refreshInterval   time.Duration
+	minReloadInterval time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]key
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewKeySet returns a key set with the HS256 secret and the keys of the
+// JWKS file at path. Both are optional, but at least one is required. The
+// file is loaded right away, so a broken file fails at startup.
+func NewKeySet(secret []byte, path string) (*KeySet, error) {
+	if len(secret) == 0 && path == "" {
+		return nil, fault.New("a secret or a JWKS file is required").Code(fault.InternalError)
+	}
+
+	s := &KeySet{
+		secret:            secret,
+		path:              path,
+		refreshInterval:   DefaultRefreshInterval,
+		minReloadInterval: DefaultMinReloadInterval,
+		keys:              map[string]key{},
+	}
+
+	if path != "" {
+		if err := s.reload(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Lookup returns the key for kid, checking that it is meant for alg.
+func (s *KeySet) Lookup(kid, alg string) (any, error) {
+	if kid == "" {
+		if alg != HS256 || len(s.secret) == 0 {
+			return nil, fault.New("token has no key id").Code(fault.Unauthorized)
+		}
+		return s.secret, nil
+	}
+
+	if s.path == "" {
+		return nil, fault.New("unknown key id").Code(fault.Unauthorized).With("kid", kid)
+	}
+
+	found, ok := s.get(kid)
+	interval := s.refreshInterval
+	if !ok {
+		interval = min(interval, s.minReloadInterval)
+	}
+	if s.claimReload(interval) {
+		// A failed reload keeps the keys loaded before
+		_ = s.reload()
+		found, ok = s.get(kid)
+	}
+	if !ok {
+		return nil, fault.New("unknown key id").Code(fault.Unauthorized).With("kid", kid)
+	}
+
+	if found.alg != alg {
+		return nil, fault.New("key is not valid for the token algorithm").Code(fault.Unauthorized).
+			With("kid", kid).
+			With("alg", alg)
+	}
+
+	return found.value, nil
+}
+
+func (s *KeySet) get(kid string) (key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	found, ok := s.keys[kid]
+	return found, ok
+}
+
+// claimReload reports whether the file was last checked more than interval
+// ago. Only one of the concurrent callers gets true, the others keep using
+// the loaded keys.
+func (s *KeySet) claimReload(interval time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.checkedAt) <= interval {
+		return false
+	}
+	s.checkedAt = now
+	return true
+}
+
+// reload parses the JWKS file again when its modification time changed.
+func (s *KeySet) reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to read JWKS file")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkedAt = time.Now()
+	if info.ModTime().Equal(s.modTime) && len(s.keys) > 0 {
+		return nil
+	}
+
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to read JWKS file")
+	}
+
+	keys, err := parseJWKS(content)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to parse JWKS file").With("path", s.path)
+	}
+
+	s.keys = keys
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// jwk is a JSON Web Key (RFC 7517). Only the members needed for RSA,
+// Ed25519 and HMAC verification keys are read.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	K   string `json:"k"`
+}
+
+func parseJWKS(content []byte) (map[string]key, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]key, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if k.Kid == "" {
+			return nil, fmt.Errorf("key without kid")
+		}
+
+		parsed, err := parseJWK(k)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", k.Kid, err)
+		}
+		if k.Alg != "" && k.Alg != parsed.alg {
+			return nil, fmt.Errorf("key %s: unsupported alg %s for kty %s", k.Kid, k.Alg, k.Kty)
+		}
+		keys[k.Kid] = parsed
+	}
+
+	return keys, nil
+}
+
+func parseJWK(k jwk) (key, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return key{}, fmt.Errorf("invalid n: %w", err)
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return key{}, fmt.Errorf("invalid e: %w", err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 {
+			return key{}, fmt.Errorf("invalid e")
+		}
+		return key{alg: RS256, value: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return key{}, fmt.Errorf("unsupported crv %s", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return key{}, fmt.Errorf("invalid x")
+		}
+		return key{alg: EdDSA, value: ed25519.PublicKey(x)}, nil
+
+	case "oct":
+		secret, err := decodeSegment(k.K)
+		if err != nil || len(secret) == 0 {
+			return key{}, fmt.Errorf("invalid k")
+		}
+		return key{alg: HS256, value: secret}, nil
+
+	default:
+		return key{}, fmt.Errorf("unsupported kty %s", k.Kty)
+	}
+}
+
+func decodeSegment(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(value)
+}
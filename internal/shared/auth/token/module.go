@@ -0,0 +1,55 @@
+package token
+
+import (
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/localconfig"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Module("token",
+	fx.Provide(
+		NewVerifierFromConfig,
+		fx.Annotate(
+			NewIssuerFromConfig,
+			fx.As(fx.Self()),
+			fx.As(new(ports.AccessTokenIssuer)),
+		),
+	),
+)
+
+func NewVerifierFromConfig(config *localconfig.Config) (*Verifier, error) {
+	keys, err := NewKeySet([]byte(config.JWT.Secret), config.JWT.JWKSFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVerifier(keys, Config{
+		Issuer:     config.JWT.Issuer,
+		Audience:   config.JWT.Audience,
+		ClockSkew:  config.JWT.ClockSkew,
+		Algorithms: config.JWT.Algorithms,
+	}), nil
+}
+
+func NewIssuerFromConfig(config *localconfig.Config) (*Issuer, error) {
+	issuer := NewIssuer([]byte(config.JWT.Secret), IssuerConfig{
+		Issuer:   config.JWT.Issuer,
+		Audience: config.JWT.Audience,
+		TTL:      config.JWT.AccessTokenTTL,
+	})
+
+	if config.JWT.SigningKeyFile == "" {
+		if config.JWT.Secret == "" {
+			return nil, fault.New("JWT_SECRET or JWT_SIGNING_KEY_FILE is required to issue tokens")
+		}
+		return issuer, nil
+	}
+
+	key, err := LoadPrivateKey(config.JWT.SigningKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return issuer.WithSigningKey(config.JWT.SigningKeyID, key)
+}
@@ -0,0 +1,147 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+const (
+	// DefaultRemoteMaxAge is how long the keys of a remote JWKS are used
+	// before they are fetched again
+	DefaultRemoteMaxAge = time.Hour
+	// DefaultRemoteMinRefreshInterval throttles the fetches caused by
+	// unknown key ids, so tokens with made-up key ids cannot flood the
+	// JWKS endpoint
+	DefaultRemoteMinRefreshInterval = 10 * time.Second
+)
+
+// maxJWKSSize bounds the remote JWKS documents read
+const maxJWKSSize = 1 << 20
+
+// RemoteKeySet resolves the verification keys of tokens issued by another
+// party, e.g. the ID tokens of an OIDC provider, from its JWKS URL. Keys
+// are cached for MaxAge. An unknown key id fetches the set again, at most
+// once every MinRefreshInterval, so rotated keys are picked up right away.
+// Only RSA and Ed25519 keys are used; shared secrets are never accepted
+// from a remote set.
+type RemoteKeySet struct {
+	url    string
+	client *http.Client
+
+	MaxAge             time.Duration
+	MinRefreshInterval time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]key
+	fetchedAt time.Time
+}
+
+// NewRemoteKeySet returns a key set fetched from url with client. Keys are
+// fetched on first use.
+func NewRemoteKeySet(url string, client *http.Client) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:                url,
+		client:             client,
+		MaxAge:             DefaultRemoteMaxAge,
+		MinRefreshInterval: DefaultRemoteMinRefreshInterval,
+	}
+}
+
+// Lookup returns the key for kid, checking that it is meant for alg.
+// Without a kid the only key of the set for alg is returned.
+func (s *RemoteKeySet) Lookup(ctx context.Context, kid, alg string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	found, ok := s.find(kid, alg)
+	if !ok || now.Sub(s.fetchedAt) > s.MaxAge {
+		if s.keys == nil || now.Sub(s.fetchedAt) > s.MinRefreshInterval {
+			// A failed fetch keeps the keys fetched before
+			if err := s.fetch(ctx); err != nil && s.keys == nil {
+				return nil, err
+			}
+			found, ok = s.find(kid, alg)
+		}
+	}
+	if !ok {
+		return nil, fault.New("unknown key id").Code(fault.Unauthorized).With("kid", kid)
+	}
+
+	if found.alg != alg {
+		return nil, fault.New("key is not valid for the token algorithm").Code(fault.Unauthorized).
+			With("kid", kid).
+			With("alg", alg)
+	}
+
+	return found.value, nil
+}
+
+func (s *RemoteKeySet) find(kid, alg string) (key, bool) {
+	if kid != "" {
+		found, ok := s.keys[kid]
+		return found, ok
+	}
+
+	var only key
+	matches := 0
+	for _, k := range s.keys {
+		if k.alg == alg {
+			only = k
+			matches++
+		}
+	}
+	return only, matches == 1
+}
+
+// fetch replaces the keys with the ones of the remote set. Keys of
+// unsupported types are skipped, so one of them does not break the rest.
+func (s *RemoteKeySet) fetch(ctx context.Context) error {
+	s.fetchedAt = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to fetch JWKS")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fault.Wrap(err).Code(fault.Unauthorized).Message("failed to fetch JWKS").With("url", s.url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fault.New("failed to fetch JWKS").Code(fault.Unauthorized).
+			With("url", s.url).
+			With("status", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&set); err != nil {
+		return fault.Wrap(err).Code(fault.Unauthorized).Message("failed to parse JWKS").With("url", s.url)
+	}
+
+	keys := make(map[string]key, len(set.Keys))
+	for _, k := range set.Keys {
+		if (k.Use != "" && k.Use != "sig") || k.Kty == "oct" {
+			continue
+		}
+		parsed, err := parseJWK(k)
+		if err != nil || (k.Alg != "" && k.Alg != parsed.alg) {
+			continue
+		}
+		keys[k.Kid] = parsed
+	}
+
+	s.keys = keys
+	return nil
+}
@@ -0,0 +1,133 @@
+// Package token verifies the JWT access tokens of the API.
+package token
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// DefaultClockSkew is the leeway applied to exp, nbf and iat.
+const DefaultClockSkew = 30 * time.Second
+
+// Claims are the claims of an access token. The subject is the user id.
+type Claims struct {
+	jwt.RegisteredClaims
+	OrganizationID string   `json:"org_id,omitempty"`
+	SessionID      string   `json:"sid,omitempty"`
+	Roles          []string `json:"roles,omitempty"`
+}
+
+type Config struct {
+	// Issuer is the required iss claim. Empty accepts any issuer.
+	Issuer string
+	// Audience must be one of the aud claims. Empty accepts any audience.
+	Audience string
+	// ClockSkew is the leeway for exp, nbf and iat. Defaults to
+	// DefaultClockSkew.
+	ClockSkew time.Duration
+	// Algorithms lists the accepted algorithms. Defaults to HS256, RS256
+	// and EdDSA.
+	Algorithms []string
+}
+
+// Verifier validates access tokens and turns them into principals.
+type Verifier struct {
+	keys   *KeySet
+	parser *jwt.Parser
+}
+
+func NewVerifier(keys *KeySet, config Config) *Verifier {
+	if config.ClockSkew == 0 {
+		config.ClockSkew = DefaultClockSkew
+	}
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = []string{HS256, RS256, EdDSA}
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(config.Algorithms),
+		jwt.WithLeeway(config.ClockSkew),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &Verifier{
+		keys:   keys,
+		parser: jwt.NewParser(options...),
+	}
+}
+
+// Verify checks the signature and claims of raw and returns its principal.
+// Every error is coded fault.Unauthorized.
+func (v *Verifier) Verify(ctx context.Context, raw string) (auth.Principal, error) {
+	claims := &Claims{}
+	_, err := v.parser.ParseWithClaims(raw, claims, v.keyFunc)
+	if err != nil {
+		return auth.Principal{}, fault.Wrap(err).Code(fault.Unauthorized).Message(verifyMessage(err))
+	}
+
+	return principal(claims)
+}
+
+func (v *Verifier) keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	return v.keys.Lookup(kid, t.Method.Alg())
+}
+
+func principal(claims *Claims) (auth.Principal, error) {
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return auth.Principal{}, fault.Wrap(err).Code(fault.Unauthorized).Message("invalid token subject")
+	}
+
+	p := auth.Principal{
+		UserID: userID,
+		Roles:  claims.Roles,
+	}
+
+	if claims.OrganizationID != "" {
+		organizationID, err := uuid.Parse(claims.OrganizationID)
+		if err != nil {
+			return auth.Principal{}, fault.Wrap(err).Code(fault.Unauthorized).Message("invalid token organization")
+		}
+		p.OrganizationID = uuid.NullUUID{UUID: organizationID, Valid: true}
+	}
+
+	if claims.SessionID != "" {
+		sessionID, err := uuid.Parse(claims.SessionID)
+		if err != nil {
+			return auth.Principal{}, fault.Wrap(err).Code(fault.Unauthorized).Message("invalid token session")
+		}
+		p.SessionID = uuid.NullUUID{UUID: sessionID, Valid: true}
+	}
+
+	return p, nil
+}
+
+func verifyMessage(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return "token has expired"
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return "token is not valid yet"
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return "token issuer is not accepted"
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return "token audience is not accepted"
+	default:
+		return "invalid token"
+	}
+}
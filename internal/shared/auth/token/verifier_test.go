@@ -0,0 +1,415 @@
+package token
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+var testSecret = []byte("test-secret-with-enough-entropy!")
+
+type testKeys struct {
+	rsa     *rsa.PrivateKey
+	ed25519 ed25519.PrivateKey
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate Ed25519 key: %v", err)
+	}
+
+	return testKeys{rsa: rsaKey, ed25519: edKey}
+}
+
+func writeJWKS(t *testing.T, path string, keys ...map[string]string) {
+	t.Helper()
+
+	content, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatalf("failed to marshal JWKS: %v", err)
+	}
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("failed to write JWKS: %v", err)
+	}
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"alg": RS256,
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ed25519JWK(kid string, key ed25519.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "OKP",
+		"crv": "Ed25519",
+		"kid": kid,
+		"x":   base64.RawURLEncoding.EncodeToString(key),
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims Claims) string {
+	t.Helper()
+
+	tok := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
+	raw, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return raw
+}
+
+func validClaims(userID uuid.UUID) Claims {
+	now := time.Now()
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			Issuer:    "https://auth.example.com",
+			Audience:  jwt.ClaimStrings{"api"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Roles: []string{"admin"},
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	keys := newTestKeys(t)
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksPath,
+		rsaJWK("rsa-1", &keys.rsa.PublicKey),
+		ed25519JWK("ed-1", keys.ed25519.Public().(ed25519.PublicKey)),
+	)
+
+	keySet, err := NewKeySet(testSecret, jwksPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	verifier := NewVerifier(keySet, Config{
+		Issuer:    "https://auth.example.com",
+		Audience:  "api",
+		ClockSkew: time.Minute,
+	})
+
+	userID := uuid.New()
+	organizationID := uuid.New()
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name          string
+		token         func() string
+		wantErr       string
+		wantOrgID     bool
+		wantSessionID bool
+		wantRoleOf    string
+	}{
+		{
+			name:       "HS256 with the shared secret",
+			token:      func() string { return sign(t, jwt.SigningMethodHS256, "", testSecret, validClaims(userID)) },
+			wantRoleOf: "admin",
+		},
+		{
+			name:  "RS256 from the JWKS file",
+			token: func() string { return sign(t, jwt.SigningMethodRS256, "rsa-1", keys.rsa, validClaims(userID)) },
+		},
+		{
+			name: "EdDSA with organization",
+			token: func() string {
+				claims := validClaims(userID)
+				claims.OrganizationID = organizationID.String()
+				return sign(t, jwt.SigningMethodEdDSA, "ed-1", keys.ed25519, claims)
+			},
+			wantOrgID: true,
+		},
+		{
+			name: "with session",
+			token: func() string {
+				claims := validClaims(userID)
+				claims.SessionID = sessionID.String()
+				return sign(t, jwt.SigningMethodHS256, "", testSecret, claims)
+			},
+			wantSessionID: true,
+		},
+		{
+			name: "expired within the clock skew",
+			token: func() string {
+				claims := validClaims(userID)
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-30 * time.Second))
+				return sign(t, jwt.SigningMethodHS256, "", testSecret, claims)
+			},
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims(userID)
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-2 * time.Minute))
+				return sign(t, jwt.SigningMethodHS256, "", testSecret, claims)
+			},
+			wantErr: "token has expired",
+		},
+		{
+			name: "without expiration",
+			token: func() string {
+				claims := validClaims(userID)
+				claims.ExpiresAt = nil
+				return sign(t, jwt.SigningMethodHS256, "", testSecret, claims)
+			},
+			wantErr: "invalid token",
+		},
+		{
+			name: "not valid yet",
+			token: func() string {
+				claims := validClaims(userID)
+				claims.NotBefore = jwt.NewNumericDate(time.Now().Add(5 * time.Minute))
+				return sign(t, jwt.SigningMethodHS256, "", testSecret, claims)
+			},
+			wantErr: "token is not valid yet",
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validClaims(userID)
+				claims.Issuer = "https://evil.example.com"
+				return sign(t, jwt.SigningMethodHS256, "", testSecret, claims)
+			},
+			wantErr: "token issuer is not accepted",
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims(userID)
+				claims.Audience = jwt.ClaimStrings{"billing"}
+				return sign(t, jwt.SigningMethodHS256, "", testSecret, claims)
+			},
+			wantErr: "token audience is not accepted",
+		},
+		{
+			name: "wrong secret",
+			token: func() string {
+				return sign(t, jwt.SigningMethodHS256, "", []byte("another-secret"), validClaims(userID))
+			},
+			wantErr: "invalid token",
+		},
+		{
+			name:    "unknown key id",
+			token:   func() string { return sign(t, jwt.SigningMethodRS256, "rsa-9", keys.rsa, validClaims(userID)) },
+			wantErr: "invalid token",
+		},
+		{
+			name: "key used with another algorithm",
+			token: func() string {
+				return sign(t, jwt.SigningMethodHS256, "rsa-1", testSecret, validClaims(userID))
+			},
+			wantErr: "invalid token",
+		},
+		{
+			name: "unsigned",
+			token: func() string {
+				return sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, validClaims(userID))
+			},
+			wantErr: "invalid token",
+		},
+		{
+			name: "subject is not a user id",
+			token: func() string {
+				claims := validClaims(userID)
+				claims.Subject = "admin"
+				return sign(t, jwt.SigningMethodHS256, "", testSecret, claims)
+			},
+			wantErr: "invalid token subject",
+		},
+		{
+			name: "session is not an id",
+			token: func() string {
+				claims := validClaims(userID)
+				claims.SessionID = "current"
+				return sign(t, jwt.SigningMethodHS256, "", testSecret, claims)
+			},
+			wantErr: "invalid token session",
+		},
+		{
+			name:    "malformed",
+			token:   func() string { return "not.a.token" },
+			wantErr: "invalid token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), tt.token())
+
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("Expected error %q, got principal %+v", tt.wantErr, principal)
+				}
+				if fault.CodeOf(err) != fault.Unauthorized {
+					t.Errorf("Expected code %s, got %s", fault.Unauthorized, fault.CodeOf(err))
+				}
+				if got := fault.MessageOf(err); got != tt.wantErr {
+					t.Errorf("Expected message %q, got %q (%v)", tt.wantErr, got, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if principal.UserID != userID {
+				t.Errorf("Expected user %s, got %s", userID, principal.UserID)
+			}
+			if principal.OrganizationID.Valid != tt.wantOrgID {
+				t.Errorf("Expected organization present=%t, got %+v", tt.wantOrgID, principal.OrganizationID)
+			}
+			if tt.wantOrgID && principal.OrganizationID.UUID != organizationID {
+				t.Errorf("Expected organization %s, got %s", organizationID, principal.OrganizationID.UUID)
+			}
+			if principal.SessionID.Valid != tt.wantSessionID || (tt.wantSessionID && principal.SessionID.UUID != sessionID) {
+				t.Errorf("Expected session present=%t, got %+v", tt.wantSessionID, principal.SessionID)
+			}
+			if tt.wantRoleOf != "" && !principal.HasRole(tt.wantRoleOf) {
+				t.Errorf("Expected role %s, got %v", tt.wantRoleOf, principal.Roles)
+			}
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldKeys := newTestKeys(t)
+	newKeys := newTestKeys(t)
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksPath, rsaJWK("2024-01", &oldKeys.rsa.PublicKey))
+
+	keySet, err := NewKeySet(nil, jwksPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	keySet.minReloadInterval = 0
+	verifier := NewVerifier(keySet, Config{})
+
+	claims := validClaims(uuid.New())
+	oldToken := sign(t, jwt.SigningMethodRS256, "2024-01", oldKeys.rsa, claims)
+	newToken := sign(t, jwt.SigningMethodRS256, "2024-02", newKeys.rsa, claims)
+
+	if _, err := verifier.Verify(context.Background(), newToken); err == nil {
+		t.Fatal("Expected the new key to be unknown before rotation")
+	}
+
+	// Publish the new key next to the old one
+	writeJWKS(t, jwksPath, rsaJWK("2024-01", &oldKeys.rsa.PublicKey), rsaJWK("2024-02", &newKeys.rsa.PublicKey))
+	touch(t, jwksPath, time.Now().Add(time.Second))
+
+	if _, err := verifier.Verify(context.Background(), newToken); err != nil {
+		t.Errorf("Expected the new key to be picked up, got %v", err)
+	}
+	if _, err := verifier.Verify(context.Background(), oldToken); err != nil {
+		t.Errorf("Expected the old key to keep working, got %v", err)
+	}
+
+	// Retire the old key
+	writeJWKS(t, jwksPath, rsaJWK("2024-02", &newKeys.rsa.PublicKey))
+	touch(t, jwksPath, time.Now().Add(2*time.Second))
+	keySet.refreshInterval = 0
+
+	if _, err := verifier.Verify(context.Background(), oldToken); err == nil {
+		t.Error("Expected the retired key to be rejected")
+	}
+	if _, err := verifier.Verify(context.Background(), newToken); err != nil {
+		t.Errorf("Expected the new key to keep working, got %v", err)
+	}
+}
+
+func TestKeySet_ThrottlesUnknownKeyIDs(t *testing.T) {
+	oldKeys := newTestKeys(t)
+	newKeys := newTestKeys(t)
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, jwksPath, rsaJWK("2024-01", &oldKeys.rsa.PublicKey))
+
+	keySet, err := NewKeySet(nil, jwksPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	verifier := NewVerifier(keySet, Config{})
+	newToken := sign(t, jwt.SigningMethodRS256, "2024-02", newKeys.rsa, validClaims(uuid.New()))
+
+	writeJWKS(t, jwksPath, rsaJWK("2024-01", &oldKeys.rsa.PublicKey), rsaJWK("2024-02", &newKeys.rsa.PublicKey))
+	touch(t, jwksPath, time.Now().Add(time.Second))
+
+	if _, err := verifier.Verify(context.Background(), newToken); err == nil {
+		t.Error("Expected the file not to be reloaded right after loading it")
+	}
+
+	keySet.mu.Lock()
+	keySet.checkedAt = time.Now().Add(-DefaultMinReloadInterval - time.Second)
+	keySet.mu.Unlock()
+
+	if _, err := verifier.Verify(context.Background(), newToken); err != nil {
+		t.Errorf("Expected the new key to be picked up once the interval passed, got %v", err)
+	}
+}
+
+func TestNewKeySet(t *testing.T) {
+	dir := t.TempDir()
+
+	invalid := filepath.Join(dir, "invalid.json")
+	writeJWKS(t, invalid, map[string]string{"kty": "RSA", "kid": "broken", "n": "!", "e": "AQAB"})
+
+	unsupported := filepath.Join(dir, "unsupported.json")
+	writeJWKS(t, unsupported, map[string]string{"kty": "EC", "kid": "ec-1", "crv": "P-256"})
+
+	tests := []struct {
+		name    string
+		secret  []byte
+		path    string
+		wantErr bool
+	}{
+		{name: "secret only", secret: testSecret},
+		{name: "nothing configured", wantErr: true},
+		{name: "missing file", path: filepath.Join(dir, "missing.json"), wantErr: true},
+		{name: "invalid key", path: invalid, wantErr: true},
+		{name: "unsupported key type", path: unsupported, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeySet(tt.secret, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error=%t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func touch(t *testing.T, path string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("failed to touch %s: %v", path, err)
+	}
+}
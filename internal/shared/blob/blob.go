@@ -0,0 +1,126 @@
+// Package blob stores uploaded files, such as avatars or the documents
+// referenced by file_url columns. Stores implement ports.BlobStore:
+// LocalStore keeps files in a directory and serves them itself, S3Store
+// keeps them in an S3 compatible bucket, e.g. AWS S3 or MinIO. Files are
+// downloaded from signed URLs that expire.
+//
+// Uploads are read with Read, which enforces a size limit and sniffs the
+// content type from the content instead of trusting the client.
+package blob
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+// ImageTypes are the content types Thumbnail decodes
+var ImageTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// extensions are the file name extensions of the content types sniffed by
+// http.DetectContentType that uploads commonly have
+var extensions = map[string]string{
+	"application/pdf":          ".pdf",
+	"application/zip":          ".zip",
+	"application/octet-stream": ".bin",
+	"image/gif":                ".gif",
+	"image/jpeg":               ".jpg",
+	"image/png":                ".png",
+	"image/webp":               ".webp",
+	"text/csv":                 ".csv",
+	"text/plain":               ".txt",
+}
+
+// Extension returns the file name extension of the content type, e.g.
+// ".png", or "" when it has none
+func Extension(contentType string) string {
+	return extensions[contentType]
+}
+
+// ValidateKey returns a fault.BadRequest error unless key is a relative,
+// slash separated path whose segments are made of letters, digits, ".",
+// "-" and "_", and are not "." or "..". Stores rely on it to keep keys
+// inside their directory or bucket.
+func ValidateKey(key string) error {
+	invalid := fault.New("invalid file key").Code(fault.BadRequest).With("key", key)
+	if key == "" || len(key) > 1024 {
+		return invalid
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return invalid
+		}
+		for _, r := range segment {
+			if !isKeyRune(r) {
+				return invalid
+			}
+		}
+	}
+	return nil
+}
+
+func isKeyRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_'
+}
+
+// Limits bound uploaded files.
+type Limits struct {
+	// MaxSize is the largest accepted size in bytes
+	MaxSize int64
+	// ContentTypes are the accepted content types, every type when empty
+	ContentTypes []string
+}
+
+// File is an uploaded file read into memory.
+type File struct {
+	Data []byte
+	// ContentType is sniffed from the content, see http.DetectContentType
+	ContentType string
+}
+
+// Blob describes the file stored under key.
+func (f File) Blob(key string) ports.Blob {
+	return ports.Blob{Key: key, ContentType: f.ContentType, Size: int64(len(f.Data))}
+}
+
+// Reader returns a reader of the content of the file.
+func (f File) Reader() io.Reader {
+	return bytes.NewReader(f.Data)
+}
+
+// Read reads an uploaded file within limits. The content type claimed by
+// the client is ignored: it is sniffed from the first bytes. Files over
+// MaxSize are fault.PayloadTooLarge errors, files of other content types
+// fault.UnsupportedMediaType errors.
+func Read(r io.Reader, limits Limits) (File, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limits.MaxSize+1))
+	if err != nil {
+		return File{}, fault.Wrap(err).Code(fault.BadRequest).Message("failed to read file")
+	}
+	if int64(len(data)) > limits.MaxSize {
+		return File{}, fault.New("file is too large").Code(fault.PayloadTooLarge).With("max_size", limits.MaxSize)
+	}
+	if len(data) == 0 {
+		return File{}, fault.New("file is empty").Code(fault.BadRequest)
+	}
+
+	contentType := http.DetectContentType(data)
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	if len(limits.ContentTypes) > 0 && !slices.Contains(limits.ContentTypes, contentType) {
+		return File{}, fault.New("file type is not allowed").
+			Code(fault.UnsupportedMediaType).
+			With("content_type", contentType).
+			With("allowed", limits.ContentTypes)
+	}
+
+	return File{Data: data, ContentType: contentType}, nil
+}
@@ -0,0 +1,106 @@
+package blob
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	// Registers the GIF decoder of image.Decode
+	_ "image/gif"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+// maxPixels bounds the decoded size of images, so small files declaring
+// huge dimensions cannot exhaust memory
+const maxPixels = 40_000_000
+
+// Thumbnail decodes a JPEG, PNG or GIF image, crops it to a centered square
+// and scales it down to size x size pixels. Smaller images are cropped but
+// not scaled up. JPEG images are encoded as JPEG, the rest as PNG to keep
+// their transparency. Metadata such as EXIF is dropped.
+func Thumbnail(data []byte, size int) (File, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return File{}, fault.Wrap(err).Code(fault.UnprocessableEntity).Message("invalid image")
+	}
+	if config.Width*config.Height > maxPixels {
+		return File{}, fault.New("image is too large").Code(fault.PayloadTooLarge).
+			With("width", config.Width).
+			With("height", config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return File{}, fault.Wrap(err).Code(fault.UnprocessableEntity).Message("invalid image")
+	}
+
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	if side == 0 {
+		return File{}, fault.New("invalid image").Code(fault.UnprocessableEntity)
+	}
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	))
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), src, crop.Min, draw.Src)
+
+	thumbnail := square
+	if side > size {
+		thumbnail = scaleDown(square, size)
+	}
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, thumbnail)
+	}
+	if err != nil {
+		return File{}, fault.Wrap(err).Code(fault.InternalError).Message("failed to encode image")
+	}
+
+	contentType := "image/png"
+	if format == "jpeg" {
+		contentType = "image/jpeg"
+	}
+	return File{Data: buf.Bytes(), ContentType: contentType}, nil
+}
+
+// scaleDown resizes a square image to size x size pixels, averaging the
+// source pixels each target pixel covers (a box filter)
+func scaleDown(src *image.RGBA, size int) *image.RGBA {
+	side := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		y0, y1 := y*side/size, (y+1)*side/size
+		for x := 0; x < size; x++ {
+			x0, x1 := x*side/size, (x+1)*side/size
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					pixel := row[sx*4 : sx*4+4]
+					r += uint64(pixel[0])
+					g += uint64(pixel[1])
+					b += uint64(pixel[2])
+					a += uint64(pixel[3])
+					n++
+				}
+			}
+
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,205 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"github.com/labstack/echo/v4"
+)
+
+// LocalRoute is the path, under the public API group, LocalStore.Handler
+// is mounted at
+const LocalRoute = "/blobs"
+
+// LocalStore keeps files in a directory, e.g. during development or on a
+// single server. Its signed URLs point to its own download route, see
+// Handler, and are signed with HMAC-SHA256: instances serving the same
+// directory must share the signing key.
+type LocalStore struct {
+	dir     string
+	baseURL string
+	key     []byte
+	now     func() time.Time
+}
+
+// NewLocalStore returns a store of the files in dir, creating it if needed.
+// baseURL is the public URL Handler is served at, e.g.
+// http://localhost:8080/api/v1/blobs.
+func NewLocalStore(dir, baseURL string, signingKey []byte) (*LocalStore, error) {
+	if len(signingKey) == 0 {
+		return nil, fault.New("local storage needs a signing key").Code(fault.InternalError)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fault.Wrap(err).Code(fault.InternalError).Message("failed to create storage directory").With("dir", dir)
+	}
+
+	return &LocalStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		key:     signingKey,
+		now:     time.Now,
+	}, nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes the file to a temporary file first, so readers never see
+// partial content
+func (s *LocalStore) Put(ctx context.Context, blob ports.Blob, body io.Reader) error {
+	name, err := s.path(blob.Key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return fault.Wrap(err).Code(fault.InternalError).Message("failed to create file directory")
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return fault.Wrap(err).Code(fault.InternalError).Message("failed to create file")
+	}
+	defer os.Remove(file.Name())
+
+	written, err := io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fault.Wrap(err).Code(fault.InternalError).Message("failed to write file").With("key", blob.Key)
+	}
+	if written != blob.Size {
+		return fault.New("file size does not match").Code(fault.InternalError).With("key", blob.Key).With("size", written)
+	}
+
+	if err := os.Rename(file.Name(), name); err != nil {
+		return fault.Wrap(err).Code(fault.InternalError).Message("failed to write file").With("key", blob.Key)
+	}
+	return nil
+}
+
+// Get returns the content type guessed from the extension of the key
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, ports.Blob, error) {
+	file, err := s.open(key)
+	if err != nil {
+		return nil, ports.Blob{}, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, ports.Blob{}, fault.Wrap(err).Code(fault.InternalError).Message("failed to read file")
+	}
+
+	return file, ports.Blob{Key: key, ContentType: mime.TypeByExtension(path.Ext(key)), Size: info.Size()}, nil
+}
+
+func (s *LocalStore) open(key string) (*os.File, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fault.Wrap(err).Code(fault.NotFound).Message("file not found").With("key", key)
+	}
+	if err != nil {
+		return nil, fault.Wrap(err).Code(fault.InternalError).Message("failed to read file").With("key", key)
+	}
+	return file, nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fault.Wrap(err).Code(fault.InternalError).Message("failed to delete file").With("key", key)
+	}
+	return nil
+}
+
+// SignedURL returns a URL of Handler carrying the expiry time and its
+// signature
+func (s *LocalStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(s.now().Add(expiry).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.sign(key, expires)}}
+	return s.baseURL + "/" + key + "?" + query.Encode(), nil
+}
+
+func (s *LocalStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify returns a fault.Forbidden error unless the signature was issued
+// by SignedURL for key and has not expired
+func (s *LocalStore) verify(key, expires, signature string) error {
+	forbidden := fault.New("invalid or expired file URL").Code(fault.Forbidden)
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !s.now().Before(time.Unix(unix, 0)) {
+		return forbidden
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return forbidden
+	}
+	return nil
+}
+
+// Handler serves the files of the URLs signed by SignedURL. Mount it at
+// LocalRoute + "/*" of the public API group.
+func (s *LocalStore) Handler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Param("*")
+		if err := s.verify(key, c.QueryParam("expires"), c.QueryParam("signature")); err != nil {
+			return err
+		}
+
+		file, err := s.open(key)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			return fault.Wrap(err).Code(fault.InternalError).Message("failed to read file")
+		}
+
+		c.Response().Header().Set("Cache-Control", "private, no-cache")
+		// Files are served from the API origin, so their scripts must not run
+		c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+		c.Response().Header().Set("Content-Security-Policy", "sandbox")
+		http.ServeContent(c.Response(), c.Request(), path.Base(key), info.ModTime(), file)
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package blob
+
+import (
+	"context"
+	"crypto/rand"
+
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/localconfig"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Module("blob",
+	fx.Provide(NewStoreFromConfig),
+)
+
+// NewStoreFromConfig returns the store of config.Storage.Driver. The local
+// store serves its files at LocalRoute of the public API.
+func NewStoreFromConfig(config *localconfig.Config, lc fx.Lifecycle) (ports.BlobStore, error) {
+	switch config.Storage.Driver {
+	case "local":
+		signingKey := []byte(config.Storage.SigningKey)
+		if len(signingKey) == 0 {
+			signingKey = make([]byte, 32)
+			if _, err := rand.Read(signingKey); err != nil {
+				return nil, fault.Wrap(err).Message("failed to generate storage signing key")
+			}
+		}
+		return NewLocalStore(config.Storage.Dir, config.HTTP.PublicURL+"/api/v1"+LocalRoute, signingKey)
+	case "s3":
+		store, err := NewS3Store(S3Config{
+			Endpoint:        config.Storage.S3.Endpoint,
+			Region:          config.Storage.S3.Region,
+			Bucket:          config.Storage.S3.Bucket,
+			AccessKeyID:     config.Storage.S3.AccessKeyID,
+			SecretAccessKey: config.Storage.S3.SecretAccessKey,
+			PathStyle:       config.Storage.S3.UsePathStyle,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if config.Storage.S3.CreateBucket {
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					return store.EnsureBucket(ctx)
+				},
+			})
+		}
+		return store, nil
+	default:
+		return nil, fault.New("unknown STORAGE_DRIVER, expected local or s3").With("driver", config.Storage.Driver)
+	}
+}
@@ -1,5 +1,7 @@
 package dafi
 
+import "slices"
+
 type (
 	FilterField string
 	FilterValue any
@@ -129,3 +131,39 @@ func (f Filters) OrGroup(filters ...Filter) Filters {
 
 	return f
 }
+
+// Grouped returns a copy of the filters to which a condition can be added
+// with AND. Filters chained with OR are wrapped in parentheses, so the
+// condition applies to all of them.
+func (f Filters) Grouped() Filters {
+	filters := slices.Clone(f)
+	if len(filters) == 0 {
+		return filters
+	}
+
+	last := len(filters) - 1
+	filters[last].ChainingKey = And
+
+	hasOr := slices.ContainsFunc(filters[:last], func(f Filter) bool {
+		return f.ChainingKey == Or
+	})
+	if !hasOr {
+		return filters
+	}
+
+	first := &filters[0]
+	if first.IsGroupOpen {
+		first.GroupOpenQty = max(1, first.GroupOpenQty) + 1
+	} else {
+		first.IsGroupOpen, first.GroupOpenQty = true, 1
+	}
+
+	end := &filters[last]
+	if end.IsGroupClose {
+		end.GroupCloseQty = max(1, end.GroupCloseQty) + 1
+	} else {
+		end.IsGroupClose, end.GroupCloseQty = true, 1
+	}
+
+	return filters
+}
@@ -0,0 +1,19 @@
+// Package database declares the interface of everything that runs SQL. It
+// sits below ports so packages that ports depends on, like valid, can use
+// the same executor as the repositories.
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Executor runs SQL on the pool or on a transaction. ports.DatabaseExecutor
+// is an alias of it.
+type Executor interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
@@ -1,6 +1,10 @@
 package fault
 
-import "net/http"
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
 
 type Code string
 
@@ -12,14 +16,72 @@ const (
 	Unauthorized        Code = "unauthorized"
 	Forbidden           Code = "forbidden"
 	NotFound            Code = "not_found"
+	Conflict            Code = "conflict"
+	TooManyRequests     Code = "too_many_requests"
+	PreconditionFailed  Code = "precondition_failed"
+	ServiceUnavailable  Code = "service_unavailable"
+	Timeout             Code = "timeout"
+	PaymentRequired     Code = "payment_required"
+	Canceled            Code = "canceled"
+	// PayloadTooLarge and UnsupportedMediaType reject uploaded files
+	PayloadTooLarge      Code = "payload_too_large"
+	UnsupportedMediaType Code = "unsupported_media_type"
 )
 
+// StatusClientClosedRequest is the non-standard status used when the client
+// goes away before the response is written.
+const StatusClientClosedRequest = 499
+
 var HTTPStatusByCode = map[Code]int{
-	BadRequest:          http.StatusBadRequest,
-	UnprocessableEntity: http.StatusUnprocessableEntity,
-	InternalError:       http.StatusInternalServerError,
-	BindFailed:          http.StatusBadRequest,
-	Unauthorized:        http.StatusUnauthorized,
-	Forbidden:           http.StatusForbidden,
-	NotFound:            http.StatusNotFound,
+	BadRequest:           http.StatusBadRequest,
+	UnprocessableEntity:  http.StatusUnprocessableEntity,
+	InternalError:        http.StatusInternalServerError,
+	BindFailed:           http.StatusBadRequest,
+	Unauthorized:         http.StatusUnauthorized,
+	Forbidden:            http.StatusForbidden,
+	NotFound:             http.StatusNotFound,
+	Conflict:             http.StatusConflict,
+	TooManyRequests:      http.StatusTooManyRequests,
+	PreconditionFailed:   http.StatusPreconditionFailed,
+	ServiceUnavailable:   http.StatusServiceUnavailable,
+	Timeout:              http.StatusGatewayTimeout,
+	PaymentRequired:      http.StatusPaymentRequired,
+	Canceled:             StatusClientClosedRequest,
+	PayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	UnsupportedMediaType: http.StatusUnsupportedMediaType,
+}
+
+var GRPCCodeByCode = map[Code]codes.Code{
+	BadRequest:           codes.InvalidArgument,
+	UnprocessableEntity:  codes.InvalidArgument,
+	InternalError:        codes.Internal,
+	BindFailed:           codes.InvalidArgument,
+	Unauthorized:         codes.Unauthenticated,
+	Forbidden:            codes.PermissionDenied,
+	NotFound:             codes.NotFound,
+	Conflict:             codes.AlreadyExists,
+	TooManyRequests:      codes.ResourceExhausted,
+	PreconditionFailed:   codes.FailedPrecondition,
+	ServiceUnavailable:   codes.Unavailable,
+	Timeout:              codes.DeadlineExceeded,
+	PaymentRequired:      codes.FailedPrecondition,
+	Canceled:             codes.Canceled,
+	PayloadTooLarge:      codes.ResourceExhausted,
+	UnsupportedMediaType: codes.InvalidArgument,
+}
+
+// HTTPStatus returns the HTTP status for the code, 500 when unknown
+func (c Code) HTTPStatus() int {
+	if status, ok := HTTPStatusByCode[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCode returns the gRPC status code for the code, Internal when unknown
+func (c Code) GRPCCode() codes.Code {
+	if code, ok := GRPCCodeByCode[c]; ok {
+		return code
+	}
+	return codes.Internal
 }
@@ -1,7 +1,10 @@
 package fault_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+
 	"api.system.soluciones-cloud.com/internal/shared/fault"
 )
 
@@ -48,6 +51,53 @@ func ExampleError_chaining() {
 	}
 }
 
+func ExampleCode_HTTPStatus() {
+	for _, code := range []fault.Code{fault.ServiceUnavailable, fault.TooManyRequests, fault.Timeout, fault.Canceled} {
+		fmt.Println(code, code.HTTPStatus(), code.GRPCCode())
+	}
+	// Output:
+	// service_unavailable 503 Unavailable
+	// too_many_requests 429 ResourceExhausted
+	// timeout 504 DeadlineExceeded
+	// canceled 499 Canceled
+}
+
+func ExampleError_unwrap() {
+	originalErr := fmt.Errorf("network unreachable")
+	err := fault.Wrap(originalErr).
+		Code(fault.ServiceUnavailable).
+		Message("User service unavailable")
+
+	// Error implements Unwrap, so errors.Is and errors.As see the whole chain
+	fmt.Println(errors.Is(err, originalErr), err.HTTPStatus())
+	// Output: true 503
+}
+
+func ExampleError_With() {
+	err := fault.Wrap(fmt.Errorf("duplicate key value")).
+		Code(fault.Conflict).
+		Message("email already registered").
+		With("email", "ana@example.com")
+
+	email, _ := fault.Value[string](err, "email")
+	fmt.Println(email)
+	fmt.Println(err.HTTPStatus(), err.GRPCCode())
+	// Output:
+	// ana@example.com
+	// 409 AlreadyExists
+}
+
+func ExampleCodeOf() {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+
+	err := fault.Wrap(ctx.Err()).Message("failed to list users")
+
+	fmt.Println(fault.CodeOf(err), fault.HTTPStatusOf(err), fault.IsTimeout(err))
+	// Output: timeout 504 true
+}
+
 func ExampleError_simple() {
 	// Simple usage without all options
 	err := fault.Wrap(fmt.Errorf("invalid input")).
@@ -3,18 +3,20 @@ package fault
 import (
 	"errors"
 	"fmt"
-	"net/http"
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"google.golang.org/grpc/codes"
 )
 
 type Error struct {
-	TitleText   string  `json:"title,omitempty"`
-	MessageText string  `json:"message"`
-	CodeName    string  `json:"code"`
-	Cause       error   `json:"cause,omitempty"`
-	Stack       []Frame `json:"stack,omitempty"`
+	TitleText   string         `json:"title,omitempty"`
+	MessageText string         `json:"message"`
+	CodeName    string         `json:"code"`
+	Cause       error          `json:"cause,omitempty"`
+	Stack       []Frame        `json:"stack,omitempty"`
+	Meta        map[string]any `json:"metadata,omitempty"`
 }
 
 type Frame struct {
@@ -23,19 +25,25 @@ type Frame struct {
 	Function string `json:"function"`
 }
 
+// New creates an error without a cause, e.g. for business rule violations.
+func New(message string) *Error {
+	return &Error{
+		MessageText: message,
+		Stack:       []Frame{captureStack()},
+	}
+}
+
 // Wrap starts a new error builder chain if the cause error is not nil.
 // Returns nil if causeError is nil.
+//
+// Wrapping a *Error creates a new link in the chain, so the message, code
+// and metadata set by inner layers are kept. Code, title and message
+// resolve to the outermost value set.
 func Wrap(causeError error) *Error {
 	if causeError == nil {
 		return nil
 	}
 
-	var errTrace *Error
-	if errors.As(causeError, &errTrace) {
-		errTrace.Stack = append(errTrace.Stack, captureStack())
-		return errTrace
-	}
-
 	return &Error{
 		Cause: causeError,
 		Stack: []Frame{captureStack()},
@@ -60,6 +68,15 @@ func (e *Error) Title(title string) *Error {
 	return e
 }
 
+// With attaches metadata, e.g. With("user_id", id). Read it back with Value.
+func (e *Error) With(key string, value any) *Error {
+	if e.Meta == nil {
+		e.Meta = make(map[string]any)
+	}
+	e.Meta[key] = value
+	return e
+}
+
 // HasTitle returns true if the error has a title
 func (e *Error) HasTitle() bool {
 	return e.TitleText != ""
@@ -75,42 +92,93 @@ func (e *Error) HasMessage() bool {
 	return e.MessageText != ""
 }
 
-// HTTPStatus returns the HTTP status code for the error code
+// Unwrap returns the cause so errors.Is and errors.As see the whole chain
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// HTTPStatus returns the HTTP status for the error code of the chain
 func (e *Error) HTTPStatus() int {
-	if e.CodeName == "" {
-		return http.StatusInternalServerError
+	return CodeOf(e).HTTPStatus()
+}
+
+// GRPCCode returns the gRPC status code for the error code of the chain
+func (e *Error) GRPCCode() codes.Code {
+	return CodeOf(e).GRPCCode()
+}
+
+// Messages returns the message chain from the outermost error to the root
+// cause, skipping links without a message.
+func (e *Error) Messages() []string {
+	var messages []string
+
+	var err error = e
+	for err != nil {
+		faultErr, ok := err.(*Error)
+		if !ok {
+			messages = append(messages, err.Error())
+			break
+		}
+		if faultErr.MessageText != "" {
+			messages = append(messages, faultErr.MessageText)
+		}
+		err = faultErr.Cause
+	}
+
+	return messages
+}
+
+// StackTrace returns the frames of the whole chain, innermost first
+func (e *Error) StackTrace() []Frame {
+	var chain []*Error
+	for err := error(e); err != nil; err = errors.Unwrap(err) {
+		if faultErr, ok := err.(*Error); ok {
+			chain = append(chain, faultErr)
+		}
 	}
 
-	if status, ok := HTTPStatusByCode[Code(e.CodeName)]; ok {
-		return status
+	var frames []Frame
+	for i := len(chain) - 1; i >= 0; i-- {
+		frames = append(frames, chain[i].Stack...)
 	}
+	return frames
+}
 
-	return http.StatusInternalServerError
+// Metadata returns the metadata of the whole chain. Outer values win.
+func (e *Error) Metadata() map[string]any {
+	metadata := make(map[string]any)
+	for err := error(e); err != nil; err = errors.Unwrap(err) {
+		faultErr, ok := err.(*Error)
+		if !ok {
+			continue
+		}
+		for key, value := range faultErr.Meta {
+			if _, exists := metadata[key]; !exists {
+				metadata[key] = value
+			}
+		}
+	}
+	return metadata
 }
 
 // Error implements the error interface with a logging-friendly format
 func (e *Error) Error() string {
 	var parts []string
 
-	if len(e.Stack) > 0 {
+	if stack := e.StackTrace(); len(stack) > 0 {
 		var stackPaths []string
-		// Stack is already in correct order, just format it
-		for _, frame := range e.Stack {
+		for _, frame := range stack {
 			location := fmt.Sprintf("%s:%d", frame.File, frame.Line)
 			stackPaths = append(stackPaths, location)
 		}
 		parts = append(parts, fmt.Sprintf("[stack=%s]", strings.Join(stackPaths, ` > `)))
 	}
 
-	if e.CodeName != "" {
-		parts = append(parts, fmt.Sprintf("[code=%s]", strings.ToLower(e.CodeName)))
+	if code := codeOf(e); code != "" {
+		parts = append(parts, fmt.Sprintf("[code=%s]", strings.ToLower(string(code))))
 	}
 
-	msg := e.MessageText
-	if e.Cause != nil {
-		msg = fmt.Sprintf("%s: %v", e.MessageText, e.Cause)
-	}
-	parts = append(parts, fmt.Sprintf("[error=%s]", msg))
+	parts = append(parts, fmt.Sprintf("[error=%s]", strings.Join(e.Messages(), ": ")))
 
 	return strings.Join(parts, " ")
 }
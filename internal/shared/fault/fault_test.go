@@ -0,0 +1,196 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+var errRoot = errors.New("no rows in result set")
+
+func TestWrap_Nil(t *testing.T) {
+	if Wrap(nil) != nil {
+		t.Error("Expected nil when wrapping nil")
+	}
+}
+
+func TestWrap_KeepsChain(t *testing.T) {
+	inner := Wrap(errRoot).Code(NotFound).Message("failed to find user")
+	outer := Wrap(inner).Message("failed to get user by ID")
+
+	if outer == inner {
+		t.Fatal("Expected Wrap to return a new error")
+	}
+	if inner.MessageText != "failed to find user" {
+		t.Errorf("Expected inner message to be kept, got %q", inner.MessageText)
+	}
+
+	wantMessages := []string{"failed to get user by ID", "failed to find user", errRoot.Error()}
+	if got := outer.Messages(); !reflect.DeepEqual(got, wantMessages) {
+		t.Errorf("Expected messages %v, got %v", wantMessages, got)
+	}
+
+	if !strings.Contains(outer.Error(), "[code=not_found] [error=failed to get user by ID: failed to find user: no rows in result set]") {
+		t.Errorf("Unexpected error string: %s", outer.Error())
+	}
+
+	if len(outer.StackTrace()) != 2 {
+		t.Errorf("Expected 2 frames, got %v", outer.StackTrace())
+	}
+}
+
+func TestErrorsIsAndAs(t *testing.T) {
+	err := fmt.Errorf("handler: %w", Wrap(Wrap(errRoot).Message("repository")).Message("usecase"))
+
+	if !errors.Is(err, errRoot) {
+		t.Error("Expected errors.Is to find the root cause")
+	}
+	if !Is(err, errRoot) {
+		t.Error("Expected fault.Is to find the root cause")
+	}
+
+	var faultErr *Error
+	if !errors.As(err, &faultErr) || faultErr.MessageText != "usecase" {
+		t.Errorf("Expected errors.As to find the outermost fault error, got %+v", faultErr)
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		code       Code
+		httpStatus int
+		grpcCode   codes.Code
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			code: "",
+		},
+		{
+			name:       "plain error",
+			err:        errRoot,
+			code:       InternalError,
+			httpStatus: http.StatusInternalServerError,
+			grpcCode:   codes.Internal,
+		},
+		{
+			name:       "inner code is inherited",
+			err:        Wrap(Wrap(errRoot).Code(Conflict)).Message("failed to create user"),
+			code:       Conflict,
+			httpStatus: http.StatusConflict,
+			grpcCode:   codes.AlreadyExists,
+		},
+		{
+			name:       "outer code wins",
+			err:        Wrap(Wrap(errRoot).Code(NotFound)).Code(PreconditionFailed),
+			code:       PreconditionFailed,
+			httpStatus: http.StatusPreconditionFailed,
+			grpcCode:   codes.FailedPrecondition,
+		},
+		{
+			name:       "deadline exceeded",
+			err:        Wrap(fmt.Errorf("query: %w", context.DeadlineExceeded)),
+			code:       Timeout,
+			httpStatus: http.StatusGatewayTimeout,
+			grpcCode:   codes.DeadlineExceeded,
+		},
+		{
+			name:       "canceled",
+			err:        Wrap(context.Canceled).Message("failed to list users"),
+			code:       Canceled,
+			httpStatus: StatusClientClosedRequest,
+			grpcCode:   codes.Canceled,
+		},
+		{
+			name:       "too many requests",
+			err:        New("rate limit exceeded").Code(TooManyRequests),
+			code:       TooManyRequests,
+			httpStatus: http.StatusTooManyRequests,
+			grpcCode:   codes.ResourceExhausted,
+		},
+		{
+			name:       "payload too large",
+			err:        New("file is too large").Code(PayloadTooLarge),
+			code:       PayloadTooLarge,
+			httpStatus: http.StatusRequestEntityTooLarge,
+			grpcCode:   codes.ResourceExhausted,
+		},
+		{
+			name:       "unsupported media type",
+			err:        New("file type is not allowed").Code(UnsupportedMediaType),
+			code:       UnsupportedMediaType,
+			httpStatus: http.StatusUnsupportedMediaType,
+			grpcCode:   codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.code {
+				t.Errorf("Expected code %q, got %q", tt.code, got)
+			}
+
+			var faultErr *Error
+			if !errors.As(tt.err, &faultErr) {
+				return
+			}
+			if got := faultErr.HTTPStatus(); got != tt.httpStatus {
+				t.Errorf("Expected HTTP status %d, got %d", tt.httpStatus, got)
+			}
+			if got := faultErr.GRPCCode(); got != tt.grpcCode {
+				t.Errorf("Expected gRPC code %v, got %v", tt.grpcCode, got)
+			}
+		})
+	}
+}
+
+func TestIsTimeoutAndCanceled(t *testing.T) {
+	if !IsTimeout(Wrap(context.DeadlineExceeded)) || IsTimeout(errRoot) {
+		t.Error("IsTimeout misclassified")
+	}
+	if !IsCanceled(Wrap(context.Canceled)) || IsCanceled(Wrap(context.DeadlineExceeded)) {
+		t.Error("IsCanceled misclassified")
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	inner := Wrap(errRoot).With("user_id", "a3f1").With("attempt", 1)
+	outer := Wrap(inner).With("attempt", 2)
+
+	if id, ok := Value[string](outer, "user_id"); !ok || id != "a3f1" {
+		t.Errorf("Expected user_id from inner error, got %q, %t", id, ok)
+	}
+	if attempt, ok := Value[int](outer, "attempt"); !ok || attempt != 2 {
+		t.Errorf("Expected outer attempt 2, got %d", attempt)
+	}
+	if _, ok := Value[int](outer, "user_id"); ok {
+		t.Error("Expected type mismatch to report false")
+	}
+
+	want := map[string]any{"user_id": "a3f1", "attempt": 2}
+	if got := outer.Metadata(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected metadata %v, got %v", want, got)
+	}
+}
+
+func TestTitleAndMessageOf(t *testing.T) {
+	err := Wrap(Wrap(errRoot).Title("Database Error").Message("failed to find user")).Message("failed to get user")
+
+	if got := TitleOf(err); got != "Database Error" {
+		t.Errorf("Expected inner title, got %q", got)
+	}
+	if got := MessageOf(err); got != "failed to get user" {
+		t.Errorf("Expected outer message, got %q", got)
+	}
+	if MessageOf(errRoot) != "" {
+		t.Error("Expected no message for a plain error")
+	}
+}
@@ -1,14 +1,97 @@
 package fault
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 // Is reports whether any error in err's chain matches target.
-// For fault.Error types, it checks the underlying Cause.
+// It is equivalent to errors.Is, which also works now that Error implements
+// Unwrap.
 func Is(err error, target error) bool {
-	var faultErr *Error
-	if errors.As(err, &faultErr) {
-		return errors.Is(faultErr.Cause, target)
+	return errors.Is(err, target)
+}
+
+// CodeOf classifies err. It returns the outermost code set in the chain;
+// otherwise Timeout for context.DeadlineExceeded, Canceled for
+// context.Canceled and InternalError for anything else. It returns an
+// empty code for a nil error.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	if code := codeOf(err); code != "" {
+		return code
 	}
+	return InternalError
+}
 
-	return errors.Is(err, target)
+func codeOf(err error) Code {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if faultErr, ok := e.(*Error); ok && faultErr.CodeName != "" {
+			return Code(faultErr.CodeName)
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	}
+	return ""
+}
+
+// HTTPStatusOf returns the HTTP status for err, see CodeOf.
+func HTTPStatusOf(err error) int {
+	return CodeOf(err).HTTPStatus()
+}
+
+// TitleOf returns the outermost title set in err's chain.
+func TitleOf(err error) string {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if faultErr, ok := e.(*Error); ok && faultErr.TitleText != "" {
+			return faultErr.TitleText
+		}
+	}
+	return ""
+}
+
+// MessageOf returns the outermost message set in err's chain.
+func MessageOf(err error) string {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if faultErr, ok := e.(*Error); ok && faultErr.MessageText != "" {
+			return faultErr.MessageText
+		}
+	}
+	return ""
+}
+
+// Value returns the metadata stored under key by With anywhere in err's
+// chain, if it has type T.
+func Value[T any](err error, key string) (T, bool) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		faultErr, ok := e.(*Error)
+		if !ok {
+			continue
+		}
+		if value, ok := faultErr.Meta[key].(T); ok {
+			return value, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// IsTimeout reports whether err was caused by an expired deadline, either
+// context.DeadlineExceeded or an error coded Timeout.
+func IsTimeout(err error) bool {
+	return CodeOf(err) == Timeout
+}
+
+// IsCanceled reports whether err was caused by a canceled context, either
+// context.Canceled or an error coded Canceled.
+func IsCanceled(err error) bool {
+	return CodeOf(err) == Canceled
 }
@@ -0,0 +1,49 @@
+package fault
+
+import "api.system.soluciones-cloud.com/internal/shared/i18n"
+
+func init() {
+	i18n.Register(i18n.English, map[string]string{
+		"fault.bad_request":          "The request is invalid",
+		"fault.unprocessable_entity": "The provided data is not valid",
+		"fault.internal_error":       "An unexpected error occurred",
+		"fault.bind_failed":          "The request body could not be read",
+		"fault.unauthorized":         "Authentication is required",
+		"fault.forbidden":            "You do not have permission to perform this action",
+		"fault.not_found":            "The requested resource was not found",
+		"fault.conflict":             "The request conflicts with the current state of the resource",
+		"fault.too_many_requests":    "Too many requests, please try again later",
+		"fault.precondition_failed":  "A precondition of the request was not met",
+		"fault.service_unavailable":  "The service is temporarily unavailable",
+		"fault.timeout":              "The operation took too long to complete",
+		"fault.payment_required":     "Payment is required to complete this action",
+		"fault.canceled":             "The request was canceled",
+	})
+
+	i18n.Register(i18n.Spanish, map[string]string{
+		"fault.bad_request":          "La solicitud no es válida",
+		"fault.unprocessable_entity": "Los datos proporcionados no son válidos",
+		"fault.internal_error":       "Ocurrió un error inesperado",
+		"fault.bind_failed":          "No se pudo leer el cuerpo de la solicitud",
+		"fault.unauthorized":         "Se requiere autenticación",
+		"fault.forbidden":            "No tienes permisos para realizar esta acción",
+		"fault.not_found":            "El recurso solicitado no fue encontrado",
+		"fault.conflict":             "La solicitud entra en conflicto con el estado actual del recurso",
+		"fault.too_many_requests":    "Demasiadas solicitudes, intenta más tarde",
+		"fault.precondition_failed":  "No se cumplió una condición previa de la solicitud",
+		"fault.service_unavailable":  "El servicio no está disponible temporalmente",
+		"fault.timeout":              "La operación tardó demasiado en completarse",
+		"fault.payment_required":     "Se requiere un pago para completar esta acción",
+		"fault.canceled":             "La solicitud fue cancelada",
+	})
+}
+
+// Message returns the user-facing default message for the code in locale,
+// or an empty string when the catalog has none.
+func (c Code) Message(locale i18n.Locale) string {
+	template, ok := i18n.Default.Lookup(locale, "fault."+string(c))
+	if !ok {
+		return ""
+	}
+	return template
+}
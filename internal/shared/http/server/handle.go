@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+)
+
+// NoContent is the result of handlers that answer 204 No Content.
+type NoContent struct{}
+
+// Redirect is the result of handlers that answer 302 Found, e.g. with a
+// signed file URL. Browsers cache the redirect for MaxAge when it is set.
+type Redirect struct {
+	URL    string
+	MaxAge time.Duration
+}
+
+// Attachment is the result of handlers that answer a file to download,
+// e.g. an export. It is sent as is with Content-Disposition: attachment.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Validator is implemented by requests that validate themselves, e.g.
+// entity.CreateUserRequest.
+type Validator interface {
+	Validate() error
+}
+
+// ContextValidator is implemented by requests whose validation needs the
+// request context, e.g. database Exists/Unique rules.
+type ContextValidator interface {
+	ValidateCtx(ctx context.Context) error
+}
+
+// RequestBinder is implemented by requests that need more than struct tag
+// binding, e.g. building dafi criteria from the query string. It runs after
+// the default binding.
+type RequestBinder interface {
+	BindRequest(c echo.Context) error
+}
+
+type handleConfig struct {
+	status int
+}
+
+type HandleOption func(*handleConfig)
+
+// WithStatus sets the success status, 200 by default. 201 renders
+// response.Created.
+func WithStatus(status int) HandleOption {
+	return func(config *handleConfig) {
+		config.status = status
+	}
+}
+
+// Handle adapts a use case style function to an echo handler:
+//
+//  1. binds the JSON body, then query (`query` tags) and path (`param` tags)
+//     parameters into Req, so path parameters always win;
+//  2. runs Validate and ValidateCtx when Req implements them;
+//  3. calls fn with the request context;
+//  4. renders the result as response.Response[Res], 204 for NoContent,
+//     302 for Redirect or the file of an Attachment.
+//
+// Every error is returned to echo, so middleware.ErrorHandler renders it as
+// Problem Details. Binding errors are coded fault.BindFailed and validation
+// errors fault.UnprocessableEntity. Requests are only validated here: use
+// cases trust the requests they receive.
+func Handle[Req, Res any](fn func(ctx context.Context, req Req) (Res, error), opts ...HandleOption) echo.HandlerFunc {
+	config := handleConfig{status: http.StatusOK}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req Req
+		if err := bind(c, &req); err != nil {
+			return err
+		}
+
+		if err := validate(ctx, &req); err != nil {
+			return err
+		}
+
+		res, err := fn(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := any(res).(NoContent); ok {
+			return c.NoContent(http.StatusNoContent)
+		}
+
+		if redirect, ok := any(res).(Redirect); ok {
+			if redirect.MaxAge > 0 {
+				c.Response().Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(redirect.MaxAge.Seconds())))
+			}
+			return c.Redirect(http.StatusFound, redirect.URL)
+		}
+
+		if attachment, ok := any(res).(Attachment); ok {
+			c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", attachment.Name))
+			return c.Blob(config.status, attachment.ContentType, attachment.Data)
+		}
+
+		if config.status == http.StatusCreated {
+			return c.JSON(http.StatusCreated, response.Created(ctx, res))
+		}
+		return c.JSON(config.status, response.Ok(res).Status(config.status))
+	}
+}
+
+func bind(c echo.Context, req any) error {
+	binder := &echo.DefaultBinder{}
+
+	if err := binder.BindBody(c, req); err != nil {
+		return fault.Wrap(err).Code(fault.BindFailed).Message("invalid request body")
+	}
+	if err := binder.BindQueryParams(c, req); err != nil {
+		return fault.Wrap(err).Code(fault.BindFailed).Message("invalid query parameters")
+	}
+	if err := binder.BindPathParams(c, req); err != nil {
+		return fault.Wrap(err).Code(fault.BindFailed).Message("invalid path parameters")
+	}
+
+	if custom, ok := req.(RequestBinder); ok {
+		if err := custom.BindRequest(c); err != nil {
+			return fault.Wrap(err).Code(fault.BindFailed).Message("invalid request")
+		}
+	}
+
+	return nil
+}
+
+func validate(ctx context.Context, req any) error {
+	if validator, ok := req.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return validationError(err)
+		}
+	}
+
+	if validator, ok := req.(ContextValidator); ok {
+		if err := validator.ValidateCtx(ctx); err != nil {
+			return validationError(err)
+		}
+	}
+
+	return nil
+}
+
+// validationError codes plain validation errors. Fault errors are kept as
+// they are, so a failed database lookup still renders as a server error.
+func validationError(err error) error {
+	var faultErr *fault.Error
+	if errors.As(err, &faultErr) {
+		return err
+	}
+	return fault.Wrap(err).Code(fault.UnprocessableEntity).Message("validation failed")
+}
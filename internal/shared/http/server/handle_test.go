@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
+
+type testRenameRequest struct {
+	ID     uuid.UUID `json:"id" param:"id"`
+	Name   string    `json:"name"`
+	DryRun bool      `json:"-" query:"dry_run"`
+	bound  bool
+}
+
+func (r *testRenameRequest) BindRequest(c echo.Context) error {
+	r.bound = true
+	return nil
+}
+
+func (r testRenameRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type testItem struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func serve(t *testing.T, handler echo.HandlerFunc, method, target, body string) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+
+	e := echo.New()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+
+	c := e.NewContext(req, rec)
+	c.SetPath("/items/:id")
+	c.SetParamNames("id")
+	c.SetParamValues(strings.Split(strings.TrimPrefix(strings.Split(target, "?")[0], "/items/"), "/")[0])
+
+	return rec, handler(c)
+}
+
+func TestHandle(t *testing.T) {
+	pathID := uuid.New()
+	var got testRenameRequest
+
+	handler := Handle(func(ctx context.Context, req testRenameRequest) (testItem, error) {
+		got = req
+		return testItem{ID: req.ID, Name: req.Name}, nil
+	})
+
+	body := `{"id": "` + uuid.NewString() + `", "name": "renamed"}`
+	rec, err := serve(t, handler, http.MethodPut, "/items/"+pathID.String()+"?dry_run=true", body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got.ID != pathID {
+		t.Errorf("Expected path parameter to win over the body, got %s", got.ID)
+	}
+	if !got.DryRun || !got.bound {
+		t.Errorf("Expected query binding and BindRequest to run, got %+v", got)
+	}
+
+	var response struct {
+		Status int      `json:"status"`
+		Data   testItem `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response body: %v", err)
+	}
+	if rec.Code != http.StatusOK || response.Status != http.StatusOK || response.Data.Name != "renamed" {
+		t.Errorf("Unexpected response %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandle_Status(t *testing.T) {
+	created := Handle(func(ctx context.Context, req testRenameRequest) (testItem, error) {
+		return testItem{Name: req.Name}, nil
+	}, WithStatus(http.StatusCreated))
+
+	rec, err := serve(t, created, http.MethodPost, "/items/"+uuid.NewString(), `{"name": "new"}`)
+	if err != nil || rec.Code != http.StatusCreated {
+		t.Errorf("Expected 201, got %d (%v)", rec.Code, err)
+	}
+
+	deleted := Handle(func(ctx context.Context, req testRenameRequest) (NoContent, error) {
+		return NoContent{}, nil
+	})
+
+	rec, err = serve(t, deleted, http.MethodDelete, "/items/"+uuid.NewString(), `{"name": "gone"}`)
+	if err != nil || rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Errorf("Expected empty 204, got %d %q (%v)", rec.Code, rec.Body.String(), err)
+	}
+
+	redirected := Handle(func(ctx context.Context, req testRenameRequest) (Redirect, error) {
+		return Redirect{URL: "https://files.example.com/a.png?signature=x", MaxAge: 5 * time.Minute}, nil
+	})
+
+	rec, err = serve(t, redirected, http.MethodGet, "/items/"+uuid.NewString(), `{"name": "avatar"}`)
+	if err != nil || rec.Code != http.StatusFound || rec.Header().Get("Location") != "https://files.example.com/a.png?signature=x" {
+		t.Errorf("Expected 302 to the URL, got %d %q (%v)", rec.Code, rec.Header().Get("Location"), err)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "private, max-age=300" {
+		t.Errorf("Expected the redirect to be cached, got %q", got)
+	}
+
+	exported := Handle(func(ctx context.Context, req testRenameRequest) (Attachment, error) {
+		return Attachment{Name: "items.csv", ContentType: "text/csv", Data: []byte("name\nexport\n")}, nil
+	})
+
+	rec, err = serve(t, exported, http.MethodGet, "/items/"+uuid.NewString(), `{"name": "export"}`)
+	if err != nil || rec.Code != http.StatusOK || rec.Body.String() != "name\nexport\n" {
+		t.Errorf("Expected the file, got %d %q (%v)", rec.Code, rec.Body.String(), err)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/csv" {
+		t.Errorf("Expected the content type of the file, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="items.csv"` {
+		t.Errorf("Expected the file to be downloaded, got %q", got)
+	}
+}
+
+func TestHandle_Errors(t *testing.T) {
+	useCaseErr := fault.New("item already exists").Code(fault.Conflict)
+
+	handler := Handle(func(ctx context.Context, req testRenameRequest) (testItem, error) {
+		return testItem{}, useCaseErr
+	})
+
+	tests := []struct {
+		name     string
+		target   string
+		body     string
+		wantCode fault.Code
+	}{
+		{
+			name:     "invalid path parameter",
+			target:   "/items/not-a-uuid",
+			body:     `{"name": "x"}`,
+			wantCode: fault.BindFailed,
+		},
+		{
+			name:     "malformed body",
+			target:   "/items/" + uuid.NewString(),
+			body:     `{"name": `,
+			wantCode: fault.BindFailed,
+		},
+		{
+			name:     "validation",
+			target:   "/items/" + uuid.NewString(),
+			body:     `{"name": ""}`,
+			wantCode: fault.UnprocessableEntity,
+		},
+		{
+			name:     "use case error is returned as is",
+			target:   "/items/" + uuid.NewString(),
+			body:     `{"name": "x"}`,
+			wantCode: fault.Conflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := serve(t, handler, http.MethodPut, tt.target, tt.body)
+			if got := fault.CodeOf(err); got != tt.wantCode {
+				t.Errorf("Expected code %s, got %s (%v)", tt.wantCode, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TokenVerifier turns a bearer token into the principal it was issued for,
+// e.g. token.Verifier.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (auth.Principal, error)
+}
+
+// APIKeyVerifier turns an API key into the principal of its service
+// account, e.g. the API key use case.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (auth.Principal, error)
+}
+
+// APIKeyScheme is the Authorization scheme of API keys
+const APIKeyScheme = "ApiKey"
+
+// Authenticate requires a valid bearer token or, when keys is not nil, an
+// API key sent as "Authorization: ApiKey <key>". Both produce the same
+// auth.Principal. The principal is stored in the request context
+// (auth.PrincipalFrom) and its user id under the "user_id" key read by
+// request.GetLoggedUserID.
+//
+// Failures are returned as fault.Unauthorized errors, so ErrorHandler
+// renders them as 401 Problem Details, with a WWW-Authenticate challenge
+// (RFC 6750).
+func Authenticate(verifier TokenVerifier, keys APIKeyVerifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			ctx := c.Request().Context()
+
+			var principal auth.Principal
+			var err error
+			if key, ok := credentials(header, APIKeyScheme); ok && keys != nil {
+				if principal, err = keys.VerifyAPIKey(ctx, key); err != nil {
+					c.Response().Header().Set(echo.HeaderWWWAuthenticate, APIKeyScheme+` realm="api", error="invalid_key"`)
+					return err
+				}
+			} else {
+				raw, ok := credentials(header, "Bearer")
+				if !ok {
+					c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="api"`)
+					return fault.New("missing bearer token").Code(fault.Unauthorized)
+				}
+
+				if principal, err = verifier.Verify(ctx, raw); err != nil {
+					c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="api", error="invalid_token"`)
+					return err
+				}
+			}
+
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("enduser.id", principal.UserID.String()))
+
+			c.SetRequest(c.Request().WithContext(auth.WithPrincipal(ctx, principal)))
+			c.Set("user_id", principal.UserID)
+
+			return next(c)
+		}
+	}
+}
+
+// credentials returns the credentials of the Authorization header when
+// it uses the scheme
+func credentials(header, scheme string) (string, bool) {
+	actual, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(actual, scheme) {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/request"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type fakeVerifier map[string]auth.Principal
+
+func (f fakeVerifier) VerifyAPIKey(ctx context.Context, key string) (auth.Principal, error) {
+	principal, ok := f[key]
+	if !ok {
+		return auth.Principal{}, fault.New("invalid API key").Code(fault.Unauthorized)
+	}
+	return principal, nil
+}
+
+func (f fakeVerifier) Verify(ctx context.Context, token string) (auth.Principal, error) {
+	principal, ok := f[token]
+	if !ok {
+		return auth.Principal{}, fault.New("invalid token").Code(fault.Unauthorized)
+	}
+	return principal, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	principal := auth.Principal{UserID: uuid.New(), Roles: []string{"admin"}}
+	verifier := fakeVerifier{"valid": principal}
+
+	tests := []struct {
+		name          string
+		authorization string
+		keys          APIKeyVerifier
+		wantErr       bool
+		wantChallenge string
+	}{
+		{name: "valid token", authorization: "Bearer valid"},
+		{name: "scheme is case insensitive", authorization: "bearer valid"},
+		{name: "missing header", wantErr: true, wantChallenge: `Bearer realm="api"`},
+		{name: "other scheme", authorization: "Basic dXNlcjpwYXNz", wantErr: true, wantChallenge: `Bearer realm="api"`},
+		{name: "empty token", authorization: "Bearer ", wantErr: true, wantChallenge: `Bearer realm="api"`},
+		{name: "invalid token", authorization: "Bearer forged", wantErr: true, wantChallenge: `Bearer realm="api", error="invalid_token"`},
+		{name: "valid api key", authorization: "ApiKey valid", keys: verifier},
+		{name: "api key scheme is case insensitive", authorization: "apikey valid", keys: verifier},
+		{name: "invalid api key", authorization: "ApiKey forged", keys: verifier, wantErr: true, wantChallenge: `ApiKey realm="api", error="invalid_key"`},
+		{name: "api keys not accepted", authorization: "ApiKey valid", wantErr: true, wantChallenge: `Bearer realm="api"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var got auth.Principal
+			var loggedUserID uuid.NullUUID
+			handler := Authenticate(verifier, tt.keys)(func(c echo.Context) error {
+				got, _ = auth.PrincipalFrom(c.Request().Context())
+				loggedUserID = request.GetLoggedUserID(c)
+				return nil
+			})
+
+			err := handler(c)
+
+			if tt.wantErr {
+				if fault.CodeOf(err) != fault.Unauthorized {
+					t.Fatalf("Expected unauthorized error, got %v", err)
+				}
+				if challenge := rec.Header().Get(echo.HeaderWWWAuthenticate); challenge != tt.wantChallenge {
+					t.Errorf("Expected challenge %q, got %q", tt.wantChallenge, challenge)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got.UserID != principal.UserID || !got.HasRole("admin") {
+				t.Errorf("Expected principal %+v in the context, got %+v", principal, got)
+			}
+			if !loggedUserID.Valid || loggedUserID.UUID != principal.UserID {
+				t.Errorf("Expected logged user %s, got %+v", principal.UserID, loggedUserID)
+			}
+		})
+	}
+}
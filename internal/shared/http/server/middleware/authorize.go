@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Authorizer checks that a principal was granted a permission, e.g.
+// rbac.Authorizer.
+type Authorizer interface {
+	Authorize(ctx context.Context, principal auth.Principal, code string) (rbac.Access, error)
+}
+
+// PermissionLookup returns the permission code a route requires, or ""
+// when it only requires authentication, e.g. openapi.Registry.Permission.
+// declared is false for routes declaring neither.
+type PermissionLookup func(method, path string) (code string, declared bool)
+
+// Authorize requires the permission declared for the matched route. It
+// must run after Authenticate. The granted access is stored in the request
+// context (rbac.AccessFrom), so use cases can restrict what the caller
+// sees to its visibility scope. A tenant (see Tenant) is limited to the
+// organizations the permission is granted in.
+//
+// Routes declared authenticated only require authentication. Routes
+// declaring neither a permission nor authentication are rejected, so a
+// route registered without one is never left open. Missing permissions
+// are returned as fault.Forbidden errors, rendered as 403.
+func Authorize(authorizer Authorizer, lookup PermissionLookup) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			code, declared := lookup(c.Request().Method, c.Path())
+			if !declared {
+				return fault.New("route declares no permission").Code(fault.Forbidden).
+					With("method", c.Request().Method).
+					With("path", c.Path())
+			}
+			if code == "" {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			principal, ok := auth.PrincipalFrom(ctx)
+			if !ok {
+				return fault.New("missing principal").Code(fault.Unauthorized)
+			}
+
+			access, err := authorizer.Authorize(ctx, principal, code)
+			if err != nil {
+				return err
+			}
+
+			trace.SpanFromContext(ctx).SetAttributes(
+				attribute.String("enduser.permission", code),
+				attribute.String("enduser.scope", string(access.Scope)),
+			)
+
+			if t, ok := tenant.From(ctx); ok && access.Limited {
+				ctx = tenant.WithTenant(ctx, t.Within(access.Organizations))
+			}
+
+			c.SetRequest(c.Request().WithContext(rbac.WithAccess(ctx, access)))
+			return next(c)
+		}
+	}
+}
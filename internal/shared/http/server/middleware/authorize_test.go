@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type fakeAuthorizer map[string]rbac.Scope
+
+func (f fakeAuthorizer) Authorize(ctx context.Context, principal auth.Principal, code string) (rbac.Access, error) {
+	scope, ok := f[code]
+	if !ok {
+		return rbac.Access{}, fault.New("missing permission").Code(fault.Forbidden)
+	}
+	return rbac.Access{Code: code, Scope: scope, UserID: principal.UserID}, nil
+}
+
+func TestAuthorize(t *testing.T) {
+	principal := auth.Principal{UserID: uuid.New()}
+	authorizer := fakeAuthorizer{"users.read": rbac.ScopeOwn}
+	permissions := map[string]string{
+		"GET /api/v1/users/:id":    "users.read",
+		"DELETE /api/v1/users/:id": "users.delete",
+		"PATCH /api/v1/users/:id":  "",
+	}
+	lookup := func(method, path string) (string, bool) {
+		code, ok := permissions[method+" "+path]
+		return code, ok
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		noPrincipal   bool
+		wantCode      fault.Code
+		wantAccess    bool
+		wantAccessFor string
+	}{
+		{name: "granted", method: http.MethodGet, wantAccess: true, wantAccessFor: "users.read"},
+		{name: "missing permission", method: http.MethodDelete, wantCode: fault.Forbidden},
+		{name: "authenticated route", method: http.MethodPatch},
+		{name: "route without permission", method: http.MethodPut, wantCode: fault.Forbidden},
+		{name: "unauthenticated", method: http.MethodGet, noPrincipal: true, wantCode: fault.Unauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(tt.method, "/api/v1/users/42", nil)
+			if !tt.noPrincipal {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+			c.SetPath("/api/v1/users/:id")
+
+			var access rbac.Access
+			var hasAccess bool
+			handler := Authorize(authorizer, lookup)(func(c echo.Context) error {
+				access, hasAccess = rbac.AccessFrom(c.Request().Context())
+				return nil
+			})
+
+			err := handler(c)
+
+			if tt.wantCode != "" {
+				if fault.CodeOf(err) != tt.wantCode {
+					t.Fatalf("Expected %s error, got %v", tt.wantCode, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if hasAccess != tt.wantAccess {
+				t.Fatalf("Expected access in the context: %v, got %+v", tt.wantAccess, access)
+			}
+			if tt.wantAccess && (access.Code != tt.wantAccessFor || access.Scope != rbac.ScopeOwn || access.UserID != principal.UserID) {
+				t.Errorf("Unexpected access %+v", access)
+			}
+		})
+	}
+}
+
+type limitedAuthorizer []uuid.UUID
+
+func (f limitedAuthorizer) Authorize(_ context.Context, principal auth.Principal, code string) (rbac.Access, error) {
+	return rbac.Access{Code: code, Scope: rbac.ScopeAll, UserID: principal.UserID, Organizations: f, Limited: true}, nil
+}
+
+func TestAuthorize_LimitsTenant(t *testing.T) {
+	orgA, orgB := uuid.New(), uuid.New()
+	lookup := func(string, string) (string, bool) { return "users.delete", true }
+
+	tests := []struct {
+		name   string
+		tenant tenant.Tenant
+		want   tenant.Tenant
+	}{
+		{name: "memberships", tenant: tenant.Tenant{Organizations: []uuid.UUID{orgA, orgB}}, want: tenant.Tenant{Organizations: []uuid.UUID{orgA}}},
+		{name: "every organization", tenant: tenant.Tenant{All: true}, want: tenant.Tenant{All: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/users/42", nil)
+			ctx := tenant.WithTenant(auth.WithPrincipal(req.Context(), auth.Principal{UserID: uuid.New()}), tt.tenant)
+			c := e.NewContext(req.WithContext(ctx), httptest.NewRecorder())
+			c.SetPath("/api/v1/users/:id")
+
+			var got tenant.Tenant
+			handler := Authorize(limitedAuthorizer{orgA}, lookup)(func(c echo.Context) error {
+				got, _ = tenant.From(c.Request().Context())
+				return nil
+			})
+
+			if err := handler(c); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected tenant %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
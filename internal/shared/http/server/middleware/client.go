@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"api.system.soluciones-cloud.com/internal/shared/http/server/request"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Client stores the user agent and IP address of the request in the
+// request context, so handlers adapted with server.Handle can read them
+// with request.ClientFrom. The IP is resolved with echo's IPExtractor.
+func Client() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			c.SetRequest(req.WithContext(request.WithClient(req.Context(), request.Client{
+				UserAgent: req.UserAgent(),
+				IPAddress: c.RealIP(),
+			})))
+
+			return next(c)
+		}
+	}
+}
@@ -1,47 +1,90 @@
 package middleware
 
 import (
+	"context"
 	"errors"
+	"net/http"
+
 	"api.system.soluciones-cloud.com/internal/shared/fault"
 	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
 	"api.system.soluciones-cloud.com/internal/shared/ports"
 
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// ErrorHandler creates a custom error handler that integrates with the fault package
-func ErrorHandler(logger ports.Logger) echo.HTTPErrorHandler {
+// ErrorHandler creates a custom error handler that integrates with the fault package.
+// The full error chain goes to the logs and the request span; clients only
+// get what policy allows, plus the correlation id to find it.
+func ErrorHandler(logger ports.Logger, policy response.Policy) echo.HTTPErrorHandler {
 	return func(err error, c echo.Context) {
 		ctx := c.Request().Context()
 		
 		var faultErr *fault.Error
 		if errors.As(err, &faultErr) {
-			resp := response.FromError(faultErr)
-			if jsonErr := c.JSON(resp.GetStatus(), resp); jsonErr != nil {
-				logger.Error(ctx, "failed to send fault error response", "error", jsonErr.Error())
-			}
+			resp := policy.FromError(ctx, faultErr)
+			recordError(ctx, logger, err, resp.GetStatus())
+			sendError(c, logger, withCorrelationID(c, resp))
 			return
 		}
 
 		// Handle Echo HTTP errors
 		if he, ok := err.(*echo.HTTPError); ok {
-			message := "Internal Server Error"
-			if he.Message != nil {
-				message = he.Message.(string)
+			resp := response.Problem(ctx, he.Code)
+			if message, ok := he.Message.(string); ok && message != http.StatusText(he.Code) {
+				resp.Detail(message)
 			}
-			resp := response.New[any]().Status(he.Code).Detail(message)
-			if jsonErr := c.JSON(he.Code, resp); jsonErr != nil {
-				logger.Error(ctx, "failed to send echo error response", "error", jsonErr.Error())
+			if he.Internal != nil {
+				recordError(ctx, logger, he.Internal, he.Code)
 			}
+			sendError(c, logger, withCorrelationID(c, resp))
 			return
 		}
 
 		// Generic error
-		logger.Error(ctx, "unhandled error", "error", err.Error())
-		resp := response.InternalError()
-		if jsonErr := c.JSON(resp.GetStatus(), resp); jsonErr != nil {
-			logger.Error(ctx, "failed to send generic error response", "error", jsonErr.Error())
-		}
+		recordError(ctx, logger, err, http.StatusInternalServerError)
+		resp := response.InternalError(ctx).Extension("error_code", string(fault.CodeOf(err)))
+		sendError(c, logger, withCorrelationID(c, resp))
+	}
+}
+
+// recordError logs err and records it on the request span. Server errors
+// are logged as errors, client errors as warnings.
+func recordError(ctx context.Context, logger ports.Logger, err error, status int) {
+	code := fault.CodeOf(err)
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetAttributes(attribute.String("error.code", string(code)))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, string(code))
+		logger.Error(ctx, "request failed", "error", err.Error(), "code", code, "status", status)
+		return
+	}
+	logger.Warn(ctx, "request rejected", "error", err.Error(), "code", code, "status", status)
+}
+
+// withCorrelationID adds the trace id as correlation id, or the request id
+// when the request is not traced.
+func withCorrelationID(c echo.Context, resp *response.Response[any]) *response.Response[any] {
+	id := response.CorrelationID(c.Request().Context())
+	if id == "" {
+		id = c.Response().Header().Get(echo.HeaderXRequestID)
+	}
+	if id != "" {
+		resp.Extension("correlation_id", id)
+	}
+	return resp
+}
+
+func sendError(c echo.Context, logger ports.Logger, resp *response.Response[any]) {
+	if c.Response().Committed {
+		return
+	}
+	if jsonErr := c.JSON(resp.GetStatus(), resp); jsonErr != nil {
+		logger.Error(c.Request().Context(), "failed to send error response", "error", jsonErr.Error())
 	}
 }
 
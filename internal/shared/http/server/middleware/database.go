@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Database stores the executor in the request context, so the Exists and
+// Unique rules of valid can run in handlers adapted with server.Handle and
+// in the use cases they call.
+func Database(executor ports.DatabaseExecutor) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			c.SetRequest(req.WithContext(valid.WithExecutor(req.Context(), executor)))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/labstack/echo/v4"
+)
+
+var errQueried = errors.New("queried")
+
+type fakeExecutor struct{}
+
+func (fakeExecutor) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errQueried
+}
+
+func (fakeExecutor) QueryRow(context.Context, string, ...any) pgx.Row { return nil }
+
+func (fakeExecutor) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errQueried
+}
+
+func TestDatabase(t *testing.T) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	var result *valid.Result
+	err := Database(fakeExecutor{})(func(c echo.Context) error {
+		result = valid.String().Rule(valid.Exists("billing.currencies", "code")).ParseCtx(c.Request().Context(), "PEN")
+		return nil
+	})(c)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !errors.Is(result.Cause, errQueried) {
+		t.Errorf("Expected the rule to query the executor, got cause %v", result.Cause)
+	}
+}
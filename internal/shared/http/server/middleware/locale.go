@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// LocaleResolver returns the preferred locale for a request, if it knows one.
+// Resolvers are tried in order before falling back to Accept-Language, so a
+// resolver reading the authenticated user's preferences takes precedence.
+type LocaleResolver func(c echo.Context) (i18n.Locale, bool)
+
+// LocalePreferences returns the locale a user chose, or "" when they chose
+// none, e.g. the user use case.
+type LocalePreferences interface {
+	PreferredLocale(ctx context.Context, userID uuid.UUID) (i18n.Locale, error)
+}
+
+// Locale stores the request locale in the request context so valid, fault
+// and response can render messages per request. It may run again after
+// Authenticate with a UserLocale resolver, replacing the locale resolved
+// before the principal was known.
+func Locale(resolvers ...LocaleResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			locale := resolveLocale(c, resolvers)
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(i18n.WithLocale(req.Context(), locale)))
+			c.Response().Header().Set("Content-Language", string(locale))
+
+			return next(c)
+		}
+	}
+}
+
+// QueryLocale resolves the locale from a query parameter such as ?lang=es.
+func QueryLocale(param string) LocaleResolver {
+	return func(c echo.Context) (i18n.Locale, bool) {
+		value := c.QueryParam(param)
+		if value == "" {
+			return "", false
+		}
+		return i18n.Normalize(value), true
+	}
+}
+
+// UserLocale resolves the locale the authenticated user chose. It must run
+// after Authenticate; requests without a principal, or whose user chose no
+// locale, are left to the next resolver.
+func UserLocale(preferences LocalePreferences) LocaleResolver {
+	return func(c echo.Context) (i18n.Locale, bool) {
+		ctx := c.Request().Context()
+		principal, ok := auth.PrincipalFrom(ctx)
+		if !ok {
+			return "", false
+		}
+
+		locale, err := preferences.PreferredLocale(ctx, principal.UserID)
+		if err != nil || locale == "" {
+			return "", false
+		}
+		return locale, true
+	}
+}
+
+func resolveLocale(c echo.Context, resolvers []LocaleResolver) i18n.Locale {
+	for _, resolve := range resolvers {
+		if locale, ok := resolve(c); ok && i18n.Default.Supports(locale) {
+			return locale
+		}
+	}
+
+	return i18n.Default.Match(c.Request().Header.Get("Accept-Language"))
+}
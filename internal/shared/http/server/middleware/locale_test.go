@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type fakePreferences map[uuid.UUID]i18n.Locale
+
+func (f fakePreferences) PreferredLocale(_ context.Context, userID uuid.UUID) (i18n.Locale, error) {
+	locale, ok := f[userID]
+	if !ok {
+		return "", errors.New("user not found")
+	}
+	return locale, nil
+}
+
+func TestLocale_UserLocale(t *testing.T) {
+	spanish, undecided, unsupported := uuid.New(), uuid.New(), uuid.New()
+	preferences := fakePreferences{spanish: i18n.Spanish, undecided: "", unsupported: "xx"}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		target    string
+		want      i18n.Locale
+	}{
+		{name: "user preference over Accept-Language", principal: &auth.Principal{UserID: spanish}, target: "/", want: i18n.Spanish},
+		{name: "query over user preference", principal: &auth.Principal{UserID: spanish}, target: "/?lang=en", want: i18n.English},
+		{name: "no preference", principal: &auth.Principal{UserID: undecided}, target: "/", want: i18n.English},
+		{name: "unsupported preference", principal: &auth.Principal{UserID: unsupported}, target: "/", want: i18n.English},
+		{name: "unknown user", principal: &auth.Principal{UserID: uuid.New()}, target: "/", want: i18n.English},
+		{name: "unauthenticated", target: "/", want: i18n.English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+			if tt.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *tt.principal))
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			var got i18n.Locale
+			err := Locale(QueryLocale("lang"), UserLocale(preferences))(func(c echo.Context) error {
+				got = i18n.FromContext(c.Request().Context())
+				return nil
+			})(c)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Expected locale %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
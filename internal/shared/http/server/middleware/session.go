@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SessionChecker rejects the principals of revoked login sessions, e.g.
+// the session use case.
+type SessionChecker interface {
+	CheckSession(ctx context.Context, principal auth.Principal) error
+}
+
+// Session rejects the requests made with access tokens of revoked
+// sessions, before they expire. Principals without a session, e.g. of API
+// keys, are let through. It must run after Tenant, so the checker sees
+// the organization the request acts on.
+func Session(sessions SessionChecker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			principal, ok := auth.PrincipalFrom(ctx)
+			if !ok {
+				return fault.New("missing principal").Code(fault.Unauthorized)
+			}
+
+			if err := sessions.CheckSession(ctx, principal); err != nil {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="api", error="invalid_token"`)
+				return err
+			}
+
+			return next(c)
+		}
+	}
+}
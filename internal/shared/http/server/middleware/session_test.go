@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type fakeSessions map[uuid.UUID]bool
+
+func (f fakeSessions) CheckSession(_ context.Context, principal auth.Principal) error {
+	if principal.SessionID.Valid && f[principal.SessionID.UUID] {
+		return fault.New("session has been revoked").Code(fault.Unauthorized)
+	}
+	return nil
+}
+
+func TestSession(t *testing.T) {
+	active, revoked := uuid.New(), uuid.New()
+	sessions := fakeSessions{revoked: true}
+
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		wantCode  fault.Code
+	}{
+		{name: "active session", principal: &auth.Principal{UserID: uuid.New(), SessionID: uuid.NullUUID{UUID: active, Valid: true}}},
+		{name: "revoked session", principal: &auth.Principal{UserID: uuid.New(), SessionID: uuid.NullUUID{UUID: revoked, Valid: true}}, wantCode: fault.Unauthorized},
+		{name: "without session", principal: &auth.Principal{UserID: uuid.New()}},
+		{name: "unauthenticated", wantCode: fault.Unauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/me/sessions", nil)
+			if tt.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *tt.principal))
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			called := false
+			err := Session(sessions)(func(echo.Context) error {
+				called = true
+				return nil
+			})(c)
+
+			if tt.wantCode != "" {
+				if fault.CodeOf(err) != tt.wantCode {
+					t.Fatalf("Expected %s error, got %v", tt.wantCode, err)
+				}
+				if called {
+					t.Error("Expected the request to be rejected")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !called {
+				t.Error("Expected the request to be served")
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tenant resolves the organizations the request acts on from the
+// principal and the X-Organization-ID header (see tenant.Resolve) and
+// stores them in the request context (tenant.From), so repositories scope
+// their queries to them. It must run after Authenticate and before
+// Authorize: a member selecting one of their organizations is authorized
+// with the roles of that organization only.
+func Tenant(memberships tenant.Memberships) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			principal, ok := auth.PrincipalFrom(ctx)
+			if !ok {
+				return fault.New("missing principal").Code(fault.Unauthorized)
+			}
+
+			requested := c.Request().Header.Get(tenant.Header)
+			t, principal, err := tenant.Resolve(ctx, memberships, principal, requested)
+			if err != nil {
+				return err
+			}
+
+			if requested != "" {
+				trace.SpanFromContext(ctx).SetAttributes(attribute.String("enduser.organization", requested))
+			}
+
+			ctx = auth.WithPrincipal(tenant.WithTenant(ctx, t), principal)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type fakeMemberships []uuid.UUID
+
+func (f fakeMemberships) Permissions(context.Context, auth.Principal) (rbac.Permissions, error) {
+	return rbac.Permissions{Organizations: f}, nil
+}
+
+func TestTenant(t *testing.T) {
+	orgA, orgB := uuid.New(), uuid.New()
+	memberships := fakeMemberships{orgA, orgB}
+
+	tests := []struct {
+		name          string
+		header        string
+		noPrincipal   bool
+		wantCode      fault.Code
+		wantTenant    []uuid.UUID
+		wantPrincipal uuid.NullUUID
+	}{
+		{name: "memberships", wantTenant: []uuid.UUID{orgA, orgB}},
+		{name: "selected organization", header: orgB.String(), wantTenant: []uuid.UUID{orgB}, wantPrincipal: uuid.NullUUID{UUID: orgB, Valid: true}},
+		{name: "foreign organization", header: uuid.NewString(), wantCode: fault.Forbidden},
+		{name: "unauthenticated", noPrincipal: true, wantCode: fault.Unauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/roles", nil)
+			if tt.header != "" {
+				req.Header.Set(tenant.Header, tt.header)
+			}
+			if !tt.noPrincipal {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{UserID: uuid.New()}))
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			var got tenant.Tenant
+			var principal auth.Principal
+			handler := Tenant(memberships)(func(c echo.Context) error {
+				got, _ = tenant.From(c.Request().Context())
+				principal, _ = auth.PrincipalFrom(c.Request().Context())
+				return nil
+			})
+
+			err := handler(c)
+
+			if tt.wantCode != "" {
+				if fault.CodeOf(err) != tt.wantCode {
+					t.Fatalf("Expected %s error, got %v", tt.wantCode, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(got.Organizations) != len(tt.wantTenant) {
+				t.Fatalf("Expected tenant %v, got %+v", tt.wantTenant, got)
+			}
+			for i := range got.Organizations {
+				if got.Organizations[i] != tt.wantTenant[i] {
+					t.Fatalf("Expected tenant %v, got %+v", tt.wantTenant, got)
+				}
+			}
+			if principal.OrganizationID != tt.wantPrincipal {
+				t.Errorf("Expected principal organization %v, got %v", tt.wantPrincipal, principal.OrganizationID)
+			}
+		})
+	}
+}
@@ -3,12 +3,16 @@ package server
 import (
 	"context"
 	"fmt"
+	"api.system.soluciones-cloud.com/internal/shared/auth/token"
 	"api.system.soluciones-cloud.com/internal/shared/fault"
 	"api.system.soluciones-cloud.com/internal/shared/http/server/middleware"
+	"api.system.soluciones-cloud.com/internal/shared/http/server/response"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
 	"api.system.soluciones-cloud.com/internal/shared/localconfig"
 	"api.system.soluciones-cloud.com/internal/shared/ports"
 	"api.system.soluciones-cloud.com/internal/shared/telemetry"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -17,17 +21,33 @@ import (
 	"go.uber.org/fx"
 )
 
+// APIPrefix starts the paths of the public and private API routes
+const APIPrefix = "/api/v1"
+
 type EchoServer struct {
 	API        *echo.Echo
 	PrivateAPI *echo.Group
 	PublicAPI  *echo.Group
 }
 
+// RouteNotFound makes the paths under APIPrefix that no route matches
+// answer 404. Group.Use registers catch-all routes running the middleware
+// of the group, so they would otherwise be rejected by Authenticate with a
+// 401. Call it once the middleware of the groups is set.
+func (s *EchoServer) RouteNotFound() {
+	s.API.RouteNotFound(APIPrefix, echo.NotFoundHandler)
+	s.API.RouteNotFound(APIPrefix+"/*", echo.NotFoundHandler)
+}
+
 type ServerParams struct {
 	fx.In
 	Config   *localconfig.Config
 	Logger   ports.Logger
 	Database ports.Database
+	Verifier *token.Verifier
+	// APIKeys authenticates API keys. Without it private routes only
+	// accept access tokens.
+	APIKeys ports.APIKeyUseCase `optional:"true"`
 }
 
 var Module = fx.Module("http_server",
@@ -37,14 +57,27 @@ var Module = fx.Module("http_server",
 func NewEchoServer(params ServerParams, lc fx.Lifecycle) *EchoServer {
 	api := echo.New()
 
+	// Message catalogs
+	i18n.Default.SetFallback(i18n.Locale(params.Config.I18N.DefaultLocale))
+	if dir := params.Config.I18N.MessagesDir; dir != "" {
+		if err := i18n.Default.LoadFS(os.DirFS(dir), "."); err != nil {
+			params.Logger.Error(context.Background(), "failed to load message files", "dir", dir, "error", err.Error())
+		}
+	}
+
 	// HTTP Error Handler using custom middleware
-	api.HTTPErrorHandler = middleware.ErrorHandler(params.Logger)
+	api.HTTPErrorHandler = middleware.ErrorHandler(params.Logger, errorPolicy(params.Config))
 
 	// Basic middleware
 	api.Use(otelecho.Middleware("api"))
 	api.Use(echomiddleware.RequestID())
 	api.Use(echomiddleware.Recover())
+	// Bounds every request body, uploads included; larger ones get 413
+	api.Use(echomiddleware.BodyLimit(fmt.Sprint(params.Config.HTTP.MaxBodySize)))
 	api.Use(echomiddleware.Logger())
+	api.Use(middleware.Locale(middleware.QueryLocale("lang")))
+	api.Use(middleware.Client())
+	api.Use(middleware.Database(params.Database))
 	// api.Use(middleware.RequestLogger(params.Logger))
 
 	// CORS middleware
@@ -53,9 +86,14 @@ func NewEchoServer(params ServerParams, lc fx.Lifecycle) *EchoServer {
 		AllowMethods: params.Config.HTTP.AllowedMethods,
 	}))
 
-	// API groups
-	publicAPI := api.Group("/api/v1")
-	privateAPI := api.Group("/api/v1")
+	// API groups. Both share the prefix; private routes require a bearer
+	// token or an API key, public ones (e.g. login) do not.
+	var apiKeys middleware.APIKeyVerifier
+	if params.APIKeys != nil {
+		apiKeys = params.APIKeys
+	}
+	publicAPI := api.Group(APIPrefix)
+	privateAPI := api.Group(APIPrefix, middleware.Authenticate(params.Verifier, apiKeys))
 
 	server := &EchoServer{
 		API:        api,
@@ -65,7 +103,7 @@ func NewEchoServer(params ServerParams, lc fx.Lifecycle) *EchoServer {
 
 	// Add health check endpoint
 	api.GET("/health", func(c echo.Context) error {
-		return healthCheckHandler(c, params.Database)
+		return healthCheckHandler(c, params.Database, params.Config.IsDevelopment())
 	})
 
 	// Lifecycle management
@@ -119,15 +157,26 @@ func NewEchoServer(params ServerParams, lc fx.Lifecycle) *EchoServer {
 	return server
 }
 
-func healthCheckHandler(c echo.Context, database ports.Database) error {
+// errorPolicy shows internals only in development
+func errorPolicy(config *localconfig.Config) response.Policy {
+	if config.IsDevelopment() {
+		return response.DevelopmentPolicy()
+	}
+	return response.ProductionPolicy()
+}
+
+func healthCheckHandler(c echo.Context, database ports.Database, exposeCause bool) error {
 	if database != nil {
 		if err := database.Ping(c.Request().Context()); err != nil {
-			return c.JSON(http.StatusServiceUnavailable, map[string]any{
+			body := map[string]any{
 				"status": "unhealthy",
 				"error":  "database connection failed",
-				"cause":  err.Error(),
 				"time":   time.Now().UTC(),
-			})
+			}
+			if exposeCause {
+				body["cause"] = err.Error()
+			}
+			return c.JSON(http.StatusServiceUnavailable, body)
 		}
 	}
 
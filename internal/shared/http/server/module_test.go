@@ -0,0 +1,55 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestEchoServer_RouteNotFound(t *testing.T) {
+	api := echo.New()
+	authenticate := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get(echo.HeaderAuthorization) == "" {
+				return echo.ErrUnauthorized
+			}
+			return next(c)
+		}
+	}
+
+	server := &EchoServer{
+		API:        api,
+		PublicAPI:  api.Group(APIPrefix),
+		PrivateAPI: api.Group(APIPrefix, authenticate),
+	}
+	server.PrivateAPI.Use(func(next echo.HandlerFunc) echo.HandlerFunc { return next })
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	server.PublicAPI.POST("/auth/login", ok)
+	server.PrivateAPI.GET("/users", ok)
+	server.RouteNotFound()
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   int
+	}{
+		{name: "public route", method: http.MethodPost, target: "/api/v1/auth/login", want: http.StatusOK},
+		{name: "private route without credentials", method: http.MethodGet, target: "/api/v1/users", want: http.StatusUnauthorized},
+		{name: "unknown path", method: http.MethodGet, target: "/api/v1/unknown", want: http.StatusNotFound},
+		{name: "prefix", method: http.MethodGet, target: "/api/v1", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			api.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package request
+
+import "context"
+
+// Client describes the client that sent a request, e.g. to record where a
+// session was started.
+type Client struct {
+	UserAgent string
+	IPAddress string
+}
+
+type clientKey struct{}
+
+// WithClient returns a copy of ctx carrying client.
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFrom returns the client stored in ctx by middleware.Client.
+func ClientFrom(ctx context.Context) Client {
+	client, _ := ctx.Value(clientKey{}).(Client)
+	return client
+}
@@ -1,11 +1,10 @@
 package response
 
 import (
-	"errors"
+	"context"
 	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
 	"net/http"
-
-	"github.com/jackc/pgx/v5"
 )
 
 const DefaultProblemType = "about:blank"
@@ -104,123 +103,63 @@ func Ok[T any](data T) *Response[T] {
 }
 
 // Created creates a 201 Created response with data
-func Created[T any](data T) *Response[T] {
+func Created[T any](ctx context.Context, data T) *Response[T] {
+	locale := i18n.FromContext(ctx)
 	return &Response[T]{
 		TypeURI:    DefaultProblemType,
-		TitleText:  "Recurso Creado",
-		DetailText: "El recurso fue creado exitosamente",
+		TitleText:  i18n.Default.Translate(locale, "response.created.title"),
+		DetailText: i18n.Default.Translate(locale, "response.created.detail"),
 		StatusCode: http.StatusCreated,
 		DataValue:  data,
 	}
 }
 
-// FromError creates a problem details response from a fault.Error
-func FromError(err *fault.Error) *Response[any] {
-	if err == nil {
-		return InternalError()
-	}
-
-	if errors.Is(err.Cause, pgx.ErrNoRows) {
-		return NotFound()
-	}
-
-	response := &Response[any]{
-		TypeURI:    DefaultProblemType,
-		StatusCode: err.HTTPStatus(),
-		Extensions: make(map[string]any),
-	}
-
-	// Use fault title if available
-	if err.HasTitle() {
-		response.TitleText = err.TitleText
-	} else {
-		// Use default title based on status code
-		if title, exists := TitleByStatus[response.StatusCode]; exists {
-			response.TitleText = title
-		}
-	}
-
-	// Use fault message as detail
-	if err.MessageText != "" {
-		response.DetailText = err.MessageText
-	} else {
-		// Use default detail based on status code
-		if detail, exists := DetailByStatus[response.StatusCode]; exists {
-			response.DetailText = detail
-		}
-	}
-
-	// Add debug information as extension
-	if err.Error() != "" {
-		response.Extension("debug_error", err.Error())
-	}
-
-	// Add fault code as extension
-	if err.CodeName != "" {
-		response.Extension("error_code", err.CodeName)
-	}
-
-	return response
+// FromError creates a problem details response from a fault.Error using
+// the production policy. Servers pass their own Policy to the error handler.
+func FromError(ctx context.Context, err *fault.Error) *Response[any] {
+	return ProductionPolicy().FromError(ctx, err)
 }
 
 // Predefined error responses
 
-// NotFound creates a 404 Not Found response
-func NotFound() *Response[any] {
+// Problem creates an error response for status with the default title and
+// detail in the locale carried by ctx
+func Problem(ctx context.Context, status int) *Response[any] {
+	locale := i18n.FromContext(ctx)
 	return &Response[any]{
 		TypeURI:    DefaultProblemType,
-		TitleText:  "Recurso No Encontrado",
-		DetailText: "El recurso solicitado no pudo ser encontrado",
-		StatusCode: http.StatusNotFound,
+		TitleText:  TitleFor(locale, status),
+		DetailText: DetailFor(locale, status),
+		StatusCode: status,
 	}
 }
 
+// NotFound creates a 404 Not Found response
+func NotFound(ctx context.Context) *Response[any] {
+	return Problem(ctx, http.StatusNotFound)
+}
+
 // BadRequest creates a 400 Bad Request response
-func BadRequest() *Response[any] {
-	return &Response[any]{
-		TypeURI:    DefaultProblemType,
-		TitleText:  "Solicitud Incorrecta",
-		DetailText: "La solicitud es inválida o está mal formada",
-		StatusCode: http.StatusBadRequest,
-	}
+func BadRequest(ctx context.Context) *Response[any] {
+	return Problem(ctx, http.StatusBadRequest)
 }
 
 // Unauthorized creates a 401 Unauthorized response
-func Unauthorized() *Response[any] {
-	return &Response[any]{
-		TypeURI:    DefaultProblemType,
-		TitleText:  "No Autorizado",
-		DetailText: "Se requiere autenticación para acceder a este recurso",
-		StatusCode: http.StatusUnauthorized,
-	}
+func Unauthorized(ctx context.Context) *Response[any] {
+	return Problem(ctx, http.StatusUnauthorized)
 }
 
 // Forbidden creates a 403 Forbidden response
-func Forbidden() *Response[any] {
-	return &Response[any]{
-		TypeURI:    DefaultProblemType,
-		TitleText:  "Prohibido",
-		DetailText: "No tienes permisos para acceder a este recurso",
-		StatusCode: http.StatusForbidden,
-	}
+func Forbidden(ctx context.Context) *Response[any] {
+	return Problem(ctx, http.StatusForbidden)
 }
 
 // InternalError creates a 500 Internal Server Error response
-func InternalError() *Response[any] {
-	return &Response[any]{
-		TypeURI:    DefaultProblemType,
-		TitleText:  "Error Interno del Servidor",
-		DetailText: "Ocurrió un error inesperado en el servidor",
-		StatusCode: http.StatusInternalServerError,
-	}
+func InternalError(ctx context.Context) *Response[any] {
+	return Problem(ctx, http.StatusInternalServerError)
 }
 
 // UnprocessableEntity creates a 422 Unprocessable Entity response
-func UnprocessableEntity() *Response[any] {
-	return &Response[any]{
-		TypeURI:    DefaultProblemType,
-		TitleText:  "Validación Fallida",
-		DetailText: "Los datos proporcionados no cumplen con los requisitos de validación",
-		StatusCode: http.StatusUnprocessableEntity,
-	}
+func UnprocessableEntity(ctx context.Context) *Response[any] {
+	return Problem(ctx, http.StatusUnprocessableEntity)
 }
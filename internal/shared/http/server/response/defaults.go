@@ -1,11 +1,16 @@
 package response
 
-import "net/http"
+import (
+	"net/http"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+)
 
 // TitleByStatus provides default titles for HTTP status codes
 var TitleByStatus = map[int]string{
 	http.StatusBadRequest:                    "Bad Request",
 	http.StatusUnauthorized:                  "Unauthorized",
+	http.StatusPaymentRequired:               "Payment Required",
 	http.StatusForbidden:                     "Forbidden",
 	http.StatusNotFound:                      "Resource Not Found",
 	http.StatusMethodNotAllowed:              "Method Not Allowed",
@@ -42,12 +47,14 @@ var TitleByStatus = map[int]string{
 	http.StatusLoopDetected:                  "Loop Detected",
 	http.StatusNotExtended:                   "Not Extended",
 	http.StatusNetworkAuthenticationRequired: "Network Authentication Required",
+	fault.StatusClientClosedRequest:          "Client Closed Request",
 }
 
 // DetailByStatus provides default detail messages for HTTP status codes
 var DetailByStatus = map[int]string{
 	http.StatusBadRequest:                    "The request is invalid or malformed. Please check your request and try again.",
 	http.StatusUnauthorized:                  "Authentication is required to access this resource. Please provide valid credentials.",
+	http.StatusPaymentRequired:               "Payment is required to complete this request.",
 	http.StatusForbidden:                     "You do not have permission to access this resource.",
 	http.StatusNotFound:                      "The requested resource could not be found on the server.",
 	http.StatusMethodNotAllowed:              "The HTTP method used is not allowed for this resource.",
@@ -84,4 +91,5 @@ var DetailByStatus = map[int]string{
 	http.StatusLoopDetected:                  "The server detected an infinite loop while processing the request.",
 	http.StatusNotExtended:                   "Further extensions to the request are required for the server to fulfill it.",
 	http.StatusNetworkAuthenticationRequired: "The client needs to authenticate to gain network access.",
+	fault.StatusClientClosedRequest:          "The client closed the connection before the request completed.",
 }
@@ -0,0 +1,103 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+)
+
+// spanishTitleByStatus and spanishDetailByStatus translate the most common
+// statuses. Missing entries fall back to English.
+var spanishTitleByStatus = map[int]string{
+	http.StatusBadRequest:            "Solicitud Incorrecta",
+	http.StatusUnauthorized:          "No Autorizado",
+	http.StatusForbidden:             "Prohibido",
+	http.StatusNotFound:              "Recurso No Encontrado",
+	http.StatusMethodNotAllowed:      "Método No Permitido",
+	http.StatusRequestTimeout:        "Tiempo de Espera Agotado",
+	http.StatusConflict:              "Conflicto",
+	http.StatusGone:                  "Recurso No Disponible",
+	http.StatusPreconditionFailed:    "Precondición Fallida",
+	http.StatusPaymentRequired:       "Pago Requerido",
+	http.StatusRequestEntityTooLarge: "Contenido Demasiado Grande",
+	http.StatusUnsupportedMediaType:  "Tipo de Contenido No Soportado",
+	http.StatusUnprocessableEntity:   "Validación Fallida",
+	http.StatusTooManyRequests:       "Demasiadas Solicitudes",
+	http.StatusInternalServerError:   "Error Interno del Servidor",
+	http.StatusNotImplemented:        "No Implementado",
+	http.StatusBadGateway:            "Puerta de Enlace Incorrecta",
+	http.StatusServiceUnavailable:    "Servicio No Disponible",
+	http.StatusGatewayTimeout:        "Tiempo de Espera de la Puerta de Enlace Agotado",
+	fault.StatusClientClosedRequest:  "Solicitud Cancelada por el Cliente",
+}
+
+var spanishDetailByStatus = map[int]string{
+	http.StatusBadRequest:            "La solicitud es inválida o está mal formada",
+	http.StatusUnauthorized:          "Se requiere autenticación para acceder a este recurso",
+	http.StatusForbidden:             "No tienes permisos para acceder a este recurso",
+	http.StatusNotFound:              "El recurso solicitado no pudo ser encontrado",
+	http.StatusMethodNotAllowed:      "El método HTTP utilizado no está permitido para este recurso",
+	http.StatusRequestTimeout:        "El servidor agotó el tiempo de espera de la solicitud",
+	http.StatusConflict:              "La solicitud entra en conflicto con el estado actual del recurso",
+	http.StatusGone:                  "El recurso solicitado ya no está disponible",
+	http.StatusPreconditionFailed:    "Una o más condiciones de la solicitud no se cumplieron",
+	http.StatusPaymentRequired:       "Se requiere un pago para completar la solicitud",
+	http.StatusRequestEntityTooLarge: "El contenido enviado supera el tamaño permitido",
+	http.StatusUnsupportedMediaType:  "El tipo de contenido enviado no está permitido",
+	http.StatusUnprocessableEntity:   "Los datos proporcionados no cumplen con los requisitos de validación",
+	http.StatusTooManyRequests:       "Has enviado demasiadas solicitudes en poco tiempo",
+	http.StatusInternalServerError:   "Ocurrió un error inesperado en el servidor",
+	http.StatusNotImplemented:        "El servidor no soporta la funcionalidad solicitada",
+	http.StatusBadGateway:            "El servidor recibió una respuesta inválida de un servicio externo",
+	http.StatusServiceUnavailable:    "El servicio no está disponible en este momento, intenta más tarde",
+	http.StatusGatewayTimeout:        "Un servicio externo no respondió a tiempo",
+	fault.StatusClientClosedRequest:  "El cliente cerró la conexión antes de completar la solicitud",
+}
+
+func init() {
+	english := map[string]string{
+		"response.created.title":  "Resource Created",
+		"response.created.detail": "The resource was created successfully",
+	}
+	for status, title := range TitleByStatus {
+		english[titleKey(status)] = title
+	}
+	for status, detail := range DetailByStatus {
+		english[detailKey(status)] = detail
+	}
+	i18n.Register(i18n.English, english)
+
+	spanish := map[string]string{
+		"response.created.title":  "Recurso Creado",
+		"response.created.detail": "El recurso fue creado exitosamente",
+	}
+	for status, title := range spanishTitleByStatus {
+		spanish[titleKey(status)] = title
+	}
+	for status, detail := range spanishDetailByStatus {
+		spanish[detailKey(status)] = detail
+	}
+	i18n.Register(i18n.Spanish, spanish)
+}
+
+// TitleFor returns the default title for status in locale.
+func TitleFor(locale i18n.Locale, status int) string {
+	template, _ := i18n.Default.Lookup(locale, titleKey(status))
+	return template
+}
+
+// DetailFor returns the default detail for status in locale.
+func DetailFor(locale i18n.Locale, status int) string {
+	template, _ := i18n.Default.Lookup(locale, detailKey(status))
+	return template
+}
+
+func titleKey(status int) string {
+	return fmt.Sprintf("response.title.%d", status)
+}
+
+func detailKey(status int) string {
+	return fmt.Sprintf("response.detail.%d", status)
+}
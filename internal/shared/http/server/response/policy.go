@@ -0,0 +1,133 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// DefaultSafeCodes lists the codes whose fault titles and messages are
+// written for API clients. Messages of other codes, such as internal_error,
+// may contain SQL, hostnames or other internals and are replaced by the
+// localized default for the code.
+var DefaultSafeCodes = []fault.Code{
+	fault.BadRequest,
+	fault.BindFailed,
+	fault.UnprocessableEntity,
+	fault.Unauthorized,
+	fault.PaymentRequired,
+	fault.Forbidden,
+	fault.NotFound,
+	fault.Conflict,
+	fault.PreconditionFailed,
+	fault.TooManyRequests,
+	fault.PayloadTooLarge,
+	fault.UnsupportedMediaType,
+}
+
+// Policy decides how much of an error reaches API clients. Every error
+// response carries the stable error_code and a correlation_id (the trace
+// id) that links it to the logs and spans holding the full error chain.
+type Policy struct {
+	// Debug adds the full chain and stack as the debug_error extension.
+	// Never enable it in production.
+	Debug bool
+
+	// SafeCodes lists the codes whose fault titles and messages are shown.
+	SafeCodes []fault.Code
+}
+
+// ProductionPolicy hides internals and shows messages of DefaultSafeCodes only
+func ProductionPolicy() Policy {
+	return Policy{SafeCodes: DefaultSafeCodes}
+}
+
+// DevelopmentPolicy shows every message and the debug_error extension
+func DevelopmentPolicy() Policy {
+	return Policy{Debug: true, SafeCodes: DefaultSafeCodes}
+}
+
+// IsSafe reports whether fault messages with code can be shown to clients
+func (p Policy) IsSafe(code fault.Code) bool {
+	return p.Debug || slices.Contains(p.SafeCodes, code)
+}
+
+// FromError creates a problem details response from a fault.Error.
+// Default titles and details are rendered in the locale carried by ctx.
+func (p Policy) FromError(ctx context.Context, err *fault.Error) *Response[any] {
+	if err == nil {
+		return withCorrelationID(ctx, InternalError(ctx))
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return withCorrelationID(ctx, NotFound(ctx).Extension("error_code", string(fault.NotFound)))
+	}
+
+	locale := i18n.FromContext(ctx)
+	code := fault.CodeOf(err)
+	safe := p.IsSafe(code)
+
+	response := &Response[any]{
+		TypeURI:    DefaultProblemType,
+		StatusCode: code.HTTPStatus(),
+		Extensions: make(map[string]any),
+	}
+
+	// Use fault title if available
+	if title := fault.TitleOf(err); title != "" && safe {
+		response.TitleText = title
+	} else {
+		// Use default title based on status code
+		response.TitleText = TitleFor(locale, response.StatusCode)
+	}
+
+	// Use fault message as detail
+	if message := fault.MessageOf(err); message != "" && safe {
+		response.DetailText = message
+	} else if message := code.Message(locale); message != "" {
+		response.DetailText = message
+	} else {
+		// Use default detail based on status code
+		response.DetailText = DetailFor(locale, response.StatusCode)
+	}
+
+	// Validation messages are written for end users, so they replace the detail
+	var validationErr *valid.ValidationError
+	if errors.As(err, &validationErr) {
+		response.DetailText = validationErr.Localize(locale).Error()
+	}
+
+	// Add debug information as extension
+	if p.Debug {
+		response.Extension("debug_error", err.Error())
+	}
+
+	// Add fault code as extension
+	response.Extension("error_code", string(code))
+
+	return withCorrelationID(ctx, response)
+}
+
+// CorrelationID returns the trace id of the span in ctx, or an empty string
+// when ctx carries no valid span.
+func CorrelationID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+func withCorrelationID(ctx context.Context, response *Response[any]) *Response[any] {
+	if id := CorrelationID(ctx); id != "" {
+		response.Extension("correlation_id", id)
+	}
+	return response
+}
@@ -0,0 +1,109 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/trace"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+)
+
+func TestPolicy_FromError(t *testing.T) {
+	dbErr := errors.New(`pq: relation "auth.users" does not exist at 10.0.3.7:5432`)
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		err        *fault.Error
+		wantStatus int
+		wantDetail string
+		wantCode   string
+		wantDebug  bool
+	}{
+		{
+			name:       "production hides internal messages",
+			policy:     ProductionPolicy(),
+			err:        fault.Wrap(dbErr).Message("failed to list users"),
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "An unexpected error occurred",
+			wantCode:   "internal_error",
+		},
+		{
+			name:       "production shows safe messages",
+			policy:     ProductionPolicy(),
+			err:        fault.Wrap(dbErr).Code(fault.Conflict).Message("email already registered"),
+			wantStatus: http.StatusConflict,
+			wantDetail: "email already registered",
+			wantCode:   "conflict",
+		},
+		{
+			name:       "inner safe code is inherited",
+			policy:     ProductionPolicy(),
+			err:        fault.Wrap(fault.New("user not found or already deleted").Code(fault.NotFound)),
+			wantStatus: http.StatusNotFound,
+			wantDetail: "user not found or already deleted",
+			wantCode:   "not_found",
+		},
+		{
+			name:       "development shows everything",
+			policy:     DevelopmentPolicy(),
+			err:        fault.Wrap(dbErr).Message("failed to list users"),
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "failed to list users",
+			wantCode:   "internal_error",
+			wantDebug:  true,
+		},
+		{
+			name:       "no rows is not found",
+			policy:     ProductionPolicy(),
+			err:        fault.Wrap(fault.Wrap(pgx.ErrNoRows).Message("failed to find user")),
+			wantStatus: http.StatusNotFound,
+			wantDetail: "The requested resource could not be found on the server.",
+			wantCode:   "not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := i18n.WithLocale(context.Background(), i18n.English)
+			got := tt.policy.FromError(ctx, tt.err)
+
+			if got.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, got.StatusCode)
+			}
+			if got.DetailText != tt.wantDetail {
+				t.Errorf("Expected detail %q, got %q", tt.wantDetail, got.DetailText)
+			}
+			if got.Extensions["error_code"] != tt.wantCode {
+				t.Errorf("Expected error_code %q, got %v", tt.wantCode, got.Extensions["error_code"])
+			}
+			if _, ok := got.Extensions["debug_error"]; ok != tt.wantDebug {
+				t.Errorf("Expected debug_error present=%t, got %v", tt.wantDebug, got.Extensions["debug_error"])
+			}
+		})
+	}
+}
+
+func TestPolicy_FromErrorCorrelationID(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	got := ProductionPolicy().FromError(ctx, fault.Wrap(errors.New("boom")))
+	if got.Extensions["correlation_id"] != traceID.String() {
+		t.Errorf("Expected correlation_id %s, got %v", traceID, got.Extensions["correlation_id"])
+	}
+
+	got = ProductionPolicy().FromError(context.Background(), fault.Wrap(errors.New("boom")))
+	if _, ok := got.Extensions["correlation_id"]; ok {
+		t.Error("Expected no correlation_id without a span")
+	}
+}
@@ -14,3 +14,13 @@ type ErrorResponse struct {
 	ServiceName string    `json:"serviceName,omitempty" example:"api"`
 	ServerTime  time.Time `json:"serverTime,omitempty" example:"2023-01-01T00:00:00Z"`
 }
+
+// CountResponse is the data of count endpoints
+type CountResponse struct {
+	Count int64 `json:"count"`
+}
+
+// ExistsResponse is the data of existence checks
+type ExistsResponse struct {
+	Exists bool `json:"exists"`
+}
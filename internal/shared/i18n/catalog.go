@@ -0,0 +1,153 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Catalog holds message templates by locale and key. Templates use fmt verbs.
+// It is safe for concurrent use.
+type Catalog struct {
+	mu       sync.RWMutex
+	fallback Locale
+	messages map[Locale]map[string]string
+}
+
+// Default is the process-wide catalog. Packages register their built-in
+// messages into it from init functions.
+var Default = NewCatalog(English)
+
+func NewCatalog(fallback Locale) *Catalog {
+	return &Catalog{
+		fallback: fallback,
+		messages: make(map[Locale]map[string]string),
+	}
+}
+
+// Add merges messages into locale, overriding existing keys.
+func (c *Catalog) Add(locale Locale, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	locale = Normalize(string(locale))
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string, len(messages))
+	}
+	for key, template := range messages {
+		c.messages[locale][key] = template
+	}
+}
+
+// LoadFS adds every "<locale>.json" file found in dir. Each file is a flat
+// JSON object of key to template, e.g. {"valid.required": "Champ requis"}.
+func (c *Catalog) LoadFS(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(content, &messages); err != nil {
+			return fmt.Errorf("i18n: invalid message file %s: %w", file, err)
+		}
+
+		c.Add(Locale(strings.TrimSuffix(path.Base(file), ".json")), messages)
+	}
+
+	return nil
+}
+
+// SetFallback changes the locale used when a key is missing or no locale
+// could be negotiated.
+func (c *Catalog) SetFallback(locale Locale) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fallback = Normalize(string(locale))
+}
+
+func (c *Catalog) Fallback() Locale {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.fallback
+}
+
+// Locales returns the registered locales in alphabetical order.
+func (c *Catalog) Locales() []Locale {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locales := make([]Locale, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Slice(locales, func(i, j int) bool { return locales[i] < locales[j] })
+	return locales
+}
+
+// Supports reports whether any message was registered for locale.
+func (c *Catalog) Supports(locale Locale) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.messages[Normalize(string(locale))]
+	return ok
+}
+
+// Match returns the first locale from an Accept-Language header that the
+// catalog supports, or the fallback.
+func (c *Catalog) Match(acceptLanguage string) Locale {
+	for _, locale := range ParseAcceptLanguage(acceptLanguage) {
+		if c.Supports(locale) {
+			return locale
+		}
+	}
+	return c.Fallback()
+}
+
+// Lookup returns the raw template for key, falling back to the fallback
+// locale. The boolean is false when neither has the key.
+func (c *Catalog) Lookup(locale Locale, key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if template, ok := c.messages[Normalize(string(locale))][key]; ok {
+		return template, true
+	}
+	template, ok := c.messages[c.fallback][key]
+	return template, ok
+}
+
+// Translate renders key for locale. Unknown keys are returned as-is so a
+// missing translation is visible instead of producing an empty message.
+func (c *Catalog) Translate(locale Locale, key string, args ...any) string {
+	template, ok := c.Lookup(locale, key)
+	if !ok {
+		return key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(template, args...)
+	}
+	return template
+}
+
+// Register adds messages for locale to the Default catalog.
+func Register(locale Locale, messages map[string]string) {
+	Default.Add(locale, messages)
+}
+
+// T renders key in the locale carried by ctx using the Default catalog.
+func T(ctx context.Context, key string, args ...any) string {
+	return Default.Translate(FromContext(ctx), key, args...)
+}
@@ -0,0 +1,129 @@
+package i18n
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []Locale
+	}{
+		{
+			name:   "empty header",
+			header: "",
+			want:   []Locale{},
+		},
+		{
+			name:   "single tag with region",
+			header: "es-PE",
+			want:   []Locale{Spanish},
+		},
+		{
+			name:   "ordered by quality",
+			header: "en;q=0.5, es-PE;q=0.9, fr",
+			want:   []Locale{"fr", Spanish, English},
+		},
+		{
+			name:   "drops wildcard and q=0",
+			header: "*, de;q=0, es",
+			want:   []Locale{Spanish},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseAcceptLanguage(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAcceptLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_Match(t *testing.T) {
+	catalog := NewCatalog(English)
+	catalog.Add(English, map[string]string{"greeting": "Hello"})
+	catalog.Add(Spanish, map[string]string{"greeting": "Hola"})
+
+	tests := []struct {
+		name   string
+		header string
+		want   Locale
+	}{
+		{name: "supported locale", header: "es-PE,es;q=0.9", want: Spanish},
+		{name: "skips unsupported locale", header: "fr-FR, es;q=0.8", want: Spanish},
+		{name: "falls back", header: "de", want: English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := catalog.Match(tt.header); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_Translate(t *testing.T) {
+	catalog := NewCatalog(English)
+	catalog.Add(English, map[string]string{
+		"min_length": "Must be at least %d characters",
+		"only_en":    "English only",
+	})
+	catalog.Add(Spanish, map[string]string{
+		"min_length": "Debe tener al menos %d caracteres",
+	})
+
+	tests := []struct {
+		name   string
+		locale Locale
+		key    string
+		args   []any
+		want   string
+	}{
+		{name: "with arguments", locale: Spanish, key: "min_length", args: []any{3}, want: "Debe tener al menos 3 caracteres"},
+		{name: "missing key falls back", locale: Spanish, key: "only_en", want: "English only"},
+		{name: "unknown key is returned", locale: Spanish, key: "unknown", want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := catalog.Translate(tt.locale, tt.key, tt.args...); got != tt.want {
+				t.Errorf("Translate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_LoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"messages/pt.json": {Data: []byte(`{"greeting": "Olá"}`)},
+		"messages/README":  {Data: []byte("ignored")},
+	}
+
+	catalog := NewCatalog(English)
+	if err := catalog.LoadFS(fsys, "messages"); err != nil {
+		t.Fatalf("LoadFS() error = %v", err)
+	}
+
+	if got := catalog.Translate("pt", "greeting"); got != "Olá" {
+		t.Errorf("Translate() = %v, want Olá", got)
+	}
+
+	fsys["messages/bad.json"] = &fstest.MapFile{Data: []byte(`not json`)}
+	if err := catalog.LoadFS(fsys, "messages"); err == nil {
+		t.Error("LoadFS() expected error for invalid file")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != Default.Fallback() {
+		t.Errorf("FromContext() = %v, want fallback", got)
+	}
+
+	ctx := WithLocale(context.Background(), Spanish)
+	if got := FromContext(ctx); got != Spanish {
+		t.Errorf("FromContext() = %v, want %v", got, Spanish)
+	}
+}
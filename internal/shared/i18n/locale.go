@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale is a BCP 47 primary language tag such as "en" or "es".
+type Locale string
+
+const (
+	English Locale = "en"
+	Spanish Locale = "es"
+)
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx that carries locale.
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns the locale stored in ctx, or the default catalog
+// fallback when none was set.
+func FromContext(ctx context.Context) Locale {
+	if ctx != nil {
+		if locale, ok := ctx.Value(localeKey{}).(Locale); ok && locale != "" {
+			return locale
+		}
+	}
+	return Default.Fallback()
+}
+
+// Normalize reduces a language tag to its lowercase primary subtag,
+// so "es-PE" and "ES" both become "es".
+func Normalize(tag string) Locale {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return Locale(strings.ToLower(tag))
+}
+
+type weightedLocale struct {
+	locale Locale
+	weight float64
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header
+// ordered by preference. Entries with q=0 and the "*" wildcard are dropped.
+func ParseAcceptLanguage(header string) []Locale {
+	var entries []weightedLocale
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		weight := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || key != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				weight = q
+			}
+		}
+
+		if weight <= 0 {
+			continue
+		}
+		entries = append(entries, weightedLocale{locale: Normalize(tag), weight: weight})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].weight > entries[j].weight
+	})
+
+	locales := make([]Locale, 0, len(entries))
+	for _, entry := range entries {
+		locales = append(locales, entry.locale)
+	}
+	return locales
+}
@@ -5,16 +5,46 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	HTTP     HTTPConfig
-	JWT      JWTConfig
-	Logger   LoggerConfig
-	OTEL     OTELConfig
+	// Environment is the deployment environment, e.g. development, staging
+	// or production
+	Environment string
+	Auth        AuthConfig
+	Database    DatabaseConfig
+	HTTP        HTTPConfig
+	I18N        I18NConfig
+	JWT         JWTConfig
+	Logger      LoggerConfig
+	Mail        MailConfig
+	OIDC        OIDCConfig
+	OTEL        OTELConfig
+	Storage     StorageConfig
+}
+
+type AuthConfig struct {
+	RefreshTokenTTL time.Duration
+	// MaxFailedLogins consecutive failures lock the account for
+	// LockoutDuration
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+	// VerifyEmailURL and ResetPasswordURL are the client pages the emails
+	// link to; the token is added as the token query parameter
+	VerifyEmailURL        string
+	ResetPasswordURL      string
+	VerificationTokenTTL  time.Duration
+	PasswordResetTokenTTL time.Duration
+	// PermissionCacheTTL bounds how long resolved permissions are cached.
+	// Changes made through the API invalidate them right away; the TTL
+	// covers changes made directly in the database or by other instances.
+	PermissionCacheTTL time.Duration
+	// SyncPermissions reconciles auth.modules and auth.module_actions with
+	// the permissions declared by the routes at startup
+	SyncPermissions bool
 }
 
 type DatabaseConfig struct {
@@ -30,10 +60,34 @@ type HTTPConfig struct {
 	Port           int
 	AllowedOrigins []string
 	AllowedMethods []string
+	// PublicURL is the URL clients reach the API at, e.g. in the links to
+	// uploaded files
+	PublicURL string
+	// MaxBodySize bounds request bodies in bytes, uploads included
+	MaxBodySize int64
+}
+
+type I18NConfig struct {
+	DefaultLocale string
+	MessagesDir   string
 }
 
 type JWTConfig struct {
+	// Secret verifies HS256 tokens without a kid
 	Secret string
+	// JWKSFile is a local JWKS file with the keys of tokens with a kid. It is
+	// reloaded when it changes, so keys can be rotated without a restart.
+	JWKSFile   string
+	Issuer     string
+	Audience   string
+	ClockSkew  time.Duration
+	Algorithms []string
+	// SigningKeyFile is a PKCS#8 PEM RSA or Ed25519 private key access
+	// tokens are signed with, published in the JWKS file as SigningKeyID.
+	// Without it tokens are signed with Secret (HS256).
+	SigningKeyFile string
+	SigningKeyID   string
+	AccessTokenTTL time.Duration
 }
 
 type LoggerConfig struct {
@@ -42,6 +96,72 @@ type LoggerConfig struct {
 	AddSource bool
 }
 
+type MailConfig struct {
+	// Driver is smtp, filesystem (writes .eml files to Dir) or memory
+	Driver string
+	From   string
+	Dir    string
+	SMTP   SMTPConfig
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	// StateTTL bounds how long users may take to log in with their
+	// identity provider
+	StateTTL time.Duration
+}
+
+// OIDCProviderConfig is an OpenID Connect provider users may log in with.
+// The name is used in the routes and, upper-cased, as the origin of the
+// users it creates.
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the client page the provider sends users back to; it
+	// posts the code and state query parameters to the callback route
+	RedirectURL string
+	Scopes      []string
+}
+
+type StorageConfig struct {
+	// Driver is local (files in Dir, served by the API) or s3
+	Driver string
+	Dir    string
+	// SigningKey signs the download URLs of the local driver. Empty uses a
+	// random key, so URLs stop working when the API restarts.
+	SigningKey string
+	// URLExpiry bounds how long signed download URLs work
+	URLExpiry time.Duration
+	// MaxUploadSize bounds uploaded files in bytes
+	MaxUploadSize int64
+	// AvatarMaxSize bounds uploaded avatars in bytes, which are stored
+	// scaled down to AvatarSize x AvatarSize pixels
+	AvatarMaxSize int64
+	AvatarSize    int
+	S3            S3Config
+}
+
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// UsePathStyle addresses the bucket in the path, as MinIO requires
+	UsePathStyle bool
+	// CreateBucket creates the bucket at startup unless it exists
+	CreateBucket bool
+}
+
 type OTELConfig struct {
 	CollectorEndpoint string
 	Environment       string
@@ -50,7 +170,9 @@ type OTELConfig struct {
 func LoadConfig() (*Config, error) {
 	_ = godotenv.Load()
 
-	config := &Config{}
+	config := &Config{
+		Environment: getEnv("ENVIRONMENT", "development"),
+	}
 
 	dbPort, err := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	if err != nil {
@@ -74,14 +196,103 @@ func LoadConfig() (*Config, error) {
 	allowedOrigins := strings.Split(getEnv("ALLOWED_ORIGINS", "*"), ",")
 	allowedMethods := strings.Split(getEnv("ALLOWED_METHODS", "GET,POST,PUT,DELETE,PATCH,OPTIONS"), ",")
 
+	maxBodySize, err := parseBytes(getEnv("HTTP_MAX_BODY_SIZE", "12M"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_MAX_BODY_SIZE: %w", err)
+	}
+
 	config.HTTP = HTTPConfig{
 		Port:           httpPort,
 		AllowedOrigins: allowedOrigins,
 		AllowedMethods: allowedMethods,
+		PublicURL:      strings.TrimSuffix(getEnv("HTTP_PUBLIC_URL", fmt.Sprintf("http://localhost:%d", httpPort)), "/"),
+		MaxBodySize:    maxBodySize,
+	}
+
+	config.I18N = I18NConfig{
+		DefaultLocale: getEnv("DEFAULT_LOCALE", "en"),
+		MessagesDir:   getEnv("I18N_MESSAGES_DIR", ""),
+	}
+
+	clockSkew, err := time.ParseDuration(getEnv("JWT_CLOCK_SKEW", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_CLOCK_SKEW: %w", err)
+	}
+
+	accessTokenTTL, err := time.ParseDuration(getEnv("JWT_ACCESS_TOKEN_TTL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_ACCESS_TOKEN_TTL: %w", err)
 	}
 
 	config.JWT = JWTConfig{
-		Secret: getEnv("JWT_SECRET", ""),
+		Secret:         getEnv("JWT_SECRET", ""),
+		JWKSFile:       getEnv("JWT_JWKS_FILE", ""),
+		Issuer:         getEnv("JWT_ISSUER", ""),
+		Audience:       getEnv("JWT_AUDIENCE", ""),
+		ClockSkew:      clockSkew,
+		Algorithms:     strings.Split(getEnv("JWT_ALGORITHMS", "HS256,RS256,EdDSA"), ","),
+		SigningKeyFile: getEnv("JWT_SIGNING_KEY_FILE", ""),
+		SigningKeyID:   getEnv("JWT_SIGNING_KEY_ID", ""),
+		AccessTokenTTL: accessTokenTTL,
+	}
+
+	refreshTokenTTL, err := time.ParseDuration(getEnv("AUTH_REFRESH_TOKEN_TTL", "720h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_REFRESH_TOKEN_TTL: %w", err)
+	}
+
+	maxFailedLogins, err := strconv.Atoi(getEnv("AUTH_MAX_FAILED_LOGINS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_MAX_FAILED_LOGINS: %w", err)
+	}
+
+	lockoutDuration, err := time.ParseDuration(getEnv("AUTH_LOCKOUT_DURATION", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_LOCKOUT_DURATION: %w", err)
+	}
+
+	verificationTokenTTL, err := time.ParseDuration(getEnv("AUTH_VERIFICATION_TOKEN_TTL", "48h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_VERIFICATION_TOKEN_TTL: %w", err)
+	}
+
+	passwordResetTokenTTL, err := time.ParseDuration(getEnv("AUTH_PASSWORD_RESET_TOKEN_TTL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_PASSWORD_RESET_TOKEN_TTL: %w", err)
+	}
+
+	permissionCacheTTL, err := time.ParseDuration(getEnv("AUTH_PERMISSION_CACHE_TTL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_PERMISSION_CACHE_TTL: %w", err)
+	}
+
+	config.Auth = AuthConfig{
+		RefreshTokenTTL:       refreshTokenTTL,
+		MaxFailedLogins:       maxFailedLogins,
+		LockoutDuration:       lockoutDuration,
+		VerifyEmailURL:        getEnv("AUTH_VERIFY_EMAIL_URL", "http://localhost:3000/verify-email"),
+		ResetPasswordURL:      getEnv("AUTH_RESET_PASSWORD_URL", "http://localhost:3000/reset-password"),
+		VerificationTokenTTL:  verificationTokenTTL,
+		PasswordResetTokenTTL: passwordResetTokenTTL,
+		PermissionCacheTTL:    permissionCacheTTL,
+		SyncPermissions:       getEnv("AUTH_SYNC_PERMISSIONS", "false") == "true",
+	}
+
+	smtpPort, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
+	}
+
+	config.Mail = MailConfig{
+		Driver: getEnv("MAIL_DRIVER", "filesystem"),
+		From:   getEnv("MAIL_FROM", "no-reply@localhost"),
+		Dir:    getEnv("MAIL_DIR", "tmp/mail"),
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     smtpPort,
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+		},
 	}
 
 	config.Logger = LoggerConfig{
@@ -90,18 +301,140 @@ func LoadConfig() (*Config, error) {
 		AddSource: getEnv("LOG_ADD_SOURCE", "false") == "true",
 	}
 
+	oidcConfig, err := loadOIDCConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.OIDC = oidcConfig
+
+	storageConfig, err := loadStorageConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Storage = storageConfig
+
 	config.OTEL = OTELConfig{
 		CollectorEndpoint: getEnv("OTEL_COLLECTOR_ENDPOINT", "localhost:4318"),
-		Environment:       getEnv("ENVIRONMENT", "development"),
+		Environment:       config.Environment,
+	}
+
+	if config.JWT.Secret == "" && config.JWT.JWKSFile == "" {
+		return nil, fmt.Errorf("JWT_SECRET or JWT_JWKS_FILE is required")
+	}
+
+	return config, nil
+}
+
+// loadOIDCConfig reads the providers listed in OIDC_PROVIDERS, each from
+// the OIDC_<NAME>_* variables
+func loadOIDCConfig() (OIDCConfig, error) {
+	stateTTL, err := time.ParseDuration(getEnv("OIDC_STATE_TTL", "10m"))
+	if err != nil {
+		return OIDCConfig{}, fmt.Errorf("invalid OIDC_STATE_TTL: %w", err)
 	}
 
-	if config.JWT.Secret == "" {
-		return nil, fmt.Errorf("JWT_SECRET is required")
+	config := OIDCConfig{StateTTL: stateTTL}
+	for _, name := range strings.Split(getEnv("OIDC_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", ""),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return OIDCConfig{}, fmt.Errorf("%sISSUER, %sCLIENT_ID and %sREDIRECT_URL are required", prefix, prefix, prefix)
+		}
+		config.Providers = append(config.Providers, provider)
 	}
 
 	return config, nil
 }
 
+func loadStorageConfig() (StorageConfig, error) {
+	urlExpiry, err := time.ParseDuration(getEnv("STORAGE_URL_EXPIRY", "15m"))
+	if err != nil {
+		return StorageConfig{}, fmt.Errorf("invalid STORAGE_URL_EXPIRY: %w", err)
+	}
+
+	maxUploadSize, err := parseBytes(getEnv("STORAGE_MAX_UPLOAD_SIZE", "10M"))
+	if err != nil {
+		return StorageConfig{}, fmt.Errorf("invalid STORAGE_MAX_UPLOAD_SIZE: %w", err)
+	}
+
+	avatarMaxSize, err := parseBytes(getEnv("STORAGE_AVATAR_MAX_SIZE", "5M"))
+	if err != nil {
+		return StorageConfig{}, fmt.Errorf("invalid STORAGE_AVATAR_MAX_SIZE: %w", err)
+	}
+
+	avatarSize, err := strconv.Atoi(getEnv("STORAGE_AVATAR_SIZE", "256"))
+	if err != nil || avatarSize <= 0 {
+		return StorageConfig{}, fmt.Errorf("invalid STORAGE_AVATAR_SIZE: %q", getEnv("STORAGE_AVATAR_SIZE", "256"))
+	}
+
+	return StorageConfig{
+		Driver:        getEnv("STORAGE_DRIVER", "local"),
+		Dir:           getEnv("STORAGE_DIR", "tmp/storage"),
+		SigningKey:    getEnv("STORAGE_SIGNING_KEY", ""),
+		URLExpiry:     urlExpiry,
+		MaxUploadSize: maxUploadSize,
+		AvatarMaxSize: avatarMaxSize,
+		AvatarSize:    avatarSize,
+		S3: S3Config{
+			Endpoint:        getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			Region:          getEnv("S3_REGION", "us-east-1"),
+			Bucket:          getEnv("S3_BUCKET", ""),
+			AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			UsePathStyle:    getEnv("S3_USE_PATH_STYLE", "false") == "true",
+			CreateBucket:    getEnv("S3_CREATE_BUCKET", "false") == "true",
+		},
+	}, nil
+}
+
+// parseBytes parses a size in bytes with an optional K, M or G suffix
+// (powers of 1024), e.g. 512K or 10M
+func parseBytes(value string) (int64, error) {
+	if value == "" {
+		return 0, fmt.Errorf("size is empty")
+	}
+
+	multiplier := int64(1)
+	switch strings.ToUpper(value[len(value)-1:]) {
+	case "K":
+		multiplier = 1 << 10
+	case "M":
+		multiplier = 1 << 20
+	case "G":
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if size <= 0 {
+		return 0, fmt.Errorf("size must be positive")
+	}
+	return size * multiplier, nil
+}
+
+// IsDevelopment reports whether the service runs on a developer machine,
+// where error responses may include internals such as stack traces.
+func (c *Config) IsDevelopment() bool {
+	return c.Environment == "development" || c.Environment == "local"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
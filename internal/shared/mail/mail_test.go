@@ -0,0 +1,284 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+func TestTemplates_Render(t *testing.T) {
+	templates, err := NewTemplates(EmbeddedTemplates, i18n.English)
+	require.NoError(t, err)
+
+	data := map[string]any{"Name": "Ana", "URL": "https://app.example.com/verify?token=a&b", "Hours": 48, "Minutes": 60}
+
+	tests := []struct {
+		name     string
+		locale   i18n.Locale
+		template string
+		subject  string
+		text     string
+	}{
+		{"english verification", i18n.English, "verify_email", "Verify your email address", "expires in 48 hours"},
+		{"spanish verification", i18n.Spanish, "verify_email", "Verifica tu correo electrónico", "vence en 48 horas"},
+		{"english reset", i18n.English, "reset_password", "Reset your password", "expires in 60 minutes"},
+		{"spanish reset", i18n.Spanish, "reset_password", "Restablece tu contraseña", "vence en 60 minutos"},
+		{"unsupported locale falls back", i18n.Locale("fr"), "verify_email", "Verify your email address", "Hi Ana"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := templates.Render(tt.locale, tt.template, data)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.subject, msg.Subject)
+			assert.Contains(t, msg.Text, tt.text)
+			assert.Contains(t, msg.Text, "https://app.example.com/verify?token=a&b")
+			assert.NotContains(t, msg.Text, "subject")
+			assert.Contains(t, msg.HTML, `href="https://app.example.com/verify?token=a&amp;b"`)
+		})
+	}
+
+	_, err = templates.Render(i18n.English, "missing", data)
+	assert.Error(t, err)
+}
+
+func TestTemplates_RenderInvitation(t *testing.T) {
+	templates, err := NewTemplates(EmbeddedTemplates, i18n.English)
+	require.NoError(t, err)
+
+	data := map[string]any{"Name": "Ana", "Organization": "Acme & Co", "Relationship": "EMPLOYEE"}
+
+	tests := []struct {
+		name    string
+		locale  i18n.Locale
+		subject string
+		text    string
+	}{
+		{"english", i18n.English, "You have been added to Acme & Co", "added to Acme & Co as EMPLOYEE"},
+		{"spanish", i18n.Spanish, "Te agregaron a Acme & Co", "agregaron a Acme & Co como EMPLOYEE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := templates.Render(tt.locale, "organization_invitation", data)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.subject, msg.Subject)
+			assert.Contains(t, msg.Text, tt.text)
+			assert.Contains(t, msg.HTML, "<strong>Acme &amp; Co</strong>")
+		})
+	}
+}
+
+func TestNewTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr bool
+	}{
+		{
+			name: "text only",
+			fsys: fstest.MapFS{"en/welcome.txt": {Data: []byte(`{{define "subject"}}Hi{{end}}Welcome`)}},
+		},
+		{
+			name:    "missing subject",
+			fsys:    fstest.MapFS{"en/welcome.txt": {Data: []byte(`Welcome`)}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid syntax",
+			fsys:    fstest.MapFS{"en/welcome.txt": {Data: []byte(`{{define "subject"}}Hi{{end}}{{.Name`)}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTemplates(tt.fsys, i18n.English)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMessage_Bytes(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  ports.Email
+	}{
+		{"plain text", ports.Email{From: "App <no-reply@example.com>", To: []string{"ana@example.com"}, Subject: "Contraseña", Text: "Hola Ana\n"}},
+		{"with html", ports.Email{From: "no-reply@example.com", To: []string{"ana@example.com", "bob@example.com"}, Subject: "Hi", Text: "Hi Ana\n", HTML: "<p>Hi Ana</p>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := Encode(tt.msg, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+			require.NoError(t, err)
+
+			parsed, err := netmail.ReadMessage(strings.NewReader(string(raw)))
+			require.NoError(t, err)
+
+			subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.msg.Subject, subject)
+			assert.Equal(t, strings.Join(tt.msg.To, ", "), parsed.Header.Get("To"))
+			assert.Contains(t, parsed.Header.Get("Message-ID"), "@example.com>")
+
+			mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+			require.NoError(t, err)
+
+			if tt.msg.HTML == "" {
+				assert.Equal(t, "text/plain", mediaType)
+				body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+				require.NoError(t, err)
+				assert.Equal(t, tt.msg.Text, strings.ReplaceAll(string(body), "\r\n", "\n"))
+				return
+			}
+
+			assert.Equal(t, "multipart/alternative", mediaType)
+			reader := multipart.NewReader(parsed.Body, params["boundary"])
+			var bodies []string
+			for {
+				part, err := reader.NextPart()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				body, err := io.ReadAll(part)
+				require.NoError(t, err)
+				bodies = append(bodies, strings.ReplaceAll(string(body), "\r\n", "\n"))
+			}
+			assert.Equal(t, []string{tt.msg.Text, tt.msg.HTML}, bodies)
+		})
+	}
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := NewFileMailer(dir, "no-reply@example.com")
+	require.NoError(t, err)
+
+	for _, subject := range []string{"first", "second"} {
+		require.NoError(t, mailer.Send(context.Background(), ports.Email{To: []string{"ana@example.com"}, Subject: subject, Text: "Hi"}))
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	content, err := os.ReadFile(filepath.Join(dir, entries[1].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "From: no-reply@example.com")
+	assert.Contains(t, string(content), "Subject: second")
+}
+
+func TestMemoryMailer_Send(t *testing.T) {
+	mailer := NewMemoryMailer()
+	require.NoError(t, mailer.Send(context.Background(), ports.Email{Subject: "first"}))
+	require.NoError(t, mailer.Send(context.Background(), ports.Email{Subject: "second"}))
+
+	messages := mailer.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "first", messages[0].Subject)
+	assert.Equal(t, "second", messages[1].Subject)
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go serveSMTP(t, listener, received)
+
+	addr := listener.Addr().(*net.TCPAddr)
+	mailer := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "App <no-reply@example.com>"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = mailer.Send(ctx, ports.Email{To: []string{"Ana <ana@example.com>"}, Subject: "Hi", Text: "Hi Ana"})
+	require.NoError(t, err)
+
+	commands := <-received
+	assert.Contains(t, commands, "MAIL FROM:<no-reply@example.com>")
+	assert.Contains(t, commands, "RCPT TO:<ana@example.com>")
+	assert.Contains(t, commands, "Subject: Hi")
+}
+
+func TestSMTPMailer_SendUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	mailer := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: port, From: "no-reply@example.com"})
+	err = mailer.Send(context.Background(), ports.Email{To: []string{"ana@example.com"}, Subject: "Hi", Text: "Hi"})
+	assert.Error(t, err)
+}
+
+// serveSMTP accepts one connection and answers a minimal SMTP dialog,
+// sending every line received on received when the client quits.
+func serveSMTP(t *testing.T, listener net.Listener, received chan<- []string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var lines []string
+	r := bufio.NewReader(conn)
+	reply := func(code int, text string) { _, _ = conn.Write([]byte(strconv.Itoa(code) + " " + text + "\r\n")) }
+
+	reply(220, "localhost ESMTP")
+	inData := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Errorf("smtp server: %v", err)
+			received <- lines
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+
+		switch {
+		case inData && line == ".":
+			inData = false
+			reply(250, "OK")
+		case inData:
+		case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+			reply(250, "localhost")
+		case line == "DATA":
+			inData = true
+			reply(354, "End data with <CR><LF>.<CR><LF>")
+		case line == "QUIT":
+			reply(221, "Bye")
+			received <- lines
+			return
+		default:
+			reply(250, "OK")
+		}
+	}
+}
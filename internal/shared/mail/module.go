@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"go.uber.org/fx"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+	"api.system.soluciones-cloud.com/internal/shared/localconfig"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+var Module = fx.Module("mail",
+	fx.Provide(
+		NewMailerFromConfig,
+		fx.Annotate(
+			NewTemplatesFromConfig,
+			fx.As(new(ports.EmailTemplates)),
+		),
+	),
+)
+
+// NewMailerFromConfig returns the mailer of config.Mail.Driver.
+func NewMailerFromConfig(config *localconfig.Config) (ports.Mailer, error) {
+	switch config.Mail.Driver {
+	case "smtp":
+		return NewSMTPMailer(SMTPConfig{
+			Host:     config.Mail.SMTP.Host,
+			Port:     config.Mail.SMTP.Port,
+			Username: config.Mail.SMTP.Username,
+			Password: config.Mail.SMTP.Password,
+			From:     config.Mail.From,
+		}), nil
+	case "filesystem":
+		return NewFileMailer(config.Mail.Dir, config.Mail.From)
+	case "memory":
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fault.New("unknown MAIL_DRIVER, expected smtp, filesystem or memory").With("driver", config.Mail.Driver)
+	}
+}
+
+func NewTemplatesFromConfig(config *localconfig.Config) (*Templates, error) {
+	return NewTemplates(EmbeddedTemplates, i18n.Normalize(config.I18N.DefaultLocale))
+}
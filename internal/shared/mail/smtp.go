@@ -0,0 +1,106 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From is the sender of messages that do not set one
+	From string
+}
+
+// SMTPMailer delivers messages to an SMTP server. The connection is
+// upgraded with STARTTLS when the server supports it, and PLAIN auth is
+// used when a username is set.
+type SMTPMailer struct {
+	config SMTPConfig
+	now    func() time.Time
+}
+
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config, now: time.Now}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg ports.Email) error {
+	if msg.From == "" {
+		msg.From = m.config.From
+	}
+
+	body, err := Encode(msg, m.now())
+	if err != nil {
+		return fault.Wrap(err).Message("failed to encode email")
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fault.Wrap(err).Code(fault.ServiceUnavailable).Message("failed to connect to SMTP server").With("addr", addr)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fault.Wrap(err).Code(fault.ServiceUnavailable).Message("failed to connect to SMTP server").With("addr", addr)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.config.Host}); err != nil {
+			return fault.Wrap(err).Message("failed to start TLS")
+		}
+	}
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fault.Wrap(err).Message("failed to authenticate to SMTP server")
+		}
+	}
+
+	if err := client.Mail(address(msg.From)); err != nil {
+		return fault.Wrap(err).Message("SMTP server rejected the sender")
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(address(to)); err != nil {
+			return fault.Wrap(err).Message("SMTP server rejected a recipient")
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fault.Wrap(err).Message("failed to send email")
+	}
+	if _, err := w.Write(body); err != nil {
+		return fault.Wrap(err).Message("failed to send email")
+	}
+	if err := w.Close(); err != nil {
+		return fault.Wrap(err).Message("failed to send email")
+	}
+
+	return client.Quit()
+}
+
+// address returns the bare address of an RFC 5322 address such as
+// "Support <support@example.com>".
+func address(s string) string {
+	if parsed, err := netmail.ParseAddress(s); err == nil {
+		return parsed.Address
+	}
+	return s
+}
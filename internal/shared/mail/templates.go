@@ -0,0 +1,106 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+
+	"api.system.soluciones-cloud.com/internal/shared/fault"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+	"api.system.soluciones-cloud.com/internal/shared/ports"
+)
+
+//go:embed templates
+var embedded embed.FS
+
+// EmbeddedTemplates holds the built-in templates, one directory per locale.
+var EmbeddedTemplates, _ = fs.Sub(embedded, "templates")
+
+// Templates renders localized emails. Every email is a <locale>/<name>.txt
+// text template that defines a "subject" block besides the plain text
+// body, and an optional <locale>/<name>.html template with the HTML body.
+type Templates struct {
+	fallback i18n.Locale
+	text     map[string]*texttemplate.Template
+	html     map[string]*htmltemplate.Template
+}
+
+func NewTemplates(fsys fs.FS, fallback i18n.Locale) (*Templates, error) {
+	t := &Templates{
+		fallback: fallback,
+		text:     map[string]*texttemplate.Template{},
+		html:     map[string]*htmltemplate.Template{},
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		key := strings.TrimSuffix(name, path.Ext(name))
+		switch path.Ext(name) {
+		case ".txt":
+			tmpl, err := texttemplate.ParseFS(fsys, name)
+			if err != nil {
+				return fault.Wrap(err).Message("failed to parse email template").With("template", name)
+			}
+			if tmpl.Lookup("subject") == nil {
+				return fault.New("email template does not define a subject").With("template", name)
+			}
+			t.text[key] = tmpl
+		case ".html":
+			tmpl, err := htmltemplate.ParseFS(fsys, name)
+			if err != nil {
+				return fault.Wrap(err).Message("failed to parse email template").With("template", name)
+			}
+			t.html[key] = tmpl
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Render renders the email name in locale, or in the fallback locale when
+// locale has no such template. The recipients of the returned email are
+// left to the caller.
+func (t *Templates) Render(locale i18n.Locale, name string, data any) (ports.Email, error) {
+	key := string(locale) + "/" + name
+	text, ok := t.text[key]
+	if !ok {
+		key = string(t.fallback) + "/" + name
+		if text, ok = t.text[key]; !ok {
+			return ports.Email{}, fault.New("email template not found").With("template", name).With("locale", locale)
+		}
+	}
+
+	var subject, body bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return ports.Email{}, fault.Wrap(err).Message("failed to render email subject").With("template", key)
+	}
+	if err := text.Execute(&body, data); err != nil {
+		return ports.Email{}, fault.Wrap(err).Message("failed to render email").With("template", key)
+	}
+
+	msg := ports.Email{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(body.String()) + "\n",
+	}
+
+	if html, ok := t.html[key]; ok {
+		var buf bytes.Buffer
+		if err := html.Execute(&buf, data); err != nil {
+			return ports.Email{}, fault.Wrap(err).Message("failed to render email").With("template", key+".html")
+		}
+		msg.HTML = buf.String()
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,112 @@
+package openapi
+
+// Version is the OpenAPI version produced by the registry.
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI           string              `json:"openapi"`
+	Info              Info                `json:"info"`
+	JSONSchemaDialect string              `json:"jsonSchemaDialect,omitempty"`
+	Servers           []Server            `json:"servers,omitempty"`
+	Paths             map[string]PathItem `json:"paths"`
+	Components        Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Version     string   `json:"version"`
+	Contact     *Contact `json:"contact,omitempty"`
+	License     *License `json:"license,omitempty"`
+}
+
+type Contact struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type License struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lowercase HTTP methods to operations.
+type PathItem map[string]*OperationObject
+
+type OperationObject struct {
+	OperationID string                     `json:"operationId,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []ParameterObject          `json:"parameters,omitempty"`
+	RequestBody *RequestBodyObject         `json:"requestBody,omitempty"`
+	Responses   map[string]*ResponseObject `json:"responses"`
+	Security    []SecurityRequirement      `json:"security,omitempty"`
+	Permission  string                     `json:"x-permission,omitempty"`
+}
+
+// SecurityRequirement maps security scheme names to required scopes.
+type SecurityRequirement map[string][]string
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// BearerJWT documents JWT bearer authentication.
+func BearerJWT(description string) SecurityScheme {
+	return SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: description}
+}
+
+// APIKeyHeader documents authentication with a key sent in the header.
+func APIKeyHeader(header, description string) SecurityScheme {
+	return SecurityScheme{Type: "apiKey", In: "header", Name: header, Description: description}
+}
+
+type ParameterObject struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      map[string]any `json:"schema"`
+}
+
+type RequestBodyObject struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type ResponseObject struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema   map[string]any      `json:"schema"`
+	Encoding map[string]Encoding `json:"encoding,omitempty"`
+}
+
+type Encoding struct {
+	ContentType string `json:"contentType,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]map[string]any  `json:"schemas,omitempty"`
+	Responses       map[string]*ResponseObject `json:"responses,omitempty"`
+	SecuritySchemes map[string]SecurityScheme  `json:"securitySchemes,omitempty"`
+}
+
+// Ref returns a JSON reference to a component schema.
+func Ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
@@ -0,0 +1,463 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+type testAuthor struct {
+	ID   uuid.UUID   `json:"id"`
+	Name null.String `json:"name"`
+}
+
+type testArticle struct {
+	ID        uuid.UUID     `json:"id"`
+	Title     string        `json:"title"`
+	Tags      []string      `json:"tags,omitempty"`
+	Author    *testAuthor   `json:"author"`
+	Related   []testArticle `json:"related"`
+	CreatedAt time.Time     `json:"created_at"`
+	internal  string
+	Ignored   string `json:"-"`
+}
+
+type testCreateArticle struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+	Price string    `json:"price"`
+}
+
+func (r testCreateArticle) Schema() valid.Schema {
+	return valid.Object(map[string]valid.Schema{
+		"id":    valid.String().UUID().Required(),
+		"title": valid.String().MinLength(3).Required(),
+		"price": valid.Decimal().Numeric(10, 2),
+	})
+}
+
+type testPage[T any] struct {
+	Items []T `json:"items"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected map[string]any
+	}{
+		{
+			name:     "string",
+			value:    "",
+			expected: map[string]any{"type": "string"},
+		},
+		{
+			name:     "pointer is nullable",
+			value:    new(int),
+			expected: map[string]any{"type": []string{"integer", "null"}},
+		},
+		{
+			name:     "null string",
+			value:    null.String{},
+			expected: map[string]any{"type": []string{"string", "null"}},
+		},
+		{
+			name:     "time",
+			value:    time.Time{},
+			expected: map[string]any{"type": "string", "format": "date-time"},
+		},
+		{
+			name:     "slice of structs",
+			value:    []testAuthor{},
+			expected: map[string]any{"type": "array", "items": Ref("testAuthor")},
+		},
+		{
+			name:     "map",
+			value:    map[string]bool{},
+			expected: map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "boolean"}},
+		},
+		{
+			name: "anonymous struct is inlined",
+			value: struct {
+				Count int64 `json:"count"`
+			}{},
+			expected: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"count": map[string]any{"type": "integer"}},
+				"required":   []string{"count"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(Info{})
+			got := registry.schemaOf(reflect.TypeOf(tt.value))
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSchemaOf_Struct(t *testing.T) {
+	registry := NewRegistry(Info{})
+	ref := registry.schemaOf(reflect.TypeOf(testArticle{}))
+
+	if !reflect.DeepEqual(ref, Ref("testArticle")) {
+		t.Fatalf("Expected reference to testArticle, got %v", ref)
+	}
+
+	schema := registry.schemas["testArticle"]
+	properties := schema["properties"].(map[string]any)
+
+	for _, name := range []string{"internal", "Ignored"} {
+		if _, ok := properties[name]; ok {
+			t.Errorf("Expected %s to be skipped", name)
+		}
+	}
+
+	wantRequired := []string{"id", "title", "created_at"}
+	if !reflect.DeepEqual(schema["required"], wantRequired) {
+		t.Errorf("Expected required %v, got %v", wantRequired, schema["required"])
+	}
+
+	wantAuthor := map[string]any{"oneOf": []any{Ref("testAuthor"), map[string]any{"type": "null"}}}
+	if !reflect.DeepEqual(properties["author"], wantAuthor) {
+		t.Errorf("Expected nullable author reference, got %v", properties["author"])
+	}
+
+	if _, ok := registry.schemas["testAuthor"]; !ok {
+		t.Error("Expected testAuthor to be registered")
+	}
+}
+
+func TestComponentName(t *testing.T) {
+	if got := componentName(reflect.TypeOf(testPage[testAuthor]{})); got != "testPagetestAuthor" {
+		t.Errorf("Expected testPagetestAuthor, got %s", got)
+	}
+	if got := componentName(reflect.TypeOf(testPage[[]*testAuthor]{})); got != "testPagetestAuthor" {
+		t.Errorf("Expected testPagetestAuthor, got %s", got)
+	}
+	if got := componentName(reflect.TypeOf(testPage[testPage[testAuthor]]{})); got != "testPagetestPagetestAuthor" {
+		t.Errorf("Expected testPagetestPagetestAuthor, got %s", got)
+	}
+	if got := componentName(reflect.TypeOf(struct{}{})); got != "" {
+		t.Errorf("Expected empty name for anonymous struct, got %s", got)
+	}
+}
+
+func TestRegistry_Add(t *testing.T) {
+	registry := NewRegistry(Info{Title: "Test", Version: "1.0.0"})
+	registry.Add(http.MethodPut, "/articles/:id", Operation{
+		ID:       "updateArticle",
+		Request:  testCreateArticle{},
+		Response: testArticle{},
+	})
+	registry.Add(http.MethodGet, "/articles/:id/comments/:comment_id", Operation{
+		ID:         "getComment",
+		Parameters: []Parameter{PathParam("id", "Article ID", valid.String().UUID())},
+	})
+
+	document := registry.Document()
+
+	t.Run("converts path templates", func(t *testing.T) {
+		for _, path := range []string{"/articles/{id}", "/articles/{id}/comments/{comment_id}"} {
+			if _, ok := document.Paths[path]; !ok {
+				t.Errorf("Expected path %s, got %v", path, document.Paths)
+			}
+		}
+	})
+
+	t.Run("documents undeclared path params", func(t *testing.T) {
+		params := document.Paths["/articles/{id}/comments/{comment_id}"]["get"].Parameters
+		if len(params) != 2 || params[1].Name != "comment_id" || !params[1].Required {
+			t.Errorf("Expected id and comment_id parameters, got %+v", params)
+		}
+	})
+
+	t.Run("merges valid constraints into request body", func(t *testing.T) {
+		body := document.Components.Schemas["testCreateArticle"]
+		properties := body["properties"].(map[string]any)
+
+		if _, ok := properties["id"]; ok {
+			t.Error("Expected path parameter id to be removed from the body")
+		}
+		if !reflect.DeepEqual(body["required"], []string{"title"}) {
+			t.Errorf("Expected required [title], got %v", body["required"])
+		}
+
+		title := properties["title"].(map[string]any)
+		if title["minLength"] != 3 || title["type"] != "string" {
+			t.Errorf("Expected title with minLength 3, got %v", title)
+		}
+
+		price := properties["price"].(map[string]any)
+		if price["type"] != "string" || price["pattern"] == nil {
+			t.Errorf("Expected price to keep its Go type and decimal pattern, got %v", price)
+		}
+	})
+
+	t.Run("adds default error responses", func(t *testing.T) {
+		responses := document.Paths["/articles/{id}"]["put"].Responses
+		for _, status := range []string{"200", "400", "404", "422", "500"} {
+			if _, ok := responses[status]; !ok {
+				t.Errorf("Expected %s response, got %v", status, responses)
+			}
+		}
+		if _, ok := document.Components.Responses["UnprocessableEntity"]; !ok {
+			t.Error("Expected UnprocessableEntity component response")
+		}
+		if _, ok := document.Components.Schemas[ProblemSchema]; !ok {
+			t.Error("Expected Problem schema")
+		}
+	})
+
+	t.Run("defaults to no content without response", func(t *testing.T) {
+		registry := NewRegistry(Info{})
+		registry.Add(http.MethodDelete, "/articles/:id", Operation{})
+		responses := registry.Document().Paths["/articles/{id}"]["delete"].Responses
+		if _, ok := responses["204"]; !ok {
+			t.Errorf("Expected 204 response, got %v", responses)
+		}
+	})
+}
+
+func TestRegistry_AddFiles(t *testing.T) {
+	registry := NewRegistry(Info{})
+	registry.Add(http.MethodPut, "/me/avatar", Operation{
+		Files:    []FileField{{Name: "file", Description: "Image", ContentTypes: []string{"image/png", "image/jpeg"}}},
+		Response: testArticle{},
+	})
+
+	operation := registry.Document().Paths["/me/avatar"]["put"]
+	if operation.RequestBody == nil {
+		t.Fatal("Expected a request body")
+	}
+
+	media, ok := operation.RequestBody.Content["multipart/form-data"]
+	if !ok {
+		t.Fatalf("Expected a multipart/form-data body, got %v", operation.RequestBody.Content)
+	}
+	file := media.Schema["properties"].(map[string]any)["file"].(map[string]any)
+	if file["type"] != "string" || file["format"] != "binary" {
+		t.Errorf("Expected a binary file field, got %v", file)
+	}
+	if !reflect.DeepEqual(media.Schema["required"], []string{"file"}) {
+		t.Errorf("Expected the file to be required, got %v", media.Schema["required"])
+	}
+	if media.Encoding["file"].ContentType != "image/png, image/jpeg" {
+		t.Errorf("Expected the accepted content types, got %v", media.Encoding)
+	}
+
+	for _, status := range []string{"200", "413", "415", "422"} {
+		if _, ok := operation.Responses[status]; !ok {
+			t.Errorf("Expected %s response, got %v", status, operation.Responses)
+		}
+	}
+}
+
+func TestRegistry_AddDownload(t *testing.T) {
+	registry := NewRegistry(Info{})
+	registry.Add(http.MethodGet, "/articles/export", Operation{Download: "text/csv"})
+
+	operation := registry.Document().Paths["/articles/export"]["get"]
+	success, ok := operation.Responses["200"]
+	if !ok {
+		t.Fatalf("Expected 200 response, got %v", operation.Responses)
+	}
+
+	media, ok := success.Content["text/csv"]
+	if !ok {
+		t.Fatalf("Expected a text/csv body, got %v", success.Content)
+	}
+	if media.Schema["type"] != "string" || media.Schema["format"] != "binary" {
+		t.Errorf("Expected a binary file, got %v", media.Schema)
+	}
+}
+
+func TestRegistry_Secured(t *testing.T) {
+	registry := NewRegistry(Info{})
+	secured := registry.Secured("bearerAuth", BearerJWT("Access token"))
+
+	registry.Add(http.MethodPost, "/auth/login", Operation{Response: testArticle{}})
+	secured.Add(http.MethodGet, "/articles", Operation{Response: []testArticle{}, Authenticated: true})
+
+	document := registry.Document()
+
+	public := document.Paths["/auth/login"]["post"]
+	if len(public.Security) != 0 {
+		t.Errorf("Expected no security on public operations, got %v", public.Security)
+	}
+	if _, ok := public.Responses["401"]; ok {
+		t.Error("Expected no 401 response on public operations")
+	}
+
+	private := document.Paths["/articles"]["get"]
+	if !reflect.DeepEqual(private.Security, []SecurityRequirement{{"bearerAuth": {}}}) {
+		t.Errorf("Expected bearerAuth requirement, got %v", private.Security)
+	}
+	if _, ok := private.Responses["401"]; !ok {
+		t.Errorf("Expected 401 response on secured operations, got %v", private.Responses)
+	}
+
+	scheme := document.Components.SecuritySchemes["bearerAuth"]
+	if scheme.Type != "http" || scheme.Scheme != "bearer" || scheme.BearerFormat != "JWT" {
+		t.Errorf("Unexpected security scheme %+v", scheme)
+	}
+}
+
+func TestRegistry_Secured_Alternatives(t *testing.T) {
+	registry := NewRegistry(Info{})
+	secured := registry.Secured("bearerAuth", BearerJWT("Access token")).
+		Secured("apiKeyAuth", APIKeyHeader("Authorization", "API key"))
+
+	secured.Add(http.MethodGet, "/articles", Operation{Response: []testArticle{}, Authenticated: true})
+
+	document := registry.Document()
+
+	want := []SecurityRequirement{{"bearerAuth": {}}, {"apiKeyAuth": {}}}
+	if got := document.Paths["/articles"]["get"].Security; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected either requirement, got %v", got)
+	}
+
+	scheme := document.Components.SecuritySchemes["apiKeyAuth"]
+	if scheme.Type != "apiKey" || scheme.In != "header" || scheme.Name != "Authorization" {
+		t.Errorf("Unexpected security scheme %+v", scheme)
+	}
+}
+
+func TestRegistry_WithParameters(t *testing.T) {
+	registry := NewRegistry(Info{})
+	tenant := registry.WithParameters(HeaderParam("X-Tenant", "Tenant", valid.String()))
+
+	tenant.Add(http.MethodGet, "/api/v1/articles/:id", Operation{Response: testArticle{}})
+	registry.Add(http.MethodGet, "/api/v1/health", Operation{})
+
+	params := registry.Document().Paths["/api/v1/articles/{id}"]["get"].Parameters
+	if len(params) != 2 || params[0].Name != "X-Tenant" || params[0].In != "header" || params[0].Required || params[1].Name != "id" {
+		t.Errorf("Expected the header and the path parameter, got %+v", params)
+	}
+
+	if params := registry.Document().Paths["/api/v1/health"]["get"].Parameters; len(params) != 0 {
+		t.Errorf("Expected no parameters outside the view, got %+v", params)
+	}
+}
+
+func TestRegistry_Permission(t *testing.T) {
+	registry := NewRegistry(Info{})
+	secured := registry.Secured("bearerAuth", BearerJWT("Access token"))
+
+	secured.Add(http.MethodGet, "/api/v1/articles/:id", Operation{Response: testArticle{}, Permission: "articles.read"})
+	secured.Add(http.MethodPost, "/api/v1/articles", Operation{Request: testCreateArticle{}, Authenticated: true})
+	registry.Add(http.MethodGet, "/api/v1/health", Operation{})
+
+	if got, ok := registry.Permission(http.MethodGet, "/api/v1/articles/:id"); !ok || got != "articles.read" {
+		t.Errorf("Expected articles.read, got %q", got)
+	}
+	if got, ok := registry.Permission(http.MethodPost, "/api/v1/articles"); !ok || got != "" {
+		t.Errorf("Expected an authenticated route without permission, got %q, %v", got, ok)
+	}
+	if _, ok := registry.Permission(http.MethodGet, "/api/v1/health"); ok {
+		t.Error("Expected public routes to declare nothing")
+	}
+
+	operation := registry.Document().Paths["/api/v1/articles/{id}"]["get"]
+	if operation.Permission != "articles.read" {
+		t.Errorf("Expected x-permission articles.read, got %q", operation.Permission)
+	}
+	if _, ok := operation.Responses["403"]; !ok {
+		t.Errorf("Expected 403 response on operations with a permission, got %v", operation.Responses)
+	}
+
+	content, err := json.Marshal(operation)
+	if err != nil {
+		t.Fatalf("Failed to marshal operation: %v", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(content, &raw); err != nil {
+		t.Fatalf("Failed to unmarshal operation: %v", err)
+	}
+	if raw["x-permission"] != "articles.read" {
+		t.Errorf("Expected x-permission in JSON, got %v", raw)
+	}
+}
+
+func TestRegistry_Actions(t *testing.T) {
+	registry := NewRegistry(Info{})
+	secured := registry.Secured("bearerAuth", BearerJWT("Access token"))
+
+	secured.Add(http.MethodPost, "/api/v1/articles/:id/tags", Operation{Summary: "Tag an article", Permission: "articles.tag"})
+	secured.Add(http.MethodDelete, "/api/v1/articles/:id/tags/:tag", Operation{Summary: "Untag an article", Permission: "articles.tag"})
+	secured.Add(http.MethodGet, "/api/v1/articles", Operation{Summary: "List articles", Permission: "articles.read"})
+	secured.Add(http.MethodGet, "/api/v1/articles/:id", Operation{Summary: "Get an article", Permission: "articles.read", PublicPermission: true})
+	secured.Add(http.MethodGet, "/api/v1/health", Operation{Summary: "Health", Authenticated: true})
+
+	expected := []Action{
+		{Module: "articles", Action: "read", Type: http.MethodGet, Public: true, Summary: "List articles"},
+		{Module: "articles", Action: "tag", Type: http.MethodPost, Summary: "Tag an article"},
+	}
+	if got := registry.Actions(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected actions %+v, got %+v", expected, got)
+	}
+	if code := expected[0].Code(); code != "articles.read" {
+		t.Errorf("Expected code articles.read, got %q", code)
+	}
+}
+
+func TestRegistry_AddInvalidPermission(t *testing.T) {
+	tests := []string{"articles", ".read", "articles.", "articles.read.all"}
+
+	for _, permission := range tests {
+		t.Run(permission, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected a panic for permission %q", permission)
+				}
+			}()
+			NewRegistry(Info{}).Add(http.MethodGet, "/api/v1/articles", Operation{Permission: permission})
+		})
+	}
+}
+
+func TestRegistry_AddSecuredWithoutPermission(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a secured route declaring neither a permission nor Authenticated")
+		}
+	}()
+	NewRegistry(Info{}).Secured("bearerAuth", BearerJWT("Access token")).Add(http.MethodGet, "/api/v1/articles", Operation{})
+}
+
+func TestRegistry_JSONIsStable(t *testing.T) {
+	build := func() []byte {
+		registry := NewRegistry(Info{Title: "Test", Version: "1.0.0"})
+		registry.Add(http.MethodPost, "/articles", Operation{Request: testCreateArticle{}, Response: testArticle{}})
+		registry.Add(http.MethodGet, "/articles", Operation{Response: []testArticle{}})
+		content, err := registry.JSON()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return content
+	}
+
+	first := build()
+	if string(first) != string(build()) {
+		t.Error("Expected identical output across builds")
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(first, &document); err != nil {
+		t.Fatalf("Expected valid JSON: %v", err)
+	}
+	if document["openapi"] != Version || document["jsonSchemaDialect"] != valid.JSONSchemaDialect {
+		t.Errorf("Unexpected document header: %v %v", document["openapi"], document["jsonSchemaDialect"])
+	}
+}
@@ -0,0 +1,190 @@
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	uuidType     = reflect.TypeOf(uuid.UUID{})
+	nullUUIDType = reflect.TypeOf(uuid.NullUUID{})
+	nullString   = reflect.TypeOf(null.String{})
+	nullInt      = reflect.TypeOf(null.Int{})
+	nullFloat    = reflect.TypeOf(null.Float{})
+	nullBool     = reflect.TypeOf(null.Bool{})
+	nullTime     = reflect.TypeOf(null.Time{})
+)
+
+// packagePathRegex matches package qualifiers such as "example.com/pkg.".
+var packagePathRegex = regexp.MustCompile(`[^\[\],*]*\.`)
+
+// schemaOf describes a Go type the way encoding/json serializes it. Named
+// structs are registered as components and referenced.
+func (r *Registry) schemaOf(t reflect.Type) map[string]any {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case uuidType:
+		return map[string]any{"type": "string", "format": "uuid"}
+	case nullUUIDType:
+		return nullable(map[string]any{"type": "string", "format": "uuid"})
+	case nullString:
+		return nullable(map[string]any{"type": "string"})
+	case nullInt:
+		return nullable(map[string]any{"type": "integer"})
+	case nullFloat:
+		return nullable(map[string]any{"type": "number"})
+	case nullBool:
+		return nullable(map[string]any{"type": "boolean"})
+	case nullTime:
+		return nullable(map[string]any{"type": "string", "format": "date-time"})
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(r.schemaOf(t.Elem()))
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": r.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": r.schemaOf(t.Elem())}
+	case reflect.Struct:
+		name := componentName(t)
+		if name == "" {
+			return r.structSchema(t)
+		}
+		if _, ok := r.schemas[name]; !ok {
+			// Reserve the name first so recursive types terminate
+			r.schemas[name] = map[string]any{}
+			r.schemas[name] = r.structSchema(t)
+		}
+		return Ref(name)
+	default:
+		return map[string]any{}
+	}
+}
+
+func (r *Registry) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, skip := jsonField(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && name == "" && indirect(field.Type).Kind() == reflect.Struct {
+			embedded := r.structSchema(indirect(field.Type))
+			for key, value := range embedded["properties"].(map[string]any) {
+				properties[key] = value
+			}
+			if embeddedRequired, ok := embedded["required"].([]string); ok {
+				required = append(required, embeddedRequired...)
+			}
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = r.schemaOf(field.Type)
+		if !omitEmpty && isAlwaysPresent(field.Type) {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func jsonField(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "omitempty" || option == "omitzero" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, false
+}
+
+// isAlwaysPresent reports whether a field can never be serialized as null.
+func isAlwaysPresent(t reflect.Type) bool {
+	switch t {
+	case nullUUIDType, nullString, nullInt, nullFloat, nullBool, nullTime:
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return false
+	}
+	return true
+}
+
+func nullable(schema map[string]any) map[string]any {
+	if _, isRef := schema["$ref"]; isRef {
+		return map[string]any{"oneOf": []any{schema, map[string]any{"type": "null"}}}
+	}
+
+	switch typ := schema["type"].(type) {
+	case string:
+		schema["type"] = []string{typ, "null"}
+	case []string:
+		schema["type"] = append(typ, "null")
+	}
+	return schema
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// componentName drops package paths from generic type arguments, so
+// Response[types.List[api.system.soluciones-cloud.com/internal/...entity.User]]
+// becomes ResponseListUser.
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return ""
+	}
+
+	name = packagePathRegex.ReplaceAllString(name, "")
+	return strings.NewReplacer("[", "", "]", "", ",", "", "*", "").Replace(name)
+}
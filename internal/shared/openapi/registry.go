@@ -0,0 +1,501 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// ProblemSchema is the component name of the RFC 9457 error body.
+const ProblemSchema = "Problem"
+
+var (
+	echoParamRegex = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+	pathParamRegex = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+)
+
+// SchemaProvider is implemented by request types that describe their body
+// with a valid schema. The same schema is used to validate and to document.
+type SchemaProvider interface {
+	Schema() valid.Schema
+}
+
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Schema      valid.Schema
+}
+
+// PathParam documents a required path parameter.
+func PathParam(name, description string, schema valid.Schema) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: schema}
+}
+
+// QueryParam documents an optional query parameter.
+func QueryParam(name, description string, schema valid.Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// HeaderParam documents an optional request header.
+func HeaderParam(name, description string, schema valid.Schema) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: schema}
+}
+
+// FileField documents a file uploaded in a multipart/form-data body.
+type FileField struct {
+	Name        string
+	Description string
+	// ContentTypes are the accepted content types, every type when empty
+	ContentTypes []string
+}
+
+// Operation describes a route. Request and Response are zero values of the
+// Go types sent and returned, e.g. entity.CreateUserRequest{} and entity.User{}.
+type Operation struct {
+	ID          string
+	Summary     string
+	Description string
+	Tags        []string
+	Parameters  []Parameter
+	Request     any
+	// Files documents a multipart/form-data body with the files, for
+	// routes without a JSON Request
+	Files    []FileField
+	Response any
+	// Download documents a file answered instead of a JSON Response, with
+	// its content type, e.g. text/vcard for server.Attachment results
+	Download string
+	// Status is the success status. Defaults to 200, or 204 without Response.
+	Status int
+	// Errors lists the documented error statuses. When empty, 400 and 500 are
+	// always added, 401 for secured routes, 403 for routes with a
+	// Permission, 404 for routes with path parameters, 422 for routes
+	// with a request body and 413 and 415 for routes with Files.
+	Errors []int
+	// Permission is the "<module>.<action>" code required to call the
+	// route, see Registry.Permission. It is documented as x-permission.
+	Permission string
+	// PublicPermission grants Permission to every user, see
+	// auth.module_actions.is_public.
+	PublicPermission bool
+	// Authenticated declares that a secured route only requires
+	// authentication, e.g. the routes on the caller's own account.
+	// Secured routes declare either it or a Permission.
+	Authenticated bool
+}
+
+// Action describes a permission declared by the routes, see
+// Registry.Actions.
+type Action struct {
+	Module string
+	Action string
+	// Type is the method of the first route declaring the action
+	Type string
+	// Public is set when any route declares the action public
+	Public bool
+	// Summary is the summary of the first route declaring the action
+	Summary string
+}
+
+// Code returns the "<module>.<action>" code of the action.
+func (a Action) Code() string {
+	return a.Module + "." + a.Action
+}
+
+// Registry collects operations and assembles an OpenAPI 3.1 document.
+type Registry struct {
+	info            Info
+	servers         []Server
+	paths           map[string]PathItem
+	schemas         map[string]map[string]any
+	errors          map[int]struct{}
+	securitySchemes map[string]SecurityScheme
+	// security is required by every operation added through this registry
+	security []SecurityRequirement
+	// parameters are accepted by every operation added through this
+	// registry
+	parameters []Parameter
+	// permissions maps "<METHOD> <echo path>" to the permission of the
+	// route, "" for routes declared Authenticated
+	permissions map[string]string
+	// actions maps permission codes to the action they describe
+	actions map[string]Action
+}
+
+func NewRegistry(info Info, servers ...Server) *Registry {
+	return &Registry{
+		info:            info,
+		servers:         servers,
+		paths:           make(map[string]PathItem),
+		schemas:         make(map[string]map[string]any),
+		errors:          make(map[int]struct{}),
+		securitySchemes: make(map[string]SecurityScheme),
+		permissions:     make(map[string]string),
+		actions:         make(map[string]Action),
+	}
+}
+
+// Secured registers the security scheme and returns a view of the registry
+// whose operations require it, e.g. for the routes of the private group.
+// Securing a secured view adds the scheme as an alternative, e.g. access
+// tokens or API keys. Operations added through the view end up in the same
+// document.
+func (r *Registry) Secured(name string, scheme SecurityScheme) *Registry {
+	r.securitySchemes[name] = scheme
+
+	secured := *r
+	secured.security = append(slices.Clone(r.security), SecurityRequirement{name: {}})
+	return &secured
+}
+
+// WithParameters returns a view of the registry whose operations accept
+// the parameters, e.g. a header read by middleware. Operations added
+// through the view end up in the same document.
+func (r *Registry) WithParameters(params ...Parameter) *Registry {
+	view := *r
+	view.parameters = slices.Concat(r.parameters, params)
+	return &view
+}
+
+// Add documents the route registered for method and path. Echo style path
+// parameters (":id") are converted to OpenAPI templates ("{id}"). It
+// panics when the permission is not a "<module>.<action>" code, or when a
+// secured route declares neither a Permission nor Authenticated.
+func (r *Registry) Add(method, path string, op Operation) {
+	if op.Permission != "" {
+		r.addAction(method, path, op)
+	}
+	switch {
+	case op.Permission != "" || op.Authenticated:
+		r.permissions[method+" "+path] = op.Permission
+	case len(r.security) > 0:
+		panic(fmt.Sprintf("openapi: secured route %s %s declares neither a permission nor Authenticated", method, path))
+	}
+
+	path = echoParamRegex.ReplaceAllString(path, "{$1}")
+
+	operation := &OperationObject{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Parameters:  r.parameterObjects(path, slices.Concat(r.parameters, op.Parameters)),
+		Responses:   make(map[string]*ResponseObject),
+		Security:    r.security,
+		Permission:  op.Permission,
+	}
+
+	if op.Request != nil {
+		operation.RequestBody = &RequestBodyObject{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: r.requestSchema(op.Request, operation.Parameters)},
+			},
+		}
+	}
+
+	if len(op.Files) > 0 {
+		operation.RequestBody = &RequestBodyObject{
+			Required: true,
+			Content: map[string]MediaType{
+				"multipart/form-data": {Schema: filesSchema(op.Files), Encoding: filesEncoding(op.Files)},
+			},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+		if op.Response == nil && op.Download == "" {
+			status = http.StatusNoContent
+		}
+	}
+
+	success := &ResponseObject{Description: http.StatusText(status)}
+	switch {
+	case op.Download != "":
+		success.Content = map[string]MediaType{
+			op.Download: {Schema: map[string]any{"type": "string", "format": "binary"}},
+		}
+	case op.Response != nil:
+		success.Content = map[string]MediaType{
+			"application/json": {Schema: r.schemaOf(reflect.TypeOf(op.Response))},
+		}
+	}
+	operation.Responses[strconv.Itoa(status)] = success
+
+	for _, code := range r.errorStatuses(op, operation) {
+		r.errors[code] = struct{}{}
+		operation.Responses[strconv.Itoa(code)] = &ResponseObject{Ref: "#/components/responses/" + responseName(code)}
+	}
+
+	if r.paths[path] == nil {
+		r.paths[path] = make(PathItem)
+	}
+	r.paths[path][strings.ToLower(method)] = operation
+}
+
+// Permission returns the permission of the route registered for method
+// and path, as reported by echo.Context.Path, or "" when the route is
+// declared Authenticated. declared is false for unknown routes and routes
+// declaring neither.
+func (r *Registry) Permission(method, path string) (code string, declared bool) {
+	code, declared = r.permissions[method+" "+path]
+	return code, declared
+}
+
+// Actions returns the permissions declared by the routes, sorted by code.
+func (r *Registry) Actions() []Action {
+	actions := make([]Action, 0, len(r.actions))
+	for _, action := range r.actions {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].Code() < actions[j].Code()
+	})
+	return actions
+}
+
+// Document returns the assembled OpenAPI document.
+func (r *Registry) Document() Document {
+	responses := make(map[string]*ResponseObject, len(r.errors))
+	for code := range r.errors {
+		responses[responseName(code)] = &ResponseObject{
+			Description: http.StatusText(code),
+			Content: map[string]MediaType{
+				"application/problem+json": {Schema: Ref(ProblemSchema)},
+			},
+		}
+	}
+
+	schemas := make(map[string]map[string]any, len(r.schemas)+1)
+	for name, schema := range r.schemas {
+		schemas[name] = schema
+	}
+	schemas[ProblemSchema] = problemSchema()
+
+	return Document{
+		OpenAPI:           Version,
+		Info:              r.info,
+		JSONSchemaDialect: valid.JSONSchemaDialect,
+		Servers:           r.servers,
+		Paths:             r.paths,
+		Components: Components{
+			Schemas:         schemas,
+			Responses:       responses,
+			SecuritySchemes: r.securitySchemes,
+		},
+	}
+}
+
+// JSON returns the document as indented JSON. Map keys are sorted, so the
+// output is stable and can be committed and diffed.
+func (r *Registry) JSON() ([]byte, error) {
+	content, err := json.MarshalIndent(r.Document(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+func (r *Registry) addAction(method, path string, op Operation) {
+	module, action, ok := strings.Cut(op.Permission, ".")
+	if !ok || module == "" || action == "" || strings.Contains(action, ".") {
+		panic(fmt.Sprintf("openapi: permission %q of %s %s is not a <module>.<action> code", op.Permission, method, path))
+	}
+
+	existing, ok := r.actions[op.Permission]
+	if !ok {
+		existing = Action{Module: module, Action: action, Type: method, Summary: op.Summary}
+	}
+	existing.Public = existing.Public || op.PublicPermission
+	r.actions[op.Permission] = existing
+}
+
+func (r *Registry) parameterObjects(path string, params []Parameter) []ParameterObject {
+	declared := make(map[string]struct{}, len(params))
+	objects := make([]ParameterObject, 0, len(params))
+	for _, param := range params {
+		declared[param.In+":"+param.Name] = struct{}{}
+		objects = append(objects, ParameterObject{
+			Name:        param.Name,
+			In:          param.In,
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      param.Schema.JSONSchema(),
+		})
+	}
+
+	// Path parameters must always be documented
+	for _, match := range pathParamRegex.FindAllStringSubmatch(path, -1) {
+		if _, ok := declared["path:"+match[1]]; ok {
+			continue
+		}
+		objects = append(objects, ParameterObject{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   map[string]any{"type": "string"},
+		})
+	}
+
+	return objects
+}
+
+// requestSchema registers the body type as a component. Field types come
+// from the struct and constraints and required fields from its valid
+// schema. Fields bound from path parameters are left out.
+func (r *Registry) requestSchema(request any, params []ParameterObject) map[string]any {
+	t := indirect(reflect.TypeOf(request))
+	name := componentName(t)
+
+	schema := r.structSchema(t)
+	delete(schema, "required")
+	properties := schema["properties"].(map[string]any)
+
+	if provider, ok := request.(SchemaProvider); ok {
+		validSchema := provider.Schema().JSONSchema()
+		if validProperties, ok := validSchema["properties"].(map[string]any); ok {
+			for field, constraints := range validProperties {
+				merged := map[string]any{}
+				if existing, ok := properties[field].(map[string]any); ok {
+					for key, value := range existing {
+						merged[key] = value
+					}
+				}
+				for key, value := range constraints.(map[string]any) {
+					if key == "type" && merged["type"] != nil {
+						continue
+					}
+					merged[key] = value
+				}
+				properties[field] = merged
+			}
+		}
+		if required, ok := validSchema["required"].([]string); ok {
+			schema["required"] = required
+		}
+	}
+
+	for _, param := range params {
+		if param.In != "path" {
+			continue
+		}
+		delete(properties, param.Name)
+		if required, ok := schema["required"].([]string); ok {
+			schema["required"] = without(required, param.Name)
+		}
+	}
+	if required, ok := schema["required"].([]string); ok && len(required) == 0 {
+		delete(schema, "required")
+	}
+
+	if name == "" {
+		return schema
+	}
+	r.schemas[name] = schema
+	return Ref(name)
+}
+
+func (r *Registry) errorStatuses(op Operation, operation *OperationObject) []int {
+	if len(op.Errors) > 0 {
+		return op.Errors
+	}
+
+	statuses := []int{http.StatusBadRequest, http.StatusInternalServerError}
+	if len(operation.Security) > 0 {
+		statuses = append(statuses, http.StatusUnauthorized)
+	}
+	if op.Permission != "" {
+		statuses = append(statuses, http.StatusForbidden)
+	}
+	for _, param := range operation.Parameters {
+		if param.In == "path" {
+			statuses = append(statuses, http.StatusNotFound)
+			break
+		}
+	}
+	if op.Request != nil || len(op.Files) > 0 {
+		statuses = append(statuses, http.StatusUnprocessableEntity)
+	}
+	if len(op.Files) > 0 {
+		statuses = append(statuses, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType)
+	}
+
+	sort.Ints(statuses)
+	return statuses
+}
+
+func filesSchema(files []FileField) map[string]any {
+	properties := make(map[string]any, len(files))
+	required := make([]string, 0, len(files))
+	for _, file := range files {
+		property := map[string]any{"type": "string", "format": "binary"}
+		if file.Description != "" {
+			property["description"] = file.Description
+		}
+		properties[file.Name] = property
+		required = append(required, file.Name)
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// filesEncoding documents the accepted content types of the files
+func filesEncoding(files []FileField) map[string]Encoding {
+	var encoding map[string]Encoding
+	for _, file := range files {
+		if len(file.ContentTypes) == 0 {
+			continue
+		}
+		if encoding == nil {
+			encoding = make(map[string]Encoding)
+		}
+		encoding[file.Name] = Encoding{ContentType: strings.Join(file.ContentTypes, ", ")}
+	}
+	return encoding
+}
+
+func problemSchema() map[string]any {
+	return map[string]any{
+		"type":        "object",
+		"description": "RFC 9457 problem details. Extension members such as error_code are added at the top level.",
+		"properties": map[string]any{
+			"type":     map[string]any{"type": "string", "format": "uri-reference"},
+			"title":    map[string]any{"type": "string"},
+			"detail":   map[string]any{"type": "string"},
+			"status":   map[string]any{"type": "integer"},
+			"instance": map[string]any{"type": "string", "format": "uri-reference"},
+		},
+		"required":             []string{"type", "status"},
+		"additionalProperties": true,
+	}
+}
+
+func responseName(code int) string {
+	return strings.NewReplacer(" ", "", "-", "", "'", "").Replace(http.StatusText(code))
+}
+
+func without(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
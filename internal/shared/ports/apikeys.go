@@ -0,0 +1,51 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/core/apikeys/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// APIKeyRepository is scoped to the tenant of the context by the
+// organization_id of the keys (see tenant.Restrict), except FindUsable
+// and Touch, which authenticate requests before their tenant is known,
+// and RevokeUsers.
+type APIKeyRepository interface {
+	RepositoryTx[APIKeyRepository]
+	// Create stores the key with its actions, and the service account
+	// user it acts as. It returns a fault.BadRequest error when an action
+	// does not exist.
+	Create(ctx context.Context, key entity.APIKey) error
+	// Find and List return the keys with their actions. Filters may use
+	// the id, organization_id, user_id, name, prefix, revoked_at and
+	// created_by fields.
+	Find(ctx context.Context, criteria dafi.Criteria) (entity.APIKey, error)
+	List(ctx context.Context, criteria dafi.Criteria) (types.List[entity.APIKey], error)
+	// Revoke revokes the unrevoked keys matching the filters. It returns
+	// a fault.NotFound error when none matches.
+	Revoke(ctx context.Context, key entity.APIKey, filters ...dafi.Filter) error
+	// RevokeUsers revokes the unrevoked keys acting as the users, e.g.
+	// when the users are deactivated, and returns how many it revoked
+	RevokeUsers(ctx context.Context, at time.Time, by *uuid.UUID, userIDs ...uuid.UUID) (int64, error)
+	// FindUsable returns the unrevoked, unexpired key with the prefix, of
+	// an active organization.
+	FindUsable(ctx context.Context, prefix string) (entity.APIKey, error)
+	// Touch records that the key was used at usedAt.
+	Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+type APIKeyUseCase interface {
+	CreateAPIKey(ctx context.Context, req entity.CreateAPIKeyRequest) (entity.CreatedAPIKey, error)
+	GetAPIKeyByID(ctx context.Context, id uuid.UUID) (entity.APIKey, error)
+	ListAPIKeys(ctx context.Context, criteria dafi.Criteria) (types.List[entity.APIKey], error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+	// VerifyAPIKey returns the principal of a raw key. It returns a
+	// fault.Unauthorized error when the key is unknown or unusable.
+	VerifyAPIKey(ctx context.Context, key string) (auth.Principal, error)
+}
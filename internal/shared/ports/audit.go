@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"api.system.soluciones-cloud.com/internal/core/audit/domain/entity"
+)
+
+// AuditRepository appends entries to the audit log. Record the entry in
+// the transaction of the change it describes, so neither is stored
+// without the other.
+type AuditRepository interface {
+	RepositoryTx[AuditRepository]
+	Record(ctx context.Context, entry entity.Entry) error
+}
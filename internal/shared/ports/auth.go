@@ -0,0 +1,182 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/core/auth/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/rbac"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+type CredentialRepository interface {
+	RepositoryTx[CredentialRepository]
+	Create(ctx context.Context, credential entity.EmailCredential) error
+	// FindByEmail returns pgx.ErrNoRows when no credential has the
+	// normalized email
+	FindByEmail(ctx context.Context, email string) (entity.EmailCredential, error)
+	// FindByUser returns pgx.ErrNoRows when the user has no email
+	// credential, e.g. users of identity providers and service accounts
+	FindByUser(ctx context.Context, userID uuid.UUID) (entity.EmailCredential, error)
+	// RecordFailedLogin counts a failed login. The maxAttempts-th
+	// consecutive failure locks the credential until lockUntil and resets
+	// the count.
+	RecordFailedLogin(ctx context.Context, id uuid.UUID, maxAttempts int, lockUntil time.Time) error
+	// RecordLogin resets the failed login count and sets last_login_at
+	RecordLogin(ctx context.Context, id uuid.UUID, at time.Time) error
+	// UpdatePasswordHash replaces the password hash, e.g. after a change
+	// of password or a rehash with stronger parameters
+	UpdatePasswordHash(ctx context.Context, id uuid.UUID, hash string, at time.Time) error
+	// SetVerificationToken replaces the pending email verification token
+	SetVerificationToken(ctx context.Context, id uuid.UUID, tokenHash string, expiresAt time.Time) error
+	// VerifyEmail consumes an unexpired verification token and marks the
+	// email as verified. It returns pgx.ErrNoRows when no credential has
+	// the token.
+	VerifyEmail(ctx context.Context, tokenHash string, at time.Time) (entity.EmailCredential, error)
+	// SetPasswordResetToken replaces the pending password reset token
+	SetPasswordResetToken(ctx context.Context, id uuid.UUID, tokenHash string, expiresAt time.Time) error
+	// ResetPassword consumes an unexpired password reset token, sets the
+	// password hash and unlocks the credential. It returns pgx.ErrNoRows
+	// when no credential has the token.
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string, at time.Time) (entity.EmailCredential, error)
+}
+
+type RefreshTokenRepository interface {
+	RepositoryTx[RefreshTokenRepository]
+	Create(ctx context.Context, token entity.RefreshToken) error
+	// FindByHash locks the token row until the transaction ends, so
+	// concurrent refreshes with the same token are serialized
+	FindByHash(ctx context.Context, hash string) (entity.RefreshToken, error)
+	// Rotate revokes the token and records the token that replaced it
+	Rotate(ctx context.Context, id, replacedBy uuid.UUID, at time.Time) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error
+	// RevokeUser revokes every refresh token of the user
+	RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time) error
+}
+
+// SessionRepository stores the login sessions of users. Revoking a
+// session does not revoke its refresh tokens, see
+// RefreshTokenRepository.RevokeFamily.
+type SessionRepository interface {
+	RepositoryTx[SessionRepository]
+	Create(ctx context.Context, session entity.Session) error
+	// Renew records the refresh of a session by the client, which extends
+	// it until expiresAt
+	Renew(ctx context.Context, id uuid.UUID, client entity.ClientInfo, at, expiresAt time.Time) error
+	// Touch records a request of an unrevoked session and the
+	// organization it acted on, if any. It returns pgx.ErrNoRows when the
+	// session is unknown or revoked.
+	Touch(ctx context.Context, id uuid.UUID, at time.Time, organizationID uuid.NullUUID) error
+	// ListActive returns the unrevoked, unexpired sessions of the user,
+	// most recently seen first
+	ListActive(ctx context.Context, userID uuid.UUID, now time.Time) (types.List[entity.Session], error)
+	// Revoke revokes an unrevoked session of the user. It returns
+	// pgx.ErrNoRows when the user has no such session.
+	Revoke(ctx context.Context, userID, id uuid.UUID, at time.Time, by *uuid.UUID) error
+	// RevokeUser revokes every unrevoked session of the user and returns
+	// their ids
+	RevokeUser(ctx context.Context, userID uuid.UUID, at time.Time, by *uuid.UUID) ([]uuid.UUID, error)
+	// RevokeOthers revokes every unrevoked session of the user but keep,
+	// and returns their ids
+	RevokeOthers(ctx context.Context, userID, keep uuid.UUID, at time.Time, by *uuid.UUID) ([]uuid.UUID, error)
+	// RevokedSince returns the ids of the sessions revoked at or after
+	// since
+	RevokedSince(ctx context.Context, since time.Time) ([]uuid.UUID, error)
+}
+
+// SessionDenylist holds the revoked sessions whose access tokens may not
+// have expired yet, e.g. session.Denylist.
+type SessionDenylist interface {
+	Add(until time.Time, ids ...uuid.UUID)
+	Contains(id uuid.UUID) bool
+}
+
+type IdentityRepository interface {
+	RepositoryTx[IdentityRepository]
+	Create(ctx context.Context, identity entity.Identity) error
+	// FindBySubject returns pgx.ErrNoRows when no user is linked to the
+	// account of the provider
+	FindBySubject(ctx context.Context, provider, subject string) (entity.Identity, error)
+	RecordLogin(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+type LoginStateRepository interface {
+	// Create stores a login state and deletes the expired ones
+	Create(ctx context.Context, state entity.LoginState) error
+	// Consume deletes and returns the unexpired state of the provider with
+	// the hash. It returns pgx.ErrNoRows when there is none, so each state
+	// is used once.
+	Consume(ctx context.Context, provider, stateHash string, now time.Time) (entity.LoginState, error)
+}
+
+// IdentityProviders are the OpenID Connect providers users may log in
+// with. Unknown providers are fault.NotFound errors.
+type IdentityProviders interface {
+	Names() []string
+	AuthCodeURL(ctx context.Context, provider, state, nonce, challenge string) (string, error)
+	// Exchange redeems the authorization code with the PKCE code verifier
+	// and returns the identity of the verified ID token, which must carry
+	// nonce
+	Exchange(ctx context.Context, provider, code, verifier, nonce string) (entity.ExternalIdentity, error)
+}
+
+// PasswordHasher hashes and verifies passwords, e.g. password.Hasher.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, hash string) (ok bool, needsRehash bool, err error)
+}
+
+// AccessTokenIssuer signs short-lived access tokens, e.g. token.Issuer.
+type AccessTokenIssuer interface {
+	Issue(principal auth.Principal) (string, error)
+	TTL() time.Duration
+}
+
+// PermissionRepository loads the effective permissions of users, see
+// rbac.Store.
+type PermissionRepository interface {
+	Permissions(ctx context.Context, userID uuid.UUID, organizationID uuid.NullUUID) (rbac.Permissions, error)
+	APIKeyPermissions(ctx context.Context, apiKeyID uuid.UUID) (rbac.Permissions, error)
+}
+
+// Authorizer resolves and caches the permissions of principals, e.g.
+// rbac.Authorizer. Invalidate must be called when the roles of users
+// change, InvalidateAll when the permissions of a role change.
+type Authorizer interface {
+	Permissions(ctx context.Context, principal auth.Principal) (rbac.Permissions, error)
+	Authorize(ctx context.Context, principal auth.Principal, code string) (rbac.Access, error)
+	Invalidate(userIDs ...uuid.UUID)
+	InvalidateAll()
+}
+
+type AuthUseCase interface {
+	Register(ctx context.Context, req entity.RegisterRequest) (entity.Tokens, error)
+	Login(ctx context.Context, req entity.LoginRequest) (entity.Tokens, error)
+	Refresh(ctx context.Context, req entity.RefreshRequest) (entity.Tokens, error)
+	Logout(ctx context.Context, req entity.RefreshRequest) error
+	VerifyEmail(ctx context.Context, req entity.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, req entity.EmailRequest) error
+	ForgotPassword(ctx context.Context, req entity.EmailRequest) error
+	ResetPassword(ctx context.Context, req entity.ResetPasswordRequest) error
+	// ChangePassword changes the password of the caller
+	ChangePassword(ctx context.Context, req entity.ChangePasswordRequest) error
+	OIDCProviders(ctx context.Context) (types.List[entity.OIDCProvider], error)
+	StartOIDCLogin(ctx context.Context, req entity.OIDCAuthorizeRequest) (entity.OIDCAuthorization, error)
+	CompleteOIDCLogin(ctx context.Context, req entity.OIDCCallbackRequest) (entity.Tokens, error)
+}
+
+type SessionUseCase interface {
+	// MySessions and RevokeMySession act on the sessions of the caller
+	MySessions(ctx context.Context) (types.List[entity.Session], error)
+	RevokeMySession(ctx context.Context, id uuid.UUID) error
+	// UserSessions and RevokeUserSessions act on the sessions of a user
+	// visible to the caller
+	UserSessions(ctx context.Context, userID uuid.UUID) (types.List[entity.Session], error)
+	RevokeUserSessions(ctx context.Context, userID uuid.UUID) error
+	// CheckSession returns a fault.Unauthorized error when the session of
+	// the principal was revoked, and records its activity
+	CheckSession(ctx context.Context, principal auth.Principal) error
+}
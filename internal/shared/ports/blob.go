@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Blob describes a stored file. Keys are slash separated relative paths,
+// e.g. "avatars/<user id>/<name>.png", see blob.ValidateKey.
+type Blob struct {
+	Key         string
+	ContentType string
+	Size        int64
+}
+
+// BlobStore stores files, e.g. blob.LocalStore or blob.S3Store. Files are
+// never public: clients download them from signed URLs that expire.
+type BlobStore interface {
+	// Put stores the Size bytes of body under the key of blob, replacing
+	// any file with the same key
+	Put(ctx context.Context, blob Blob, body io.Reader) error
+	// Get returns the content of the file, which the caller must close. It
+	// returns a fault.NotFound error when no file has the key.
+	Get(ctx context.Context, key string) (io.ReadCloser, Blob, error)
+	// Delete removes the file. Deleting a missing file succeeds.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL the file can be downloaded from, without
+	// credentials, until expiry elapses
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/core/companies/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// CompanyRepository is scoped to the tenant of the context by the
+// organizations the companies belong to (see tenant.Restrict). Filters
+// may use organization_id to select the companies of organizations.
+// Deleted companies are only returned when the filters select them by
+// deleted_at.
+type CompanyRepository interface {
+	RepositoryTx[CompanyRepository]
+	RepositoryCommand[entity.Company, entity.Company]
+	RepositoryQuery[entity.Company]
+	// AddOrganization makes the company belong to the organization
+	AddOrganization(ctx context.Context, companyID, organizationID uuid.UUID, at time.Time, by *uuid.UUID) error
+	// Restore undeletes the deleted companies matching the filters. It
+	// returns a fault.NotFound error when none matches.
+	Restore(ctx context.Context, filters ...dafi.Filter) error
+	// TaxIDTaken reports whether another live company of the organizations
+	// of the company has its tax id. It must run in the transaction
+	// writing the company, which it serializes with the other checks of
+	// the tax id in those organizations.
+	TaxIDTaken(ctx context.Context, company entity.Company) (bool, error)
+}
+
+// CatalogOption requires values to be active options of the config catalog
+// with the code, e.g. company_type. It runs on the executor of the
+// context, see valid.WithExecutor.
+func CatalogOption(catalog string) *valid.DatabaseRule {
+	return valid.Exists("config.active_catalog_options", "value").Where("catalog", catalog)
+}
+
+type CompanyUseCase interface {
+	CreateCompany(ctx context.Context, req entity.CreateCompanyRequest) (entity.Company, error)
+	GetCompanyByID(ctx context.Context, id uuid.UUID) (entity.Company, error)
+	ListCompanies(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Company], error)
+	CountCompanies(ctx context.Context, criteria dafi.Criteria) (int64, error)
+	UpdateCompany(ctx context.Context, req entity.UpdateCompanyRequest) (entity.Company, error)
+	// DeleteCompany soft deletes a company and RestoreCompany undoes it
+	DeleteCompany(ctx context.Context, id uuid.UUID) error
+	RestoreCompany(ctx context.Context, id uuid.UUID) (entity.Company, error)
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/core/contacts/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// ContactRepository is scoped to the tenant of the context by the
+// organization of the contacts (see tenant.Restrict). Filters on
+// contact_types and primary_types select the contacts having the type.
+// Deleted contacts are never returned.
+type ContactRepository interface {
+	RepositoryTx[ContactRepository]
+	RepositoryCommand[entity.Contact, entity.Contact]
+	RepositoryQuery[entity.Contact]
+	// ReleasePrimary removes the primary types of the contact from the
+	// other contacts of its company in its organization
+	ReleasePrimary(ctx context.Context, contact entity.Contact) error
+}
+
+type ContactUseCase interface {
+	CreateContact(ctx context.Context, req entity.CreateContactRequest) (entity.Contact, error)
+	GetContactByID(ctx context.Context, id uuid.UUID) (entity.Contact, error)
+	ListContacts(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Contact], error)
+	CountContacts(ctx context.Context, criteria dafi.Criteria) (int64, error)
+	UpdateContact(ctx context.Context, req entity.UpdateContactRequest) (entity.Contact, error)
+	DeleteContact(ctx context.Context, id uuid.UUID) error
+	// ExportContacts returns the contacts of a company as a vCard file
+	ExportContacts(ctx context.Context, companyID uuid.UUID) (entity.ContactExport, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"api.system.soluciones-cloud.com/internal/core/files/domain/entity"
+)
+
+// FileUseCase stores the files of the organization of the request, e.g.
+// the documents linked by file_url columns.
+type FileUseCase interface {
+	// Upload stores the file in the organization of the request, which
+	// must be a single one
+	Upload(ctx context.Context, file io.Reader) (entity.StoredFile, error)
+	// URL signs a download URL of a file of an organization of the
+	// request. Files of other organizations are not found.
+	URL(ctx context.Context, key string) (entity.FileURL, error)
+}
@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+)
+
+// Email is a message with a plain text body and an optional HTML
+// alternative. An empty From is replaced by the default sender of the
+// Mailer.
+type Email struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers emails, e.g. mail.SMTPMailer, or mail.FileMailer during
+// development.
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// EmailTemplates renders localized emails, e.g. mail.Templates. The
+// recipients of the returned email are left to the caller.
+type EmailTemplates interface {
+	Render(locale i18n.Locale, name string, data any) (Email, error)
+}
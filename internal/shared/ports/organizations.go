@@ -0,0 +1,65 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/core/organizations/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// OrganizationRepository is scoped to the tenant of the context by the id
+// of the organizations, MemberRepository by their organization_id (see
+// tenant.Restrict). Deleted organizations are never returned.
+type OrganizationRepository interface {
+	RepositoryTx[OrganizationRepository]
+	RepositoryCommand[entity.Organization, entity.Organization]
+	RepositoryQuery[entity.Organization]
+}
+
+type MemberRepository interface {
+	RepositoryTx[MemberRepository]
+	// Add adds the member. It returns a fault.Conflict error when the
+	// user already belongs to the organization on behalf of the customer.
+	Add(ctx context.Context, member entity.Member) error
+	// Find and List return the members with the name and email of their
+	// user. Filters may use the id, organization_id, user_id, customer_id,
+	// relationship and is_active fields.
+	Find(ctx context.Context, criteria dafi.Criteria) (entity.Member, error)
+	List(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Member], error)
+	// Update changes the relationship and active flag of the members
+	// matching the filters. It returns a fault.NotFound error when none
+	// matches.
+	Update(ctx context.Context, member entity.Member, filters ...dafi.Filter) error
+	// Remove deletes the members matching the filters. It returns a
+	// fault.NotFound error when none matches.
+	Remove(ctx context.Context, filters ...dafi.Filter) error
+	// Memberships lists the active memberships of the user in active
+	// organizations. It is not scoped to the tenant of the context.
+	Memberships(ctx context.Context, userID uuid.UUID) (types.List[entity.Membership], error)
+	// DeactivateUsers deactivates the active memberships of the users,
+	// e.g. when the users are deactivated, and ActivateUsers restores the
+	// memberships deactivated this way. Both return how many memberships
+	// changed and are not scoped to the tenant of the context.
+	DeactivateUsers(ctx context.Context, at time.Time, by *uuid.UUID, userIDs ...uuid.UUID) (int64, error)
+	ActivateUsers(ctx context.Context, at time.Time, by *uuid.UUID, userIDs ...uuid.UUID) (int64, error)
+}
+
+type OrganizationUseCase interface {
+	CreateOrganization(ctx context.Context, req entity.CreateOrganizationRequest) (entity.Organization, error)
+	GetOrganizationByID(ctx context.Context, id uuid.UUID) (entity.Organization, error)
+	ListOrganizations(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Organization], error)
+	CountOrganizations(ctx context.Context, criteria dafi.Criteria) (int64, error)
+	UpdateOrganization(ctx context.Context, req entity.UpdateOrganizationRequest) (entity.Organization, error)
+	DeleteOrganization(ctx context.Context, id uuid.UUID) error
+	ListMembers(ctx context.Context, organizationID uuid.UUID, criteria dafi.Criteria) (types.List[entity.Member], error)
+	AddMember(ctx context.Context, req entity.AddMemberRequest) (entity.Member, error)
+	InviteMember(ctx context.Context, req entity.InviteMemberRequest) (entity.Member, error)
+	UpdateMember(ctx context.Context, req entity.UpdateMemberRequest) (entity.Member, error)
+	RemoveMember(ctx context.Context, req entity.MemberRequest) error
+	// MyOrganizations lists the organizations the caller may switch to
+	MyOrganizations(ctx context.Context) (types.List[entity.Membership], error)
+}
@@ -3,10 +3,11 @@ package ports
 import (
 	"context"
 	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/database"
+	"api.system.soluciones-cloud.com/internal/shared/fault"
 	"api.system.soluciones-cloud.com/internal/shared/types"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type Transaction interface {
@@ -19,13 +20,28 @@ type UnitOfWork interface {
 	Rollback(ctx context.Context, tx Transaction) error
 }
 
+// InTx runs fn in a transaction of uow, committing when it returns nil
+// and rolling back otherwise.
+func InTx(ctx context.Context, uow UnitOfWork, fn func(tx Transaction) error) error {
+	tx, err := uow.Begin(ctx)
+	if err != nil {
+		return fault.Wrap(err).Message("failed to begin transaction")
+	}
+
+	if err := fn(tx); err != nil {
+		_ = uow.Rollback(ctx, tx)
+		return err
+	}
+
+	if err := uow.Commit(ctx, tx); err != nil {
+		return fault.Wrap(err).Message("failed to commit transaction")
+	}
+	return nil
+}
+
 // DatabaseExecutor defines the common interface for database operations
 // This interface is implemented by both Database and Tx for consistency
-type DatabaseExecutor interface {
-	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
-	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
-	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
-}
+type DatabaseExecutor = database.Executor
 
 type Database interface {
 	DatabaseExecutor
@@ -0,0 +1,80 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/core/roles/domain/entity"
+	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/types"
+)
+
+// RoleRepository and UserRoleRepository are scoped to the tenant of the
+// context (see tenant.Restrict). Permissions are reached through the ids
+// of scoped roles.
+type RoleRepository interface {
+	RepositoryTx[RoleRepository]
+	RepositoryCommand[entity.Role, entity.Role]
+	RepositoryQuery[entity.Role]
+}
+
+type UserRoleRepository interface {
+	RepositoryTx[UserRoleRepository]
+	// Assign assigns the role to the user and returns the id of the
+	// assignment. An existing assignment is reactivated with the new
+	// expiry. It returns a fault.NotFound error when the role is not one
+	// of the tenant.
+	Assign(ctx context.Context, userRole entity.UserRole) (uuid.UUID, error)
+	// Revoke deletes the assignments matching the filters. It returns a
+	// fault.NotFound error when none matches.
+	Revoke(ctx context.Context, filters ...dafi.Filter) error
+	// List lists assignments with the role they assign. Filters may use
+	// the user_id, role_id, organization_id and assigned_by fields.
+	List(ctx context.Context, criteria dafi.Criteria) (types.List[entity.UserRole], error)
+}
+
+type RolePermissionRepository interface {
+	RepositoryTx[RolePermissionRepository]
+	// Actions returns the module actions with the "<module>.<action>"
+	// codes. Unknown codes are left out.
+	Actions(ctx context.Context, codes []string) (types.List[entity.ModuleAction], error)
+	// List lists the module actions granted to the role
+	List(ctx context.Context, roleID uuid.UUID) (types.List[entity.ModuleAction], error)
+	// Grant grants the actions to the role, skipping granted ones
+	Grant(ctx context.Context, roleID uuid.UUID, actionIDs []uuid.UUID) error
+	Revoke(ctx context.Context, roleID uuid.UUID, actionIDs []uuid.UUID) error
+}
+
+type CatalogRepository interface {
+	RepositoryTx[CatalogRepository]
+	// Sync creates the missing modules and actions, updates the action
+	// type and public flag of existing actions and reports the actions
+	// that are not declared. Nothing is deleted.
+	Sync(ctx context.Context, actions []entity.ActionDescriptor) (entity.CatalogReport, error)
+}
+
+// CatalogUseCase reconciles auth.modules and auth.module_actions with the
+// actions declared by the API routes.
+type CatalogUseCase interface {
+	// Sync applies the changes, or only reports them when dryRun is set
+	Sync(ctx context.Context, actions []entity.ActionDescriptor, dryRun bool) (entity.CatalogReport, error)
+}
+
+type RoleUseCase interface {
+	CreateRole(ctx context.Context, req entity.CreateRoleRequest) (entity.Role, error)
+	GetRoleByID(ctx context.Context, id uuid.UUID) (entity.Role, error)
+	ListRoles(ctx context.Context, criteria dafi.Criteria) (types.List[entity.Role], error)
+	CountRoles(ctx context.Context, criteria dafi.Criteria) (int64, error)
+	UpdateRole(ctx context.Context, req entity.UpdateRoleRequest) (entity.Role, error)
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	ListRolePermissions(ctx context.Context, roleID uuid.UUID) (types.List[entity.ModuleAction], error)
+	// GrantPermissions and RevokePermissions return the permissions of
+	// the role after the change
+	GrantPermissions(ctx context.Context, req entity.RolePermissionsRequest) (types.List[entity.ModuleAction], error)
+	RevokePermissions(ctx context.Context, req entity.RolePermissionsRequest) (types.List[entity.ModuleAction], error)
+	ListUserRoles(ctx context.Context, userID uuid.UUID) (types.List[entity.UserRole], error)
+	AssignRole(ctx context.Context, req entity.AssignRoleRequest) (entity.UserRole, error)
+	RevokeRole(ctx context.Context, req entity.RevokeRoleRequest) error
+	UserPermissions(ctx context.Context, req entity.UserPermissionsRequest) (entity.UserPermissions, error)
+}
@@ -1,12 +1,15 @@
 package ports
 
 import (
+	"io"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
 	"api.system.soluciones-cloud.com/internal/core/users/domain/entity"
 	"api.system.soluciones-cloud.com/internal/shared/dafi"
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
 	"api.system.soluciones-cloud.com/internal/shared/types"
 )
 
@@ -14,6 +17,14 @@ type UserRepository interface {
 	RepositoryTx[UserRepository]
 	RepositoryCommand[entity.User, entity.User]
 	RepositoryQuery[entity.User]
+	// SetActive sets the active flag of the live users matching the
+	// filters whose flag differs, and returns their ids
+	SetActive(ctx context.Context, active bool, at time.Time, by *uuid.UUID, filters ...dafi.Filter) ([]uuid.UUID, error)
+	// Merge re-points the created_by and updated_by columns of every table,
+	// the memberships and the role assignments of the duplicate user to
+	// the canonical one. It must run in a transaction. The duplicate is
+	// left as it is otherwise.
+	Merge(ctx context.Context, duplicateID, canonicalID uuid.UUID, at time.Time, by *uuid.UUID) (entity.MergeResult, error)
 }
 
 type UserUseCase interface {
@@ -24,4 +35,28 @@ type UserUseCase interface {
 	DeleteUser(ctx context.Context, req entity.DeleteUserRequest) error
 	ExistsUser(ctx context.Context, id uuid.UUID) (bool, error)
 	CountUsers(ctx context.Context, criteria dafi.Criteria) (int64, error)
+	// GetMe, UpdateMe and MyPermissions act on the user of the caller
+	GetMe(ctx context.Context) (entity.User, error)
+	UpdateMe(ctx context.Context, req entity.UpdateMeRequest) (entity.User, error)
+	MyPermissions(ctx context.Context) (entity.MyPermissions, error)
+	// PreferredLocale returns the locale the user chose, or "" when they
+	// chose none
+	PreferredLocale(ctx context.Context, userID uuid.UUID) (i18n.Locale, error)
+	// UploadAvatar and DeleteAvatar replace and remove the picture of the
+	// caller. AvatarURL signs the download URL of an uploaded avatar.
+	UploadAvatar(ctx context.Context, image io.Reader) (entity.User, error)
+	DeleteAvatar(ctx context.Context) (entity.User, error)
+	AvatarURL(ctx context.Context, req entity.AvatarRequest) (entity.SignedURL, error)
+	// ActivateUser and DeactivateUser change the status of a user,
+	// ActivateUsers and DeactivateUsers of the users matching the
+	// criteria. Deactivation revokes the sessions and API keys of the
+	// users and deactivates their memberships, which activation restores.
+	ActivateUser(ctx context.Context, id uuid.UUID) (entity.User, error)
+	DeactivateUser(ctx context.Context, id uuid.UUID) (entity.User, error)
+	ActivateUsers(ctx context.Context, criteria dafi.Criteria) (entity.StatusChange, error)
+	DeactivateUsers(ctx context.Context, criteria dafi.Criteria) (entity.StatusChange, error)
+	// MergeUsers moves the references, memberships and roles of a
+	// duplicate user to the canonical one, then deactivates and deletes
+	// the duplicate
+	MergeUsers(ctx context.Context, req entity.MergeUsersRequest) (entity.MergeResult, error)
 }
\ No newline at end of file
@@ -31,6 +31,12 @@ func New(configDB localconfig.DatabaseConfig) (*Adapter, error) {
 
 	config.ConnConfig.Tracer = otelpgx.NewTracer(otelpgx.WithIncludeQueryParameters())
 
+	// Every borrowed connection runs in the tenant of the request, as
+	// defense in depth for the filters of the repositories
+	sessions := newSessions()
+	config.BeforeAcquire = sessions.prepare
+	config.BeforeClose = sessions.forget
+
 	dbPool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
 		return nil, fault.Wrap(fmt.Errorf("unable to create connection pool: %w", err))
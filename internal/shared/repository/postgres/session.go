@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TenantRole is the database role connections switch to while serving a
+// tenant, so the row-level security policies of the business tables apply
+// even when the API connects as their owner.
+const TenantRole = "api_tenant"
+
+// session is the tenant a connection was prepared for. The zero value is a
+// connection without tenant.
+type session struct {
+	role           string
+	organizationID string
+	userID         string
+}
+
+// sessionFrom returns the session a query of ctx must run in.
+// app.organization_id holds the comma separated organizations of the
+// tenant, or tenant.AllOrganizations.
+func sessionFrom(ctx context.Context) session {
+	var s session
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		s.userID = principal.UserID.String()
+	}
+
+	t, ok := tenant.From(ctx)
+	if !ok {
+		return s
+	}
+
+	s.role = TenantRole
+	if t.All {
+		s.organizationID = tenant.AllOrganizations
+		return s
+	}
+
+	organizations := make([]string, len(t.Organizations))
+	for i, organizationID := range t.Organizations {
+		organizations[i] = organizationID.String()
+	}
+	s.organizationID = strings.Join(organizations, ",")
+	return s
+}
+
+// sessions tracks the session of every connection of the pool, to only
+// reach the database when a connection changes of tenant.
+type sessions struct {
+	mu    sync.Mutex
+	conns map[*pgx.Conn]session
+	// set sets the session on the connection
+	set func(ctx context.Context, conn *pgx.Conn, s session) error
+}
+
+func newSessions() *sessions {
+	return &sessions{conns: map[*pgx.Conn]session{}, set: setSession}
+}
+
+// prepare is the BeforeAcquire hook of the pool: it sets the role,
+// app.organization_id and app.user_id of the tenant of ctx on the
+// connection. A connection whose session cannot be set is rejected, so
+// the pool destroys it and acquires another one; the query fails once ctx
+// is done instead of running outside its tenant.
+func (s *sessions) prepare(ctx context.Context, conn *pgx.Conn) bool {
+	want := sessionFrom(ctx)
+
+	s.mu.Lock()
+	current, known := s.conns[conn]
+	s.mu.Unlock()
+	if known && current == want {
+		return true
+	}
+
+	if err := s.set(ctx, conn, want); err != nil {
+		s.forget(conn)
+		return false
+	}
+
+	s.mu.Lock()
+	s.conns[conn] = want
+	s.mu.Unlock()
+	return true
+}
+
+func setSession(ctx context.Context, conn *pgx.Conn, s session) error {
+	role := s.role
+	if role == "" {
+		role = "none"
+	}
+
+	_, err := conn.Exec(ctx,
+		"SELECT set_config('role', $1, false), set_config('app.organization_id', $2, false), set_config('app.user_id', $3, false)",
+		role, s.organizationID, s.userID,
+	)
+	return err
+}
+
+// forget is the BeforeClose hook of the pool.
+func (s *sessions) forget(conn *pgx.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+}
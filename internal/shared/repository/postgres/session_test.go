@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth"
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+)
+
+func TestSessionFrom(t *testing.T) {
+	userID, orgA, orgB := uuid.New(), uuid.New(), uuid.New()
+	withUser := func(ctx context.Context) context.Context {
+		return auth.WithPrincipal(ctx, auth.Principal{UserID: userID})
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want session
+	}{
+		{
+			name: "no tenant",
+			ctx:  context.Background(),
+			want: session{},
+		},
+		{
+			name: "no tenant keeps the user",
+			ctx:  withUser(context.Background()),
+			want: session{userID: userID.String()},
+		},
+		{
+			name: "single organization",
+			ctx:  withUser(tenant.WithTenant(context.Background(), tenant.Tenant{Organizations: []uuid.UUID{orgA}})),
+			want: session{role: TenantRole, organizationID: orgA.String(), userID: userID.String()},
+		},
+		{
+			name: "several organizations",
+			ctx:  tenant.WithTenant(context.Background(), tenant.Tenant{Organizations: []uuid.UUID{orgA, orgB}}),
+			want: session{role: TenantRole, organizationID: orgA.String() + "," + orgB.String()},
+		},
+		{
+			name: "all organizations",
+			ctx:  tenant.WithTenant(context.Background(), tenant.Tenant{All: true}),
+			want: session{role: TenantRole, organizationID: tenant.AllOrganizations},
+		},
+		{
+			name: "no organizations sees nothing",
+			ctx:  tenant.WithTenant(context.Background(), tenant.Tenant{}),
+			want: session{role: TenantRole},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sessionFrom(tt.ctx))
+		})
+	}
+}
+
+func TestSessions_Prepare(t *testing.T) {
+	orgA := uuid.New()
+	ctx := tenant.WithTenant(context.Background(), tenant.Tenant{Organizations: []uuid.UUID{orgA}})
+	want := session{role: TenantRole, organizationID: orgA.String()}
+
+	t.Run("sets the session once", func(t *testing.T) {
+		calls := 0
+		s := newSessions()
+		s.set = func(context.Context, *pgx.Conn, session) error {
+			calls++
+			return nil
+		}
+		conn := &pgx.Conn{}
+
+		assert.True(t, s.prepare(ctx, conn))
+		assert.True(t, s.prepare(ctx, conn))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, want, s.conns[conn])
+
+		assert.True(t, s.prepare(context.Background(), conn))
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, session{}, s.conns[conn])
+	})
+
+	t.Run("rejects connections whose session cannot be set", func(t *testing.T) {
+		s := newSessions()
+		s.set = func(context.Context, *pgx.Conn, session) error {
+			return errors.New("connection reset by peer")
+		}
+		conn := &pgx.Conn{}
+		s.conns[conn] = session{}
+
+		assert.False(t, s.prepare(ctx, conn))
+		assert.NotContains(t, s.conns, conn)
+	})
+}
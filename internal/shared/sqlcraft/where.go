@@ -11,8 +11,9 @@ import (
 )
 
 var (
-	ErrInvalidOperator  = errors.New("invalid dafi operator")
-	ErrInvalidFieldName = errors.New("invalid field name")
+	ErrInvalidOperator     = errors.New("invalid dafi operator")
+	ErrInvalidFieldName    = errors.New("invalid field name")
+	ErrInvalidPlaceholders = errors.New("placeholders do not match the values")
 )
 
 var psqlOperatorByDafiOperator = map[dafi.FilterOperator]string{
@@ -22,6 +23,7 @@ var psqlOperatorByDafiOperator = map[dafi.FilterOperator]string{
 	dafi.GreaterOrEqual: ">=",
 	dafi.Less:           "<",
 	dafi.LessOrEqual:    "<=",
+	dafi.Like:           "LIKE",
 	dafi.Contains:       "ILIKE",
 	dafi.NotContains:    "NOT ILIKE",
 	dafi.Is:             "IS",
@@ -34,10 +36,14 @@ var psqlOperatorByDafiOperator = map[dafi.FilterOperator]string{
 }
 
 // WhereSafe maps domain field names to sql column names,
-// if a filter with an unknow domain field name is found it will return an error
+// if a filter with an unknow domain field name is found it will return an error.
+// dafi.Default filters are conditions written by the repository and are not mapped
 func WhereSafe(initialArgCount int, sqlColumnByDomainField map[string]string, filters ...dafi.Filter) (Result, error) {
 	if len(sqlColumnByDomainField) > 0 {
 		for i, filter := range filters {
+			if filter.Operator == dafi.Default {
+				continue
+			}
 			sqlColumnName, ok := sqlColumnByDomainField[string(filter.Field)]
 			if !ok {
 				return Result{}, fault.Wrap(ErrInvalidFieldName).
@@ -75,7 +81,24 @@ func Where(initialArgCount int, filters ...dafi.Filter) (Result, error) {
 			operator = dafi.Equal
 		}
 
-		if operator == dafi.IsNull || operator == dafi.IsNotNull {
+		if operator == dafi.Default {
+			// The field is a whole condition, e.g. a sub-query, with a ?
+			// placeholder for each value
+			values := defaultValues(filter.Value)
+			parts := strings.Split(string(filter.Field), "?")
+			if len(parts)-1 != len(values) {
+				return Result{}, fault.Wrap(ErrInvalidPlaceholders).With("field", filter.Field)
+			}
+
+			builder.WriteString(parts[0])
+			for j, part := range parts[1:] {
+				argCount++
+				builder.WriteString("$")
+				builder.WriteString(strconv.Itoa(argCount))
+				builder.WriteString(part)
+				args = append(args, values[j])
+			}
+		} else if operator == dafi.IsNull || operator == dafi.IsNotNull {
 			builder.WriteString(string(filter.Field))
 			builder.WriteString(" ")
 			builder.WriteString(psqlOperatorByDafiOperator[operator])
@@ -135,6 +158,19 @@ func Where(initialArgCount int, filters ...dafi.Filter) (Result, error) {
 	}, nil
 }
 
+// defaultValues returns the values of a dafi.Default filter: none for nil,
+// the elements of a []any, or the value itself
+func defaultValues(value any) []any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	default:
+		return []any{v}
+	}
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
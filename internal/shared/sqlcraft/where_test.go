@@ -235,6 +235,76 @@ func TestWhere(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "like operator",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field:    "first_name",
+						Operator: dafi.Like,
+						Value:    "%ana%",
+					},
+				},
+			},
+			want: Result{
+				Sql:  " WHERE first_name LIKE $1",
+				Args: []any{"%ana%"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "default operator with sub-query",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field:       "is_active",
+						Operator:    dafi.Equal,
+						Value:       true,
+						ChainingKey: dafi.And,
+					},
+					dafi.Filter{
+						Field:    "id IN (SELECT user_id FROM members WHERE organization_id = ANY(?) AND role = ?)",
+						Operator: dafi.Default,
+						Value:    []any{[]string{"a", "b"}, "admin"},
+					},
+				},
+			},
+			want: Result{
+				Sql:  " WHERE is_active = $1 AND id IN (SELECT user_id FROM members WHERE organization_id = ANY($2) AND role = $3)",
+				Args: []any{true, []string{"a", "b"}, "admin"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "default operator without values",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field:    "EXISTS (SELECT 1 FROM members)",
+						Operator: dafi.Default,
+					},
+				},
+			},
+			want: Result{
+				Sql:  " WHERE EXISTS (SELECT 1 FROM members)",
+				Args: []any{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "default operator with missing placeholder",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field:    "id IN (SELECT user_id FROM members)",
+						Operator: dafi.Default,
+						Value:    "admin",
+					},
+				},
+			},
+			want:    Result{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -249,3 +319,45 @@ func TestWhere(t *testing.T) {
 		})
 	}
 }
+
+func TestWhereSafe(t *testing.T) {
+	columns := map[string]string{"name": "u.first_name"}
+
+	tests := []struct {
+		name            string
+		initialArgCount int
+		filters         dafi.Filters
+		want            Result
+		wantErr         bool
+	}{
+		{
+			name:    "maps domain fields",
+			filters: dafi.FilterBy("name", dafi.Equal, "Ana"),
+			want:    Result{Sql: " WHERE u.first_name = $1", Args: []any{"Ana"}},
+		},
+		{
+			name:    "unknown field",
+			filters: dafi.FilterBy("password", dafi.Equal, "secret"),
+			wantErr: true,
+		},
+		{
+			name:            "default filters are not mapped",
+			initialArgCount: 1,
+			filters:         dafi.FilterBy("name", dafi.Equal, "Ana").And("u.id IN (SELECT user_id FROM members WHERE role = ?)", dafi.Default, "admin"),
+			want:            Result{Sql: " WHERE u.first_name = $2 AND u.id IN (SELECT user_id FROM members WHERE role = $3)", Args: []any{"Ana", "admin"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WhereSafe(tt.initialArgCount, columns, tt.filters...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WhereSafe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WhereSafe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
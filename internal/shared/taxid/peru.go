@@ -0,0 +1,55 @@
+package taxid
+
+import "slices"
+
+func init() {
+	Register(Peru, "PE", "PER", "PERU", "PERÚ")
+}
+
+// rucPrefixes are the types of taxpayer a RUC can start with: 10 for
+// natural persons with a DNI, 15 and 17 for natural persons without one,
+// 16 for those registered before the DNI and 20 for legal entities
+var rucPrefixes = []string{"10", "15", "16", "17", "20"}
+
+// rucWeights weight the first ten digits of a RUC for its check digit
+var rucWeights = [10]int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+
+// Peru validates RUCs, 11 digits with a check digit, and DNIs, 8 digits.
+func Peru(id string) (string, error) {
+	if !isDigits(id) {
+		return "", ErrFormat
+	}
+
+	switch len(id) {
+	case 8:
+		return id, nil
+	case 11:
+		if err := validateRUC(id); err != nil {
+			return "", err
+		}
+		return id, nil
+	default:
+		return "", ErrFormat
+	}
+}
+
+// validateRUC checks the prefix and the module 11 check digit of a RUC
+func validateRUC(ruc string) error {
+	if !slices.Contains(rucPrefixes, ruc[:2]) {
+		return ErrPrefix
+	}
+
+	sum := 0
+	for i, weight := range rucWeights {
+		sum += int(ruc[i]-'0') * weight
+	}
+
+	check := 11 - sum%11
+	if check >= 10 {
+		check -= 10
+	}
+	if int(ruc[10]-'0') != check {
+		return ErrCheckDigit
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Package taxid validates and normalizes tax identification numbers.
+// Countries plug in their own rules with Register; Peru's RUC and DNI are
+// registered by default. Tax ids of countries without rules are only
+// normalized.
+package taxid
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+// Errors are validation errors with messages in the i18n catalog, so Rule
+// reports them in the language of the request.
+var (
+	ErrEmpty      = valid.NewError("tax_id_empty", "taxid.empty")
+	ErrFormat     = valid.NewError("tax_id_format", "taxid.format")
+	ErrPrefix     = valid.NewError("tax_id_prefix", "taxid.prefix")
+	ErrCheckDigit = valid.NewError("tax_id_check_digit", "taxid.check_digit")
+)
+
+func init() {
+	i18n.Register(i18n.English, map[string]string{
+		"taxid.empty":       "Please enter a tax id",
+		"taxid.format":      "Tax id has an invalid format",
+		"taxid.prefix":      "Tax id has an invalid prefix",
+		"taxid.check_digit": "Tax id has an invalid check digit",
+	})
+
+	i18n.Register(i18n.Spanish, map[string]string{
+		"taxid.empty":       "Por favor ingresa un número de identificación tributaria",
+		"taxid.format":      "El número de identificación tributaria no tiene un formato válido",
+		"taxid.prefix":      "El número de identificación tributaria tiene un prefijo no válido",
+		"taxid.check_digit": "El número de identificación tributaria tiene un dígito verificador no válido",
+	})
+}
+
+// Validator validates a tax id of a country, already stripped of
+// separators and upper cased, and returns it in its canonical form.
+type Validator func(id string) (string, error)
+
+var registry = struct {
+	sync.RWMutex
+	validators map[string]Validator
+}{validators: make(map[string]Validator)}
+
+// Register sets the validator of the countries, given as ISO 3166 codes or
+// names, e.g. Register(validator, "CL", "CHL", "CHILE"). It replaces the
+// validator the countries had.
+func Register(validator Validator, countries ...string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, country := range countries {
+		registry.validators[countryKey(country)] = validator
+	}
+}
+
+// Normalize returns the canonical form of the tax id of the country:
+// without spaces, dots or hyphens, upper cased and validated by the
+// validator of the country, if any.
+func Normalize(country, id string) (string, error) {
+	id = strings.ToUpper(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '.' || r == '-' {
+			return -1
+		}
+		return r
+	}, id))
+	if id == "" {
+		return "", ErrEmpty
+	}
+
+	registry.RLock()
+	validator, ok := registry.validators[countryKey(country)]
+	registry.RUnlock()
+	if !ok {
+		return id, nil
+	}
+
+	return validator(id)
+}
+
+// Rule validates string values as tax ids of the country, e.g.
+// valid.String().Custom(taxid.Rule("PE")).
+func Rule(country string) valid.CustomValidatorFunc {
+	return func(value any) error {
+		id, _ := value.(string)
+		_, err := Normalize(country, id)
+		return err
+	}
+}
+
+func countryKey(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
+
+// isDigits reports whether s only has ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
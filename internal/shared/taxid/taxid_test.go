@@ -0,0 +1,89 @@
+package taxid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"api.system.soluciones-cloud.com/internal/shared/valid"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		country string
+		id      string
+		want    string
+		wantErr error
+	}{
+		{name: "RUC of a legal entity", country: "PE", id: "20100047218", want: "20100047218"},
+		{name: "RUC of a natural person", country: "PE", id: "10467793549", want: "10467793549"},
+		{name: "RUC with separators", country: "pe", id: " 20-10004721.8 ", want: "20100047218"},
+		{name: "country name", country: "Perú", id: "20131312955", want: "20131312955"},
+		{name: "DNI", country: "PER", id: "46779354", want: "46779354"},
+		{name: "RUC check digit", country: "PE", id: "20100047219", wantErr: ErrCheckDigit},
+		{name: "RUC prefix", country: "PE", id: "30100047218", wantErr: ErrPrefix},
+		{name: "RUC with letters", country: "PE", id: "2010004721A", wantErr: ErrFormat},
+		{name: "Peruvian length", country: "PE", id: "123456789", wantErr: ErrFormat},
+		{name: "empty", country: "PE", id: " - ", wantErr: ErrEmpty},
+		{name: "country without validator", country: "AR", id: "30-71234567-1", want: "30712345671"},
+		{name: "no country", id: "b12.345 678", want: "B12345678"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.country, tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	errLength := errors.New("too long")
+	Register(func(id string) (string, error) {
+		if len(id) > 5 {
+			return "", errLength
+		}
+		return "X" + id, nil
+	}, "ZZ")
+	t.Cleanup(func() {
+		registry.Lock()
+		delete(registry.validators, "ZZ")
+		registry.Unlock()
+	})
+
+	if got, err := Normalize("zz", "1-23"); err != nil || got != "X123" {
+		t.Errorf("Expected X123, got %q (%v)", got, err)
+	}
+	if _, err := Normalize("ZZ", "123456"); !errors.Is(err, errLength) {
+		t.Errorf("Expected the error of the validator, got %v", err)
+	}
+}
+
+func TestRule(t *testing.T) {
+	schema := valid.Object(map[string]valid.Schema{
+		"tax_id": valid.String().Custom(Rule("PE")),
+	})
+
+	if result := schema.Parse(map[string]any{"tax_id": "20100047218"}); !result.Success {
+		t.Errorf("Expected a valid RUC, got %v", result.Errors)
+	}
+
+	result := schema.Parse(map[string]any{"tax_id": "20100047219"})
+	if result.Success {
+		t.Fatal("Expected an invalid RUC")
+	}
+	if result.Errors[0].Path != "tax_id" || result.Errors[0].Code != "tax_id_check_digit" || !strings.Contains(result.Errors[0].Message, "check digit") {
+		t.Errorf("Unexpected error %v", result.Errors[0])
+	}
+
+	want := "El número de identificación tributaria tiene un dígito verificador no válido"
+	if message := result.Errors[0].Localize(valid.Spanish).Message; message != want {
+		t.Errorf("Expected %q, got %q", want, message)
+	}
+}
@@ -0,0 +1,16 @@
+package types
+
+import "gopkg.in/guregu/null.v4"
+
+// OptionalString returns s as a null string, null when it is empty
+func OptionalString(s string) null.String {
+	return null.NewString(s, s != "")
+}
+
+// SetString applies a field of an update: nothing when it was omitted,
+// null when it is empty
+func SetString(field *null.String, value null.String) {
+	if value.Valid {
+		*field = OptionalString(value.String)
+	}
+}
@@ -1,6 +1,7 @@
 package valid
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 )
@@ -23,6 +24,10 @@ func (a *ArraySchema) Parse(value interface{}) *Result {
 	return a.parseWithPath(value, "")
 }
 
+func (a *ArraySchema) ParseCtx(ctx context.Context, value interface{}) *Result {
+	return parseCtx(ctx, a, value)
+}
+
 func (a *ArraySchema) parseWithPath(value interface{}, path string) *Result {
 	// Skip all validations for null library types that are not valid
 	if isNullLibraryType(value) {
@@ -43,53 +48,29 @@ func (a *ArraySchema) parseWithPath(value interface{}, path string) *Result {
 
 	items, ok := convertToSlice(value)
 	if !ok {
-		return newResult(false, nil, []ValidationError{{
-			Path:    path,
-			Message: getMessage(msgs.TypeArray),
-			Code:    "type_error",
-		}})
+		return newResult(false, nil, []ValidationError{newError(path, "type_error", msgs.TypeArray)})
 	}
 
 	var errors []ValidationError
 
 	if a.minItems != nil && len(items) < *a.minItems {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.MinItems, *a.minItems),
-			Code:    "min_items",
-		})
+		errors = append(errors, newError(path, "min_items", msgs.MinItems, *a.minItems))
 	}
 
 	if a.maxItems != nil && len(items) > *a.maxItems {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.MaxItems, *a.maxItems),
-			Code:    "max_items",
-		})
+		errors = append(errors, newError(path, "max_items", msgs.MaxItems, *a.maxItems))
 	}
 
 	var validatedItems []interface{}
+	var pending []pendingCheck
 	for i, item := range items {
 		itemPath := fmt.Sprintf("%s[%d]", path, i)
 		if len(path) == 0 {
 			itemPath = fmt.Sprintf("[%d]", i)
 		}
 
-		var result *Result
-		if stringSchema, ok := a.itemSchema.(*StringSchema); ok {
-			result = stringSchema.parseWithPath(item, itemPath)
-		} else if numberSchema, ok := a.itemSchema.(*NumberSchema); ok {
-			result = numberSchema.parseWithPath(item, itemPath)
-		} else if objectSchema, ok := a.itemSchema.(*ObjectSchema); ok {
-			result = objectSchema.parseWithPath(item, itemPath)
-		} else if arraySchema, ok := a.itemSchema.(*ArraySchema); ok {
-			result = arraySchema.parseWithPath(item, itemPath)
-		} else {
-			result = a.itemSchema.Parse(item)
-			if len(result.Errors) > 0 {
-				result.Errors = addPathPrefix(result.Errors, itemPath)
-			}
-		}
+		result := parseAt(a.itemSchema, item, itemPath)
+		pending = append(pending, result.pending...)
 
 		if result.HasErrors() {
 			errors = append(errors, result.Errors...)
@@ -101,10 +82,10 @@ func (a *ArraySchema) parseWithPath(value interface{}, path string) *Result {
 	errors = append(errors, a.validateCustom(validatedItems, path)...)
 
 	if len(errors) > 0 {
-		return newResult(false, nil, errors)
+		return withPending(newResult(false, nil, errors), pending)
 	}
 
-	return newResult(true, validatedItems, nil)
+	return withPending(newResult(true, validatedItems, nil), pending)
 }
 
 func (a *ArraySchema) MinItems(min int) *ArraySchema {
@@ -138,6 +119,16 @@ func (a *ArraySchema) Custom(fn CustomValidatorFunc) Schema {
 	return a
 }
 
+func (a *ArraySchema) CustomCtx(fn CustomCtxValidatorFunc) Schema {
+	a.baseSchema.addCtxRule(&funcRule{fn: fn})
+	return a
+}
+
+func (a *ArraySchema) Rule(rule CtxRule) Schema {
+	a.baseSchema.addCtxRule(rule)
+	return a
+}
+
 func convertToSlice(value interface{}) ([]interface{}, bool) {
 	if value == nil {
 		return nil, false
@@ -0,0 +1,84 @@
+package valid
+
+import (
+	"context"
+)
+
+type BoolSchema struct {
+	baseSchema
+}
+
+func Bool() *BoolSchema {
+	return &BoolSchema{
+		baseSchema: baseSchema{},
+	}
+}
+
+func (b *BoolSchema) Parse(value any) *Result {
+	return b.parseWithPath(value, "")
+}
+
+func (b *BoolSchema) ParseCtx(ctx context.Context, value any) *Result {
+	return parseCtx(ctx, b, value)
+}
+
+func (b *BoolSchema) parseWithPath(value any, path string) *Result {
+	// Skip all validations for null library types that are not valid
+	if isNullLibraryType(value) {
+		return newResult(true, value, nil)
+	}
+
+	if b.optional && isNilOrEmpty(value) {
+		return newResult(true, value, nil)
+	}
+
+	if errors := b.validateRequired(value, path); len(errors) > 0 {
+		return newResult(false, nil, errors)
+	}
+
+	if isNilOrEmpty(value) && !b.required {
+		return newResult(true, value, nil)
+	}
+
+	boolean, ok := value.(bool)
+	if !ok {
+		if ptr, isPtr := value.(*bool); isPtr && ptr != nil {
+			boolean = *ptr
+		} else {
+			return newResult(false, nil, []ValidationError{newError(path, "type_error", msgs.TypeBool)})
+		}
+	}
+
+	errors := b.validateCustom(boolean, path)
+
+	if len(errors) > 0 {
+		return newResult(false, nil, errors)
+	}
+
+	return newResult(true, boolean, nil)
+}
+
+func (b *BoolSchema) Optional() Schema {
+	b.baseSchema.setOptional()
+	return b
+}
+
+func (b *BoolSchema) Required() Schema {
+	b.baseSchema.setRequired()
+	return b
+}
+
+func (b *BoolSchema) Custom(fn CustomValidatorFunc) Schema {
+	b.baseSchema.addCustom(fn)
+	return b
+}
+
+func (b *BoolSchema) CustomCtx(fn CustomCtxValidatorFunc) Schema {
+	b.baseSchema.addCtxRule(&funcRule{fn: fn})
+	return b
+}
+
+func (b *BoolSchema) Rule(rule CtxRule) Schema {
+	b.baseSchema.addCtxRule(rule)
+	return b
+}
@@ -0,0 +1,141 @@
+package valid
+
+import (
+	"context"
+	"fmt"
+)
+
+// CustomCtxValidatorFunc is a custom validator that needs the request
+// context, typically because it performs IO.
+type CustomCtxValidatorFunc func(ctx context.Context, value any) error
+
+// CtxRule is a context-aware check that can validate many values at once.
+// ParseCtx groups the pending values of every rule sharing the same Key and
+// calls Check once per group, so a list of 50 company ids costs one query.
+type CtxRule interface {
+	// Key identifies equivalent rules. Rules with the same key must accept
+	// each other's values.
+	Key() string
+	// Check returns one error per value (nil when the value is valid) in the
+	// same order as values. The second return value reports failures that
+	// prevented the check from running at all.
+	Check(ctx context.Context, values []any) ([]error, error)
+}
+
+type pendingCheck struct {
+	path  string
+	value any
+	rule  CtxRule
+}
+
+func withPending(result *Result, pending []pendingCheck) *Result {
+	result.pending = append(result.pending, pending...)
+	return result
+}
+
+// funcRule adapts a CustomCtxValidatorFunc to CtxRule. It is never batched.
+type funcRule struct {
+	fn CustomCtxValidatorFunc
+}
+
+func (f *funcRule) Key() string {
+	return fmt.Sprintf("func:%p", f)
+}
+
+func (f *funcRule) Check(ctx context.Context, values []any) ([]error, error) {
+	errs := make([]error, len(values))
+	for i, value := range values {
+		errs[i] = f.fn(ctx, value)
+	}
+	return errs, nil
+}
+
+// parseCtx runs the synchronous validation and then every pending context
+// rule, grouped by key. Rules run even when other fields failed so the
+// caller gets every error in one pass.
+func parseCtx(ctx context.Context, schema Schema, value any) *Result {
+	result := parseAt(schema, value, "")
+	if len(result.pending) == 0 {
+		return result
+	}
+
+	pending := result.pending
+	result.pending = nil
+
+	var order []string
+	groups := make(map[string][]pendingCheck)
+	for _, check := range pending {
+		key := check.rule.Key()
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], check)
+	}
+
+	var errors []ValidationError
+	for _, key := range order {
+		if err := ctx.Err(); err != nil {
+			result.Cause = err
+			break
+		}
+
+		checks := groups[key]
+		values := make([]any, len(checks))
+		for i, check := range checks {
+			values[i] = check.value
+		}
+
+		errs, err := checks[0].rule.Check(ctx, values)
+		if err != nil {
+			result.Cause = err
+			for _, check := range checks {
+				errors = append(errors, newError(check.path, "lookup_failed", msgs.LookupFailed))
+			}
+			continue
+		}
+
+		for i, check := range checks {
+			if i >= len(errs) || errs[i] == nil {
+				continue
+			}
+			errors = append(errors, toValidationError(errs[i], check.path))
+		}
+	}
+
+	if len(errors) == 0 && result.Cause == nil {
+		return result
+	}
+
+	return &Result{
+		Success: false,
+		Errors:  append(result.Errors, errors...),
+		Cause:   result.Cause,
+	}
+}
+
+// at returns a copy of the error at path, with the message of errors built
+// by NewError rendered.
+func (e ValidationError) at(path string) ValidationError {
+	e.Path = path
+	if e.Message == "" && e.key != "" {
+		e.Message = getMessage(e.key, e.args...)
+	}
+	return e
+}
+
+// toValidationError keeps the code and message key of errors built by rules
+// in this package or NewError and treats anything else as a custom error.
+func toValidationError(err error, path string) ValidationError {
+	switch e := err.(type) {
+	case ValidationError:
+		return e.at(path)
+	case *ValidationError:
+		return e.at(path)
+	default:
+		return ValidationError{
+			Path:    path,
+			Message: err.Error(),
+			Code:    "custom",
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package valid
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type fakeExecutor struct {
+	rows    map[string][]string
+	queries []string
+	args    [][]any
+	err     error
+}
+
+func (f *fakeExecutor) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("not implemented")
+}
+
+func (f *fakeExecutor) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return nil
+}
+
+func (f *fakeExecutor) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	f.queries = append(f.queries, sql)
+	f.args = append(f.args, args)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	table := strings.Fields(sql[strings.Index(sql, "FROM"):])[1]
+	var values []string
+	for _, arg := range args {
+		for _, existing := range f.rows[table] {
+			if s, ok := arg.(string); ok && s == existing {
+				values = append(values, existing)
+			}
+		}
+	}
+	return &fakeRows{values: values, index: -1}, nil
+}
+
+type fakeRows struct {
+	values []string
+	index  int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return []any{r.values[r.index]}, nil }
+
+func (r *fakeRows) Next() bool {
+	r.index++
+	return r.index < len(r.values)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	*dest[0].(*string) = r.values[r.index]
+	return nil
+}
+
+func TestParseCtx_ExistsBatchesLookups(t *testing.T) {
+	executor := &fakeExecutor{rows: map[string][]string{
+		"relationships.companies": {"0b7e7d4a-4d5e-4a57-9a43-2d0f6a1e4f10", "5f1c9f51-5d1a-4b8e-8d8b-3c2a43f0b6a2"},
+	}}
+	ctx := WithExecutor(context.Background(), executor)
+
+	schema := Array(Object(map[string]Schema{
+		"company_id": String().UUID().Required().Rule(Exists("relationships.companies", "id")),
+	}))
+
+	result := schema.ParseCtx(ctx, []map[string]any{
+		{"company_id": "0B7E7D4A-4D5E-4A57-9A43-2D0F6A1E4F10"},
+		{"company_id": "5f1c9f51-5d1a-4b8e-8d8b-3c2a43f0b6a2"},
+		{"company_id": "9a6a4cf1-3f8d-4d0e-8a55-2a9c1f7f4b11"},
+	})
+
+	if len(executor.queries) != 1 {
+		t.Fatalf("Expected one batched query, got %d: %v", len(executor.queries), executor.queries)
+	}
+	if result.Success {
+		t.Fatal("Expected validation to fail for missing company")
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Path != "[2].company_id" || result.Errors[0].Code != "exists" {
+		t.Errorf("Expected exists error at [2].company_id, got: %+v", result.Errors)
+	}
+}
+
+func TestParseCtx_Unique(t *testing.T) {
+	executor := &fakeExecutor{rows: map[string][]string{
+		"auth.email_credentials": {"taken@example.com"},
+	}}
+
+	schema := Object(map[string]Schema{
+		"email": String().Email().Required().Rule(Unique("auth.email_credentials", "email", "a3f1").CaseInsensitive().Using(executor)),
+	})
+
+	result := schema.ParseCtx(context.Background(), map[string]any{"email": "Taken@Example.com"})
+	if result.Success || result.Errors[0].Code != "unique" {
+		t.Errorf("Expected unique error, got: %+v", result)
+	}
+
+	sql := executor.queries[0]
+	if !strings.Contains(sql, "lower(email) IN ($1)") || !strings.Contains(sql, "id <> $2") {
+		t.Errorf("Unexpected query: %s", sql)
+	}
+
+	result = schema.ParseCtx(context.Background(), map[string]any{"email": "free@example.com"})
+	if !result.Success {
+		t.Errorf("Expected validation to pass, got errors: %v", result.Errors)
+	}
+}
+
+func TestParseCtx_ScopedExists(t *testing.T) {
+	executor := &fakeExecutor{}
+	ctx := WithExecutor(context.Background(), executor)
+
+	schema := Enum("CUSTOMER", "SUPPLIER").Rule(
+		Exists("config.catalog_options", "value").Where("catalog_type_id", 7).WhereNull("deleted_at"),
+	)
+	schema.ParseCtx(ctx, "CUSTOMER")
+
+	want := "SELECT value::text FROM config.catalog_options WHERE value IN ($1) AND catalog_type_id = $2 AND deleted_at IS NULL"
+	if executor.queries[0] != want {
+		t.Errorf("Expected query %q, got %q", want, executor.queries[0])
+	}
+}
+
+func TestParseCtx_SkipsEmptyOptionalValues(t *testing.T) {
+	executor := &fakeExecutor{}
+	ctx := WithExecutor(context.Background(), executor)
+
+	schema := Object(map[string]Schema{
+		"currency_code": CurrencyCode().Optional().Rule(Exists("billing.currencies", "code")),
+	})
+
+	result := schema.ParseCtx(ctx, map[string]any{})
+	if !result.Success {
+		t.Errorf("Expected validation to pass, got errors: %v", result.Errors)
+	}
+	if len(executor.queries) != 0 {
+		t.Errorf("Expected no queries for empty value, got %v", executor.queries)
+	}
+}
+
+func TestParseCtx_LookupFailure(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	ctx := WithExecutor(context.Background(), &fakeExecutor{err: dbErr})
+
+	result := String().Rule(Exists("billing.currencies", "code")).ParseCtx(ctx, "PEN")
+	if result.Success || !errors.Is(result.Cause, dbErr) {
+		t.Errorf("Expected lookup failure with cause, got: %+v", result)
+	}
+
+	result = String().Rule(Exists("billing.currencies", "code")).ParseCtx(context.Background(), "PEN")
+	if !errors.Is(result.Cause, ErrNoExecutor) {
+		t.Errorf("Expected ErrNoExecutor, got: %v", result.Cause)
+	}
+}
+
+func TestCustomCtx(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "reserved")
+
+	schema := String().Required().CustomCtx(func(ctx context.Context, value any) error {
+		if value == ctx.Value(ctxKey{}) {
+			return errors.New("this name is reserved")
+		}
+		return nil
+	})
+
+	result := schema.ParseCtx(ctx, "reserved")
+	if result.Success || result.Errors[0].Message != "this name is reserved" {
+		t.Errorf("Expected custom ctx error, got: %+v", result)
+	}
+
+	result = schema.Parse("reserved")
+	if !result.Success {
+		t.Error("Expected Parse to skip context validators")
+	}
+}
+
+func TestExists_InvalidIdentifier(t *testing.T) {
+	executor := &fakeExecutor{}
+	ctx := WithExecutor(context.Background(), executor)
+
+	schema := String().Rule(Exists("users; DROP TABLE users", "id"))
+	result := schema.ParseCtx(ctx, "1")
+	if result.Success || result.Cause == nil || !strings.Contains(result.Cause.Error(), "invalid SQL identifier") {
+		t.Errorf("Expected invalid identifier cause, got: %+v", result)
+	}
+
+	result = String().Rule(Exists("billing.currencies", "code").Where("code = code OR true", 1)).ParseCtx(ctx, "PEN")
+	if result.Success || result.Cause == nil {
+		t.Errorf("Expected invalid identifier cause, got: %+v", result)
+	}
+
+	if len(executor.queries) != 0 {
+		t.Errorf("Expected no queries, got %v", executor.queries)
+	}
+}
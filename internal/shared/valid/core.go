@@ -1,36 +1,87 @@
 package valid
 
 import (
+	"context"
 	"reflect"
 
 	"github.com/google/uuid"
 	"gopkg.in/guregu/null.v4"
+
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
 )
 
 type Schema interface {
 	Parse(value any) *Result
+	ParseCtx(ctx context.Context, value any) *Result
 	Optional() Schema
 	Required() Schema
 	Custom(fn CustomValidatorFunc) Schema
+	CustomCtx(fn CustomCtxValidatorFunc) Schema
+	Rule(rule CtxRule) Schema
+	JSONSchema() map[string]any
 }
 
 type Result struct {
 	Success bool
 	Data    any
 	Errors  []ValidationError
+
+	// Cause holds the infrastructure error that prevented a CtxRule from
+	// completing, e.g. a database failure. It is only set by ParseCtx.
+	Cause error
+
+	// pending holds the context-aware checks collected during parsing
+	pending []pendingCheck
 }
 
 type ValidationError struct {
 	Path    string
 	Message string
 	Code    string
+
+	// key and args let the message be rendered again in another language
+	key  string
+	args []any
+}
+
+func newError(path, code, key string, args ...any) ValidationError {
+	return ValidationError{
+		Path:    path,
+		Message: getMessage(key, args...),
+		Code:    code,
+		key:     key,
+		args:    args,
+	}
+}
+
+// NewError returns an error whose message is the template of key in the
+// i18n catalog, so custom validators can report messages that are
+// localized like the ones of the built-in rules. The message is rendered
+// when the error is returned by Parse or printed.
+func NewError(code, key string, args ...any) *ValidationError {
+	return &ValidationError{Code: code, key: key, args: args}
+}
+
+// Localize returns a copy of the error with its message rendered in lang.
+// Errors raised by custom validators are returned unchanged, unless they
+// were built by NewError.
+func (e ValidationError) Localize(lang Language) ValidationError {
+	if e.key == "" {
+		return e
+	}
+	e.Message = i18n.Default.Translate(lang, e.key, e.args...)
+	return e
 }
 
 func (e ValidationError) Error() string {
+	message := e.Message
+	if message == "" && e.key != "" {
+		message = getMessage(e.key, e.args...)
+	}
 	if e.Path != "" {
-		return e.Path + ": " + e.Message
+		return e.Path + ": " + message
 	}
-	return e.Message
+	return message
 }
 
 type CustomValidatorFunc func(value any) error
@@ -39,6 +90,7 @@ type baseSchema struct {
 	optional         bool
 	required         bool
 	customValidators []CustomValidatorFunc
+	ctxRules         []CtxRule
 }
 
 func (b *baseSchema) setOptional() {
@@ -55,13 +107,17 @@ func (b *baseSchema) addCustom(fn CustomValidatorFunc) {
 	b.customValidators = append(b.customValidators, fn)
 }
 
+func (b *baseSchema) addCtxRule(rule CtxRule) {
+	b.ctxRules = append(b.ctxRules, rule)
+}
+
+func (b *baseSchema) rules() []CtxRule {
+	return b.ctxRules
+}
+
 func (b *baseSchema) validateRequired(value any, path string) []ValidationError {
 	if b.required && isNilOrEmpty(value) {
-		return []ValidationError{{
-			Path:    path,
-			Message: getMessage(msgs.Required),
-			Code:    "required",
-		}}
+		return []ValidationError{newError(path, "required", msgs.Required)}
 	}
 	return nil
 }
@@ -70,11 +126,7 @@ func (b *baseSchema) validateCustom(value any, path string) []ValidationError {
 	var errors []ValidationError
 	for _, validator := range b.customValidators {
 		if err := validator(value); err != nil {
-			errors = append(errors, ValidationError{
-				Path:    path,
-				Message: err.Error(),
-				Code:    "custom",
-			})
+			errors = append(errors, toValidationError(err, path))
 		}
 	}
 	return errors
@@ -140,3 +192,46 @@ func newResult(success bool, data any, errors []ValidationError) *Result {
 		Errors:  errors,
 	}
 }
+
+// parseAt validates value with schema, reporting errors under path. When
+// the value passes, the schema context rules are queued for ParseCtx.
+func parseAt(schema Schema, value any, path string) *Result {
+	result := dispatch(schema, value, path)
+
+	if r, ok := schema.(interface{ rules() []CtxRule }); ok && result.Success && !isNilOrEmpty(result.Data) {
+		for _, rule := range r.rules() {
+			result.pending = append(result.pending, pendingCheck{path: path, value: result.Data, rule: rule})
+		}
+	}
+
+	return result
+}
+
+func dispatch(schema Schema, value any, path string) *Result {
+	switch s := schema.(type) {
+	case *StringSchema:
+		return s.parseWithPath(value, path)
+	case *NumberSchema:
+		return s.parseWithPath(value, path)
+	case *ObjectSchema:
+		return s.parseWithPath(value, path)
+	case *ArraySchema:
+		return s.parseWithPath(value, path)
+	case *DateSchema:
+		return s.parseWithPath(value, path)
+	case *DecimalSchema:
+		return s.parseWithPath(value, path)
+	case *EnumSchema:
+		return s.parseWithPath(value, path)
+	case *BoolSchema:
+		return s.parseWithPath(value, path)
+	case *RecordSchema:
+		return s.parseWithPath(value, path)
+	default:
+		result := schema.Parse(value)
+		if len(result.Errors) > 0 {
+			result.Errors = addPathPrefix(result.Errors, path)
+		}
+		return result
+	}
+}
@@ -0,0 +1,245 @@
+package valid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"api.system.soluciones-cloud.com/internal/shared/database"
+)
+
+// ErrNoExecutor is the cause of database rules run without an executor.
+var ErrNoExecutor = errors.New("valid: no database executor in context, use valid.WithExecutor")
+
+var identifierRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+type executorKey struct{}
+
+// WithExecutor returns a copy of ctx carrying the executor used by Exists
+// and Unique. middleware.Database stores the pool in every request; pass
+// the transaction executor to validate inside a unit of work.
+func WithExecutor(ctx context.Context, executor database.Executor) context.Context {
+	return context.WithValue(ctx, executorKey{}, executor)
+}
+
+func executorFromContext(ctx context.Context) (database.Executor, bool) {
+	executor, ok := ctx.Value(executorKey{}).(database.Executor)
+	return executor, ok && executor != nil
+}
+
+type condition struct {
+	column string
+	value  any
+	isNull bool
+}
+
+// DatabaseRule checks values against a table column. Build it with Exists
+// or Unique.
+type DatabaseRule struct {
+	table           string
+	column          string
+	unique          bool
+	excludeID       any
+	conditions      []condition
+	caseInsensitive bool
+	executor        database.Executor
+	// err reports an invalid table or column name. Check returns it, so
+	// ParseCtx fails with it as the cause instead of running the query.
+	err error
+}
+
+// Exists requires the value to be present in table.column, e.g. an existing
+// company_id or a currency code in billing.currencies.
+func Exists(table, column string) *DatabaseRule {
+	return newDatabaseRule(table, column, false, nil)
+}
+
+// Unique requires the value to be absent from table.column. Rows whose id
+// equals excludeID are ignored so updates can keep their own value; pass nil
+// when creating.
+func Unique(table, column string, excludeID any) *DatabaseRule {
+	return newDatabaseRule(table, column, true, excludeID)
+}
+
+func newDatabaseRule(table, column string, unique bool, excludeID any) *DatabaseRule {
+	rule := &DatabaseRule{
+		table:     table,
+		column:    column,
+		unique:    unique,
+		excludeID: excludeID,
+	}
+	rule.checkIdentifier(table)
+	rule.checkIdentifier(column)
+	return rule
+}
+
+// Where adds an equality condition, e.g. Where("catalog_type_id", id) to
+// scope catalog options to their catalog type.
+func (d *DatabaseRule) Where(column string, value any) *DatabaseRule {
+	d.checkIdentifier(column)
+	d.conditions = append(d.conditions, condition{column: column, value: value})
+	return d
+}
+
+// WhereNull adds an IS NULL condition, e.g. WhereNull("deleted_at") to ignore
+// soft-deleted rows.
+func (d *DatabaseRule) WhereNull(column string) *DatabaseRule {
+	d.checkIdentifier(column)
+	d.conditions = append(d.conditions, condition{column: column, isNull: true})
+	return d
+}
+
+// CaseInsensitive compares lower(column) with the lowercased value.
+func (d *DatabaseRule) CaseInsensitive() *DatabaseRule {
+	d.caseInsensitive = true
+	return d
+}
+
+// Using sets the executor explicitly instead of reading it from the context.
+func (d *DatabaseRule) Using(executor database.Executor) *DatabaseRule {
+	d.executor = executor
+	return d
+}
+
+func (d *DatabaseRule) Key() string {
+	kind := "exists"
+	if d.unique {
+		kind = "unique"
+	}
+
+	key := fmt.Sprintf("%s:%s.%s:ci=%t:exclude=%v", kind, d.table, d.column, d.caseInsensitive, d.excludeID)
+	for _, c := range d.conditions {
+		if c.isNull {
+			key += fmt.Sprintf(":%s=null", c.column)
+		} else {
+			key += fmt.Sprintf(":%s=%v", c.column, c.value)
+		}
+	}
+	if d.executor != nil {
+		key += fmt.Sprintf(":executor=%p", d.executor)
+	}
+	return key
+}
+
+func (d *DatabaseRule) Check(ctx context.Context, values []any) ([]error, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	executor := d.executor
+	if executor == nil {
+		var ok bool
+		if executor, ok = executorFromContext(ctx); !ok {
+			return nil, ErrNoExecutor
+		}
+	}
+
+	normalized := make([]string, len(values))
+	distinct := make([]any, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for i, value := range values {
+		normalized[i] = d.normalize(value)
+		if _, ok := seen[normalized[i]]; ok {
+			continue
+		}
+		seen[normalized[i]] = struct{}{}
+		distinct = append(distinct, normalized[i])
+	}
+
+	sql, args := d.buildQuery(distinct)
+	rows, err := executor.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]struct{}, len(found))
+	for _, value := range found {
+		existing[d.normalize(value)] = struct{}{}
+	}
+
+	errs := make([]error, len(values))
+	for i := range values {
+		_, exists := existing[normalized[i]]
+		switch {
+		case d.unique && exists:
+			errs[i] = newError("", "unique", msgs.Unique)
+		case !d.unique && !exists:
+			errs[i] = newError("", "exists", msgs.Exists)
+		}
+	}
+	return errs, nil
+}
+
+func (d *DatabaseRule) buildQuery(values []any) (string, []any) {
+	column := d.column
+	if d.caseInsensitive {
+		column = "lower(" + d.column + ")"
+	}
+
+	args := make([]any, 0, len(values)+len(d.conditions)+1)
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		args = append(args, value)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", "))}
+	for _, c := range d.conditions {
+		if c.isNull {
+			where = append(where, c.column+" IS NULL")
+			continue
+		}
+		args = append(args, c.value)
+		where = append(where, fmt.Sprintf("%s = $%d", c.column, len(args)))
+	}
+
+	if d.unique && d.excludeID != nil {
+		args = append(args, d.excludeID)
+		where = append(where, fmt.Sprintf("id <> $%d", len(args)))
+	}
+
+	sql := fmt.Sprintf("SELECT %s::text FROM %s WHERE %s", column, d.table, strings.Join(where, " AND "))
+	return sql, args
+}
+
+// normalize turns values into the text form PostgreSQL returns so results
+// can be matched back to their inputs.
+func (d *DatabaseRule) normalize(value any) string {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case uuid.UUID:
+		str = v.String()
+	case fmt.Stringer:
+		str = v.String()
+	default:
+		str = fmt.Sprint(v)
+	}
+
+	if id, err := uuid.Parse(str); err == nil {
+		return id.String()
+	}
+	if d.caseInsensitive {
+		return strings.ToLower(str)
+	}
+	return str
+}
+
+// checkIdentifier records an error for names that are not plain SQL
+// identifiers, since they are written into the query
+func (d *DatabaseRule) checkIdentifier(name string) {
+	if d.err == nil && !identifierRegex.MatchString(name) {
+		d.err = fmt.Errorf("valid: invalid SQL identifier %q", name)
+	}
+}
@@ -0,0 +1,191 @@
+package valid
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DateLayout = time.DateOnly
+	TimeLayout = time.RFC3339
+)
+
+type DateSchema struct {
+	baseSchema
+	layout string
+	min    *time.Time
+	max    *time.Time
+	before *time.Time
+	after  *time.Time
+}
+
+// Date validates calendar dates such as "2025-07-05", matching DATE columns.
+func Date() *DateSchema {
+	return &DateSchema{
+		baseSchema: baseSchema{},
+		layout:     DateLayout,
+	}
+}
+
+// Time validates RFC 3339 timestamps, matching TIMESTAMPTZ columns.
+func Time() *DateSchema {
+	return &DateSchema{
+		baseSchema: baseSchema{},
+		layout:     TimeLayout,
+	}
+}
+
+func (d *DateSchema) Parse(value any) *Result {
+	return d.parseWithPath(value, "")
+}
+
+func (d *DateSchema) ParseCtx(ctx context.Context, value any) *Result {
+	return parseCtx(ctx, d, value)
+}
+
+func (d *DateSchema) parseWithPath(value any, path string) *Result {
+	// Skip all validations for null library types that are not valid
+	if isNullLibraryType(value) {
+		return newResult(true, value, nil)
+	}
+
+	if d.optional && isNilOrEmpty(value) {
+		return newResult(true, value, nil)
+	}
+
+	if errors := d.validateRequired(value, path); len(errors) > 0 {
+		return newResult(false, nil, errors)
+	}
+
+	if isNilOrEmpty(value) && !d.required {
+		return newResult(true, value, nil)
+	}
+
+	t, ok := d.convertToTime(value)
+	if !ok {
+		return newResult(false, nil, []ValidationError{newError(path, "type_error", msgs.TypeDate)})
+	}
+
+	var errors []ValidationError
+
+	if d.min != nil && t.Before(*d.min) {
+		errors = append(errors, newError(path, "min_date", msgs.MinDate, d.format(*d.min)))
+	}
+
+	if d.max != nil && t.After(*d.max) {
+		errors = append(errors, newError(path, "max_date", msgs.MaxDate, d.format(*d.max)))
+	}
+
+	if d.before != nil && !t.Before(*d.before) {
+		errors = append(errors, newError(path, "before", msgs.BeforeDate, d.format(*d.before)))
+	}
+
+	if d.after != nil && !t.After(*d.after) {
+		errors = append(errors, newError(path, "after", msgs.AfterDate, d.format(*d.after)))
+	}
+
+	errors = append(errors, d.validateCustom(t, path)...)
+
+	if len(errors) > 0 {
+		return newResult(false, nil, errors)
+	}
+
+	return newResult(true, t, nil)
+}
+
+// Min requires the value to be on or after min.
+func (d *DateSchema) Min(min time.Time) *DateSchema {
+	min = d.truncate(min)
+	d.min = &min
+	return d
+}
+
+// Max requires the value to be on or before max.
+func (d *DateSchema) Max(max time.Time) *DateSchema {
+	max = d.truncate(max)
+	d.max = &max
+	return d
+}
+
+// Before requires the value to be strictly before t.
+func (d *DateSchema) Before(t time.Time) *DateSchema {
+	t = d.truncate(t)
+	d.before = &t
+	return d
+}
+
+// After requires the value to be strictly after t.
+func (d *DateSchema) After(t time.Time) *DateSchema {
+	t = d.truncate(t)
+	d.after = &t
+	return d
+}
+
+// Layout overrides the layout used to parse string values.
+func (d *DateSchema) Layout(layout string) *DateSchema {
+	d.layout = layout
+	return d
+}
+
+func (d *DateSchema) Optional() Schema {
+	d.baseSchema.setOptional()
+	return d
+}
+
+func (d *DateSchema) Required() Schema {
+	d.baseSchema.setRequired()
+	return d
+}
+
+func (d *DateSchema) Custom(fn CustomValidatorFunc) Schema {
+	d.baseSchema.addCustom(fn)
+	return d
+}
+
+func (d *DateSchema) CustomCtx(fn CustomCtxValidatorFunc) Schema {
+	d.baseSchema.addCtxRule(&funcRule{fn: fn})
+	return d
+}
+
+func (d *DateSchema) Rule(rule CtxRule) Schema {
+	d.baseSchema.addCtxRule(rule)
+	return d
+}
+
+func (d *DateSchema) convertToTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return d.truncate(v), true
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return d.truncate(*v), true
+	case string:
+		t, err := time.Parse(d.layout, v)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return d.truncate(t), true
+	case *string:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return d.convertToTime(*v)
+	default:
+		return time.Time{}, false
+	}
+}
+
+// truncate drops the clock for date-only schemas so comparisons are per day.
+func (d *DateSchema) truncate(t time.Time) time.Time {
+	if d.layout != DateLayout {
+		return t
+	}
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func (d *DateSchema) format(t time.Time) string {
+	return t.Format(d.layout)
+}
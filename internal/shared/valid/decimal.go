@@ -0,0 +1,228 @@
+package valid
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var decimalRegex = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+
+type DecimalSchema struct {
+	baseSchema
+	precision *int
+	scale     *int
+	min       *float64
+	max       *float64
+	positive  bool
+}
+
+// Decimal validates exact numeric values such as NUMERIC(12,4) columns.
+// Strings and json.Number are checked digit by digit; floats are checked
+// using their shortest decimal representation.
+func Decimal() *DecimalSchema {
+	return &DecimalSchema{
+		baseSchema: baseSchema{},
+	}
+}
+
+func (d *DecimalSchema) Parse(value any) *Result {
+	return d.parseWithPath(value, "")
+}
+
+func (d *DecimalSchema) ParseCtx(ctx context.Context, value any) *Result {
+	return parseCtx(ctx, d, value)
+}
+
+func (d *DecimalSchema) parseWithPath(value any, path string) *Result {
+	// Skip all validations for null library types that are not valid
+	if isNullLibraryType(value) {
+		return newResult(true, value, nil)
+	}
+
+	if d.optional && isNilOrEmpty(value) {
+		return newResult(true, value, nil)
+	}
+
+	if errors := d.validateRequired(value, path); len(errors) > 0 {
+		return newResult(false, nil, errors)
+	}
+
+	if isNilOrEmpty(value) && !d.required {
+		return newResult(true, value, nil)
+	}
+
+	str, ok := convertToDecimalString(value)
+	if !ok {
+		return newResult(false, nil, []ValidationError{newError(path, "type_error", msgs.TypeDecimal)})
+	}
+
+	var errors []ValidationError
+
+	integerDigits, fractionDigits := countDecimalDigits(str)
+
+	scale := d.effectiveScale()
+	if scale != nil && fractionDigits > *scale {
+		errors = append(errors, newError(path, "scale", msgs.Scale, *scale))
+	}
+
+	if d.precision != nil {
+		if maxIntegerDigits := *d.precision - *scale; integerDigits > maxIntegerDigits {
+			errors = append(errors, newError(path, "precision", msgs.Precision, maxIntegerDigits))
+		}
+	}
+
+	// Bounds are compared as exact decimals, so values such as
+	// "0.30000000000000001" are not rounded into range
+	num, _ := new(big.Rat).SetString(str)
+
+	if d.positive && num.Sign() <= 0 {
+		errors = append(errors, newError(path, "positive", msgs.Positive))
+	}
+
+	if d.min != nil && num.Cmp(exactDecimal(*d.min)) < 0 {
+		errors = append(errors, newError(path, "min", msgs.Min, *d.min))
+	}
+
+	if d.max != nil && num.Cmp(exactDecimal(*d.max)) > 0 {
+		errors = append(errors, newError(path, "max", msgs.Max, *d.max))
+	}
+
+	errors = append(errors, d.validateCustom(value, path)...)
+
+	if len(errors) > 0 {
+		return newResult(false, nil, errors)
+	}
+
+	return newResult(true, value, nil)
+}
+
+// Precision sets the total number of significant digits, as in NUMERIC(p, s).
+// Without a Scale it mirrors NUMERIC(p), whose scale is 0, so values with
+// fraction digits are rejected instead of rounded.
+func (d *DecimalSchema) Precision(precision int) *DecimalSchema {
+	d.precision = &precision
+	return d
+}
+
+// Scale sets the number of digits allowed after the decimal point.
+func (d *DecimalSchema) Scale(scale int) *DecimalSchema {
+	d.scale = &scale
+	return d
+}
+
+// Numeric sets precision and scale at once, mirroring NUMERIC(p, s).
+func (d *DecimalSchema) Numeric(precision, scale int) *DecimalSchema {
+	d.precision = &precision
+	d.scale = &scale
+	return d
+}
+
+// effectiveScale returns the scale fraction digits are limited to: the one
+// set, 0 when only the precision is set, or nil when neither is
+func (d *DecimalSchema) effectiveScale() *int {
+	if d.scale == nil && d.precision != nil {
+		zero := 0
+		return &zero
+	}
+	return d.scale
+}
+
+// Min and Max bound the value. Bounds are taken by their shortest decimal
+// representation, so Min(0.1) means exactly 0.1.
+func (d *DecimalSchema) Min(min float64) *DecimalSchema {
+	d.min = &min
+	return d
+}
+
+func (d *DecimalSchema) Max(max float64) *DecimalSchema {
+	d.max = &max
+	return d
+}
+
+func (d *DecimalSchema) Positive() *DecimalSchema {
+	d.positive = true
+	return d
+}
+
+func (d *DecimalSchema) Optional() Schema {
+	d.baseSchema.setOptional()
+	return d
+}
+
+func (d *DecimalSchema) Required() Schema {
+	d.baseSchema.setRequired()
+	return d
+}
+
+func (d *DecimalSchema) Custom(fn CustomValidatorFunc) Schema {
+	d.baseSchema.addCustom(fn)
+	return d
+}
+
+func (d *DecimalSchema) CustomCtx(fn CustomCtxValidatorFunc) Schema {
+	d.baseSchema.addCtxRule(&funcRule{fn: fn})
+	return d
+}
+
+func (d *DecimalSchema) Rule(rule CtxRule) Schema {
+	d.baseSchema.addCtxRule(rule)
+	return d
+}
+
+func convertToDecimalString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, decimalRegex.MatchString(v)
+	case *string:
+		if v == nil {
+			return "", false
+		}
+		return *v, decimalRegex.MatchString(*v)
+	case json.Number:
+		str := v.String()
+		return str, decimalRegex.MatchString(str)
+	}
+
+	val := reflect.ValueOf(value)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return "", false
+		}
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// exactDecimal returns f as the decimal it is written as, e.g. 0.1 and not
+// its binary approximation
+func exactDecimal(f float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	return r
+}
+
+// countDecimalDigits ignores sign, leading integer zeros and trailing
+// fraction zeros, the same way PostgreSQL counts digits for NUMERIC(p, s).
+func countDecimalDigits(str string) (int, int) {
+	str = strings.TrimLeft(str, "+-")
+	integerPart, fractionPart, _ := strings.Cut(str, ".")
+	integerPart = strings.TrimLeft(integerPart, "0")
+	fractionPart = strings.TrimRight(fractionPart, "0")
+	return len(integerPart), len(fractionPart)
+}
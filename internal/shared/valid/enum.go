@@ -0,0 +1,118 @@
+package valid
+
+import (
+	"context"
+	"strings"
+)
+
+type EnumSchema struct {
+	baseSchema
+	values          []string
+	caseInsensitive bool
+}
+
+// Enum validates that a string is one of the given values, e.g. the
+// values of a config.catalog_options group.
+func Enum(values ...string) *EnumSchema {
+	return &EnumSchema{
+		baseSchema: baseSchema{},
+		values:     values,
+	}
+}
+
+func (e *EnumSchema) Parse(value any) *Result {
+	return e.parseWithPath(value, "")
+}
+
+func (e *EnumSchema) ParseCtx(ctx context.Context, value any) *Result {
+	return parseCtx(ctx, e, value)
+}
+
+func (e *EnumSchema) parseWithPath(value any, path string) *Result {
+	// Skip all validations for null library types that are not valid
+	if isNullLibraryType(value) {
+		return newResult(true, value, nil)
+	}
+
+	if e.optional && isNilOrEmpty(value) {
+		return newResult(true, value, nil)
+	}
+
+	if errors := e.validateRequired(value, path); len(errors) > 0 {
+		return newResult(false, nil, errors)
+	}
+
+	if isNilOrEmpty(value) && !e.required {
+		return newResult(true, value, nil)
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		if ptr, isPtr := value.(*string); isPtr && ptr != nil {
+			str = *ptr
+		} else {
+			return newResult(false, nil, []ValidationError{newError(path, "type_error", msgs.TypeString)})
+		}
+	}
+
+	var errors []ValidationError
+
+	matched, ok := e.match(str)
+	if !ok {
+		errors = append(errors, newError(path, "enum", msgs.Enum, strings.Join(e.values, ", ")))
+	}
+
+	errors = append(errors, e.validateCustom(matched, path)...)
+
+	if len(errors) > 0 {
+		return newResult(false, nil, errors)
+	}
+
+	return newResult(true, matched, nil)
+}
+
+// CaseInsensitive accepts values regardless of case and returns the
+// canonical spelling declared in Enum.
+func (e *EnumSchema) CaseInsensitive() *EnumSchema {
+	e.caseInsensitive = true
+	return e
+}
+
+// Values returns the allowed values.
+func (e *EnumSchema) Values() []string {
+	return e.values
+}
+
+func (e *EnumSchema) Optional() Schema {
+	e.baseSchema.setOptional()
+	return e
+}
+
+func (e *EnumSchema) Required() Schema {
+	e.baseSchema.setRequired()
+	return e
+}
+
+func (e *EnumSchema) Custom(fn CustomValidatorFunc) Schema {
+	e.baseSchema.addCustom(fn)
+	return e
+}
+
+func (e *EnumSchema) CustomCtx(fn CustomCtxValidatorFunc) Schema {
+	e.baseSchema.addCtxRule(&funcRule{fn: fn})
+	return e
+}
+
+func (e *EnumSchema) Rule(rule CtxRule) Schema {
+	e.baseSchema.addCtxRule(rule)
+	return e
+}
+
+func (e *EnumSchema) match(str string) (string, bool) {
+	for _, v := range e.values {
+		if v == str || (e.caseInsensitive && strings.EqualFold(v, str)) {
+			return v, true
+		}
+	}
+	return str, false
+}
@@ -1,8 +1,11 @@
 package valid
 
 import (
+	"context"
 	"fmt"
 	"strings"
+
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
 )
 
 func (r *Result) Error() string {
@@ -40,6 +43,24 @@ func (r *Result) GetErrorsForField(field string) []ValidationError {
 	return errors
 }
 
+// Localize returns a copy of the result with every error message rendered
+// in lang.
+func (r *Result) Localize(lang Language) *Result {
+	localized := &Result{
+		Success: r.Success,
+		Data:    r.Data,
+	}
+	for _, err := range r.Errors {
+		localized.Errors = append(localized.Errors, err.Localize(lang))
+	}
+	return localized
+}
+
+// LocalizeCtx is Localize using the language carried by ctx.
+func (r *Result) LocalizeCtx(ctx context.Context) *Result {
+	return r.Localize(i18n.FromContext(ctx))
+}
+
 func combineResults(results ...*Result) *Result {
 	var allErrors []ValidationError
 	var lastData interface{}
@@ -67,11 +88,8 @@ func addPathPrefix(errors []ValidationError, prefix string) []ValidationError {
 		if err.Path != "" {
 			newPath = prefix + "." + err.Path
 		}
-		prefixedErrors = append(prefixedErrors, ValidationError{
-			Path:    newPath,
-			Message: err.Message,
-			Code:    err.Code,
-		})
+		err.Path = newPath
+		prefixedErrors = append(prefixedErrors, err)
 	}
 	return prefixedErrors
 }
\ No newline at end of file
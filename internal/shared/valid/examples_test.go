@@ -37,18 +37,11 @@ func Example() {
 	}
 }
 
-func ExampleSetLanguage() {
-	SetLanguage(Spanish)
-
+func ExampleResult_Localize() {
 	result := String().Email().Required().Parse("")
 	if !result.Success {
-		_ = result.Error() // "Este campo es obligatorio"
-	}
-
-	SetLanguage(English)
-	result = String().Email().Required().Parse("")
-	if !result.Success {
-		_ = result.Error() // "This field is required"
+		_ = result.Localize(Spanish).Error() // "Este campo es obligatorio"
+		_ = result.Localize(English).Error() // "This field is required"
 	}
 }
 
@@ -140,6 +133,24 @@ func ExampleObject_reusingSchemas() {
 	_, _ = result1.Success, result2.Success
 }
 
+func ExampleDecimal() {
+	priceSchema := Object(map[string]Schema{
+		"amount":       Decimal().Numeric(12, 4).Positive().Required(),
+		"currency":     CurrencyCode().Required(),
+		"billing_date": Date().Required(),
+		"status":       Enum("DRAFT", "ISSUED", "PAID").Required(),
+		"metadata":     Record(String()).Optional(),
+	})
+
+	result := priceSchema.Parse(map[string]interface{}{
+		"amount":       "1250.5000",
+		"currency":     "PEN",
+		"billing_date": "2025-07-05",
+		"status":       "ISSUED",
+	})
+	_ = result.Success
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
\ No newline at end of file
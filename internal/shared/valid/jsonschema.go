@@ -0,0 +1,210 @@
+package valid
+
+import (
+	"fmt"
+	"sort"
+)
+
+// JSONSchemaDialect is the JSON Schema version produced by JSONSchema.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchemaDocument returns the JSON Schema of schema as a standalone
+// document, including the $schema keyword.
+func JSONSchemaDocument(schema Schema) map[string]any {
+	document := schema.JSONSchema()
+	document["$schema"] = JSONSchemaDialect
+	return document
+}
+
+func (b *baseSchema) isRequired() bool {
+	return b.required
+}
+
+func (s *StringSchema) JSONSchema() map[string]any {
+	schema := map[string]any{"type": "string"}
+
+	if s.minLength != nil {
+		schema["minLength"] = *s.minLength
+	}
+	if s.maxLength != nil {
+		schema["maxLength"] = *s.maxLength
+	}
+	if s.pattern != nil {
+		schema["pattern"] = s.pattern.String()
+	}
+
+	switch {
+	case s.email:
+		schema["format"] = "email"
+	case s.url:
+		schema["format"] = "uri"
+	case s.uuid:
+		schema["format"] = "uuid"
+	case s.currency:
+		schema["pattern"] = "^[A-Z]{3}$"
+		schema["description"] = "ISO 4217 currency code"
+	}
+
+	return schema
+}
+
+func (n *NumberSchema) JSONSchema() map[string]any {
+	schema := map[string]any{"type": "number"}
+	if n.integer {
+		schema["type"] = "integer"
+	}
+
+	if n.min != nil {
+		schema["minimum"] = *n.min
+	}
+	if n.max != nil {
+		schema["maximum"] = *n.max
+	}
+	if n.positive {
+		schema["exclusiveMinimum"] = 0
+	}
+	if n.negative {
+		schema["exclusiveMaximum"] = 0
+	}
+
+	return schema
+}
+
+func (o *ObjectSchema) JSONSchema() map[string]any {
+	names := make([]string, 0, len(o.fields))
+	for name := range o.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties := make(map[string]any, len(o.fields))
+	var required []string
+	for _, name := range names {
+		field := o.fields[name]
+		properties[name] = field.JSONSchema()
+		if r, ok := field.(interface{ isRequired() bool }); ok && r.isRequired() {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+func (a *ArraySchema) JSONSchema() map[string]any {
+	schema := map[string]any{
+		"type":  "array",
+		"items": a.itemSchema.JSONSchema(),
+	}
+
+	if a.minItems != nil {
+		schema["minItems"] = *a.minItems
+	}
+	if a.maxItems != nil {
+		schema["maxItems"] = *a.maxItems
+	}
+
+	return schema
+}
+
+func (d *DateSchema) JSONSchema() map[string]any {
+	schema := map[string]any{"type": "string"}
+
+	switch d.layout {
+	case DateLayout:
+		schema["format"] = "date"
+	case TimeLayout:
+		schema["format"] = "date-time"
+	default:
+		schema["description"] = fmt.Sprintf("Date in Go layout %q", d.layout)
+	}
+
+	// JSON Schema has no keyword for date bounds, so they are documented
+	// as annotations that generators and readers can use.
+	if d.min != nil {
+		schema["x-minimum"] = d.format(*d.min)
+	}
+	if d.max != nil {
+		schema["x-maximum"] = d.format(*d.max)
+	}
+	if d.after != nil {
+		schema["x-exclusiveMinimum"] = d.format(*d.after)
+	}
+	if d.before != nil {
+		schema["x-exclusiveMaximum"] = d.format(*d.before)
+	}
+
+	return schema
+}
+
+func (d *DecimalSchema) JSONSchema() map[string]any {
+	schema := map[string]any{"type": []string{"string", "number"}}
+
+	scale := d.effectiveScale()
+
+	integerDigits := "+"
+	if d.precision != nil {
+		integerDigits = fmt.Sprintf("{1,%d}", max(*d.precision-*scale, 1))
+	}
+
+	fraction := `(\.\d+)?`
+	if scale != nil {
+		fraction = fmt.Sprintf(`(\.\d{1,%d})?`, *scale)
+		if *scale == 0 {
+			fraction = ""
+		}
+	}
+	schema["pattern"] = fmt.Sprintf(`^[+-]?\d%s%s$`, integerDigits, fraction)
+
+	if d.min != nil {
+		schema["minimum"] = *d.min
+	}
+	if d.max != nil {
+		schema["maximum"] = *d.max
+	}
+	if d.positive {
+		schema["exclusiveMinimum"] = 0
+	}
+
+	return schema
+}
+
+func (e *EnumSchema) JSONSchema() map[string]any {
+	values := make([]string, len(e.values))
+	copy(values, e.values)
+
+	return map[string]any{
+		"type": "string",
+		"enum": values,
+	}
+}
+
+func (b *BoolSchema) JSONSchema() map[string]any {
+	return map[string]any{"type": "boolean"}
+}
+
+func (r *RecordSchema) JSONSchema() map[string]any {
+	schema := map[string]any{
+		"type":                 "object",
+		"additionalProperties": r.valueSchema.JSONSchema(),
+	}
+
+	if r.keySchema != nil {
+		schema["propertyNames"] = r.keySchema.JSONSchema()
+	}
+	if r.minEntries != nil {
+		schema["minProperties"] = *r.minEntries
+	}
+	if r.maxEntries != nil {
+		schema["maxProperties"] = *r.maxEntries
+	}
+
+	return schema
+}
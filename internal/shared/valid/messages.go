@@ -1,128 +1,180 @@
 package valid
 
-import "fmt"
+import (
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
+)
 
-type Language string
+// Language is kept as an alias so callers can keep writing valid.Spanish.
+type Language = i18n.Locale
 
 const (
-	English Language = "en"
-	Spanish Language = "es"
+	English = i18n.English
+	Spanish = i18n.Spanish
 )
 
-var currentLanguage = English
-
+// SetLanguage sets the language of messages when no locale is negotiated.
+//
+// Deprecated: render messages in the locale of the request with
+// Result.LocalizeCtx, and change the default language with
+// i18n.Default.SetFallback.
 func SetLanguage(lang Language) {
-	currentLanguage = lang
+	i18n.Default.SetFallback(lang)
 }
 
+// GetLanguage returns the language of messages when no locale is
+// negotiated.
+//
+// Deprecated: use i18n.Default.Fallback.
 func GetLanguage() Language {
-	return currentLanguage
+	return i18n.Default.Fallback()
 }
 
+// Message keys registered in the i18n catalog. Other languages can be added
+// with i18n.Register or by loading a message file with the same keys.
 type messages struct {
-	Required    map[Language]string
-	TypeString  map[Language]string
-	TypeNumber  map[Language]string
-	MinLength   map[Language]string
-	MaxLength   map[Language]string
-	Pattern     map[Language]string
-	Email       map[Language]string
-	URL         map[Language]string
-	UUID        map[Language]string
-	Min         map[Language]string
-	Max         map[Language]string
-	Integer     map[Language]string
-	Positive    map[Language]string
-	Negative    map[Language]string
-	TypeObject  map[Language]string
-	TypeArray   map[Language]string
-	MinItems    map[Language]string
-	MaxItems    map[Language]string
+	Required     string
+	TypeString   string
+	TypeNumber   string
+	MinLength    string
+	MaxLength    string
+	Pattern      string
+	Email        string
+	URL          string
+	UUID         string
+	Min          string
+	Max          string
+	Integer      string
+	Positive     string
+	Negative     string
+	TypeObject   string
+	TypeArray    string
+	MinItems     string
+	MaxItems     string
+	TypeDate     string
+	MinDate      string
+	MaxDate      string
+	BeforeDate   string
+	AfterDate    string
+	TypeDecimal  string
+	Precision    string
+	Scale        string
+	Enum         string
+	Currency     string
+	TypeBool     string
+	Exists       string
+	Unique       string
+	LookupFailed string
 }
 
 var msgs = messages{
-	Required: map[Language]string{
-		English: "This field is required",
-		Spanish: "Este campo es obligatorio",
-	},
-	TypeString: map[Language]string{
-		English: "This field must be text",
-		Spanish: "Este campo debe ser texto",
-	},
-	TypeNumber: map[Language]string{
-		English: "This field must be a number",
-		Spanish: "Este campo debe ser un número",
-	},
-	MinLength: map[Language]string{
-		English: "Must be at least %d characters",
-		Spanish: "Debe tener al menos %d caracteres",
-	},
-	MaxLength: map[Language]string{
-		English: "Must be at most %d characters",
-		Spanish: "Debe tener como máximo %d caracteres",
-	},
-	Pattern: map[Language]string{
-		English: "Format is not valid",
-		Spanish: "El formato no es válido",
-	},
-	Email: map[Language]string{
-		English: "Please enter a valid email address",
-		Spanish: "Por favor ingresa una dirección de correo válida",
-	},
-	URL: map[Language]string{
-		English: "Please enter a valid web address",
-		Spanish: "Por favor ingresa una dirección web válida",
-	},
-	UUID: map[Language]string{
-		English: "Please enter a valid identifier",
-		Spanish: "Por favor ingresa un identificador válido",
-	},
-	Min: map[Language]string{
-		English: "Must be at least %v",
-		Spanish: "Debe ser al menos %v",
-	},
-	Max: map[Language]string{
-		English: "Must be at most %v",
-		Spanish: "Debe ser como máximo %v",
-	},
-	Integer: map[Language]string{
-		English: "Must be a whole number",
-		Spanish: "Debe ser un número entero",
-	},
-	Positive: map[Language]string{
-		English: "Must be a positive number",
-		Spanish: "Debe ser un número positivo",
-	},
-	Negative: map[Language]string{
-		English: "Must be a negative number",
-		Spanish: "Debe ser un número negativo",
-	},
-	TypeObject: map[Language]string{
-		English: "This field must be an object",
-		Spanish: "Este campo debe ser un objeto",
-	},
-	TypeArray: map[Language]string{
-		English: "This field must be a list",
-		Spanish: "Este campo debe ser una lista",
-	},
-	MinItems: map[Language]string{
-		English: "Must have at least %d items",
-		Spanish: "Debe tener al menos %d elementos",
-	},
-	MaxItems: map[Language]string{
-		English: "Must have at most %d items",
-		Spanish: "Debe tener como máximo %d elementos",
-	},
+	Required:     "valid.required",
+	TypeString:   "valid.type_string",
+	TypeNumber:   "valid.type_number",
+	MinLength:    "valid.min_length",
+	MaxLength:    "valid.max_length",
+	Pattern:      "valid.pattern",
+	Email:        "valid.email",
+	URL:          "valid.url",
+	UUID:         "valid.uuid",
+	Min:          "valid.min",
+	Max:          "valid.max",
+	Integer:      "valid.integer",
+	Positive:     "valid.positive",
+	Negative:     "valid.negative",
+	TypeObject:   "valid.type_object",
+	TypeArray:    "valid.type_array",
+	MinItems:     "valid.min_items",
+	MaxItems:     "valid.max_items",
+	TypeDate:     "valid.type_date",
+	MinDate:      "valid.min_date",
+	MaxDate:      "valid.max_date",
+	BeforeDate:   "valid.before_date",
+	AfterDate:    "valid.after_date",
+	TypeDecimal:  "valid.type_decimal",
+	Precision:    "valid.precision",
+	Scale:        "valid.scale",
+	Enum:         "valid.enum",
+	Currency:     "valid.currency",
+	TypeBool:     "valid.type_bool",
+	Exists:       "valid.exists",
+	Unique:       "valid.unique",
+	LookupFailed: "valid.lookup_failed",
+}
+
+func init() {
+	i18n.Register(English, map[string]string{
+		"valid.required":      "This field is required",
+		"valid.type_string":   "This field must be text",
+		"valid.type_number":   "This field must be a number",
+		"valid.min_length":    "Must be at least %d characters",
+		"valid.max_length":    "Must be at most %d characters",
+		"valid.pattern":       "Format is not valid",
+		"valid.email":         "Please enter a valid email address",
+		"valid.url":           "Please enter a valid web address",
+		"valid.uuid":          "Please enter a valid identifier",
+		"valid.min":           "Must be at least %v",
+		"valid.max":           "Must be at most %v",
+		"valid.integer":       "Must be a whole number",
+		"valid.positive":      "Must be a positive number",
+		"valid.negative":      "Must be a negative number",
+		"valid.type_object":   "This field must be an object",
+		"valid.type_array":    "This field must be a list",
+		"valid.min_items":     "Must have at least %d items",
+		"valid.max_items":     "Must have at most %d items",
+		"valid.type_date":     "Please enter a valid date",
+		"valid.min_date":      "Must be on or after %s",
+		"valid.max_date":      "Must be on or before %s",
+		"valid.before_date":   "Must be before %s",
+		"valid.after_date":    "Must be after %s",
+		"valid.type_decimal":  "This field must be a decimal number",
+		"valid.precision":     "Must have at most %d digits before the decimal point",
+		"valid.scale":         "Must have at most %d decimal places",
+		"valid.enum":          "Must be one of: %s",
+		"valid.currency":      "Please enter a valid currency code",
+		"valid.type_bool":     "This field must be true or false",
+		"valid.exists":        "The selected value does not exist",
+		"valid.unique":        "This value is already in use",
+		"valid.lookup_failed": "This value could not be verified, please try again",
+	})
+
+	i18n.Register(Spanish, map[string]string{
+		"valid.required":      "Este campo es obligatorio",
+		"valid.type_string":   "Este campo debe ser texto",
+		"valid.type_number":   "Este campo debe ser un número",
+		"valid.min_length":    "Debe tener al menos %d caracteres",
+		"valid.max_length":    "Debe tener como máximo %d caracteres",
+		"valid.pattern":       "El formato no es válido",
+		"valid.email":         "Por favor ingresa una dirección de correo válida",
+		"valid.url":           "Por favor ingresa una dirección web válida",
+		"valid.uuid":          "Por favor ingresa un identificador válido",
+		"valid.min":           "Debe ser al menos %v",
+		"valid.max":           "Debe ser como máximo %v",
+		"valid.integer":       "Debe ser un número entero",
+		"valid.positive":      "Debe ser un número positivo",
+		"valid.negative":      "Debe ser un número negativo",
+		"valid.type_object":   "Este campo debe ser un objeto",
+		"valid.type_array":    "Este campo debe ser una lista",
+		"valid.min_items":     "Debe tener al menos %d elementos",
+		"valid.max_items":     "Debe tener como máximo %d elementos",
+		"valid.type_date":     "Por favor ingresa una fecha válida",
+		"valid.min_date":      "Debe ser igual o posterior a %s",
+		"valid.max_date":      "Debe ser igual o anterior a %s",
+		"valid.before_date":   "Debe ser anterior a %s",
+		"valid.after_date":    "Debe ser posterior a %s",
+		"valid.type_decimal":  "Este campo debe ser un número decimal",
+		"valid.precision":     "Debe tener como máximo %d dígitos antes del punto decimal",
+		"valid.scale":         "Debe tener como máximo %d decimales",
+		"valid.enum":          "Debe ser uno de: %s",
+		"valid.currency":      "Por favor ingresa un código de moneda válido",
+		"valid.type_bool":     "Este campo debe ser verdadero o falso",
+		"valid.exists":        "El valor seleccionado no existe",
+		"valid.unique":        "Este valor ya está en uso",
+		"valid.lookup_failed": "No se pudo verificar este valor, intenta nuevamente",
+	})
 }
 
-func getMessage(msgMap map[Language]string, args ...interface{}) string {
-	template := msgMap[currentLanguage]
-	if template == "" {
-		template = msgMap[English] // fallback to English
-	}
-	
-	if len(args) > 0 {
-		return fmt.Sprintf(template, args...)
-	}
-	return template
-}
\ No newline at end of file
+// getMessage renders key in the catalog fallback language. Errors keep the
+// key and arguments so they can be localized later with Localize.
+func getMessage(key string, args ...any) string {
+	return i18n.Default.Translate(i18n.Default.Fallback(), key, args...)
+}
@@ -1,6 +1,7 @@
 package valid
 
 import (
+	"context"
 	"reflect"
 )
 
@@ -30,6 +31,10 @@ func (n *NumberSchema) Parse(value interface{}) *Result {
 	return n.parseWithPath(value, "")
 }
 
+func (n *NumberSchema) ParseCtx(ctx context.Context, value interface{}) *Result {
+	return parseCtx(ctx, n, value)
+}
+
 func (n *NumberSchema) parseWithPath(value interface{}, path string) *Result {
 	// Skip all validations for null library types that are not valid
 	if isNullLibraryType(value) {
@@ -50,53 +55,29 @@ func (n *NumberSchema) parseWithPath(value interface{}, path string) *Result {
 
 	num, ok := convertToFloat64(value)
 	if !ok {
-		return newResult(false, nil, []ValidationError{{
-			Path:    path,
-			Message: getMessage(msgs.TypeNumber),
-			Code:    "type_error",
-		}})
+		return newResult(false, nil, []ValidationError{newError(path, "type_error", msgs.TypeNumber)})
 	}
 
 	var errors []ValidationError
 
 	if n.integer && !isInteger(num) {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.Integer),
-			Code:    "integer",
-		})
+		errors = append(errors, newError(path, "integer", msgs.Integer))
 	}
 
 	if n.positive && num <= 0 {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.Positive),
-			Code:    "positive",
-		})
+		errors = append(errors, newError(path, "positive", msgs.Positive))
 	}
 
 	if n.negative && num >= 0 {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.Negative),
-			Code:    "negative",
-		})
+		errors = append(errors, newError(path, "negative", msgs.Negative))
 	}
 
 	if n.min != nil && num < *n.min {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.Min, *n.min),
-			Code:    "min",
-		})
+		errors = append(errors, newError(path, "min", msgs.Min, *n.min))
 	}
 
 	if n.max != nil && num > *n.max {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.Max, *n.max),
-			Code:    "max",
-		})
+		errors = append(errors, newError(path, "max", msgs.Max, *n.max))
 	}
 
 	errors = append(errors, n.validateCustom(value, path)...)
@@ -154,6 +135,16 @@ func (n *NumberSchema) Custom(fn CustomValidatorFunc) Schema {
 	return n
 }
 
+func (n *NumberSchema) CustomCtx(fn CustomCtxValidatorFunc) Schema {
+	n.baseSchema.addCtxRule(&funcRule{fn: fn})
+	return n
+}
+
+func (n *NumberSchema) Rule(rule CtxRule) Schema {
+	n.baseSchema.addCtxRule(rule)
+	return n
+}
+
 func convertToFloat64(value interface{}) (float64, bool) {
 	if value == nil {
 		return 0, false
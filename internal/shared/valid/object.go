@@ -1,6 +1,7 @@
 package valid
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 
@@ -24,6 +25,10 @@ func (o *ObjectSchema) Parse(value any) *Result {
 	return o.parseWithPath(value, "")
 }
 
+func (o *ObjectSchema) ParseCtx(ctx context.Context, value any) *Result {
+	return parseCtx(ctx, o, value)
+}
+
 func (o *ObjectSchema) parseWithPath(value any, path string) *Result {
 	// Skip all validations for null library types that are not valid
 	if isNullLibraryType(value) {
@@ -44,14 +49,11 @@ func (o *ObjectSchema) parseWithPath(value any, path string) *Result {
 
 	data, err := convertToMap(value)
 	if err != nil {
-		return newResult(false, nil, []ValidationError{{
-			Path:    path,
-			Message: getMessage(msgs.TypeObject),
-			Code:    "type_error",
-		}})
+		return newResult(false, nil, []ValidationError{newError(path, "type_error", msgs.TypeObject)})
 	}
 
 	var allErrors []ValidationError
+	var pending []pendingCheck
 	validatedData := make(map[string]any)
 
 	for fieldName, fieldSchema := range o.fields {
@@ -63,6 +65,7 @@ func (o *ObjectSchema) parseWithPath(value any, path string) *Result {
 		}
 
 		result := o.validateField(fieldSchema, fieldValue, fieldPath)
+		pending = append(pending, result.pending...)
 
 		if result.HasErrors() {
 			allErrors = append(allErrors, result.Errors...)
@@ -77,10 +80,10 @@ func (o *ObjectSchema) parseWithPath(value any, path string) *Result {
 	errors := append(allErrors, o.validateCustom(validatedData, path)...)
 
 	if len(errors) > 0 {
-		return newResult(false, nil, errors)
+		return withPending(newResult(false, nil, errors), pending)
 	}
 
-	return newResult(true, validatedData, nil)
+	return withPending(newResult(true, validatedData, nil), pending)
 }
 
 func (o *ObjectSchema) buildFieldPath(path, fieldName string) string {
@@ -143,22 +146,7 @@ func (o *ObjectSchema) getFieldName(field reflect.StructField) string {
 }
 
 func (o *ObjectSchema) validateField(fieldSchema Schema, fieldValue any, fieldPath string) *Result {
-	switch schema := fieldSchema.(type) {
-	case *StringSchema:
-		return schema.parseWithPath(fieldValue, fieldPath)
-	case *NumberSchema:
-		return schema.parseWithPath(fieldValue, fieldPath)
-	case *ObjectSchema:
-		return schema.parseWithPath(fieldValue, fieldPath)
-	case *ArraySchema:
-		return schema.parseWithPath(fieldValue, fieldPath)
-	default:
-		result := fieldSchema.Parse(fieldValue)
-		if len(result.Errors) > 0 {
-			result.Errors = addPathPrefix(result.Errors, fieldPath)
-		}
-		return result
-	}
+	return parseAt(fieldSchema, fieldValue, fieldPath)
 }
 
 func (o *ObjectSchema) Optional() Schema {
@@ -176,6 +164,16 @@ func (o *ObjectSchema) Custom(fn CustomValidatorFunc) Schema {
 	return o
 }
 
+func (o *ObjectSchema) CustomCtx(fn CustomCtxValidatorFunc) Schema {
+	o.baseSchema.addCtxRule(&funcRule{fn: fn})
+	return o
+}
+
+func (o *ObjectSchema) Rule(rule CtxRule) Schema {
+	o.baseSchema.addCtxRule(rule)
+	return o
+}
+
 func convertToMap(value any) (map[string]any, error) {
 	if value == nil {
 		return nil, nil
@@ -288,7 +286,18 @@ func extractNullValue(fieldVal any) any {
 		return nil
 	case uuid.NullUUID:
 		if v.Valid {
-			return v.UUID
+			return v.UUID.String()
+		}
+		return nil
+	// UUIDs are validated as text, the zero UUID as a missing value
+	case uuid.UUID:
+		if v != uuid.Nil {
+			return v.String()
+		}
+		return nil
+	case *uuid.UUID:
+		if v != nil {
+			return v.String()
 		}
 		return nil
 	default:
@@ -0,0 +1,174 @@
+package valid
+
+import (
+	"context"
+	"reflect"
+	"sort"
+)
+
+type RecordSchema struct {
+	baseSchema
+	keySchema   Schema
+	valueSchema Schema
+	minEntries  *int
+	maxEntries  *int
+}
+
+// Record validates maps with arbitrary string keys whose values all match
+// valueSchema, e.g. JSONB metadata columns.
+func Record(valueSchema Schema) *RecordSchema {
+	return &RecordSchema{
+		baseSchema:  baseSchema{},
+		valueSchema: valueSchema,
+	}
+}
+
+func (r *RecordSchema) Parse(value any) *Result {
+	return r.parseWithPath(value, "")
+}
+
+func (r *RecordSchema) ParseCtx(ctx context.Context, value any) *Result {
+	return parseCtx(ctx, r, value)
+}
+
+func (r *RecordSchema) parseWithPath(value any, path string) *Result {
+	// Skip all validations for null library types that are not valid
+	if isNullLibraryType(value) {
+		return newResult(true, value, nil)
+	}
+
+	if r.optional && value == nil {
+		return newResult(true, value, nil)
+	}
+
+	if errors := r.validateRequired(value, path); len(errors) > 0 {
+		return newResult(false, nil, errors)
+	}
+
+	if value == nil && !r.required {
+		return newResult(true, value, nil)
+	}
+
+	entries, ok := convertToRecord(value)
+	if !ok {
+		return newResult(false, nil, []ValidationError{newError(path, "type_error", msgs.TypeObject)})
+	}
+
+	var errors []ValidationError
+
+	if r.minEntries != nil && len(entries) < *r.minEntries {
+		errors = append(errors, newError(path, "min_entries", msgs.MinItems, *r.minEntries))
+	}
+
+	if r.maxEntries != nil && len(entries) > *r.maxEntries {
+		errors = append(errors, newError(path, "max_entries", msgs.MaxItems, *r.maxEntries))
+	}
+
+	// Sorted keys keep error order stable between runs
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pending []pendingCheck
+	validatedEntries := make(map[string]any, len(entries))
+	for _, key := range keys {
+		entryPath := key
+		if path != "" {
+			entryPath = path + "." + key
+		}
+
+		if r.keySchema != nil {
+			if keyResult := parseAt(r.keySchema, key, entryPath); keyResult.HasErrors() {
+				for _, err := range keyResult.Errors {
+					err.Code = "invalid_key"
+					errors = append(errors, err)
+				}
+				continue
+			}
+		}
+
+		result := parseAt(r.valueSchema, entries[key], entryPath)
+		pending = append(pending, result.pending...)
+		if result.HasErrors() {
+			errors = append(errors, result.Errors...)
+			continue
+		}
+		validatedEntries[key] = result.Data
+	}
+
+	errors = append(errors, r.validateCustom(validatedEntries, path)...)
+
+	if len(errors) > 0 {
+		return withPending(newResult(false, nil, errors), pending)
+	}
+
+	return withPending(newResult(true, validatedEntries, nil), pending)
+}
+
+// Keys validates every key against keySchema.
+func (r *RecordSchema) Keys(keySchema Schema) *RecordSchema {
+	r.keySchema = keySchema
+	return r
+}
+
+func (r *RecordSchema) MinEntries(min int) *RecordSchema {
+	r.minEntries = &min
+	return r
+}
+
+func (r *RecordSchema) MaxEntries(max int) *RecordSchema {
+	r.maxEntries = &max
+	return r
+}
+
+func (r *RecordSchema) Optional() Schema {
+	r.baseSchema.setOptional()
+	return r
+}
+
+func (r *RecordSchema) Required() Schema {
+	r.baseSchema.setRequired()
+	return r
+}
+
+func (r *RecordSchema) Custom(fn CustomValidatorFunc) Schema {
+	r.baseSchema.addCustom(fn)
+	return r
+}
+
+func (r *RecordSchema) CustomCtx(fn CustomCtxValidatorFunc) Schema {
+	r.baseSchema.addCtxRule(&funcRule{fn: fn})
+	return r
+}
+
+func (r *RecordSchema) Rule(rule CtxRule) Schema {
+	r.baseSchema.addCtxRule(rule)
+	return r
+}
+
+func convertToRecord(value any) (map[string]any, bool) {
+	if m, ok := value.(map[string]any); ok {
+		return m, true
+	}
+
+	val := reflect.ValueOf(value)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil, false
+		}
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Map || val.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	result := make(map[string]any, val.Len())
+	iter := val.MapRange()
+	for iter.Next() {
+		result[iter.Key().String()] = iter.Value().Interface()
+	}
+	return result, true
+}
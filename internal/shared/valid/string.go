@@ -1,6 +1,7 @@
 package valid
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
@@ -15,6 +16,7 @@ type StringSchema struct {
 	email     bool
 	url       bool
 	uuid      bool
+	currency  bool
 }
 
 func String() *StringSchema {
@@ -27,6 +29,10 @@ func (s *StringSchema) Parse(value interface{}) *Result {
 	return s.parseWithPath(value, "")
 }
 
+func (s *StringSchema) ParseCtx(ctx context.Context, value interface{}) *Result {
+	return parseCtx(ctx, s, value)
+}
+
 func (s *StringSchema) parseWithPath(value interface{}, path string) *Result {
 	// Skip all validations for null library types that are not valid
 	if isNullLibraryType(value) {
@@ -50,62 +56,38 @@ func (s *StringSchema) parseWithPath(value interface{}, path string) *Result {
 		if ptr, isPtr := value.(*string); isPtr && ptr != nil {
 			str = *ptr
 		} else {
-			return newResult(false, nil, []ValidationError{{
-				Path:    path,
-				Message: getMessage(msgs.TypeString),
-				Code:    "type_error",
-			}})
+			return newResult(false, nil, []ValidationError{newError(path, "type_error", msgs.TypeString)})
 		}
 	}
 
 	var errors []ValidationError
 
 	if s.minLength != nil && len(str) < *s.minLength {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.MinLength, *s.minLength),
-			Code:    "min_length",
-		})
+		errors = append(errors, newError(path, "min_length", msgs.MinLength, *s.minLength))
 	}
 
 	if s.maxLength != nil && len(str) > *s.maxLength {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.MaxLength, *s.maxLength),
-			Code:    "max_length",
-		})
+		errors = append(errors, newError(path, "max_length", msgs.MaxLength, *s.maxLength))
 	}
 
 	if s.pattern != nil && !s.pattern.MatchString(str) {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.Pattern),
-			Code:    "pattern",
-		})
+		errors = append(errors, newError(path, "pattern", msgs.Pattern))
 	}
 
 	if s.email && !isValidEmail(str) {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.Email),
-			Code:    "email",
-		})
+		errors = append(errors, newError(path, "email", msgs.Email))
 	}
 
 	if s.url && !isValidURL(str) {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.URL),
-			Code:    "url",
-		})
+		errors = append(errors, newError(path, "url", msgs.URL))
 	}
 
 	if s.uuid && !isValidUUID(str) {
-		errors = append(errors, ValidationError{
-			Path:    path,
-			Message: getMessage(msgs.UUID),
-			Code:    "uuid",
-		})
+		errors = append(errors, newError(path, "uuid", msgs.UUID))
+	}
+
+	if s.currency && !isValidCurrencyCode(str) {
+		errors = append(errors, newError(path, "currency", msgs.Currency))
 	}
 
 	errors = append(errors, s.validateCustom(str, path)...)
@@ -168,6 +150,16 @@ func (s *StringSchema) Custom(fn CustomValidatorFunc) Schema {
 	return s
 }
 
+func (s *StringSchema) CustomCtx(fn CustomCtxValidatorFunc) Schema {
+	s.baseSchema.addCtxRule(&funcRule{fn: fn})
+	return s
+}
+
+func (s *StringSchema) Rule(rule CtxRule) Schema {
+	s.baseSchema.addCtxRule(rule)
+	return s
+}
+
 func isValidEmail(email string) bool {
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 	return emailRegex.MatchString(email)
@@ -1,8 +1,16 @@
 package valid
 
 import (
+	"context"
 	"errors"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"api.system.soluciones-cloud.com/internal/shared/i18n"
 )
 
 func TestBasicValidation(t *testing.T) {
@@ -77,20 +85,110 @@ func TestObjectValidation(t *testing.T) {
 	}
 }
 
+func TestObjectValidation_UUIDFields(t *testing.T) {
+	type request struct {
+		ID        uuid.UUID     `json:"id"`
+		CreatedBy *uuid.UUID    `json:"created_by"`
+		ParentID  uuid.NullUUID `json:"parent_id"`
+	}
+	schema := Object(map[string]Schema{
+		"id":         String().UUID().Required(),
+		"created_by": String().UUID(),
+		"parent_id":  String().UUID(),
+	})
+
+	id := uuid.New()
+	result := schema.Parse(request{ID: id, CreatedBy: &id, ParentID: uuid.NullUUID{UUID: id, Valid: true}})
+	if !result.Success {
+		t.Errorf("Expected validation to pass, got errors: %v", result.Errors)
+	}
+
+	result = schema.Parse(request{})
+	if result.Success || len(result.Errors) != 1 || result.Errors[0].Path != "id" {
+		t.Errorf("Expected only the zero id to fail, got errors: %v", result.Errors)
+	}
+}
+
 func TestLanguageSupport(t *testing.T) {
-	SetLanguage(English)
 	result := String().Required().Parse("")
-	englishMessage := result.Error()
-	
-	SetLanguage(Spanish)
-	result = String().Required().Parse("")
-	spanishMessage := result.Error()
-	
+	englishMessage := result.Localize(English).Error()
+	spanishMessage := result.Localize(Spanish).Error()
+
 	if englishMessage == spanishMessage {
 		t.Error("Expected different messages for different languages")
 	}
-	
-	SetLanguage(English)
+
+	if spanishMessage != "Este campo es obligatorio" {
+		t.Errorf("Expected Spanish message, got: %s", spanishMessage)
+	}
+
+	ctx := i18n.WithLocale(context.Background(), Spanish)
+	if message := result.LocalizeCtx(ctx).Error(); message != spanishMessage {
+		t.Errorf("Expected locale from context, got: %s", message)
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	previous := GetLanguage()
+	t.Cleanup(func() { SetLanguage(previous) })
+
+	SetLanguage(Spanish)
+	if GetLanguage() != Spanish {
+		t.Errorf("Expected language %q, got %q", Spanish, GetLanguage())
+	}
+
+	result := String().Required().Parse("")
+	if message := result.Errors[0].Message; message != "Este campo es obligatorio" {
+		t.Errorf("Expected Spanish message, got: %s", message)
+	}
+}
+
+func TestLanguageSupport_Concurrent(t *testing.T) {
+	schema := String().MinLength(5).Required()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		lang := English
+		if i%2 == 0 {
+			lang = Spanish
+		}
+
+		wg.Add(1)
+		go func(lang Language) {
+			defer wg.Done()
+			result := schema.Parse("abc").Localize(lang)
+			want := i18n.Default.Translate(lang, msgs.MinLength, 5)
+			if result.Errors[0].Message != want {
+				t.Errorf("Expected %q, got %q", want, result.Errors[0].Message)
+			}
+		}(lang)
+	}
+	wg.Wait()
+}
+
+func TestLanguageSupport_CustomErrorsUntouched(t *testing.T) {
+	schema := String().Custom(func(value any) error {
+		return errors.New("custom message")
+	})
+
+	result := schema.Parse("value").Localize(Spanish)
+	if result.Errors[0].Message != "custom message" {
+		t.Errorf("Expected custom message to be preserved, got: %s", result.Errors[0].Message)
+	}
+}
+
+func TestLanguageSupport_CustomErrorsWithKey(t *testing.T) {
+	schema := String().Custom(func(value any) error {
+		return NewError("min_length", msgs.MinLength, 8)
+	})
+
+	result := schema.Parse("value")
+	if result.Errors[0].Code != "min_length" || result.Errors[0].Message != "Must be at least 8 characters" {
+		t.Errorf("Expected the English message of the key, got %+v", result.Errors[0])
+	}
+	if message := result.Localize(Spanish).Errors[0].Message; message != "Debe tener al menos 8 caracteres" {
+		t.Errorf("Expected Spanish message, got: %s", message)
+	}
 }
 
 func TestCustomValidation(t *testing.T) {
@@ -134,4 +232,209 @@ func TestArrayValidation(t *testing.T) {
 	if result.Success {
 		t.Error("Expected validation to fail for array with too many items")
 	}
-}
\ No newline at end of file
+}
+
+func TestDateValidation(t *testing.T) {
+	schema := Date().Min(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)).Before(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	result := schema.Parse("2025-07-05")
+	if !result.Success {
+		t.Errorf("Expected validation to pass, got errors: %v", result.Errors)
+	}
+
+	result = schema.Parse("2025-01-01")
+	if !result.Success {
+		t.Errorf("Expected min date to be inclusive, got errors: %v", result.Errors)
+	}
+
+	result = schema.Parse("2026-01-01")
+	if result.Success {
+		t.Error("Expected validation to fail for date not before limit")
+	}
+
+	result = schema.Parse("05/07/2025")
+	if result.Success {
+		t.Error("Expected validation to fail for wrong date format")
+	}
+
+	result = Time().After(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)).Parse("2025-01-01T11:00:00Z")
+	if result.Success {
+		t.Error("Expected validation to fail for time not after limit")
+	}
+}
+
+func TestDecimalValidation(t *testing.T) {
+	schema := Decimal().Numeric(12, 4).Required()
+
+	for _, value := range []any{"12345678.1234", 99.5, 10, "-0.0001", "1.23400"} {
+		result := schema.Parse(value)
+		if !result.Success {
+			t.Errorf("Expected %v to pass, got errors: %v", value, result.Errors)
+		}
+	}
+
+	result := schema.Parse("1.12345")
+	if result.Success || result.Errors[0].Code != "scale" {
+		t.Errorf("Expected scale error, got: %+v", result)
+	}
+
+	result = schema.Parse("123456789.1")
+	if result.Success || result.Errors[0].Code != "precision" {
+		t.Errorf("Expected precision error, got: %+v", result)
+	}
+
+	result = schema.Parse("12,5")
+	if result.Success {
+		t.Error("Expected validation to fail for non-decimal string")
+	}
+}
+
+func TestDecimalPrecisionWithoutScale(t *testing.T) {
+	// NUMERIC(5) has a scale of 0
+	schema := Decimal().Precision(5).Required()
+
+	if result := schema.Parse("12345"); !result.Success {
+		t.Errorf("Expected integer to pass, got errors: %v", result.Errors)
+	}
+
+	result := schema.Parse("1.5")
+	if result.Success || result.Errors[0].Code != "scale" {
+		t.Errorf("Expected scale error, got: %+v", result)
+	}
+
+	if got, want := schema.JSONSchema()["pattern"], `^[+-]?\d{1,5}$`; got != want {
+		t.Errorf("Expected pattern %s, got %v", want, got)
+	}
+}
+
+func TestDecimalBoundsAreExact(t *testing.T) {
+	schema := Decimal().Min(0.1).Max(0.3).Required()
+
+	for _, value := range []string{"0.1", "0.3", "0.2"} {
+		if result := schema.Parse(value); !result.Success {
+			t.Errorf("Expected %s to pass, got errors: %v", value, result.Errors)
+		}
+	}
+
+	// Both round to the bounds as float64
+	for value, code := range map[string]string{"0.30000000000000001": "max", "0.09999999999999999999": "min"} {
+		result := schema.Parse(value)
+		if result.Success || result.Errors[0].Code != code {
+			t.Errorf("Expected %s error for %s, got: %+v", code, value, result)
+		}
+	}
+}
+
+func TestEnumValidation(t *testing.T) {
+	schema := Enum("CUSTOMER", "SUPPLIER").Required()
+
+	result := schema.Parse("CUSTOMER")
+	if !result.Success {
+		t.Errorf("Expected validation to pass, got errors: %v", result.Errors)
+	}
+
+	result = schema.Parse("customer")
+	if result.Success {
+		t.Error("Expected validation to fail for different case")
+	}
+
+	result = Enum("CUSTOMER", "SUPPLIER").CaseInsensitive().Parse("supplier")
+	if !result.Success || result.Data != "SUPPLIER" {
+		t.Errorf("Expected canonical value, got: %+v", result)
+	}
+}
+
+func TestCurrencyCodeValidation(t *testing.T) {
+	schema := CurrencyCode().Required()
+
+	result := schema.Parse("PEN")
+	if !result.Success {
+		t.Errorf("Expected validation to pass, got errors: %v", result.Errors)
+	}
+
+	result = schema.Parse("XYZ")
+	if result.Success {
+		t.Error("Expected validation to fail for unknown currency")
+	}
+
+	result = schema.Parse("pen")
+	if result.Success {
+		t.Error("Expected validation to fail for lower case currency")
+	}
+
+	for _, code := range []string{"XXX", "XTS", "XAU", "XDR"} {
+		if result := schema.Parse(code); result.Success {
+			t.Errorf("Expected validation to fail for non-billable code %s", code)
+		}
+	}
+}
+
+func TestBoolValidation(t *testing.T) {
+	schema := Bool().Required()
+
+	result := schema.Parse(false)
+	if !result.Success {
+		t.Errorf("Expected false to be a valid value, got errors: %v", result.Errors)
+	}
+
+	result = schema.Parse("true")
+	if result.Success {
+		t.Error("Expected validation to fail for string")
+	}
+}
+
+func TestRecordValidation(t *testing.T) {
+	schema := Record(Int().Min(0)).Keys(String().Pattern(`^[a-z_]+$`)).MaxEntries(2)
+
+	result := schema.Parse(map[string]int{"seats": 3, "projects": 1})
+	if !result.Success {
+		t.Errorf("Expected validation to pass, got errors: %v", result.Errors)
+	}
+
+	result = schema.Parse(map[string]any{"seats": -1})
+	if result.Success || result.Errors[0].Path != "seats" {
+		t.Errorf("Expected error at path seats, got: %+v", result)
+	}
+
+	result = schema.Parse(map[string]any{"Seats": 1})
+	if result.Success || result.Errors[0].Code != "invalid_key" {
+		t.Errorf("Expected invalid key error, got: %+v", result)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"email":    String().Email().MaxLength(255).Required(),
+		"age":      Int().Min(0).Optional(),
+		"amount":   Decimal().Numeric(12, 4).Required(),
+		"status":   Enum("DRAFT", "ISSUED"),
+		"birthday": Date(),
+		"tags":     Array(String()).MaxItems(5),
+		"metadata": Record(Bool()),
+	})
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{name: "required fields", got: schema.JSONSchema()["required"], want: []string{"amount", "email"}},
+		{name: "email format", got: String().Email().JSONSchema()["format"], want: "email"},
+		{name: "string max length", got: String().MaxLength(255).JSONSchema()["maxLength"], want: 255},
+		{name: "integer type", got: Int().JSONSchema()["type"], want: "integer"},
+		{name: "decimal pattern", got: Decimal().Numeric(12, 4).JSONSchema()["pattern"], want: `^[+-]?\d{1,8}(\.\d{1,4})?$`},
+		{name: "enum values", got: Enum("DRAFT", "ISSUED").JSONSchema()["enum"], want: []string{"DRAFT", "ISSUED"}},
+		{name: "date format", got: Date().JSONSchema()["format"], want: "date"},
+		{name: "time format", got: Time().JSONSchema()["format"], want: "date-time"},
+		{name: "array items", got: Array(Bool()).JSONSchema()["items"], want: map[string]any{"type": "boolean"}},
+		{name: "record values", got: Record(Bool()).JSONSchema()["additionalProperties"], want: map[string]any{"type": "boolean"}},
+		{name: "document dialect", got: JSONSchemaDocument(Bool())["$schema"], want: JSONSchemaDialect},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("JSONSchema() = %#v, want %#v", tt.got, tt.want)
+			}
+		})
+	}
+}
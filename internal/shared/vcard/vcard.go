@@ -0,0 +1,133 @@
+// Package vcard encodes contacts as vCard 4.0 (RFC 6350), the format
+// address books import.
+package vcard
+
+import (
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of vCard files.
+const ContentType = "text/vcard; charset=utf-8"
+
+// maxLineLength is the length in octets lines are folded at
+const maxLineLength = 75
+
+// Card is a vCard. Empty fields are left out, except FormattedName, which
+// every card has.
+type Card struct {
+	// UID identifies the contact across exports, e.g. urn:uuid:<id>
+	UID           string
+	FormattedName string
+	FamilyName    string
+	GivenName     string
+	// Organization is the name of the organization followed by its units,
+	// e.g. the company and the department
+	Organization []string
+	Title        string
+	Emails       []string
+	Phones       []Phone
+	Categories   []string
+	Note         string
+	Revision     time.Time
+}
+
+// Phone is a telephone number with its types, e.g. work, cell or voice.
+type Phone struct {
+	Number string
+	Types  []string
+}
+
+// Encode writes the cards to w, one after the other.
+func Encode(w io.Writer, cards ...Card) error {
+	var b strings.Builder
+	for _, card := range cards {
+		card.encode(&b)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c Card) encode(b *strings.Builder) {
+	writeLine(b, "BEGIN:VCARD")
+	writeLine(b, "VERSION:4.0")
+	if c.UID != "" {
+		writeLine(b, "UID:"+c.UID)
+	}
+	writeLine(b, "FN:"+escape(c.FormattedName))
+	if c.FamilyName != "" || c.GivenName != "" {
+		// family;given;additional;prefixes;suffixes
+		writeLine(b, "N:"+escape(c.FamilyName)+";"+escape(c.GivenName)+";;;")
+	}
+	if len(c.Organization) > 0 {
+		writeLine(b, "ORG:"+join(c.Organization, ";"))
+	}
+	if c.Title != "" {
+		writeLine(b, "TITLE:"+escape(c.Title))
+	}
+	for _, email := range c.Emails {
+		writeLine(b, "EMAIL;TYPE=work:"+escape(email))
+	}
+	for _, phone := range c.Phones {
+		property := "TEL;VALUE=text"
+		if len(phone.Types) > 0 {
+			property += ";TYPE=" + strings.Join(phone.Types, ",")
+		}
+		writeLine(b, property+":"+escape(phone.Number))
+	}
+	if len(c.Categories) > 0 {
+		writeLine(b, "CATEGORIES:"+join(c.Categories, ","))
+	}
+	if c.Note != "" {
+		writeLine(b, "NOTE:"+escape(c.Note))
+	}
+	if !c.Revision.IsZero() {
+		writeLine(b, "REV:"+c.Revision.UTC().Format("20060102T150405Z"))
+	}
+	writeLine(b, "END:VCARD")
+}
+
+// writeLine writes a content line ended with CRLF, folding it every 75
+// octets without splitting UTF-8 characters. Continuation lines start
+// with a space, which counts towards their length.
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineLength - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// escape escapes a text value, see RFC 6350 section 3.4
+func escape(value string) string {
+	return textEscaper.Replace(value)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	",", `\,`,
+	";", `\;`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// join escapes the components of a value and joins them with sep
+func join(values []string, sep string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = escape(value)
+	}
+	return strings.Join(escaped, sep)
+}
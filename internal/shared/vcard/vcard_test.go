@@ -0,0 +1,75 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	var b strings.Builder
+	err := Encode(&b, Card{
+		UID:           "urn:uuid:0b5f5f3e-6d1f-4a53-9d3e-1a2b3c4d5e6f",
+		FormattedName: "María Pérez",
+		FamilyName:    "Pérez",
+		GivenName:     "María",
+		Organization:  []string{"Acme, S.A.", "Ventas; Norte"},
+		Title:         "Gerente",
+		Emails:        []string{"maria@acme.pe"},
+		Phones: []Phone{
+			{Number: "+51 1 234 5678", Types: []string{"work", "voice"}},
+			{Number: "+51 987 654 321", Types: []string{"cell"}},
+		},
+		Categories: []string{"BILLING", "PRIMARY"},
+		Note:       "Line one\nLine two \\ end",
+		Revision:   time.Date(2026, 10, 19, 12, 30, 0, 0, time.FixedZone("PET", -5*3600)),
+	}, Card{FormattedName: "Juan"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "BEGIN:VCARD\r\n" +
+		"VERSION:4.0\r\n" +
+		"UID:urn:uuid:0b5f5f3e-6d1f-4a53-9d3e-1a2b3c4d5e6f\r\n" +
+		"FN:María Pérez\r\n" +
+		"N:Pérez;María;;;\r\n" +
+		"ORG:Acme\\, S.A.;Ventas\\; Norte\r\n" +
+		"TITLE:Gerente\r\n" +
+		"EMAIL;TYPE=work:maria@acme.pe\r\n" +
+		"TEL;VALUE=text;TYPE=work,voice:+51 1 234 5678\r\n" +
+		"TEL;VALUE=text;TYPE=cell:+51 987 654 321\r\n" +
+		"CATEGORIES:BILLING,PRIMARY\r\n" +
+		"NOTE:Line one\\nLine two \\\\ end\r\n" +
+		"REV:20261019T173000Z\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"VERSION:4.0\r\n" +
+		"FN:Juan\r\n" +
+		"END:VCARD\r\n"
+	if got := b.String(); got != want {
+		t.Errorf("Unexpected vCard:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	var b strings.Builder
+	note := strings.Repeat("ñ", 100)
+	if err := Encode(&b, Card{FormattedName: "Long", Note: note}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var unfolded strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("Expected lines of at most %d octets, got %d", maxLineLength, len(line))
+		}
+		if !strings.HasPrefix(line, " ") {
+			unfolded.WriteString("\r\n")
+		}
+		unfolded.WriteString(strings.TrimPrefix(line, " "))
+	}
+
+	if !strings.Contains(unfolded.String(), "\r\nNOTE:"+note+"\r\n") {
+		t.Errorf("Expected the folded note to unfold to the original, got %q", unfolded.String())
+	}
+}
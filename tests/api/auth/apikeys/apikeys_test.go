@@ -0,0 +1,215 @@
+//go:build integration
+
+package apikeys
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type apiKey struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Prefix         string     `json:"prefix"`
+	Actions        []string   `json:"actions"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	Key            string     `json:"key"`
+}
+
+// APIKeysTestSuite covers API keys and the requests made with them
+type APIKeysTestSuite struct {
+	suite.Suite
+	testSuite      *shared.TestSuite
+	organizationID uuid.UUID
+	adminToken     string
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *APIKeysTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+
+	// Given: An administrator allowed to manage the API keys of an
+	// organization and to read it
+	s.organizationID = s.testSuite.CreateOrganization("Integrations")
+	adminID := s.testSuite.CreateUser("Admin")
+	s.testSuite.GrantPermissions(s.organizationID, adminID,
+		"api_keys.create", "api_keys.read", "api_keys.revoke", "organizations.read")
+	s.adminToken = s.testSuite.AccessToken(adminID)
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *APIKeysTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *APIKeysTestSuite) request() *resty.Request {
+	return s.testSuite.Client.Client.R().SetAuthToken(s.adminToken)
+}
+
+func (s *APIKeysTestSuite) withKey(key string) *resty.Request {
+	return s.testSuite.Client.Client.R().SetHeader("Authorization", "ApiKey "+key)
+}
+
+func (s *APIKeysTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *APIKeysTestSuite) createKey(body map[string]any) *resty.Response {
+	resp, err := s.request().SetBody(body).Post("/api/v1/api-keys")
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *APIKeysTestSuite) readOrganization(key string) int {
+	resp, err := s.withKey(key).Get("/api/v1/organizations/" + s.organizationID.String())
+	s.Require().NoError(err)
+	return resp.StatusCode()
+}
+
+// TestAPIKeyLifeCycle_ShouldBeAudited tests create, use, list and revoke
+func (s *APIKeysTestSuite) TestAPIKeyLifeCycle_ShouldBeAudited() {
+	// When: A key granted to read organizations is created
+	var created apiKey
+	s.data(s.createKey(map[string]any{"name": "Exporter", "actions": []string{"organizations.read"}}), http.StatusCreated, &created)
+
+	// Then: It belongs to the organization of the caller, and only its
+	// hash is stored
+	s.Equal(s.organizationID, created.OrganizationID)
+	s.Equal([]string{"organizations.read"}, created.Actions)
+	s.True(strings.HasPrefix(created.Key, created.Prefix+"."))
+	s.Equal(0, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.api_keys WHERE key_hash = $1`, created.Key))
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.users WHERE id = $1 AND origin = 'API_KEY'`, created.UserID))
+
+	// When: The key reads the organization
+	s.Equal(http.StatusOK, s.readOrganization(created.Key))
+
+	// Then: Its use is tracked, and its secret is never listed
+	path := "/api/v1/api-keys/" + created.ID.String()
+	resp, err := s.request().Get(path)
+	s.Require().NoError(err)
+	var found apiKey
+	s.data(resp, http.StatusOK, &found)
+	s.NotNil(found.LastUsedAt)
+	s.Empty(found.Key)
+
+	// When: The key is revoked
+	resp, err = s.request().Delete(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNoContent, resp.StatusCode())
+
+	// Then: It is rejected right away, and both changes were audited
+	s.Equal(http.StatusUnauthorized, s.readOrganization(created.Key))
+	for _, action := range []string{"api_key.created", "api_key.revoked"} {
+		s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.audit_logs WHERE entity_id = $1 AND action = $2`, created.ID, action), action)
+	}
+}
+
+// TestAPIKey_ShouldOnlyBeGrantedItsActions tests that keys do not get the permissions of their creator
+func (s *APIKeysTestSuite) TestAPIKey_ShouldOnlyBeGrantedItsActions() {
+	var created apiKey
+	s.data(s.createKey(map[string]any{"name": "Reader", "actions": []string{"organizations.read"}}), http.StatusCreated, &created)
+
+	resp, err := s.withKey(created.Key).Get("/api/v1/api-keys")
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Other organizations are out of reach too
+	otherID := s.testSuite.CreateOrganization("Other")
+	resp, err = s.withKey(created.Key).Get("/api/v1/organizations/" + otherID.String())
+	s.Require().NoError(err)
+	s.Contains([]int{http.StatusForbidden, http.StatusNotFound}, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCreateAPIKey_ActionNotGranted_ShouldReturnForbidden tests that keys never hold more than their creator
+func (s *APIKeysTestSuite) TestCreateAPIKey_ActionNotGranted_ShouldReturnForbidden() {
+	resp := s.createKey(map[string]any{"name": "Escalated", "actions": []string{"organizations.read", "roles.create"}})
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCreateAPIKey_OtherOrganization_ShouldReturnForbidden tests that keys
+// only get the actions their creator holds in the organization of the key
+func (s *APIKeysTestSuite) TestCreateAPIKey_OtherOrganization_ShouldReturnForbidden() {
+	// Given: A user that reads organizations in orgA but not in orgB
+	orgA := s.testSuite.CreateOrganization("Keys A")
+	orgB := s.testSuite.CreateOrganization("Keys B")
+	userID := s.testSuite.CreateUser("Integrator")
+	s.testSuite.GrantPermissions(orgA, userID, "api_keys.create", "organizations.read")
+	s.testSuite.GrantPermissions(orgB, userID, "api_keys.create")
+	token := s.testSuite.AccessToken(userID)
+	createKey := func(organizationID uuid.UUID) *resty.Response {
+		resp, err := s.testSuite.Client.Client.R().SetAuthToken(token).
+			SetBody(map[string]any{"name": "Reader", "organization_id": organizationID, "actions": []string{"organizations.read"}}).
+			Post("/api/v1/api-keys")
+		s.Require().NoError(err)
+		return resp
+	}
+
+	resp := createKey(orgB)
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	resp = createKey(orgA)
+	s.Equal(http.StatusCreated, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCreateAPIKey_PastExpiry_ShouldReturnBadRequest tests expires_at validation
+func (s *APIKeysTestSuite) TestCreateAPIKey_PastExpiry_ShouldReturnBadRequest() {
+	resp := s.createKey(map[string]any{
+		"name":       "Expired",
+		"actions":    []string{"organizations.read"},
+		"expires_at": time.Now().Add(-time.Hour),
+	})
+	s.Equal(http.StatusBadRequest, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestAPIKey_Expired_ShouldReturnUnauthorized tests key expiry
+func (s *APIKeysTestSuite) TestAPIKey_Expired_ShouldReturnUnauthorized() {
+	var created apiKey
+	s.data(s.createKey(map[string]any{
+		"name":       "Short lived",
+		"actions":    []string{"organizations.read"},
+		"expires_at": time.Now().Add(time.Hour),
+	}), http.StatusCreated, &created)
+	s.Equal(http.StatusOK, s.readOrganization(created.Key))
+
+	s.testSuite.Exec(`UPDATE auth.api_keys SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, created.ID)
+
+	s.Equal(http.StatusUnauthorized, s.readOrganization(created.Key))
+}
+
+// TestAPIKey_Invalid_ShouldReturnChallenge tests unknown and tampered keys
+func (s *APIKeysTestSuite) TestAPIKey_Invalid_ShouldReturnChallenge() {
+	var created apiKey
+	s.data(s.createKey(map[string]any{"name": "Tampered", "actions": []string{"organizations.read"}}), http.StatusCreated, &created)
+
+	for _, key := range []string{"not-a-key", created.Prefix + ".tampered", "sk_000000000000.secret"} {
+		resp, err := s.withKey(key).Get("/api/v1/organizations/" + s.organizationID.String())
+		s.Require().NoError(err)
+		s.Equal(http.StatusUnauthorized, resp.StatusCode(), key)
+		s.Contains(resp.Header().Get("WWW-Authenticate"), `error="invalid_key"`, key)
+	}
+}
+
+// TestAPIKeysTestSuite runs the API keys test suite
+func TestAPIKeysTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeysTestSuite))
+}
@@ -0,0 +1,125 @@
+//go:build integration
+
+package bearer
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// BearerTestSuite checks that private routes require a valid access token
+type BearerTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *BearerTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *BearerTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+// TestPrivateRoute_WithoutToken_ShouldReturnProblem tests the 401 problem details
+func (s *BearerTestSuite) TestPrivateRoute_WithoutToken_ShouldReturnProblem() {
+	// When: We call a private route without a token
+	resp, err := s.testSuite.Client.Client.R().Get("/api/v1/users")
+
+	// Then: We should get a 401 problem with a bearer challenge
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode())
+	s.Contains(resp.Header().Get("Content-Type"), "application/json")
+	s.Equal(`Bearer realm="api"`, resp.Header().Get("WWW-Authenticate"))
+
+	var problem map[string]any
+	s.Require().NoError(json.Unmarshal(resp.Body(), &problem))
+	s.Equal(float64(http.StatusUnauthorized), problem["status"])
+	s.Equal("unauthorized", problem["error_code"])
+	s.Equal("missing bearer token", problem["detail"])
+}
+
+// TestPrivateRoute_WithExpiredToken_ShouldReturnUnauthorized tests exp validation
+func (s *BearerTestSuite) TestPrivateRoute_WithExpiredToken_ShouldReturnUnauthorized() {
+	// Given: A token that expired an hour ago
+	claims := jwt.RegisteredClaims{
+		Subject:   uuid.NewString(),
+		IssuedAt:  jwt.NewNumericDate(time.Now().Add(-2 * time.Hour)),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+	}
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(shared.TestJWTSecret))
+	s.Require().NoError(err)
+
+	// When: We call a private route with it
+	resp, err := s.testSuite.Client.Client.R().SetAuthToken(expired).Get("/api/v1/users")
+
+	// Then: We should get a 401 saying the token expired
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode())
+	s.Contains(resp.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+
+	var problem map[string]any
+	s.Require().NoError(json.Unmarshal(resp.Body(), &problem))
+	s.Equal("token has expired", problem["detail"])
+}
+
+// TestPrivateRoute_WithValidToken_ShouldSucceed tests a valid HS256 token
+func (s *BearerTestSuite) TestPrivateRoute_WithValidToken_ShouldSucceed() {
+	// Given: A user allowed to read users and a valid access token
+	userID := s.testSuite.CreateUser("Ada")
+	s.testSuite.GrantPermissions(s.testSuite.CreateOrganization("Bearer"), userID, "users.read")
+	accessToken := s.testSuite.AccessToken(userID)
+
+	// When: We call a private route with it
+	resp, err := s.testSuite.Client.Client.R().SetAuthToken(accessToken).Get("/api/v1/users")
+
+	// Then: We should get the users
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode())
+}
+
+// TestPrivateRoute_WithoutPermission_ShouldReturnForbidden tests that a
+// valid token is not enough for routes that require a permission
+func (s *BearerTestSuite) TestPrivateRoute_WithoutPermission_ShouldReturnForbidden() {
+	// Given: A valid access token whose user has no roles
+	accessToken := s.testSuite.AccessToken(s.testSuite.CreateUser("Grace"), "admin")
+
+	// When: We call a private route with it
+	resp, err := s.testSuite.Client.Client.R().SetAuthToken(accessToken).Get("/api/v1/users")
+
+	// Then: We should get a 403 problem, the roles claim is not trusted
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode())
+
+	var problem map[string]any
+	s.Require().NoError(json.Unmarshal(resp.Body(), &problem))
+	s.Equal("forbidden", problem["error_code"])
+}
+
+// TestPublicRoutes_ShouldNotRequireToken tests that docs and health stay public
+func (s *BearerTestSuite) TestPublicRoutes_ShouldNotRequireToken() {
+	for _, path := range []string{"/health", "/docs/openapi.json"} {
+		resp, err := s.testSuite.Client.Client.R().Get(path)
+		s.Require().NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode(), "%s should not require a token", path)
+	}
+}
+
+// TestBearerTestSuite runs the bearer authentication test suite
+func TestBearerTestSuite(t *testing.T) {
+	suite.Run(t, new(BearerTestSuite))
+}
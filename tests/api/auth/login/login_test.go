@@ -0,0 +1,196 @@
+//go:build integration
+
+package login
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type tokens struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LoginTestSuite covers register, login, refresh and logout
+type LoginTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *LoginTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *LoginTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *LoginTestSuite) post(path string, body any) *resty.Response {
+	resp, err := s.testSuite.Client.Client.R().SetBody(body).Post(path)
+	s.Require().NoError(err, "Request to %s should not fail", path)
+	return resp
+}
+
+func (s *LoginTestSuite) tokens(resp *resty.Response, expectedStatus int) tokens {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	var body struct {
+		Data tokens `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+	s.Require().NotEmpty(body.Data.AccessToken)
+	s.Require().NotEmpty(body.Data.RefreshToken)
+	s.Equal("Bearer", body.Data.TokenType)
+	return body.Data
+}
+
+func (s *LoginTestSuite) register(email string) tokens {
+	return s.tokens(s.post("/api/v1/auth/register", map[string]any{
+		"email":      email,
+		"password":   "correct horse battery",
+		"first_name": "Ada",
+		"last_name":  "Lovelace",
+	}), http.StatusCreated)
+}
+
+func uniqueEmail() string {
+	return fmt.Sprintf("user-%s@example.com", uuid.NewString()[:8])
+}
+
+// TestRegister_ThenAccessPrivateRoute tests that the access token is accepted
+func (s *LoginTestSuite) TestRegister_ThenAccessPrivateRoute() {
+	// Given: A registered user
+	registered := s.register(uniqueEmail())
+
+	// When: We call a private route with the access token
+	resp, err := s.testSuite.Client.Client.R().SetAuthToken(registered.AccessToken).Get("/api/v1/users")
+
+	// Then: The request is authenticated but new users have no permissions
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode())
+
+	// When: The user is granted users.read
+	userID := s.testSuite.TokenUserID(registered.AccessToken)
+	s.testSuite.GrantPermissions(s.testSuite.CreateOrganization("Login"), userID, "users.read")
+	resp, err = s.testSuite.Client.Client.R().SetAuthToken(registered.AccessToken).Get("/api/v1/users")
+
+	// Then: The same token can list users
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode())
+}
+
+// TestLogin_ShouldIncludeRoles tests the roles claim of the access token
+func (s *LoginTestSuite) TestLogin_ShouldIncludeRoles() {
+	// Given: A registered user with a role
+	email := uniqueEmail()
+	userID := s.testSuite.TokenUserID(s.register(email).AccessToken)
+	role := s.testSuite.GrantPermissions(s.testSuite.CreateOrganization("Roles"), userID, "users.read")
+
+	// When: The user logs in again
+	logged := s.tokens(s.post("/api/v1/auth/login", map[string]any{"email": email, "password": "correct horse battery"}), http.StatusOK)
+
+	// Then: The access token lists the role
+	s.Equal([]string{role}, s.testSuite.TokenClaims(logged.AccessToken).Roles)
+}
+
+// TestRegister_DuplicateEmail_ShouldReturnConflict tests case insensitive email uniqueness
+func (s *LoginTestSuite) TestRegister_DuplicateEmail_ShouldReturnConflict() {
+	email := uniqueEmail()
+	s.register(email)
+
+	resp := s.post("/api/v1/auth/register", map[string]any{
+		"email":      strings.ToUpper(email),
+		"password":   "another password",
+		"first_name": "Grace",
+	})
+	s.Equal(http.StatusConflict, resp.StatusCode())
+}
+
+// TestLogin_WithValidAndInvalidPasswords tests login and the generic error
+func (s *LoginTestSuite) TestLogin_WithValidAndInvalidPasswords() {
+	email := uniqueEmail()
+	s.register(email)
+
+	s.tokens(s.post("/api/v1/auth/login", map[string]any{"email": email, "password": "correct horse battery"}), http.StatusOK)
+
+	wrongPassword := s.post("/api/v1/auth/login", map[string]any{"email": email, "password": "wrong password"})
+	unknownEmail := s.post("/api/v1/auth/login", map[string]any{"email": uniqueEmail(), "password": "wrong password"})
+
+	s.Equal(http.StatusUnauthorized, wrongPassword.StatusCode())
+	s.Equal(http.StatusUnauthorized, unknownEmail.StatusCode())
+
+	var wrong, unknown map[string]any
+	s.Require().NoError(json.Unmarshal(wrongPassword.Body(), &wrong))
+	s.Require().NoError(json.Unmarshal(unknownEmail.Body(), &unknown))
+	s.Equal(wrong["detail"], unknown["detail"], "Errors should not reveal which emails exist")
+}
+
+// TestLogin_RepeatedFailures_ShouldLockAccount tests the lockout
+func (s *LoginTestSuite) TestLogin_RepeatedFailures_ShouldLockAccount() {
+	email := uniqueEmail()
+	s.register(email)
+
+	// When: The password is wrong five times in a row
+	for i := 0; i < 5; i++ {
+		resp := s.post("/api/v1/auth/login", map[string]any{"email": email, "password": "wrong password"})
+		s.Equal(http.StatusUnauthorized, resp.StatusCode())
+	}
+
+	// Then: Even the right password is rejected while locked
+	resp := s.post("/api/v1/auth/login", map[string]any{"email": email, "password": "correct horse battery"})
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode())
+}
+
+// TestRefresh_RotatesAndDetectsReuse tests refresh token rotation
+func (s *LoginTestSuite) TestRefresh_RotatesAndDetectsReuse() {
+	first := s.register(uniqueEmail())
+
+	// When: The refresh token is rotated
+	second := s.tokens(s.post("/api/v1/auth/refresh", map[string]any{"refresh_token": first.RefreshToken}), http.StatusOK)
+	s.NotEqual(first.RefreshToken, second.RefreshToken)
+
+	// Then: Reusing the rotated token fails and revokes the family
+	reused := s.post("/api/v1/auth/refresh", map[string]any{"refresh_token": first.RefreshToken})
+	s.Equal(http.StatusUnauthorized, reused.StatusCode())
+
+	revoked := s.post("/api/v1/auth/refresh", map[string]any{"refresh_token": second.RefreshToken})
+	s.Equal(http.StatusUnauthorized, revoked.StatusCode(), "Tokens rotated from a reused token should be revoked")
+}
+
+// TestLogout_RevokesRefreshToken tests logout
+func (s *LoginTestSuite) TestLogout_RevokesRefreshToken() {
+	registered := s.register(uniqueEmail())
+
+	resp := s.post("/api/v1/auth/logout", map[string]any{"refresh_token": registered.RefreshToken})
+	s.Equal(http.StatusNoContent, resp.StatusCode())
+
+	refreshed := s.post("/api/v1/auth/refresh", map[string]any{"refresh_token": registered.RefreshToken})
+	s.Equal(http.StatusUnauthorized, refreshed.StatusCode())
+
+	// Logging out twice is not an error
+	resp = s.post("/api/v1/auth/logout", map[string]any{"refresh_token": registered.RefreshToken})
+	s.Equal(http.StatusNoContent, resp.StatusCode())
+}
+
+// TestLoginTestSuite runs the login test suite
+func TestLoginTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginTestSuite))
+}
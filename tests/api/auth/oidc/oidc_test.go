@@ -0,0 +1,228 @@
+//go:build integration
+
+package oidc
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type authorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	ExpiresIn        int64  `json:"expires_in"`
+}
+
+type tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// OIDCTestSuite covers the login with an OpenID Connect provider, against
+// a fake provider running in the test process
+type OIDCTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+	provider  *shared.OIDCProvider
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *OIDCTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	s.provider = shared.NewOIDCProvider(s.testSuite, "fake")
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *OIDCTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *OIDCTestSuite) post(path string, body any) *resty.Response {
+	req := s.testSuite.Client.Client.R()
+	if body != nil {
+		req.SetBody(body)
+	}
+
+	resp, err := req.Post(path)
+	s.Require().NoError(err, "Request to %s should not fail", path)
+	return resp
+}
+
+func (s *OIDCTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+// authorize starts a login and returns the code and state the provider
+// redirects the user back with
+func (s *OIDCTestSuite) authorize(user shared.OIDCUser) (code, state string) {
+	var started authorization
+	s.data(s.post("/api/v1/auth/oidc/fake/authorize", nil), http.StatusOK, &started)
+	s.NotEmpty(started.State)
+	s.Positive(started.ExpiresIn)
+
+	code, state = s.provider.Authorize(s.T(), started.AuthorizationURL, user)
+	s.Equal(started.State, state)
+	return code, state
+}
+
+func (s *OIDCTestSuite) callback(code, state string) *resty.Response {
+	return s.post("/api/v1/auth/oidc/fake/callback", map[string]any{"code": code, "state": state})
+}
+
+// login logs the user in with the provider and returns its tokens
+func (s *OIDCTestSuite) login(user shared.OIDCUser) tokens {
+	var issued tokens
+	s.data(s.callback(s.authorize(user)), http.StatusOK, &issued)
+	s.NotEmpty(issued.AccessToken)
+	s.NotEmpty(issued.RefreshToken)
+	return issued
+}
+
+// TestProviders_ShouldListConfiguredProviders tests the provider listing
+func (s *OIDCTestSuite) TestProviders_ShouldListConfiguredProviders() {
+	resp, err := s.testSuite.Client.Client.R().Get("/api/v1/auth/oidc/providers")
+	s.Require().NoError(err)
+
+	var providers []struct {
+		Name string `json:"name"`
+	}
+	s.data(resp, http.StatusOK, &providers)
+	s.Require().Len(providers, 1)
+	s.Equal("fake", providers[0].Name)
+}
+
+// TestLogin_FirstLogin_ShouldCreateUser tests that new accounts get a user
+// whose origin is the provider
+func (s *OIDCTestSuite) TestLogin_FirstLogin_ShouldCreateUser() {
+	// Given: An account unknown to the API
+	user := shared.NewOIDCUser("Grace")
+
+	// When: The user logs in twice
+	first := s.login(user)
+	second := s.login(user)
+
+	// Then: One user was created from the claims and both logins are theirs
+	userID := s.testSuite.TokenUserID(first.AccessToken)
+	s.Equal(userID, s.testSuite.TokenUserID(second.AccessToken))
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.users
+		WHERE id = $1 AND origin = 'FAKE' AND first_name = 'Grace' AND last_name = 'Tester' AND picture = $2`,
+		userID, user.Picture))
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.user_identities
+		WHERE user_id = $1 AND provider = 'fake' AND subject = $2 AND last_login_at IS NOT NULL`,
+		userID, user.Subject))
+
+	// Then: The refresh token works like the one of any login
+	resp := s.post("/api/v1/auth/refresh", map[string]any{"refresh_token": second.RefreshToken})
+	s.Equal(http.StatusOK, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestLogin_VerifiedEmail_ShouldLinkExistingUser tests linking to the user
+// of a verified email credential
+func (s *OIDCTestSuite) TestLogin_VerifiedEmail_ShouldLinkExistingUser() {
+	// Given: A user registered with the email of the account, who verified it
+	user := shared.NewOIDCUser("Ada")
+	resp := s.post("/api/v1/auth/register", map[string]any{
+		"email":      user.Email,
+		"password":   "correct horse battery",
+		"first_name": "Ada",
+	})
+	var registered tokens
+	s.data(resp, http.StatusCreated, &registered)
+	resp = s.post("/api/v1/auth/verify-email", map[string]any{"token": s.testSuite.EmailToken(user.Email)})
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+	userID := s.testSuite.TokenUserID(registered.AccessToken)
+
+	// When: The user logs in with the provider
+	issued := s.login(user)
+
+	// Then: The account is linked to the existing user, who gets its picture
+	s.Equal(userID, s.testSuite.TokenUserID(issued.AccessToken))
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.users WHERE id = $1 AND origin = 'EMAIL' AND picture = $2`, userID, user.Picture))
+}
+
+// TestLogin_UnverifiedEmail_ShouldNotLinkExistingUser tests that unverified
+// emails cannot take over accounts
+func (s *OIDCTestSuite) TestLogin_UnverifiedEmail_ShouldNotLinkExistingUser() {
+	// Given: A user registered with the email of the account, who did not
+	// verify it
+	user := shared.NewOIDCUser("Mallory")
+	resp := s.post("/api/v1/auth/register", map[string]any{
+		"email":      user.Email,
+		"password":   "correct horse battery",
+		"first_name": "Mallory",
+	})
+	var registered tokens
+	s.data(resp, http.StatusCreated, &registered)
+
+	// When: Someone logs in with a provider account of the same email
+	issued := s.login(user)
+
+	// Then: They get a user of their own
+	s.NotEqual(s.testSuite.TokenUserID(registered.AccessToken), s.testSuite.TokenUserID(issued.AccessToken))
+}
+
+// TestCallback_StateIsSingleUse tests that a state cannot be replayed
+func (s *OIDCTestSuite) TestCallback_StateIsSingleUse() {
+	code, state := s.authorize(shared.NewOIDCUser("Replay"))
+
+	first := s.callback(code, state)
+	second := s.callback(code, state)
+
+	s.Equal(http.StatusOK, first.StatusCode(), "Unexpected status: %s", first.Body())
+	s.Equal(http.StatusUnauthorized, second.StatusCode(), "Unexpected status: %s", second.Body())
+}
+
+// TestCallback_UnknownState_ShouldReturnUnauthorized tests forged callbacks
+func (s *OIDCTestSuite) TestCallback_UnknownState_ShouldReturnUnauthorized() {
+	code, _ := s.authorize(shared.NewOIDCUser("Forged"))
+
+	resp := s.callback(code, uuid.NewString())
+	s.Equal(http.StatusUnauthorized, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCallback_RejectedCode_ShouldReturnUnauthorized tests codes the
+// provider does not know
+func (s *OIDCTestSuite) TestCallback_RejectedCode_ShouldReturnUnauthorized() {
+	_, state := s.authorize(shared.NewOIDCUser("Rejected"))
+
+	resp := s.callback("not-a-code", state)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestLogin_DisabledUser_ShouldReturnForbidden tests that disabled users
+// cannot log in with a provider
+func (s *OIDCTestSuite) TestLogin_DisabledUser_ShouldReturnForbidden() {
+	user := shared.NewOIDCUser("Disabled")
+	userID := s.testSuite.TokenUserID(s.login(user).AccessToken)
+	s.testSuite.Exec(`UPDATE auth.users SET is_active = false WHERE id = $1`, userID)
+
+	resp := s.callback(s.authorize(user))
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestAuthorize_UnknownProvider_ShouldReturnNotFound tests provider names
+func (s *OIDCTestSuite) TestAuthorize_UnknownProvider_ShouldReturnNotFound() {
+	resp := s.post("/api/v1/auth/oidc/unknown/authorize", nil)
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestOIDCTestSuite runs the OIDC test suite
+func TestOIDCTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCTestSuite))
+}
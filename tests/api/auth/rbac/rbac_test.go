@@ -0,0 +1,134 @@
+//go:build integration
+
+package rbac
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// RBACTestSuite checks route permissions and visibility scopes
+type RBACTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *RBACTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *RBACTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+// listUserIDs lists the users visible to userID
+func (s *RBACTestSuite) listUserIDs(userID uuid.UUID) []uuid.UUID {
+	resp, err := s.testSuite.Client.Client.R().
+		SetAuthToken(s.testSuite.AccessToken(userID)).
+		SetQueryParam("page_size", "100").
+		Get("/api/v1/users")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	var body struct {
+		Data []struct {
+			ID uuid.UUID `json:"id"`
+		} `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+
+	ids := make([]uuid.UUID, 0, len(body.Data))
+	for _, user := range body.Data {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+// getUserStatus gets id as userID and returns the status code
+func (s *RBACTestSuite) getUserStatus(userID, id uuid.UUID) int {
+	resp, err := s.testSuite.Client.Client.R().
+		SetAuthToken(s.testSuite.AccessToken(userID)).
+		Get("/api/v1/users/" + id.String())
+	s.Require().NoError(err)
+	return resp.StatusCode()
+}
+
+// TestMissingPermission_ShouldReturnForbidden tests that each route checks its own permission
+func (s *RBACTestSuite) TestMissingPermission_ShouldReturnForbidden() {
+	// Given: A user allowed to read but not to delete users
+	userID := s.testSuite.CreateUser("Reader")
+	s.testSuite.GrantPermissions(s.testSuite.CreateOrganization("Readers"), userID, "users.read")
+
+	// When: The user deletes itself
+	resp, err := s.testSuite.Client.Client.R().
+		SetAuthToken(s.testSuite.AccessToken(userID)).
+		Delete("/api/v1/users/" + userID.String())
+
+	// Then: The request is forbidden and the user still exists
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode())
+	s.Equal(http.StatusOK, s.getUserStatus(userID, userID))
+}
+
+// TestOwnScope_ShouldOnlyShowCreatedUsers tests the OWN visibility scope
+func (s *RBACTestSuite) TestOwnScope_ShouldOnlyShowCreatedUsers() {
+	s.testSuite.SetVisibilityScope("users.read", "OWN")
+	defer s.testSuite.SetVisibilityScope("users.read", "ALL")
+
+	// Given: A user that created one user, and a user created by someone else
+	userID := s.testSuite.CreateUser("Owner")
+	s.testSuite.GrantPermissions(s.testSuite.CreateOrganization("Owners"), userID, "users.read")
+
+	owned := s.testSuite.CreateUser("Owned")
+	s.testSuite.Exec(`UPDATE auth.users SET created_by = $1 WHERE id = $2`, userID, owned)
+	other := s.testSuite.CreateUser("Other")
+
+	// When: The user lists users
+	ids := s.listUserIDs(userID)
+
+	// Then: Only the created user is visible
+	s.Equal([]uuid.UUID{owned}, ids)
+	s.Equal(http.StatusOK, s.getUserStatus(userID, owned))
+	s.Equal(http.StatusNotFound, s.getUserStatus(userID, other))
+}
+
+// TestOrgScope_ShouldOnlyShowMembers tests the ORG visibility scope
+func (s *RBACTestSuite) TestOrgScope_ShouldOnlyShowMembers() {
+	s.testSuite.SetVisibilityScope("users.read", "ORG")
+	defer s.testSuite.SetVisibilityScope("users.read", "ALL")
+
+	// Given: A user of an organization with another member, and an outsider
+	organizationID := s.testSuite.CreateOrganization("Members")
+	userID := s.testSuite.CreateUser("Member")
+	s.testSuite.GrantPermissions(organizationID, userID, "users.read")
+
+	member := s.testSuite.CreateUser("Colleague")
+	s.testSuite.AddMember(organizationID, member)
+	outsider := s.testSuite.CreateUser("Outsider")
+	s.testSuite.AddMember(s.testSuite.CreateOrganization("Outsiders"), outsider)
+
+	// When: The user lists users
+	ids := s.listUserIDs(userID)
+
+	// Then: Only the members of the organization are visible
+	s.ElementsMatch([]uuid.UUID{userID, member}, ids)
+	s.Equal(http.StatusOK, s.getUserStatus(userID, member))
+	s.Equal(http.StatusNotFound, s.getUserStatus(userID, outsider))
+}
+
+// TestRBACTestSuite runs the RBAC test suite
+func TestRBACTestSuite(t *testing.T) {
+	suite.Run(t, new(RBACTestSuite))
+}
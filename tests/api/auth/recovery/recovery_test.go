@@ -0,0 +1,189 @@
+//go:build integration
+
+package recovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const password = "correct horse battery"
+
+// RecoveryTestSuite covers email verification and password reset
+type RecoveryTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *RecoveryTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *RecoveryTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *RecoveryTestSuite) post(path string, body any, headers ...string) *resty.Response {
+	req := s.testSuite.Client.Client.R().SetBody(body)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.SetHeader(headers[i], headers[i+1])
+	}
+
+	resp, err := req.Post(path)
+	s.Require().NoError(err, "Request to %s should not fail", path)
+	return resp
+}
+
+// register creates an account and returns its refresh token
+func (s *RecoveryTestSuite) register(email string, headers ...string) string {
+	resp := s.post("/api/v1/auth/register", map[string]any{
+		"email":      email,
+		"password":   password,
+		"first_name": "Ada",
+	}, headers...)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	var body struct {
+		Data struct {
+			RefreshToken string `json:"refresh_token"`
+		} `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+	return body.Data.RefreshToken
+}
+
+func (s *RecoveryTestSuite) login(email, pw string) *resty.Response {
+	return s.post("/api/v1/auth/login", map[string]any{"email": email, "password": pw})
+}
+
+func uniqueEmail() string {
+	return fmt.Sprintf("user-%s@example.com", uuid.NewString()[:8])
+}
+
+// TestVerifyEmail_SingleUse tests that the registration email verifies the account once
+func (s *RecoveryTestSuite) TestVerifyEmail_SingleUse() {
+	// Given: A registered user
+	email := uniqueEmail()
+	s.register(email)
+
+	subject, text := s.testSuite.LastEmail(email)
+	s.Equal("Verify your email address", subject)
+	s.Contains(text, "Hi Ada")
+	token := s.testSuite.EmailToken(email)
+
+	// When: Verifying with the emailed token twice
+	first := s.post("/api/v1/auth/verify-email", map[string]any{"token": token})
+	second := s.post("/api/v1/auth/verify-email", map[string]any{"token": token})
+
+	// Then: Only the first succeeds
+	s.Equal(http.StatusNoContent, first.StatusCode(), "Unexpected status: %s", first.Body())
+	s.Equal(http.StatusBadRequest, second.StatusCode())
+}
+
+// TestVerifyEmail_LocalizedTemplate tests that emails follow Accept-Language
+func (s *RecoveryTestSuite) TestVerifyEmail_LocalizedTemplate() {
+	// Given: A user registered in Spanish
+	email := uniqueEmail()
+	s.register(email, "Accept-Language", "es-PE,es;q=0.9")
+
+	// Then: The email is in Spanish
+	subject, text := s.testSuite.LastEmail(email)
+	s.Equal("Verifica tu correo electrónico", subject)
+	s.Contains(text, "Hola Ada")
+}
+
+// TestResendVerification_InvalidatesPreviousToken tests that only the newest link works
+func (s *RecoveryTestSuite) TestResendVerification_InvalidatesPreviousToken() {
+	// Given: A registered user who asked for a new verification email
+	email := uniqueEmail()
+	s.register(email)
+	previous := s.testSuite.EmailToken(email)
+
+	resp := s.post("/api/v1/auth/verify-email/resend", map[string]any{"email": email})
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+	current := s.testSuite.EmailToken(email)
+	s.Require().NotEqual(previous, current)
+
+	// Then: The previous token is rejected and the new one accepted
+	s.Equal(http.StatusBadRequest, s.post("/api/v1/auth/verify-email", map[string]any{"token": previous}).StatusCode())
+	s.Equal(http.StatusNoContent, s.post("/api/v1/auth/verify-email", map[string]any{"token": current}).StatusCode())
+}
+
+// TestUnknownEmail_DoesNotRevealAccounts tests that unknown emails get the same response
+func (s *RecoveryTestSuite) TestUnknownEmail_DoesNotRevealAccounts() {
+	for _, path := range []string{"/api/v1/auth/verify-email/resend", "/api/v1/auth/forgot-password"} {
+		resp := s.post(path, map[string]any{"email": uniqueEmail()})
+		s.Equal(http.StatusNoContent, resp.StatusCode(), "%s: %s", path, resp.Body())
+	}
+}
+
+// TestResetPassword tests the forgot password flow
+func (s *RecoveryTestSuite) TestResetPassword() {
+	// Given: A logged in user who forgot their password
+	email := uniqueEmail()
+	refreshToken := s.register(email)
+
+	resp := s.post("/api/v1/auth/forgot-password", map[string]any{"email": email})
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	subject, _ := s.testSuite.LastEmail(email)
+	s.Equal("Reset your password", subject)
+	token := s.testSuite.EmailToken(email)
+
+	// When: Resetting the password with the emailed token
+	resp = s.post("/api/v1/auth/reset-password", map[string]any{"token": token, "password": "a brand new password"})
+
+	// Then: The new password works, the old one and the old session do not
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+	s.Equal(http.StatusOK, s.login(email, "a brand new password").StatusCode())
+	s.Equal(http.StatusUnauthorized, s.login(email, password).StatusCode())
+	s.Equal(http.StatusUnauthorized, s.post("/api/v1/auth/refresh", map[string]any{"refresh_token": refreshToken}).StatusCode())
+
+	// And: The token cannot be used again
+	resp = s.post("/api/v1/auth/reset-password", map[string]any{"token": token, "password": "yet another password"})
+	s.Equal(http.StatusBadRequest, resp.StatusCode())
+}
+
+// TestResetPassword_UnlocksAccount tests that a reset lifts the login lockout
+func (s *RecoveryTestSuite) TestResetPassword_UnlocksAccount() {
+	// Given: A user locked out after too many failed logins
+	email := uniqueEmail()
+	s.register(email)
+	for i := 0; i < 5; i++ {
+		s.login(email, "wrong password")
+	}
+	s.Require().Equal(http.StatusTooManyRequests, s.login(email, password).StatusCode())
+
+	// When: Resetting the password
+	s.post("/api/v1/auth/forgot-password", map[string]any{"email": email})
+	resp := s.post("/api/v1/auth/reset-password", map[string]any{"token": s.testSuite.EmailToken(email), "password": "a brand new password"})
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: The user can log in again
+	s.Equal(http.StatusOK, s.login(email, "a brand new password").StatusCode())
+}
+
+// TestResetPassword_InvalidToken tests that unknown tokens are rejected
+func (s *RecoveryTestSuite) TestResetPassword_InvalidToken() {
+	resp := s.post("/api/v1/auth/reset-password", map[string]any{"token": "not-a-token", "password": "a brand new password"})
+	s.Equal(http.StatusBadRequest, resp.StatusCode())
+}
+
+// TestRecoveryTestSuite runs the email verification and password reset test suite
+func TestRecoveryTestSuite(t *testing.T) {
+	suite.Run(t, new(RecoveryTestSuite))
+}
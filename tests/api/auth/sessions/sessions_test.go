@@ -0,0 +1,243 @@
+//go:build integration
+
+package sessions
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const password = "correct horse battery"
+
+type session struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	UserAgent  *string   `json:"user_agent"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// SessionsTestSuite covers the login sessions of users: listing them and
+// revoking them, by their user or by an admin
+type SessionsTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *SessionsTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *SessionsTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *SessionsTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *SessionsTestSuite) post(path, userAgent string, body any) *resty.Response {
+	resp, err := s.testSuite.Client.Client.R().SetHeader("User-Agent", userAgent).SetBody(body).Post(path)
+	s.Require().NoError(err, "Request to %s should not fail", path)
+	return resp
+}
+
+func (s *SessionsTestSuite) register(email string) tokens {
+	var issued tokens
+	resp := s.post("/api/v1/auth/register", "device-register", map[string]any{
+		"email":      email,
+		"password":   password,
+		"first_name": "Session",
+	})
+	s.data(resp, http.StatusCreated, &issued)
+	return issued
+}
+
+func (s *SessionsTestSuite) login(email, userAgent string) tokens {
+	var issued tokens
+	resp := s.post("/api/v1/auth/login", userAgent, map[string]any{"email": email, "password": password})
+	s.data(resp, http.StatusOK, &issued)
+	return issued
+}
+
+func (s *SessionsTestSuite) get(accessToken, path string) *resty.Response {
+	resp, err := s.testSuite.Client.Client.R().SetAuthToken(accessToken).Get(path)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *SessionsTestSuite) delete(accessToken, path string) *resty.Response {
+	resp, err := s.testSuite.Client.Client.R().SetAuthToken(accessToken).Delete(path)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *SessionsTestSuite) mySessions(accessToken string) []session {
+	var sessions []session
+	s.data(s.get(accessToken, "/api/v1/me/sessions"), http.StatusOK, &sessions)
+	return sessions
+}
+
+func (s *SessionsTestSuite) sessionID(accessToken string) uuid.UUID {
+	id, err := uuid.Parse(s.testSuite.TokenClaims(accessToken).SessionID)
+	s.Require().NoError(err, "Access tokens should carry their session")
+	return id
+}
+
+func email(name string) string {
+	return name + "-" + uuid.NewString()[:8] + "@example.com"
+}
+
+// TestMySessions_ShouldListDevices tests that every login opens a session
+// with its device
+func (s *SessionsTestSuite) TestMySessions_ShouldListDevices() {
+	// Given: A user logged in on two devices
+	address := email("devices")
+	s.register(address)
+	laptop := s.login(address, "laptop")
+	phone := s.login(address, "phone")
+
+	// When: The user lists their sessions from the phone
+	sessions := s.mySessions(phone.AccessToken)
+
+	// Then: Both logins and the registration are listed, the phone first
+	// and flagged as current
+	s.Require().Len(sessions, 3)
+	s.Equal(s.sessionID(phone.AccessToken), sessions[0].ID)
+	s.True(sessions[0].Current)
+	s.Require().NotNil(sessions[0].UserAgent)
+	s.Equal("phone", *sessions[0].UserAgent)
+	s.Equal(s.sessionID(laptop.AccessToken), sessions[1].ID)
+	s.False(sessions[1].Current)
+}
+
+// TestRefresh_ShouldKeepSession tests that refreshed tokens stay in their
+// session, which follows the latest device
+func (s *SessionsTestSuite) TestRefresh_ShouldKeepSession() {
+	address := email("refresh")
+	issued := s.register(address)
+
+	var refreshed tokens
+	s.data(s.post("/api/v1/auth/refresh", "upgraded-browser", map[string]any{"refresh_token": issued.RefreshToken}), http.StatusOK, &refreshed)
+
+	s.Equal(s.sessionID(issued.AccessToken), s.sessionID(refreshed.AccessToken))
+	sessions := s.mySessions(refreshed.AccessToken)
+	s.Require().Len(sessions, 1)
+	s.Require().NotNil(sessions[0].UserAgent)
+	s.Equal("upgraded-browser", *sessions[0].UserAgent)
+}
+
+// TestRevokeMySession_ShouldRejectItsTokens tests ending the session of a
+// lost device from another one
+func (s *SessionsTestSuite) TestRevokeMySession_ShouldRejectItsTokens() {
+	// Given: A user logged in on two devices
+	address := email("lost")
+	s.register(address)
+	lost := s.login(address, "lost-phone")
+	laptop := s.login(address, "laptop")
+
+	// When: The user ends the session of the lost phone from the laptop
+	resp := s.delete(laptop.AccessToken, "/api/v1/me/sessions/"+s.sessionID(lost.AccessToken).String())
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: The access and refresh tokens of the phone are rejected right
+	// away, and the laptop keeps working
+	s.Equal(http.StatusUnauthorized, s.get(lost.AccessToken, "/api/v1/me/sessions").StatusCode())
+	refresh := s.post("/api/v1/auth/refresh", "lost-phone", map[string]any{"refresh_token": lost.RefreshToken})
+	s.Equal(http.StatusUnauthorized, refresh.StatusCode())
+	s.Len(s.mySessions(laptop.AccessToken), 2)
+}
+
+// TestRevokeMySession_OtherUser_ShouldReturnNotFound tests that users only
+// end their own sessions
+func (s *SessionsTestSuite) TestRevokeMySession_OtherUser_ShouldReturnNotFound() {
+	victim := s.register(email("victim"))
+	attacker := s.register(email("attacker"))
+
+	resp := s.delete(attacker.AccessToken, "/api/v1/me/sessions/"+s.sessionID(victim.AccessToken).String())
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+	s.Equal(http.StatusOK, s.get(victim.AccessToken, "/api/v1/me/sessions").StatusCode())
+}
+
+// TestLogout_ShouldRejectAccessToken tests that logging out ends the
+// session of the access token too
+func (s *SessionsTestSuite) TestLogout_ShouldRejectAccessToken() {
+	issued := s.register(email("logout"))
+
+	resp := s.post("/api/v1/auth/logout", "device", map[string]any{"refresh_token": issued.RefreshToken})
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	s.Equal(http.StatusUnauthorized, s.get(issued.AccessToken, "/api/v1/me/sessions").StatusCode())
+}
+
+// TestRevokeUserSessions_ShouldLogUserOut tests the force logout of a user
+// by an admin
+func (s *SessionsTestSuite) TestRevokeUserSessions_ShouldLogUserOut() {
+	// Given: A user logged in on two devices, and an admin allowed to
+	// manage sessions
+	address := email("compromised")
+	issued := s.register(address)
+	s.login(address, "unknown-device")
+	userID := s.testSuite.TokenUserID(issued.AccessToken)
+
+	organizationID := s.testSuite.CreateOrganization("Sessions")
+	adminID := s.testSuite.CreateUser("Admin")
+	s.testSuite.GrantPermissions(organizationID, adminID, "users.sessions")
+	adminToken := s.testSuite.AccessToken(adminID)
+	path := "/api/v1/users/" + userID.String() + "/sessions"
+
+	var sessions []session
+	s.data(s.get(adminToken, path), http.StatusOK, &sessions)
+	s.Len(sessions, 2)
+
+	// When: The admin logs the user out
+	resp := s.delete(adminToken, path)
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: Every session is revoked by the admin and its tokens rejected
+	s.Equal(http.StatusUnauthorized, s.get(issued.AccessToken, "/api/v1/me/sessions").StatusCode())
+	s.Equal(2, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.sessions WHERE user_id = $1 AND revoked_by = $2`, userID, adminID))
+	s.Equal(0, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.refresh_tokens WHERE user_id = $1 AND revoked_at IS NULL`, userID))
+	s.data(s.get(adminToken, path), http.StatusOK, &sessions)
+	s.Empty(sessions)
+}
+
+// TestUserSessions_WithoutPermission_ShouldReturnForbidden tests that only
+// admins see the sessions of other users
+func (s *SessionsTestSuite) TestUserSessions_WithoutPermission_ShouldReturnForbidden() {
+	victim := s.register(email("private"))
+	other := s.register(email("curious"))
+
+	resp := s.get(other.AccessToken, "/api/v1/users/"+s.testSuite.TokenUserID(victim.AccessToken).String()+"/sessions")
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestSessionsTestSuite runs the sessions test suite
+func TestSessionsTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionsTestSuite))
+}
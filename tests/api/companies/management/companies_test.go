@@ -0,0 +1,304 @@
+//go:build integration
+
+package management
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type company struct {
+	ID           uuid.UUID `json:"id"`
+	BusinessName string    `json:"business_name"`
+	TaxID        *string   `json:"tax_id"`
+	CompanyType  string    `json:"company_type"`
+	Status       string    `json:"status"`
+	IsActive     bool      `json:"is_active"`
+	DeletedAt    *string   `json:"deleted_at"`
+}
+
+// CompaniesTestSuite covers company management and its organization
+// scoping
+type CompaniesTestSuite struct {
+	suite.Suite
+	testSuite  *shared.TestSuite
+	orgA       uuid.UUID
+	adminToken string
+	otherToken string
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *CompaniesTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+
+	// Given: The administrators of the companies of two organizations
+	permissions := []string{"companies.create", "companies.read", "companies.update", "companies.delete"}
+
+	s.orgA = s.testSuite.CreateOrganization("Companies A")
+	adminID := s.testSuite.CreateUser("Admin")
+	s.testSuite.GrantPermissions(s.orgA, adminID, permissions...)
+	s.adminToken = s.testSuite.AccessToken(adminID)
+
+	orgB := s.testSuite.CreateOrganization("Companies B")
+	otherID := s.testSuite.CreateUser("Other")
+	s.testSuite.GrantPermissions(orgB, otherID, permissions...)
+	s.otherToken = s.testSuite.AccessToken(otherID)
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *CompaniesTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *CompaniesTestSuite) request() *resty.Request {
+	return s.testSuite.Client.Client.R().SetAuthToken(s.adminToken)
+}
+
+func (s *CompaniesTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *CompaniesTestSuite) createCompany(body map[string]any) *resty.Response {
+	resp, err := s.request().SetBody(body).Post("/api/v1/companies")
+	s.Require().NoError(err)
+	return resp
+}
+
+// TestCompanyLifeCycle_ShouldSoftDeleteAndRestore tests create, get,
+// update, delete and restore
+func (s *CompaniesTestSuite) TestCompanyLifeCycle_ShouldSoftDeleteAndRestore() {
+	// When: We create a company without type and status
+	var created company
+	s.data(s.createCompany(map[string]any{"business_name": "Acme S.A.", "tax_id": "20123456789"}), http.StatusCreated, &created)
+
+	// Then: It is an active customer of the organization
+	s.Equal("CUSTOMER", created.CompanyType)
+	s.Equal("ACTIVE", created.Status)
+	s.True(created.IsActive)
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.organization_customers WHERE organization_id = $1 AND customer_id = $2`, s.orgA, created.ID))
+	path := "/api/v1/companies/" + created.ID.String()
+
+	// When: We update it
+	resp, err := s.request().SetBody(map[string]any{"business_name": "Acme Inc", "company_type": "PARTNER", "tax_id": ""}).Put(path)
+	s.Require().NoError(err)
+	var updated company
+	s.data(resp, http.StatusOK, &updated)
+	s.Equal("Acme Inc", updated.BusinessName)
+	s.Equal("PARTNER", updated.CompanyType)
+	s.Nil(updated.TaxID)
+
+	// When: We delete it
+	resp, err = s.request().Delete(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNoContent, resp.StatusCode())
+
+	// Then: It is not found anymore, but listed among the deleted ones
+	resp, err = s.request().Get(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+
+	resp, err = s.request().SetQueryParams(map[string]string{"deleted": "true", "search": "Acme Inc"}).Get("/api/v1/companies")
+	s.Require().NoError(err)
+	var deleted []company
+	s.data(resp, http.StatusOK, &deleted)
+	s.Require().Len(deleted, 1)
+	s.NotNil(deleted[0].DeletedAt)
+
+	// When: We restore it
+	resp, err = s.request().Post(path + "/restore")
+	s.Require().NoError(err)
+	var restored company
+	s.data(resp, http.StatusOK, &restored)
+
+	// Then: It is live again, and cannot be restored twice
+	s.Nil(restored.DeletedAt)
+	resp, err = s.request().Get(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode())
+
+	resp, err = s.request().Post(path + "/restore")
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCreateCompany_UnknownOption_ShouldReturnValidationError tests the
+// validation of company_type and status against their catalogs
+func (s *CompaniesTestSuite) TestCreateCompany_UnknownOption_ShouldReturnValidationError() {
+	resp := s.createCompany(map[string]any{"business_name": "Invalid", "company_type": "GALAXY"})
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	resp = s.createCompany(map[string]any{"business_name": "Invalid", "status": "DORMANT"})
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	var created company
+	s.data(s.createCompany(map[string]any{"business_name": "Valid", "company_type": "SUPPLIER", "status": "UNDER_REVIEW"}), http.StatusCreated, &created)
+
+	resp, err := s.request().SetBody(map[string]any{"status": "DORMANT"}).Put("/api/v1/companies/" + created.ID.String())
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCompanyTaxID_ShouldBeValidAndUniqueInTheOrganization tests the
+// validation, normalization and uniqueness of tax ids
+func (s *CompaniesTestSuite) TestCompanyTaxID_ShouldBeValidAndUniqueInTheOrganization() {
+	// When: We create Peruvian companies with invalid RUCs
+	resp := s.createCompany(map[string]any{"business_name": "Bad RUC", "country": "PE", "tax_id": "20100047219"})
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+	resp = s.createCompany(map[string]any{"business_name": "Bad RUC", "country": "PE", "tax_id": "2010004721"})
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// When: We create one with a valid RUC written with separators
+	var created company
+	s.data(s.createCompany(map[string]any{"business_name": "Good RUC", "country": "PE", "tax_id": "20-10004721.8"}), http.StatusCreated, &created)
+
+	// Then: It is stored normalized
+	s.Require().NotNil(created.TaxID)
+	s.Equal("20100047218", *created.TaxID)
+
+	// Then: The RUC cannot be used again in the organization
+	resp = s.createCompany(map[string]any{"business_name": "Same RUC", "country": "PE", "tax_id": "20100047218"})
+	s.Equal(http.StatusConflict, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	var other company
+	s.data(s.createCompany(map[string]any{"business_name": "Other RUC", "country": "PE", "tax_id": "20131312955"}), http.StatusCreated, &other)
+	resp, err := s.request().SetBody(map[string]any{"tax_id": "20100047218"}).Put("/api/v1/companies/" + other.ID.String())
+	s.Require().NoError(err)
+	s.Equal(http.StatusConflict, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: But it can in other organizations
+	resp, err = s.testSuite.Client.Client.R().SetAuthToken(s.otherToken).
+		SetBody(map[string]any{"business_name": "Good RUC", "country": "PE", "tax_id": "20100047218"}).
+		Post("/api/v1/companies")
+	s.Require().NoError(err)
+	s.Equal(http.StatusCreated, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// When: The company is deleted and its RUC is taken
+	resp, err = s.request().Delete("/api/v1/companies/" + created.ID.String())
+	s.Require().NoError(err)
+	s.Equal(http.StatusNoContent, resp.StatusCode())
+	resp, err = s.request().SetBody(map[string]any{"tax_id": "20100047218"}).Put("/api/v1/companies/" + other.ID.String())
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: It cannot be restored
+	resp, err = s.request().Post("/api/v1/companies/" + created.ID.String() + "/restore")
+	s.Require().NoError(err)
+	s.Equal(http.StatusConflict, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCreateCompany_ConcurrentTaxID_ShouldCreateOnlyOne tests that
+// concurrent creates of the same tax id are serialized
+func (s *CompaniesTestSuite) TestCreateCompany_ConcurrentTaxID_ShouldCreateOnlyOne() {
+	const attempts = 8
+	taxID := "CONCURRENT" + strings.ToUpper(uuid.NewString()[:8])
+
+	statuses := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := s.request().SetBody(map[string]any{"business_name": "Concurrent", "tax_id": taxID}).Post("/api/v1/companies")
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			statuses <- resp.StatusCode()
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	counts := map[int]int{}
+	for status := range statuses {
+		counts[status]++
+	}
+	s.Equal(map[int]int{http.StatusCreated: 1, http.StatusConflict: attempts - 1}, counts)
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM relationships.companies WHERE tax_id = $1`, taxID))
+}
+
+// TestListCompanies_ShouldSearchByBusinessNameAndTaxID tests searching and
+// counting
+func (s *CompaniesTestSuite) TestListCompanies_ShouldSearchByBusinessNameAndTaxID() {
+	suffix := uuid.NewString()[:8]
+	s.Equal(http.StatusCreated, s.createCompany(map[string]any{"business_name": "Search " + suffix}).StatusCode())
+	s.Equal(http.StatusCreated, s.createCompany(map[string]any{"business_name": "Other", "tax_id": "TAX-" + suffix}).StatusCode())
+	s.Equal(http.StatusCreated, s.createCompany(map[string]any{"business_name": "Unrelated"}).StatusCode())
+
+	resp, err := s.request().SetQueryParam("search", strings.ToUpper(suffix)).Get("/api/v1/companies")
+	s.Require().NoError(err)
+	var companies []company
+	s.data(resp, http.StatusOK, &companies)
+	s.Len(companies, 2)
+
+	resp, err = s.request().SetQueryParams(map[string]string{"search": suffix, "tax_id": "TAX-" + suffix}).Get("/api/v1/companies/count")
+	s.Require().NoError(err)
+	var count struct {
+		Count int64 `json:"count"`
+	}
+	s.data(resp, http.StatusOK, &count)
+	s.Equal(int64(1), count.Count)
+}
+
+// TestCompanies_OtherOrganization_ShouldNotBeVisible tests organization
+// scoping
+func (s *CompaniesTestSuite) TestCompanies_OtherOrganization_ShouldNotBeVisible() {
+	name := "Private " + uuid.NewString()[:8]
+	var created company
+	s.data(s.createCompany(map[string]any{"business_name": name}), http.StatusCreated, &created)
+
+	other := s.testSuite.Client.Client.R().SetAuthToken(s.otherToken)
+
+	resp, err := other.Get("/api/v1/companies/" + created.ID.String())
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	resp, err = other.SetQueryParam("search", name).Get("/api/v1/companies")
+	s.Require().NoError(err)
+	var companies []company
+	s.data(resp, http.StatusOK, &companies)
+	s.Empty(companies)
+
+	resp, err = s.testSuite.Client.Client.R().SetAuthToken(s.otherToken).Delete("/api/v1/companies/" + created.ID.String())
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: Companies cannot be created in organizations of others
+	resp, err = other.SetBody(map[string]any{"business_name": "Intruder", "organization_id": s.orgA}).Post("/api/v1/companies")
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCompanies_WithoutPermission_ShouldReturnForbidden tests authorization
+func (s *CompaniesTestSuite) TestCompanies_WithoutPermission_ShouldReturnForbidden() {
+	userID := s.testSuite.CreateUser("Reader")
+	s.testSuite.GrantPermissions(s.orgA, userID, "companies.read")
+
+	resp, err := s.testSuite.Client.Client.R().SetAuthToken(s.testSuite.AccessToken(userID)).
+		SetBody(map[string]any{"business_name": "Forbidden"}).
+		Post("/api/v1/companies")
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+func TestCompaniesTestSuite(t *testing.T) {
+	suite.Run(t, new(CompaniesTestSuite))
+}
@@ -0,0 +1,311 @@
+//go:build integration
+
+package management
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type contact struct {
+	ID           uuid.UUID `json:"id"`
+	CompanyID    uuid.UUID `json:"company_id"`
+	FirstName    string    `json:"first_name"`
+	Email        *string   `json:"email"`
+	ContactTypes []string  `json:"contact_types"`
+	PrimaryTypes []string  `json:"primary_types"`
+}
+
+// ContactsTestSuite covers the contacts of companies, their types and
+// their export
+type ContactsTestSuite struct {
+	suite.Suite
+	testSuite  *shared.TestSuite
+	adminToken string
+	otherToken string
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *ContactsTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+
+	// Given: The administrators of the contacts of two organizations
+	permissions := []string{"companies.create", "contacts.create", "contacts.read", "contacts.update", "contacts.delete"}
+
+	orgA := s.testSuite.CreateOrganization("Contacts A")
+	adminID := s.testSuite.CreateUser("Admin")
+	s.testSuite.GrantPermissions(orgA, adminID, permissions...)
+	s.adminToken = s.testSuite.AccessToken(adminID)
+
+	orgB := s.testSuite.CreateOrganization("Contacts B")
+	otherID := s.testSuite.CreateUser("Other")
+	s.testSuite.GrantPermissions(orgB, otherID, permissions...)
+	s.otherToken = s.testSuite.AccessToken(otherID)
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *ContactsTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *ContactsTestSuite) request() *resty.Request {
+	return s.testSuite.Client.Client.R().SetAuthToken(s.adminToken)
+}
+
+func (s *ContactsTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *ContactsTestSuite) createCompany(name string) uuid.UUID {
+	resp, err := s.request().SetBody(map[string]any{"business_name": name}).Post("/api/v1/companies")
+	s.Require().NoError(err)
+
+	var created struct {
+		ID uuid.UUID `json:"id"`
+	}
+	s.data(resp, http.StatusCreated, &created)
+	return created.ID
+}
+
+func (s *ContactsTestSuite) createContact(companyID uuid.UUID, body map[string]any) *resty.Response {
+	resp, err := s.request().SetBody(body).Post("/api/v1/companies/" + companyID.String() + "/contacts")
+	s.Require().NoError(err)
+	return resp
+}
+
+// TestContactLifeCycle_ShouldNormalizeAndSoftDelete tests create, get,
+// update and delete
+func (s *ContactsTestSuite) TestContactLifeCycle_ShouldNormalizeAndSoftDelete() {
+	companyID := s.createCompany("Contacts Life Cycle")
+
+	// When: We create a contact with a mixed case email and lower case types
+	var created contact
+	s.data(s.createContact(companyID, map[string]any{
+		"first_name":    "Ana",
+		"last_name":     "Pérez",
+		"email":         " Ana.Perez@Example.COM ",
+		"contact_types": []string{"billing", "technical"},
+	}), http.StatusCreated, &created)
+
+	// Then: They are stored normalized
+	s.Equal(companyID, created.CompanyID)
+	s.Require().NotNil(created.Email)
+	s.Equal("ana.perez@example.com", *created.Email)
+	s.Equal([]string{"BILLING", "TECHNICAL"}, created.ContactTypes)
+	s.Empty(created.PrimaryTypes)
+	path := "/api/v1/contacts/" + created.ID.String()
+
+	// When: We update it
+	resp, err := s.request().SetBody(map[string]any{"first_name": "Ana María", "contact_types": []string{"SALES"}}).Put(path)
+	s.Require().NoError(err)
+	var updated contact
+	s.data(resp, http.StatusOK, &updated)
+	s.Equal("Ana María", updated.FirstName)
+	s.Equal([]string{"SALES"}, updated.ContactTypes)
+
+	// When: We delete it
+	resp, err = s.request().Delete(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNoContent, resp.StatusCode())
+
+	// Then: It is not found anymore
+	resp, err = s.request().Get(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: Its email can be used again
+	resp = s.createContact(companyID, map[string]any{"first_name": "Ana", "email": "ana.perez@example.com"})
+	s.Equal(http.StatusCreated, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCreateContact_Invalid_ShouldReturnErrors tests the validation of
+// companies, types and emails
+func (s *ContactsTestSuite) TestCreateContact_Invalid_ShouldReturnErrors() {
+	companyID := s.createCompany("Contacts Validation")
+
+	resp := s.createContact(uuid.New(), map[string]any{"first_name": "Nobody"})
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	resp = s.createContact(companyID, map[string]any{"first_name": "Unknown", "contact_types": []string{"GALAXY"}})
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	resp = s.createContact(companyID, map[string]any{"first_name": "Primary", "contact_types": []string{"SALES"}, "primary_types": []string{"BILLING"}})
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// When: Two contacts of the company have the same email
+	s.Equal(http.StatusCreated, s.createContact(companyID, map[string]any{"first_name": "First", "email": "same@example.com"}).StatusCode())
+	resp = s.createContact(companyID, map[string]any{"first_name": "Second", "email": "SAME@example.com"})
+	s.Equal(http.StatusConflict, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	var other contact
+	s.data(s.createContact(companyID, map[string]any{"first_name": "Other", "email": "other@example.com"}), http.StatusCreated, &other)
+	r, err := s.request().SetBody(map[string]any{"email": "same@example.com"}).Put("/api/v1/contacts/" + other.ID.String())
+	s.Require().NoError(err)
+	s.Equal(http.StatusConflict, r.StatusCode(), "Unexpected status: %s", r.Body())
+
+	// Then: But contacts of other companies can
+	resp = s.createContact(s.createCompany("Contacts Validation Other"), map[string]any{"first_name": "Second", "email": "same@example.com"})
+	s.Equal(http.StatusCreated, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCreateContact_ConcurrentEmail_ShouldCreateOnlyOne tests that the
+// email of concurrent creates is unique too
+func (s *ContactsTestSuite) TestCreateContact_ConcurrentEmail_ShouldCreateOnlyOne() {
+	const attempts = 8
+	companyID := s.createCompany("Contacts Concurrent")
+
+	statuses := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := s.request().SetBody(map[string]any{"first_name": "Racer", "email": "racer@example.com"}).
+				Post("/api/v1/companies/" + companyID.String() + "/contacts")
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			statuses <- resp.StatusCode()
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	counts := map[int]int{}
+	for status := range statuses {
+		counts[status]++
+	}
+	s.Equal(map[int]int{http.StatusCreated: 1, http.StatusConflict: attempts - 1}, counts)
+}
+
+// TestPrimaryContact_ShouldBeUniquePerType tests that a new primary
+// contact replaces the previous one of the same type
+func (s *ContactsTestSuite) TestPrimaryContact_ShouldBeUniquePerType() {
+	companyID := s.createCompany("Contacts Primary")
+
+	var first contact
+	s.data(s.createContact(companyID, map[string]any{
+		"first_name":    "First",
+		"contact_types": []string{"BILLING", "TECHNICAL"},
+		"primary_types": []string{"BILLING", "TECHNICAL"},
+	}), http.StatusCreated, &first)
+	s.ElementsMatch([]string{"BILLING", "TECHNICAL"}, first.PrimaryTypes)
+
+	// When: Another contact becomes the billing primary contact
+	var second contact
+	s.data(s.createContact(companyID, map[string]any{
+		"first_name":    "Second",
+		"contact_types": []string{"BILLING"},
+		"primary_types": []string{"BILLING"},
+	}), http.StatusCreated, &second)
+
+	// Then: The first one is only the technical primary contact
+	resp, err := s.request().Get("/api/v1/contacts/" + first.ID.String())
+	s.Require().NoError(err)
+	s.data(resp, http.StatusOK, &first)
+	s.Equal([]string{"TECHNICAL"}, first.PrimaryTypes)
+
+	resp, err = s.request().SetQueryParam("primary_for", "billing").Get("/api/v1/companies/" + companyID.String() + "/contacts")
+	s.Require().NoError(err)
+	var primary []contact
+	s.data(resp, http.StatusOK, &primary)
+	s.Require().Len(primary, 1)
+	s.Equal(second.ID, primary[0].ID)
+
+	resp, err = s.request().SetQueryParam("contact_type", "BILLING").Get("/api/v1/companies/" + companyID.String() + "/contacts")
+	s.Require().NoError(err)
+	var billing []contact
+	s.data(resp, http.StatusOK, &billing)
+	s.Len(billing, 2)
+
+	// When: The second contact is not a billing contact anymore
+	resp, err = s.request().SetBody(map[string]any{"contact_types": []string{"SALES"}}).Put("/api/v1/contacts/" + second.ID.String())
+	s.Require().NoError(err)
+	s.data(resp, http.StatusOK, &second)
+
+	// Then: It is not the primary one either
+	s.Empty(second.PrimaryTypes)
+}
+
+// TestListContacts_ShouldSearchAndCount tests searching across companies
+// and counting
+func (s *ContactsTestSuite) TestListContacts_ShouldSearchAndCount() {
+	suffix := uuid.NewString()[:8]
+	companyID := s.createCompany("Contacts Search")
+	s.Equal(http.StatusCreated, s.createContact(companyID, map[string]any{"first_name": "Search " + suffix}).StatusCode())
+	s.Equal(http.StatusCreated, s.createContact(companyID, map[string]any{"first_name": "Mail", "email": suffix + "@example.com"}).StatusCode())
+	s.Equal(http.StatusCreated, s.createContact(companyID, map[string]any{"first_name": "Unrelated"}).StatusCode())
+
+	resp, err := s.request().SetQueryParam("search", strings.ToUpper(suffix)).Get("/api/v1/contacts")
+	s.Require().NoError(err)
+	var contacts []contact
+	s.data(resp, http.StatusOK, &contacts)
+	s.Len(contacts, 2)
+
+	resp, err = s.request().SetQueryParams(map[string]string{"company_id": companyID.String()}).Get("/api/v1/contacts/count")
+	s.Require().NoError(err)
+	var count struct {
+		Count int64 `json:"count"`
+	}
+	s.data(resp, http.StatusOK, &count)
+	s.Equal(int64(3), count.Count)
+
+	// Then: Other organizations do not see them
+	resp, err = s.testSuite.Client.Client.R().SetAuthToken(s.otherToken).SetQueryParam("search", suffix).Get("/api/v1/contacts")
+	s.Require().NoError(err)
+	var others []contact
+	s.data(resp, http.StatusOK, &others)
+	s.Empty(others)
+}
+
+// TestExportContacts_ShouldDownloadVCard tests the vCard export of the
+// contacts of a company
+func (s *ContactsTestSuite) TestExportContacts_ShouldDownloadVCard() {
+	companyID := s.createCompany("Contacts Export")
+	s.Equal(http.StatusCreated, s.createContact(companyID, map[string]any{
+		"first_name":    "Luis",
+		"last_name":     "Torres",
+		"email":         "luis@example.com",
+		"phone":         "+51 1 555 0000",
+		"contact_types": []string{"BILLING"},
+	}).StatusCode())
+
+	resp, err := s.request().Get("/api/v1/companies/" + companyID.String() + "/contacts/vcard")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	s.True(strings.HasPrefix(resp.Header().Get("Content-Type"), "text/vcard"))
+	s.Contains(resp.Header().Get("Content-Disposition"), "attachment")
+	body := string(resp.Body())
+	s.True(strings.HasPrefix(body, "BEGIN:VCARD\r\nVERSION:4.0\r\n"), body)
+	s.Contains(body, "FN:Luis Torres\r\n")
+	s.Contains(body, "EMAIL;TYPE=work:luis@example.com\r\n")
+	s.Contains(body, "CATEGORIES:BILLING\r\n")
+
+	resp, err = s.testSuite.Client.Client.R().SetAuthToken(s.otherToken).Get("/api/v1/companies/" + companyID.String() + "/contacts/vcard")
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+func TestContactsTestSuite(t *testing.T) {
+	suite.Run(t, new(ContactsTestSuite))
+}
@@ -0,0 +1,171 @@
+//go:build integration
+
+package upload
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type storedFile struct {
+	Key         string    `json:"key"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	URL         string    `json:"url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// FilesTestSuite covers the files of organizations with the local storage
+// driver
+type FilesTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *FilesTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *FilesTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *FilesTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *FilesTestSuite) upload(accessToken string, organizationID uuid.UUID, name string, content []byte) *resty.Response {
+	resp, err := s.testSuite.Client.Client.R().
+		SetAuthToken(accessToken).
+		SetHeader("X-Organization-ID", organizationID.String()).
+		SetFileReader("file", name, bytes.NewReader(content)).
+		Post("/api/v1/files")
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *FilesTestSuite) fileURL(accessToken string, organizationID uuid.UUID, key string) *resty.Response {
+	resp, err := s.testSuite.Client.Client.R().
+		SetAuthToken(accessToken).
+		SetHeader("X-Organization-ID", organizationID.String()).
+		SetQueryParam("key", key).
+		Get("/api/v1/files/url")
+	s.Require().NoError(err)
+	return resp
+}
+
+// download requests the path and query of the signed URL from the API
+// container, which tests reach at another address than the public one
+func (s *FilesTestSuite) download(link string) (int, []byte) {
+	u, err := url.Parse(link)
+	s.Require().NoError(err)
+
+	resp, err := http.Get(s.testSuite.Client.BaseURL + u.RequestURI())
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	return resp.StatusCode, body
+}
+
+// member returns the access token of a member of a new organization
+// granted the files actions
+func (s *FilesTestSuite) member(name string) (uuid.UUID, string) {
+	organizationID := s.testSuite.CreateOrganization(name)
+	userID := s.testSuite.CreateUser(name)
+	s.testSuite.GrantPermissions(organizationID, userID, "files.upload", "files.read")
+	return organizationID, s.testSuite.AccessToken(userID)
+}
+
+// TestUpload_ShouldStoreInOrganization tests uploading a document and
+// downloading it from a signed URL
+func (s *FilesTestSuite) TestUpload_ShouldStoreInOrganization() {
+	// Given: A member allowed to upload files
+	organizationID, accessToken := s.member("Files")
+	content := []byte("%PDF-1.4\n% contract\n")
+
+	// When: The member uploads a PDF
+	var file storedFile
+	s.data(s.upload(accessToken, organizationID, "contract.pdf", content), http.StatusCreated, &file)
+
+	// Then: It is stored in the organization with the sniffed type, and
+	// its signed URL downloads it
+	s.True(strings.HasPrefix(file.Key, "organizations/"+organizationID.String()+"/"), file.Key)
+	s.True(strings.HasSuffix(file.Key, ".pdf"), file.Key)
+	s.Equal("application/pdf", file.ContentType)
+	s.Equal(int64(len(content)), file.Size)
+	s.True(file.ExpiresAt.After(time.Now()))
+
+	status, body := s.download(file.URL)
+	s.Equal(http.StatusOK, status)
+	s.Equal(content, body)
+
+	// When: The member asks for a new URL of the key
+	var signed storedFile
+	s.data(s.fileURL(accessToken, organizationID, file.Key), http.StatusOK, &signed)
+
+	// Then: It downloads the file too
+	status, _ = s.download(signed.URL)
+	s.Equal(http.StatusOK, status)
+}
+
+// TestFileURL_OtherOrganization_ShouldReturnNotFound tests that files are
+// only reachable from their organization
+func (s *FilesTestSuite) TestFileURL_OtherOrganization_ShouldReturnNotFound() {
+	organizationID, accessToken := s.member("Owner")
+	var file storedFile
+	s.data(s.upload(accessToken, organizationID, "notes.txt", []byte("private notes")), http.StatusCreated, &file)
+
+	otherID, otherToken := s.member("Other")
+	resp := s.fileURL(otherToken, otherID, file.Key)
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestUpload_UnsupportedType_ShouldFail tests that HTML is rejected, as it
+// could run scripts
+func (s *FilesTestSuite) TestUpload_UnsupportedType_ShouldFail() {
+	organizationID, accessToken := s.member("Html")
+
+	resp := s.upload(accessToken, organizationID, "page.pdf", []byte("<!DOCTYPE html><html><body>hi</body></html>"))
+	s.Equal(http.StatusUnsupportedMediaType, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestUpload_WithoutPermission_ShouldReturnForbidden tests that uploads
+// require files.upload
+func (s *FilesTestSuite) TestUpload_WithoutPermission_ShouldReturnForbidden() {
+	organizationID := s.testSuite.CreateOrganization("Forbidden")
+	userID := s.testSuite.CreateUser("Reader")
+	s.testSuite.GrantPermissions(organizationID, userID, "files.read")
+
+	resp := s.upload(s.testSuite.AccessToken(userID), organizationID, "notes.txt", []byte("notes"))
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestFilesTestSuite runs the files test suite
+func TestFilesTestSuite(t *testing.T) {
+	suite.Run(t, new(FilesTestSuite))
+}
@@ -0,0 +1,188 @@
+//go:build integration
+
+package management
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type organization struct {
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	Code             *string   `json:"code"`
+	OrganizationType string    `json:"organization_type"`
+	Status           string    `json:"status"`
+	IsActive         bool      `json:"is_active"`
+}
+
+// OrganizationsTestSuite covers organization management and auditing
+type OrganizationsTestSuite struct {
+	suite.Suite
+	testSuite  *shared.TestSuite
+	rootID     uuid.UUID
+	adminToken string
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *OrganizationsTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+
+	// Given: An administrator of the root organization, who may act on
+	// every organization
+	s.rootID = s.testSuite.CreateRootOrganization("Root")
+	adminID := s.testSuite.CreateUser("Admin")
+	s.testSuite.GrantPermissions(s.rootID, adminID,
+		"organizations.create", "organizations.read", "organizations.update", "organizations.delete")
+	s.adminToken = s.testSuite.AccessToken(adminID)
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *OrganizationsTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *OrganizationsTestSuite) request() *resty.Request {
+	return s.testSuite.Client.Client.R().SetAuthToken(s.adminToken)
+}
+
+func (s *OrganizationsTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *OrganizationsTestSuite) createOrganization(body map[string]any) *resty.Response {
+	resp, err := s.request().SetBody(body).Post("/api/v1/organizations")
+	s.Require().NoError(err)
+	return resp
+}
+
+// TestOrganizationLifeCycle_ShouldBeAudited tests create, get, update and delete
+func (s *OrganizationsTestSuite) TestOrganizationLifeCycle_ShouldBeAudited() {
+	// When: We create an organization without type and status
+	code := "acme-" + uuid.NewString()[:8]
+	var created organization
+	s.data(s.createOrganization(map[string]any{"name": "Acme", "code": code}), http.StatusCreated, &created)
+
+	// Then: It is an active customer
+	s.Equal("CUSTOMER", created.OrganizationType)
+	s.Equal("ACTIVE", created.Status)
+	s.True(created.IsActive)
+	path := "/api/v1/organizations/" + created.ID.String()
+
+	// When: We update it
+	resp, err := s.request().SetBody(map[string]any{"name": "Acme Inc", "organization_type": "PARTNER"}).Put(path)
+	s.Require().NoError(err)
+	var updated organization
+	s.data(resp, http.StatusOK, &updated)
+	s.Equal("Acme Inc", updated.Name)
+	s.Equal("PARTNER", updated.OrganizationType)
+
+	// When: We delete it
+	resp, err = s.request().Delete(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNoContent, resp.StatusCode())
+
+	// Then: It is not found anymore, is kept soft deleted, and every change
+	// was audited
+	resp, err = s.request().Get(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.organizations WHERE id = $1 AND deleted_at IS NOT NULL AND NOT is_active`, created.ID))
+	for _, action := range []string{"organization.created", "organization.updated", "organization.deleted"} {
+		s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.audit_logs WHERE entity_id = $1 AND action = $2`, created.ID, action), action)
+	}
+}
+
+// TestCreateOrganization_DuplicateCode_ShouldReturnConflict tests code uniqueness
+func (s *OrganizationsTestSuite) TestCreateOrganization_DuplicateCode_ShouldReturnConflict() {
+	code := "dup-" + uuid.NewString()[:8]
+	s.Equal(http.StatusCreated, s.createOrganization(map[string]any{"name": "First", "code": code}).StatusCode())
+
+	resp := s.createOrganization(map[string]any{"name": "Second", "code": code})
+	s.Equal(http.StatusConflict, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestCreateOrganization_InvalidType_ShouldReturnValidationError tests validation
+func (s *OrganizationsTestSuite) TestCreateOrganization_InvalidType_ShouldReturnValidationError() {
+	resp := s.createOrganization(map[string]any{"name": "Invalid", "organization_type": "GALAXY"})
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestListOrganizations_ShouldFilterByName tests filtering and counting
+func (s *OrganizationsTestSuite) TestListOrganizations_ShouldFilterByName() {
+	name := "Filtered " + uuid.NewString()[:8]
+	s.Equal(http.StatusCreated, s.createOrganization(map[string]any{"name": name + " A"}).StatusCode())
+	s.Equal(http.StatusCreated, s.createOrganization(map[string]any{"name": name + " B", "organization_type": "SUPPLIER"}).StatusCode())
+
+	resp, err := s.request().SetQueryParam("name", name).Get("/api/v1/organizations")
+	s.Require().NoError(err)
+	var organizations []organization
+	s.data(resp, http.StatusOK, &organizations)
+	s.Len(organizations, 2)
+
+	resp, err = s.request().SetQueryParams(map[string]string{"name": name, "organization_type": "SUPPLIER"}).Get("/api/v1/organizations/count")
+	s.Require().NoError(err)
+	var count struct {
+		Count int `json:"count"`
+	}
+	s.data(resp, http.StatusOK, &count)
+	s.Equal(1, count.Count)
+}
+
+// TestRootOrganization_ShouldNotBeDeletedOrDeactivated tests the root organization protection
+func (s *OrganizationsTestSuite) TestRootOrganization_ShouldNotBeDeletedOrDeactivated() {
+	path := "/api/v1/organizations/" + s.rootID.String()
+
+	deactivate, err := s.request().SetBody(map[string]any{"is_active": false}).Put(path)
+	s.Require().NoError(err)
+	remove, err := s.request().Delete(path)
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusForbidden, deactivate.StatusCode())
+	s.Equal(http.StatusForbidden, remove.StatusCode())
+}
+
+// TestDeactivateOrganization_ShouldRevokeAccessOfMembers tests that inactive organizations grant no access
+func (s *OrganizationsTestSuite) TestDeactivateOrganization_ShouldRevokeAccessOfMembers() {
+	// Given: A member allowed to read the organization
+	var created organization
+	s.data(s.createOrganization(map[string]any{"name": "Deactivated"}), http.StatusCreated, &created)
+	memberID := s.testSuite.CreateUser("Member")
+	s.testSuite.GrantPermissions(created.ID, memberID, "organizations.read")
+	memberToken := s.testSuite.AccessToken(memberID)
+	get := func() int {
+		resp, err := s.testSuite.Client.Client.R().SetAuthToken(memberToken).Get("/api/v1/organizations/" + created.ID.String())
+		s.Require().NoError(err)
+		return resp.StatusCode()
+	}
+	s.Equal(http.StatusOK, get())
+
+	// When: The organization is deactivated
+	resp, err := s.request().SetBody(map[string]any{"is_active": false}).Put("/api/v1/organizations/" + created.ID.String())
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: The member cannot reach it anymore
+	s.NotEqual(http.StatusOK, get())
+}
+
+// TestOrganizationsTestSuite runs the organizations test suite
+func TestOrganizationsTestSuite(t *testing.T) {
+	suite.Run(t, new(OrganizationsTestSuite))
+}
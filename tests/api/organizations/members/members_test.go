@@ -0,0 +1,245 @@
+//go:build integration
+
+package members
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type member struct {
+	ID           uuid.UUID `json:"id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Relationship string    `json:"relationship"`
+	IsActive     bool      `json:"is_active"`
+	FirstName    string    `json:"first_name"`
+	Email        *string   `json:"email"`
+}
+
+type membership struct {
+	OrganizationID     uuid.UUID `json:"organization_id"`
+	IsRootOrganization bool      `json:"is_root_organization"`
+	Relationship       string    `json:"relationship"`
+}
+
+// MembersTestSuite covers membership management and the organization
+// switcher
+type MembersTestSuite struct {
+	suite.Suite
+	testSuite      *shared.TestSuite
+	organizationID uuid.UUID
+	adminToken     string
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *MembersTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+
+	// Given: An administrator allowed to manage the members of an
+	// organization
+	s.organizationID = s.testSuite.CreateOrganization("Members")
+	adminID := s.testSuite.CreateUser("Admin")
+	s.testSuite.GrantPermissions(s.organizationID, adminID, "organizations.read", "organizations.members")
+	s.adminToken = s.testSuite.AccessToken(adminID)
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *MembersTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *MembersTestSuite) request() *resty.Request {
+	return s.testSuite.Client.Client.R().SetAuthToken(s.adminToken)
+}
+
+func (s *MembersTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *MembersTestSuite) membersPath() string {
+	return "/api/v1/organizations/" + s.organizationID.String() + "/members"
+}
+
+func (s *MembersTestSuite) addMember(body map[string]any) *resty.Response {
+	resp, err := s.request().SetBody(body).Post(s.membersPath())
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *MembersTestSuite) myOrganizations(accessToken string) []membership {
+	resp, err := s.testSuite.Client.Client.R().SetAuthToken(accessToken).Get("/api/v1/me/organizations")
+	s.Require().NoError(err)
+
+	var memberships []membership
+	s.data(resp, http.StatusOK, &memberships)
+	return memberships
+}
+
+// TestMemberLifeCycle_ShouldBeAudited tests add, update, deactivate and remove
+func (s *MembersTestSuite) TestMemberLifeCycle_ShouldBeAudited() {
+	// Given: A user without memberships
+	userID := s.testSuite.CreateUser("Grace")
+	userToken := s.testSuite.AccessToken(userID)
+	s.Empty(s.myOrganizations(userToken))
+
+	// When: The user is added to the organization
+	var added member
+	s.data(s.addMember(map[string]any{"user_id": userID}), http.StatusCreated, &added)
+
+	// Then: The user is an employee and may switch to the organization
+	s.Equal("EMPLOYEE", added.Relationship)
+	s.Equal("Grace", added.FirstName)
+	s.True(added.IsActive)
+	s.Equal([]membership{{OrganizationID: s.organizationID, Relationship: "EMPLOYEE"}}, s.myOrganizations(userToken))
+	path := s.membersPath() + "/" + added.ID.String()
+
+	// When: The relationship is changed
+	resp, err := s.request().SetBody(map[string]any{"relationship": "ADMIN"}).Put(path)
+	s.Require().NoError(err)
+	var updated member
+	s.data(resp, http.StatusOK, &updated)
+	s.Equal("ADMIN", updated.Relationship)
+
+	// When: The membership is deactivated
+	resp, err = s.request().SetBody(map[string]any{"is_active": false}).Put(path)
+	s.Require().NoError(err)
+	s.data(resp, http.StatusOK, &updated)
+	s.False(updated.IsActive)
+
+	// Then: The organization is not offered to the user anymore
+	s.Empty(s.myOrganizations(userToken))
+
+	// When: The member is removed
+	resp, err = s.request().Delete(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNoContent, resp.StatusCode())
+
+	// Then: It is not found anymore, and every change was audited
+	resp, err = s.request().Delete(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.audit_logs WHERE entity_id = $1 AND action = 'member.added'`, added.ID))
+	s.Equal(2, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.audit_logs WHERE entity_id = $1 AND action = 'member.updated'`, added.ID))
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.audit_logs WHERE entity_id = $1 AND action = 'member.removed'`, added.ID))
+}
+
+// TestAddMember_Duplicate_ShouldReturnConflict tests membership uniqueness
+func (s *MembersTestSuite) TestAddMember_Duplicate_ShouldReturnConflict() {
+	userID := s.testSuite.CreateUser("Duplicated")
+	s.Equal(http.StatusCreated, s.addMember(map[string]any{"user_id": userID}).StatusCode())
+
+	resp := s.addMember(map[string]any{"user_id": userID, "relationship": "ADMIN"})
+	s.Equal(http.StatusConflict, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestAddMember_UnknownUser_ShouldReturnBadRequest tests the user reference
+func (s *MembersTestSuite) TestAddMember_UnknownUser_ShouldReturnBadRequest() {
+	resp := s.addMember(map[string]any{"user_id": uuid.New()})
+	s.Equal(http.StatusBadRequest, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestAddMember_OtherOrganization_ShouldBeRejected tests tenant isolation
+func (s *MembersTestSuite) TestAddMember_OtherOrganization_ShouldBeRejected() {
+	otherID := s.testSuite.CreateOrganization("Other")
+	userID := s.testSuite.CreateUser("Outsider")
+
+	resp, err := s.request().SetBody(map[string]any{"user_id": userID}).Post("/api/v1/organizations/" + otherID.String() + "/members")
+	s.Require().NoError(err)
+	s.Contains([]int{http.StatusForbidden, http.StatusNotFound}, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+	s.Equal(0, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.organization_users WHERE user_id = $1`, userID))
+}
+
+// TestInviteMember_ShouldAddUserAndSendEmail tests invitations by email
+func (s *MembersTestSuite) TestInviteMember_ShouldAddUserAndSendEmail() {
+	// Given: A registered user
+	email := fmt.Sprintf("invited-%s@example.com", uuid.NewString()[:8])
+	resp, err := s.testSuite.Client.Client.R().SetBody(map[string]any{
+		"email":      email,
+		"password":   "Sup3r-secret-password",
+		"first_name": "Ada",
+	}).Post("/api/v1/auth/register")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// When: The user is invited by email, in any case
+	resp, err = s.request().SetBody(map[string]any{"email": strings.ToUpper(email), "relationship": "CONTACT"}).
+		Post("/api/v1/organizations/" + s.organizationID.String() + "/invitations")
+	s.Require().NoError(err)
+
+	// Then: The user is a member and was notified
+	var invited member
+	s.data(resp, http.StatusCreated, &invited)
+	s.Equal("CONTACT", invited.Relationship)
+	s.Require().NotNil(invited.Email)
+	s.Equal(email, *invited.Email)
+
+	subject, text := s.testSuite.LastEmail(email)
+	s.Equal("You have been added to Members", subject)
+	s.Contains(text, "Hi Ada")
+}
+
+// TestInviteMember_UnknownEmail_ShouldReturnNotFound tests invitations of unregistered emails
+func (s *MembersTestSuite) TestInviteMember_UnknownEmail_ShouldReturnNotFound() {
+	resp, err := s.request().SetBody(map[string]any{"email": "nobody-" + uuid.NewString()[:8] + "@example.com"}).
+		Post("/api/v1/organizations/" + s.organizationID.String() + "/invitations")
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestListMembers_ShouldFilterByRelationship tests member filtering
+func (s *MembersTestSuite) TestListMembers_ShouldFilterByRelationship() {
+	ownerID := s.testSuite.CreateUser("Owner")
+	s.Equal(http.StatusCreated, s.addMember(map[string]any{"user_id": ownerID, "relationship": "OWNER"}).StatusCode())
+
+	resp, err := s.request().SetQueryParam("relationship", "OWNER").Get(s.membersPath())
+	s.Require().NoError(err)
+	var members []member
+	s.data(resp, http.StatusOK, &members)
+	s.Require().Len(members, 1)
+	s.Equal(ownerID, members[0].UserID)
+}
+
+// TestMyOrganizations_ShouldListRootFirst tests the organization switcher
+func (s *MembersTestSuite) TestMyOrganizations_ShouldListRootFirst() {
+	// Given: A member of an organization, of the root organization and of
+	// a deleted organization
+	userID := s.testSuite.CreateUser("Switcher")
+	rootID := s.testSuite.CreateRootOrganization("Root")
+	deletedID := s.testSuite.CreateOrganization("Deleted")
+	s.testSuite.AddMember(s.organizationID, userID)
+	s.testSuite.AddMember(rootID, userID)
+	s.testSuite.AddMember(deletedID, userID)
+	s.testSuite.Exec(`UPDATE auth.organizations SET deleted_at = now(), is_active = false WHERE id = $1`, deletedID)
+
+	// When: The user lists their organizations
+	memberships := s.myOrganizations(s.testSuite.AccessToken(userID))
+
+	// Then: The root organization comes first and the deleted one is left out
+	s.Require().Len(memberships, 2)
+	s.Equal(rootID, memberships[0].OrganizationID)
+	s.True(memberships[0].IsRootOrganization)
+	s.Equal(s.organizationID, memberships[1].OrganizationID)
+}
+
+// TestMembersTestSuite runs the members test suite
+func TestMembersTestSuite(t *testing.T) {
+	suite.Run(t, new(MembersTestSuite))
+}
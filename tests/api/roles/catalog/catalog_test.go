@@ -0,0 +1,72 @@
+//go:build integration
+
+package catalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// CatalogTestSuite checks that the permission catalog synced at startup
+// holds the permission of every documented route
+type CatalogTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *CatalogTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *CatalogTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *CatalogTestSuite) TestCatalog_ShouldHoldEveryRoutePermission() {
+	// Given: The documented routes
+	resp, err := s.testSuite.Client.Get("/docs/openapi.json")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode())
+
+	var spec struct {
+		Paths map[string]map[string]struct {
+			Permission string `json:"x-permission"`
+		} `json:"paths"`
+	}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &spec))
+
+	// Then: Every permission is a module action of the catalog
+	checked := 0
+	for path, operations := range spec.Paths {
+		for method, operation := range operations {
+			if operation.Permission == "" {
+				continue
+			}
+			module, action, _ := strings.Cut(operation.Permission, ".")
+			count := s.testSuite.QueryInt(`
+				SELECT COUNT(*)
+				FROM auth.module_actions ma
+				JOIN auth.modules m ON m.id = ma.module_id
+				WHERE m.code = $1 AND ma.code = $2`, module, action)
+			s.Equal(1, count, "%s %s requires %s, which is not in the catalog", strings.ToUpper(method), path, operation.Permission)
+			checked++
+		}
+	}
+	s.NotZero(checked, "Expected routes with permissions")
+}
+
+func TestCatalogTestSuite(t *testing.T) {
+	suite.Run(t, new(CatalogTestSuite))
+}
@@ -0,0 +1,249 @@
+//go:build integration
+
+package management
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type role struct {
+	ID           uuid.UUID `json:"id"`
+	Code         string    `json:"code"`
+	IsSystemRole bool      `json:"is_system_role"`
+}
+
+type moduleAction struct {
+	Code string `json:"code"`
+}
+
+// RolesTestSuite covers role management, assignments and auditing
+type RolesTestSuite struct {
+	suite.Suite
+	testSuite      *shared.TestSuite
+	organizationID uuid.UUID
+	adminToken     string
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *RolesTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+
+	// Given: A root administrator allowed to manage roles and read users
+	s.organizationID = s.testSuite.CreateRootOrganization("Roles")
+	adminID := s.testSuite.CreateUser("Admin")
+	s.testSuite.GrantPermissions(s.organizationID, adminID,
+		"roles.create", "roles.read", "roles.update", "roles.delete", "roles.assign", "roles.grant", "users.read")
+	s.adminToken = s.testSuite.AccessToken(adminID)
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *RolesTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *RolesTestSuite) request() *resty.Request {
+	return s.testSuite.Client.Client.R().SetAuthToken(s.adminToken)
+}
+
+func (s *RolesTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *RolesTestSuite) createRole(code string) role {
+	resp, err := s.request().SetBody(map[string]any{
+		"name":            "Role " + code,
+		"code":            code,
+		"organization_id": s.organizationID,
+	}).Post("/api/v1/roles")
+	s.Require().NoError(err)
+
+	var created role
+	s.data(resp, http.StatusCreated, &created)
+	return created
+}
+
+func (s *RolesTestSuite) auditCount(entityID uuid.UUID, action string) int {
+	return s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.audit_logs WHERE entity_id = $1 AND action = $2`, entityID, action)
+}
+
+// TestGrantAndAssign_ShouldAuthorizeUser tests the full role life cycle
+func (s *RolesTestSuite) TestGrantAndAssign_ShouldAuthorizeUser() {
+	// Given: A role allowed to read users and a user without roles
+	created := s.createRole("readers-" + uuid.NewString()[:8])
+	resp, err := s.request().SetBody(map[string]any{"actions": []string{"users.read"}}).Post("/api/v1/roles/" + created.ID.String() + "/permissions")
+	s.Require().NoError(err)
+	var granted []moduleAction
+	s.data(resp, http.StatusOK, &granted)
+	s.Equal([]moduleAction{{Code: "users.read"}}, granted)
+
+	userID := s.testSuite.CreateUser("Reader")
+	userToken := s.testSuite.AccessToken(userID)
+	listUsers := func() int {
+		resp, err := s.testSuite.Client.Client.R().SetAuthToken(userToken).Get("/api/v1/users")
+		s.Require().NoError(err)
+		return resp.StatusCode()
+	}
+	s.Equal(http.StatusForbidden, listUsers())
+
+	// When: The role is assigned to the user
+	resp, err = s.request().SetBody(map[string]any{"role_id": created.ID}).Post("/api/v1/users/" + userID.String() + "/roles")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: The user can list users and has the permission
+	s.Equal(http.StatusOK, listUsers())
+
+	resp, err = s.request().Get("/api/v1/users/" + userID.String() + "/permissions")
+	s.Require().NoError(err)
+	var permissions struct {
+		Roles       []string          `json:"roles"`
+		Permissions map[string]string `json:"permissions"`
+	}
+	s.data(resp, http.StatusOK, &permissions)
+	s.Equal([]string{created.Code}, permissions.Roles)
+	s.Equal("ALL", permissions.Permissions["users.read"])
+
+	// When: The permission is revoked from the role
+	resp, err = s.request().SetBody(map[string]any{"actions": []string{"users.read"}}).Delete("/api/v1/roles/" + created.ID.String() + "/permissions")
+	s.Require().NoError(err)
+	var remaining []moduleAction
+	s.data(resp, http.StatusOK, &remaining)
+	s.Empty(remaining)
+
+	// Then: The user is forbidden again, and every change was audited
+	s.Equal(http.StatusForbidden, listUsers())
+	s.Equal(1, s.auditCount(created.ID, "role.created"))
+	s.Equal(1, s.auditCount(created.ID, "role.permissions_granted"))
+	s.Equal(1, s.auditCount(created.ID, "role.permissions_revoked"))
+	s.Equal(1, s.auditCount(userID, "user.role_assigned"))
+}
+
+// TestRevokeRole_ShouldRemoveAssignment tests role revocation
+func (s *RolesTestSuite) TestRevokeRole_ShouldRemoveAssignment() {
+	created := s.createRole("revoked-" + uuid.NewString()[:8])
+	userID := s.testSuite.CreateUser("Revoked")
+
+	resp, err := s.request().SetBody(map[string]any{"role_id": created.ID}).Post("/api/v1/users/" + userID.String() + "/roles")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode())
+
+	path := "/api/v1/users/" + userID.String() + "/roles/" + created.ID.String()
+	resp, err = s.request().Delete(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNoContent, resp.StatusCode())
+
+	resp, err = s.request().Get("/api/v1/users/" + userID.String() + "/roles")
+	s.Require().NoError(err)
+	var userRoles []map[string]any
+	s.data(resp, http.StatusOK, &userRoles)
+	s.Empty(userRoles)
+
+	// Revoking a role the user does not have is not found
+	resp, err = s.request().Delete(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+	s.Equal(1, s.auditCount(userID, "user.role_revoked"))
+}
+
+// TestSystemRole_ShouldNotBeModified tests the system role protection
+func (s *RolesTestSuite) TestSystemRole_ShouldNotBeModified() {
+	// Given: A system role
+	created := s.createRole("system-" + uuid.NewString()[:8])
+	s.testSuite.Exec(`UPDATE auth.roles SET is_system_role = true WHERE id = $1`, created.ID)
+	path := "/api/v1/roles/" + created.ID.String()
+
+	// When: We update, grant permissions to and delete it
+	update, err := s.request().SetBody(map[string]any{"name": "Renamed"}).Put(path)
+	s.Require().NoError(err)
+	grant, err := s.request().SetBody(map[string]any{"actions": []string{"users.read"}}).Post(path + "/permissions")
+	s.Require().NoError(err)
+	remove, err := s.request().Delete(path)
+	s.Require().NoError(err)
+
+	// Then: Every change is forbidden
+	s.Equal(http.StatusForbidden, update.StatusCode())
+	s.Equal(http.StatusForbidden, grant.StatusCode())
+	s.Equal(http.StatusForbidden, remove.StatusCode())
+}
+
+// TestGrantPermissions_UnknownAction_ShouldReturnBadRequest tests action validation
+func (s *RolesTestSuite) TestGrantPermissions_UnknownAction_ShouldReturnBadRequest() {
+	created := s.createRole("unknown-" + uuid.NewString()[:8])
+
+	resp, err := s.request().SetBody(map[string]any{"actions": []string{"users.read", "users.fly"}}).Post("/api/v1/roles/" + created.ID.String() + "/permissions")
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode())
+
+	resp, err = s.request().Get("/api/v1/roles/" + created.ID.String() + "/permissions")
+	s.Require().NoError(err)
+	var granted []moduleAction
+	s.data(resp, http.StatusOK, &granted)
+	s.Empty(granted, "No action should be granted")
+}
+
+// TestGrantPermissions_NotGrantable_ShouldReturnForbidden tests that
+// roles never hold more than the callers managing them
+func (s *RolesTestSuite) TestGrantPermissions_NotGrantable_ShouldReturnForbidden() {
+	// Given: The administrator of an organization that may merge users
+	organizationID := s.testSuite.CreateOrganization("Roles Tenant")
+	adminID := s.testSuite.CreateUser("Tenant Admin")
+	s.testSuite.GrantPermissions(organizationID, adminID, "roles.create", "roles.read", "roles.grant", "users.merge")
+	request := func() *resty.Request {
+		return s.testSuite.Client.Client.R().SetAuthToken(s.testSuite.AccessToken(adminID))
+	}
+
+	resp, err := request().SetBody(map[string]any{"name": "Escalation", "code": "escalation-" + uuid.NewString()[:8]}).Post("/api/v1/roles")
+	s.Require().NoError(err)
+	var created role
+	s.data(resp, http.StatusCreated, &created)
+	path := "/api/v1/roles/" + created.ID.String() + "/permissions"
+
+	// When: They grant an action they do not hold
+	resp, err = request().SetBody(map[string]any{"actions": []string{"organizations.create"}}).Post(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// When: They grant an action they hold, but on every organization
+	resp, err = request().SetBody(map[string]any{"actions": []string{"users.merge"}}).Post(path)
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: Nothing is granted
+	s.Equal(0, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.permissions WHERE role_id = $1`, created.ID))
+}
+
+// TestCreateRole_DuplicateCode_ShouldReturnConflict tests code uniqueness per organization
+func (s *RolesTestSuite) TestCreateRole_DuplicateCode_ShouldReturnConflict() {
+	code := "duplicate-" + uuid.NewString()[:8]
+	s.createRole(code)
+
+	resp, err := s.request().SetBody(map[string]any{
+		"name":            "Duplicate",
+		"code":            code,
+		"organization_id": s.organizationID,
+	}).Post("/api/v1/roles")
+	s.Require().NoError(err)
+	s.Equal(http.StatusConflict, resp.StatusCode())
+}
+
+// TestRolesTestSuite runs the roles test suite
+func TestRolesTestSuite(t *testing.T) {
+	suite.Run(t, new(RolesTestSuite))
+}
@@ -0,0 +1,218 @@
+//go:build integration
+
+package tenancy
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type role struct {
+	ID             uuid.UUID `json:"id"`
+	Code           string    `json:"code"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+}
+
+// TenancyTestSuite covers the scoping of requests to the organizations of
+// the caller and the X-Organization-ID header
+type TenancyTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+	orgA      uuid.UUID
+	orgB      uuid.UUID
+	foreign   uuid.UUID
+	// roles maps the organizations to the code of a role created in them
+	roles map[uuid.UUID]string
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *TenancyTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+
+	// Given: Two organizations of the caller, a foreign one, and a role in each
+	s.orgA = s.testSuite.CreateOrganization("Tenant A")
+	s.orgB = s.testSuite.CreateOrganization("Tenant B")
+	s.foreign = s.testSuite.CreateOrganization("Foreign")
+	s.roles = map[uuid.UUID]string{}
+	for _, organizationID := range []uuid.UUID{s.orgA, s.orgB, s.foreign} {
+		code := "tenant-" + uuid.NewString()[:8]
+		s.testSuite.Exec(`INSERT INTO auth.roles (name, code, organization_id) VALUES ($1, $1, $2)`, code, organizationID)
+		s.roles[organizationID] = code
+	}
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *TenancyTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+// member returns the token of a user of orgA and orgB allowed to manage
+// roles in both
+func (s *TenancyTestSuite) member() string {
+	userID := s.testSuite.CreateUser("Member")
+	s.testSuite.GrantPermissions(s.orgA, userID, "roles.read", "roles.create")
+	s.testSuite.GrantPermissions(s.orgB, userID, "roles.read", "roles.create")
+	return s.testSuite.AccessToken(userID)
+}
+
+func (s *TenancyTestSuite) request(token, organization string) *resty.Request {
+	req := s.testSuite.Client.Client.R().SetAuthToken(token)
+	if organization != "" {
+		req.SetHeader("X-Organization-ID", organization)
+	}
+	return req
+}
+
+func (s *TenancyTestSuite) listRoles(token, organization string) []string {
+	resp, err := s.request(token, organization).SetQueryParam("page_size", "100").Get("/api/v1/roles")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	var body struct {
+		Data []role `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+
+	codes := make([]string, 0, len(body.Data))
+	for _, r := range body.Data {
+		codes = append(codes, r.Code)
+	}
+	return codes
+}
+
+// TestWithoutHeader_ShouldScopeToMemberships tests the default tenant
+func (s *TenancyTestSuite) TestWithoutHeader_ShouldScopeToMemberships() {
+	codes := s.listRoles(s.member(), "")
+
+	s.Contains(codes, s.roles[s.orgA])
+	s.Contains(codes, s.roles[s.orgB])
+	s.NotContains(codes, s.roles[s.foreign])
+}
+
+// TestHeader_ShouldScopeToSelectedOrganization tests tenant selection
+func (s *TenancyTestSuite) TestHeader_ShouldScopeToSelectedOrganization() {
+	token := s.member()
+
+	codes := s.listRoles(token, s.orgA.String())
+	s.Contains(codes, s.roles[s.orgA])
+	s.NotContains(codes, s.roles[s.orgB])
+
+	// When: A role is created without organization
+	resp, err := s.request(token, s.orgB.String()).
+		SetBody(map[string]any{"name": "Scoped", "code": "scoped-" + uuid.NewString()[:8]}).
+		Post("/api/v1/roles")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: It belongs to the selected organization
+	var body struct {
+		Data role `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+	s.Equal(s.orgB, body.Data.OrganizationID)
+
+	// And: It is not visible from the other organization
+	resp, err = s.request(token, s.orgA.String()).Get("/api/v1/roles/" + body.Data.ID.String())
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+}
+
+// TestHeader_ForeignOrganization_ShouldReturnForbidden tests membership validation
+func (s *TenancyTestSuite) TestHeader_ForeignOrganization_ShouldReturnForbidden() {
+	token := s.member()
+
+	resp, err := s.request(token, s.foreign.String()).Get("/api/v1/roles")
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode())
+
+	resp, err = s.request(token, "*").Get("/api/v1/roles")
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode())
+
+	resp, err = s.request(token, "not-an-organization").Get("/api/v1/roles")
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode())
+
+	// Roles cannot be created in organizations outside the tenant either
+	resp, err = s.request(token, s.orgA.String()).
+		SetBody(map[string]any{"name": "Foreign", "code": "foreign-" + uuid.NewString()[:8], "organization_id": s.foreign}).
+		Post("/api/v1/roles")
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode())
+}
+
+// TestWithoutHeader_ShouldLimitActionsToGrantingOrganizations tests that
+// the roles of one organization grant nothing in the others
+func (s *TenancyTestSuite) TestWithoutHeader_ShouldLimitActionsToGrantingOrganizations() {
+	// Given: An administrator of orgA who only reads roles in orgB
+	userID := s.testSuite.CreateUser("Admin A")
+	s.testSuite.GrantPermissions(s.orgA, userID, "roles.read", "roles.create", "roles.delete")
+	s.testSuite.GrantPermissions(s.orgB, userID, "roles.read")
+	token := s.testSuite.AccessToken(userID)
+
+	victimID := uuid.New()
+	s.testSuite.Exec(`INSERT INTO auth.roles (id, name, code, organization_id) VALUES ($1, $2, $2, $3)`, victimID, "victim-"+victimID.String()[:8], s.orgB)
+
+	// Then: Roles are read in both organizations
+	codes := s.listRoles(token, "")
+	s.Contains(codes, s.roles[s.orgA])
+	s.Contains(codes, s.roles[s.orgB])
+
+	// But: orgB roles are neither created nor deleted
+	resp, err := s.request(token, "").
+		SetBody(map[string]any{"name": "Intruder", "code": "intruder-" + uuid.NewString()[:8], "organization_id": s.orgB}).
+		Post("/api/v1/roles")
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	resp, err = s.request(token, "").Delete("/api/v1/roles/" + victimID.String())
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.roles WHERE id = $1`, victimID))
+
+	// And: Roles created without organization belong to orgA
+	resp, err = s.request(token, "").
+		SetBody(map[string]any{"name": "Own", "code": "own-" + uuid.NewString()[:8]}).
+		Post("/api/v1/roles")
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+	var body struct {
+		Data role `json:"data"`
+	}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+	s.Equal(s.orgA, body.Data.OrganizationID)
+}
+
+// TestRootOrganization_ShouldCrossTenants tests root organization members
+func (s *TenancyTestSuite) TestRootOrganization_ShouldCrossTenants() {
+	// Given: A member of the root organization
+	userID := s.testSuite.CreateUser("Root")
+	s.testSuite.GrantPermissions(s.testSuite.CreateRootOrganization("Root"), userID, "roles.read")
+	token := s.testSuite.AccessToken(userID)
+
+	// Then: Only their organization is visible by default
+	s.NotContains(s.listRoles(token, ""), s.roles[s.foreign])
+
+	// And: Other organizations are visible when selected explicitly
+	codes := s.listRoles(token, s.foreign.String())
+	s.Equal([]string{s.roles[s.foreign]}, codes)
+
+	codes = s.listRoles(token, "*")
+	s.Contains(codes, s.roles[s.orgA])
+	s.Contains(codes, s.roles[s.foreign])
+}
+
+func TestTenancyTestSuite(t *testing.T) {
+	suite.Run(t, new(TenancyTestSuite))
+}
@@ -0,0 +1,162 @@
+//go:build integration
+
+package rls
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth/tenant"
+	"api.system.soluciones-cloud.com/internal/shared/localconfig"
+	"api.system.soluciones-cloud.com/internal/shared/repository/postgres"
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// RLSTestSuite covers the row-level security policies of the business
+// tables: queries run in the tenant of their context even when they do not
+// filter by organization
+type RLSTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+	db        *postgres.Adapter
+	orgA      uuid.UUID
+	orgB      uuid.UUID
+	company   uuid.UUID
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *RLSTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+
+	port, err := strconv.Atoi(s.testSuite.DB.Port)
+	s.Require().NoError(err)
+	s.db, err = postgres.New(localconfig.DatabaseConfig{
+		Host:     s.testSuite.DB.Host,
+		Port:     port,
+		User:     s.testSuite.DB.Username,
+		Password: s.testSuite.DB.Password,
+		DBName:   s.testSuite.DB.Database,
+		SSLMode:  "disable",
+	})
+	s.Require().NoError(err, "Failed to connect to database")
+
+	// Given: A contact in each of two organizations
+	s.orgA = s.testSuite.CreateOrganization("RLS A")
+	s.orgB = s.testSuite.CreateOrganization("RLS B")
+	s.company = uuid.New()
+	s.testSuite.Exec(`INSERT INTO relationships.companies (id, business_name) VALUES ($1, 'RLS Company')`, s.company)
+	for _, organizationID := range []uuid.UUID{s.orgA, s.orgB} {
+		s.testSuite.Exec(`INSERT INTO relationships.contacts (company_id, first_name, organization_id) VALUES ($1, 'Contact', $2)`,
+			s.company, organizationID)
+	}
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *RLSTestSuite) TearDownSuite() {
+	if s.db != nil {
+		s.db.Close()
+	}
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *RLSTestSuite) in(organizations ...uuid.UUID) context.Context {
+	return tenant.WithTenant(context.Background(), tenant.Tenant{Organizations: organizations})
+}
+
+// organizations runs a query without organization filter, like a
+// repository that forgot it, and returns the organizations of the rows
+func (s *RLSTestSuite) organizations(ctx context.Context) map[uuid.UUID]int {
+	rows, err := s.db.Query(ctx, `SELECT organization_id FROM relationships.contacts WHERE company_id = $1`, s.company)
+	s.Require().NoError(err)
+	defer rows.Close()
+
+	found := map[uuid.UUID]int{}
+	for rows.Next() {
+		var organizationID uuid.UUID
+		s.Require().NoError(rows.Scan(&organizationID))
+		found[organizationID]++
+	}
+	s.Require().NoError(rows.Err())
+	return found
+}
+
+func (s *RLSTestSuite) TestUnfilteredQueriesOnlyReadTheTenant() {
+	// When: Querying every contact in the tenant of orgA
+	found := s.organizations(s.in(s.orgA))
+
+	// Then: Only the contacts of orgA come back
+	s.Equal(map[uuid.UUID]int{s.orgA: 1}, found)
+}
+
+func (s *RLSTestSuite) TestSeveralOrganizations() {
+	found := s.organizations(s.in(s.orgA, s.orgB))
+	s.Equal(map[uuid.UUID]int{s.orgA: 1, s.orgB: 1}, found)
+}
+
+func (s *RLSTestSuite) TestTenantWithoutOrganizationsReadsNothing() {
+	found := s.organizations(s.in())
+	s.Empty(found)
+}
+
+func (s *RLSTestSuite) TestAllOrganizations() {
+	ctx := tenant.WithTenant(context.Background(), tenant.Tenant{All: true})
+	found := s.organizations(ctx)
+	s.Equal(map[uuid.UUID]int{s.orgA: 1, s.orgB: 1}, found)
+}
+
+func (s *RLSTestSuite) TestWithoutTenantIsNotRestricted() {
+	found := s.organizations(context.Background())
+	s.Equal(map[uuid.UUID]int{s.orgA: 1, s.orgB: 1}, found)
+}
+
+func (s *RLSTestSuite) TestConnectionsDoNotLeakTheTenant() {
+	// Given: A connection that served orgB
+	s.organizations(s.in(s.orgB))
+
+	// When: The pool serves orgA right after
+	found := s.organizations(s.in(s.orgA))
+
+	// Then: It runs in orgA
+	s.Equal(map[uuid.UUID]int{s.orgA: 1}, found)
+}
+
+func (s *RLSTestSuite) TestTransactionsRunInTheTenant() {
+	tx, err := s.db.Begin(s.in(s.orgB))
+	s.Require().NoError(err)
+	defer tx.Rollback(context.Background())
+
+	var count int
+	err = tx.QueryRow(context.Background(), `SELECT COUNT(*) FROM relationships.contacts WHERE company_id = $1`, s.company).Scan(&count)
+	s.Require().NoError(err)
+	s.Equal(1, count)
+}
+
+func (s *RLSTestSuite) TestWritesOutsideTheTenantAreRejected() {
+	// When: Inserting a contact of orgB in the tenant of orgA
+	_, err := s.db.Exec(s.in(s.orgA),
+		`INSERT INTO relationships.contacts (company_id, first_name, organization_id) VALUES ($1, 'Intruder', $2)`,
+		s.company, s.orgB)
+
+	// Then: The policy rejects it
+	s.Require().Error(err)
+	s.Contains(err.Error(), "row-level security")
+}
+
+func (s *RLSTestSuite) TestUpdatesOutsideTheTenantMatchNothing() {
+	tag, err := s.db.Exec(s.in(s.orgA),
+		`UPDATE relationships.contacts SET notes = 'changed' WHERE organization_id = $1`, s.orgB)
+	s.Require().NoError(err)
+	s.Zero(tag.RowsAffected())
+}
+
+func TestRLSTestSuite(t *testing.T) {
+	suite.Run(t, new(RLSTestSuite))
+}
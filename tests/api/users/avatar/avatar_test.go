@@ -0,0 +1,186 @@
+//go:build integration
+
+package avatar
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type user struct {
+	ID      uuid.UUID `json:"id"`
+	Picture *string   `json:"picture"`
+}
+
+// AvatarTestSuite covers avatar uploads with the local storage driver
+type AvatarTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *AvatarTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *AvatarTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *AvatarTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *AvatarTestSuite) upload(accessToken, name string, content []byte) *resty.Response {
+	resp, err := s.testSuite.Client.Client.R().
+		SetAuthToken(accessToken).
+		SetFileReader("file", name, bytes.NewReader(content)).
+		Put("/api/v1/me/avatar")
+	s.Require().NoError(err)
+	return resp
+}
+
+// get requests the path and query of link from the API container, without
+// following redirects. Links carry the public URL of the API, which tests
+// do not reach.
+func (s *AvatarTestSuite) get(link string) *http.Response {
+	u, err := url.Parse(link)
+	s.Require().NoError(err)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(s.testSuite.Client.BaseURL + u.RequestURI())
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func pngImage(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// TestUploadAvatar_ShouldStoreThumbnail tests that the picture links to a
+// square thumbnail, downloaded through a signed URL
+func (s *AvatarTestSuite) TestUploadAvatar_ShouldStoreThumbnail() {
+	// Given: A user
+	userID := s.testSuite.CreateUser("Ada")
+	accessToken := s.testSuite.AccessToken(userID)
+
+	// When: The user uploads a landscape image
+	var me user
+	s.data(s.upload(accessToken, "photo.png", pngImage(600, 400)), http.StatusOK, &me)
+
+	// Then: The picture links to the avatar route, which redirects to a
+	// 256x256 thumbnail
+	s.Require().NotNil(me.Picture)
+	s.True(strings.HasPrefix(*me.Picture, "http://localhost:8080/api/v1/avatars/"+userID.String()+"/"), *me.Picture)
+
+	redirect := s.get(*me.Picture)
+	s.Require().Equal(http.StatusFound, redirect.StatusCode)
+	location := redirect.Header.Get("Location")
+	s.Contains(location, "signature=")
+
+	download := s.get(location)
+	s.Require().Equal(http.StatusOK, download.StatusCode)
+	thumbnail, err := png.Decode(download.Body)
+	s.Require().NoError(err)
+	s.Equal(image.Rect(0, 0, 256, 256), thumbnail.Bounds())
+
+	// When: The user uploads another avatar
+	var replaced user
+	s.data(s.upload(accessToken, "other.png", pngImage(300, 300)), http.StatusOK, &replaced)
+
+	// Then: The previous avatar is deleted
+	s.NotEqual(*me.Picture, *replaced.Picture)
+	s.Equal(http.StatusNotFound, s.get(location).StatusCode)
+}
+
+// TestUploadAvatar_NotAnImage_ShouldFail tests that the content, not the
+// file name, decides the type of the upload
+func (s *AvatarTestSuite) TestUploadAvatar_NotAnImage_ShouldFail() {
+	userID := s.testSuite.CreateUser("Ada")
+
+	resp := s.upload(s.testSuite.AccessToken(userID), "photo.png", []byte("<html><script>alert(1)</script></html>"))
+	s.Equal(http.StatusUnsupportedMediaType, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestUploadAvatar_WithoutFile_ShouldFail tests the required file field
+func (s *AvatarTestSuite) TestUploadAvatar_WithoutFile_ShouldFail() {
+	userID := s.testSuite.CreateUser("Ada")
+
+	resp, err := s.testSuite.Client.Client.R().
+		SetAuthToken(s.testSuite.AccessToken(userID)).
+		SetMultipartFormData(map[string]string{"name": "photo"}).
+		Put("/api/v1/me/avatar")
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestDeleteAvatar_ShouldClearPicture tests removing the avatar
+func (s *AvatarTestSuite) TestDeleteAvatar_ShouldClearPicture() {
+	// Given: A user with an avatar
+	userID := s.testSuite.CreateUser("Ada")
+	accessToken := s.testSuite.AccessToken(userID)
+	var me user
+	s.data(s.upload(accessToken, "photo.png", pngImage(64, 64)), http.StatusOK, &me)
+	location := s.get(*me.Picture).Header.Get("Location")
+
+	// When: The user deletes their avatar
+	resp, err := s.testSuite.Client.Client.R().SetAuthToken(accessToken).Delete("/api/v1/me/avatar")
+	s.Require().NoError(err)
+
+	// Then: The picture is cleared and the file deleted
+	s.data(resp, http.StatusOK, &me)
+	s.Nil(me.Picture)
+	s.Equal(http.StatusNotFound, s.get(location).StatusCode)
+}
+
+// TestAvatarURL_Tampered_ShouldBeForbidden tests that signed URLs cannot be
+// pointed at other files
+func (s *AvatarTestSuite) TestAvatarURL_Tampered_ShouldBeForbidden() {
+	userID := s.testSuite.CreateUser("Ada")
+	var me user
+	s.data(s.upload(s.testSuite.AccessToken(userID), "photo.png", pngImage(64, 64)), http.StatusOK, &me)
+	location := s.get(*me.Picture).Header.Get("Location")
+
+	tampered := strings.Replace(location, userID.String(), uuid.NewString(), 1)
+	s.Equal(http.StatusForbidden, s.get(tampered).StatusCode)
+}
+
+// TestAvatarTestSuite runs the avatar test suite
+func TestAvatarTestSuite(t *testing.T) {
+	suite.Run(t, new(AvatarTestSuite))
+}
@@ -0,0 +1,302 @@
+//go:build integration
+
+package lifecycle
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const password = "correct horse battery"
+
+type user struct {
+	ID       uuid.UUID `json:"id"`
+	IsActive bool      `json:"is_active"`
+}
+
+type statusChange struct {
+	UserIDs     []uuid.UUID `json:"user_ids"`
+	Sessions    int         `json:"sessions"`
+	APIKeys     int64       `json:"api_keys"`
+	Memberships int64       `json:"memberships"`
+}
+
+type mergeResult struct {
+	User        user  `json:"user"`
+	References  int64 `json:"references"`
+	Memberships int64 `json:"memberships"`
+	Roles       int64 `json:"roles"`
+}
+
+type tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LifecycleTestSuite covers activating, deactivating and merging users
+type LifecycleTestSuite struct {
+	suite.Suite
+	testSuite      *shared.TestSuite
+	organizationID uuid.UUID
+	adminID        uuid.UUID
+	adminToken     string
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *LifecycleTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+
+	// Given: An administrator of the root organization allowed to
+	// activate and merge users and to manage API keys
+	s.organizationID = s.testSuite.CreateRootOrganization("Lifecycle")
+	s.adminID = s.testSuite.CreateUser("Admin")
+	s.testSuite.GrantPermissions(s.organizationID, s.adminID,
+		"users.read", "users.activate", "users.merge", "api_keys.create", "organizations.read")
+	s.adminToken = s.testSuite.AccessToken(s.adminID)
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *LifecycleTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *LifecycleTestSuite) admin() *resty.Request {
+	return s.testSuite.Client.Client.R().SetAuthToken(s.adminToken)
+}
+
+func (s *LifecycleTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *LifecycleTestSuite) post(request *resty.Request, path string) *resty.Response {
+	resp, err := request.Post(path)
+	s.Require().NoError(err, "Request to %s should not fail", path)
+	return resp
+}
+
+// register signs a user up and makes them a member of the organization
+func (s *LifecycleTestSuite) register(name string) (uuid.UUID, string, tokens) {
+	address := name + "-" + uuid.NewString()[:8] + "@example.com"
+
+	var issued tokens
+	resp := s.post(s.testSuite.Client.Client.R().SetBody(map[string]any{
+		"email":      address,
+		"password":   password,
+		"first_name": name,
+	}), "/api/v1/auth/register")
+	s.data(resp, http.StatusCreated, &issued)
+
+	userID := s.testSuite.TokenUserID(issued.AccessToken)
+	s.testSuite.AddMember(s.organizationID, userID)
+	return userID, address, issued
+}
+
+func (s *LifecycleTestSuite) login(address string) *resty.Response {
+	return s.post(s.testSuite.Client.Client.R().SetBody(map[string]any{"email": address, "password": password}), "/api/v1/auth/login")
+}
+
+func (s *LifecycleTestSuite) membershipActive(userID uuid.UUID) int {
+	return s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.organization_users WHERE user_id = $1 AND is_active`, userID)
+}
+
+// TestDeactivateUser_ShouldRevokeAccess tests that deactivating a user
+// logs them out, revokes their refresh tokens and deactivates their
+// memberships, and that activating them restores the memberships
+func (s *LifecycleTestSuite) TestDeactivateUser_ShouldRevokeAccess() {
+	// Given: A member logged in
+	userID, address, issued := s.register("Leaving")
+	s.Equal(1, s.membershipActive(userID))
+
+	// When: The admin deactivates them
+	var deactivated user
+	s.data(s.post(s.admin(), "/api/v1/users/"+userID.String()+"/deactivate"), http.StatusOK, &deactivated)
+
+	// Then: Their tokens are rejected, they cannot log in and their
+	// membership is inactive
+	s.False(deactivated.IsActive)
+	resp, err := s.testSuite.Client.Client.R().SetAuthToken(issued.AccessToken).Get("/api/v1/me")
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode())
+	refresh := s.post(s.testSuite.Client.Client.R().SetBody(map[string]any{"refresh_token": issued.RefreshToken}), "/api/v1/auth/refresh")
+	s.Equal(http.StatusUnauthorized, refresh.StatusCode())
+	s.Equal(http.StatusForbidden, s.login(address).StatusCode())
+	s.Equal(0, s.membershipActive(userID))
+
+	// When: The admin activates them again
+	var activated user
+	s.data(s.post(s.admin(), "/api/v1/users/"+userID.String()+"/activate"), http.StatusOK, &activated)
+
+	// Then: They may log in and their membership is restored
+	s.True(activated.IsActive)
+	s.Equal(http.StatusOK, s.login(address).StatusCode())
+	s.Equal(1, s.membershipActive(userID))
+}
+
+// TestActivateUser_ShouldKeepMembershipsDeactivatedByAdmins tests that
+// activation only restores the memberships deactivated with the user
+func (s *LifecycleTestSuite) TestActivateUser_ShouldKeepMembershipsDeactivatedByAdmins() {
+	userID, _, _ := s.register("Suspended")
+	otherID := s.testSuite.CreateOrganization("Former employer")
+	s.testSuite.AddMember(otherID, userID)
+	s.testSuite.Exec(`UPDATE auth.organization_users SET is_active = false WHERE organization_id = $1 AND user_id = $2`, otherID, userID)
+
+	s.data(s.post(s.admin(), "/api/v1/users/"+userID.String()+"/deactivate"), http.StatusOK, &user{})
+	s.data(s.post(s.admin(), "/api/v1/users/"+userID.String()+"/activate"), http.StatusOK, &user{})
+
+	s.Equal(1, s.membershipActive(userID))
+	s.Equal(0, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.organization_users WHERE organization_id = $1 AND user_id = $2 AND is_active`, otherID, userID))
+}
+
+// TestDeactivateUser_ShouldRevokeAPIKeys tests that deactivating a
+// service account revokes its keys
+func (s *LifecycleTestSuite) TestDeactivateUser_ShouldRevokeAPIKeys() {
+	// Given: An API key of the organization
+	var created struct {
+		UserID uuid.UUID `json:"user_id"`
+		Key    string    `json:"key"`
+	}
+	s.data(s.post(s.admin().SetBody(map[string]any{"name": "Exporter", "actions": []string{"organizations.read"}}), "/api/v1/api-keys"),
+		http.StatusCreated, &created)
+
+	// When: Its service account is deactivated
+	var change statusChange
+	s.data(s.post(s.admin().SetQueryParam("first_name", "Exporter"), "/api/v1/users/deactivate"), http.StatusOK, &change)
+
+	// Then: The key is revoked and rejected
+	s.Contains(change.UserIDs, created.UserID)
+	s.GreaterOrEqual(change.APIKeys, int64(1))
+	resp, err := s.testSuite.Client.Client.R().SetHeader("Authorization", "ApiKey "+created.Key).
+		Get("/api/v1/organizations/" + s.organizationID.String())
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode())
+}
+
+// TestDeactivateUsers_ShouldChangeMatchingUsers tests bulk deactivation
+// and activation by filters
+func (s *LifecycleTestSuite) TestDeactivateUsers_ShouldChangeMatchingUsers() {
+	// Given: Two users sharing a name and another user
+	name := "Bulk" + uuid.NewString()[:8]
+	first, _, _ := s.register(name)
+	second, _, _ := s.register(name)
+	other, _, _ := s.register("Bystander")
+
+	// When: The users named so are deactivated
+	var change statusChange
+	s.data(s.post(s.admin().SetQueryParam("first_name", name), "/api/v1/users/deactivate"), http.StatusOK, &change)
+
+	// Then: Only they are, with their sessions and memberships
+	s.ElementsMatch([]uuid.UUID{first, second}, change.UserIDs)
+	s.Equal(2, change.Sessions)
+	s.Equal(int64(2), change.Memberships)
+	s.Equal(1, s.membershipActive(other))
+
+	// When: They are deactivated again, then activated
+	s.data(s.post(s.admin().SetQueryParam("first_name", name), "/api/v1/users/deactivate"), http.StatusOK, &change)
+	s.Empty(change.UserIDs, "Users already inactive are left out")
+	s.data(s.post(s.admin().SetQueryParam("first_name", name), "/api/v1/users/activate"), http.StatusOK, &change)
+
+	// Then: Both are active members again
+	s.ElementsMatch([]uuid.UUID{first, second}, change.UserIDs)
+	s.Equal(1, s.membershipActive(first))
+	s.Equal(1, s.membershipActive(second))
+}
+
+// TestDeactivateUsers_WithoutFilters_ShouldReturnUnprocessableEntity tests
+// that every user cannot be deactivated by mistake
+func (s *LifecycleTestSuite) TestDeactivateUsers_WithoutFilters_ShouldReturnUnprocessableEntity() {
+	resp := s.post(s.admin(), "/api/v1/users/deactivate")
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestDeactivateUser_Self_ShouldReturnBadRequest tests that admins cannot
+// lock themselves out
+func (s *LifecycleTestSuite) TestDeactivateUser_Self_ShouldReturnBadRequest() {
+	resp := s.post(s.admin(), "/api/v1/users/"+s.adminID.String()+"/deactivate")
+	s.Equal(http.StatusBadRequest, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	var me user
+	s.data(s.post(s.admin(), "/api/v1/users/"+s.adminID.String()+"/activate"), http.StatusOK, &me)
+	s.True(me.IsActive)
+}
+
+// TestDeactivateUser_WithoutPermission_ShouldReturnForbidden tests that
+// only admins change the status of users
+func (s *LifecycleTestSuite) TestDeactivateUser_WithoutPermission_ShouldReturnForbidden() {
+	victimID, _, _ := s.register("Victim")
+	_, _, attacker := s.register("Attacker")
+
+	resp := s.post(s.testSuite.Client.Client.R().SetAuthToken(attacker.AccessToken), "/api/v1/users/"+victimID.String()+"/deactivate")
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestMergeUsers_ShouldMoveReferencesAndMemberships tests merging a
+// duplicate account into the canonical one
+func (s *LifecycleTestSuite) TestMergeUsers_ShouldMoveReferencesAndMemberships() {
+	// Given: A duplicate account that created a user and is a member of an
+	// organization the canonical account is not, and of a shared one
+	canonicalID, _, _ := s.register("Canonical")
+	duplicateID, duplicateAddress, _ := s.register("Duplicate")
+	createdID := s.testSuite.CreateUser("Created")
+	s.testSuite.Exec(`UPDATE auth.users SET created_by = $2, updated_by = $2 WHERE id = $1`, createdID, duplicateID)
+	otherID := s.testSuite.CreateOrganization("Other")
+	s.testSuite.AddMember(otherID, duplicateID)
+
+	// When: The admin merges the duplicate into the canonical account,
+	// acting on every organization
+	var result mergeResult
+	resp := s.post(s.admin().SetHeader("X-Organization-ID", "*").SetBody(map[string]any{"duplicate_id": duplicateID}),
+		"/api/v1/users/"+canonicalID.String()+"/merge")
+	s.data(resp, http.StatusOK, &result)
+
+	// Then: References and memberships point to the canonical account,
+	// and the duplicate is deactivated and deleted
+	s.Equal(canonicalID, result.User.ID)
+	s.GreaterOrEqual(result.References, int64(2))
+	s.Equal(int64(1), result.Memberships, "The shared membership is merged, the other one moved")
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.users WHERE id = $1 AND created_by = $2 AND updated_by = $2`, createdID, canonicalID))
+	s.Equal(2, s.membershipActive(canonicalID))
+	s.Equal(0, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.organization_users WHERE user_id = $1`, duplicateID))
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.users WHERE id = $1 AND NOT is_active AND deleted_at IS NOT NULL`, duplicateID))
+	s.NotEqual(http.StatusOK, s.login(duplicateAddress).StatusCode())
+}
+
+// TestMergeUsers_SingleOrganization_ShouldReturnForbidden tests that merges
+// must act on every organization, whose rows they re-point
+func (s *LifecycleTestSuite) TestMergeUsers_SingleOrganization_ShouldReturnForbidden() {
+	canonicalID, _, _ := s.register("Canonical")
+	duplicateID, _, _ := s.register("Duplicate")
+
+	resp := s.post(s.admin().SetBody(map[string]any{"duplicate_id": duplicateID}), "/api/v1/users/"+canonicalID.String()+"/merge")
+	s.Equal(http.StatusForbidden, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestMergeUsers_IntoItself_ShouldReturnUnprocessableEntity tests that a
+// user cannot be merged into itself
+func (s *LifecycleTestSuite) TestMergeUsers_IntoItself_ShouldReturnUnprocessableEntity() {
+	userID, _, _ := s.register("Self")
+
+	resp := s.post(s.admin().SetHeader("X-Organization-ID", "*").SetBody(map[string]any{"duplicate_id": userID}),
+		"/api/v1/users/"+userID.String()+"/merge")
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestLifecycleTestSuite runs the lifecycle test suite
+func TestLifecycleTestSuite(t *testing.T) {
+	suite.Run(t, new(LifecycleTestSuite))
+}
@@ -0,0 +1,317 @@
+//go:build integration
+
+package me
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"api.system.soluciones-cloud.com/tests/shared"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const password = "correct horse battery"
+
+type user struct {
+	ID        uuid.UUID  `json:"id"`
+	Origin    string     `json:"origin"`
+	FirstName string     `json:"first_name"`
+	LastName  *string    `json:"last_name"`
+	Picture   *string    `json:"picture"`
+	Locale    *string    `json:"locale"`
+	IsActive  bool       `json:"is_active"`
+	UpdatedBy *uuid.UUID `json:"updated_by"`
+}
+
+type permissions struct {
+	OrganizationID *uuid.UUID        `json:"organization_id"`
+	Roles          []string          `json:"roles"`
+	Permissions    map[string]string `json:"permissions"`
+	Organizations  []uuid.UUID       `json:"organizations"`
+	Root           bool              `json:"root"`
+}
+
+type tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// MeTestSuite covers the profile, password and permissions of the caller
+type MeTestSuite struct {
+	suite.Suite
+	testSuite *shared.TestSuite
+}
+
+// SetupSuite runs before all tests in the suite
+func (s *MeTestSuite) SetupSuite() {
+	s.testSuite = shared.NewTestSuite(s.T())
+	err := s.testSuite.Setup()
+	s.Require().NoError(err, "Failed to setup test environment")
+}
+
+// TearDownSuite runs after all tests in the suite
+func (s *MeTestSuite) TearDownSuite() {
+	if s.testSuite != nil {
+		s.testSuite.Teardown()
+	}
+}
+
+func (s *MeTestSuite) data(resp *resty.Response, expectedStatus int, data any) {
+	s.Require().Equal(expectedStatus, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+	s.Require().NoError(json.Unmarshal(resp.Body(), &body))
+}
+
+func (s *MeTestSuite) request(accessToken string) *resty.Request {
+	return s.testSuite.Client.Client.R().SetAuthToken(accessToken)
+}
+
+func (s *MeTestSuite) post(path string, body any) *resty.Response {
+	resp, err := s.testSuite.Client.Client.R().SetBody(body).Post(path)
+	s.Require().NoError(err, "Request to %s should not fail", path)
+	return resp
+}
+
+func (s *MeTestSuite) changePassword(accessToken, current, next string) *resty.Response {
+	resp, err := s.request(accessToken).
+		SetBody(map[string]any{"current_password": current, "password": next}).
+		Put("/api/v1/me/password")
+	s.Require().NoError(err)
+	return resp
+}
+
+// register creates an account with an email credential and returns its
+// tokens
+func (s *MeTestSuite) register(email string) tokens {
+	var issued tokens
+	resp := s.post("/api/v1/auth/register", map[string]any{
+		"email":      email,
+		"password":   password,
+		"first_name": "Ada",
+	})
+	s.data(resp, http.StatusCreated, &issued)
+	return issued
+}
+
+func (s *MeTestSuite) login(email, pw string) *resty.Response {
+	return s.post("/api/v1/auth/login", map[string]any{"email": email, "password": pw})
+}
+
+func uniqueEmail() string {
+	return fmt.Sprintf("me-%s@example.com", uuid.NewString()[:8])
+}
+
+// TestGetMe_ShouldReturnCaller tests that users get their own profile
+// without any permission
+func (s *MeTestSuite) TestGetMe_ShouldReturnCaller() {
+	userID := s.testSuite.CreateUser("Ada")
+
+	resp, err := s.request(s.testSuite.AccessToken(userID)).Get("/api/v1/me")
+	s.Require().NoError(err)
+
+	var me user
+	s.data(resp, http.StatusOK, &me)
+	s.Equal(userID, me.ID)
+	s.Equal("Ada", me.FirstName)
+}
+
+// TestUpdateMe_ShouldEditProfile tests that users edit their name and
+// picture, and clear optional fields with empty strings
+func (s *MeTestSuite) TestUpdateMe_ShouldEditProfile() {
+	// Given: A user
+	userID := s.testSuite.CreateUser("Ada")
+	accessToken := s.testSuite.AccessToken(userID)
+
+	// When: The user edits their profile, trying to deactivate themselves
+	resp, err := s.request(accessToken).SetBody(map[string]any{
+		"first_name": "Grace",
+		"last_name":  "Hopper",
+		"picture":    "https://example.com/grace.png",
+		"is_active":  false,
+	}).Patch("/api/v1/me")
+	s.Require().NoError(err)
+
+	// Then: Only the profile fields change, and the user is the updater
+	var me user
+	s.data(resp, http.StatusOK, &me)
+	s.Equal("Grace", me.FirstName)
+	s.Require().NotNil(me.LastName)
+	s.Equal("Hopper", *me.LastName)
+	s.True(me.IsActive)
+	s.Require().NotNil(me.UpdatedBy)
+	s.Equal(userID, *me.UpdatedBy)
+
+	// When: The user clears their last name and leaves the rest as it is
+	resp, err = s.request(accessToken).SetBody(map[string]any{"last_name": ""}).Patch("/api/v1/me")
+	s.Require().NoError(err)
+
+	// Then: The last name is cleared
+	s.data(resp, http.StatusOK, &me)
+	s.Equal("Grace", me.FirstName)
+	s.Nil(me.LastName)
+	s.Require().NotNil(me.Picture)
+	s.Equal(1, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.users WHERE id = $1 AND last_name IS NULL AND first_name = 'Grace'`, userID))
+}
+
+// TestUpdateMe_EmptyFirstName_ShouldFail tests that the first name cannot
+// be cleared
+func (s *MeTestSuite) TestUpdateMe_EmptyFirstName_ShouldFail() {
+	userID := s.testSuite.CreateUser("Ada")
+
+	resp, err := s.request(s.testSuite.AccessToken(userID)).SetBody(map[string]any{"first_name": ""}).Patch("/api/v1/me")
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestUpdateMe_Locale_ShouldLocalizeResponses tests that the locale users
+// choose takes precedence over Accept-Language, and ?lang over both
+func (s *MeTestSuite) TestUpdateMe_Locale_ShouldLocalizeResponses() {
+	// Given: A user
+	userID := s.testSuite.CreateUser("Ada")
+	accessToken := s.testSuite.AccessToken(userID)
+
+	// When: The user chooses Spanish with a regional tag
+	resp, err := s.request(accessToken).SetBody(map[string]any{"locale": "es-PE"}).Patch("/api/v1/me")
+	s.Require().NoError(err)
+
+	// Then: The primary language is stored
+	var me user
+	s.data(resp, http.StatusOK, &me)
+	s.Require().NotNil(me.Locale)
+	s.Equal("es", *me.Locale)
+
+	// Then: Later requests are answered in Spanish, unless ?lang overrides it
+	resp, err = s.request(accessToken).SetHeader("Accept-Language", "en").Get("/api/v1/me")
+	s.Require().NoError(err)
+	s.Equal("es", resp.Header().Get("Content-Language"))
+
+	resp, err = s.request(accessToken).SetHeader("Accept-Language", "en").SetQueryParam("lang", "en").Get("/api/v1/me")
+	s.Require().NoError(err)
+	s.Equal("en", resp.Header().Get("Content-Language"))
+
+	// When: The user clears their locale
+	resp, err = s.request(accessToken).SetBody(map[string]any{"locale": ""}).Patch("/api/v1/me")
+	s.Require().NoError(err)
+
+	// Then: Accept-Language applies again
+	s.data(resp, http.StatusOK, &me)
+	s.Nil(me.Locale)
+	resp, err = s.request(accessToken).SetHeader("Accept-Language", "en").Get("/api/v1/me")
+	s.Require().NoError(err)
+	s.Equal("en", resp.Header().Get("Content-Language"))
+}
+
+// TestUpdateMe_UnsupportedLocale_ShouldFail tests that only locales with
+// a message catalog can be chosen
+func (s *MeTestSuite) TestUpdateMe_UnsupportedLocale_ShouldFail() {
+	userID := s.testSuite.CreateUser("Ada")
+
+	resp, err := s.request(s.testSuite.AccessToken(userID)).SetBody(map[string]any{"locale": "xx"}).Patch("/api/v1/me")
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+	s.Equal(0, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.users WHERE id = $1 AND locale IS NOT NULL`, userID))
+}
+
+// TestUpdateMe_UnsafePicture_ShouldFail tests that pictures must be http
+// or https URLs, so clients never render script or data URLs
+func (s *MeTestSuite) TestUpdateMe_UnsafePicture_ShouldFail() {
+	userID := s.testSuite.CreateUser("Ada")
+	accessToken := s.testSuite.AccessToken(userID)
+
+	for _, picture := range []string{"javascript:alert(1)", "data:image/svg+xml;base64,PHN2Zz4=", "ftp://example.com/ada.png"} {
+		resp, err := s.request(accessToken).SetBody(map[string]any{"picture": picture}).Patch("/api/v1/me")
+		s.Require().NoError(err)
+		s.Equal(http.StatusUnprocessableEntity, resp.StatusCode(), "Unexpected status for %s: %s", picture, resp.Body())
+	}
+	s.Equal(0, s.testSuite.QueryInt(`SELECT COUNT(*) FROM auth.users WHERE id = $1 AND picture IS NOT NULL`, userID))
+}
+
+// TestMyPermissions_ShouldSummarizeGrants tests the permissions summary of
+// the organization of the request
+func (s *MeTestSuite) TestMyPermissions_ShouldSummarizeGrants() {
+	// Given: A member of an organization granted users.read
+	organizationID := s.testSuite.CreateOrganization("Me")
+	userID := s.testSuite.CreateUser("Ada")
+	s.testSuite.GrantPermissions(organizationID, userID, "users.read")
+
+	// When: The user asks for their permissions in the organization
+	resp, err := s.request(s.testSuite.AccessToken(userID)).
+		SetHeader("X-Organization-ID", organizationID.String()).
+		Get("/api/v1/me/permissions")
+	s.Require().NoError(err)
+
+	// Then: The granted action, its scope and the organization are listed
+	var summary permissions
+	s.data(resp, http.StatusOK, &summary)
+	s.Require().NotNil(summary.OrganizationID)
+	s.Equal(organizationID, *summary.OrganizationID)
+	s.Equal("ALL", summary.Permissions["users.read"])
+	s.NotContains(summary.Permissions, "users.delete")
+	s.Contains(summary.Organizations, organizationID)
+	s.NotEmpty(summary.Roles)
+	s.False(summary.Root)
+}
+
+// TestChangePassword_ShouldKeepCurrentSession tests that changing the
+// password ends the other sessions only
+func (s *MeTestSuite) TestChangePassword_ShouldKeepCurrentSession() {
+	// Given: A user logged in on two devices
+	email := uniqueEmail()
+	other := s.register(email)
+	var current tokens
+	s.data(s.login(email, password), http.StatusOK, &current)
+
+	// When: The user changes their password from one of them
+	resp := s.changePassword(current.AccessToken, password, "a brand new password")
+	s.Require().Equal(http.StatusNoContent, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	// Then: Only the new password logs in, the other device is logged out
+	// and the current one keeps working
+	s.Equal(http.StatusUnauthorized, s.login(email, password).StatusCode())
+	s.Equal(http.StatusOK, s.login(email, "a brand new password").StatusCode())
+
+	meResp, err := s.request(other.AccessToken).Get("/api/v1/me")
+	s.Require().NoError(err)
+	s.Equal(http.StatusUnauthorized, meResp.StatusCode())
+	s.Equal(http.StatusUnauthorized, s.post("/api/v1/auth/refresh", map[string]any{"refresh_token": other.RefreshToken}).StatusCode())
+
+	meResp, err = s.request(current.AccessToken).Get("/api/v1/me")
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, meResp.StatusCode())
+	s.Equal(http.StatusOK, s.post("/api/v1/auth/refresh", map[string]any{"refresh_token": current.RefreshToken}).StatusCode())
+}
+
+// TestChangePassword_WrongCurrentPassword_ShouldFail tests that the
+// current password is required and wrong ones count as failed logins
+func (s *MeTestSuite) TestChangePassword_WrongCurrentPassword_ShouldFail() {
+	email := uniqueEmail()
+	issued := s.register(email)
+
+	resp := s.changePassword(issued.AccessToken, "not my password", "a brand new password")
+	s.Equal(http.StatusBadRequest, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+
+	s.Equal(1, s.testSuite.QueryInt(`SELECT failed_login_attempts FROM auth.email_credentials WHERE email = $1`, email))
+	s.Equal(http.StatusOK, s.login(email, password).StatusCode())
+}
+
+// TestChangePassword_WithoutPassword_ShouldFail tests users without an
+// email credential, e.g. of identity providers
+func (s *MeTestSuite) TestChangePassword_WithoutPassword_ShouldFail() {
+	userID := s.testSuite.CreateUser("Ada")
+
+	resp := s.changePassword(s.testSuite.AccessToken(userID), password, "a brand new password")
+	s.Equal(http.StatusBadRequest, resp.StatusCode(), "Unexpected status: %s", resp.Body())
+}
+
+// TestMeTestSuite runs the me test suite
+func TestMeTestSuite(t *testing.T) {
+	suite.Run(t, new(MeTestSuite))
+}
@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"api.system.soluciones-cloud.com/internal/shared/auth/token"
+)
+
+// TestJWTSecret is the HS256 secret the API container verifies tokens with
+const TestJWTSecret = "test-jwt-secret-key-for-integration-tests"
+
+// AccessToken signs a one hour access token for userID with TestJWTSecret
+func (ts *TestSuite) AccessToken(userID uuid.UUID, roles ...string) string {
+	now := time.Now()
+	claims := token.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Roles: roles,
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(TestJWTSecret))
+	require.NoError(ts.T, err, "Failed to sign access token")
+
+	return signed
+}
+
+// TokenClaims parses an access token issued by the API
+func (ts *TestSuite) TokenClaims(accessToken string) token.Claims {
+	var claims token.Claims
+	_, err := jwt.ParseWithClaims(accessToken, &claims, func(*jwt.Token) (any, error) {
+		return []byte(TestJWTSecret), nil
+	})
+	require.NoError(ts.T, err, "Failed to parse access token")
+
+	return claims
+}
+
+// TokenUserID returns the user id in the subject of an access token
+func (ts *TestSuite) TokenUserID(accessToken string) uuid.UUID {
+	userID, err := uuid.Parse(ts.TokenClaims(accessToken).Subject)
+	require.NoError(ts.T, err, "Invalid access token subject")
+
+	return userID
+}
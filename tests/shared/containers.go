@@ -93,8 +93,17 @@ func (c *APIContainer) Close(ctx context.Context) error {
 	return c.Container.Terminate(ctx)
 }
 
+// APIOptions customizes the API container of a test suite
+type APIOptions struct {
+	// Env is added to the environment of the API
+	Env map[string]string
+	// HostAccessPorts are ports of the test process the API reaches at
+	// testcontainers.HostInternal, e.g. of a fake identity provider
+	HostAccessPorts []int
+}
+
 // CreateAPIContainer creates and starts an API service test container  
-func CreateAPIContainer(ctx context.Context, dbContainer *PostgreSQLContainer) (*APIContainer, error) {
+func CreateAPIContainer(ctx context.Context, dbContainer *PostgreSQLContainer, options APIOptions) (*APIContainer, error) {
 	// Get the container internal IP for database connection
 	dbHost, err := dbContainer.Container.ContainerIP(ctx)
 	if err != nil {
@@ -116,13 +125,23 @@ func CreateAPIContainer(ctx context.Context, dbContainer *PostgreSQLContainer) (
 			"DB_PASSWORD": dbContainer.Password,
 			"DB_SSL_MODE": "disable",
 			"HTTP_PORT":   "8080",
-			"JWT_SECRET":  "test-jwt-secret-key-for-integration-tests",
+			"JWT_SECRET":  TestJWTSecret,
+			"MAIL_DRIVER": "filesystem",
+			"MAIL_DIR":    TestMailDir,
+			// Tests grant permissions to users that already logged in
+			"AUTH_PERMISSION_CACHE_TTL": "0s",
+			// The catalog must hold the permissions of every route
+			"AUTH_SYNC_PERMISSIONS": "true",
 		},
 		WaitingFor: wait.ForHTTP("/health").
 			WithPort("8080/tcp").
 			WithStartupTimeout(60 * time.Second).
 			WithPollInterval(2 * time.Second),
 	}
+	for key, value := range options.Env {
+		req.Env[key] = value
+	}
+	req.HostAccessPorts = options.HostAccessPorts
 
 	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: req,
@@ -17,6 +17,8 @@ type TestSuite struct {
 	DB        *PostgreSQLContainer
 	API       *APIContainer
 	Client    *HTTPClient
+	// APIOptions are applied to the API container when set before Setup
+	APIOptions APIOptions
 	T         *testing.T
 	ctx       context.Context
 	cancelled context.CancelFunc
@@ -53,7 +55,7 @@ func (ts *TestSuite) Setup() error {
 
 	// 3. Start API container
 	ts.T.Log("Starting API container...")
-	api, err := CreateAPIContainer(ts.ctx, db)
+	api, err := CreateAPIContainer(ts.ctx, db, ts.APIOptions)
 	if err != nil {
 		return fmt.Errorf("failed to create API container: %w", err)
 	}
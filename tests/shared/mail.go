@@ -0,0 +1,61 @@
+package shared
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/exec"
+)
+
+// TestMailDir is the directory the API container writes emails to
+const TestMailDir = "/app/mail"
+
+var tokenParam = regexp.MustCompile(`[?&]token=([A-Za-z0-9_-]+)`)
+
+// LastEmail returns the subject and plain text body of the last email the
+// API sent to the address
+func (ts *TestSuite) LastEmail(to string) (subject, text string) {
+	script := `grep -l "^To: ` + to + `" ` + TestMailDir + `/*.eml | sort | tail -n 1 | xargs cat`
+	code, reader, err := ts.API.Container.Exec(ts.ctx, []string{"sh", "-c", script}, exec.Multiplexed())
+	require.NoError(ts.T, err, "Failed to read emails")
+	require.Equal(ts.T, 0, code, "No email was sent to %s", to)
+
+	msg, err := mail.ReadMessage(reader)
+	require.NoError(ts.T, err, "Failed to parse email")
+
+	subject, err = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(ts.T, err, "Failed to decode email subject")
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(ts.T, err, "Failed to parse email content type")
+
+	body := io.Reader(quotedprintable.NewReader(msg.Body))
+	if strings.HasPrefix(mediaType, "multipart/") {
+		// The plain text alternative comes first; parts are decoded by the
+		// multipart reader
+		part, err := multipart.NewReader(msg.Body, params["boundary"]).NextPart()
+		require.NoError(ts.T, err, "Failed to read email body")
+		body = part
+	}
+
+	content, err := io.ReadAll(body)
+	require.NoError(ts.T, err, "Failed to decode email body")
+
+	return subject, string(content)
+}
+
+// EmailToken returns the token of the link in the last email sent to the
+// address
+func (ts *TestSuite) EmailToken(to string) string {
+	_, text := ts.LastEmail(to)
+
+	match := tokenParam.FindStringSubmatch(text)
+	require.NotNil(ts.T, match, "Email to %s has no token link: %s", to, text)
+	return match[1]
+}
@@ -0,0 +1,214 @@
+package shared
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	oidcClientID     = "api-test-client"
+	oidcClientSecret = "api-test-secret"
+	oidcKeyID        = "fake-oidc-key"
+	// OIDCRedirectURL is the client page the fake provider redirects to
+	OIDCRedirectURL = "http://localhost:3000/login/callback"
+)
+
+// OIDCUser is the account a login with the fake provider is made with
+type OIDCUser struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Picture       string
+}
+
+type oidcLogin struct {
+	user      OIDCUser
+	nonce     string
+	challenge string
+}
+
+// OIDCProvider is a fake OpenID Connect provider running in the test
+// process. The API container reaches it at testcontainers.HostInternal;
+// logins are made without a browser with Authorize.
+type OIDCProvider struct {
+	Name   string
+	Issuer string
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	logins map[string]oidcLogin
+}
+
+// NewOIDCProvider starts a fake provider and registers it with the API
+// options of the suite. It must be called before Setup.
+func NewOIDCProvider(ts *TestSuite, name string) *OIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(ts.T, err, "Failed to generate provider key")
+
+	p := &OIDCProvider{
+		Name:   name,
+		key:    key,
+		logins: map[string]oidcLogin{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /jwks", p.jwks)
+	mux.HandleFunc("POST /token", p.token)
+	p.server = httptest.NewServer(mux)
+	ts.T.Cleanup(p.server.Close)
+
+	_, portValue, err := net.SplitHostPort(p.server.Listener.Addr().String())
+	require.NoError(ts.T, err)
+	port, err := strconv.Atoi(portValue)
+	require.NoError(ts.T, err)
+	p.Issuer = fmt.Sprintf("http://%s:%d", testcontainers.HostInternal, port)
+
+	prefix := "OIDC_" + strings.ToUpper(name) + "_"
+	if ts.APIOptions.Env == nil {
+		ts.APIOptions.Env = map[string]string{}
+	}
+	ts.APIOptions.Env["OIDC_PROVIDERS"] = name
+	ts.APIOptions.Env[prefix+"ISSUER"] = p.Issuer
+	ts.APIOptions.Env[prefix+"CLIENT_ID"] = oidcClientID
+	ts.APIOptions.Env[prefix+"CLIENT_SECRET"] = oidcClientSecret
+	ts.APIOptions.Env[prefix+"REDIRECT_URL"] = OIDCRedirectURL
+	ts.APIOptions.HostAccessPorts = append(ts.APIOptions.HostAccessPorts, port)
+
+	return p
+}
+
+// NewOIDCUser returns an account with a verified email unique to the test
+func NewOIDCUser(givenName string) OIDCUser {
+	subject := uuid.NewString()
+	return OIDCUser{
+		Subject:       subject,
+		Email:         strings.ToLower(givenName) + "-" + subject[:8] + "@example.com",
+		EmailVerified: true,
+		GivenName:     givenName,
+		FamilyName:    "Tester",
+		Picture:       "https://example.com/" + subject + ".png",
+	}
+}
+
+// Authorize plays the user logging in at the authorization URL returned by
+// the API and returns the code and state the provider redirects back with
+func (p *OIDCProvider) Authorize(t *testing.T, authorizationURL string, user OIDCUser) (code, state string) {
+	parsed, err := url.Parse(authorizationURL)
+	require.NoError(t, err, "Invalid authorization URL")
+	query := parsed.Query()
+
+	require.Equal(t, p.Issuer+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, oidcClientID, query.Get("client_id"))
+	require.Equal(t, OIDCRedirectURL, query.Get("redirect_uri"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+	require.NotEmpty(t, query.Get("code_challenge"))
+	require.NotEmpty(t, query.Get("nonce"))
+
+	code = uuid.NewString()
+	p.mu.Lock()
+	p.logins[code] = oidcLogin{user: user, nonce: query.Get("nonce"), challenge: query.Get("code_challenge")}
+	p.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+func (p *OIDCProvider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeProviderJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.Issuer,
+		"authorization_endpoint":                p.Issuer + "/authorize",
+		"token_endpoint":                        p.Issuer + "/token",
+		"jwks_uri":                              p.Issuer + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *OIDCProvider) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeProviderJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": oidcKeyID,
+		"alg": "RS256",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+	}}})
+}
+
+// token redeems a code once, checking the client and the PKCE verifier
+func (p *OIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	clientID, secret, _ := r.BasicAuth()
+	if clientID != oidcClientID || secret != oidcClientSecret {
+		writeProviderJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	login, ok := p.logins[r.PostFormValue("code")]
+	delete(p.logins, r.PostFormValue("code"))
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || r.PostFormValue("redirect_uri") != OIDCRedirectURL ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != login.challenge {
+		writeProviderJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.Issuer,
+		"aud":            oidcClientID,
+		"sub":            login.user.Subject,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          login.nonce,
+		"email":          login.user.Email,
+		"email_verified": login.user.EmailVerified,
+		"given_name":     login.user.GivenName,
+		"family_name":    login.user.FamilyName,
+		"picture":        login.user.Picture,
+	})
+	idToken.Header["kid"] = oidcKeyID
+	raw, err := idToken.SignedString(p.key)
+	if err != nil {
+		writeProviderJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeProviderJSON(w, http.StatusOK, map[string]any{
+		"access_token": uuid.NewString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     raw,
+	})
+}
+
+func writeProviderJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
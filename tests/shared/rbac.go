@@ -0,0 +1,100 @@
+package shared
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// Exec runs a statement against the test database
+func (ts *TestSuite) Exec(query string, args ...any) sql.Result {
+	db, err := sql.Open("postgres", ts.DB.GetDSN())
+	require.NoError(ts.T, err, "Failed to connect to database")
+	defer db.Close()
+
+	result, err := db.ExecContext(ts.ctx, query, args...)
+	require.NoError(ts.T, err, "Failed to execute %s", query)
+	return result
+}
+
+// QueryInt runs a query returning a single integer, e.g. a count
+func (ts *TestSuite) QueryInt(query string, args ...any) int {
+	db, err := sql.Open("postgres", ts.DB.GetDSN())
+	require.NoError(ts.T, err, "Failed to connect to database")
+	defer db.Close()
+
+	var value int
+	require.NoError(ts.T, db.QueryRowContext(ts.ctx, query, args...).Scan(&value), "Failed to query %s", query)
+	return value
+}
+
+// CreateUser inserts an active user and returns its id
+func (ts *TestSuite) CreateUser(firstName string) uuid.UUID {
+	id := uuid.New()
+	ts.Exec(`INSERT INTO auth.users (id, origin, first_name) VALUES ($1, 'SYSTEM', $2)`, id, firstName)
+	return id
+}
+
+// CreateOrganization inserts an active organization and returns its id
+func (ts *TestSuite) CreateOrganization(name string) uuid.UUID {
+	id := uuid.New()
+	ts.Exec(`INSERT INTO auth.organizations (id, name, code) VALUES ($1, $2, $3)`, id, name, "org-"+id.String()[:8])
+	return id
+}
+
+// CreateRootOrganization inserts the root organization, whose members may
+// act on every organization, and returns its id. There is a single root
+// organization.
+func (ts *TestSuite) CreateRootOrganization(name string) uuid.UUID {
+	id := ts.CreateOrganization(name)
+	ts.Exec(`UPDATE auth.organizations SET is_root_organization = true WHERE id = $1`, id)
+	return id
+}
+
+// AddMember makes the user an active member of the organization
+func (ts *TestSuite) AddMember(organizationID, userID uuid.UUID) {
+	ts.Exec(`INSERT INTO auth.organization_users (organization_id, user_id) VALUES ($1, $2)`, organizationID, userID)
+}
+
+// GrantPermissions assigns the user a new role of the organization with
+// the "<module>.<action>" permission codes and returns the role code. The
+// user is made a member of the organization.
+func (ts *TestSuite) GrantPermissions(organizationID, userID uuid.UUID, codes ...string) string {
+	roleID := uuid.New()
+	roleCode := "role-" + roleID.String()[:8]
+
+	ts.Exec(`INSERT INTO auth.roles (id, name, code, organization_id) VALUES ($1, $2, $2, $3)`, roleID, roleCode, organizationID)
+	ts.Exec(`INSERT INTO auth.user_roles (user_id, role_id) VALUES ($1, $2)`, userID, roleID)
+	ts.Exec(`
+		INSERT INTO auth.organization_users (organization_id, user_id)
+		SELECT $1::uuid, $2::uuid
+		WHERE NOT EXISTS (SELECT 1 FROM auth.organization_users WHERE organization_id = $1 AND user_id = $2)
+	`, organizationID, userID)
+
+	result := ts.Exec(`
+		INSERT INTO auth.permissions (role_id, module_action_id)
+		SELECT $1::uuid, ma.id
+		FROM auth.module_actions ma
+		JOIN auth.modules m ON m.id = ma.module_id
+		WHERE m.code || '.' || ma.code = ANY($2::text[])
+	`, roleID, pq.Array(codes))
+
+	granted, err := result.RowsAffected()
+	require.NoError(ts.T, err)
+	require.Equal(ts.T, int64(len(codes)), granted, "Unknown permission in %v", codes)
+
+	return roleCode
+}
+
+// SetVisibilityScope changes the visibility scope of an action, e.g.
+// SetVisibilityScope("users.read", "OWN")
+func (ts *TestSuite) SetVisibilityScope(code, scope string) {
+	ts.Exec(`
+		UPDATE auth.module_actions ma
+		SET visibility_scope = $2
+		FROM auth.modules m
+		WHERE m.id = ma.module_id AND m.code || '.' || ma.code = $1
+	`, code, scope)
+}